@@ -58,6 +58,14 @@ type ACL struct {
 	// We use an iradix for the purposes of ordered iteration.
 	wildcardHostVolumes *iradix.Tree
 
+	// variables maps a namespace to a tree of variables path specs, which
+	// in turn map to a capabilitySet
+	variables *iradix.Tree
+
+	// wildcardVariables maps a glob pattern of a namespace to a tree of
+	// variables path specs
+	wildcardVariables *iradix.Tree
+
 	agent    string
 	node     string
 	operator string
@@ -96,6 +104,11 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	hvTxn := iradix.New().Txn()
 	whvTxn := iradix.New().Txn()
 
+	// The variables path specs are collected per namespace before being
+	// committed, as the trees are nested.
+	variables := make(map[string]map[string]capabilitySet)
+	wildcardVariables := make(map[string]map[string]capabilitySet)
+
 	for _, policy := range policies {
 	NAMESPACES:
 		for _, ns := range policy.Namespaces {
@@ -123,6 +136,21 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 				}
 			}
 
+			// Collect the variables path specs, including those granted by
+			// the short hand policy
+			varPaths := variables
+			if globDefinition {
+				varPaths = wildcardVariables
+			}
+			if ns.Variables != nil {
+				for _, pathPolicy := range ns.Variables.Paths {
+					addVariablesCapabilities(varPaths, ns.Name, pathPolicy.PathSpec, pathPolicy.Capabilities)
+				}
+			}
+			if varCap := expandVariablesPolicy(ns.Policy); len(varCap) > 0 {
+				addVariablesCapabilities(varPaths, ns.Name, "*", varCap)
+			}
+
 			// Deny always takes precedence
 			if capabilities.Check(NamespaceCapabilityDeny) {
 				continue NAMESPACES
@@ -206,10 +234,54 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	acl.wildcardNamespaces = wnsTxn.Commit()
 	acl.hostVolumes = hvTxn.Commit()
 	acl.wildcardHostVolumes = whvTxn.Commit()
+	acl.variables = variablesTree(variables)
+	acl.wildcardVariables = variablesTree(wildcardVariables)
 
 	return acl, nil
 }
 
+// addVariablesCapabilities adds the capabilities for the variables path spec
+// within the namespace, handling deny in the same manner as namespaces.
+func addVariablesCapabilities(m map[string]map[string]capabilitySet, ns, pathSpec string, caps []string) {
+	paths, ok := m[ns]
+	if !ok {
+		paths = make(map[string]capabilitySet)
+		m[ns] = paths
+	}
+	capabilities, ok := paths[pathSpec]
+	if !ok {
+		capabilities = make(capabilitySet)
+		paths[pathSpec] = capabilities
+	}
+
+	// Deny always takes precedence
+	if capabilities.Check(VariablesCapabilityDeny) {
+		return
+	}
+	for _, cap := range caps {
+		if cap == VariablesCapabilityDeny {
+			capabilities.Clear()
+			capabilities.Set(VariablesCapabilityDeny)
+			return
+		}
+		capabilities.Set(cap)
+	}
+}
+
+// variablesTree converts the collected variables path specs into the nested
+// trees stored on the ACL.
+func variablesTree(m map[string]map[string]capabilitySet) *iradix.Tree {
+	txn := iradix.New().Txn()
+	for ns, paths := range m {
+		pathTxn := iradix.New().Txn()
+		for pathSpec, capabilities := range paths {
+			pathTxn.Insert([]byte(pathSpec), capabilities)
+		}
+		txn.Insert([]byte(ns), pathTxn.Commit())
+	}
+	return txn.Commit()
+}
+
 // AllowNsOp is shorthand for AllowNamespaceOperation
 func (a *ACL) AllowNsOp(ns string, op string) bool {
 	return a.AllowNamespaceOperation(ns, op)
@@ -291,6 +363,92 @@ func (a *ACL) AllowHostVolume(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// AllowVariableOperation checks if a given operation is allowed for the
+// variable at the path within the namespace
+func (a *ACL) AllowVariableOperation(ns, path, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	// Check for a matching capability set
+	capabilities, ok := a.matchingVariablesCapabilitySet(ns, path)
+	if !ok {
+		return false
+	}
+
+	// Check if the capability has been granted
+	return capabilities.Check(op)
+}
+
+// AllowVariableSearch checks if any variables operations are allowed within
+// the namespace. It is used to determine whether a namespace should be
+// searched when listing variables.
+func (a *ACL) AllowVariableSearch(ns string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	paths, ok := a.matchingVariablesPathTree(ns)
+	if !ok {
+		return false
+	}
+
+	var allowed bool
+	paths.Root().Walk(func(_ []byte, iv interface{}) bool {
+		capabilities := iv.(capabilitySet)
+		allowed = len(capabilities) > 0 && !capabilities.Check(VariablesCapabilityDeny)
+		return allowed
+	})
+	return allowed
+}
+
+// matchingVariablesCapabilitySet looks for a capabilitySet that matches the
+// variable path within the namespace. Concrete definitions are preferred over
+// globs, for both the namespace and path.
+func (a *ACL) matchingVariablesCapabilitySet(ns, path string) (capabilitySet, bool) {
+	paths, ok := a.matchingVariablesPathTree(ns)
+	if !ok {
+		return nil, false
+	}
+
+	raw, ok := paths.Get([]byte(path))
+	if ok {
+		return raw.(capabilitySet), true
+	}
+	return a.findClosestMatchingGlob(paths, path)
+}
+
+// matchingVariablesPathTree looks for the tree of variables path specs which
+// matches the namespace. If no concrete definition is found, the closest
+// matching namespace glob is used.
+func (a *ACL) matchingVariablesPathTree(ns string) (*iradix.Tree, bool) {
+	if a.variables == nil {
+		return nil, false
+	}
+
+	raw, ok := a.variables.Get([]byte(ns))
+	if ok {
+		return raw.(*iradix.Tree), true
+	}
+
+	var closest *iradix.Tree
+	difference := -1
+	a.wildcardVariables.Root().Walk(func(bk []byte, iv interface{}) bool {
+		k := string(bk)
+		if glob.Glob(k, ns) {
+			diff := len(ns) - len(k) + strings.Count(k, glob.GLOB)
+			if difference == -1 || diff < difference {
+				closest = iv.(*iradix.Tree)
+				difference = diff
+			}
+		}
+		return false
+	})
+	return closest, closest != nil
+}
+
 // matchingNamespaceCapabilitySet looks for a capabilitySet that matches the namespace,
 // if no concrete definitions are found, then we return the closest matching
 // glob.
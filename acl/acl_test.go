@@ -5,6 +5,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCapabilitySet(t *testing.T) {
@@ -327,6 +328,124 @@ func TestWildcardNamespaceMatching(t *testing.T) {
 	}
 }
 
+func TestVariablesMatching(t *testing.T) {
+	ci.Parallel(t)
+
+	tests := []struct {
+		Name      string
+		Policy    string
+		Namespace string
+		Path      string
+		Op        string
+		Allow     bool
+	}{
+		{
+			Name:      "concrete namespace with concrete path",
+			Policy:    `namespace "ns" { variables { path "foo/bar" { capabilities = ["read"] }}}`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityRead,
+			Allow:     true,
+		},
+		{
+			Name:      "concrete namespace with missing capability",
+			Policy:    `namespace "ns" { variables { path "foo/bar" { capabilities = ["read"] }}}`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityWrite,
+			Allow:     false,
+		},
+		{
+			Name:      "concrete namespace with glob path",
+			Policy:    `namespace "ns" { variables { path "foo/*" { capabilities = ["read"] }}}`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityRead,
+			Allow:     true,
+		},
+		{
+			Name:      "glob namespace with glob path",
+			Policy:    `namespace "n*" { variables { path "foo/*" { capabilities = ["read"] }}}`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityRead,
+			Allow:     true,
+		},
+		{
+			Name:      "non-matching namespace",
+			Policy:    `namespace "other" { variables { path "*" { capabilities = ["read"] }}}`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityRead,
+			Allow:     false,
+		},
+		{
+			Name: "concrete path takes precedence",
+			Policy: `namespace "ns" { variables {
+				path "foo/*" { capabilities = ["read"] }
+				path "foo/bar" { capabilities = ["deny"] }
+			}}`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityRead,
+			Allow:     false,
+		},
+		{
+			Name:      "short hand policy grants variables",
+			Policy:    `namespace "ns" { policy = "write" }`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityDestroy,
+			Allow:     true,
+		},
+		{
+			Name:      "short hand read policy",
+			Policy:    `namespace "ns" { policy = "read" }`,
+			Namespace: "ns",
+			Path:      "foo/bar",
+			Op:        VariablesCapabilityWrite,
+			Allow:     false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			policy, err := Parse(tc.Policy)
+			require.NoError(t, err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.Allow, acl.AllowVariableOperation(tc.Namespace, tc.Path, tc.Op))
+		})
+	}
+}
+
+func TestAllowVariableSearch(t *testing.T) {
+	ci.Parallel(t)
+
+	policy, err := Parse(`
+namespace "ns" {
+  variables {
+    path "foo/*" { capabilities = ["list"] }
+  }
+}
+namespace "denied" {
+  variables {
+    path "*" { capabilities = ["deny"] }
+  }
+}`)
+	require.NoError(t, err)
+
+	acl, err := NewACL(false, []*Policy{policy})
+	require.NoError(t, err)
+
+	require.True(t, acl.AllowVariableSearch("ns"))
+	require.False(t, acl.AllowVariableSearch("denied"))
+	require.False(t, acl.AllowVariableSearch("other"))
+	require.True(t, ManagementACL.AllowVariableSearch("other"))
+}
+
 func TestWildcardHostVolumeMatching(t *testing.T) {
 	ci.Parallel(t)
 
@@ -34,6 +34,7 @@ const (
 	NamespaceCapabilityReadFS               = "read-fs"
 	NamespaceCapabilityAllocExec            = "alloc-exec"
 	NamespaceCapabilityAllocNodeExec        = "alloc-node-exec"
+	NamespaceCapabilityAllocAction          = "alloc-action"
 	NamespaceCapabilityAllocLifecycle       = "alloc-lifecycle"
 	NamespaceCapabilitySentinelOverride     = "sentinel-override"
 	NamespaceCapabilityCSIRegisterPlugin    = "csi-register-plugin"
@@ -41,6 +42,8 @@ const (
 	NamespaceCapabilityCSIReadVolume        = "csi-read-volume"
 	NamespaceCapabilityCSIListVolume        = "csi-list-volume"
 	NamespaceCapabilityCSIMountVolume       = "csi-mount-volume"
+	NamespaceCapabilityHostVolumeRead       = "host-volume-read"
+	NamespaceCapabilityHostVolumeWrite      = "host-volume-write"
 	NamespaceCapabilityListScalingPolicies  = "list-scaling-policies"
 	NamespaceCapabilityReadScalingPolicy    = "read-scaling-policy"
 	NamespaceCapabilityReadJobScaling       = "read-job-scaling"
@@ -67,6 +70,19 @@ var (
 	validVolume = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
 )
 
+const (
+	// The following are the fine-grained capabilities that can be granted for
+	// a variables path. When capabilities are combined we take the union of
+	// all capabilities. If the deny capability is present, it takes precedence
+	// and overwrites all other capabilities.
+
+	VariablesCapabilityList    = "list"
+	VariablesCapabilityRead    = "read"
+	VariablesCapabilityWrite   = "write"
+	VariablesCapabilityDestroy = "destroy"
+	VariablesCapabilityDeny    = "deny"
+)
+
 // Policy represents a parsed HCL or JSON policy.
 type Policy struct {
 	Namespaces  []*NamespacePolicy  `hcl:"namespace,expand"`
@@ -96,6 +112,19 @@ type NamespacePolicy struct {
 	Name         string `hcl:",key"`
 	Policy       string
 	Capabilities []string
+	Variables    *VariablesPolicy `hcl:"variables"`
+}
+
+// VariablesPolicy is the policy for the variables within a namespace
+type VariablesPolicy struct {
+	Paths []*VariablesPathPolicy `hcl:"path,expand"`
+}
+
+// VariablesPathPolicy is the policy for a variables path, which may contain
+// a glob
+type VariablesPathPolicy struct {
+	PathSpec     string `hcl:",key"`
+	Capabilities []string
 }
 
 // HostVolumePolicy is the policy for a specific named host volume
@@ -150,8 +179,9 @@ func isNamespaceCapabilityValid(cap string) bool {
 	case NamespaceCapabilityDeny, NamespaceCapabilityParseJob, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
 		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
 		NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
-		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec,
+		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec, NamespaceCapabilityAllocAction,
 		NamespaceCapabilityCSIReadVolume, NamespaceCapabilityCSIWriteVolume, NamespaceCapabilityCSIListVolume, NamespaceCapabilityCSIMountVolume, NamespaceCapabilityCSIRegisterPlugin,
+		NamespaceCapabilityHostVolumeRead, NamespaceCapabilityHostVolumeWrite,
 		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob:
 		return true
 	// Separate the enterprise-only capabilities
@@ -171,6 +201,7 @@ func expandNamespacePolicy(policy string) []string {
 		NamespaceCapabilityReadJob,
 		NamespaceCapabilityCSIListVolume,
 		NamespaceCapabilityCSIReadVolume,
+		NamespaceCapabilityHostVolumeRead,
 		NamespaceCapabilityReadJobScaling,
 		NamespaceCapabilityListScalingPolicies,
 		NamespaceCapabilityReadScalingPolicy,
@@ -186,6 +217,7 @@ func expandNamespacePolicy(policy string) []string {
 		NamespaceCapabilityReadLogs,
 		NamespaceCapabilityReadFS,
 		NamespaceCapabilityAllocExec,
+		NamespaceCapabilityAllocAction,
 		NamespaceCapabilityAllocLifecycle,
 		NamespaceCapabilityCSIMountVolume,
 		NamespaceCapabilityCSIWriteVolume,
@@ -211,6 +243,34 @@ func expandNamespacePolicy(policy string) []string {
 	}
 }
 
+// isVariablesCapabilityValid ensures the given capability is valid for a
+// variables path policy
+func isVariablesCapabilityValid(cap string) bool {
+	switch cap {
+	case VariablesCapabilityList, VariablesCapabilityRead, VariablesCapabilityWrite,
+		VariablesCapabilityDestroy, VariablesCapabilityDeny:
+		return true
+	default:
+		return false
+	}
+}
+
+// expandVariablesPolicy provides the equivalent set of variables capabilities
+// for a namespace policy
+func expandVariablesPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{VariablesCapabilityDeny}
+	case PolicyRead:
+		return []string{VariablesCapabilityList, VariablesCapabilityRead}
+	case PolicyWrite:
+		return []string{VariablesCapabilityList, VariablesCapabilityRead,
+			VariablesCapabilityWrite, VariablesCapabilityDestroy}
+	default:
+		return nil
+	}
+}
+
 func isHostVolumeCapabilityValid(cap string) bool {
 	switch cap {
 	case HostVolumeCapabilityDeny, HostVolumeCapabilityMountReadOnly, HostVolumeCapabilityMountReadWrite:
@@ -269,6 +329,20 @@ func Parse(rules string) (*Policy, error) {
 			}
 		}
 
+		if ns.Variables != nil {
+			for _, pathPolicy := range ns.Variables.Paths {
+				if pathPolicy.PathSpec == "" {
+					return nil, fmt.Errorf("Invalid missing variables path in namespace %#v", ns)
+				}
+				for _, cap := range pathPolicy.Capabilities {
+					if !isVariablesCapabilityValid(cap) {
+						return nil, fmt.Errorf(
+							"Invalid variables capability '%s' in namespace %#v", cap, ns)
+					}
+				}
+			}
+		}
+
 		// Expand the short hand policy to the capabilities and
 		// add to any existing capabilities
 		if ns.Policy != "" {
@@ -36,6 +36,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityReadJob,
 							NamespaceCapabilityCSIListVolume,
 							NamespaceCapabilityCSIReadVolume,
+							NamespaceCapabilityHostVolumeRead,
 							NamespaceCapabilityReadJobScaling,
 							NamespaceCapabilityListScalingPolicies,
 							NamespaceCapabilityReadScalingPolicy,
@@ -86,6 +87,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityReadJob,
 							NamespaceCapabilityCSIListVolume,
 							NamespaceCapabilityCSIReadVolume,
+							NamespaceCapabilityHostVolumeRead,
 							NamespaceCapabilityReadJobScaling,
 							NamespaceCapabilityListScalingPolicies,
 							NamespaceCapabilityReadScalingPolicy,
@@ -100,6 +102,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityReadJob,
 							NamespaceCapabilityCSIListVolume,
 							NamespaceCapabilityCSIReadVolume,
+							NamespaceCapabilityHostVolumeRead,
 							NamespaceCapabilityReadJobScaling,
 							NamespaceCapabilityListScalingPolicies,
 							NamespaceCapabilityReadScalingPolicy,
@@ -109,6 +112,7 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityReadLogs,
 							NamespaceCapabilityReadFS,
 							NamespaceCapabilityAllocExec,
+							NamespaceCapabilityAllocAction,
 							NamespaceCapabilityAllocLifecycle,
 							NamespaceCapabilityCSIMountVolume,
 							NamespaceCapabilityCSIWriteVolume,
@@ -150,6 +154,25 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			`
+			namespace "default" {
+				capabilities = ["alloc-action", "read-job"]
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Capabilities: []string{
+							NamespaceCapabilityAllocAction,
+							NamespaceCapabilityReadJob,
+						},
+					},
+				},
+			},
+		},
 		{
 			`
 			namespace "default" {
@@ -168,6 +191,53 @@ func TestParse(t *testing.T) {
 			"Invalid namespace capability",
 			nil,
 		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "nomad/jobs/*" {
+						capabilities = ["read", "foo"]
+					}
+				}
+			}
+			`,
+			"Invalid variables capability",
+			nil,
+		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "nomad/jobs/*" {
+						capabilities = ["list", "read"]
+					}
+					path "secrets/db" {
+						capabilities = ["write", "destroy"]
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec:     "nomad/jobs/*",
+									Capabilities: []string{VariablesCapabilityList, VariablesCapabilityRead},
+								},
+								{
+									PathSpec:     "secrets/db",
+									Capabilities: []string{VariablesCapabilityWrite, VariablesCapabilityDestroy},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			`
 			agent {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
@@ -202,6 +203,267 @@ func (a *ACLTokens) ExchangeOneTimeToken(secret string, q *WriteOptions) (*ACLTo
 	return resp.Token, wm, nil
 }
 
+// ACLRoles is used to query the ACL Role endpoints.
+type ACLRoles struct {
+	client *Client
+}
+
+// ACLRoles returns a new handle on the ACL roles API client.
+func (c *Client) ACLRoles() *ACLRoles {
+	return &ACLRoles{client: c}
+}
+
+// List is used to detail all the ACL roles currently stored within state.
+func (a *ACLRoles) List(q *QueryOptions) ([]*ACLRoleListStub, *QueryMeta, error) {
+	var resp []*ACLRoleListStub
+	qm, err := a.client.query("/v1/acl/roles", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Create is used to create an ACL role.
+func (a *ACLRoles) Create(role *ACLRole, w *WriteOptions) (*ACLRole, *WriteMeta, error) {
+	if role.ID != "" {
+		return nil, nil, errors.New("cannot specify ACL role ID")
+	}
+	var resp ACLRole
+	wm, err := a.client.write("/v1/acl/role", role, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Update is used to update an existing ACL role.
+func (a *ACLRoles) Update(role *ACLRole, w *WriteOptions) (*ACLRole, *WriteMeta, error) {
+	if role.ID == "" {
+		return nil, nil, errors.New("missing ACL role ID")
+	}
+	var resp ACLRole
+	wm, err := a.client.write("/v1/acl/role/"+role.ID, role, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete an ACL role.
+func (a *ACLRoles) Delete(roleID string, w *WriteOptions) (*WriteMeta, error) {
+	if roleID == "" {
+		return nil, errors.New("missing ACL role ID")
+	}
+	wm, err := a.client.delete("/v1/acl/role/"+roleID, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Get is used to look up an ACL role.
+func (a *ACLRoles) Get(roleID string, q *QueryOptions) (*ACLRole, *QueryMeta, error) {
+	if roleID == "" {
+		return nil, nil, errors.New("missing ACL role ID")
+	}
+	var resp ACLRole
+	qm, err := a.client.query("/v1/acl/role/"+roleID, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// GetByName is used to look up an ACL role using its name.
+func (a *ACLRoles) GetByName(roleName string, q *QueryOptions) (*ACLRole, *QueryMeta, error) {
+	if roleName == "" {
+		return nil, nil, errors.New("missing ACL role name")
+	}
+	var resp ACLRole
+	qm, err := a.client.query("/v1/acl/role/name/"+roleName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// ACLAuthMethods is used to query the ACL auth method endpoints.
+type ACLAuthMethods struct {
+	client *Client
+}
+
+// ACLAuthMethods returns a new handle on the ACL auth methods API client.
+func (c *Client) ACLAuthMethods() *ACLAuthMethods {
+	return &ACLAuthMethods{client: c}
+}
+
+// List is used to detail all the ACL auth methods currently stored within
+// state.
+func (a *ACLAuthMethods) List(q *QueryOptions) ([]*ACLAuthMethodListStub, *QueryMeta, error) {
+	var resp []*ACLAuthMethodListStub
+	qm, err := a.client.query("/v1/acl/auth-methods", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Create is used to create an ACL auth method.
+func (a *ACLAuthMethods) Create(authMethod *ACLAuthMethod, w *WriteOptions) (*ACLAuthMethod, *WriteMeta, error) {
+	if authMethod.Name == "" {
+		return nil, nil, errors.New("missing ACL auth method name")
+	}
+	var resp ACLAuthMethod
+	wm, err := a.client.write("/v1/acl/auth-method", authMethod, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Update is used to update an existing ACL auth method.
+func (a *ACLAuthMethods) Update(authMethod *ACLAuthMethod, w *WriteOptions) (*ACLAuthMethod, *WriteMeta, error) {
+	if authMethod.Name == "" {
+		return nil, nil, errors.New("missing ACL auth method name")
+	}
+	var resp ACLAuthMethod
+	wm, err := a.client.write("/v1/acl/auth-method/"+authMethod.Name, authMethod, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete an ACL auth method. The binding rules of the auth
+// method are also deleted.
+func (a *ACLAuthMethods) Delete(authMethodName string, w *WriteOptions) (*WriteMeta, error) {
+	if authMethodName == "" {
+		return nil, errors.New("missing ACL auth method name")
+	}
+	wm, err := a.client.delete("/v1/acl/auth-method/"+authMethodName, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Get is used to look up an ACL auth method.
+func (a *ACLAuthMethods) Get(authMethodName string, q *QueryOptions) (*ACLAuthMethod, *QueryMeta, error) {
+	if authMethodName == "" {
+		return nil, nil, errors.New("missing ACL auth method name")
+	}
+	var resp ACLAuthMethod
+	qm, err := a.client.query("/v1/acl/auth-method/"+authMethodName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// ACLBindingRules is used to query the ACL binding rule endpoints.
+type ACLBindingRules struct {
+	client *Client
+}
+
+// ACLBindingRules returns a new handle on the ACL binding rules API client.
+func (c *Client) ACLBindingRules() *ACLBindingRules {
+	return &ACLBindingRules{client: c}
+}
+
+// List is used to detail all the ACL binding rules currently stored within
+// state.
+func (a *ACLBindingRules) List(q *QueryOptions) ([]*ACLBindingRuleListStub, *QueryMeta, error) {
+	var resp []*ACLBindingRuleListStub
+	qm, err := a.client.query("/v1/acl/binding-rules", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Create is used to create an ACL binding rule.
+func (a *ACLBindingRules) Create(bindingRule *ACLBindingRule, w *WriteOptions) (*ACLBindingRule, *WriteMeta, error) {
+	if bindingRule.ID != "" {
+		return nil, nil, errors.New("cannot specify ACL binding rule ID")
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.write("/v1/acl/binding-rule", bindingRule, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Update is used to update an existing ACL binding rule.
+func (a *ACLBindingRules) Update(bindingRule *ACLBindingRule, w *WriteOptions) (*ACLBindingRule, *WriteMeta, error) {
+	if bindingRule.ID == "" {
+		return nil, nil, errors.New("missing ACL binding rule ID")
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.write("/v1/acl/binding-rule/"+bindingRule.ID, bindingRule, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete an ACL binding rule.
+func (a *ACLBindingRules) Delete(bindingRuleID string, w *WriteOptions) (*WriteMeta, error) {
+	if bindingRuleID == "" {
+		return nil, errors.New("missing ACL binding rule ID")
+	}
+	wm, err := a.client.delete("/v1/acl/binding-rule/"+bindingRuleID, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Get is used to look up an ACL binding rule.
+func (a *ACLBindingRules) Get(bindingRuleID string, q *QueryOptions) (*ACLBindingRule, *QueryMeta, error) {
+	if bindingRuleID == "" {
+		return nil, nil, errors.New("missing ACL binding rule ID")
+	}
+	var resp ACLBindingRule
+	qm, err := a.client.query("/v1/acl/binding-rule/"+bindingRuleID, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// ACLOIDC is used to query the ACL OIDC login endpoints.
+type ACLOIDC struct {
+	client *Client
+}
+
+// ACLOIDC returns a new handle on the ACL OIDC login API client.
+func (c *Client) ACLOIDC() *ACLOIDC {
+	return &ACLOIDC{client: c}
+}
+
+// GetAuthURL generates the URL which the user must visit in order to
+// authenticate against the identity provider of an OIDC auth method.
+func (a *ACLOIDC) GetAuthURL(req *ACLOIDCAuthURLRequest, w *WriteOptions) (*ACLOIDCAuthURLResponse, *WriteMeta, error) {
+	var resp ACLOIDCAuthURLResponse
+	wm, err := a.client.write("/v1/acl/oidc/auth-url", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// CompleteAuth exchanges the OIDC provider token for a Nomad ACL token, which
+// expires after the max token TTL of the auth method.
+func (a *ACLOIDC) CompleteAuth(req *ACLOIDCCompleteAuthRequest, w *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	var resp ACLToken
+	wm, err := a.client.write("/v1/acl/oidc/complete-auth", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // ACLPolicyListStub is used to for listing ACL policies
 type ACLPolicyListStub struct {
 	Name        string
@@ -221,26 +483,44 @@ type ACLPolicy struct {
 
 // ACLToken represents a client token which is used to Authenticate
 type ACLToken struct {
-	AccessorID  string
-	SecretID    string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	CreateTime  time.Time
+	AccessorID string
+	SecretID   string
+	Name       string
+	Type       string
+	Policies   []string
+
+	// Roles represents the ACL roles that this token is tied to. The token
+	// will inherit the permissions of all policies detailed within the role.
+	Roles []*ACLTokenRoleLink
+
+	Global     bool
+	CreateTime time.Time
+
+	// ExpirationTime represents the point after which the token should be
+	// considered revoked. Tokens created by an auth method expire after the
+	// max token TTL of the auth method.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is a convenience field for helping set ExpirationTime to
+	// a value of CreateTime+ExpirationTTL. This can only be set during token
+	// creation.
+	ExpirationTTL time.Duration
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	CreateTime  time.Time
-	CreateIndex uint64
-	ModifyIndex uint64
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []*ACLTokenRoleLink
+	Global         bool
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
 }
 
 type OneTimeToken struct {
@@ -267,3 +547,285 @@ type OneTimeTokenExchangeResponse struct {
 type BootstrapRequest struct {
 	BootstrapSecret string
 }
+
+// ACLTokenRoleLink is used to link an ACL token to an ACL role. The ACL token
+// can therefore inherit all the ACL policy permissions that the ACL role
+// contains.
+type ACLTokenRoleLink struct {
+
+	// ID is the ACLRole.ID UUID. This field is immutable and represents the
+	// absolute truth for the link.
+	ID string
+
+	// Name is the human friendly identifier for the ACL role and is a
+	// convenience field for operators. It is resolved to the ID when the
+	// token is created or updated.
+	Name string
+}
+
+// ACLRole is an abstraction for the ACL system which allows the grouping of
+// ACL policies into a single object. ACL tokens can be created and linked to
+// a role; the token then inherits all the permissions granted by the
+// policies.
+type ACLRole struct {
+
+	// ID is an internally generated UUID for this role and is controlled by
+	// Nomad. It can be used after role creation to update the existing role.
+	ID string
+
+	// Name is unique across the entire set of federated clusters and is
+	// supplied by the operator on role creation. The name can be modified by
+	// updating the role and including the Nomad generated ID. This update
+	// will not affect tokens created and linked to this role. This is a
+	// required field.
+	Name string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the role. This is an optional field.
+	Description string
+
+	// Policies is an array of ACL policy links. Although currently policies
+	// can only be linked using their name, in the future we will want to add
+	// IDs and thus allow operators to specify either a name, an ID, or both.
+	// At least one entry is required.
+	Policies []*ACLRolePolicyLink
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRolePolicyLink is used to link a policy to an ACL role. We use a struct
+// rather than a list of strings as in the future we will want to add IDs to
+// policies and then link via these.
+type ACLRolePolicyLink struct {
+
+	// Name is the ACLPolicy.Name value which will be linked to the ACL role.
+	Name string
+}
+
+// ACLRoleListStub is the stub object returned when performing a listing of
+// ACL roles. While it might not currently be different to the full response
+// object, it allows us to future-proof the RPC in the event the ACLRole
+// object grows over time.
+type ACLRoleListStub struct {
+
+	// ID is an internally generated UUID for this role and is controlled by
+	// Nomad.
+	ID string
+
+	// Name is unique across the entire set of federated clusters and is
+	// supplied by the operator on role creation.
+	Name string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the role.
+	Description string
+
+	// Policies is an array of ACL policy links.
+	Policies []*ACLRolePolicyLink
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+const (
+	// ACLAuthMethodTokenLocalityLocal is the ACLAuthMethod.TokenLocality that
+	// will generate ACL tokens which can only be used on the local cluster the
+	// request was made.
+	ACLAuthMethodTokenLocalityLocal = "local"
+
+	// ACLAuthMethodTokenLocalityGlobal is the ACLAuthMethod.TokenLocality that
+	// will generate ACL tokens which can be used on all federated clusters.
+	ACLAuthMethodTokenLocalityGlobal = "global"
+
+	// ACLAuthMethodTypeOIDC is the ACLAuthMethod.Type and represents an
+	// auth method which uses the OIDC protocol.
+	ACLAuthMethodTypeOIDC = "OIDC"
+)
+
+// ACLAuthMethod is used to capture the properties of an authentication method
+// used for single sign-on.
+type ACLAuthMethod struct {
+
+	// Name is the identifier for this auth method and is a required
+	// parameter.
+	Name string
+
+	// Type is the SSO identifier this auth method is. Currently, the only
+	// supported value is "OIDC".
+	Type string
+
+	// TokenLocality defines whether the ACL tokens created by this auth
+	// method are local to the region or global. Valid values are "local"
+	// and "global".
+	TokenLocality string
+
+	// MaxTokenTTL is the maximum life of the ACL tokens created by this auth
+	// method.
+	MaxTokenTTL time.Duration
+
+	// Default identifies whether this is the default auth method used by
+	// the login command when one is not specified.
+	Default bool
+
+	// Config contains the detailed configuration which is specific to the
+	// auth method type.
+	Config *ACLAuthMethodConfig
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is used to store configuration of an auth method.
+type ACLAuthMethodConfig struct {
+	OIDCDiscoveryURL    string
+	OIDCClientID        string
+	OIDCClientSecret    string
+	OIDCScopes          []string
+	BoundAudiences      []string
+	AllowedRedirectURIs []string
+	DiscoveryCaPem      []string
+	SigningAlgs         []string
+	ClaimMappings       map[string]string
+	ListClaimMappings   map[string]string
+}
+
+// ACLAuthMethodListStub is the stub object returned when performing a listing
+// of ACL auth methods. It is intentionally minimal due to the unauthenticated
+// nature of the list endpoint.
+type ACLAuthMethodListStub struct {
+	Name    string
+	Type    string
+	Default bool
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+const (
+	// ACLBindingRuleBindTypeRole is the ACL binding rule bind type that only
+	// allows the binding rule to function if a role exists at login-time. The
+	// role name will be specified within the ACLBindingRule.BindName
+	// parameter.
+	ACLBindingRuleBindTypeRole = "role"
+
+	// ACLBindingRuleBindTypePolicy is the ACL binding rule bind type that
+	// assigns a policy to the generated ACL token. The policy name will be
+	// specified within the ACLBindingRule.BindName parameter.
+	ACLBindingRuleBindTypePolicy = "policy"
+
+	// ACLBindingRuleBindTypeManagement is the ACL binding rule bind type that
+	// will generate management ACL tokens when matched.
+	ACLBindingRuleBindTypeManagement = "management"
+)
+
+// ACLBindingRule contains a direct relation to an ACLAuthMethod and
+// represents a rule to apply when logging in via the named AuthMethod. This
+// allows the transformation of OIDC provider claims, to Nomad based ACL
+// concepts such as ACL Roles and Policies.
+type ACLBindingRule struct {
+
+	// ID is an internally generated UUID for this rule and is controlled by
+	// Nomad.
+	ID string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the binding rule. This is an
+	// operational field.
+	Description string
+
+	// AuthMethod is the name of the auth method for which this rule applies
+	// to. This is required and the method must exist within state before the
+	// cluster administrator can create the rule.
+	AuthMethod string
+
+	// Selector is an expression that matches against verified identity
+	// attributes returned from the auth method during login. This is
+	// optional and when not set, provides a catch-all rule.
+	Selector string
+
+	// BindType adjusts how this binding rule is applied at login time. The
+	// valid values are ACLBindingRuleBindTypeRole,
+	// ACLBindingRuleBindTypePolicy and ACLBindingRuleBindTypeManagement.
+	BindType string
+
+	// BindName is the target of the binding. It can be templated using the
+	// selector variables, for example "${value.team}". It must be empty when
+	// the BindType is ACLBindingRuleBindTypeManagement.
+	BindName string
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRuleListStub is the stub object returned when performing a
+// listing of ACL binding rules.
+type ACLBindingRuleListStub struct {
+
+	// ID is an internally generated UUID for this rule and is controlled by
+	// Nomad.
+	ID string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the binding rule. This is an
+	// operational field.
+	Description string
+
+	// AuthMethod is the name of the auth method for which this rule applies
+	// to.
+	AuthMethod string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLOIDCAuthURLRequest is the request to make when starting the OIDC
+// authentication login flow.
+type ACLOIDCAuthURLRequest struct {
+
+	// AuthMethodName is the OIDC auth method to use. This is a required
+	// parameter.
+	AuthMethodName string
+
+	// RedirectURI is the URL that authorization should redirect to. This is a
+	// required parameter.
+	RedirectURI string
+
+	// ClientNonce is a randomly generated string to prevent replay attacks.
+	// It is up to the client to generate this, and the same value must be
+	// passed when completing the login. This is a required parameter.
+	ClientNonce string
+}
+
+// ACLOIDCAuthURLResponse is the response when starting the OIDC
+// authentication login flow.
+type ACLOIDCAuthURLResponse struct {
+
+	// AuthURL is URL to begin authorization and is where the user logging in
+	// should go.
+	AuthURL string
+}
+
+// ACLOIDCCompleteAuthRequest is the request object to begin completing the
+// OIDC auth cycle after receiving the callback from the OIDC provider.
+type ACLOIDCCompleteAuthRequest struct {
+
+	// AuthMethodName is the name of the auth method being used to login via
+	// OIDC. This will match ACLOIDCAuthURLRequest.AuthMethodName. This is a
+	// required parameter.
+	AuthMethodName string
+
+	// ClientNonce, State, and Code are provided from the parameters given to
+	// the redirect URL. These are all required parameters.
+	ClientNonce string
+	State       string
+	Code        string
+
+	// RedirectURI is the URL that authorization should redirect to. This is a
+	// required parameter.
+	RedirectURI string
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/api/internal/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestACLPolicies_ListUpsert(t *testing.T) {
@@ -299,3 +301,217 @@ func TestACLTokens_BootstrapValidToken(t *testing.T) {
 	assertWriteMeta(t, wm)
 	assert.Equal(t, bootkn, out.SecretID)
 }
+
+func TestACLRoles(t *testing.T) {
+	testutil.Parallel(t)
+	testClient, testServer, _ := makeACLClient(t, nil, nil)
+	defer testServer.Stop()
+
+	// An initial listing shouldn't return any results.
+	aclRoleListResp, queryMeta, err := testClient.ACLRoles().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclRoleListResp)
+	assertQueryMeta(t, queryMeta)
+
+	// Create an ACL policy that can be referenced within the ACL role.
+	aclPolicy := ACLPolicy{
+		Name: "acl-role-api-test",
+		Rules: `namespace "default" {
+			policy = "read"
+		}
+		`,
+	}
+	writeMeta, err := testClient.ACLPolicies().Upsert(&aclPolicy, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	// Create an ACL role referencing the previously created policy.
+	role := ACLRole{
+		Name:     "acl-role-api-test",
+		Policies: []*ACLRolePolicyLink{{Name: aclPolicy.Name}},
+	}
+	aclRoleCreateResp, writeMeta, err := testClient.ACLRoles().Create(&role, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.NotEmpty(t, aclRoleCreateResp.ID)
+	require.Equal(t, role.Name, aclRoleCreateResp.Name)
+
+	// Another listing should return one result.
+	aclRoleListResp, queryMeta, err = testClient.ACLRoles().List(nil)
+	require.NoError(t, err)
+	require.Len(t, aclRoleListResp, 1)
+	assertQueryMeta(t, queryMeta)
+
+	// Read the role using its ID.
+	aclRoleReadResp, queryMeta, err := testClient.ACLRoles().Get(aclRoleCreateResp.ID, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, aclRoleCreateResp, aclRoleReadResp)
+
+	// Read the role using its name.
+	aclRoleReadResp, queryMeta, err = testClient.ACLRoles().GetByName(aclRoleCreateResp.Name, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, aclRoleCreateResp, aclRoleReadResp)
+
+	// Update the role name.
+	role.ID = aclRoleCreateResp.ID
+	role.Name = "acl-role-api-test-badger-badger-badger"
+	aclRoleUpdateResp, writeMeta, err := testClient.ACLRoles().Update(&role, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, role.Name, aclRoleUpdateResp.Name)
+	require.Equal(t, role.ID, aclRoleUpdateResp.ID)
+
+	// Create a token linked to the role using its name.
+	token := ACLToken{
+		Type:  "client",
+		Roles: []*ACLTokenRoleLink{{Name: role.Name}},
+	}
+	tokenCreateResp, _, err := testClient.ACLTokens().Create(&token, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*ACLTokenRoleLink{{ID: role.ID}}, tokenCreateResp.Roles)
+
+	// Delete the role.
+	writeMeta, err = testClient.ACLRoles().Delete(aclRoleCreateResp.ID, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	// Make sure there are no ACL roles now present.
+	aclRoleListResp, queryMeta, err = testClient.ACLRoles().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclRoleListResp)
+	assertQueryMeta(t, queryMeta)
+}
+
+func TestACLAuthMethods(t *testing.T) {
+	testutil.Parallel(t)
+	testClient, testServer, _ := makeACLClient(t, nil, nil)
+	defer testServer.Stop()
+
+	// An initial listing shouldn't return any results.
+	aclAuthMethodsListResp, queryMeta, err := testClient.ACLAuthMethods().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclAuthMethodsListResp)
+	assertQueryMeta(t, queryMeta)
+
+	// Create an ACL auth method.
+	authMethod := ACLAuthMethod{
+		Name:          "acl-auth-method-api-test",
+		Type:          ACLAuthMethodTypeOIDC,
+		TokenLocality: ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   15 * time.Minute,
+		Default:       true,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		},
+	}
+	aclAuthMethodCreateResp, writeMeta, err := testClient.ACLAuthMethods().Create(&authMethod, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, authMethod.Name, aclAuthMethodCreateResp.Name)
+
+	// Another listing should return one result.
+	aclAuthMethodsListResp, queryMeta, err = testClient.ACLAuthMethods().List(nil)
+	require.NoError(t, err)
+	require.Len(t, aclAuthMethodsListResp, 1)
+	require.True(t, aclAuthMethodsListResp[0].Default)
+	assertQueryMeta(t, queryMeta)
+
+	// Read the auth method using its name.
+	aclAuthMethodReadResp, queryMeta, err := testClient.ACLAuthMethods().Get(authMethod.Name, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, aclAuthMethodCreateResp, aclAuthMethodReadResp)
+
+	// Update the auth method max token TTL.
+	authMethod.MaxTokenTTL = time.Hour
+	aclAuthMethodUpdateResp, writeMeta, err := testClient.ACLAuthMethods().Update(&authMethod, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, time.Hour, aclAuthMethodUpdateResp.MaxTokenTTL)
+
+	// Delete the auth method.
+	writeMeta, err = testClient.ACLAuthMethods().Delete(authMethod.Name, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	// Make sure there are no ACL auth methods now present.
+	aclAuthMethodsListResp, queryMeta, err = testClient.ACLAuthMethods().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclAuthMethodsListResp)
+	assertQueryMeta(t, queryMeta)
+}
+
+func TestACLBindingRules(t *testing.T) {
+	testutil.Parallel(t)
+	testClient, testServer, _ := makeACLClient(t, nil, nil)
+	defer testServer.Stop()
+
+	// An initial listing shouldn't return any results.
+	aclBindingRulesListResp, queryMeta, err := testClient.ACLBindingRules().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclBindingRulesListResp)
+	assertQueryMeta(t, queryMeta)
+
+	// Create an ACL auth method, which the binding rule will reference.
+	authMethod := ACLAuthMethod{
+		Name:          "acl-binding-rule-api-test",
+		Type:          ACLAuthMethodTypeOIDC,
+		TokenLocality: ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   15 * time.Minute,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		},
+	}
+	_, _, err = testClient.ACLAuthMethods().Create(&authMethod, nil)
+	require.NoError(t, err)
+
+	// Create an ACL binding rule.
+	bindingRule := ACLBindingRule{
+		Description: "my-binding-rule",
+		AuthMethod:  authMethod.Name,
+		Selector:    `"engineering" in list.roles`,
+		BindType:    ACLBindingRuleBindTypeRole,
+		BindName:    "engineering",
+	}
+	aclBindingRuleCreateResp, writeMeta, err := testClient.ACLBindingRules().Create(&bindingRule, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.NotEmpty(t, aclBindingRuleCreateResp.ID)
+
+	// Another listing should return one result.
+	aclBindingRulesListResp, queryMeta, err = testClient.ACLBindingRules().List(nil)
+	require.NoError(t, err)
+	require.Len(t, aclBindingRulesListResp, 1)
+	assertQueryMeta(t, queryMeta)
+
+	// Read the binding rule using its ID.
+	aclBindingRuleReadResp, queryMeta, err := testClient.ACLBindingRules().Get(aclBindingRuleCreateResp.ID, nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, queryMeta)
+	require.Equal(t, aclBindingRuleCreateResp, aclBindingRuleReadResp)
+
+	// Update the binding rule description.
+	bindingRule.ID = aclBindingRuleCreateResp.ID
+	bindingRule.Description = "my-binding-rule-updated"
+	aclBindingRuleUpdateResp, writeMeta, err := testClient.ACLBindingRules().Update(&bindingRule, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+	require.Equal(t, bindingRule.Description, aclBindingRuleUpdateResp.Description)
+
+	// Delete the binding rule.
+	writeMeta, err = testClient.ACLBindingRules().Delete(bindingRule.ID, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, writeMeta)
+
+	// Make sure there are no ACL binding rules now present.
+	aclBindingRulesListResp, queryMeta, err = testClient.ACLBindingRules().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, aclBindingRulesListResp)
+	assertQueryMeta(t, queryMeta)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -99,6 +100,29 @@ func (a *Allocations) Exec(ctx context.Context,
 	return s.run(ctx)
 }
 
+// ExecAction is used to run an action defined by a task in the allocation,
+// streaming its output. Actions are run non-interactively, without a tty or
+// stdin. It returns the exit code of the action.
+func (a *Allocations) ExecAction(ctx context.Context,
+	alloc *Allocation, task, action string,
+	stdout, stderr io.Writer, q *QueryOptions) (exitCode int, err error) {
+
+	s := &execSession{
+		client: a.client,
+		alloc:  alloc,
+		task:   task,
+		action: action,
+
+		stdin:  strings.NewReader(""),
+		stdout: stdout,
+		stderr: stderr,
+
+		q: q,
+	}
+
+	return s.run(ctx)
+}
+
 func (a *Allocations) Stats(alloc *Allocation, q *QueryOptions) (*AllocResourceUsage, error) {
 	var resp AllocResourceUsage
 	path := fmt.Sprintf("/v1/client/allocation/%s/stats", alloc.ID)
@@ -444,6 +468,10 @@ type DesiredTransition struct {
 	// Reschedule is used to indicate that this allocation is eligible to be
 	// rescheduled.
 	Reschedule *bool
+
+	// Unpin is used to indicate that the replacement of this allocation may
+	// be placed on any node, even if its task group uses sticky_host.
+	Unpin *bool
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -451,6 +479,12 @@ func (d DesiredTransition) ShouldMigrate() bool {
 	return d.Migrate != nil && *d.Migrate
 }
 
+// ShouldUnpin returns whether the transition object dictates that the
+// replacement of the allocation is not pinned to its node.
+func (d DesiredTransition) ShouldUnpin() bool {
+	return d.Unpin != nil && *d.Unpin
+}
+
 // ExecStreamingIOOperation represents a stream write operation: either appending data or close (exclusively)
 type ExecStreamingIOOperation struct {
 	Data  []byte `json:"data,omitempty"`
@@ -25,6 +25,7 @@ type execSession struct {
 	task    string
 	tty     bool
 	command []string
+	action  string
 
 	stdin  io.Reader
 	stdout io.Writer
@@ -83,14 +84,17 @@ func (s *execSession) startConnection() (*websocket.Conn, error) {
 		q.Params = make(map[string]string)
 	}
 
-	commandBytes, err := json.Marshal(s.command)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal command: %W", err)
-	}
-
 	q.Params["tty"] = strconv.FormatBool(s.tty)
 	q.Params["task"] = s.task
-	q.Params["command"] = string(commandBytes)
+	if s.action != "" {
+		q.Params["action"] = s.action
+	} else {
+		commandBytes, err := json.Marshal(s.command)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal command: %W", err)
+		}
+		q.Params["command"] = string(commandBytes)
+	}
 
 	reqPath := fmt.Sprintf("/v1/client/allocation/%s/exec", s.alloc.ID)
 
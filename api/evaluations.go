@@ -40,6 +40,23 @@ func (e *Evaluations) Info(evalID string, q *QueryOptions) (*Evaluation, *QueryM
 	return &resp, qm, nil
 }
 
+// Delete is used to delete the given pending or blocked evaluations. The eval
+// broker, or the evaluations of the jobs of the evaluations, must be paused.
+func (e *Evaluations) Delete(evalIDs []string, w *WriteOptions) (*EvalDeleteResponse, *WriteMeta, error) {
+	return e.DeleteOpts(&EvalDeleteRequest{EvalIDs: evalIDs}, w)
+}
+
+// DeleteOpts is used to delete pending or blocked evaluations either by ID or
+// by matching a filter expression.
+func (e *Evaluations) DeleteOpts(req *EvalDeleteRequest, w *WriteOptions) (*EvalDeleteResponse, *WriteMeta, error) {
+	var resp EvalDeleteResponse
+	wm, err := e.client.deleteOpts("/v1/evaluations", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Allocations is used to retrieve a set of allocations given
 // an evaluation ID.
 func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
@@ -52,6 +69,20 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 	return resp, qm, nil
 }
 
+// EvalDeleteRequest is used to delete pending or blocked evaluations. Exactly
+// one of EvalIDs or Filter must be set.
+type EvalDeleteRequest struct {
+	EvalIDs []string
+	Filter  string
+	WriteRequest
+}
+
+// EvalDeleteResponse is the response to an EvalDeleteRequest.
+type EvalDeleteResponse struct {
+	// Count is the number of evaluations that were deleted.
+	Count int
+}
+
 // Evaluation is used to serialize an evaluation.
 type Evaluation struct {
 	ID                   string
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/url"
+	"sort"
+)
+
+// SinkType is the type of destination an EventSink delivers events to.
+type SinkType string
+
+const (
+	// SinkWebhook delivers events with HTTP POST requests to an address.
+	SinkWebhook SinkType = "webhook"
+
+	// SinkFile appends events as newline delimited JSON to a file local to
+	// the leader.
+	SinkFile SinkType = "file"
+)
+
+// EventSink is a server managed subscription to the event stream, whose
+// events the leader delivers to a webhook or a file.
+type EventSink struct {
+	ID          string
+	Type        SinkType
+	Topics      map[Topic][]string
+	Namespace   string
+	Address     string
+	Headers     map[string]string
+	Path        string
+	LatestIndex uint64
+
+	// LostEventsIndex is the index of the last events delivered to the sink
+	// before events left the event buffer of the leader before they could
+	// be delivered. It is zero if the sink never lost events.
+	LostEventsIndex uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EventSinks is used to query the event sink endpoints.
+type EventSinks struct {
+	client *Client
+}
+
+// EventSinks returns a new handle on the event sinks.
+func (c *Client) EventSinks() *EventSinks {
+	return &EventSinks{client: c}
+}
+
+// List is used to dump all of the event sinks.
+func (e *EventSinks) List(q *QueryOptions) ([]*EventSink, *QueryMeta, error) {
+	var resp []*EventSink
+	qm, err := e.client.query("/v1/event/sinks", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].ID < resp[j].ID })
+	return resp, qm, nil
+}
+
+// Info is used to query a single event sink by its ID.
+func (e *EventSinks) Info(id string, q *QueryOptions) (*EventSink, *QueryMeta, error) {
+	var resp EventSink
+	qm, err := e.client.query("/v1/event/sink/"+url.PathEscape(id), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to register or update an event sink. The delivery of the
+// events to an updated sink resumes where it stopped.
+func (e *EventSinks) Register(sink *EventSink, q *WriteOptions) (*WriteMeta, error) {
+	wm, err := e.client.write("/v1/event/sink/"+url.PathEscape(sink.ID), sink, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Deregister is used to delete an event sink.
+func (e *EventSinks) Deregister(id string, q *WriteOptions) (*WriteMeta, error) {
+	wm, err := e.client.delete("/v1/event/sink/"+url.PathEscape(id), nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
@@ -11,13 +11,21 @@ import (
 )
 
 const (
-	TopicDeployment Topic = "Deployment"
-	TopicEvaluation Topic = "Evaluation"
-	TopicAllocation Topic = "Allocation"
-	TopicJob        Topic = "Job"
-	TopicNode       Topic = "Node"
-	TopicService    Topic = "Service"
-	TopicAll        Topic = "*"
+	TopicDeployment     Topic = "Deployment"
+	TopicEvaluation     Topic = "Evaluation"
+	TopicAllocation     Topic = "Allocation"
+	TopicJob            Topic = "Job"
+	TopicNode           Topic = "Node"
+	TopicService        Topic = "Service"
+	TopicMaintenance    Topic = "Maintenance"
+	TopicCSIVolume      Topic = "CSIVolume"
+	TopicCSIPlugin      Topic = "CSIPlugin"
+	TopicHostVolume     Topic = "HostVolume"
+	TopicNamespace      Topic = "Namespace"
+	TopicScalingPolicy  Topic = "ScalingPolicy"
+	TopicPeriodicLaunch Topic = "PeriodicLaunch"
+	TopicOperator       Topic = "Operator"
+	TopicAll            Topic = "*"
 )
 
 // Events is a set of events for a corresponding index. Events returned for the
@@ -102,13 +110,103 @@ func (e *Event) Service() (*ServiceRegistration, error) {
 	return out.Service, nil
 }
 
+// MaintenancePlan returns a MaintenancePlan struct from a given event
+// payload. If the Event Topic is Maintenance this will return a valid
+// MaintenancePlan.
+func (e *Event) MaintenancePlan() (*MaintenancePlan, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Plan, nil
+}
+
+// CSIVolume returns a CSIVolume struct from a given event payload. If the
+// Event Topic is CSIVolume this will return a valid CSIVolume.
+func (e *Event) CSIVolume() (*CSIVolume, error) {
+	// The fields of CSIVolume are tagged for decoding volume specifications,
+	// so the payload is decoded from its JSON encoding instead
+	raw, ok := e.Payload["Volume"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var out CSIVolume
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CSIPlugin returns a CSIPlugin struct from a given event payload. If the
+// Event Topic is CSIPlugin this will return a valid CSIPlugin.
+func (e *Event) CSIPlugin() (*CSIPlugin, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Plugin, nil
+}
+
+// HostVolume returns a HostVolume struct from a given event payload. If the
+// Event Topic is HostVolume this will return a valid HostVolume.
+func (e *Event) HostVolume() (*HostVolume, error) {
+	// The fields of HostVolume are tagged for decoding volume
+	// specifications, so the payload is decoded from its JSON encoding
+	// instead
+	raw, ok := e.Payload["HostVolume"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var out HostVolume
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Namespace returns a Namespace struct from a given event payload. If the
+// Event Topic is Namespace this will return a valid Namespace.
+func (e *Event) Namespace() (*Namespace, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Namespace, nil
+}
+
+// ScalingPolicy returns a ScalingPolicy struct from a given event payload. If
+// the Event Topic is ScalingPolicy this will return a valid ScalingPolicy.
+func (e *Event) ScalingPolicy() (*ScalingPolicy, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.ScalingPolicy, nil
+}
+
 type eventPayload struct {
-	Allocation *Allocation          `mapstructure:"Allocation"`
-	Deployment *Deployment          `mapstructure:"Deployment"`
-	Evaluation *Evaluation          `mapstructure:"Evaluation"`
-	Job        *Job                 `mapstructure:"Job"`
-	Node       *Node                `mapstructure:"Node"`
-	Service    *ServiceRegistration `mapstructure:"Service"`
+	Allocation    *Allocation          `mapstructure:"Allocation"`
+	Deployment    *Deployment          `mapstructure:"Deployment"`
+	Evaluation    *Evaluation          `mapstructure:"Evaluation"`
+	Job           *Job                 `mapstructure:"Job"`
+	Node          *Node                `mapstructure:"Node"`
+	Service       *ServiceRegistration `mapstructure:"Service"`
+	Plan          *MaintenancePlan     `mapstructure:"Plan"`
+	Plugin        *CSIPlugin           `mapstructure:"Plugin"`
+	Namespace     *Namespace           `mapstructure:"Namespace"`
+	ScalingPolicy *ScalingPolicy       `mapstructure:"ScalingPolicy"`
 }
 
 func (e *Event) decodePayload() (*eventPayload, error) {
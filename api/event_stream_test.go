@@ -270,6 +270,44 @@ func TestEventStream_PayloadValueHelpers(t *testing.T) {
 				require.Equal(t, "some-service-namespace-id", a.Namespace)
 			},
 		},
+		{
+			desc:  "csi volume",
+			input: []byte(`{"Topic": "CSIVolume", "Payload": {"Volume":{"ID":"some-volume-id","Namespace":"some-namespace-id","PluginID":"some-plugin-id"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				require.Equal(t, TopicCSIVolume, event.Topic)
+				v, err := event.CSIVolume()
+				require.NoError(t, err)
+				require.Equal(t, "some-volume-id", v.ID)
+				require.Equal(t, "some-namespace-id", v.Namespace)
+				require.Equal(t, "some-plugin-id", v.PluginID)
+			},
+		},
+		{
+			desc:  "host volume",
+			input: []byte(`{"Topic": "HostVolume", "Payload": {"HostVolume":{"ID":"some-volume-id","Name":"data","NodeID":"some-node-id","State":"ready"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				require.Equal(t, TopicHostVolume, event.Topic)
+				v, err := event.HostVolume()
+				require.NoError(t, err)
+				require.Equal(t, "some-volume-id", v.ID)
+				require.Equal(t, "data", v.Name)
+				require.Equal(t, "some-node-id", v.NodeID)
+				require.Equal(t, HostVolumeStateReady, v.State)
+			},
+		},
+		{
+			desc:  "namespace",
+			input: []byte(`{"Topic": "Namespace", "Payload": {"Namespace":{"Name":"some-namespace","Description":"some description"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				require.Equal(t, TopicNamespace, event.Topic)
+				ns, err := event.Namespace()
+				require.NoError(t, err)
+				require.Equal(t, &Namespace{
+					Name:        "some-namespace",
+					Description: "some description",
+				}, ns)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
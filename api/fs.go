@@ -226,6 +226,26 @@ func queryClientNode(c *Client, alloc *Allocation, reqPath string, q *QueryOptio
 // Unexpected (non-EOF) errors will be sent on the error chan.
 func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin string,
 	offset int64, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
+	return a.SearchLogs(alloc, follow, task, logType, origin, offset, nil, cancel, q)
+}
+
+// LogSearch restricts the logs streamed by SearchLogs.
+type LogSearch struct {
+	// Since and Until restrict the logs to the lines written within the
+	// given time range. When Since is set, the logs are streamed from the
+	// lines written at that time regardless of the origin and offset.
+	Since time.Time
+	Until time.Time
+
+	// Grep is a regular expression the streamed lines must match.
+	Grep string
+}
+
+// SearchLogs streams the content of a tasks logs like Logs, but only streams
+// the lines matching the given search. The logs can't be followed when the
+// search has an Until time.
+func (a *AllocFS) SearchLogs(alloc *Allocation, follow bool, task, logType, origin string,
+	offset int64, search *LogSearch, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
 
 	errCh := make(chan error, 1)
 
@@ -237,6 +257,18 @@ func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin str
 			q.Params["type"] = logType
 			q.Params["origin"] = origin
 			q.Params["offset"] = strconv.FormatInt(offset, 10)
+			if search == nil {
+				return
+			}
+			if !search.Since.IsZero() {
+				q.Params["since"] = search.Since.Format(time.RFC3339Nano)
+			}
+			if !search.Until.IsZero() {
+				q.Params["until"] = search.Until.Format(time.RFC3339Nano)
+			}
+			if search.Grep != "" {
+				q.Params["grep"] = search.Grep
+			}
 		})
 	if err != nil {
 		errCh <- err
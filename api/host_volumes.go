@@ -0,0 +1,138 @@
+package api
+
+import (
+	"net/url"
+)
+
+// HostVolume is a host volume created on a node through the API, rather than
+// configured statically in the client configuration.
+type HostVolume struct {
+	// ID is the unique identifier of the volume, generated by the servers.
+	ID string `mapstructure:"-" hcl:"-"`
+
+	// Name is the name the volume is fingerprinted with on its node, and
+	// which task groups use as the source of their host volumes.
+	Name string `hcl:"name"`
+
+	Namespace string `hcl:"namespace,optional"`
+
+	// PluginID is the host volume plugin used to create the volume. It
+	// defaults to the built-in "mkdir" plugin.
+	PluginID string `mapstructure:"plugin_id" hcl:"plugin_id,optional"`
+
+	// NodePool and Constraints are used to select the node of the volume
+	// when no NodeID is given.
+	NodePool    string        `mapstructure:"node_pool" hcl:"node_pool,optional"`
+	Constraints []*Constraint `mapstructure:"-" hcl:"constraint,optional"`
+
+	// NodeID is the node the volume is created on.
+	NodeID string `mapstructure:"node_id" hcl:"node_id,optional"`
+
+	// RequestedCapacityMinBytes and RequestedCapacityMaxBytes are passed to
+	// the plugin, which reports the capacity actually provisioned.
+	RequestedCapacityMinBytes int64 `mapstructure:"capacity_min" hcl:"capacity_min,optional"`
+	RequestedCapacityMaxBytes int64 `mapstructure:"capacity_max" hcl:"capacity_max,optional"`
+	CapacityBytes             int64 `mapstructure:"-" hcl:"-"`
+
+	// Parameters are passed to the plugin as is, and are typically used to
+	// set the ownership and mode of the volume.
+	Parameters map[string]string `mapstructure:"parameters" hcl:"parameters,optional"`
+
+	// HostPath is the path of the volume on the node, as reported by the
+	// plugin.
+	HostPath string `mapstructure:"-" hcl:"-"`
+
+	// State is "pending" until the node has fingerprinted the volume, and
+	// "ready" afterwards.
+	State string `mapstructure:"-" hcl:"-"`
+
+	// Allocations are the non-terminal allocations that claim the volume.
+	Allocations []*AllocationListStub `mapstructure:"-" hcl:"-"`
+
+	CreateIndex uint64 `mapstructure:"-" hcl:"-"`
+	ModifyIndex uint64 `mapstructure:"-" hcl:"-"`
+}
+
+const (
+	HostVolumeStatePending = "pending"
+	HostVolumeStateReady   = "ready"
+)
+
+// HostVolumeStub is the summarized version of a host volume returned when
+// listing volumes.
+type HostVolumeStub struct {
+	ID            string
+	Name          string
+	Namespace     string
+	PluginID      string
+	NodePool      string
+	NodeID        string
+	CapacityBytes int64
+	State         string
+	CreateIndex   uint64
+	ModifyIndex   uint64
+}
+
+type HostVolumeCreateRequest struct {
+	Volume *HostVolume
+}
+
+type HostVolumeCreateResponse struct {
+	Volume *HostVolume
+}
+
+// HostVolumes is used to access the dynamic host volume endpoints.
+type HostVolumes struct {
+	client *Client
+}
+
+// HostVolumes returns a handle on the HostVolumes endpoint.
+func (c *Client) HostVolumes() *HostVolumes {
+	return &HostVolumes{client: c}
+}
+
+// Create asks a node to create a dynamic host volume with a host volume
+// plugin. The node is selected by the servers unless the volume's NodeID is
+// set. The volume is returned with its ID and node.
+func (hv *HostVolumes) Create(vol *HostVolume, w *WriteOptions) (*HostVolume, *WriteMeta, error) {
+	req := &HostVolumeCreateRequest{Volume: vol}
+	var resp HostVolumeCreateResponse
+	wm, err := hv.client.write("/v1/volume/host/create", req, &resp, w)
+	if err != nil {
+		return nil, wm, err
+	}
+	return resp.Volume, wm, nil
+}
+
+// Delete deletes a dynamic host volume from its node. Volumes that are
+// claimed by allocations can not be deleted.
+func (hv *HostVolumes) Delete(id string, w *WriteOptions) (*WriteMeta, error) {
+	return hv.client.delete("/v1/volume/host/"+url.PathEscape(id), nil, w)
+}
+
+// Info is used to retrieve a single dynamic host volume, along with the
+// allocations that claim it.
+func (hv *HostVolumes) Info(id string, q *QueryOptions) (*HostVolume, *QueryMeta, error) {
+	var resp HostVolume
+	qm, err := hv.client.query("/v1/volume/host/"+url.PathEscape(id), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// List returns the dynamic host volumes, optionally only those of a node.
+func (hv *HostVolumes) List(nodeID string, q *QueryOptions) ([]*HostVolumeStub, *QueryMeta, error) {
+	qp := url.Values{}
+	qp.Set("type", "host")
+	if nodeID != "" {
+		qp.Set("node_id", nodeID)
+	}
+
+	var resp []*HostVolumeStub
+	qm, err := hv.client.query("/v1/volumes?"+qp.Encode(), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
@@ -105,6 +105,10 @@ type RegisterOptions struct {
 	PolicyOverride bool
 	PreserveCounts bool
 	EvalPriority   int
+
+	// Submission is the original source of the job, which is stored with
+	// the registered job version.
+	Submission *JobSubmission
 }
 
 // Register is used to register a new job. It returns the ID
@@ -134,6 +138,7 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		req.PolicyOverride = opts.PolicyOverride
 		req.PreserveCounts = opts.PreserveCounts
 		req.EvalPriority = opts.EvalPriority
+		req.Submission = opts.Submission
 	}
 
 	var resp JobRegisterResponse
@@ -220,6 +225,17 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// Submission is used to retrieve the original source the given version of
+// a job was submitted with.
+func (j *Jobs) Submission(jobID string, version int, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	var sub JobSubmission
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/submission?version=%d", url.PathEscape(jobID), version), &sub, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &sub, qm, nil
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -472,6 +488,21 @@ func (j *Jobs) Stable(jobID string, version uint64, stable bool,
 	return &resp, wm, nil
 }
 
+// EvalPause is used to pause or resume the evaluations of a job. While paused,
+// evaluations of the job are held by the eval broker instead of being handed
+// to the schedulers.
+func (j *Jobs) EvalPause(jobID string, paused bool, q *WriteOptions) (*WriteMeta, error) {
+	req := &JobEvalPauseRequest{
+		JobID:  jobID,
+		Paused: paused,
+	}
+	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/evaluations/pause", req, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
 // Services is used to return a list of service registrations associated to the
 // specified jobID.
 func (j *Jobs) Services(jobID string, q *QueryOptions) ([]*ServiceRegistration, *QueryMeta, error) {
@@ -480,6 +511,25 @@ func (j *Jobs) Services(jobID string, q *QueryOptions) ([]*ServiceRegistration,
 	return resp, qm, err
 }
 
+// Actions is used to list the actions defined by the tasks of a job.
+func (j *Jobs) Actions(jobID string, q *QueryOptions) ([]*JobAction, *QueryMeta, error) {
+	var resp []*JobAction
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/actions", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// JobAction is an action defined by a task of a job.
+type JobAction struct {
+	TaskGroup string
+	Task      string
+	Name      string
+	Command   string
+	Args      []string
+}
+
 // periodicForceResponse is used to deserialize a force response
 type periodicForceResponse struct {
 	EvalID string
@@ -759,11 +809,14 @@ type MultiregionRegion struct {
 
 // PeriodicConfig is for serializing periodic config for a job.
 type PeriodicConfig struct {
-	Enabled         *bool   `hcl:"enabled,optional"`
-	Spec            *string `hcl:"cron,optional"`
+	Enabled         *bool    `hcl:"enabled,optional"`
+	Spec            *string  `hcl:"cron,optional"`
+	Specs           []string `mapstructure:"crons" hcl:"crons,optional"`
 	SpecType        *string
-	ProhibitOverlap *bool   `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
-	TimeZone        *string `mapstructure:"time_zone" hcl:"time_zone,optional"`
+	ProhibitOverlap *bool    `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
+	TimeZone        *string  `mapstructure:"time_zone" hcl:"time_zone,optional"`
+	ExcludeDates    []string `mapstructure:"exclude_dates" hcl:"exclude_dates,optional"`
+	ExcludeCrons    []string `mapstructure:"exclude_crons" hcl:"exclude_crons,optional"`
 }
 
 func (p *PeriodicConfig) Canonicalize() {
@@ -788,16 +841,34 @@ func (p *PeriodicConfig) Canonicalize() {
 // passed time. If no matching instance exists, the zero value of time.Time is
 // returned. The `time.Location` of the returned value matches that of the
 // passed time.
+//
+// When multiple specs are set, the earliest of their next times is returned.
 func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
-	if *p.SpecType == PeriodicSpecCron {
-		e, err := cronexpr.Parse(*p.Spec)
+	if *p.SpecType != PeriodicSpecCron {
+		return time.Time{}, nil
+	}
+
+	specs := p.Specs
+	if p.Spec != nil && *p.Spec != "" {
+		specs = append([]string{*p.Spec}, specs...)
+	}
+
+	var next time.Time
+	for _, spec := range specs {
+		e, err := cronexpr.Parse(spec)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed parsing cron expression %q: %v", spec, err)
+		}
+		t, err := cronParseNext(e, fromTime, spec)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("failed parsing cron expression %q: %v", *p.Spec, err)
+			return time.Time{}, err
+		}
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
 		}
-		return cronParseNext(e, fromTime, *p.Spec)
 	}
 
-	return time.Time{}, nil
+	return next, nil
 }
 
 // cronParseNext is a helper that parses the next time for the given expression
@@ -834,27 +905,29 @@ type ParameterizedJobConfig struct {
 type Job struct {
 	/* Fields parsed from HCL config */
 
-	Region           *string                 `hcl:"region,optional"`
-	Namespace        *string                 `hcl:"namespace,optional"`
-	ID               *string                 `hcl:"id,optional"`
-	Name             *string                 `hcl:"name,optional"`
-	Type             *string                 `hcl:"type,optional"`
-	Priority         *int                    `hcl:"priority,optional"`
-	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
-	Datacenters      []string                `hcl:"datacenters,optional"`
-	Constraints      []*Constraint           `hcl:"constraint,block"`
-	Affinities       []*Affinity             `hcl:"affinity,block"`
-	TaskGroups       []*TaskGroup            `hcl:"group,block"`
-	Update           *UpdateStrategy         `hcl:"update,block"`
-	Multiregion      *Multiregion            `hcl:"multiregion,block"`
-	Spreads          []*Spread               `hcl:"spread,block"`
-	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
-	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
-	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
-	Migrate          *MigrateStrategy        `hcl:"migrate,block"`
-	Meta             map[string]string       `hcl:"meta,block"`
-	ConsulToken      *string                 `mapstructure:"consul_token" hcl:"consul_token,optional"`
-	VaultToken       *string                 `mapstructure:"vault_token" hcl:"vault_token,optional"`
+	Region             *string                 `hcl:"region,optional"`
+	Namespace          *string                 `hcl:"namespace,optional"`
+	ID                 *string                 `hcl:"id,optional"`
+	Name               *string                 `hcl:"name,optional"`
+	Type               *string                 `hcl:"type,optional"`
+	Priority           *int                    `hcl:"priority,optional"`
+	AllAtOnce          *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
+	Datacenters        []string                `hcl:"datacenters,optional"`
+	NodePool           *string                 `mapstructure:"node_pool" hcl:"node_pool,optional"`
+	SchedulerAlgorithm *string                 `mapstructure:"scheduler_algorithm" hcl:"scheduler_algorithm,optional"`
+	Constraints        []*Constraint           `hcl:"constraint,block"`
+	Affinities         []*Affinity             `hcl:"affinity,block"`
+	TaskGroups         []*TaskGroup            `hcl:"group,block"`
+	Update             *UpdateStrategy         `hcl:"update,block"`
+	Multiregion        *Multiregion            `hcl:"multiregion,block"`
+	Spreads            []*Spread               `hcl:"spread,block"`
+	Periodic           *PeriodicConfig         `hcl:"periodic,block"`
+	ParameterizedJob   *ParameterizedJobConfig `hcl:"parameterized,block"`
+	Reschedule         *ReschedulePolicy       `hcl:"reschedule,block"`
+	Migrate            *MigrateStrategy        `hcl:"migrate,block"`
+	Meta               map[string]string       `hcl:"meta,block"`
+	ConsulToken        *string                 `mapstructure:"consul_token" hcl:"consul_token,optional"`
+	VaultToken         *string                 `mapstructure:"vault_token" hcl:"vault_token,optional"`
 
 	/* Fields set by server, not sourced from job config file */
 
@@ -869,6 +942,7 @@ type Job struct {
 	Status                   *string
 	StatusDescription        *string
 	Stable                   *bool
+	EvalsPaused              *bool
 	Version                  *uint64
 	SubmitTime               *int64
 	CreateIndex              *uint64
@@ -1009,6 +1083,7 @@ type JobChildrenSummary struct {
 	Pending int64
 	Running int64
 	Dead    int64
+	Skipped int64
 }
 
 func (jc *JobChildrenSummary) Sum() int {
@@ -1039,6 +1114,7 @@ type JobListStub struct {
 	Name              string
 	Namespace         string `json:",omitempty"`
 	Datacenters       []string
+	NodePool          string
 	Type              string
 	Priority          int
 	Periodic          bool
@@ -1231,9 +1307,48 @@ type JobRegisterRequest struct {
 	// change the job priority which also impacts preemption.
 	EvalPriority int `json:",omitempty"`
 
+	// Submission is the original source of the job. It is optional.
+	Submission *JobSubmission `json:",omitempty"`
+
 	WriteRequest
 }
 
+const (
+	JobSubmissionFormatHCL1 = "hcl1"
+	JobSubmissionFormatHCL2 = "hcl2"
+	JobSubmissionFormatJSON = "json"
+)
+
+// JobSubmission is the original source a job version was submitted with,
+// along with the variables used to render it.
+type JobSubmission struct {
+	// Source is the original job definition.
+	Source string
+
+	// Format is the format of Source, one of "hcl1", "hcl2" or "json".
+	Format string
+
+	// VariableFlags are the HCL2 variables given as -var flags.
+	VariableFlags map[string]string
+
+	// Variables is the content of the HCL2 variable files.
+	Variables string
+
+	// VariableEnvs are the HCL2 variables set by NOMAD_VAR_ environment
+	// variables.
+	VariableEnvs map[string]string
+
+	// Files are the contents of the files read by the HCL2 file function,
+	// keyed by path.
+	Files map[string]string
+
+	// The fields below are set by the server.
+	Namespace      string
+	JobID          string
+	Version        uint64
+	JobModifyIndex uint64
+}
+
 // JobRegisterResponse is used to respond to a job registration
 type JobRegisterResponse struct {
 	EvalID          string
@@ -1315,8 +1430,9 @@ type ObjectDiff struct {
 }
 
 type PlanAnnotations struct {
-	DesiredTGUpdates map[string]*DesiredUpdates
-	PreemptedAllocs  []*AllocationListStub
+	DesiredTGUpdates    map[string]*DesiredUpdates
+	PreemptedAllocs     []*AllocationListStub
+	SchedulerAlgorithms map[string]SchedulerAlgorithm
 }
 
 type DesiredUpdates struct {
@@ -1368,6 +1484,13 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobEvalPauseRequest is used to pause or resume the evaluations of a job.
+type JobEvalPauseRequest struct {
+	JobID  string
+	Paused bool
+	WriteRequest
+}
+
 // JobEvaluateRequest is used when we just need to re-evaluate a target job
 type JobEvaluateRequest struct {
 	JobID       string
@@ -67,13 +67,15 @@ func (n *Namespaces) Delete(namespace string, q *WriteOptions) (*WriteMeta, erro
 
 // Namespace is used to serialize a namespace.
 type Namespace struct {
-	Name         string
-	Description  string
-	Quota        string
-	Capabilities *NamespaceCapabilities `hcl:"capabilities,block"`
-	Meta         map[string]string
-	CreateIndex  uint64
-	ModifyIndex  uint64
+	Name                   string
+	Description            string
+	Quota                  string
+	Capabilities           *NamespaceCapabilities           `hcl:"capabilities,block"`
+	NodePoolConfiguration  *NamespaceNodePoolConfiguration  `hcl:"node_pool_config,block"`
+	SchedulerConfiguration *NamespaceSchedulerConfiguration `hcl:"scheduler_config,block"`
+	Meta                   map[string]string
+	CreateIndex            uint64
+	ModifyIndex            uint64
 }
 
 type NamespaceCapabilities struct {
@@ -81,6 +83,20 @@ type NamespaceCapabilities struct {
 	DisabledTaskDrivers []string `hcl:"disabled_task_drivers"`
 }
 
+// NamespaceNodePoolConfiguration stores configuration about node pools for a
+// namespace.
+type NamespaceNodePoolConfiguration struct {
+	Default string   `hcl:"default"`
+	Allowed []string `hcl:"allowed"`
+	Denied  []string `hcl:"denied"`
+}
+
+// NamespaceSchedulerConfiguration is used to serialize the scheduler
+// configuration of a namespace.
+type NamespaceSchedulerConfiguration struct {
+	SchedulerAlgorithm SchedulerAlgorithm `hcl:"scheduler_algorithm,optional"`
+}
+
 // NamespaceIndexSort is a wrapper to sort Namespaces by CreateIndex. We
 // reverse the test so that we get the highest index first.
 type NamespaceIndexSort []*Namespace
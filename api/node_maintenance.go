@@ -0,0 +1,197 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// MaintenancePlanStatus* are the statuses of a maintenance plan.
+	MaintenancePlanStatusRunning   = "running"
+	MaintenancePlanStatusComplete  = "complete"
+	MaintenancePlanStatusFailed    = "failed"
+	MaintenancePlanStatusCancelled = "cancelled"
+
+	// MaintenanceNodeStatus* are the statuses of a node within a maintenance
+	// plan.
+	MaintenanceNodeStatusPending  = "pending"
+	MaintenanceNodeStatusDraining = "draining"
+	MaintenanceNodeStatusWaiting  = "waiting"
+	MaintenanceNodeStatusComplete = "complete"
+	MaintenanceNodeStatusFailed   = "failed"
+
+	// MaintenanceCompleteOnSignal waits for an operator to signal that the
+	// maintenance of a drained node is done.
+	MaintenanceCompleteOnSignal = "signal"
+
+	// MaintenanceCompleteOnReregister considers the maintenance of a drained
+	// node done once it registers again.
+	MaintenanceCompleteOnReregister = "reregister"
+)
+
+// NodeMaintenance is used to access the node maintenance plan endpoints.
+type NodeMaintenance struct {
+	client *Client
+}
+
+// NodeMaintenance returns a handle on the node maintenance plan endpoints.
+func (c *Client) NodeMaintenance() *NodeMaintenance {
+	return &NodeMaintenance{client: c}
+}
+
+// List is used to list all maintenance plans.
+func (n *NodeMaintenance) List(q *QueryOptions) ([]*MaintenancePlanListStub, *QueryMeta, error) {
+	var resp []*MaintenancePlanListStub
+	qm, err := n.client.query("/v1/node/maintenance", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list maintenance plans whose ID matches a prefix.
+func (n *NodeMaintenance) PrefixList(prefix string, q *QueryOptions) ([]*MaintenancePlanListStub, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return n.List(q)
+}
+
+// Info is used to fetch the details of a maintenance plan.
+func (n *NodeMaintenance) Info(planID string, q *QueryOptions) (*MaintenancePlan, *QueryMeta, error) {
+	if planID == "" {
+		return nil, nil, errors.New("missing maintenance plan ID")
+	}
+
+	var resp MaintenancePlan
+	qm, err := n.client.query("/v1/node/maintenance/"+url.PathEscape(planID), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to create a maintenance plan. The leader starts working
+// through the nodes of the plan right away.
+func (n *NodeMaintenance) Register(plan *MaintenancePlan, w *WriteOptions) (*MaintenancePlanRegisterResponse, *WriteMeta, error) {
+	if plan == nil {
+		return nil, nil, errors.New("missing maintenance plan")
+	}
+
+	var resp MaintenancePlanRegisterResponse
+	wm, err := n.client.write("/v1/node/maintenance", plan, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Cancel is used to cancel a running maintenance plan.
+func (n *NodeMaintenance) Cancel(planID string, w *WriteOptions) (*WriteMeta, error) {
+	if planID == "" {
+		return nil, errors.New("missing maintenance plan ID")
+	}
+
+	return n.client.write(fmt.Sprintf("/v1/node/maintenance/%s/cancel", url.PathEscape(planID)), nil, nil, w)
+}
+
+// NodeDone is used to signal that the maintenance of the given nodes of a
+// plan is done.
+func (n *NodeMaintenance) NodeDone(planID string, nodeIDs []string, w *WriteOptions) (*WriteMeta, error) {
+	if planID == "" {
+		return nil, errors.New("missing maintenance plan ID")
+	}
+
+	req := &MaintenancePlanNodeDoneRequest{NodeIDs: nodeIDs}
+	return n.client.write(fmt.Sprintf("/v1/node/maintenance/%s/done", url.PathEscape(planID)), req, nil, w)
+}
+
+// Delete is used to delete a maintenance plan that is no longer running.
+func (n *NodeMaintenance) Delete(planID string, w *WriteOptions) (*WriteMeta, error) {
+	if planID == "" {
+		return nil, errors.New("missing maintenance plan ID")
+	}
+
+	return n.client.delete(fmt.Sprintf("/v1/node/maintenance/%s", url.PathEscape(planID)), nil, w)
+}
+
+// MaintenancePlan is a rolling maintenance of a set of nodes.
+type MaintenancePlan struct {
+	ID                string
+	Name              string
+	Selector          *MaintenanceSelector
+	Concurrency       int
+	DrainSpec         *DrainSpec
+	CompleteOn        string
+	Gate              *MaintenanceGate
+	Nodes             []*MaintenanceNode
+	Status            string
+	StatusDescription string
+	CreateTime        int64
+	ModifyTime        int64
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// MaintenanceSelector selects the nodes of a maintenance plan. All the set
+// fields must match for a node to be selected.
+type MaintenanceSelector struct {
+	NodeClass  string            `json:",omitempty"`
+	Datacenter string            `json:",omitempty"`
+	Meta       map[string]string `json:",omitempty"`
+
+	// Filter is a filter expression evaluated against each node.
+	Filter string `json:",omitempty"`
+}
+
+// MaintenanceGate is checked before starting the next batch of a plan.
+type MaintenanceGate struct {
+	// Delay is the time to wait after a batch completes before the next batch
+	// starts.
+	Delay time.Duration
+
+	// HealthyAllocs waits for the allocations migrated off the nodes of the
+	// previous batches to have healthy replacements.
+	HealthyAllocs bool
+}
+
+// MaintenanceNode is the progress of a single node of a maintenance plan.
+type MaintenanceNode struct {
+	NodeID            string
+	Batch             int
+	Status            string
+	StatusDescription string
+	Done                bool
+	DrainedAgentStartID string
+	UpdateTime          int64
+}
+
+// MaintenancePlanListStub is a summary of a maintenance plan.
+type MaintenancePlanListStub struct {
+	ID                string
+	Name              string
+	Concurrency       int
+	Status            string
+	StatusDescription string
+	NodesTotal        int
+	NodesComplete     int
+	CreateTime        int64
+	ModifyTime        int64
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// MaintenancePlanRegisterResponse is the response of a maintenance plan
+// register request.
+type MaintenancePlanRegisterResponse struct {
+	PlanID string
+}
+
+// MaintenancePlanNodeDoneRequest is used to signal that the maintenance of
+// some nodes of a plan is done.
+type MaintenancePlanNodeDoneRequest struct {
+	NodeIDs []string
+}
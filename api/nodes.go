@@ -468,6 +468,17 @@ func (n *Nodes) Stats(nodeID string, q *QueryOptions) (*HostStats, error) {
 	return &resp, nil
 }
 
+// ArtifactCache returns the content of the artifact cache of a node.
+func (n *Nodes) ArtifactCache(nodeID string, q *QueryOptions) (*ArtifactCacheStats, error) {
+	var resp ArtifactCacheStats
+	path := fmt.Sprintf("/v1/client/artifacts?node_id=%s", nodeID)
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
 func (n *Nodes) GC(nodeID string, q *QueryOptions) error {
 	path := fmt.Sprintf("/v1/client/gc?node_id=%s", nodeID)
 	_, err := n.client.query(path, nil, q)
@@ -549,6 +560,7 @@ type Node struct {
 	Links                 map[string]string
 	Meta                  map[string]string
 	NodeClass             string
+	NodePool              string
 	CgroupParent          string
 	Drain                 bool
 	DrainStrategy         *DrainStrategy
@@ -556,6 +568,7 @@ type Node struct {
 	Status                string
 	StatusDescription     string
 	StatusUpdatedAt       int64
+	AgentStartID          string
 	Events                []*NodeEvent
 	Drivers               map[string]*DriverInfo
 	HostVolumes           map[string]*HostVolumeInfo
@@ -718,6 +731,9 @@ type DrainStrategy struct {
 
 	// StartedAt is the time the drain process started
 	StartedAt time.Time
+
+	// WindowStart is the start of the drain window the drain is waiting for
+	WindowStart time.Time
 }
 
 // DrainSpec describes a Node's drain behavior.
@@ -729,6 +745,33 @@ type DrainSpec struct {
 	// IgnoreSystemJobs allows systems jobs to remain on the node even though it
 	// has been marked for draining.
 	IgnoreSystemJobs bool
+
+	// JobOrder is the list of job IDs whose allocations are migrated off the
+	// node first, in order.
+	JobOrder []string `json:",omitempty"`
+
+	// OrderByPriority migrates the allocations of higher priority jobs before
+	// those of lower priority jobs.
+	OrderByPriority bool `json:",omitempty"`
+
+	// MaxParallelMigrations caps the number of allocations that may be
+	// migrating across the cluster at once while the node drains.
+	MaxParallelMigrations int `json:",omitempty"`
+
+	// Window restricts the start of the drain to a maintenance window.
+	Window *DrainWindow `json:",omitempty"`
+}
+
+// DrainWindow is a recurring maintenance window in which a drain may start.
+type DrainWindow struct {
+	// Cron is the cron expression for the start of each window.
+	Cron string
+
+	// Duration is how long each window lasts.
+	Duration time.Duration
+
+	// TimeZone is the time zone the cron expression is evaluated in.
+	TimeZone string `json:",omitempty"`
 }
 
 func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
@@ -745,12 +788,39 @@ func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
 	if d.IgnoreSystemJobs != o.IgnoreSystemJobs {
 		return false
 	}
+	if len(d.JobOrder) != len(o.JobOrder) {
+		return false
+	}
+	for i, jobID := range d.JobOrder {
+		if o.JobOrder[i] != jobID {
+			return false
+		}
+	}
+	if d.OrderByPriority != o.OrderByPriority {
+		return false
+	}
+	if d.MaxParallelMigrations != o.MaxParallelMigrations {
+		return false
+	}
+	if d.Window == nil || o.Window == nil {
+		if d.Window != o.Window {
+			return false
+		}
+	} else if *d.Window != *o.Window {
+		return false
+	}
+	if d.WindowStart != o.WindowStart {
+		return false
+	}
 
 	return true
 }
 
 // String returns a human readable version of the drain strategy.
 func (d *DrainStrategy) String() string {
+	if d.Window != nil && d.WindowStart.After(time.Now()) {
+		return fmt.Sprintf("drain waiting for window at %s and deadline at %s", d.WindowStart, d.ForceDeadline)
+	}
 	if d.IgnoreSystemJobs {
 		return fmt.Sprintf("drain ignoring system jobs and deadline at %s", d.ForceDeadline)
 	}
@@ -807,6 +877,29 @@ type HostDiskStats struct {
 	InodesUsedPercent float64
 }
 
+// ArtifactCacheStats is the content of the artifact cache of a Nomad client.
+type ArtifactCacheStats struct {
+	// Size is the size of the cached artifacts in bytes.
+	Size int64
+
+	// MaxSize is the maximum size of the cache in bytes, which is 0 if the
+	// cache is disabled.
+	MaxSize int64
+
+	// Entries are the cached artifacts, from the most to the least recently
+	// used.
+	Entries []*ArtifactCacheEntry
+}
+
+// ArtifactCacheEntry is an artifact cached by a Nomad client.
+type ArtifactCacheEntry struct {
+	Source   string
+	Checksum string
+	Size     int64
+	LastUsed time.Time
+	InUse    bool
+}
+
 // DeviceGroupStats contains statistics for each device of a particular
 // device group, identified by the vendor, type and name of the device.
 type DeviceGroupStats struct {
@@ -910,6 +1003,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
@@ -56,10 +56,40 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the latest result of each health check defined within
+	// the service block, as executed by the Nomad client.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// ServiceRegistrationCheck is the status of a single health check which is
+// executed by the Nomad client on behalf of a service registration.
+type ServiceRegistrationCheck struct {
+
+	// ID is the unique identifier of the check within the service
+	// registration.
+	ID string
+
+	// Name is the name of the check as defined within the jobspec.
+	Name string
+
+	// Type is the check type, such as "http" or "tcp".
+	Type string
+
+	// Status is the current status of the check and is one of "passing",
+	// "warning", or "critical".
+	Status string
+
+	// Output is a human-readable description of the last check result.
+	Output string
+
+	// Timestamp is the UnixNano time at which the status of the check last
+	// changed.
+	Timestamp int64
+}
+
 // ServiceRegistrationListStub represents all service registrations held within a
 // single namespace.
 type ServiceRegistrationListStub struct {
@@ -256,7 +256,9 @@ func (p *ReschedulePolicy) String() string {
 // Spread is used to serialize task group allocation spread preferences
 type Spread struct {
 	Attribute    string          `hcl:"attribute,optional"`
+	Attributes   []string        `hcl:"attributes,optional"`
 	Weight       *int8           `hcl:"weight,optional"`
+	MaxSkew      int             `mapstructure:"max_skew" hcl:"max_skew,optional"`
 	SpreadTarget []*SpreadTarget `hcl:"target,block"`
 }
 
@@ -432,6 +434,8 @@ type TaskGroup struct {
 	ShutdownDelay             *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
 	StopAfterClientDisconnect *time.Duration            `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
 	MaxClientDisconnect       *time.Duration            `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
+	StickyHost                *bool                     `mapstructure:"sticky_host" hcl:"sticky_host,optional"`
+	SchedulerAlgorithm        *string                   `mapstructure:"scheduler_algorithm" hcl:"scheduler_algorithm,optional"`
 	Scaling                   *ScalingPolicy            `hcl:"scaling,block"`
 	Consul                    *Consul                   `hcl:"consul,block"`
 }
@@ -617,8 +621,9 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
-	MaxFiles      *int `mapstructure:"max_files" hcl:"max_files,optional"`
-	MaxFileSizeMB *int `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	MaxFiles      *int       `mapstructure:"max_files" hcl:"max_files,optional"`
+	MaxFileSizeMB *int       `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	Sinks         []*LogSink `mapstructure:"sink" hcl:"sink,block"`
 }
 
 func DefaultLogConfig() *LogConfig {
@@ -635,6 +640,33 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = intToPtr(10)
 	}
+	for _, sink := range l.Sinks {
+		sink.Canonicalize()
+	}
+}
+
+// LogSink is an external destination that the task logs are shipped to, in
+// addition to the local rotated files.
+type LogSink struct {
+	Type       string            `mapstructure:"type" hcl:"type,optional"`
+	Address    string            `mapstructure:"address" hcl:"address,optional"`
+	Tag        string            `mapstructure:"tag" hcl:"tag,optional"`
+	Headers    map[string]string `mapstructure:"headers" hcl:"headers,block"`
+	BatchSize  *int              `mapstructure:"batch_size" hcl:"batch_size,optional"`
+	BatchWait  *time.Duration    `mapstructure:"batch_wait" hcl:"batch_wait,optional"`
+	BufferSize *int              `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
+}
+
+func (s *LogSink) Canonicalize() {
+	if s.BatchSize == nil {
+		s.BatchSize = intToPtr(100)
+	}
+	if s.BatchWait == nil {
+		s.BatchWait = timeToPtr(1 * time.Second)
+	}
+	if s.BufferSize == nil {
+		s.BufferSize = intToPtr(10000)
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -685,6 +717,7 @@ type Task struct {
 	KillSignal      string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
 	Kind            string                 `hcl:"kind,optional"`
 	ScalingPolicies []*ScalingPolicy       `hcl:"scaling,block"`
+	Actions         []*Action              `hcl:"action,block"`
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -735,6 +768,14 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	}
 }
 
+// Action is a named command defined on a task, which operators can run in
+// the task's running allocations.
+type Action struct {
+	Name    string   `hcl:"name,label"`
+	Command string   `hcl:"command,optional"`
+	Args    []string `hcl:"args,optional"`
+}
+
 // TaskArtifact is used to download artifacts before running a task.
 type TaskArtifact struct {
 	GetterSource  *string           `mapstructure:"source" hcl:"source,optional"`
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrVariableNotFound is returned when reading a variable which does not
+// exist.
+var ErrVariableNotFound = errors.New("variable not found")
+
+// Variables is used to access the variables HTTP endpoints.
+type Variables struct {
+	client *Client
+}
+
+// Variables returns a new handle on the variables.
+func (c *Client) Variables() *Variables {
+	return &Variables{client: c}
+}
+
+// Variable is a set of encrypted key/value items stored at a path within a
+// namespace. The items are only ever returned decrypted via the API.
+type Variable struct {
+	// Namespace is the namespace within which the variable is stored.
+	Namespace string
+
+	// Path is the unique identifier of the variable within its namespace.
+	Path string
+
+	// CreateIndex and ModifyIndex are the Raft indexes at which the variable
+	// was created and last modified. ModifyIndex is used as the check index
+	// of check-and-set operations.
+	CreateIndex uint64
+	ModifyIndex uint64
+
+	// CreateTime and ModifyTime are the times, in Unix nanoseconds, at which
+	// the variable was created and last modified.
+	CreateTime int64
+	ModifyTime int64
+
+	// Items are the key/value pairs stored within the variable.
+	Items VariableItems
+}
+
+// VariableMetadata is the metadata of a variable, as returned when listing.
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	ModifyIndex uint64
+	CreateTime  int64
+	ModifyTime  int64
+}
+
+// VariableItems are the key/value pairs stored within a variable.
+type VariableItems map[string]string
+
+// NewVariable returns a new variable at the given path, with no items.
+func NewVariable(path string) *Variable {
+	return &Variable{
+		Path:  path,
+		Items: make(VariableItems),
+	}
+}
+
+// Metadata returns the metadata of the variable.
+func (v *Variable) Metadata() *VariableMetadata {
+	return &VariableMetadata{
+		Namespace:   v.Namespace,
+		Path:        v.Path,
+		CreateIndex: v.CreateIndex,
+		ModifyIndex: v.ModifyIndex,
+		CreateTime:  v.CreateTime,
+		ModifyTime:  v.ModifyTime,
+	}
+}
+
+// ErrCASConflict is returned when a check-and-set operation fails because the
+// variable was modified since it was read. Conflict holds the current
+// variable, which is empty if the variable does not exist and has its items
+// removed if the caller is not permitted to read them.
+type ErrCASConflict struct {
+	CheckIndex uint64
+	Conflict   *Variable
+}
+
+func (e ErrCASConflict) Error() string {
+	return fmt.Sprintf("cas conflict: expected ModifyIndex %v; found %v",
+		e.CheckIndex, e.Conflict.ModifyIndex)
+}
+
+// List is used to list the metadata of all the variables within the
+// namespace of the query options.
+func (vars *Variables) List(q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	var resp []*VariableMetadata
+	qm, err := vars.client.query("/v1/vars", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the metadata of the variables whose path begins
+// with the prefix.
+func (vars *Variables) PrefixList(prefix string, q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return vars.List(q)
+}
+
+// Read is used to read the variable at the given path, including its items.
+// ErrVariableNotFound is returned if the variable does not exist.
+func (vars *Variables) Read(path string, q *QueryOptions) (*Variable, *QueryMeta, error) {
+	r, err := vars.client.newRequest(http.MethodGet, variablePath(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	r.setQueryOptions(q)
+
+	rtt, resp, err := vars.client.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil, ErrVariableNotFound
+	}
+	_, resp, err = requireOK(rtt, resp, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out Variable
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
+// Create is used to create or overwrite the variable, regardless of its
+// current state.
+func (vars *Variables) Create(v *Variable, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	return vars.apply(http.MethodPut, v.Path, v, nil, q)
+}
+
+// CheckedCreate is used to create the variable only if it does not already
+// exist. ErrCASConflict is returned if it does.
+func (vars *Variables) CheckedCreate(v *Variable, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	var checkIndex uint64
+	return vars.apply(http.MethodPut, v.Path, v, &checkIndex, q)
+}
+
+// Update is used to overwrite the variable only if it has not been modified
+// since it was read, as determined by its ModifyIndex. ErrCASConflict is
+// returned if it has.
+func (vars *Variables) Update(v *Variable, q *WriteOptions) (*Variable, *WriteMeta, error) {
+	checkIndex := v.ModifyIndex
+	return vars.apply(http.MethodPut, v.Path, v, &checkIndex, q)
+}
+
+// Delete is used to delete the variable at the given path, regardless of its
+// current state.
+func (vars *Variables) Delete(path string, q *WriteOptions) (*WriteMeta, error) {
+	_, wm, err := vars.apply(http.MethodDelete, path, nil, nil, q)
+	return wm, err
+}
+
+// CheckedDelete is used to delete the variable at the given path only if its
+// ModifyIndex matches the check index. ErrCASConflict is returned if it does
+// not.
+func (vars *Variables) CheckedDelete(path string, checkIndex uint64, q *WriteOptions) (*WriteMeta, error) {
+	_, wm, err := vars.apply(http.MethodDelete, path, nil, &checkIndex, q)
+	return wm, err
+}
+
+// apply performs a write against the variable endpoint. If checkIndex is
+// non-nil, the write is a check-and-set operation.
+func (vars *Variables) apply(method, path string, in *Variable, checkIndex *uint64,
+	q *WriteOptions) (*Variable, *WriteMeta, error) {
+
+	r, err := vars.client.newRequest(method, variablePath(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	r.setWriteOptions(q)
+	if in != nil {
+		r.obj = in
+	}
+	if checkIndex != nil {
+		r.params.Set("cas", strconv.FormatUint(*checkIndex, 10))
+	}
+
+	rtt, resp, err := vars.client.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A conflict includes the current variable within the body.
+	if resp.StatusCode == http.StatusConflict {
+		defer resp.Body.Close()
+		var conflict Variable
+		if err := decodeBody(resp, &conflict); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrCASConflict{CheckIndex: *checkIndex, Conflict: &conflict}
+	}
+
+	_, resp, err = requireOK(rtt, resp, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+
+	if method == http.MethodDelete {
+		return nil, wm, nil
+	}
+
+	var out Variable
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, wm, nil
+}
+
+func variablePath(path string) string {
+	return "/v1/var/" + strings.TrimPrefix(path, "/")
+}
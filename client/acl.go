@@ -21,6 +21,10 @@ const (
 	// tokenCacheSize is the number of ACL tokens to keep cached. Tokens have a fetching cost,
 	// so we keep the hot tokens cached to reduce the lookups.
 	tokenCacheSize = 64
+
+	// roleCacheSize is the number of ACL roles to keep cached. Roles have a fetching cost,
+	// so we keep the hot roles cached to reduce the ACL token resolution time.
+	roleCacheSize = 64
 )
 
 // clientACLResolver holds the state required for client resolution
@@ -34,6 +38,9 @@ type clientACLResolver struct {
 
 	// tokenCache is used to maintain the fetched token objects
 	tokenCache *lru.TwoQueueCache
+
+	// roleCache is used to maintain the fetched role objects
+	roleCache *lru.TwoQueueCache
 }
 
 // init is used to setup the client resolver state
@@ -52,13 +59,18 @@ func (c *clientACLResolver) init() error {
 	if err != nil {
 		return err
 	}
+	c.roleCache, err = lru.New2Q(roleCacheSize)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
-// cachedACLValue is used to manage ACL Token or Policy TTLs
+// cachedACLValue is used to manage ACL Token, Policy or Role TTLs
 type cachedACLValue struct {
 	Token     *structs.ACLToken
 	Policy    *structs.ACLPolicy
+	Role      *structs.ACLRole
 	CacheTime time.Time
 }
 
@@ -94,14 +106,23 @@ func (c *Client) resolveTokenAndACL(secretID string) (*acl.ACL, *structs.ACLToke
 	if token == nil {
 		return nil, nil, structs.ErrTokenNotFound
 	}
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, nil, structs.ErrTokenExpired
+	}
 
 	// Check if this is a management token
 	if token.Type == structs.ACLManagementToken {
 		return acl.ManagementACL, token, nil
 	}
 
+	// Resolve the names of the policies linked to via the token's roles
+	policyNames, err := c.resolveTokenPolicyNames(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Resolve the policies
-	policies, err := c.resolvePolicies(token.SecretID, token.Policies)
+	policies, err := c.resolvePolicies(token.SecretID, policyNames)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -227,3 +248,105 @@ func (c *Client) resolvePolicies(secretID string, policies []string) ([]*structs
 	// Return the valid policies
 	return out, nil
 }
+
+// resolveTokenPolicyNames returns the de-duplicated names of the policies the
+// token is linked to, either directly or via its roles.
+func (c *Client) resolveTokenPolicyNames(token *structs.ACLToken) ([]string, error) {
+	if len(token.Roles) == 0 {
+		return token.Policies, nil
+	}
+
+	roles, err := c.resolveRoles(token.SecretID, token.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(token.Policies))
+	names := make([]string, 0, len(token.Policies))
+	add := func(name string) {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	for _, policyName := range token.Policies {
+		add(policyName)
+	}
+	for _, role := range roles {
+		for _, policyLink := range role.Policies {
+			add(policyLink.Name)
+		}
+	}
+	return names, nil
+}
+
+// resolveRoles is used to translate a set of ACL role links into the objects.
+// We cache the roles locally, and fault them from a server as necessary. Roles
+// are cached for the same TTL as policies, and then refreshed. If a server
+// cannot be reached, the cache TTL will be ignored to gracefully handle
+// outages.
+func (c *Client) resolveRoles(secretID string, roleLinks []*structs.ACLTokenRoleLink) ([]*structs.ACLRole, error) {
+	var out []*structs.ACLRole
+	var expired []*structs.ACLRole
+	var missing []string
+
+	// Scan the cache for each role
+	for _, roleLink := range roleLinks {
+		// Lookup the role in the cache
+		raw, ok := c.roleCache.Get(roleLink.ID)
+		if !ok {
+			missing = append(missing, roleLink.ID)
+			continue
+		}
+
+		// Check if the cached value is valid or expired
+		cached := raw.(*cachedACLValue)
+		if cached.Age() <= c.config.ACLPolicyTTL {
+			out = append(out, cached.Role)
+		} else {
+			expired = append(expired, cached.Role)
+		}
+	}
+
+	// Hot-path if we have no missing or expired roles
+	if len(missing)+len(expired) == 0 {
+		return out, nil
+	}
+
+	// Lookup the missing and expired roles
+	fetch := missing
+	for _, r := range expired {
+		fetch = append(fetch, r.ID)
+	}
+	req := structs.ACLRolesByIDRequest{
+		ACLRoleIDs: fetch,
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			AuthToken:  secretID,
+			AllowStale: true,
+		},
+	}
+	var resp structs.ACLRolesByIDResponse
+	if err := c.RPC(structs.ACLGetRolesByIDRPCMethod, &req, &resp); err != nil {
+		// If we encounter an error but have cached roles, mask the error and extend the cache
+		if len(missing) == 0 {
+			c.logger.Warn("failed to resolve roles, using expired cached value", "error", err)
+			out = append(out, expired...)
+			return out, nil
+		}
+		return nil, err
+	}
+
+	// Handle each output
+	for _, role := range resp.ACLRoles {
+		c.roleCache.Add(role.ID, &cachedACLValue{
+			Role:      role,
+			CacheTime: time.Now(),
+		})
+		out = append(out, role)
+	}
+
+	// Return the valid roles
+	return out, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClient_ACL_resolveTokenValue(t *testing.T) {
@@ -106,6 +107,49 @@ func TestClient_ACL_resolvePolicies(t *testing.T) {
 	}
 }
 
+func TestClient_ACL_resolveRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, root, cleanupS1 := testACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ACLEnabled = true
+	})
+	defer cleanup()
+
+	// Create a policy, a role linked to it, and a token linked to the role
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	role.SetHash()
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: role.ID}}
+	require.NoError(t, s1.State().UpsertACLPolicies(structs.MsgTypeTestSetup, 100, []*structs.ACLPolicy{policy}))
+	require.NoError(t, s1.State().UpsertACLRoles(structs.MsgTypeTestSetup, 110, []*structs.ACLRole{role}, false))
+	require.NoError(t, s1.State().UpsertACLTokens(structs.MsgTypeTestSetup, 120, []*structs.ACLToken{token}))
+
+	// Test the client resolution
+	out, err := c1.resolveRoles(root.SecretID, token.Roles)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, role.ID, out[0].ID)
+
+	// Test caching
+	out2, err := c1.resolveRoles(root.SecretID, token.Roles)
+	require.NoError(t, err)
+	require.Len(t, out2, 1)
+	require.Same(t, out[0], out2[0])
+
+	// The token should resolve to an ACL object granting the role's policy
+	aclObj, err := c1.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+	require.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+}
+
 func TestClient_ACL_ResolveToken_Disabled(t *testing.T) {
 	ci.Parallel(t)
 
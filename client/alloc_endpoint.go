@@ -195,16 +195,19 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 			"alloc_id", req.AllocID,
 			"task", req.Task,
 			"command", req.Cmd,
+			"action", req.Action,
 			"tty", req.Tty,
 			"access_token_name", tokenName,
 			"access_token_id", tokenID,
 		)
 	}
 
-	// Check alloc-exec permission.
+	// Check alloc-exec permission. Task actions only require the narrower
+	// alloc-action permission.
 	if err != nil {
 		return nil, err
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) &&
+		!(req.Action != "" && aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocAction)) {
 		return nil, nstructs.ErrPermissionDenied
 	}
 
@@ -212,7 +215,22 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	if req.Task == "" {
 		return helper.Int64ToPtr(400), taskNotPresentErr
 	}
-	if len(req.Cmd) == 0 {
+	if req.Action != "" {
+		if len(req.Cmd) != 0 {
+			return helper.Int64ToPtr(400), errors.New("command and action are mutually exclusive")
+		}
+
+		// Actions are run non-interactively
+		if req.Tty {
+			return helper.Int64ToPtr(400), errors.New("actions can't be run with a tty")
+		}
+
+		action := lookupTaskAction(alloc, req.Task, req.Action)
+		if action == nil {
+			return helper.Int64ToPtr(404), fmt.Errorf("unknown action %q for task %q", req.Action, req.Task)
+		}
+		req.Cmd = append([]string{action.Command}, action.Args...)
+	} else if len(req.Cmd) == 0 {
 		return helper.Int64ToPtr(400), errors.New("command is not present")
 	}
 
@@ -226,7 +244,8 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 		return code, err
 	}
 
-	// check node access
+	// check node access. Task actions run with the isolation of the task, so
+	// they require the same access to the node as any other command.
 	if aclObj != nil && capabilities.FSIsolation == drivers.FSIsolationNone {
 		exec := aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocNodeExec)
 		if !exec {
@@ -262,7 +281,12 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 		return helper.Int64ToPtr(404), fmt.Errorf("task %q is not running.", req.Task)
 	}
 
-	err = h(ctx, req.Cmd, req.Tty, newExecStream(decoder, encoder))
+	stream := newExecStream(decoder, encoder)
+	if req.Action != "" {
+		stream = &actionStream{stream}
+	}
+
+	err = h(ctx, req.Cmd, req.Tty, stream)
 	if err != nil {
 		code := helper.Int64ToPtr(500)
 		return code, err
@@ -271,6 +295,20 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	return nil, nil
 }
 
+// lookupTaskAction returns the action of a task of the allocation, or nil if
+// the task doesn't define it.
+func lookupTaskAction(alloc *nstructs.Allocation, taskName, name string) *nstructs.Action {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+	task := tg.LookupTask(taskName)
+	if task == nil {
+		return nil
+	}
+	return task.LookupAction(name)
+}
+
 // newExecStream returns a new exec stream as expected by drivers that interpolate with RPC streaming format
 func newExecStream(decoder *codec.Decoder, encoder *codec.Encoder) drivers.ExecTaskStream {
 	buf := new(bytes.Buffer)
@@ -308,3 +346,22 @@ func (s *execStream) Recv() (*drivers.ExecTaskStreamingRequestMsg, error) {
 	err := s.decoder.Decode(&req)
 	return &req, err
 }
+
+// actionStream is the stream of a task action, which doesn't accept input as
+// actions are run non-interactively.
+type actionStream struct {
+	drivers.ExecTaskStream
+}
+
+// Recv returns the next message of the RPC, or an error if it carries stdin
+// data or a terminal size.
+func (s *actionStream) Recv() (*drivers.ExecTaskStreamingRequestMsg, error) {
+	req, err := s.ExecTaskStream.Recv()
+	if err != nil {
+		return req, err
+	}
+	if req.TtySize != nil || (req.Stdin != nil && len(req.Stdin.Data) != 0) {
+		return nil, errors.New("actions don't accept input")
+	}
+	return req, nil
+}
@@ -22,6 +22,7 @@ import (
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
 	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/plugins/drivers"
+	dproto "github.com/hashicorp/nomad/plugins/drivers/proto"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sys/unix"
@@ -845,6 +846,273 @@ func TestAlloc_ExecStreaming_ACL_Basic(t *testing.T) {
 	}
 }
 
+// TestAlloc_ExecStreaming_Action asserts that task actions run their command
+// and only require the alloc-action acl policy when the task is isolated
+func TestAlloc_ExecStreaming_Action(t *testing.T) {
+	ci.Parallel(t)
+	isolation := drivers.FSIsolationImage
+
+	// Start a server and client
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+
+		pluginConfig := []*nconfig.PluginConfig{
+			{
+				Name: "mock_driver",
+				Config: map[string]interface{}{
+					"fs_isolation": string(isolation),
+				},
+			},
+		}
+
+		c.PluginLoader = catalog.TestPluginLoaderWithOptions(t, "", map[string]string{}, pluginConfig)
+	})
+	defer cleanupC()
+
+	policyAction := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocAction})
+	tokenAction := mock.CreatePolicyAndToken(t, s.State(), 1005, "action", policyAction)
+
+	job := testActionJob()
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name           string
+		Token          string
+		Action         string
+		Cmd            []string
+		Tty            bool
+		ExpectedError  string
+		ExpectedStdout string
+	}{
+		{
+			Name:           "action token",
+			Token:          tokenAction.SecretID,
+			Action:         "show",
+			ExpectedStdout: "some output",
+		},
+		{
+			Name:          "action token running command",
+			Token:         tokenAction.SecretID,
+			Cmd:           []string{"showinput"},
+			ExpectedError: nstructs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "unknown action",
+			Token:         root.SecretID,
+			Action:        "missing",
+			ExpectedError: `unknown action "missing"`,
+		},
+		{
+			Name:          "action and command",
+			Token:         root.SecretID,
+			Action:        "show",
+			Cmd:           []string{"showinput"},
+			ExpectedError: "mutually exclusive",
+		},
+		{
+			Name:          "action with tty",
+			Token:         root.SecretID,
+			Action:        "show",
+			Tty:           true,
+			ExpectedError: "can't be run with a tty",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			stdout, err := testExecRequest(t, client, &cstructs.AllocExecRequest{
+				AllocID: alloc.ID,
+				Task:    job.TaskGroups[0].Tasks[0].Name,
+				Cmd:     c.Cmd,
+				Action:  c.Action,
+				Tty:     c.Tty,
+				QueryOptions: nstructs.QueryOptions{
+					Region:    "global",
+					AuthToken: c.Token,
+					Namespace: nstructs.DefaultNamespace,
+				},
+			})
+			if c.ExpectedError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.ExpectedStdout, stdout)
+		})
+	}
+}
+
+// TestAlloc_ExecStreaming_Action_WithIsolation_None asserts that task actions
+// also require the alloc-node-exec acl policy when the task isn't isolated
+func TestAlloc_ExecStreaming_Action_WithIsolation_None(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client, the mock driver defaults to no isolation
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	policyAction := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocAction})
+	tokenAction := mock.CreatePolicyAndToken(t, s.State(), 1005, "action", policyAction)
+
+	policyActionNodeExec := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocAction, acl.NamespaceCapabilityAllocNodeExec})
+	tokenActionNodeExec := mock.CreatePolicyAndToken(t, s.State(), 1007, "action-node-exec", policyActionNodeExec)
+
+	job := testActionJob()
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	req := &cstructs.AllocExecRequest{
+		AllocID: alloc.ID,
+		Task:    job.TaskGroups[0].Tasks[0].Name,
+		Action:  "show",
+		QueryOptions: nstructs.QueryOptions{
+			Region:    "global",
+			AuthToken: tokenAction.SecretID,
+			Namespace: nstructs.DefaultNamespace,
+		},
+	}
+	_, err := testExecRequest(t, client, req)
+	require.EqualError(t, err, nstructs.ErrPermissionDenied.Error())
+
+	req.AuthToken = tokenActionNodeExec.SecretID
+	stdout, err := testExecRequest(t, client, req)
+	require.NoError(t, err)
+	require.Equal(t, "some output", stdout)
+}
+
+// testActionJob returns a batch job whose task defines the "show" action.
+func testActionJob() *nstructs.Job {
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+		"exec_command": map[string]interface{}{
+			"run_for":       "1ms",
+			"stdout_string": "some output",
+		},
+	}
+	job.TaskGroups[0].Tasks[0].Actions = []*nstructs.Action{
+		{Name: "show", Command: "echo", Args: []string{"some output"}},
+	}
+	return job
+}
+
+// testExecRequest runs an exec request against the client and returns the
+// stdout of the command, or the error of the request.
+func testExecRequest(t *testing.T, client *Client, req *cstructs.AllocExecRequest) (string, error) {
+	// Get the handler
+	handler, err := client.StreamingRpcHandler("Allocations.Exec")
+	require.Nil(t, err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	errCh := make(chan error)
+	frames := make(chan *drivers.ExecTaskStreamingResponseMsg)
+
+	// Start the handler
+	go handler(p2)
+	go decodeFrames(t, p1, frames, errCh)
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+
+	receivedStdout := ""
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			require.FailNow(t, "timed out")
+		case err := <-errCh:
+			return "", err
+		case f := <-frames:
+			switch {
+			case f.Stdout != nil && len(f.Stdout.Data) != 0:
+				receivedStdout += string(f.Stdout.Data)
+			case f.Exited && f.Result != nil:
+				require.Zero(t, f.Result.ExitCode)
+				return receivedStdout, nil
+			}
+		}
+	}
+}
+
+// TestAlloc_actionStream asserts that task actions don't accept input
+func TestAlloc_actionStream(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name          string
+		Msg           *drivers.ExecTaskStreamingRequestMsg
+		ExpectedError bool
+	}{
+		{
+			Name: "heartbeat",
+			Msg:  &drivers.ExecTaskStreamingRequestMsg{},
+		},
+		{
+			Name: "stdin close",
+			Msg: &drivers.ExecTaskStreamingRequestMsg{
+				Stdin: &dproto.ExecTaskStreamingIOOperation{Close: true},
+			},
+		},
+		{
+			Name: "stdin data",
+			Msg: &drivers.ExecTaskStreamingRequestMsg{
+				Stdin: &dproto.ExecTaskStreamingIOOperation{Data: []byte("input")},
+			},
+			ExpectedError: true,
+		},
+		{
+			Name: "tty size",
+			Msg: &drivers.ExecTaskStreamingRequestMsg{
+				TtySize: &dproto.ExecTaskStreamingRequest_TerminalSize{Height: 24, Width: 80},
+			},
+			ExpectedError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			go codec.NewEncoder(p1, nstructs.MsgpackHandle).Encode(c.Msg)
+
+			stream := &actionStream{newExecStream(codec.NewDecoder(p2, nstructs.MsgpackHandle), nil)}
+			_, err := stream.Recv()
+			if c.ExpectedError {
+				require.EqualError(t, err, "actions don't accept input")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 // TestAlloc_ExecStreaming_ACL_WithIsolation_Image asserts that token only needs
 // alloc-exec acl policy when image isolation is used
 func TestAlloc_ExecStreaming_ACL_WithIsolation_Image(t *testing.T) {
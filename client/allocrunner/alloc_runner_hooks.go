@@ -7,6 +7,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	clientconfig "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/serviceregistration"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -149,7 +150,7 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 		newCgroupHook(ar.Alloc(), ar.cpusetManager),
 		newUpstreamAllocsHook(hookLogger, ar.prevAllocWatcher),
 		newDiskMigrationHook(hookLogger, ar.prevAllocMigrator, ar.allocDir),
-		newAllocHealthWatcherHook(hookLogger, alloc, hs, ar.Listener(), ar.consulClient),
+		newAllocHealthWatcherHook(hookLogger, alloc, hs, ar.Listener(), ar.healthCheckHandler(alloc)),
 		newNetworkHook(hookLogger, ns, alloc, nm, nc, ar, builtTaskEnv),
 		newGroupServiceHook(groupServiceHookConfig{
 			alloc:               alloc,
@@ -169,6 +170,17 @@ func (ar *allocRunner) initRunnerHooks(config *clientconfig.Config) error {
 	return nil
 }
 
+// healthCheckHandler returns the service registration handler which should be
+// queried for the check results of the allocation. Task groups using Nomad
+// native service discovery have their checks executed by the Nomad provider,
+// all others use Consul.
+func (ar *allocRunner) healthCheckHandler(alloc *structs.Allocation) serviceregistration.Handler {
+	if ar.serviceRegWrapper != nil && alloc.Job.RequiredNativeServiceDiscovery()[alloc.TaskGroup] {
+		return ar.serviceRegWrapper.ProviderHandler(structs.ServiceProviderNomad)
+	}
+	return ar.consulClient
+}
+
 // prerun is used to run the runners prerun hooks.
 func (ar *allocRunner) prerun() error {
 	if ar.logger.IsTrace() {
@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/stretchr/testify/require"
 )
@@ -412,6 +413,10 @@ func (vm mockVolumeMounter) UnmountVolume(ctx context.Context, volID, remoteID,
 	vm.callCounts["unmount"]++
 	return nil
 }
+func (vm mockVolumeMounter) ExpandVolume(ctx context.Context, volID, remoteID, allocID string, usageOpts *csimanager.UsageOptions, capacity *csi.CapacityRange) (int64, error) {
+	vm.callCounts["expand"]++
+	return capacity.RequiredBytes, nil
+}
 
 type mockPluginManager struct {
 	mounter mockVolumeMounter
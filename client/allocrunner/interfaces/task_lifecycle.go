@@ -56,6 +56,9 @@ type TaskPrestartRequest struct {
 	// Vault token may optionally be set if a Vault token is available
 	VaultToken string
 
+	// NomadToken is the workload identity of the task, if one was signed
+	NomadToken string
+
 	// TaskDir contains the task's directory tree on the host
 	TaskDir *allocdir.TaskDir
 
@@ -153,6 +156,9 @@ type TaskExitedHook interface {
 type TaskUpdateRequest struct {
 	VaultToken string
 
+	// NomadToken is the workload identity of the task, if one was signed
+	NomadToken string
+
 	// Alloc is the current version of the allocation (may have been
 	// updated since the hook was created)
 	Alloc *structs.Allocation
@@ -0,0 +1,556 @@
+package getter
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gg "github.com/hashicorp/go-getter"
+	hclog "github.com/hashicorp/go-hclog"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+const (
+	// cacheMetaFile is the name of the file holding the metadata of a cached
+	// artifact. Its modification time is the last time the artifact was used.
+	cacheMetaFile = "meta.json"
+
+	// cacheDataName is the name of the file or directory holding the content
+	// of a cached artifact.
+	cacheDataName = "data"
+
+	// cacheTmpDir is the directory of the cache artifacts are downloaded into
+	// before being cached.
+	cacheTmpDir = "tmp"
+)
+
+// Cache is a client wide cache of downloaded artifacts, keyed by their source
+// URL, options and headers.
+//
+// Artifacts pinned by a checksum are kept in the cache once downloaded so that
+// other tasks get them without accessing the network. The least recently used
+// artifacts are evicted once the size of the cache is over its limit, or when
+// the garbage collector needs to free disk space. Concurrent downloads of the
+// same artifact are deduplicated whether it is pinned or not.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	logger   hclog.Logger
+
+	// entries are the cached artifacts by key, and lru orders them from the
+	// most to the least recently used.
+	entries map[string]*cacheEntry
+	lru     *list.List
+	size    int64
+
+	// downloads are the artifacts being downloaded by key
+	downloads map[string]*cacheEntry
+
+	l sync.Mutex
+}
+
+// cacheEntry is an artifact that is cached or being downloaded.
+type cacheEntry struct {
+	key      string
+	dir      string
+	meta     *cacheMeta
+	lastUsed time.Time
+
+	// refs is the number of tasks copying the artifact. An artifact is only
+	// evicted or removed once it isn't referenced.
+	refs int
+
+	// elem is the element of the entry in the LRU list, or nil if the entry
+	// isn't cached.
+	elem *list.Element
+
+	// doneCh is closed once the artifact is downloaded, with err set if the
+	// download failed.
+	doneCh chan struct{}
+	err    error
+}
+
+// cacheMeta is the metadata of a cached artifact.
+type cacheMeta struct {
+	// Source is the source URL of the artifact, without its query string
+	// and credentials.
+	Source   string
+	Checksum string
+	Size     int64
+}
+
+// NewCache returns the artifact cache stored in the given directory, which
+// holds at most maxBytes of artifacts. The artifacts cached by a previous run
+// of the client are restored.
+func NewCache(logger hclog.Logger, dir string, maxBytes int64) (*Cache, error) {
+	c := &Cache{
+		dir:       dir,
+		maxBytes:  maxBytes,
+		logger:    logger.Named("artifact_cache"),
+		entries:   make(map[string]*cacheEntry),
+		lru:       list.New(),
+		downloads: make(map[string]*cacheEntry),
+	}
+
+	// Discard the downloads that were interrupted
+	tmpDir := filepath.Join(dir, cacheTmpDir)
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return nil, fmt.Errorf("failed to clean artifact cache: %v", err)
+	}
+	if err := os.MkdirAll(tmpDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create artifact cache: %v", err)
+	}
+
+	if err := c.restore(); err != nil {
+		return nil, err
+	}
+	for _, e := range c.evictLocked() {
+		os.RemoveAll(e.dir)
+	}
+	return c, nil
+}
+
+// restore restores the cached artifacts from the cache directory.
+func (c *Cache) restore() error {
+	fis, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read artifact cache: %v", err)
+	}
+
+	var entries []*cacheEntry
+	for _, fi := range fis {
+		if !fi.IsDir() || fi.Name() == cacheTmpDir {
+			continue
+		}
+
+		dir := filepath.Join(c.dir, fi.Name())
+		e, err := readCacheEntry(fi.Name(), dir)
+		if err != nil {
+			c.logger.Warn("discarding invalid cached artifact", "path", dir, "error", err)
+			os.RemoveAll(dir)
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	// Restore the entries from the least to the most recently used
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+	for _, e := range entries {
+		e.elem = c.lru.PushFront(e)
+		c.entries[e.key] = e
+		c.size += e.meta.Size
+	}
+	return nil
+}
+
+// readCacheEntry reads a cached artifact from its directory.
+func readCacheEntry(key, dir string) (*cacheEntry, error) {
+	metaPath := filepath.Join(dir, cacheMetaFile)
+	fi, err := os.Stat(metaPath)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ioutil.ReadFile(metaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta cacheMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, cacheDataName)); err != nil {
+		return nil, err
+	}
+
+	return &cacheEntry{
+		key:      key,
+		dir:      dir,
+		meta:     &meta,
+		lastUsed: fi.ModTime(),
+	}, nil
+}
+
+// Get copies an artifact to its destination in a task directory, which must
+// be within root, the directory of the allocation. The artifact is copied
+// from the cache if it is cached, or once it is downloaded if another task is
+// downloading it. Otherwise, it is downloaded into the cache using fetch, and
+// cached if it is pinned.
+func (c *Cache) Get(key string, meta *cacheMeta, pinned bool, fetch func(dst string) error, root, dst string) error {
+	c.l.Lock()
+
+	if e, ok := c.entries[key]; ok {
+		e.refs++
+		e.lastUsed = time.Now()
+		c.lru.MoveToFront(e.elem)
+		c.l.Unlock()
+
+		// Persist the last time the artifact was used
+		metaPath := filepath.Join(e.dir, cacheMetaFile)
+		if err := os.Chtimes(metaPath, e.lastUsed, e.lastUsed); err != nil {
+			c.logger.Debug("failed to update cached artifact", "path", metaPath, "error", err)
+		}
+
+		c.logger.Debug("using cached artifact", "source", e.meta.Source)
+		return c.copy(e, root, dst)
+	}
+
+	if e, ok := c.downloads[key]; ok {
+		e.refs++
+		c.l.Unlock()
+
+		c.logger.Debug("waiting for artifact being downloaded", "source", e.meta.Source)
+		<-e.doneCh
+		if e.err != nil {
+			c.release(e)
+			return e.err
+		}
+		return c.copy(e, root, dst)
+	}
+
+	tmpDir, err := ioutil.TempDir(filepath.Join(c.dir, cacheTmpDir), "")
+	if err != nil {
+		c.l.Unlock()
+		return fmt.Errorf("failed to create artifact cache directory: %v", err)
+	}
+	e := &cacheEntry{
+		key:    key,
+		dir:    tmpDir,
+		meta:   meta,
+		refs:   1,
+		doneCh: make(chan struct{}),
+	}
+	c.downloads[key] = e
+	c.l.Unlock()
+
+	data := filepath.Join(tmpDir, cacheDataName)
+	err = fetch(data)
+	if err == nil {
+		meta.Size, err = diskSize(data)
+	}
+
+	c.l.Lock()
+	delete(c.downloads, key)
+	e.err = err
+	if err == nil && pinned && meta.Size <= c.maxBytes {
+		if err := c.storeLocked(e); err != nil {
+			c.logger.Warn("failed to cache artifact", "source", meta.Source, "error", err)
+		}
+	}
+	close(e.doneCh)
+	c.l.Unlock()
+
+	if err != nil {
+		c.release(e)
+		return err
+	}
+	return c.copy(e, root, dst)
+}
+
+// storeLocked moves a downloaded artifact into the cache. The lock must be
+// held.
+func (c *Cache) storeLocked(e *cacheEntry) error {
+	raw, err := json.Marshal(e.meta)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(e.dir, cacheMetaFile), raw, 0600); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(c.dir, e.key)
+	if err := os.Rename(e.dir, dir); err != nil {
+		return err
+	}
+
+	e.dir = dir
+	e.lastUsed = time.Now()
+	e.elem = c.lru.PushFront(e)
+	c.entries[e.key] = e
+	c.size += e.meta.Size
+	return nil
+}
+
+// copy copies an artifact to its destination and releases it.
+func (c *Cache) copy(e *cacheEntry, root, dst string) error {
+	defer c.release(e)
+	return copyArtifact(filepath.Join(e.dir, cacheDataName), root, dst)
+}
+
+// release releases a reference to an artifact. Artifacts that aren't cached
+// are removed once they aren't referenced anymore, and cached artifacts are
+// evicted if the cache is over its size limit.
+func (c *Cache) release(e *cacheEntry) {
+	c.l.Lock()
+	e.refs--
+
+	var remove []*cacheEntry
+	if e.elem == nil {
+		if e.refs == 0 {
+			remove = append(remove, e)
+		}
+	} else {
+		remove = c.evictLocked()
+	}
+	c.l.Unlock()
+
+	for _, e := range remove {
+		if err := os.RemoveAll(e.dir); err != nil {
+			c.logger.Warn("failed to remove artifact", "path", e.dir, "error", err)
+		}
+	}
+}
+
+// evictLocked evicts the least recently used artifacts that aren't
+// referenced until the cache is within its size limit, and returns them so
+// they are removed once the lock is released. The lock must be held.
+func (c *Cache) evictLocked() []*cacheEntry {
+	var evicted []*cacheEntry
+	for elem := c.lru.Back(); elem != nil && c.size > c.maxBytes; {
+		e := elem.Value.(*cacheEntry)
+		elem = elem.Prev()
+		if e.refs > 0 {
+			continue
+		}
+
+		c.removeLocked(e)
+		evicted = append(evicted, e)
+	}
+	return evicted
+}
+
+// removeLocked removes an artifact from the cache. The lock must be held.
+func (c *Cache) removeLocked(e *cacheEntry) {
+	c.lru.Remove(e.elem)
+	e.elem = nil
+	delete(c.entries, e.key)
+	c.size -= e.meta.Size
+	c.logger.Debug("evicting cached artifact", "source", e.meta.Source)
+}
+
+// EvictLRU evicts the least recently used artifact that isn't being copied
+// to free disk space. It returns whether an artifact was evicted.
+func (c *Cache) EvictLRU() bool {
+	c.l.Lock()
+	var evicted *cacheEntry
+	for elem := c.lru.Back(); elem != nil; elem = elem.Prev() {
+		if e := elem.Value.(*cacheEntry); e.refs == 0 {
+			c.removeLocked(e)
+			evicted = e
+			break
+		}
+	}
+	c.l.Unlock()
+
+	if evicted == nil {
+		return false
+	}
+	if err := os.RemoveAll(evicted.dir); err != nil {
+		c.logger.Warn("failed to remove artifact", "path", evicted.dir, "error", err)
+	}
+	return true
+}
+
+// Stats returns the content of the cache, from the most to the least
+// recently used artifact.
+func (c *Cache) Stats() *cstructs.ArtifactCacheStats {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	stats := &cstructs.ArtifactCacheStats{
+		Size:    c.size,
+		MaxSize: c.maxBytes,
+		Entries: make([]*cstructs.ArtifactCacheEntry, 0, c.lru.Len()),
+	}
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*cacheEntry)
+		stats.Entries = append(stats.Entries, &cstructs.ArtifactCacheEntry{
+			Source:   e.meta.Source,
+			Checksum: e.meta.Checksum,
+			Size:     e.meta.Size,
+			LastUsed: e.lastUsed,
+			InUse:    e.refs > 0,
+		})
+	}
+	return stats
+}
+
+// cacheKey returns the key of an artifact in the cache, which identifies its
+// go-getter URL, including the options such as its checksum, the getter mode
+// and the headers it is downloaded with.
+func cacheKey(ggURL string, mode gg.ClientMode, headers http.Header) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\n%s\n", mode, ggURL)
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "%s: %s\n", name, strings.Join(headers[name], ","))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheSource returns the source of an artifact as reported by the cache
+// stats, which omits the query string and credentials of the go-getter URL.
+func cacheSource(ggURL string) string {
+	source := ggURL
+	if i := strings.IndexByte(source, '?'); i >= 0 {
+		source = source[:i]
+	}
+	if u, err := url.Parse(source); err == nil && u.User != nil {
+		u.User = nil
+		source = u.String()
+	}
+	return source
+}
+
+// diskSize returns the size of the regular files of a file or directory.
+func diskSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// copyArtifact copies a downloaded artifact, which is either a file or a
+// directory, to its destination in the task directory. Existing files are
+// replaced, and like go-getter it refuses to write through symlinks, so none
+// of the path components of the destination below root may be a symlink.
+func copyArtifact(src, root, dst string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := mkdirNoSymlinks(root, filepath.Dir(dst)); err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return copyArtifactFile(src, dst, fi.Mode())
+	}
+
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			return fmt.Errorf("artifact contains a symlink: %s", rel)
+		case info.IsDir():
+			ti, err := os.Lstat(target)
+			if os.IsNotExist(err) {
+				if err := os.MkdirAll(target, info.Mode().Perm()); err != nil {
+					return err
+				}
+				return os.Chmod(target, info.Mode().Perm())
+			} else if err != nil {
+				return err
+			}
+			if ti.Mode()&os.ModeSymlink != 0 {
+				return fmt.Errorf("refusing to write artifact through symlink %s", target)
+			}
+			if !ti.IsDir() {
+				return fmt.Errorf("artifact destination %s is not a directory", target)
+			}
+			return nil
+		default:
+			return copyArtifactFile(path, target, info.Mode())
+		}
+	})
+}
+
+// mkdirNoSymlinks creates the directory dir, which must be within root, and
+// its missing parents. It fails if any of the path components below root is a
+// symlink, which a task could use to redirect its artifacts outside of root.
+func mkdirNoSymlinks(root, dir string) error {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("artifact destination %s is outside of %s", dir, root)
+	}
+
+	path := root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if part == "." {
+			continue
+		}
+		path = filepath.Join(path, part)
+
+		fi, err := os.Lstat(path)
+		switch {
+		case os.IsNotExist(err):
+			if err := os.Mkdir(path, 0755); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case fi.Mode()&os.ModeSymlink != 0:
+			return fmt.Errorf("refusing to write artifact through symlink %s", path)
+		case !fi.IsDir():
+			return fmt.Errorf("artifact destination %s is not a directory", path)
+		}
+	}
+	return nil
+}
+
+// copyArtifactFile copies a file of a downloaded artifact to the task
+// directory.
+func copyArtifactFile(src, dst string, mode os.FileMode) error {
+	if fi, err := os.Lstat(dst); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("refusing to write artifact through symlink %s", dst)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// Set the permissions regardless of the umask, like go-getter does
+	return os.Chmod(dst, mode.Perm())
+}
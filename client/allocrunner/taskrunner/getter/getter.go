@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-cleanhttp"
 	gg "github.com/hashicorp/go-getter"
+	hclog "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/interfaces"
@@ -22,22 +25,39 @@ const (
 
 // Getter wraps go-getter calls in an artifact configuration.
 type Getter struct {
+	logger hclog.Logger
+
 	// httpClient is a shared HTTP client for use across all http/https
 	// Getter instantiations. The HTTP client is designed to be
 	// thread-safe, and using a pooled transport will help reduce excessive
 	// connections when clients are downloading lots of artifacts.
 	httpClient *http.Client
 	config     *config.ArtifactConfig
+
+	// cache is the client wide artifact cache, or nil if artifacts are
+	// downloaded directly into the task directories.
+	cache *Cache
+
+	// isolationSupported returns whether the host supports the filesystem
+	// isolation of the sandbox subprocess.
+	isolationSupported func() bool
+
+	// sandboxWarnings are the warnings about the sandbox already logged.
+	sandboxWarnings sync.Map
 }
 
 // NewGetter returns a new Getter instance. This function is called once per
-// client and shared across alloc and task runners.
-func NewGetter(config *config.ArtifactConfig) *Getter {
+// client and shared across alloc and task runners. Artifacts are downloaded
+// through the given cache unless it is nil.
+func NewGetter(logger hclog.Logger, config *config.ArtifactConfig, cache *Cache) *Getter {
 	return &Getter{
+		logger: logger.Named("artifact_getter"),
 		httpClient: &http.Client{
 			Transport: cleanhttp.DefaultPooledTransport(),
 		},
-		config: config,
+		config:             config,
+		cache:              cache,
+		isolationSupported: isolationSupported,
 	}
 }
 
@@ -65,14 +85,55 @@ func (g *Getter) GetArtifact(taskEnv interfaces.EnvReplacer, artifact *structs.T
 		mode = gg.ClientModeDir
 	}
 
+	// Artifacts may be written anywhere in the allocation directory, which
+	// contains the task directory and the shared alloc directory
+	taskDir, _ := taskEnv.ClientPath(".", false)
+	allocDir := filepath.Dir(taskDir)
+
 	headers := getHeaders(taskEnv, artifact.GetterHeaders)
-	if err := g.getClient(ggURL, headers, mode, dest).Get(); err != nil {
+	if g.cache == nil {
+		return g.fetch(ggURL, headers, mode, allocDir, dest)
+	}
+
+	// Only artifacts pinned by a checksum are kept in the cache, as the
+	// content of other artifacts may change between downloads
+	checksum := artifact.GetterOptions["checksum"]
+	pinned := checksum != ""
+	meta := &cacheMeta{
+		Source:   cacheSource(ggURL),
+		Checksum: taskEnv.ReplaceEnv(checksum),
+	}
+	fetch := func(dst string) error {
+		return g.fetch(ggURL, headers, mode, filepath.Dir(dst), dst)
+	}
+	if err := g.cache.Get(cacheKey(ggURL, mode, headers), meta, pinned, fetch, allocDir, dest); err != nil {
+		var getErr *GetError
+		if errors.As(err, &getErr) {
+			return getErr
+		}
 		return newGetError(ggURL, err, true)
 	}
 
 	return nil
 }
 
+// fetch downloads an artifact to its destination, in the sandbox subprocess
+// unless it is disabled. The destination must be within root, and none of its
+// parents below root may be a symlink.
+func (g *Getter) fetch(src string, headers http.Header, mode gg.ClientMode, root, dst string) error {
+	if !g.config.DisableSandbox {
+		return g.sandboxGet(src, headers, mode, root, dst)
+	}
+
+	if err := mkdirNoSymlinks(root, filepath.Dir(dst)); err != nil {
+		return newGetError(src, err, false)
+	}
+	if err := g.getClient(src, headers, mode, dst).Get(); err != nil {
+		return newGetError(src, err, true)
+	}
+	return nil
+}
+
 // getClient returns a client that is suitable for Nomad downloading artifacts.
 func (g *Getter) getClient(src string, headers http.Header, mode gg.ClientMode, dst string) *gg.Client {
 	return &gg.Client{
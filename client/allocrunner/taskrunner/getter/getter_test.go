@@ -20,6 +20,7 @@ import (
 	"github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
@@ -72,14 +73,14 @@ func (u upperReplacer) ClientPath(p string, join bool) (string, bool) {
 }
 
 func TestGetter_getClient(t *testing.T) {
-	getter := NewGetter(&clientconfig.ArtifactConfig{
+	getter := NewGetter(testlog.HCLogger(t), &clientconfig.ArtifactConfig{
 		HTTPReadTimeout: time.Minute,
 		HTTPMaxBytes:    100_000,
 		GCSTimeout:      1 * time.Minute,
 		GitTimeout:      2 * time.Minute,
 		HgTimeout:       3 * time.Minute,
 		S3Timeout:       4 * time.Minute,
-	})
+	}, nil)
 	client := getter.getClient("src", nil, gg.ClientModeAny, "dst")
 
 	t.Run("check symlink config", func(t *testing.T) {
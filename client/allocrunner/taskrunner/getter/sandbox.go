@@ -0,0 +1,356 @@
+package getter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cleanhttp"
+	gg "github.com/hashicorp/go-getter"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/uuid"
+)
+
+const (
+	// sandboxCommand is the command the client binary is run with to
+	// download an artifact in the sandbox subprocess.
+	sandboxCommand = "artifact-isol"
+
+	// sandboxParamsEnv is the environment variable the parameters of a
+	// download are passed in when the sandbox subprocess re-executes itself
+	// once isolated.
+	sandboxParamsEnv = "NOMAD_ARTIFACT_SANDBOX_PARAMS"
+
+	// sandboxExitFailure and sandboxExitInvalid are the exit codes of the
+	// sandbox subprocess when the download fails and when its parameters are
+	// invalid. The error is written to stderr.
+	sandboxExitFailure = 10
+	sandboxExitInvalid = 11
+
+	// sandboxMaxStderr is the maximum size of the output of the sandbox
+	// subprocess that is kept to report errors.
+	sandboxMaxStderr = 64 * 1024
+)
+
+// sandboxEnvVars are the environment variables of the client passed to the
+// sandbox subprocess, which are the ones the getters need to find their tools
+// and proxies. Other variables, like tokens and cloud credentials, are left
+// out.
+var sandboxEnvVars = []string{
+	"PATH",
+	"HTTP_PROXY", "http_proxy",
+	"HTTPS_PROXY", "https_proxy",
+	"NO_PROXY", "no_proxy",
+	"ALL_PROXY", "all_proxy",
+
+	// Windows requires it for networking
+	"SYSTEMROOT",
+}
+
+// sandboxParams are the parameters of the download of an artifact by the
+// sandbox subprocess.
+type sandboxParams struct {
+	Config  *config.ArtifactConfig
+	Source  string
+	Dest    string
+	Mode    gg.ClientMode
+	Headers http.Header
+
+	// Writable is the only directory the subprocess may write to once
+	// isolated, which holds the destination of the download.
+	Writable string
+
+	// Isolate is whether the subprocess must restrict its filesystem access
+	// before downloading. It is unset when the isolation is disabled or not
+	// supported by the host.
+	Isolate bool
+}
+
+// sandboxGet downloads an artifact in a subprocess of the client, which is
+// isolated to only write to a private download directory and is resource
+// constrained. The artifact is then moved or copied to its destination, which
+// must be within root without any symlinked parent below root.
+func (g *Getter) sandboxGet(src string, headers http.Header, mode gg.ClientMode, root, dst string) error {
+	if err := mkdirNoSymlinks(root, filepath.Dir(dst)); err != nil {
+		return newGetError(src, err, false)
+	}
+
+	// Download into a directory of root, which the task can't replace with a
+	// symlink, so the artifact can usually be renamed into place
+	dir, err := ioutil.TempDir(root, ".artifact-")
+	if err != nil {
+		return newGetError(src, fmt.Errorf("failed to create download directory: %v", err), false)
+	}
+	defer os.RemoveAll(dir)
+
+	user, err := sandboxUser(g.config.SandboxUser)
+	if errors.Is(err, errSandboxUserIgnored) {
+		g.warnSandbox("artifact downloads run as the client's user", "error", err)
+	} else if err != nil {
+		return newGetError(src, err, false)
+	}
+	isolate, err := g.checkSandbox(g.isolationSupported())
+	if err != nil {
+		return newGetError(src, err, false)
+	}
+	if err := user.own(dir); err != nil {
+		return newGetError(src, fmt.Errorf("failed to create download directory: %v", err), false)
+	}
+
+	params := &sandboxParams{
+		Config:   g.config,
+		Source:   src,
+		Dest:     filepath.Join(dir, "data"),
+		Mode:     mode,
+		Headers:  headers,
+		Writable: dir,
+		Isolate:  isolate,
+	}
+	if err := g.runSandbox(params, user); err != nil {
+		return err
+	}
+
+	// Files owned by the sandbox user are copied so the task directory only
+	// holds files owned by the client
+	if _, err := os.Lstat(dst); os.IsNotExist(err) && user == nil {
+		if err := mkdirNoSymlinks(root, filepath.Dir(dst)); err != nil {
+			return newGetError(src, err, false)
+		}
+		if err := os.Rename(params.Dest, dst); err == nil {
+			return nil
+		}
+	}
+	if err := copyArtifact(params.Dest, root, dst); err != nil {
+		return newGetError(src, err, true)
+	}
+	return nil
+}
+
+// checkSandbox returns whether the sandbox subprocess downloads an artifact
+// with its filesystem access restricted. Where the host doesn't support the
+// isolation, artifacts are only downloaded once the operator disabled it.
+func (g *Getter) checkSandbox(supported bool) (bool, error) {
+	if g.config.DisableFilesystemIsolation {
+		return false, nil
+	}
+	if !supported {
+		return false, fmt.Errorf("%w on this host, set disable_filesystem_isolation to download artifacts without it",
+			errIsolationUnsupported)
+	}
+	return true, nil
+}
+
+// warnSandbox logs a warning about the protections of the sandbox, once per
+// client.
+func (g *Getter) warnSandbox(msg string, args ...interface{}) {
+	if _, warned := g.sandboxWarnings.LoadOrStore(msg, struct{}{}); !warned {
+		g.logger.Warn(msg, args...)
+	}
+}
+
+// runSandbox runs the sandbox subprocess and waits for the download to
+// complete. The subprocess is placed in a cgroup enforcing its resource
+// limits before it receives its parameters.
+func (g *Getter) runSandbox(params *sandboxParams, user *sandboxCredential) error {
+	src := params.Source
+
+	bin, err := os.Executable()
+	if err != nil {
+		return newGetError(src, fmt.Errorf("failed to find client binary: %v", err), false)
+	}
+
+	// Temporary files of go-getter and the files the VCS tools write to
+	// their home directory are written to the download directory as well
+	tmpDir := filepath.Join(params.Writable, "tmp")
+	homeDir := filepath.Join(params.Writable, "home")
+	for _, dir := range []string{tmpDir, homeDir} {
+		if err := os.Mkdir(dir, 0700); err != nil {
+			return newGetError(src, err, false)
+		}
+		if err := user.own(dir); err != nil {
+			return newGetError(src, err, false)
+		}
+	}
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return newGetError(src, err, false)
+	}
+
+	stderr := &limitedBuffer{max: sandboxMaxStderr}
+	cmd := exec.Command(bin, sandboxCommand)
+	cmd.Env = sandboxEnv(homeDir, tmpDir)
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return newGetError(src, err, false)
+	}
+	setSandboxAttrs(cmd, user)
+
+	cgroup, err := newSandboxCgroup(g.config, uuid.Generate())
+	if err != nil {
+		return newGetError(src, fmt.Errorf("failed to create cgroup for artifact download, "+
+			"set sandbox_memory_max and sandbox_cpu_max to 0 to download artifacts without resource limits: %v", err), false)
+	}
+	defer cgroup.destroy()
+
+	if err := cmd.Start(); err != nil {
+		return newGetError(src, fmt.Errorf("failed to start artifact download: %v", err), false)
+	}
+
+	// The subprocess waits for its parameters, so it can't download anything
+	// before it is constrained
+	if err := cgroup.apply(cmd.Process.Pid); err != nil {
+		killSandbox(cmd)
+		cmd.Wait()
+		return newGetError(src, fmt.Errorf("failed to apply cgroup to artifact download: %v", err), false)
+	}
+
+	ctx, cancel := context.Background(), func() {}
+	if g.config.SandboxTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, g.config.SandboxTimeout)
+	}
+	defer cancel()
+
+	doneCh := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killSandbox(cmd)
+		case <-doneCh:
+		}
+	}()
+
+	_, err = stdin.Write(raw)
+	stdin.Close()
+	if err != nil {
+		killSandbox(cmd)
+	}
+	if waitErr := cmd.Wait(); err == nil {
+		err = waitErr
+	}
+	close(doneCh)
+
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return newGetError(src, fmt.Errorf("artifact download timed out after %s", g.config.SandboxTimeout), true)
+	case cgroup.oomKilled():
+		return newGetError(src, fmt.Errorf("artifact download exceeded memory limit of %d bytes", g.config.SandboxMemoryMaxBytes), false)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && msg != "" {
+		switch exitErr.ExitCode() {
+		case sandboxExitFailure:
+			return newGetError(src, errors.New(msg), true)
+		case sandboxExitInvalid:
+			return newGetError(src, errors.New(msg), false)
+		}
+	}
+	if msg != "" {
+		err = fmt.Errorf("%v: %s", err, msg)
+	}
+	return newGetError(src, fmt.Errorf("artifact download failed: %v", err), true)
+}
+
+// sandboxEnv returns the environment of the sandbox subprocess, which only
+// holds the variables of sandboxEnvVars set for the client.
+func sandboxEnv(homeDir, tmpDir string) []string {
+	env := []string{"HOME=" + homeDir, "TMPDIR=" + tmpDir}
+	for _, name := range sandboxEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// sandboxMain is the entry point of the sandbox subprocess. The parameters of
+// the download are read from stdin, or from the environment once the
+// subprocess re-executed itself with its filesystem access restricted.
+func sandboxMain() int {
+	var params sandboxParams
+
+	raw, reexecuted := os.LookupEnv(sandboxParamsEnv)
+	if reexecuted {
+		os.Unsetenv(sandboxParamsEnv)
+	} else {
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read parameters: %v\n", err)
+			return sandboxExitInvalid
+		}
+		raw = string(b)
+	}
+	if err := json.Unmarshal([]byte(raw), &params); err != nil || params.Config == nil {
+		fmt.Fprintf(os.Stderr, "invalid parameters: %v\n", err)
+		return sandboxExitInvalid
+	}
+
+	if !reexecuted && params.Isolate {
+		err := isolateAndExec(params.Writable, raw)
+		fmt.Fprintf(os.Stderr, "failed to isolate artifact download: %v\n", err)
+		return sandboxExitInvalid
+	}
+
+	g := &Getter{
+		httpClient: &http.Client{
+			Transport: cleanhttp.DefaultTransport(),
+		},
+		config: params.Config,
+	}
+	if err := g.getClient(params.Source, params.Headers, params.Mode, params.Dest).Get(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return sandboxExitFailure
+	}
+	return 0
+}
+
+// errIsolationUnsupported is returned when the filesystem access of the
+// sandbox subprocess can't be restricted on this host.
+var errIsolationUnsupported = errors.New("filesystem isolation is not supported")
+
+// errSandboxUserIgnored is returned when the sandbox subprocess can't run as
+// the configured sandbox user.
+var errSandboxUserIgnored = errors.New("sandbox user is ignored")
+
+// limitedBuffer is a writer keeping the first bytes written to it.
+type limitedBuffer struct {
+	max int
+	buf []byte
+	l   sync.Mutex
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if n := b.max - len(b.buf); n > 0 {
+		if len(p) < n {
+			n = len(p)
+		}
+		b.buf = append(b.buf, p[:n]...)
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.l.Lock()
+	defer b.l.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,64 @@
+//go:build !linux
+
+package getter
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/hashicorp/nomad/client/config"
+)
+
+// isolationSupported returns whether the filesystem access of the sandbox
+// subprocess can be restricted, which is only supported on Linux.
+func isolationSupported() bool {
+	return false
+}
+
+// isolateAndExec is only supported on Linux.
+func isolateAndExec(string, string) error {
+	return errIsolationUnsupported
+}
+
+// sandboxCredential is the unprivileged user the sandbox subprocess runs as,
+// which is only supported on Linux.
+type sandboxCredential struct{}
+
+// sandboxUser always runs the sandbox subprocess as the client's user.
+func sandboxUser(name string) (*sandboxCredential, error) {
+	if name != "" {
+		return nil, fmt.Errorf("%w as it is only supported on Linux", errSandboxUserIgnored)
+	}
+	return nil, nil
+}
+
+func (c *sandboxCredential) own(string) error {
+	return nil
+}
+
+func setSandboxAttrs(*exec.Cmd, *sandboxCredential) {}
+
+// killSandbox kills the running sandbox subprocess.
+func killSandbox(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+// sandboxCgroup enforces the resource limits of the sandbox subprocess, which
+// is only supported on Linux.
+type sandboxCgroup struct{}
+
+// newSandboxCgroup returns nil if the subprocess has no resource limits, as
+// they can't be enforced.
+func newSandboxCgroup(conf *config.ArtifactConfig, _ string) (*sandboxCgroup, error) {
+	if conf.SandboxMemoryMaxBytes > 0 || conf.SandboxCPUMax > 0 {
+		return nil, errors.New("resource limits are only supported on Linux")
+	}
+	return nil, nil
+}
+
+func (c *sandboxCgroup) apply(int) error { return nil }
+
+func (c *sandboxCgroup) oomKilled() bool { return false }
+
+func (c *sandboxCgroup) destroy() {}
@@ -0,0 +1,297 @@
+//go:build linux
+
+package getter
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs2"
+	"github.com/opencontainers/runc/libcontainer/configs"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// landlockAccessFSRefer and landlockAccessFSTruncate are the access
+	// rights added by the versions 2 and 3 of the Landlock ABI.
+	landlockAccessFSRefer    = 1 << 13
+	landlockAccessFSTruncate = 1 << 14
+
+	// landlockRead and landlockWrite are the access rights of the sandbox
+	// subprocess to the filesystem and to its download directory.
+	landlockRead  = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+	landlockWrite = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE | unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR | unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK | unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+	// sandboxCPUPeriod is the period of the CPU quota of the sandbox cgroup
+	// in microseconds.
+	sandboxCPUPeriod = 100000
+)
+
+// isolationSupported returns whether the filesystem access of the sandbox
+// subprocess can be restricted using Landlock.
+func isolationSupported() bool {
+	_, ok := landlockABI()
+	return ok
+}
+
+// landlockABI returns the version of the Landlock ABI supported by the
+// kernel, if any.
+func landlockABI() (uintptr, bool) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	return abi, errno == 0
+}
+
+// isolateAndExec restricts the filesystem access of the sandbox subprocess
+// using Landlock, so that it can read the whole filesystem but only write to
+// its download directory, and re-executes it with the given parameters.
+//
+// Landlock only restricts the calling thread, so the restricted thread
+// re-executes the subprocess for the restrictions to apply to all of its
+// threads and children. It only returns if the restrictions failed.
+func isolateAndExec(writable, params string) error {
+	abi, ok := landlockABI()
+	if !ok {
+		return errIsolationUnsupported
+	}
+
+	read, write := uint64(landlockRead), uint64(landlockWrite)
+	if abi >= 2 {
+		write |= landlockAccessFSRefer
+	}
+	if abi >= 3 {
+		write |= landlockAccessFSTruncate
+	}
+
+	bin, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// The thread must not run other goroutines once restricted
+	runtime.LockOSThread()
+
+	attr := unix.LandlockRulesetAttr{Access_fs: read | write}
+	ruleset, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create landlock ruleset: %v", errno)
+	}
+	defer unix.Close(int(ruleset))
+
+	rules := []struct {
+		path   string
+		access uint64
+	}{
+		{"/", read},
+		{writable, read | write},
+		{"/dev/null", unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE},
+	}
+	for _, rule := range rules {
+		if err := landlockAddRule(int(ruleset), rule.path, rule.access); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %v", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, ruleset, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce landlock ruleset: %v", errno)
+	}
+
+	env := append(os.Environ(), sandboxParamsEnv+"="+params)
+	return syscall.Exec(bin, []string{bin, sandboxCommand}, env)
+}
+
+// landlockAddRule allows the given access beneath a path.
+func landlockAddRule(ruleset int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer unix.Close(fd)
+
+	// Files only support the access rights to files
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return fmt.Errorf("failed to stat %s: %v", path, err)
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE |
+			unix.LANDLOCK_ACCESS_FS_WRITE_FILE | landlockAccessFSTruncate
+	}
+
+	rule := unix.LandlockPathBeneathAttr{
+		Allowed_access: access,
+		Parent_fd:      int32(fd),
+	}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to add landlock rule for %s: %v", path, errno)
+	}
+	return nil
+}
+
+// sandboxCredential is the unprivileged user the sandbox subprocess runs as.
+type sandboxCredential struct {
+	uid uint32
+	gid uint32
+}
+
+// sandboxUser returns the credential of the sandbox user, or nil if the
+// subprocess runs as the client's user. Switching users requires the client
+// to run as root, otherwise errSandboxUserIgnored is returned.
+func sandboxUser(name string) (*sandboxCredential, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if os.Geteuid() != 0 {
+		return nil, fmt.Errorf("%w as the client is not running as root", errSandboxUserIgnored)
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sandbox user %q: %v", name, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid of sandbox user %q: %v", name, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid of sandbox user %q: %v", name, err)
+	}
+	return &sandboxCredential{uid: uint32(uid), gid: uint32(gid)}, nil
+}
+
+// own gives a directory to the sandbox user.
+func (c *sandboxCredential) own(path string) error {
+	if c == nil {
+		return nil
+	}
+	return os.Chown(path, int(c.uid), int(c.gid))
+}
+
+// setSandboxAttrs runs the sandbox subprocess in its own process group, so
+// that it can be killed along with its children, and as the sandbox user.
+func setSandboxAttrs(cmd *exec.Cmd, user *sandboxCredential) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if user != nil {
+		cmd.SysProcAttr.Credential = &syscall.Credential{
+			Uid:         user.uid,
+			Gid:         user.gid,
+			NoSetGroups: true,
+		}
+	}
+}
+
+// killSandbox kills the process group of the running sandbox subprocess.
+func killSandbox(cmd *exec.Cmd) {
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// sandboxCgroup is the cgroup enforcing the resource limits of the sandbox
+// subprocess.
+type sandboxCgroup struct {
+	manager cgroups.Manager
+}
+
+// newSandboxCgroup creates the cgroup of a sandbox subprocess under the
+// client's cgroup parent. It returns nil if the subprocess has no resource
+// limits.
+func newSandboxCgroup(conf *config.ArtifactConfig, id string) (*sandboxCgroup, error) {
+	if conf.SandboxMemoryMaxBytes <= 0 && conf.SandboxCPUMax <= 0 {
+		return nil, nil
+	}
+
+	resources := &configs.Resources{SkipDevices: true}
+	if conf.SandboxMemoryMaxBytes > 0 {
+		resources.Memory = conf.SandboxMemoryMaxBytes
+
+		// Disable swap so the limit is enforced
+		if cgutil.UseV2 {
+			resources.MemorySwap = conf.SandboxMemoryMaxBytes
+		} else {
+			var swappiness uint64
+			resources.MemorySwappiness = &swappiness
+		}
+	}
+	if conf.SandboxCPUMax > 0 {
+		resources.CpuPeriod = sandboxCPUPeriod
+		resources.CpuQuota = int64(conf.SandboxCPUMax * sandboxCPUPeriod)
+	}
+
+	cg := &configs.Cgroup{
+		Path:      filepath.Join("/", cgutil.GetCgroupParent(conf.CgroupParent), "artifact-"+id),
+		Resources: resources,
+	}
+
+	var manager cgroups.Manager
+	if cgutil.UseV2 {
+		m, err := fs2.NewManager(cg, "", false)
+		if err != nil {
+			return nil, err
+		}
+		manager = m
+	} else {
+		manager = fs.NewManager(cg, nil, false)
+	}
+
+	// Apply the limits to an empty cgroup, which creates it
+	if err := manager.Apply(-1); err != nil {
+		manager.Destroy()
+		return nil, err
+	}
+	if err := manager.Set(resources); err != nil {
+		manager.Destroy()
+		return nil, err
+	}
+	return &sandboxCgroup{manager: manager}, nil
+}
+
+// apply moves the sandbox subprocess into the cgroup.
+func (c *sandboxCgroup) apply(pid int) error {
+	if c == nil {
+		return nil
+	}
+	return cgroups.EnterPid(c.manager.GetPaths(), pid)
+}
+
+// oomKilled returns whether processes of the cgroup were killed because
+// they exceeded the memory limit.
+func (c *sandboxCgroup) oomKilled() bool {
+	if c == nil {
+		return false
+	}
+	n, err := c.manager.OOMKillCount()
+	return err == nil && n > 0
+}
+
+// destroy kills the processes left in the cgroup and removes it.
+func (c *sandboxCgroup) destroy() {
+	if c == nil {
+		return
+	}
+	if pids, err := c.manager.GetAllPids(); err == nil {
+		for _, pid := range pids {
+			syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+	c.manager.Destroy()
+}
@@ -0,0 +1,50 @@
+//go:build linux
+
+package getter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestSandbox_FilesystemIsolation(t *testing.T) {
+	ci.Parallel(t)
+
+	_, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		t.Skip("Landlock is not supported")
+	}
+
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+	getter := testSandboxGetter(t)
+
+	// The subprocess can't write outside of its download directory
+	dst := filepath.Join(t.TempDir(), "test.sh")
+	params := &sandboxParams{
+		Config:   getter.config,
+		Source:   fmt.Sprintf("%s/test.sh", ts.URL),
+		Dest:     dst,
+		Mode:     gg.ClientModeFile,
+		Writable: t.TempDir(),
+		Isolate:  true,
+	}
+	err := getter.runSandbox(params, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+	require.NoFileExists(t, dst)
+
+	// Unless its filesystem access isn't restricted
+	params.Isolate = false
+	params.Writable = t.TempDir()
+	require.NoError(t, getter.runSandbox(params, nil))
+	checkContents(filepath.Dir(dst), map[string]string{"test.sh": "sleep 1\n"}, t)
+}
@@ -0,0 +1,226 @@
+package getter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testSandboxGetter returns a getter downloading artifacts in the sandbox
+// subprocess with the default configuration.
+func testSandboxGetter(t *testing.T) *Getter {
+	getterConf := testArtifactConfig(t)
+	require.False(t, getterConf.DisableSandbox)
+	return NewGetter(testlog.HCLogger(t), getterConf, nil)
+}
+
+func TestSandbox_GetArtifact(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+	getter := testSandboxGetter(t)
+
+	taskDir := t.TempDir()
+	createContents(taskDir, map[string]string{
+		"exist/my.config": "to be replaced",
+		"untouched":       "existing top-level",
+	}, t)
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/archive.tar.gz", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "sha1:20bab73c72c56490856f913cf594bad9a4d730f6",
+		},
+	}
+	require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+	checkContents(taskDir, map[string]string{
+		"untouched":       "existing top-level",
+		"exist/my.config": "hello world\n",
+		"new/my.config":   "hello world\n",
+		"test.sh":         "sleep 1\n",
+	}, t)
+}
+
+func TestSandbox_GetArtifact_SymlinkParent(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+	getter := testSandboxGetter(t)
+
+	// Files aren't written through a symlinked parent directory in the task
+	// directory, which points outside of the alloc directory
+	allocDir := t.TempDir()
+	taskDir := filepath.Join(allocDir, "web")
+	require.NoError(t, os.Mkdir(taskDir, 0755))
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(taskDir, "local")))
+
+	for _, dest := range []string{"local/bin/", "local/"} {
+		artifact := &structs.TaskArtifact{
+			GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+			RelativeDest: dest,
+		}
+		err := getter.GetArtifact(noopTaskEnv(taskDir), artifact)
+		require.Error(t, err, dest)
+		require.Contains(t, err.Error(), "symlink", dest)
+	}
+
+	entries, err := ioutil.ReadDir(outside)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// The download directories are removed
+	entries, err = ioutil.ReadDir(allocDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestSandbox_GetArtifact_Errors(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+	getter := testSandboxGetter(t)
+
+	// Download failures are recoverable
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/missing", ts.URL),
+	}
+	err := getter.GetArtifact(noopTaskEnv(t.TempDir()), artifact)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+	require.True(t, err.(*GetError).IsRecoverable())
+
+	// Checksum mismatches are reported by the subprocess
+	artifact = &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:00000000000000000000000000000000",
+		},
+	}
+	err = getter.GetArtifact(noopTaskEnv(t.TempDir()), artifact)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Checksums did not match")
+}
+
+func TestSandbox_GetArtifact_Timeout(t *testing.T) {
+	ci.Parallel(t)
+
+	doneCh := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-doneCh:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(doneCh)
+
+	getter := testSandboxGetter(t)
+	getter.config.SandboxTimeout = 500 * time.Millisecond
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/slow", ts.URL),
+	}
+	err := getter.GetArtifact(noopTaskEnv(t.TempDir()), artifact)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timed out")
+	require.True(t, err.(*GetError).IsRecoverable())
+}
+
+func TestSandbox_GetArtifact_Unisolated(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := httptest.NewServer(http.FileServer(http.Dir("./test-fixtures/")))
+	defer ts.Close()
+
+	// Hosts without filesystem isolation refuse to download artifacts
+	getter := testSandboxGetter(t)
+	getter.config.DisableFilesystemIsolation = false
+	getter.isolationSupported = func() bool { return false }
+
+	taskDir := t.TempDir()
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+	}
+	err := getter.GetArtifact(noopTaskEnv(taskDir), artifact)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "disable_filesystem_isolation")
+	require.False(t, err.(*GetError).IsRecoverable())
+	require.NoFileExists(t, filepath.Join(taskDir, "test.sh"))
+
+	// Unless the operator disabled the isolation
+	getter.config.DisableFilesystemIsolation = true
+	require.NoError(t, getter.GetArtifact(noopTaskEnv(taskDir), artifact))
+	checkContents(taskDir, map[string]string{
+		"test.sh": "sleep 1\n",
+	}, t)
+}
+
+func TestSandbox_checkSandbox(t *testing.T) {
+	ci.Parallel(t)
+
+	getter := testSandboxGetter(t)
+	getter.config.DisableFilesystemIsolation = false
+
+	// Downloads are isolated by default
+	isolate, err := getter.checkSandbox(true)
+	require.NoError(t, err)
+	require.True(t, isolate)
+
+	// And fail where the host doesn't support it
+	_, err = getter.checkSandbox(false)
+	require.ErrorIs(t, err, errIsolationUnsupported)
+
+	// Unless the isolation is explicitly disabled
+	getter.config.DisableFilesystemIsolation = true
+	for _, supported := range []bool{true, false} {
+		isolate, err = getter.checkSandbox(supported)
+		require.NoError(t, err)
+		require.False(t, isolate)
+	}
+}
+
+func TestSandbox_sandboxEnv(t *testing.T) {
+	// Not parallel as it sets environment variables
+	t.Setenv("PATH", "/usr/bin:/bin")
+	t.Setenv("HTTPS_PROXY", "http://proxy:3128")
+	t.Setenv("VAULT_TOKEN", "secret")
+	t.Setenv("NOMAD_TOKEN", "secret")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	env := sandboxEnv("/alloc/.artifact-1/home", "/alloc/.artifact-1/tmp")
+	require.Contains(t, env, "HOME=/alloc/.artifact-1/home")
+	require.Contains(t, env, "TMPDIR=/alloc/.artifact-1/tmp")
+	require.Contains(t, env, "PATH=/usr/bin:/bin")
+	require.Contains(t, env, "HTTPS_PROXY=http://proxy:3128")
+	for _, v := range env {
+		require.NotContains(t, v, "secret")
+	}
+}
+
+func TestSandbox_limitedBuffer(t *testing.T) {
+	ci.Parallel(t)
+
+	b := &limitedBuffer{max: 5}
+	n, err := b.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	n, err = b.Write([]byte("defgh"))
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.Equal(t, "abcde", b.String())
+}
@@ -4,15 +4,33 @@
 package getter
 
 import (
+	"os"
+	"runtime"
 	"testing"
 
 	clientconfig "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultGetter(t *testing.T) *Getter {
+	return NewGetter(testlog.HCLogger(t), testArtifactConfig(t), nil)
+}
+
+// testArtifactConfig returns the default artifact configuration, opting out of
+// the protections of the sandbox the host can't enforce like an operator
+// would, as downloads fail otherwise.
+func testArtifactConfig(t *testing.T) *clientconfig.ArtifactConfig {
 	getterConf, err := clientconfig.ArtifactConfigFromAgent(config.DefaultArtifactConfig())
 	require.NoError(t, err)
-	return NewGetter(getterConf)
+
+	if !isolationSupported() {
+		getterConf.DisableFilesystemIsolation = true
+	}
+	if runtime.GOOS != "linux" || os.Geteuid() != 0 {
+		getterConf.SandboxMemoryMaxBytes = 0
+		getterConf.SandboxCPUMax = 0
+	}
+	return getterConf
 }
@@ -0,0 +1,78 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// wiTokenFile is the name of the file within the task's secrets directory
+	// to which the workload identity is written.
+	wiTokenFile = "nomad_token"
+)
+
+// identityHook sets the workload identity signed for the task by the servers
+// on the task runner, writes it to the task's secrets directory and injects
+// it into the task's environment as NOMAD_TOKEN.
+type identityHook struct {
+	tr       *TaskRunner
+	taskName string
+	logger   log.Logger
+
+	// lock ensures the token is not concurrently written by Prestart and
+	// Update.
+	lock sync.Mutex
+}
+
+func newIdentityHook(tr *TaskRunner, logger log.Logger) *identityHook {
+	h := &identityHook{
+		tr:       tr,
+		taskName: tr.taskName,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*identityHook) Name() string {
+	return "identity"
+}
+
+func (h *identityHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.setToken(h.tr.Alloc(), req.TaskDir.SecretsDir)
+}
+
+func (h *identityHook) Update(_ context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.setToken(req.Alloc, h.tr.taskDir.SecretsDir)
+}
+
+// setToken sets the workload identity of the task from the allocation. It is
+// a no-op if the servers did not sign an identity for the task, which is the
+// case for allocations placed before the keyring was initialized.
+func (h *identityHook) setToken(alloc *structs.Allocation, secretsDir string) error {
+	token := alloc.SignedIdentities[h.taskName]
+	if token == "" || token == h.tr.getNomadToken() {
+		return nil
+	}
+
+	tokenPath := filepath.Join(secretsDir, wiTokenFile)
+	if err := ioutil.WriteFile(tokenPath, []byte(token), 0666); err != nil {
+		return fmt.Errorf("failed to write workload identity: %v", err)
+	}
+
+	h.tr.setNomadToken(token)
+	h.logger.Trace("workload identity set", "path", tokenPath)
+	return nil
+}
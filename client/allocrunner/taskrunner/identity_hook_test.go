@@ -0,0 +1,101 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the identity hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*identityHook)(nil)
+var _ interfaces.TaskUpdateHook = (*identityHook)(nil)
+
+func TestIdentityHook_Prestart(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	alloc.SignedIdentities = map[string]string{task.Name: "header.claims.sig"}
+
+	allocDir := allocdir.NewAllocDir(logger, t.TempDir(), alloc.ID)
+	defer allocDir.Destroy()
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(t, taskDir.Build(false, nil))
+
+	tr := &TaskRunner{
+		alloc:      alloc,
+		taskName:   task.Name,
+		taskDir:    taskDir,
+		envBuilder: taskenv.NewBuilder(mock.Node(), alloc, task, "global"),
+	}
+	h := newIdentityHook(tr, logger)
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: taskDir,
+	}
+	require.NoError(t, h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+
+	// The token should be written to the secrets dir and set in the
+	// environment.
+	tokenPath := filepath.Join(taskDir.SecretsDir, wiTokenFile)
+	token, err := ioutil.ReadFile(tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, "header.claims.sig", string(token))
+	require.Equal(t, "header.claims.sig", tr.getNomadToken())
+	require.Equal(t, "header.claims.sig", tr.envBuilder.Build().Map()[taskenv.WorkloadToken])
+
+	// An updated allocation should update the token.
+	updated := alloc.Copy()
+	updated.SignedIdentities[task.Name] = "header.claims.newsig"
+	require.NoError(t, h.Update(context.Background(),
+		&interfaces.TaskUpdateRequest{Alloc: updated}, &interfaces.TaskUpdateResponse{}))
+
+	token, err = ioutil.ReadFile(tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, "header.claims.newsig", string(token))
+	require.Equal(t, "header.claims.newsig", tr.getNomadToken())
+}
+
+func TestIdentityHook_NoIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	allocDir := allocdir.NewAllocDir(logger, t.TempDir(), alloc.ID)
+	defer allocDir.Destroy()
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(t, taskDir.Build(false, nil))
+
+	tr := &TaskRunner{
+		alloc:      alloc,
+		taskName:   task.Name,
+		taskDir:    taskDir,
+		envBuilder: taskenv.NewBuilder(mock.Node(), alloc, task, "global"),
+	}
+	h := newIdentityHook(tr, logger)
+
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: taskDir,
+	}
+	require.NoError(t, h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+
+	// Allocations placed without an identity should not fail, nor have a
+	// token set.
+	require.NoFileExists(t, filepath.Join(taskDir.SecretsDir, wiTokenFile))
+	require.Empty(t, tr.getNomadToken())
+	require.NotContains(t, tr.envBuilder.Build().Map(), taskenv.WorkloadToken)
+}
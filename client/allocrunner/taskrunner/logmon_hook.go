@@ -12,6 +12,7 @@ import (
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/logmon"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
@@ -176,6 +177,15 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
+	// Tag the lines shipped to the log sinks with the task metadata
+	meta := &logging.LogMetadata{Task: req.Task.Name}
+	if alloc := h.runner.Alloc(); alloc != nil {
+		meta.AllocID = alloc.ID
+		meta.Namespace = alloc.Namespace
+		meta.JobID = alloc.JobID
+		meta.Group = alloc.TaskGroup
+	}
+
 	err := h.logmon.Start(&logmon.LogConfig{
 		LogDir:        h.config.logDir,
 		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
@@ -184,6 +194,8 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		StderrFifo:    h.config.stderrFifo,
 		MaxFiles:      req.Task.LogConfig.MaxFiles,
 		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
+		Metadata:      meta,
+		Sinks:         req.Task.LogConfig.Sinks,
 	})
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
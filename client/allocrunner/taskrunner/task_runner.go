@@ -187,6 +187,11 @@ type TaskRunner struct {
 	vaultToken     string
 	vaultTokenLock sync.Mutex
 
+	// nomadToken is the workload identity signed for the task. It should be
+	// accessed with the getter.
+	nomadToken     string
+	nomadTokenLock sync.Mutex
+
 	// baseLabels are used when emitting tagged metrics. All task runner metrics
 	// will have these tags, and optionally more.
 	baseLabels []metrics.Label
@@ -247,6 +252,14 @@ type TaskRunner struct {
 
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
+
+	// rpcClient is used by hooks to make RPC calls to the servers.
+	rpcClient RPCer
+}
+
+// RPCer is the interface needed by hooks to make RPC calls.
+type RPCer interface {
+	RPC(method string, args interface{}, reply interface{}) error
 }
 
 type Config struct {
@@ -315,6 +328,10 @@ type Config struct {
 
 	// Getter is an interface for retrieving artifacts.
 	Getter cinterfaces.ArtifactGetter
+
+	// RPCClient is the RPC Client that should be used by hooks to make RPC
+	// calls to the servers.
+	RPCClient RPCer
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -374,6 +391,7 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		shutdownDelayCancelFn:  config.ShutdownDelayCancelFn,
 		serviceRegWrapper:      config.ServiceRegWrapper,
 		getter:                 config.Getter,
+		rpcClient:              config.RPCClient,
 	}
 
 	// Create the logger based on the allocation ID
@@ -76,6 +76,22 @@ func (tr *TaskRunner) setVaultToken(token string) {
 	tr.envBuilder.SetVaultToken(token, ns, tr.task.Vault.Env)
 }
 
+func (tr *TaskRunner) getNomadToken() string {
+	tr.nomadTokenLock.Lock()
+	defer tr.nomadTokenLock.Unlock()
+	return tr.nomadToken
+}
+
+// setNomadToken updates the workload identity token on the task runner as
+// well as in the task's environment.
+func (tr *TaskRunner) setNomadToken(token string) {
+	tr.nomadTokenLock.Lock()
+	defer tr.nomadTokenLock.Unlock()
+
+	tr.nomadToken = token
+	tr.envBuilder.SetWorkloadToken(token)
+}
+
 // getDriverHandle returns a driver handle.
 func (tr *TaskRunner) getDriverHandle() *DriverHandle {
 	tr.handleLock.Lock()
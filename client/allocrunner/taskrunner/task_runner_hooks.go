@@ -61,6 +61,7 @@ func (tr *TaskRunner) initHooks() {
 	tr.runnerHooks = []interfaces.TaskHook{
 		newValidateHook(tr.clientConfig, hookLogger),
 		newTaskDirHook(tr, hookLogger),
+		newIdentityHook(tr, hookLogger),
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
 		newVolumeHook(tr, hookLogger),
@@ -114,6 +115,9 @@ func (tr *TaskRunner) initHooks() {
 			envBuilder:      tr.envBuilder,
 			consulNamespace: consulNamespace,
 			nomadNamespace:  tr.alloc.Job.Namespace,
+			alloc:           tr.alloc,
+			taskName:        task.Name,
+			rpcClient:       tr.rpcClient,
 		}))
 	}
 
@@ -243,6 +247,7 @@ func (tr *TaskRunner) prestart() error {
 		}
 
 		req.VaultToken = tr.getVaultToken()
+		req.NomadToken = tr.getNomadToken()
 
 		// Time the prestart hook
 		var start time.Time
@@ -480,6 +485,7 @@ func (tr *TaskRunner) updateHooks() {
 		// Build the request
 		req := interfaces.TaskUpdateRequest{
 			VaultToken: tr.getVaultToken(),
+			NomadToken: tr.getNomadToken(),
 			Alloc:      alloc,
 			TaskEnv:    tr.envBuilder.Build(),
 		}
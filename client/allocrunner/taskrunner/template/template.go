@@ -105,6 +105,15 @@ type TaskTemplateManagerConfig struct {
 
 	// NomadNamespace is the Nomad namespace for the task
 	NomadNamespace string
+
+	// NomadToken is the workload identity of the task, which is used to
+	// authenticate Nomad template function calls if set.
+	NomadToken string
+
+	// NomadVariables are the items of the variables accessible to the task,
+	// keyed by "<path>.<key>". They are exposed to templates via the env
+	// function.
+	NomadVariables map[string]string
 }
 
 // Validate validates the configuration.
@@ -538,6 +547,12 @@ func templateRunner(config *TaskTemplateManagerConfig) (
 	// available.
 	runner.Env = maskProcessEnv(config.EnvBuilder.Build().All())
 
+	// Expose the Nomad variables of the task. Their keys are prefixed with
+	// the variable path, so are unlikely to collide with the task env.
+	for k, v := range config.NomadVariables {
+		runner.Env[k] = v
+	}
+
 	// Build the lookup
 	idMap := runner.TemplateConfigMapping()
 	lookup := make(map[string][]*structs.Template, len(idMap))
@@ -814,8 +829,14 @@ func newRunnerConfig(config *TaskTemplateManagerConfig,
 	conf.Nomad.Namespace = &config.NomadNamespace
 	conf.Nomad.Transport.CustomDialer = cc.TemplateDialer
 
-	// Use the Node's SecretID to authenticate Nomad template function calls.
-	conf.Nomad.Token = &cc.Node.SecretID
+	// Use the task's workload identity to authenticate Nomad template
+	// function calls, falling back to the Node's SecretID for allocations
+	// placed without one.
+	if config.NomadToken != "" {
+		conf.Nomad.Token = &config.NomadToken
+	} else {
+		conf.Nomad.Token = &cc.Node.SecretID
+	}
 
 	conf.Finalize()
 	return conf, nil
@@ -42,6 +42,14 @@ type templateHookConfig struct {
 
 	// nomadNamespace is the job's Nomad namespace
 	nomadNamespace string
+
+	// alloc and taskName identify the task, and therefore the variables it
+	// is implicitly permitted to read.
+	alloc    *structs.Allocation
+	taskName string
+
+	// rpcClient is used to read the variables of the task from the servers.
+	rpcClient RPCer
 }
 
 type templateHook struct {
@@ -63,8 +71,15 @@ type templateHook struct {
 	// vaultNamespace is the current Vault namespace
 	vaultNamespace string
 
+	// nomadToken is the workload identity of the task
+	nomadToken string
+
 	// taskDir is the task directory
 	taskDir string
+
+	// nomadVariables are the items of the variables implicitly accessible to
+	// the task, keyed by "<path>.<key>".
+	nomadVariables map[string]string
 }
 
 func newTemplateHook(config *templateHookConfig) *templateHook {
@@ -91,12 +106,21 @@ func (h *templateHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	// Store the current Vault token and the task directory
 	h.taskDir = req.TaskDir.Dir
 	h.vaultToken = req.VaultToken
+	h.nomadToken = req.NomadToken
 
 	// Set vault namespace if specified
 	if req.Task.Vault != nil {
 		h.vaultNamespace = req.Task.Vault.Namespace
 	}
 
+	// Fetch the variables of the task, so they can be rendered via the
+	// template env function.
+	nomadVariables, err := h.readNomadVariables()
+	if err != nil {
+		return err
+	}
+	h.nomadVariables = nomadVariables
+
 	unblockCh, err := h.newManager()
 	if err != nil {
 		return err
@@ -126,6 +150,8 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		EnvBuilder:           h.config.envBuilder,
 		MaxTemplateEventRate: template.DefaultMaxTemplateEventRate,
 		NomadNamespace:       h.config.nomadNamespace,
+		NomadToken:           h.nomadToken,
+		NomadVariables:       h.nomadVariables,
 	})
 	if err != nil {
 		h.logger.Error("failed to create template manager", "error", err)
@@ -136,6 +162,48 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 	return unblock, nil
 }
 
+// readNomadVariables reads the variables which are implicitly accessible to
+// the task, at the paths of its job, group, and task. The items are keyed by
+// "<path>.<key>", so a template can render them via the env function, such as
+// {{ env "nomad/jobs/example/web.password" }}. Variables are read once when the
+// task starts, using the workload identity of the task if it has one.
+func (h *templateHook) readNomadVariables() (map[string]string, error) {
+	if h.config.rpcClient == nil || h.config.alloc == nil {
+		return nil, nil
+	}
+
+	alloc := h.config.alloc
+	out := make(map[string]string)
+
+	authToken := h.nomadToken
+	if authToken == "" {
+		authToken = h.config.clientConfig.Node.SecretID
+	}
+
+	for _, path := range structs.VariablesTaskPaths(alloc.JobID, alloc.TaskGroup, h.config.taskName) {
+		req := structs.VariablesReadRequest{
+			Path: path,
+			QueryOptions: structs.QueryOptions{
+				Region:     h.config.clientConfig.Region,
+				Namespace:  alloc.Namespace,
+				AuthToken:  authToken,
+				AllowStale: true,
+			},
+		}
+		var resp structs.VariablesReadResponse
+		if err := h.config.rpcClient.RPC(structs.VariablesReadRPCMethod, &req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to read variable %q: %v", path, err)
+		}
+		if resp.Data == nil {
+			continue
+		}
+		for k, v := range resp.Data.Items {
+			out[path+"."+k] = v
+		}
+	}
+	return out, nil
+}
+
 func (h *templateHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
 	h.managerLock.Lock()
 	defer h.managerLock.Unlock()
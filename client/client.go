@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -30,6 +31,7 @@ import (
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
 	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/client/hostvolumemanager"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/lib/cgutil"
 	"github.com/hashicorp/nomad/client/pluginmanager"
@@ -290,6 +292,10 @@ type Client struct {
 	// csimanager is responsible for managing csi plugins.
 	csimanager csimanager.Manager
 
+	// hostVolumeManager is responsible for creating and deleting dynamic
+	// host volumes.
+	hostVolumeManager *hostvolumemanager.HostVolumeManager
+
 	// devicemanger is responsible for managing device plugins.
 	devicemanager devicemanager.Manager
 
@@ -324,6 +330,10 @@ type Client struct {
 
 	// getter is an interface for retrieving artifacts.
 	getter cinterfaces.ArtifactGetter
+
+	// artifactCache caches the artifacts downloaded by the getter, or is nil
+	// if the cache is disabled.
+	artifactCache *getter.Cache
 }
 
 var (
@@ -382,7 +392,6 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		serversContactedCh:   make(chan struct{}),
 		serversContactedOnce: sync.Once{},
 		cpusetManager:        cgutil.CreateCPUSetManager(cfg.CgroupParent, logger),
-		getter:               getter.NewGetter(cfg.Artifact),
 		EnterpriseClient:     newEnterpriseClient(logger),
 	}
 
@@ -403,6 +412,25 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		return nil, fmt.Errorf("failed to initialize client: %v", err)
 	}
 
+	// initialize the artifact getter (needs to happen after init). The cache
+	// lives on the filesystem of the alloc dir, whose disk usage triggers its
+	// eviction by the garbage collector.
+	if cfg.Artifact != nil && cfg.Artifact.CacheMaxBytes > 0 {
+		cache, err := getter.NewCache(c.logger, filepath.Join(c.config.AllocDir, "artifact_cache"), cfg.Artifact.CacheMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize artifact cache: %v", err)
+		}
+		c.artifactCache = cache
+	}
+	artifactConfig := cfg.Artifact
+	if artifactConfig != nil {
+		// Sandboxed downloads are constrained by a cgroup under the client's
+		// cgroup parent
+		artifactConfig = artifactConfig.Copy()
+		artifactConfig.CgroupParent = cfg.CgroupParent
+	}
+	c.getter = getter.NewGetter(c.logger, artifactConfig, c.artifactCache)
+
 	// initialize the dynamic registry (needs to happen after init)
 	c.dynamicRegistry =
 		dynamicplugins.NewRegistry(c.stateDB, map[string]dynamicplugins.PluginDispenser{
@@ -427,6 +455,12 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		return nil, fmt.Errorf("node setup failed: %v", err)
 	}
 
+	// Setup the host volume manager (needs to happen after the node is setup
+	// so the dynamic host volumes are restored into it)
+	if err := c.setupHostVolumeManager(); err != nil {
+		return nil, fmt.Errorf("host volume manager setup failed: %v", err)
+	}
+
 	// Store the config copy before restoring state but after it has been
 	// initialized.
 	c.configLock.Lock()
@@ -520,6 +554,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		ReservedDiskMB:      cfg.Node.Reserved.DiskMB,
 	}
 	c.garbageCollector = NewAllocGarbageCollector(c.logger, statsCollector, c, gcConfig)
+	if c.artifactCache != nil {
+		c.garbageCollector.artifactCache = c.artifactCache
+	}
 	go c.garbageCollector.Run()
 
 	// Set the preconfigured list of static servers
@@ -1401,6 +1438,7 @@ func (c *Client) setupNode() error {
 
 	node.ID = id
 	node.SecretID = secretID
+	node.AgentStartID = uuid.Generate()
 	if node.Attributes == nil {
 		node.Attributes = make(map[string]string)
 	}
@@ -1482,6 +1520,53 @@ func (c *Client) setupNode() error {
 	return nil
 }
 
+// setupHostVolumeManager creates the host volume manager, and adds the
+// dynamic host volumes created before a restart and the host volume plugin
+// attributes to the node.
+func (c *Client) setupHostVolumeManager() error {
+	volumesDir := c.config.HostVolumesDir
+	if volumesDir == "" {
+		volumesDir = filepath.Join(c.config.StateDir, "host_volumes")
+	}
+
+	c.hostVolumeManager = hostvolumemanager.NewHostVolumeManager(c.logger, &hostvolumemanager.Config{
+		PluginDir:      c.config.HostVolumePluginDir,
+		SharedMountDir: volumesDir,
+		StateMgr:       c.stateDB,
+		UpdateNodeVols: c.updateNodeFromHostVolume,
+	})
+
+	vols, err := c.hostVolumeManager.Restore()
+	if err != nil {
+		return fmt.Errorf("failed to restore host volumes: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hostVolumeRequestTimeout)
+	defer cancel()
+	attrs := c.hostVolumeManager.Fingerprint(ctx)
+
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	node := c.config.Node
+	for name, vol := range vols {
+		if _, ok := node.HostVolumes[name]; ok {
+			c.logger.Warn("dynamic host volume has the same name as a configured host volume",
+				"name", name, "volume_id", vol.ID)
+			continue
+		}
+		if node.HostVolumes == nil {
+			node.HostVolumes = make(map[string]*structs.ClientHostVolumeConfig)
+		}
+		node.HostVolumes[name] = vol
+	}
+	for k, v := range attrs {
+		node.Attributes[k] = v
+	}
+
+	return nil
+}
+
 // updateNodeFromFingerprint updates the node with the result of
 // fingerprinting the node from the diff that was created
 func (c *Client) updateNodeFromFingerprint(response *fingerprint.FingerprintResponse) *structs.Node {
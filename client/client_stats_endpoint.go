@@ -28,3 +28,23 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	reply.HostStats = clientStats.LatestHostStats()
 	return nil
 }
+
+// ArtifactCache is used to retrieve the content of the Client's artifact
+// cache.
+func (s *ClientStats) ArtifactCache(args *nstructs.NodeSpecificRequest, reply *structs.ArtifactCacheResponse) error {
+	defer metrics.MeasureSince([]string{"client", "client_stats", "artifact_cache"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := s.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if s.c.artifactCache == nil {
+		reply.Cache = &structs.ArtifactCacheStats{Entries: []*structs.ArtifactCacheEntry{}}
+		return nil
+	}
+	reply.Cache = s.c.artifactCache.Stats()
+	return nil
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/nomad/acl"
@@ -9,6 +10,7 @@ import (
 	"github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/mock"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -87,3 +89,31 @@ func TestClientStats_Stats_ACL(t *testing.T) {
 		require.NotNil(resp.HostStats)
 	}
 }
+
+func TestClientStats_ArtifactCache(t *testing.T) {
+	ci.Parallel(t)
+
+	// The cache is disabled by default in tests
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	req := &nstructs.NodeSpecificRequest{}
+	var resp structs.ArtifactCacheResponse
+	require.NoError(t, client.ClientRPC("ClientStats.ArtifactCache", &req, &resp))
+	require.NotNil(t, resp.Cache)
+	require.Zero(t, resp.Cache.MaxSize)
+	require.Empty(t, resp.Cache.Entries)
+
+	artifactConfig, err := config.ArtifactConfigFromAgent(sconfig.DefaultArtifactConfig())
+	require.NoError(t, err)
+	client2, cleanup2 := TestClient(t, func(c *config.Config) {
+		c.Artifact = artifactConfig
+	})
+	defer cleanup2()
+
+	var resp2 structs.ArtifactCacheResponse
+	require.NoError(t, client2.ClientRPC("ClientStats.ArtifactCache", &req, &resp2))
+	require.Equal(t, int64(10_000_000_000), resp2.Cache.MaxSize)
+	require.Empty(t, resp2.Cache.Entries)
+	require.DirExists(t, filepath.Join(client2.config.AllocDir, "artifact_cache"))
+}
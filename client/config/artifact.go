@@ -18,6 +18,29 @@ type ArtifactConfig struct {
 	GitTimeout time.Duration
 	HgTimeout  time.Duration
 	S3Timeout  time.Duration
+
+	// CacheMaxBytes is the maximum size of the artifact cache, which is
+	// disabled if 0.
+	CacheMaxBytes int64
+
+	// DisableSandbox downloads artifacts in the client process instead of an
+	// isolated subprocess, and DisableFilesystemIsolation disables the
+	// Landlock restrictions of the subprocess.
+	DisableSandbox             bool
+	DisableFilesystemIsolation bool
+
+	// SandboxUser is the user the subprocess runs as, if set.
+	SandboxUser string
+
+	// SandboxMemoryMaxBytes, SandboxCPUMax and SandboxTimeout are the
+	// resource limits of the subprocess, which are disabled if 0.
+	SandboxMemoryMaxBytes int64
+	SandboxCPUMax         float64
+	SandboxTimeout        time.Duration
+
+	// CgroupParent is the cgroup the cgroups of the subprocesses are created
+	// under. It is set by the client from its own configuration.
+	CgroupParent string
 }
 
 // ArtifactConfigFromAgent creates a new internal readonly copy of the client
@@ -61,6 +84,42 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 	}
 	newConfig.S3Timeout = t
 
+	if c.CacheMaxSize != nil {
+		s, err = humanize.ParseBytes(*c.CacheMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing CacheMaxSize: %w", err)
+		}
+		newConfig.CacheMaxBytes = int64(s)
+	}
+
+	if c.DisableSandbox != nil {
+		newConfig.DisableSandbox = *c.DisableSandbox
+	}
+	if c.DisableFilesystemIsolation != nil {
+		newConfig.DisableFilesystemIsolation = *c.DisableFilesystemIsolation
+	}
+	if c.SandboxUser != nil {
+		newConfig.SandboxUser = *c.SandboxUser
+	}
+
+	if c.SandboxMemoryMax != nil {
+		s, err = humanize.ParseBytes(*c.SandboxMemoryMax)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing SandboxMemoryMax: %w", err)
+		}
+		newConfig.SandboxMemoryMaxBytes = int64(s)
+	}
+	if c.SandboxCPUMax != nil {
+		newConfig.SandboxCPUMax = *c.SandboxCPUMax
+	}
+	if c.SandboxTimeout != nil {
+		t, err = time.ParseDuration(*c.SandboxTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing SandboxTimeout: %w", err)
+		}
+		newConfig.SandboxTimeout = t
+	}
+
 	return newConfig, nil
 }
 
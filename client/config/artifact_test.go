@@ -29,6 +29,11 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 				GitTimeout:      30 * time.Minute,
 				HgTimeout:       30 * time.Minute,
 				S3Timeout:       30 * time.Minute,
+				CacheMaxBytes:   10_000_000_000,
+
+				SandboxMemoryMaxBytes: 2_000_000_000,
+				SandboxCPUMax:         1,
+				SandboxTimeout:        time.Hour,
 			},
 		},
 		{
@@ -103,6 +108,32 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 			},
 			expectedError: "error parsing S3Timeout",
 		},
+		{
+			name: "invalid cache max size",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout: helper.StringToPtr("30m"),
+				HTTPMaxSize:     helper.StringToPtr("100GB"),
+				GCSTimeout:      helper.StringToPtr("30m"),
+				GitTimeout:      helper.StringToPtr("30m"),
+				HgTimeout:       helper.StringToPtr("30m"),
+				S3Timeout:       helper.StringToPtr("30m"),
+				CacheMaxSize:    helper.StringToPtr("invalid"),
+			},
+			expectedError: "error parsing CacheMaxSize",
+		},
+		{
+			name: "invalid sandbox timeout",
+			config: &config.ArtifactConfig{
+				HTTPReadTimeout: helper.StringToPtr("30m"),
+				HTTPMaxSize:     helper.StringToPtr("100GB"),
+				GCSTimeout:      helper.StringToPtr("30m"),
+				GitTimeout:      helper.StringToPtr("30m"),
+				HgTimeout:       helper.StringToPtr("30m"),
+				S3Timeout:       helper.StringToPtr("30m"),
+				SandboxTimeout:  helper.StringToPtr("invalid"),
+			},
+			expectedError: "error parsing SandboxTimeout",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -263,6 +263,13 @@ type Config struct {
 	// HostVolumes is a map of the configured host volumes by name.
 	HostVolumes map[string]*structs.ClientHostVolumeConfig
 
+	// HostVolumesDir is where the built-in host volume plugin creates
+	// dynamic host volumes
+	HostVolumesDir string
+
+	// HostVolumePluginDir is where external host volume plugins are found
+	HostVolumePluginDir string
+
 	// HostNetworks is a map of the conigured host networks by name.
 	HostNetworks map[string]*structs.ClientHostNetworkConfig
 
@@ -524,6 +524,91 @@ func TestCSIController_DeleteVolume(t *testing.T) {
 	}
 }
 
+func TestCSIController_ExpandVolume(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name             string
+		ClientSetupFunc  func(*fake.Client)
+		Request          *structs.ClientCSIControllerExpandVolumeRequest
+		ExpectedErr      error
+		ExpectedResponse *structs.ClientCSIControllerExpandVolumeResponse
+	}{
+		{
+			Name: "returns plugin not found errors",
+			Request: &structs.ClientCSIControllerExpandVolumeRequest{
+				CSIControllerQuery: structs.CSIControllerQuery{
+					PluginID: "some-garbage",
+				},
+			},
+			ExpectedErr: errors.New("CSI.ControllerExpandVolume: CSI client error (retryable): plugin some-garbage for type csi-controller not found"),
+		},
+		{
+			Name: "returns transitive errors",
+			ClientSetupFunc: func(fc *fake.Client) {
+				fc.NextControllerExpandVolumeErr = errors.New("internal plugin error")
+			},
+			Request: &structs.ClientCSIControllerExpandVolumeRequest{
+				CSIControllerQuery: structs.CSIControllerQuery{
+					PluginID: fakePlugin.Name,
+				},
+				ExternalVolumeID: "1234-4321-1234-4321",
+				CapacityMin:      100,
+			},
+			ExpectedErr: errors.New("CSI.ControllerExpandVolume: internal plugin error"),
+		},
+		{
+			Name: "returns expanded capacity",
+			ClientSetupFunc: func(fc *fake.Client) {
+				fc.NextControllerExpandVolumeResponse = &csi.ControllerExpandVolumeResponse{
+					CapacityBytes:         100,
+					NodeExpansionRequired: true,
+				}
+			},
+			Request: &structs.ClientCSIControllerExpandVolumeRequest{
+				CSIControllerQuery: structs.CSIControllerQuery{
+					PluginID: fakePlugin.Name,
+				},
+				ExternalVolumeID: "1234-4321-1234-4321",
+				CapacityMin:      100,
+			},
+			ExpectedResponse: &structs.ClientCSIControllerExpandVolumeResponse{
+				CapacityBytes:         100,
+				NodeExpansionRequired: true,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			require := require.New(t)
+			client, cleanup := TestClient(t, nil)
+			defer cleanup()
+
+			fakeClient := &fake.Client{}
+			if tc.ClientSetupFunc != nil {
+				tc.ClientSetupFunc(fakeClient)
+			}
+
+			dispenserFunc := func(*dynamicplugins.PluginInfo) (interface{}, error) {
+				return fakeClient, nil
+			}
+			client.dynamicRegistry.StubDispenserForType(
+				dynamicplugins.PluginTypeCSIController, dispenserFunc)
+
+			err := client.dynamicRegistry.RegisterPlugin(fakePlugin)
+			require.Nil(err)
+
+			var resp structs.ClientCSIControllerExpandVolumeResponse
+			err = client.ClientRPC("CSI.ControllerExpandVolume", tc.Request, &resp)
+			require.Equal(tc.ExpectedErr, err)
+			if tc.ExpectedResponse != nil {
+				require.Equal(tc.ExpectedResponse, &resp)
+			}
+		})
+	}
+}
+
 func TestCSIController_ListVolumes(t *testing.T) {
 	ci.Parallel(t)
 
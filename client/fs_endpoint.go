@@ -253,7 +253,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 
 	// Start streaming
 	go func() {
-		if err := f.streamFile(ctx, req.Offset, req.Path, req.Limit, fs, framer, nil, cancelAfterFirstEof); err != nil {
+		if err := f.streamFile(ctx, req.Offset, req.Path, req.Limit, fs, framer, nil, cancelAfterFirstEof, nil); err != nil {
 			select {
 			case errCh <- err:
 			case <-ctx.Done():
@@ -387,6 +387,11 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		handleStreamResultError(invalidOrigin, helper.Int64ToPtr(400), encoder)
 		return
 	}
+	search, err := newLogSearch(&req)
+	if err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
 
 	fs, err := f.c.GetAllocFS(req.AllocID)
 	if err != nil {
@@ -437,7 +442,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	// Start streaming
 	go func() {
 		if err := f.logsImpl(ctx, req.Follow, req.PlainText,
-			req.Offset, req.Origin, req.Task, req.LogType, fs, frames); err != nil {
+			req.Offset, req.Origin, req.Task, req.LogType, search, fs, frames); err != nil {
 			select {
 			case errCh <- err:
 			case <-ctx.Done():
@@ -520,9 +525,10 @@ OUTER:
 
 // logsImpl is used to stream the logs of a the given task. Output is sent on
 // the passed frames channel and the method will return on EOF if follow is not
-// true otherwise when the context is cancelled or on an error.
+// true otherwise when the context is cancelled or on an error. If search is
+// not nil, only the log lines matching it are streamed.
 func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset int64,
-	origin, task, logType string,
+	origin, task, logType string, search *logSearch,
 	fs allocdir.AllocDirFS, frames chan<- *sframer.StreamFrame) error {
 
 	// Create the framer
@@ -545,6 +551,19 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		return invalidOrigin
 	}
 
+	// Start at the lines written at the start of the searched time range
+	if search != nil && !search.since.IsZero() {
+		entries, err := fs.List(logPath)
+		if err != nil {
+			return fmt.Errorf("failed to list entries: %v", err)
+		}
+
+		nextIdx, offset, err = search.start(fs, logPath, entries, task, logType)
+		if err != nil {
+			return err
+		}
+	}
+
 	for {
 		// Logic for picking next file is:
 		// 1) List log files
@@ -587,8 +606,13 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			eofCancelCh = blockUntilNextLog(ctx, fs, logPath, task, logType, idx+1)
 		}
 
+		var filter *logFilter
+		if search != nil {
+			filter = search.newFilter(readLogIndex(fs, logPath, task, logType, idx), openOffset)
+		}
+
 		p := filepath.Join(logPath, logEntry.Name)
-		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof)
+		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof, filter)
 
 		// Check if the context is cancelled
 		select {
@@ -616,6 +640,11 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 			return nil
 		}
 
+		// The end of the searched time range was reached
+		if filter != nil && filter.done {
+			return nil
+		}
+
 		// defensively check to make sure StreamFramer hasn't stopped
 		// running to avoid tight loops with goroutine leaks as in
 		// #3342
@@ -634,10 +663,12 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 // streamFile is the internal method to stream the content of a file. If limit
 // is greater than zero, the stream will end once that many bytes have been
 // read. If eofCancelCh is triggered while at EOF, read one more frame and
-// cancel the stream on the next EOF. If the connection is broken an EPIPE
-// error is returned.
+// cancel the stream on the next EOF. If filter is not nil, only the lines it
+// keeps are streamed and the stream ends once it is done. If the connection
+// is broken an EPIPE error is returned.
 func (f *FileSystem) streamFile(ctx context.Context, offset int64, path string, limit int64,
-	fs allocdir.AllocDirFS, framer *sframer.StreamFramer, eofCancelCh chan error, cancelAfterFirstEof bool) error {
+	fs allocdir.AllocDirFS, framer *sframer.StreamFramer, eofCancelCh chan error, cancelAfterFirstEof bool,
+	filter *logFilter) error {
 
 	// Get the reader
 	file, err := fs.ReadAt(path, offset)
@@ -688,8 +719,14 @@ OUTER:
 
 		// Send the frame
 		if n != 0 || lastEvent != "" {
-			if err := framer.Send(path, lastEvent, data[:n], offset); err != nil {
-				return parseFramerErr(err)
+			payload := data[:n]
+			if filter != nil {
+				payload = filter.Write(payload)
+			}
+			if len(payload) != 0 || lastEvent != "" {
+				if err := framer.Send(path, lastEvent, payload, offset); err != nil {
+					return parseFramerErr(err)
+				}
 			}
 		}
 
@@ -698,6 +735,11 @@ OUTER:
 			lastEvent = ""
 		}
 
+		// Stop once the filter has found the end of the searched time range
+		if filter != nil && filter.done {
+			return nil
+		}
+
 		// Just keep reading since we aren't at the end of the file so we can
 		// avoid setting up a file event watcher.
 		if readErr == nil {
@@ -709,6 +751,11 @@ OUTER:
 		// or we received an event from the eofCancelCh channel
 		// and last read was executed
 		if cancelReceived {
+			if filter != nil {
+				if last := filter.Flush(); len(last) != 0 {
+					return parseFramerErr(framer.Send(path, "", last, offset))
+				}
+			}
 			return nil
 		}
 
@@ -734,6 +781,9 @@ OUTER:
 
 				// Get a new reader at offset zero
 				offset = 0
+				if filter != nil {
+					filter.Reset()
+				}
 				var err error
 				file, err = fs.ReadAt(path, offset)
 				if err != nil {
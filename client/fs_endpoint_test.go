@@ -1571,7 +1571,7 @@ func TestFS_streamFile_NoFile(t *testing.T) {
 	defer framer.Destroy()
 
 	err := c.endpoints.FileSystem.streamFile(
-		context.Background(), 0, "foo", 0, ad, framer, nil, false)
+		context.Background(), 0, "foo", 0, ad, framer, nil, false, nil)
 	require.Error(t, err)
 	if runtime.GOOS == "windows" {
 		require.Contains(t, err.Error(), "cannot find the file")
@@ -1632,7 +1632,7 @@ func TestFS_streamFile_Modify(t *testing.T) {
 	// Start streaming
 	go func() {
 		if err := c.endpoints.FileSystem.streamFile(
-			context.Background(), 0, streamFile, 0, ad, framer, nil, false); err != nil {
+			context.Background(), 0, streamFile, 0, ad, framer, nil, false, nil); err != nil {
 			t.Fatalf("stream() failed: %v", err)
 		}
 	}()
@@ -1708,7 +1708,7 @@ func TestFS_streamFile_Truncate(t *testing.T) {
 	// Start streaming
 	go func() {
 		if err := c.endpoints.FileSystem.streamFile(
-			context.Background(), 0, streamFile, 0, ad, framer, nil, false); err != nil {
+			context.Background(), 0, streamFile, 0, ad, framer, nil, false, nil); err != nil {
 			t.Fatalf("stream() failed: %v", err)
 		}
 	}()
@@ -1812,7 +1812,7 @@ func TestFS_streamImpl_Delete(t *testing.T) {
 	// Start streaming
 	go func() {
 		if err := c.endpoints.FileSystem.streamFile(
-			context.Background(), 0, streamFile, 0, ad, framer, nil, false); err != nil {
+			context.Background(), 0, streamFile, 0, ad, framer, nil, false, nil); err != nil {
 			t.Fatalf("stream() failed: %v", err)
 		}
 	}()
@@ -1889,7 +1889,7 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 
 	if err := c.endpoints.FileSystem.logsImpl(
 		ctx, false, false, 0,
-		OriginStart, task, logType, ad, frames); err != nil {
+		OriginStart, task, logType, nil, ad, frames); err != nil {
 		t.Fatalf("logsImpl failed: %v", err)
 	}
 
@@ -1979,7 +1979,7 @@ func TestFS_logsImpl_Follow(t *testing.T) {
 	// Start streaming logs
 	go c.endpoints.FileSystem.logsImpl(
 		context.Background(), true, false, 0,
-		OriginStart, task, logType, ad, frames)
+		OriginStart, task, logType, nil, ad, frames)
 
 	select {
 	case <-firstResultCh:
@@ -2001,3 +2001,118 @@ func TestFS_logsImpl_Follow(t *testing.T) {
 		t.Fatalf("did not receive data: got %q", string(received))
 	}
 }
+
+func TestFS_logsImpl_Search(t *testing.T) {
+	ci.Parallel(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	require.NoError(t, ad.Build())
+	defer ad.Destroy()
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	require.NoError(t, os.MkdirAll(logDir, 0777))
+
+	// Create a series of log files along with their index, the last one
+	// holding JSON lines
+	task := "foo"
+	logType := "stdout"
+	base := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	entry := func(hours int, offset int64) string {
+		return fmt.Sprintf("%d %d\n", at(hours).UnixNano(), offset)
+	}
+
+	files := []struct {
+		content string
+		index   string
+	}{
+		{
+			content: "a old\nb old\n",
+			index:   entry(0, 0),
+		},
+		{
+			content: "c mid\nd mid\n",
+			index:   entry(1, 0) + entry(2, 6),
+		},
+		{
+			content: fmt.Sprintf(`{"time":%q,"msg":"e new"}`+"\n"+`{"time":%q,"msg":"f new"}`,
+				at(3).Format(time.RFC3339), at(4).Format(time.RFC3339)),
+			index: entry(3, 0),
+		},
+	}
+	for i, f := range files {
+		logFile := filepath.Join(logDir, fmt.Sprintf("%s.%s.%d", task, logType, i))
+		require.NoError(t, ioutil.WriteFile(logFile, []byte(f.content), 0777))
+
+		indexFile := filepath.Join(logDir, fmt.Sprintf(".%s.%s.%d.idx", task, logType, i))
+		require.NoError(t, ioutil.WriteFile(indexFile, []byte(f.index), 0777))
+	}
+	newLines := strings.SplitAfter(files[2].content, "\n")
+
+	search := func(since, until time.Time, grep string) string {
+		s, err := newLogSearch(&cstructs.FsLogsRequest{Since: since, Until: until, Grep: grep})
+		require.NoError(t, err)
+
+		frames := make(chan *sframer.StreamFrame, 32)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- c.endpoints.FileSystem.logsImpl(
+				context.Background(), false, false, 0,
+				OriginStart, task, logType, s, ad, frames)
+		}()
+
+		var received []byte
+		for frame := range frames {
+			received = append(received, frame.Data...)
+		}
+		require.NoError(t, <-errCh)
+		return string(received)
+	}
+
+	cases := []struct {
+		name     string
+		since    time.Time
+		until    time.Time
+		grep     string
+		expected string
+	}{
+		{
+			name:     "since",
+			since:    at(2),
+			expected: "d mid\n" + files[2].content,
+		},
+		{
+			name:     "until",
+			until:    at(1),
+			expected: "a old\nb old\nc mid\n",
+		},
+		{
+			name:     "time range",
+			since:    at(2),
+			until:    at(3),
+			expected: "d mid\n" + newLines[0],
+		},
+		{
+			name:     "grep",
+			grep:     `^[a-c] `,
+			expected: "a old\nb old\nc mid\n",
+		},
+		{
+			name:     "since and grep",
+			since:    at(3).Add(time.Minute),
+			grep:     "new",
+			expected: newLines[1],
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, search(tc.since, tc.until, tc.grep))
+		})
+	}
+}
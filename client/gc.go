@@ -34,6 +34,15 @@ type AllocCounter interface {
 	NumAllocs() int
 }
 
+// ArtifactCache is used by AllocGarbageCollector to free the disk space used by
+// cached artifacts before destroying allocations and is generally fulfilled by
+// the artifact getter's cache.
+type ArtifactCache interface {
+	// EvictLRU evicts the least recently used artifact and returns whether
+	// an artifact was evicted.
+	EvictLRU() bool
+}
+
 // AllocGarbageCollector garbage collects terminated allocations on a node
 type AllocGarbageCollector struct {
 	config *GCConfig
@@ -47,6 +56,10 @@ type AllocGarbageCollector struct {
 	// allocCounter return the number of un-GC'd allocs on this node
 	allocCounter AllocCounter
 
+	// artifactCache is evicted from before allocations are destroyed when
+	// disk usage is over the thresholds, or is nil if artifacts aren't cached
+	artifactCache ArtifactCache
+
 	// destroyCh is a semaphore for rate limiting concurrent garbage
 	// collections
 	destroyCh chan struct{}
@@ -133,14 +146,17 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		logf := a.logger.Warn
 
 		liveAllocs := a.allocCounter.NumAllocs()
+		diskUsage := false
 
 		switch {
 		case diskStats.UsedPercent > a.config.DiskUsageThreshold:
 			reason = fmt.Sprintf("disk usage of %.0f is over gc threshold of %.0f",
 				diskStats.UsedPercent, a.config.DiskUsageThreshold)
+			diskUsage = true
 		case diskStats.InodesUsedPercent > a.config.InodeUsageThreshold:
 			reason = fmt.Sprintf("inode usage of %.0f is over gc threshold of %.0f",
 				diskStats.InodesUsedPercent, a.config.InodeUsageThreshold)
+			diskUsage = true
 		case liveAllocs > a.config.MaxAllocs:
 			// if we're unable to gc, don't WARN until at least 2x over limit
 			if liveAllocs < (a.config.MaxAllocs * 2) {
@@ -154,6 +170,13 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 			break
 		}
 
+		// Evict cached artifacts before destroying allocations, as they can
+		// be downloaded again
+		if diskUsage && a.artifactCache != nil && a.artifactCache.EvictLRU() {
+			a.logger.Info("evicted cached artifact", "reason", reason)
+			continue
+		}
+
 		// Collect an allocation
 		gcAlloc := a.allocRunners.Pop()
 		if gcAlloc == nil {
@@ -123,6 +123,19 @@ func (m *MockStatsCollector) Stats() *stats.HostStats {
 	}
 }
 
+// MockArtifactCache is an artifact cache holding a number of artifacts.
+type MockArtifactCache struct {
+	artifacts int
+}
+
+func (m *MockArtifactCache) EvictLRU() bool {
+	if m.artifacts == 0 {
+		return false
+	}
+	m.artifacts--
+	return true
+}
+
 func TestAllocGarbageCollector_MarkForCollection(t *testing.T) {
 	ci.Parallel(t)
 
@@ -585,3 +598,41 @@ func TestAllocGarbageCollector_UsedPercentThreshold(t *testing.T) {
 		t.Fatalf("gcAlloc: %v", gcAlloc)
 	}
 }
+
+func TestAllocGarbageCollector_UsedPercentThreshold_ArtifactCache(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	statsCollector := &MockStatsCollector{}
+	conf := gcConfig()
+	conf.ReservedDiskMB = 20
+	gc := NewAllocGarbageCollector(logger, statsCollector, &MockAllocCounter{}, conf)
+	cache := &MockArtifactCache{artifacts: 1}
+	gc.artifactCache = cache
+
+	ar1, cleanup1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup1()
+	ar2, cleanup2 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc())
+	defer cleanup2()
+
+	go ar1.Run()
+	go ar2.Run()
+
+	gc.MarkForCollection(ar1.Alloc().ID, ar1)
+	gc.MarkForCollection(ar2.Alloc().ID, ar2)
+
+	// Exit the alloc runners
+	exitAllocRunner(ar1, ar2)
+
+	statsCollector.availableValues = []uint64{1000, 900, 800}
+	statsCollector.usedPercents = []float64{85, 85, 60}
+	statsCollector.inodePercents = []float64{50, 50, 30}
+
+	require.NoError(t, gc.keepUsageBelowThreshold())
+
+	// The cached artifact is evicted first, and then only one of the alloc
+	// runners is GC-ed since used percent is below threshold the third time
+	require.Zero(t, cache.artifacts)
+	require.NotNil(t, gc.allocRunners.Pop())
+	require.Nil(t, gc.allocRunners.Pop())
+}
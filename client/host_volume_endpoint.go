@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/client/structs"
+)
+
+// HostVolume endpoint is used by the servers to create and delete the dynamic
+// host volumes of a client.
+type HostVolume struct {
+	c *Client
+}
+
+const (
+	// hostVolumeRequestTimeout is the timeout of the host volume plugins
+	hostVolumeRequestTimeout = time.Minute
+)
+
+// Create is used to create a dynamic host volume with a host volume plugin.
+// The volume is added to the node once it has been created.
+func (v *HostVolume) Create(req *structs.ClientHostVolumeCreateRequest, resp *structs.ClientHostVolumeCreateResponse) error {
+	defer metrics.MeasureSince([]string{"client", "host_volume", "create"}, time.Now())
+
+	if req.ID == "" {
+		return errors.New("HostVolume.Create: ID is required")
+	}
+	if req.Name == "" {
+		return errors.New("HostVolume.Create: Name is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hostVolumeRequestTimeout)
+	defer cancel()
+
+	cresp, err := v.c.hostVolumeManager.Create(ctx, req)
+	if err != nil {
+		v.c.logger.Error("failed to create host volume", "name", req.Name, "error", err)
+		return err
+	}
+
+	*resp = *cresp
+	return nil
+}
+
+// Delete is used to delete a dynamic host volume with the plugin that created
+// it. The volume is removed from the node once it has been deleted.
+func (v *HostVolume) Delete(req *structs.ClientHostVolumeDeleteRequest, resp *structs.ClientHostVolumeDeleteResponse) error {
+	defer metrics.MeasureSince([]string{"client", "host_volume", "delete"}, time.Now())
+
+	if req.ID == "" {
+		return errors.New("HostVolume.Delete: ID is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hostVolumeRequestTimeout)
+	defer cancel()
+
+	if _, err := v.c.hostVolumeManager.Delete(ctx, req); err != nil {
+		v.c.logger.Error("failed to delete host volume", "name", req.Name, "error", err)
+		return err
+	}
+
+	return nil
+}
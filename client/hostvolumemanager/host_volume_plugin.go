@@ -0,0 +1,263 @@
+package hostvolumemanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+// HostVolumePlugin is used to create and delete dynamic host volumes on the
+// client.
+type HostVolumePlugin interface {
+	// Fingerprint returns the version of the plugin.
+	Fingerprint(ctx context.Context) (*PluginFingerprint, error)
+
+	// Create creates the volume. It must be idempotent, as it is called
+	// again if the servers retry a create request.
+	Create(ctx context.Context, req *cstructs.ClientHostVolumeCreateRequest) (*HostVolumePluginCreateResponse, error)
+
+	// Delete deletes the volume. It must not fail if the volume does not
+	// exist anymore.
+	Delete(ctx context.Context, req *cstructs.ClientHostVolumeDeleteRequest) error
+}
+
+// PluginFingerprint is the fingerprint of a host volume plugin.
+type PluginFingerprint struct {
+	Version string `json:"version"`
+}
+
+// HostVolumePluginCreateResponse is returned by the plugins once a volume
+// has been created.
+type HostVolumePluginCreateResponse struct {
+	// Path is the path of the volume on the host
+	Path string `json:"path"`
+
+	// SizeBytes is the capacity of the volume, if the plugin enforces one
+	SizeBytes int64 `json:"bytes"`
+}
+
+const (
+	// mkdirPluginVersion is the version of the built-in mkdir plugin
+	mkdirPluginVersion = "0.0.1"
+
+	// mkdirDefaultMode is the mode of the directories created by the mkdir
+	// plugin unless the "mode" parameter is set
+	mkdirDefaultMode = 0o700
+)
+
+// HostVolumePluginMkdir is the built-in host volume plugin. It creates a
+// directory named after the volume ID in TargetPath, whose mode and owner
+// can be set with the "mode", "uid" and "gid" parameters. The directory can
+// only be given to an unprivileged owner, so requesting a volume doesn't
+// grant root access to the node. The capacity of the volume is not enforced.
+type HostVolumePluginMkdir struct {
+	ID         string
+	TargetPath string
+
+	log hclog.Logger
+}
+
+func (p *HostVolumePluginMkdir) Fingerprint(_ context.Context) (*PluginFingerprint, error) {
+	return &PluginFingerprint{Version: mkdirPluginVersion}, nil
+}
+
+func (p *HostVolumePluginMkdir) Create(_ context.Context,
+	req *cstructs.ClientHostVolumeCreateRequest) (*HostVolumePluginCreateResponse, error) {
+
+	params, err := decodeMkdirParameters(req.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(p.TargetPath, req.ID)
+	log := p.log.With("operation", "create", "volume_id", req.ID, "path", path)
+	log.Debug("running plugin")
+
+	if err := os.MkdirAll(p.TargetPath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create volumes directory: %v", err)
+	}
+	if err := os.Mkdir(path, params.mode); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	// The mode given to Mkdir is subject to the umask and isn't applied
+	// when the directory already exists
+	if err := os.Chmod(path, params.mode); err != nil {
+		return nil, fmt.Errorf("failed to set directory mode: %v", err)
+	}
+	if params.uid != -1 || params.gid != -1 {
+		if err := os.Chown(path, params.uid, params.gid); err != nil {
+			return nil, fmt.Errorf("failed to set directory owner: %v", err)
+		}
+	}
+
+	log.Debug("plugin ran successfully")
+	return &HostVolumePluginCreateResponse{Path: path}, nil
+}
+
+func (p *HostVolumePluginMkdir) Delete(_ context.Context, req *cstructs.ClientHostVolumeDeleteRequest) error {
+	path := filepath.Join(p.TargetPath, req.ID)
+	log := p.log.With("operation", "delete", "volume_id", req.ID, "path", path)
+	log.Debug("running plugin")
+
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to delete directory: %v", err)
+	}
+
+	log.Debug("plugin ran successfully")
+	return nil
+}
+
+// mkdirParameters are the parameters supported by the mkdir plugin
+type mkdirParameters struct {
+	mode os.FileMode
+	uid  int
+	gid  int
+}
+
+func decodeMkdirParameters(params map[string]string) (*mkdirParameters, error) {
+	out := &mkdirParameters{mode: mkdirDefaultMode, uid: -1, gid: -1}
+
+	for k, v := range params {
+		switch k {
+		case "mode":
+			mode, err := strconv.ParseUint(v, 8, 32)
+			if err != nil || mode > 0o777 {
+				return nil, fmt.Errorf("invalid mode %q", v)
+			}
+			out.mode = os.FileMode(mode)
+		case "uid", "gid":
+			id, err := strconv.Atoi(v)
+			if err != nil || id < 0 {
+				return nil, fmt.Errorf("invalid %s %q", k, v)
+			}
+			if id == 0 {
+				return nil, fmt.Errorf("invalid %s %q: volumes can't be owned by root", k, v)
+			}
+			if k == "uid" {
+				out.uid = id
+			} else {
+				out.gid = id
+			}
+		default:
+			return nil, fmt.Errorf("unknown parameter %q", k)
+		}
+	}
+	return out, nil
+}
+
+// HostVolumePluginExternal is a host volume plugin implemented by an
+// executable in the host volume plugin directory. The executable is run with
+// the operation as its only argument, and the request passed as environment
+// variables. The "fingerprint" and "create" operations must write their
+// response as JSON to stdout.
+type HostVolumePluginExternal struct {
+	ID         string
+	Executable string
+	TargetPath string
+
+	log hclog.Logger
+}
+
+func (p *HostVolumePluginExternal) Fingerprint(ctx context.Context) (*PluginFingerprint, error) {
+	stdout, err := p.runPlugin(ctx, "fingerprint", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fp := &PluginFingerprint{}
+	if err := json.Unmarshal(stdout, fp); err != nil {
+		return nil, fmt.Errorf("failed to decode plugin fingerprint: %v", err)
+	}
+	if fp.Version == "" {
+		return nil, errors.New("plugin fingerprint is missing a version")
+	}
+	return fp, nil
+}
+
+func (p *HostVolumePluginExternal) Create(ctx context.Context,
+	req *cstructs.ClientHostVolumeCreateRequest) (*HostVolumePluginCreateResponse, error) {
+
+	params, err := json.Marshal(req.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode parameters: %v", err)
+	}
+
+	stdout, err := p.runPlugin(ctx, "create", []string{
+		"DHV_VOLUME_ID=" + req.ID,
+		"DHV_VOLUME_NAME=" + req.Name,
+		"DHV_NODE_ID=" + req.NodeID,
+		"DHV_HOST_PATH=" + filepath.Join(p.TargetPath, req.ID),
+		"DHV_CAPACITY_MIN_BYTES=" + strconv.FormatInt(req.RequestedCapacityMinBytes, 10),
+		"DHV_CAPACITY_MAX_BYTES=" + strconv.FormatInt(req.RequestedCapacityMaxBytes, 10),
+		"DHV_PARAMETERS=" + string(params),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &HostVolumePluginCreateResponse{}
+	if err := json.Unmarshal(stdout, resp); err != nil {
+		return nil, fmt.Errorf("failed to decode plugin response: %v", err)
+	}
+	if resp.Path == "" {
+		return nil, errors.New("plugin response is missing the volume path")
+	}
+	return resp, nil
+}
+
+func (p *HostVolumePluginExternal) Delete(ctx context.Context, req *cstructs.ClientHostVolumeDeleteRequest) error {
+	params, err := json.Marshal(req.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to encode parameters: %v", err)
+	}
+
+	_, err = p.runPlugin(ctx, "delete", []string{
+		"DHV_VOLUME_ID=" + req.ID,
+		"DHV_VOLUME_NAME=" + req.Name,
+		"DHV_NODE_ID=" + req.NodeID,
+		"DHV_HOST_PATH=" + req.HostPath,
+		"DHV_PARAMETERS=" + string(params),
+	})
+	return err
+}
+
+// runPlugin runs the plugin executable for the operation and returns its
+// stdout. Stderr is logged, and included in the error if the plugin fails.
+func (p *HostVolumePluginExternal) runPlugin(ctx context.Context, op string, env []string) ([]byte, error) {
+	log := p.log.With("operation", op)
+	log.Debug("running plugin")
+
+	cmd := exec.CommandContext(ctx, p.Executable, op)
+	cmd.Env = append([]string{
+		"PATH=" + os.Getenv("PATH"),
+		"DHV_OPERATION=" + op,
+		"DHV_VOLUMES_DIR=" + p.TargetPath,
+	}, env...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if stderr.Len() != 0 {
+		log.Debug("plugin output", "stderr", stderr.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin %q %s failed: %v: %s",
+			p.ID, op, err, strings.TrimSpace(stderr.String()))
+	}
+
+	log.Debug("plugin ran successfully")
+	return stdout.Bytes(), nil
+}
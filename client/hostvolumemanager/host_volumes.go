@@ -0,0 +1,276 @@
+// hostvolumemanager is a package that manages the dynamic host volumes of a
+// Nomad client. Volumes are created and deleted by host volume plugins on
+// behalf of the servers, and fingerprinted into the node's host volumes.
+package hostvolumemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+var (
+	// ErrPluginNotExists is returned when no host volume plugin has the
+	// requested ID.
+	ErrPluginNotExists = errors.New("no such host volume plugin")
+)
+
+// HostVolumeStateManager is used to persist the dynamic host volumes of the
+// client.
+type HostVolumeStateManager interface {
+	GetDynamicHostVolumes() ([]*cstructs.HostVolumeState, error)
+	PutDynamicHostVolume(*cstructs.HostVolumeState) error
+	DeleteDynamicHostVolume(string) error
+}
+
+// HostVolumeNodeUpdater is used to add a dynamic host volume to the node, or
+// remove the volume with the given name and ID if the volume is nil.
+type HostVolumeNodeUpdater func(name, id string, volume *structs.ClientHostVolumeConfig)
+
+// Config is used to configure the host volume manager.
+type Config struct {
+	// PluginDir is where external host volume plugins are found
+	PluginDir string
+
+	// SharedMountDir is where the plugins create the volumes
+	SharedMountDir string
+
+	// StateMgr is used to persist the volumes
+	StateMgr HostVolumeStateManager
+
+	// UpdateNodeVols is used to update the host volumes of the node
+	UpdateNodeVols HostVolumeNodeUpdater
+}
+
+// HostVolumeManager creates and deletes the dynamic host volumes of the
+// client with the host volume plugins.
+type HostVolumeManager struct {
+	pluginDir      string
+	sharedMountDir string
+	stateMgr       HostVolumeStateManager
+	updateNodeVols HostVolumeNodeUpdater
+	log            hclog.Logger
+
+	// volLocks serializes the operations on a volume name, so that a volume
+	// can't be deleted while it is being created
+	volLocks   map[string]*sync.Mutex
+	volLocksMu sync.Mutex
+}
+
+// NewHostVolumeManager returns a host volume manager configured with the
+// given config.
+func NewHostVolumeManager(logger hclog.Logger, config *Config) *HostVolumeManager {
+	return &HostVolumeManager{
+		pluginDir:      config.PluginDir,
+		sharedMountDir: config.SharedMountDir,
+		stateMgr:       config.StateMgr,
+		updateNodeVols: config.UpdateNodeVols,
+		log:            logger.Named("host_volume_manager"),
+		volLocks:       make(map[string]*sync.Mutex),
+	}
+}
+
+// Fingerprint returns the node attributes of the host volume plugins of the
+// client. Plugins that fail to fingerprint are skipped.
+func (hvm *HostVolumeManager) Fingerprint(ctx context.Context) map[string]string {
+	attrs := map[string]string{}
+
+	ids := []string{structs.HostVolumePluginMkdir}
+	entries, err := os.ReadDir(hvm.pluginDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		hvm.log.Warn("failed to read host volume plugin directory", "error", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == structs.HostVolumePluginMkdir {
+			continue
+		}
+		ids = append(ids, entry.Name())
+	}
+
+	for _, id := range ids {
+		plug, err := hvm.getPlugin(id)
+		if err != nil {
+			hvm.log.Warn("skipping host volume plugin", "plugin_id", id, "error", err)
+			continue
+		}
+		fp, err := plug.Fingerprint(ctx)
+		if err != nil {
+			hvm.log.Warn("failed to fingerprint host volume plugin", "plugin_id", id, "error", err)
+			continue
+		}
+		attrs[structs.HostVolumePluginAttrPrefix+id+".version"] = fp.Version
+	}
+	return attrs
+}
+
+// Restore returns the dynamic host volumes stored by the client, keyed by
+// name, so that they can be fingerprinted again. Volumes whose path doesn't
+// exist anymore are skipped.
+func (hvm *HostVolumeManager) Restore() (map[string]*structs.ClientHostVolumeConfig, error) {
+	vols, err := hvm.stateMgr.GetDynamicHostVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*structs.ClientHostVolumeConfig, len(vols))
+	for _, vol := range vols {
+		if _, err := os.Stat(vol.HostPath); err != nil {
+			hvm.log.Warn("skipping restore of host volume", "volume_id", vol.ID, "path", vol.HostPath, "error", err)
+			continue
+		}
+		out[vol.CreateReq.Name] = &structs.ClientHostVolumeConfig{
+			Name: vol.CreateReq.Name,
+			Path: vol.HostPath,
+			ID:   vol.ID,
+		}
+	}
+	return out, nil
+}
+
+// Create creates a dynamic host volume with its plugin, stores it and adds it
+// to the node.
+func (hvm *HostVolumeManager) Create(ctx context.Context,
+	req *cstructs.ClientHostVolumeCreateRequest) (*cstructs.ClientHostVolumeCreateResponse, error) {
+
+	unlock := hvm.lockVolume(req.Name)
+	defer unlock()
+
+	// Volumes are fingerprinted by name, so it must be unique on the node
+	vols, err := hvm.stateMgr.GetDynamicHostVolumes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host volumes from state: %v", err)
+	}
+	for _, vol := range vols {
+		if vol.CreateReq.Name == req.Name && vol.ID != req.ID {
+			return nil, fmt.Errorf("host volume %q already exists with ID %q", req.Name, vol.ID)
+		}
+	}
+
+	plug, err := hvm.getPlugin(req.PluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginResp, err := plug.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	volState := &cstructs.HostVolumeState{
+		ID:        req.ID,
+		CreateReq: req,
+		HostPath:  pluginResp.Path,
+	}
+	if err := hvm.stateMgr.PutDynamicHostVolume(volState); err != nil {
+		// The volume can't be restored after a restart, so clean it up
+		var mErr *multierror.Error
+		mErr = multierror.Append(mErr, fmt.Errorf("failed to store host volume: %v", err))
+		delReq := &cstructs.ClientHostVolumeDeleteRequest{
+			ID:         req.ID,
+			Name:       req.Name,
+			PluginID:   req.PluginID,
+			NodeID:     req.NodeID,
+			HostPath:   pluginResp.Path,
+			Parameters: req.Parameters,
+		}
+		if err := plug.Delete(ctx, delReq); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("failed to clean up host volume: %v", err))
+		}
+		return nil, mErr.ErrorOrNil()
+	}
+
+	hvm.updateNodeVols(req.Name, req.ID, &structs.ClientHostVolumeConfig{
+		Name: req.Name,
+		Path: pluginResp.Path,
+		ID:   req.ID,
+	})
+
+	return &cstructs.ClientHostVolumeCreateResponse{
+		HostPath:      pluginResp.Path,
+		CapacityBytes: pluginResp.SizeBytes,
+	}, nil
+}
+
+// Delete deletes a dynamic host volume with its plugin and removes it from
+// the node.
+func (hvm *HostVolumeManager) Delete(ctx context.Context,
+	req *cstructs.ClientHostVolumeDeleteRequest) (*cstructs.ClientHostVolumeDeleteResponse, error) {
+
+	unlock := hvm.lockVolume(req.Name)
+	defer unlock()
+
+	plug, err := hvm.getPlugin(req.PluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plug.Delete(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if err := hvm.stateMgr.DeleteDynamicHostVolume(req.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete host volume from state: %v", err)
+	}
+
+	hvm.updateNodeVols(req.Name, req.ID, nil)
+
+	return &cstructs.ClientHostVolumeDeleteResponse{}, nil
+}
+
+// getPlugin returns the built-in plugin or the external plugin with the ID.
+func (hvm *HostVolumeManager) getPlugin(id string) (HostVolumePlugin, error) {
+	log := hvm.log.With("plugin_id", id)
+
+	if id == structs.HostVolumePluginMkdir {
+		return &HostVolumePluginMkdir{
+			ID:         id,
+			TargetPath: hvm.sharedMountDir,
+			log:        log,
+		}, nil
+	}
+
+	// Plugin IDs are file names in the plugin directory
+	if id == "" || id != filepath.Base(id) {
+		return nil, fmt.Errorf("%w: %q", ErrPluginNotExists, id)
+	}
+	path := filepath.Join(hvm.pluginDir, id)
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %q", ErrPluginNotExists, id)
+		}
+		return nil, err
+	}
+	if info.IsDir() || info.Mode()&0o111 == 0 {
+		return nil, fmt.Errorf("host volume plugin %q is not executable", id)
+	}
+
+	return &HostVolumePluginExternal{
+		ID:         id,
+		Executable: path,
+		TargetPath: hvm.sharedMountDir,
+		log:        log,
+	}, nil
+}
+
+// lockVolume locks the volume name and returns the function to unlock it.
+func (hvm *HostVolumeManager) lockVolume(name string) func() {
+	hvm.volLocksMu.Lock()
+	l, ok := hvm.volLocks[name]
+	if !ok {
+		l = &sync.Mutex{}
+		hvm.volLocks[name] = l
+	}
+	hvm.volLocksMu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
@@ -0,0 +1,185 @@
+package hostvolumemanager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/state"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestManager returns a host volume manager with an in-memory state and
+// the node volumes it updates.
+func newTestManager(t *testing.T) (*HostVolumeManager, map[string]*structs.ClientHostVolumeConfig) {
+	logger := testlog.HCLogger(t)
+	nodeVols := map[string]*structs.ClientHostVolumeConfig{}
+
+	hvm := NewHostVolumeManager(logger, &Config{
+		PluginDir:      t.TempDir(),
+		SharedMountDir: t.TempDir(),
+		StateMgr:       state.NewMemDB(logger),
+		UpdateNodeVols: func(name, id string, vol *structs.ClientHostVolumeConfig) {
+			if vol == nil {
+				if existing, ok := nodeVols[name]; ok && existing.ID == id {
+					delete(nodeVols, name)
+				}
+				return
+			}
+			nodeVols[name] = vol
+		},
+	})
+	return hvm, nodeVols
+}
+
+func TestHostVolumeManager_Mkdir(t *testing.T) {
+	ci.Parallel(t)
+	hvm, nodeVols := newTestManager(t)
+	ctx := context.Background()
+
+	req := &cstructs.ClientHostVolumeCreateRequest{
+		ID:         "vol-id",
+		Name:       "data",
+		PluginID:   structs.HostVolumePluginMkdir,
+		Parameters: map[string]string{"mode": "0750"},
+	}
+	resp, err := hvm.Create(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(hvm.sharedMountDir, "vol-id"), resp.HostPath)
+
+	info, err := os.Stat(resp.HostPath)
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	require.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+
+	require.Equal(t, &structs.ClientHostVolumeConfig{
+		Name: "data",
+		Path: resp.HostPath,
+		ID:   "vol-id",
+	}, nodeVols["data"])
+
+	// The volume is restored from the client state.
+	restored, err := hvm.Restore()
+	require.NoError(t, err)
+	require.Equal(t, nodeVols, restored)
+
+	// Creating the volume again is idempotent, but another volume can't use
+	// the same name.
+	_, err = hvm.Create(ctx, req)
+	require.NoError(t, err)
+
+	other := *req
+	other.ID = "other-id"
+	_, err = hvm.Create(ctx, &other)
+	require.EqualError(t, err, `host volume "data" already exists with ID "vol-id"`)
+	require.NoDirExists(t, filepath.Join(hvm.sharedMountDir, "other-id"))
+
+	// Deleting another volume with the same name leaves the volume alone.
+	_, err = hvm.Delete(ctx, &cstructs.ClientHostVolumeDeleteRequest{
+		ID:       "other-id",
+		Name:     "data",
+		PluginID: structs.HostVolumePluginMkdir,
+		HostPath: filepath.Join(hvm.sharedMountDir, "other-id"),
+	})
+	require.NoError(t, err)
+	require.Contains(t, nodeVols, "data")
+
+	_, err = hvm.Delete(ctx, &cstructs.ClientHostVolumeDeleteRequest{
+		ID:       "vol-id",
+		Name:     "data",
+		PluginID: structs.HostVolumePluginMkdir,
+		HostPath: resp.HostPath,
+	})
+	require.NoError(t, err)
+	require.NoDirExists(t, resp.HostPath)
+	require.Empty(t, nodeVols)
+
+	restored, err = hvm.Restore()
+	require.NoError(t, err)
+	require.Empty(t, restored)
+
+	// Unknown parameters are rejected.
+	req.Parameters = map[string]string{"size": "10G"}
+	_, err = hvm.Create(ctx, req)
+	require.EqualError(t, err, `unknown parameter "size"`)
+
+	// Volumes can't be given to root.
+	req.Parameters = map[string]string{"uid": "0"}
+	_, err = hvm.Create(ctx, req)
+	require.EqualError(t, err, `invalid uid "0": volumes can't be owned by root`)
+
+	req.Parameters = map[string]string{"gid": "0"}
+	_, err = hvm.Create(ctx, req)
+	require.EqualError(t, err, `invalid gid "0": volumes can't be owned by root`)
+}
+
+func TestHostVolumeManager_External(t *testing.T) {
+	ci.Parallel(t)
+	hvm, nodeVols := newTestManager(t)
+	ctx := context.Background()
+
+	script := `#!/bin/sh
+set -e
+case "$1" in
+  fingerprint)
+    echo '{"version": "1.2.3"}' ;;
+  create)
+    mkdir -p "$DHV_HOST_PATH"
+    echo "$DHV_PARAMETERS" > "$DHV_HOST_PATH/params"
+    echo "{\"path\": \"$DHV_HOST_PATH\", \"bytes\": $DHV_CAPACITY_MAX_BYTES}" ;;
+  delete)
+    rm -rf "$DHV_HOST_PATH" ;;
+  *)
+    echo "unknown operation $1" >&2
+    exit 1 ;;
+esac
+`
+	path := filepath.Join(hvm.pluginDir, "example")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+
+	attrs := hvm.Fingerprint(ctx)
+	require.Equal(t, map[string]string{
+		"plugins.host_volume.mkdir.version":   mkdirPluginVersion,
+		"plugins.host_volume.example.version": "1.2.3",
+	}, attrs)
+
+	resp, err := hvm.Create(ctx, &cstructs.ClientHostVolumeCreateRequest{
+		ID:                        "vol-id",
+		Name:                      "data",
+		PluginID:                  "example",
+		RequestedCapacityMaxBytes: 1024,
+		Parameters:                map[string]string{"foo": "bar"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(hvm.sharedMountDir, "vol-id"), resp.HostPath)
+	require.Equal(t, int64(1024), resp.CapacityBytes)
+	require.Contains(t, nodeVols, "data")
+
+	params, err := os.ReadFile(filepath.Join(resp.HostPath, "params"))
+	require.NoError(t, err)
+	require.Equal(t, "{\"foo\":\"bar\"}\n", string(params))
+
+	_, err = hvm.Delete(ctx, &cstructs.ClientHostVolumeDeleteRequest{
+		ID:       "vol-id",
+		Name:     "data",
+		PluginID: "example",
+		HostPath: resp.HostPath,
+	})
+	require.NoError(t, err)
+	require.NoDirExists(t, resp.HostPath)
+	require.NotContains(t, nodeVols, "data")
+
+	// Plugins are looked up by file name in the plugin directory only.
+	for _, id := range []string{"missing", "../example"} {
+		_, err = hvm.Create(ctx, &cstructs.ClientHostVolumeCreateRequest{
+			ID: "vol-id", Name: "data", PluginID: id,
+		})
+		require.True(t, errors.Is(err, ErrPluginNotExists), "plugin %q: %v", id, err)
+	}
+}
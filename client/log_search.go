@@ -0,0 +1,294 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+const (
+	// logSearchMaxLineSize is the maximum size of a line that is searched.
+	// Longer lines are split and each part is searched separately.
+	logSearchMaxLineSize = 64 * 1024
+)
+
+var (
+	// logTimeFields are the fields of JSON log lines that may hold the time
+	// of the line, by order of preference.
+	logTimeFields = []string{"time", "timestamp", "ts", "@timestamp"}
+
+	// logTimeLayouts are the layouts of the timestamps of log lines.
+	logTimeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.999999999Z0700",
+	}
+)
+
+// logSearch restricts the logs streamed by the FileSystem.Logs endpoint to the
+// lines written within a time range and matching a regular expression.
+//
+// The time of a line is parsed from the line itself when it is a JSON object
+// with a time field or when it starts with a timestamp. Otherwise, the time is
+// estimated from the index the log rotator keeps next to each log file.
+type logSearch struct {
+	since time.Time
+	until time.Time
+	grep  *regexp.Regexp
+}
+
+// newLogSearch returns the search of a logs request, or nil if the request
+// doesn't search the logs.
+func newLogSearch(req *cstructs.FsLogsRequest) (*logSearch, error) {
+	if req.Since.IsZero() && req.Until.IsZero() && req.Grep == "" {
+		return nil, nil
+	}
+
+	if !req.Since.IsZero() && !req.Until.IsZero() && req.Since.After(req.Until) {
+		return nil, fmt.Errorf("since must not be after until")
+	}
+	if req.Follow && !req.Until.IsZero() {
+		return nil, fmt.Errorf("logs can't be followed until a given time")
+	}
+
+	s := &logSearch{
+		since: req.Since,
+		until: req.Until,
+	}
+	if req.Grep != "" {
+		grep, err := regexp.Compile(req.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grep pattern: %v", err)
+		}
+		s.grep = grep
+	}
+	return s, nil
+}
+
+// start returns the index of the log file and the offset to start streaming
+// the searched logs at, using the indexes of the log files to skip the lines
+// written before the start of the time range.
+func (s *logSearch) start(fs allocdir.AllocDirFS, logPath string,
+	entries []*cstructs.AllocFileInfo, task, logType string) (int64, int64, error) {
+
+	indexes, err := logIndexes(entries, task, logType)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(indexes) == 0 {
+		return 0, 0, notFoundErr{taskName: task, logType: logType}
+	}
+	sort.Sort(indexes)
+
+	// Files without an index can't be skipped
+	startIdx, startOffset := indexes[0].idx, int64(0)
+	for _, e := range indexes {
+		index := readLogIndex(fs, logPath, task, logType, e.idx)
+		if len(index) == 0 {
+			continue
+		}
+		if index[0].Time.After(s.since) {
+			break
+		}
+
+		startIdx, startOffset = e.idx, 0
+		for _, entry := range index {
+			if entry.Time.After(s.since) {
+				break
+			}
+			startOffset = entry.Offset
+		}
+
+		// The index may be ahead of the buffered content of the file
+		if startOffset > e.entry.Size {
+			startOffset = e.entry.Size
+		}
+	}
+
+	return startIdx, startOffset, nil
+}
+
+// newFilter returns the filter of the content of a log file, which is read
+// from the given offset.
+func (s *logSearch) newFilter(index []*logging.LogIndexEntry, offset int64) *logFilter {
+	return &logFilter{
+		search: s,
+		index:  index,
+		offset: offset,
+	}
+}
+
+// readLogIndex returns the entries of the index of a log file, or nil if the
+// log file has no index.
+func readLogIndex(fs allocdir.AllocDirFS, logPath, task, logType string, idx int64) []*logging.LogIndexEntry {
+	p := filepath.Join(logPath, logging.IndexFileName(fmt.Sprintf("%s.%s", task, logType), idx))
+	r, err := fs.ReadAt(p, 0)
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+
+	index, err := logging.ParseLogIndex(r)
+	if err != nil {
+		return nil
+	}
+	return index
+}
+
+// logFilter filters the content of a log file, keeping the lines that match
+// a log search.
+type logFilter struct {
+	search *logSearch
+	index  []*logging.LogIndexEntry
+
+	// offset is the offset of the start of the partial line
+	offset  int64
+	partial []byte
+	out     []byte
+
+	// done is set once a line written after the end of the time range is
+	// found
+	done bool
+}
+
+// Write filters content read from the log file and returns the complete
+// lines that match the search. The returned slice is only valid until the
+// next call.
+func (lf *logFilter) Write(data []byte) []byte {
+	lf.out = lf.out[:0]
+	lf.partial = append(lf.partial, data...)
+
+	consumed := 0
+	for !lf.done {
+		rest := lf.partial[consumed:]
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			if len(rest) < logSearchMaxLineSize {
+				break
+			}
+			i = logSearchMaxLineSize - 1
+		}
+		lf.filterLine(rest[:i+1])
+		consumed += i + 1
+	}
+
+	lf.partial = append(lf.partial[:0], lf.partial[consumed:]...)
+	return lf.out
+}
+
+// Flush filters the partial line at the end of the log file and returns it
+// if it matches the search.
+func (lf *logFilter) Flush() []byte {
+	lf.out = lf.out[:0]
+	if len(lf.partial) != 0 && !lf.done {
+		lf.filterLine(lf.partial)
+	}
+	lf.partial = lf.partial[:0]
+	return lf.out
+}
+
+// Reset discards the partial line after the log file has been truncated.
+func (lf *logFilter) Reset() {
+	lf.partial = lf.partial[:0]
+	lf.offset = 0
+}
+
+// filterLine appends the line to the output if it matches the search.
+func (lf *logFilter) filterLine(line []byte) {
+	lineOffset := lf.offset
+	lf.offset += int64(len(line))
+	content := bytes.TrimRight(line, "\r\n")
+
+	// The index gives a lower bound of the time of the line, which ends the
+	// search once it is past the time range
+	if t, ok := lf.indexTime(lineOffset); ok && !lf.search.until.IsZero() && t.After(lf.search.until) {
+		lf.done = true
+		return
+	}
+
+	// Lines without a time are only located using the index, which was used
+	// to skip the lines written before the start of the time range
+	if t, ok := parseLogLineTime(content); ok {
+		if !lf.search.since.IsZero() && t.Before(lf.search.since) {
+			return
+		}
+		if !lf.search.until.IsZero() && t.After(lf.search.until) {
+			return
+		}
+	}
+
+	if lf.search.grep != nil && !lf.search.grep.Match(content) {
+		return
+	}
+	lf.out = append(lf.out, line...)
+}
+
+// indexTime returns the time of the last index entry at or before the given
+// offset.
+func (lf *logFilter) indexTime(offset int64) (time.Time, bool) {
+	i := sort.Search(len(lf.index), func(i int) bool { return lf.index[i].Offset > offset })
+	if i == 0 {
+		return time.Time{}, false
+	}
+	return lf.index[i-1].Time, true
+}
+
+// parseLogLineTime returns the time of a log line. JSON lines hold their time
+// in one of the logTimeFields, either as a timestamp or as seconds or
+// milliseconds since the epoch. Other lines may start with a timestamp.
+func parseLogLineTime(line []byte) (time.Time, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return time.Time{}, false
+	}
+
+	if line[0] == '{' {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return time.Time{}, false
+		}
+		for _, name := range logTimeFields {
+			switch v := fields[name].(type) {
+			case string:
+				if t, ok := parseLogTime(v); ok {
+					return t, true
+				}
+			case float64:
+				// Values beyond the year 33658 are in milliseconds
+				if v > 1e12 {
+					v /= 1e3
+				}
+				sec, frac := math.Modf(v)
+				return time.Unix(int64(sec), int64(frac*1e9)), true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	if line[0] < '0' || line[0] > '9' {
+		return time.Time{}, false
+	}
+	end := bytes.IndexAny(line, " \t")
+	if end < 0 {
+		end = len(line)
+	}
+	return parseLogTime(string(line[:end]))
+}
+
+// parseLogTime parses a timestamp of a log line.
+func parseLogTime(v string) (time.Time, bool) {
+	for _, layout := range logTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
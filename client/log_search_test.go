@@ -0,0 +1,104 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSearch_New(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+
+	s, err := newLogSearch(&cstructs.FsLogsRequest{Follow: true})
+	require.NoError(t, err)
+	require.Nil(t, s)
+
+	_, err = newLogSearch(&cstructs.FsLogsRequest{Since: now, Until: now.Add(-time.Hour)})
+	require.EqualError(t, err, "since must not be after until")
+
+	_, err = newLogSearch(&cstructs.FsLogsRequest{Follow: true, Until: now})
+	require.EqualError(t, err, "logs can't be followed until a given time")
+
+	_, err = newLogSearch(&cstructs.FsLogsRequest{Grep: "("})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid grep pattern")
+
+	s, err = newLogSearch(&cstructs.FsLogsRequest{Follow: true, Since: now, Grep: "error"})
+	require.NoError(t, err)
+	require.Equal(t, now, s.since)
+	require.True(t, s.grep.MatchString("an error"))
+}
+
+func TestLogSearch_parseLogLineTime(t *testing.T) {
+	ci.Parallel(t)
+
+	expected := time.Date(2022, 6, 1, 12, 30, 15, 500000000, time.UTC)
+
+	cases := []struct {
+		line string
+		ok   bool
+	}{
+		{line: `{"time":"2022-06-01T12:30:15.5Z","msg":"hello"}`, ok: true},
+		{line: `{"@timestamp":"2022-06-01T14:30:15.5+02:00"}`, ok: true},
+		{line: `{"ts":1654086615.5,"level":"info"}`, ok: true},
+		{line: `{"timestamp":1654086615500}`, ok: true},
+		{line: `2022-06-01T12:30:15.500Z [INFO] hello`, ok: true},
+		{line: `2022-06-01T14:30:15.500+0200 [INFO] hello`, ok: true},
+		{line: `{"msg":"hello"}`},
+		{line: `{"time":"yesterday"}`},
+		{line: `{not json`},
+		{line: `2022 was a good year`},
+		{line: `hello`},
+		{line: ``},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.line, func(t *testing.T) {
+			actual, ok := parseLogLineTime([]byte(tc.line))
+			require.Equal(t, tc.ok, ok)
+			if tc.ok {
+				require.True(t, expected.Equal(actual), "got %v", actual)
+			}
+		})
+	}
+}
+
+func TestLogSearch_Filter(t *testing.T) {
+	ci.Parallel(t)
+
+	base := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	s, err := newLogSearch(&cstructs.FsLogsRequest{Until: base.Add(time.Hour), Grep: "keep"})
+	require.NoError(t, err)
+
+	// The lines after the first index entry past the end of the time range
+	// aren't searched
+	index := []*logging.LogIndexEntry{
+		{Time: base, Offset: 0},
+		{Time: base.Add(2 * time.Hour), Offset: 20},
+	}
+	f := s.newFilter(index, 0)
+
+	// Lines split across reads are searched once complete
+	require.Equal(t, "keep 1\n", string(f.Write([]byte("keep 1\ndrop 2\nke"))))
+	require.Equal(t, "", string(f.Write([]byte("ep"))))
+	require.False(t, f.done)
+	require.Equal(t, "keep 3\r\n", string(f.Write([]byte(" 3\r\nkeep 4\n"))))
+	require.True(t, f.done)
+	require.Empty(t, f.Flush())
+
+	// The partial line at the end of a file is searched when flushed, and
+	// long lines are split
+	f = s.newFilter(nil, 0)
+	long := "keep " + strings.Repeat("x", logSearchMaxLineSize)
+	require.Equal(t, long[:logSearchMaxLineSize], string(f.Write([]byte(long))))
+	require.Empty(t, f.Flush())
+	require.Equal(t, "", string(f.Write([]byte("keep 5"))))
+	require.Equal(t, "keep 5", string(f.Flush()))
+}
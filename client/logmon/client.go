@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/nomad/client/logmon/proto"
 	"github.com/hashicorp/nomad/helper/pluginutils/grpcutils"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 type logmonClient struct {
@@ -26,6 +27,14 @@ func (c *logmonClient) Start(cfg *LogConfig) error {
 		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
 		StdoutFifo:     cfg.StdoutFifo,
 		StderrFifo:     cfg.StderrFifo,
+		Sinks:          logSinksToProto(cfg.Sinks),
+	}
+	if meta := cfg.Metadata; meta != nil {
+		req.AllocId = meta.AllocID
+		req.Namespace = meta.Namespace
+		req.JobId = meta.JobID
+		req.Group = meta.Group
+		req.Task = meta.Task
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -42,3 +51,23 @@ func (c *logmonClient) Stop() error {
 	_, err := c.client.Stop(ctx, req)
 	return grpcutils.HandleGrpcErr(err, c.doneCtx)
 }
+
+func logSinksToProto(sinks []*structs.LogSink) []*proto.LogSink {
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	out := make([]*proto.LogSink, len(sinks))
+	for i, sink := range sinks {
+		out[i] = &proto.LogSink{
+			Type:           sink.Type,
+			Address:        sink.Address,
+			Tag:            sink.Tag,
+			Headers:        sink.Headers,
+			BatchSize:      uint32(sink.BatchSize),
+			BatchWaitNanos: sink.BatchWait.Nanoseconds(),
+			BufferSize:     uint32(sink.BufferSize),
+		}
+	}
+	return out
+}
@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// fluentHandle is the msgpack handle used to encode the messages of the
+// fluent forward protocol.
+var fluentHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	if err := h.SetBytesExt(reflect.TypeOf(fluentEventTime{}), 0, fluentEventTimeExt{}); err != nil {
+		panic(err)
+	}
+	return h
+}()
+
+// fluentEventTime is the EventTime extension of the fluent forward
+// protocol, which carries the time of an entry with nanosecond precision.
+type fluentEventTime time.Time
+
+// fluentEventTimeExt encodes fluentEventTime values as the EventTime
+// extension of the fluent forward protocol.
+type fluentEventTimeExt struct{}
+
+func (fluentEventTimeExt) WriteExt(v interface{}) []byte {
+	var t time.Time
+	switch et := v.(type) {
+	case fluentEventTime:
+		t = time.Time(et)
+	case *fluentEventTime:
+		t = time.Time(*et)
+	default:
+		return nil
+	}
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, uint32(t.Unix()))
+	binary.BigEndian.PutUint32(b[4:], uint32(t.Nanosecond()))
+	return b
+}
+
+func (fluentEventTimeExt) ReadExt(dst interface{}, src []byte) {
+	if len(src) != 8 {
+		return
+	}
+	sec := binary.BigEndian.Uint32(src)
+	nsec := binary.BigEndian.Uint32(src[4:])
+	*(dst.(*fluentEventTime)) = fluentEventTime(time.Unix(int64(sec), int64(nsec)))
+}
+
+// fluentClient ships batches of lines to a fluent forward endpoint, such as
+// a local Fluentd or Fluent Bit agent, using the forward mode of the fluent
+// forward protocol.
+type fluentClient struct {
+	network string
+	address string
+	tag     string
+	meta    *LogMetadata
+
+	conn net.Conn
+}
+
+func newFluentClient(sink *structs.LogSink, meta *LogMetadata) (*fluentClient, error) {
+	c := &fluentClient{
+		network: "tcp",
+		address: sink.Address,
+		tag:     sink.Tag,
+		meta:    meta,
+	}
+	if strings.HasPrefix(sink.Address, "unix://") {
+		c.network = "unix"
+		c.address = strings.TrimPrefix(sink.Address, "unix://")
+	} else if strings.HasPrefix(sink.Address, "tcp://") {
+		c.address = strings.TrimPrefix(sink.Address, "tcp://")
+	}
+	if c.tag == "" {
+		c.tag = "nomad"
+	}
+	return c, nil
+}
+
+func (c *fluentClient) Send(ctx context.Context, lines []*LogLine) error {
+	if c.conn == nil {
+		dialer := &net.Dialer{Timeout: sinkDialTimeout}
+		conn, err := dialer.DialContext(ctx, c.network, c.address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to fluent endpoint: %v", err)
+		}
+		c.conn = conn
+	}
+
+	entries := make([]interface{}, len(lines))
+	for i, line := range lines {
+		entries[i] = []interface{}{
+			fluentEventTime(line.Time),
+			map[string]string{
+				"log":       line.Message,
+				"stream":    line.Stream,
+				"alloc_id":  c.meta.AllocID,
+				"namespace": c.meta.Namespace,
+				"job":       c.meta.JobID,
+				"group":     c.meta.Group,
+				"task":      c.meta.Task,
+			},
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+	} else {
+		c.conn.SetWriteDeadline(time.Now().Add(sinkDialTimeout))
+	}
+
+	enc := codec.NewEncoder(c.conn, fluentHandle)
+	if err := enc.Encode([]interface{}{c.tag, entries}); err != nil {
+		// Reconnect when the lines are sent again
+		c.conn.Close()
+		c.conn = nil
+		return fmt.Errorf("failed to write to fluent endpoint: %v", err)
+	}
+	return nil
+}
+
+func (c *fluentClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// httpSinkTimeout is the timeout of the requests sent to HTTP sinks.
+	httpSinkTimeout = 30 * time.Second
+)
+
+// httpLogLine is the JSON encoding of the lines shipped to HTTP sinks.
+type httpLogLine struct {
+	Time      time.Time `json:"time"`
+	Stream    string    `json:"stream"`
+	Message   string    `json:"message"`
+	AllocID   string    `json:"alloc_id"`
+	Namespace string    `json:"namespace"`
+	Job       string    `json:"job"`
+	Group     string    `json:"group"`
+	Task      string    `json:"task"`
+}
+
+// httpClient POSTs each batch of lines as a JSON array to the address of an
+// HTTP sink. Responses with a 429 or 503 status code are retried after the
+// delay of their Retry-After header.
+type httpClient struct {
+	address string
+	headers map[string]string
+	meta    *LogMetadata
+	client  *http.Client
+}
+
+func newHTTPClient(sink *structs.LogSink, meta *LogMetadata) (*httpClient, error) {
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = httpSinkTimeout
+
+	return &httpClient{
+		address: sink.Address,
+		headers: sink.Headers,
+		meta:    meta,
+		client:  client,
+	}, nil
+}
+
+func (c *httpClient) Send(ctx context.Context, lines []*LogLine) error {
+	batch := make([]*httpLogLine, len(lines))
+	for i, line := range lines {
+		batch[i] = &httpLogLine{
+			Time:      line.Time,
+			Stream:    line.Stream,
+			Message:   line.Message,
+			AllocID:   c.meta.AllocID,
+			Namespace: c.meta.Namespace,
+			Job:       c.meta.JobID,
+			Group:     c.meta.Group,
+			Task:      c.meta.Task,
+		}
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode log lines: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		err := fmt.Errorf("log sink is unavailable: %d", resp.StatusCode)
+		if secs, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil && secs > 0 {
+			return &retryAfterError{err: err, delay: time.Duration(secs) * time.Second}
+		}
+		return err
+	default:
+		return fmt.Errorf("unexpected response code from log sink: %d", resp.StatusCode)
+	}
+}
+
+func (c *httpClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
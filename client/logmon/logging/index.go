@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// logIndexInterval is the minimum interval between two entries of the
+	// index of a log file.
+	logIndexInterval = 1 * time.Second
+)
+
+// LogIndexEntry is an entry of the index of a log file. It records that the
+// lines starting at Offset were written at or after Time. The lines written
+// before the next entry of the index were written within logIndexInterval of
+// Time.
+type LogIndexEntry struct {
+	Time   time.Time
+	Offset int64
+}
+
+// IndexFileName returns the name of the index file of the log file with the
+// given base file name and index. Index files are hidden so that they aren't
+// mistaken for rotated log files.
+func IndexFileName(baseFileName string, idx int64) string {
+	return fmt.Sprintf(".%s.%d.idx", baseFileName, idx)
+}
+
+// formatIndexEntry returns the encoding of an index entry, which is a line
+// made of the time of the entry in nanoseconds since the epoch and of its
+// offset.
+func formatIndexEntry(t time.Time, offset int64) string {
+	return fmt.Sprintf("%d %d\n", t.UnixNano(), offset)
+}
+
+// ParseLogIndex parses the entries of a log file index. Malformed entries and
+// an entry that is still being written are skipped.
+func ParseLogIndex(r io.Reader) ([]*LogIndexEntry, error) {
+	var entries []*LogIndexEntry
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		nanos, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		offset, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || offset < 0 {
+			continue
+		}
+		entries = append(entries, &LogIndexEntry{
+			Time:   time.Unix(0, nanos),
+			Offset: offset,
+		})
+	}
+}
@@ -45,9 +45,14 @@ type FileRotator struct {
 
 	currentFile *os.File // currentFile is the file that is currently getting written
 	currentWr   int64    // currentWr is the number of bytes written to the current file
+	lineStart   int64    // lineStart is the offset of the line being written to the current file
 	bufw        *bufio.Writer
 	bufLock     sync.Mutex
 
+	indexFile     *os.File      // indexFile is the index of the current file
+	indexInterval time.Duration // indexInterval is the minimum interval between two entries of an index
+	lastIndexed   time.Time     // lastIndexed is the time of the last entry of the index of the current file
+
 	flushTicker *time.Ticker
 	logger      hclog.Logger
 	purgeCh     chan struct{}
@@ -65,8 +70,9 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		MaxFiles: maxFiles,
 		FileSize: fileSize,
 
-		path:         path,
-		baseFileName: baseFile,
+		path:          path,
+		baseFileName:  baseFile,
+		indexInterval: logIndexInterval,
 
 		flushTicker: time.NewTicker(bufferFlushDuration),
 		logger:      logger,
@@ -114,7 +120,7 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 			idx := bytes.IndexByte(p[n:], newLineDelimiter)
 			if idx >= 0 && (remainingSpace-int64(idx)-1) >= 0 {
 				// We have space so write it to buffer
-				nw, err = f.writeToBuffer(p[n : n+idx+1])
+				nw, err = f.writeIndexed(p[n : n+idx+1])
 			} else if idx >= 0 {
 				// We found a new line but don't have space so just force rotate
 				forceRotate = true
@@ -126,7 +132,7 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 				if remainingSpace > remainingToWrite {
 					li = int64(n) + remainingToWrite
 				}
-				nw, err = f.writeToBuffer(p[n:li])
+				nw, err = f.writeIndexed(p[n:li])
 			} else {
 				// There is no new line in the data remaining for us to write
 				// and it will fit in the next file so rotate.
@@ -134,7 +140,7 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 			}
 		} else {
 			// Write all the bytes in the current file
-			nw, err = f.writeToBuffer(p[n:])
+			nw, err = f.writeIndexed(p[n:])
 		}
 
 		// Increment the number of bytes written so far in this method
@@ -142,7 +148,7 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 		n += nw
 
 		// Increment the total number of bytes in the file
-		f.currentWr += int64(n)
+		f.currentWr += int64(nw)
 		if err != nil {
 			f.logger.Error("error writing to file", "err", err)
 
@@ -230,10 +236,48 @@ func (f *FileRotator) createFile() error {
 		return err
 	}
 	f.currentWr = fi.Size()
+	f.lineStart = f.currentWr
 	f.createOrResetBuffer()
+	f.openIndexFile()
 	return nil
 }
 
+// openIndexFile opens the index of the current file. Logs are still written
+// if the index can't be opened, but they can't be searched by time.
+func (f *FileRotator) openIndexFile() {
+	if f.indexFile != nil {
+		f.indexFile.Close()
+		f.indexFile = nil
+	}
+	f.lastIndexed = time.Time{}
+
+	indexFileName := filepath.Join(f.path, IndexFileName(f.baseFileName, int64(f.logFileIdx)))
+	iFile, err := os.OpenFile(indexFileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		f.logger.Warn("error opening log index", "filename", indexFileName, "err", err)
+		return
+	}
+	f.indexFile = iFile
+}
+
+// writeIndexed writes the byte array to buffer, recording the offset of the
+// line being written in the index of the current file if the last entry of
+// the index is older than the index interval.
+func (f *FileRotator) writeIndexed(p []byte) (int, error) {
+	if now := time.Now(); f.indexFile != nil && now.Sub(f.lastIndexed) >= f.indexInterval {
+		f.lastIndexed = now
+		if _, err := f.indexFile.WriteString(formatIndexEntry(now, f.lineStart)); err != nil {
+			f.logger.Warn("error writing to log index", "err", err)
+		}
+	}
+
+	nw, err := f.writeToBuffer(p)
+	if idx := bytes.LastIndexByte(p[:nw], newLineDelimiter); idx >= 0 {
+		f.lineStart = f.currentWr + int64(idx) + 1
+	}
+	return nw, err
+}
+
 // flushPeriodically flushes the buffered writer every 100ms to the underlying
 // file
 func (f *FileRotator) flushPeriodically() {
@@ -263,6 +307,9 @@ func (f *FileRotator) Close() error {
 		close(f.purgeCh)
 		f.closed = true
 		f.currentFile.Close()
+		if f.indexFile != nil {
+			f.indexFile.Close()
+		}
 	}
 
 	return nil
@@ -309,6 +356,10 @@ func (f *FileRotator) purgeOldFiles() {
 				if err != nil {
 					f.logger.Error("error removing file", "filename", fname, "err", err)
 				}
+				iname := filepath.Join(f.path, IndexFileName(f.baseFileName, int64(fIndex)))
+				if err := os.Remove(iname); err != nil && !os.IsNotExist(err) {
+					f.logger.Error("error removing file", "filename", iname, "err", err)
+				}
 			}
 			f.oldestLogFileIdx = fIndexes[0]
 		case <-f.doneCh:
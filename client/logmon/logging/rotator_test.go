@@ -6,7 +6,9 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/testutil"
@@ -263,8 +265,21 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 			return false, fmt.Errorf("failed to read dir %v: %w", path, err)
 		}
 
-		if len(f) != 2 {
-			return false, fmt.Errorf("expected number of files: %v, got: %v %v", 2, len(f), f)
+		// The index of each file is purged along with it
+		var logFiles, indexFiles int
+		for _, fi := range f {
+			if strings.HasSuffix(fi.Name(), ".idx") {
+				indexFiles++
+			} else {
+				logFiles++
+			}
+		}
+
+		if logFiles != 2 {
+			return false, fmt.Errorf("expected number of files: %v, got: %v %v", 2, logFiles, f)
+		}
+		if indexFiles != 2 {
+			return false, fmt.Errorf("expected number of index files: %v, got: %v %v", 2, indexFiles, f)
 		}
 
 		return true, nil
@@ -273,6 +288,59 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 	})
 }
 
+func TestFileRotator_Index(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	fr, err := NewFileRotator(path, baseFileName, 10, 20, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer fr.Close()
+	fr.indexInterval = 0
+
+	// Each entry records the offset of the line being written
+	start := time.Now()
+	for _, s := range []string{"line 1\nline 2\npart", "ial\n", "line 4\n"} {
+		_, err = fr.Write([]byte(s))
+		require.NoError(t, err)
+	}
+
+	readIndex := func(idx int64) []*LogIndexEntry {
+		f, err := os.Open(filepath.Join(path, IndexFileName(baseFileName, idx)))
+		require.NoError(t, err)
+		defer f.Close()
+
+		entries, err := ParseLogIndex(f)
+		require.NoError(t, err)
+		return entries
+	}
+
+	offsets := func(entries []*LogIndexEntry) []int64 {
+		var offsets []int64
+		for _, e := range entries {
+			require.False(t, e.Time.Before(start))
+			offsets = append(offsets, e.Offset)
+		}
+		return offsets
+	}
+
+	// The end of the partial line didn't fit in the first file so the index
+	// of the second file starts at its beginning
+	require.Equal(t, []int64{0, 7, 14}, offsets(readIndex(0)))
+	require.Equal(t, []int64{0, 4}, offsets(readIndex(1)))
+}
+
+func TestParseLogIndex(t *testing.T) {
+	// Malformed entries and the entry being written are skipped
+	index := "1654086615000000000 0\nfoo\n1654086616000000000 120\n16540866"
+	entries, err := ParseLogIndex(strings.NewReader(index))
+	require.NoError(t, err)
+	require.Equal(t, []*LogIndexEntry{
+		{Time: time.Unix(1654086615, 0), Offset: 0},
+		{Time: time.Unix(1654086616, 0), Offset: 120},
+	}, entries)
+}
+
 func BenchmarkRotator(b *testing.B) {
 	kb := 1024
 	for _, inputSize := range []int{kb, 2 * kb, 4 * kb, 8 * kb, 16 * kb, 32 * kb, 64 * kb, 128 * kb, 256 * kb} {
@@ -0,0 +1,303 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// shipperBaseBackoff and shipperMaxBackoff bound the exponential back off
+	// applied when lines fail to be shipped to a sink.
+	shipperBaseBackoff = 1 * time.Second
+	shipperMaxBackoff  = 30 * time.Second
+
+	// shipperCloseTimeout is the time given to a shipper to ship its
+	// buffered lines when it is closed.
+	shipperCloseTimeout = 5 * time.Second
+)
+
+// LogLine is a line of the stdout or stderr stream of a task.
+type LogLine struct {
+	// Time is the time the line was read from the task.
+	Time time.Time
+
+	// Stream is the name of the stream of the line, stdout or stderr.
+	Stream string
+
+	// Message is the content of the line, without the trailing new line.
+	Message string
+}
+
+// LogMetadata identifies the task that the shipped lines are tagged with.
+type LogMetadata struct {
+	AllocID   string
+	Namespace string
+	JobID     string
+	Group     string
+	Task      string
+}
+
+// sinkClient delivers batches of lines to the destination of a sink.
+type sinkClient interface {
+	// Send delivers the lines to the sink. The lines are sent again if an
+	// error is returned.
+	Send(ctx context.Context, lines []*LogLine) error
+
+	// Close releases the resources of the client.
+	Close() error
+}
+
+// retryAfterError is returned by sink clients when the sink asks for the
+// lines to be sent again after a delay.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+// Shipper ships the lines of a task log to a sink, in batches. Lines are
+// buffered while the sink is slow or unavailable and dropped once the buffer
+// is full, so that a sink never blocks the task or its local log files.
+type Shipper struct {
+	sink   *structs.LogSink
+	client sinkClient
+	logger hclog.Logger
+
+	batchSize int
+	batchWait time.Duration
+
+	// baseBackoff and maxBackoff bound the back off between retries.
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+
+	// linesCh buffers the lines until they are shipped.
+	linesCh chan *LogLine
+
+	// dropped is the number of lines dropped since the last warning. It must
+	// be accessed atomically.
+	dropped uint64
+
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewShipper returns a shipper delivering lines tagged with the task
+// metadata to the given sink.
+func NewShipper(sink *structs.LogSink, meta *LogMetadata, logger hclog.Logger) (*Shipper, error) {
+	var client sinkClient
+	var err error
+	switch sink.Type {
+	case structs.LogSinkTypeSyslog:
+		client, err = newSyslogClient(sink, meta)
+	case structs.LogSinkTypeFluent:
+		client, err = newFluentClient(sink, meta)
+	case structs.LogSinkTypeHTTP:
+		client, err = newHTTPClient(sink, meta)
+	default:
+		err = fmt.Errorf("unsupported log sink type %q", sink.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := newShipper(sink, client, logger)
+	go s.run()
+	return s, nil
+}
+
+func newShipper(sink *structs.LogSink, client sinkClient, logger hclog.Logger) *Shipper {
+	s := &Shipper{
+		sink:        sink,
+		client:      client,
+		logger:      logger.Named("shipper").With("sink_type", sink.Type, "sink_address", sink.Address),
+		batchSize:   sink.BatchSize,
+		batchWait:   sink.BatchWait,
+		baseBackoff: shipperBaseBackoff,
+		maxBackoff:  shipperMaxBackoff,
+		stopCh:      make(chan struct{}),
+		doneCh:      make(chan struct{}),
+	}
+
+	if s.batchSize <= 0 {
+		s.batchSize = structs.DefaultLogSinkBatchSize
+	}
+	if s.batchWait <= 0 {
+		s.batchWait = structs.DefaultLogSinkBatchWait
+	}
+	bufferSize := sink.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = structs.DefaultLogSinkBufferSize
+	}
+	s.linesCh = make(chan *LogLine, bufferSize)
+	return s
+}
+
+// Ship queues the line to be shipped to the sink. It never blocks: the line
+// is dropped if the buffer of the shipper is full.
+func (s *Shipper) Ship(line *LogLine) {
+	select {
+	case <-s.stopCh:
+		return
+	default:
+	}
+
+	select {
+	case s.linesCh <- line:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Close stops the shipper once its buffered lines are shipped, or once the
+// close timeout is reached.
+func (s *Shipper) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stopCh)
+		<-s.doneCh
+		if err := s.client.Close(); err != nil {
+			s.logger.Warn("failed to close log sink client", "error", err)
+		}
+	})
+}
+
+// run is the long lived go-routine that batches the lines and ships them.
+func (s *Shipper) run() {
+	defer close(s.doneCh)
+
+	// ctx is cancelled once the close timeout is reached after the shipper
+	// is closed, to give up on the lines that can't be shipped.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.doneCh:
+			return
+		case <-s.stopCh:
+		}
+
+		timer, stop := helper.NewSafeTimer(shipperCloseTimeout)
+		defer stop()
+		select {
+		case <-s.doneCh:
+		case <-timer.C:
+			cancel()
+		}
+	}()
+
+	timer, stop := helper.NewSafeTimer(s.batchWait)
+	defer stop()
+
+	batch := make([]*LogLine, 0, s.batchSize)
+	for {
+		select {
+		case line := <-s.linesCh:
+			if len(batch) == 0 {
+				timer.Reset(s.batchWait)
+			}
+			batch = append(batch, line)
+			if len(batch) < s.batchSize {
+				continue
+			}
+		case <-timer.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-s.stopCh:
+			s.drain(ctx, batch)
+			return
+		}
+
+		if !s.ship(ctx, batch) {
+			return
+		}
+		batch = make([]*LogLine, 0, s.batchSize)
+	}
+}
+
+// drain ships the batch and the lines that are still buffered once the
+// shipper is closed.
+func (s *Shipper) drain(ctx context.Context, batch []*LogLine) {
+	for {
+		select {
+		case line := <-s.linesCh:
+			batch = append(batch, line)
+			if len(batch) < s.batchSize {
+				continue
+			}
+		default:
+			if len(batch) > 0 {
+				s.ship(ctx, batch)
+			}
+			return
+		}
+
+		if !s.ship(ctx, batch) {
+			return
+		}
+		batch = make([]*LogLine, 0, s.batchSize)
+	}
+}
+
+// ship sends the batch to the sink, retrying until it is delivered. It
+// returns false if the context was cancelled before the batch was delivered.
+func (s *Shipper) ship(ctx context.Context, batch []*LogLine) bool {
+	failures := 0
+	for {
+		err := s.client.Send(ctx, batch)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			s.logger.Warn("dropping log lines that could not be shipped", "lines", len(batch), "error", err)
+			return false
+		}
+
+		backoff := (1 << failures) * s.baseBackoff
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		} else {
+			failures++
+		}
+
+		var retryErr *retryAfterError
+		if errors.As(err, &retryErr) && retryErr.delay > backoff {
+			backoff = retryErr.delay
+		}
+
+		s.logger.Warn("failed to ship log lines", "lines", len(batch), "retry", backoff, "error", err)
+		if !s.wait(ctx, backoff) {
+			return false
+		}
+	}
+
+	if dropped := atomic.SwapUint64(&s.dropped, 0); dropped > 0 {
+		s.logger.Warn("dropped log lines while the log sink was unavailable", "lines", dropped)
+	}
+	return true
+}
+
+// wait waits for the given duration. It returns false if the context was
+// cancelled while waiting.
+func (s *Shipper) wait(ctx context.Context, d time.Duration) bool {
+	timer, stop := helper.NewSafeTimer(d)
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
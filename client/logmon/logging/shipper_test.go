@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSinkClient records the batches it receives. It fails to send batches
+// while failures is positive and blocks while blockCh is open.
+type mockSinkClient struct {
+	batches  [][]string
+	failures int
+	blockCh  chan struct{}
+	closed   bool
+	l        sync.Mutex
+}
+
+func (c *mockSinkClient) Send(ctx context.Context, lines []*LogLine) error {
+	c.l.Lock()
+	blockCh := c.blockCh
+	c.l.Unlock()
+	if blockCh != nil {
+		select {
+		case <-blockCh:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	c.l.Lock()
+	defer c.l.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("sink unavailable")
+	}
+
+	var batch []string
+	for _, line := range lines {
+		batch = append(batch, line.Message)
+	}
+	c.batches = append(c.batches, batch)
+	return nil
+}
+
+func (c *mockSinkClient) Close() error {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *mockSinkClient) received() [][]string {
+	c.l.Lock()
+	defer c.l.Unlock()
+	return append([][]string(nil), c.batches...)
+}
+
+func testShipper(t *testing.T, sink *structs.LogSink, client *mockSinkClient) *Shipper {
+	s := newShipper(sink, client, testlog.HCLogger(t))
+	s.baseBackoff = 10 * time.Millisecond
+	s.maxBackoff = 10 * time.Millisecond
+	go s.run()
+	t.Cleanup(s.Close)
+	return s
+}
+
+func shipLines(s *Shipper, from, to int) {
+	for i := from; i < to; i++ {
+		s.Ship(&LogLine{Time: time.Now(), Stream: "stdout", Message: fmt.Sprintf("line %d", i)})
+	}
+}
+
+func TestShipper_Batches(t *testing.T) {
+	ci.Parallel(t)
+
+	client := &mockSinkClient{}
+	s := testShipper(t, &structs.LogSink{BatchSize: 2, BatchWait: 50 * time.Millisecond}, client)
+
+	// Full batches are shipped right away, the others once the batch wait
+	// has elapsed
+	shipLines(s, 0, 3)
+	testutil.WaitForResult(func() (bool, error) {
+		received := client.received()
+		return len(received) == 2, fmt.Errorf("received %v", received)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, [][]string{{"line 0", "line 1"}, {"line 2"}}, client.received())
+}
+
+func TestShipper_Retry(t *testing.T) {
+	ci.Parallel(t)
+
+	client := &mockSinkClient{failures: 2}
+	s := testShipper(t, &structs.LogSink{BatchSize: 2}, client)
+
+	shipLines(s, 0, 2)
+	testutil.WaitForResult(func() (bool, error) {
+		received := client.received()
+		return len(received) == 1, fmt.Errorf("received %v", received)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, [][]string{{"line 0", "line 1"}}, client.received())
+}
+
+func TestShipper_DropsWhenFull(t *testing.T) {
+	ci.Parallel(t)
+
+	client := &mockSinkClient{blockCh: make(chan struct{})}
+	s := testShipper(t, &structs.LogSink{BatchSize: 1, BufferSize: 2}, client)
+
+	// The first line is being shipped while the next two are buffered, so
+	// the following lines are dropped without blocking
+	shipLines(s, 0, 1)
+	testutil.WaitForResult(func() (bool, error) {
+		return len(s.linesCh) == 0, fmt.Errorf("line not being shipped")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	shipLines(s, 1, 10)
+	close(client.blockCh)
+
+	testutil.WaitForResult(func() (bool, error) {
+		received := client.received()
+		return len(received) == 3, fmt.Errorf("received %v", received)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, [][]string{{"line 0"}, {"line 1"}, {"line 2"}}, client.received())
+}
+
+func TestShipper_Close(t *testing.T) {
+	ci.Parallel(t)
+
+	client := &mockSinkClient{}
+	s := testShipper(t, &structs.LogSink{BatchSize: 2, BatchWait: time.Hour}, client)
+
+	// The buffered lines are shipped when the shipper is closed
+	shipLines(s, 0, 3)
+	s.Close()
+	require.Equal(t, [][]string{{"line 0", "line 1"}, {"line 2"}}, client.received())
+	require.True(t, client.closed)
+
+	// Lines shipped after the shipper is closed are ignored
+	shipLines(s, 3, 4)
+	require.Len(t, s.linesCh, 0)
+}
@@ -0,0 +1,209 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+var testMetadata = &LogMetadata{
+	AllocID:   "8ba85cef-26cc-40d5-8d40-25ed5d5bb3d1",
+	Namespace: "default",
+	JobID:     "web",
+	Group:     "frontend",
+	Task:      "server",
+}
+
+func testLines() []*LogLine {
+	now := time.Date(2022, 6, 1, 12, 30, 15, 123456000, time.UTC)
+	return []*LogLine{
+		{Time: now, Stream: "stdout", Message: "hello"},
+		{Time: now, Stream: "stderr", Message: `bad "request"`},
+	}
+}
+
+func TestSyslogClient_UDP(t *testing.T) {
+	ci.Parallel(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client, err := newSyslogClient(&structs.LogSink{
+		Type:    structs.LogSinkTypeSyslog,
+		Address: "udp://" + conn.LocalAddr().String(),
+		Tag:     "web",
+	}, testMetadata)
+	require.NoError(t, err)
+	defer client.Close()
+	client.hostname = "node1"
+
+	require.NoError(t, client.Send(context.Background(), testLines()))
+
+	// Each line is sent as a datagram
+	buf := make([]byte, 1024)
+	var messages []string
+	for i := 0; i < 2; i++ {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		messages = append(messages, string(buf[:n]))
+	}
+
+	sd := `[nomad@32473 alloc_id="8ba85cef-26cc-40d5-8d40-25ed5d5bb3d1" namespace="default" job="web" group="frontend" task="server"]`
+	require.Equal(t, []string{
+		`<14>1 2022-06-01T12:30:15.123456Z node1 web - stdout ` + sd + ` hello`,
+		`<11>1 2022-06-01T12:30:15.123456Z node1 web - stderr ` + sd + ` bad "request"`,
+	}, messages)
+}
+
+func TestSyslogClient_TCP(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	client, err := newSyslogClient(&structs.LogSink{
+		Type:    structs.LogSinkTypeSyslog,
+		Address: "tcp://" + ln.Addr().String(),
+	}, testMetadata)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send(context.Background(), testLines()))
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// Messages are framed using octet counting
+	r := bufio.NewReader(conn)
+	for _, stream := range []string{"stdout", "stderr"} {
+		size, err := r.ReadString(' ')
+		require.NoError(t, err)
+		n, err := strconv.Atoi(strings.TrimSpace(size))
+		require.NoError(t, err)
+
+		msg := make([]byte, n)
+		_, err = io.ReadFull(r, msg)
+		require.NoError(t, err)
+		require.Contains(t, string(msg), " nomad - "+stream+" [nomad@32473 ")
+	}
+}
+
+func TestFluentClient(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	client, err := newFluentClient(&structs.LogSink{
+		Type:    structs.LogSinkTypeFluent,
+		Address: ln.Addr().String(),
+		Tag:     "nomad.web",
+	}, testMetadata)
+	require.NoError(t, err)
+	defer client.Close()
+
+	lines := testLines()
+	require.NoError(t, client.Send(context.Background(), lines))
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// The lines are sent in forward mode, with their event time
+	var msg struct {
+		_struct bool `codec:",toarray"`
+		Tag     string
+		Entries []struct {
+			_struct bool `codec:",toarray"`
+			Time    fluentEventTime
+			Record  map[string]string
+		}
+	}
+	handle := &codec.MsgpackHandle{WriteExt: true}
+	handle.RawToString = true
+	require.NoError(t, handle.SetBytesExt(reflect.TypeOf(fluentEventTime{}), 0, fluentEventTimeExt{}))
+	require.NoError(t, codec.NewDecoder(conn, handle).Decode(&msg))
+
+	require.Equal(t, "nomad.web", msg.Tag)
+	require.Len(t, msg.Entries, 2)
+	require.True(t, lines[0].Time.Equal(time.Time(msg.Entries[0].Time)))
+	require.Equal(t, map[string]string{
+		"log":       `bad "request"`,
+		"stream":    "stderr",
+		"alloc_id":  "8ba85cef-26cc-40d5-8d40-25ed5d5bb3d1",
+		"namespace": "default",
+		"job":       "web",
+		"group":     "frontend",
+		"task":      "server",
+	}, msg.Entries[1].Record)
+}
+
+func TestHTTPClient(t *testing.T) {
+	ci.Parallel(t)
+
+	var status int
+	var received []httpLogLine
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		var lines []httpLogLine
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&lines))
+		received = append(received, lines...)
+
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	client, err := newHTTPClient(&structs.LogSink{
+		Type:    structs.LogSinkTypeHTTP,
+		Address: ts.URL,
+		Headers: map[string]string{"X-Token": "secret"},
+	}, testMetadata)
+	require.NoError(t, err)
+	defer client.Close()
+
+	status = http.StatusOK
+	require.NoError(t, client.Send(context.Background(), testLines()))
+	require.Len(t, received, 2)
+	require.Equal(t, "hello", received[0].Message)
+	require.Equal(t, "stderr", received[1].Stream)
+	require.Equal(t, "server", received[1].Task)
+	require.Equal(t, testMetadata.AllocID, received[1].AllocID)
+
+	// Throttled requests are retried after the delay of the sink
+	status = http.StatusTooManyRequests
+	err = client.Send(context.Background(), testLines())
+	require.EqualError(t, err, "log sink is unavailable: 429")
+	retryErr, ok := err.(*retryAfterError)
+	require.True(t, ok, fmt.Sprintf("unexpected error type %T", err))
+	require.Equal(t, 5*time.Second, retryErr.delay)
+
+	status = http.StatusBadRequest
+	err = client.Send(context.Background(), testLines())
+	require.EqualError(t, err, "unexpected response code from log sink: 400")
+}
@@ -0,0 +1,196 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// syslogFacilityUser is the syslog facility of the shipped lines.
+	syslogFacilityUser = 1
+
+	// syslogSeverityInfo and syslogSeverityError are the syslog severities
+	// of the lines of the stdout and stderr streams.
+	syslogSeverityInfo  = 6
+	syslogSeverityError = 3
+
+	// syslogStructuredDataID is the ID of the structured data element the
+	// shipped lines are tagged with.
+	syslogStructuredDataID = "nomad@32473"
+
+	// syslogTimestampFormat is the RFC5424 timestamp format, with the
+	// maximum precision it allows.
+	syslogTimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+	// sinkDialTimeout is the timeout to connect to a sink.
+	sinkDialTimeout = 10 * time.Second
+)
+
+// syslogClient ships lines as RFC5424 syslog messages over TCP, UDP or a unix
+// socket. Messages sent over a stream connection are framed using octet
+// counting as described in RFC6587.
+type syslogClient struct {
+	network  string
+	address  string
+	hostname string
+	appName  string
+
+	// structuredData is the structured data element of the messages, which
+	// tags them with the task metadata.
+	structuredData string
+
+	conn   net.Conn
+	stream bool
+}
+
+func newSyslogClient(sink *structs.LogSink, meta *LogMetadata) (*syslogClient, error) {
+	u, err := url.Parse(sink.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %v", sink.Address, err)
+	}
+
+	c := &syslogClient{
+		network: u.Scheme,
+		appName: syslogValue(sink.Tag, 48),
+	}
+	switch u.Scheme {
+	case "tcp", "udp":
+		c.address = u.Host
+	case "unix":
+		c.address = u.Path
+	default:
+		return nil, fmt.Errorf("unsupported syslog address scheme %q", u.Scheme)
+	}
+
+	if c.appName == "-" {
+		c.appName = "nomad"
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		c.hostname = syslogValue(hostname, 255)
+	} else {
+		c.hostname = "-"
+	}
+
+	c.structuredData = fmt.Sprintf(`[%s alloc_id="%s" namespace="%s" job="%s" group="%s" task="%s"]`,
+		syslogStructuredDataID,
+		syslogParamValue(meta.AllocID),
+		syslogParamValue(meta.Namespace),
+		syslogParamValue(meta.JobID),
+		syslogParamValue(meta.Group),
+		syslogParamValue(meta.Task))
+
+	return c, nil
+}
+
+// connect connects to the syslog server if the client isn't connected. Unix
+// sockets are connected to as datagram sockets first, and as stream sockets
+// otherwise.
+func (c *syslogClient) connect(ctx context.Context) error {
+	if c.conn != nil {
+		return nil
+	}
+
+	dialer := &net.Dialer{Timeout: sinkDialTimeout}
+	switch c.network {
+	case "unix":
+		conn, err := dialer.DialContext(ctx, "unixgram", c.address)
+		if err == nil {
+			c.conn, c.stream = conn, false
+			return nil
+		}
+		conn, err = dialer.DialContext(ctx, "unix", c.address)
+		if err != nil {
+			return err
+		}
+		c.conn, c.stream = conn, true
+	default:
+		conn, err := dialer.DialContext(ctx, c.network, c.address)
+		if err != nil {
+			return err
+		}
+		c.conn, c.stream = conn, c.network == "tcp"
+	}
+	return nil
+}
+
+func (c *syslogClient) Send(ctx context.Context, lines []*LogLine) error {
+	if err := c.connect(ctx); err != nil {
+		return fmt.Errorf("failed to connect to syslog server: %v", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+	} else {
+		c.conn.SetWriteDeadline(time.Now().Add(sinkDialTimeout))
+	}
+
+	for len(lines) > 0 {
+		msg := c.format(lines[0])
+		if c.stream {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		if _, err := c.conn.Write([]byte(msg)); err != nil {
+			// Reconnect when the lines are sent again
+			c.conn.Close()
+			c.conn = nil
+			return fmt.Errorf("failed to write to syslog server: %v", err)
+		}
+		lines = lines[1:]
+	}
+	return nil
+}
+
+// format returns the RFC5424 syslog message of the line.
+func (c *syslogClient) format(line *LogLine) string {
+	severity := syslogSeverityInfo
+	if line.Stream == "stderr" {
+		severity = syslogSeverityError
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s",
+		syslogFacilityUser*8+severity,
+		line.Time.UTC().Format(syslogTimestampFormat),
+		c.hostname,
+		c.appName,
+		syslogValue(line.Stream, 32),
+		c.structuredData,
+		line.Message)
+}
+
+func (c *syslogClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// syslogValue returns the value of a syslog header field, which is made of
+// at most max printable ASCII characters, or "-" if the value is empty.
+func syslogValue(value string, max int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+
+	if len(value) > max {
+		value = value[:max]
+	}
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// syslogParamValue escapes the value of a structured data parameter.
+func syslogParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
@@ -1,6 +1,7 @@
 package logmon
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -11,6 +12,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/lib/fifo"
 	"github.com/hashicorp/nomad/client/logmon/logging"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 const (
@@ -18,6 +20,10 @@ const (
 	// launched process to close its stdout/stderr before we force close it. If
 	// data is written after this tolerance, we will not capture it.
 	processOutputCloseTolerance = 2 * time.Second
+
+	// maxShippedLineSize is the maximum size of the lines shipped to the log
+	// sinks. Longer lines are split.
+	maxShippedLineSize = 64 * 1024
 )
 
 type LogConfig struct {
@@ -41,6 +47,14 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// Metadata identifies the task the lines shipped to the sinks are tagged
+	// with
+	Metadata *logging.LogMetadata
+
+	// Sinks are the external destinations the log lines are shipped to, in
+	// addition to the rotated files
+	Sinks []*structs.LogSink
 }
 
 type LogMon interface {
@@ -106,6 +120,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// shippers ship the log lines to the sinks
+	shippers []*logging.Shipper
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -133,20 +150,52 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// Ship the remaining lines once the streams are closed
+	tl.closeShippers()
+}
+
+// closeShippers closes the shippers once they shipped their remaining lines.
+func (tl *TaskLogger) closeShippers() {
+	var wg sync.WaitGroup
+	for _, shipper := range tl.shippers {
+		wg.Add(1)
+		go func(shipper *logging.Shipper) {
+			shipper.Close()
+			wg.Done()
+		}(shipper)
+	}
+	wg.Wait()
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
+	meta := cfg.Metadata
+	if meta == nil {
+		meta = &logging.LogMetadata{}
+	}
+	for _, sink := range cfg.Sinks {
+		shipper, err := logging.NewShipper(sink, meta, logger)
+		if err != nil {
+			tl.closeShippers()
+			return nil, fmt.Errorf("failed to create %s log sink: %v", sink.Type, err)
+		}
+		tl.shippers = append(tl.shippers, shipper)
+	}
+
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
 	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeShippers()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger,
+		newShippingWriter(lro, "stdout", tl.shippers))
 	if err != nil {
+		tl.closeShippers()
 		return nil, err
 	}
 
@@ -155,11 +204,14 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeShippers()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger,
+		newShippingWriter(lre, "stderr", tl.shippers))
 	if err != nil {
+		tl.closeShippers()
 		return nil, err
 	}
 
@@ -294,3 +346,79 @@ func (l *logRotatorWrapper) Close() {
 
 	l.rotatorWriter.Close()
 }
+
+// shippingWriter writes the output of a task to its rotator and ships each
+// of its lines to the sinks of the task.
+type shippingWriter struct {
+	rotator  io.WriteCloser
+	stream   string
+	shippers []*logging.Shipper
+
+	// partial is the last line written, until its new line is written
+	partial []byte
+	lock    sync.Mutex
+}
+
+// newShippingWriter returns a writer that writes to the rotator and ships
+// the lines of the stream to the shippers, if there are any.
+func newShippingWriter(rotator io.WriteCloser, stream string, shippers []*logging.Shipper) io.WriteCloser {
+	if len(shippers) == 0 {
+		return rotator
+	}
+
+	return &shippingWriter{
+		rotator:  rotator,
+		stream:   stream,
+		shippers: shippers,
+	}
+}
+
+func (w *shippingWriter) Write(p []byte) (int, error) {
+	n, err := w.rotator.Write(p)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	data := append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		w.ship(data[:i])
+		data = data[i+1:]
+	}
+
+	// Split the lines that are too long, so that a task writing without new
+	// lines can't grow the partial line indefinitely
+	for len(data) >= maxShippedLineSize {
+		w.ship(data[:maxShippedLineSize])
+		data = data[maxShippedLineSize:]
+	}
+	w.partial = append([]byte(nil), data...)
+
+	return n, err
+}
+
+// ship ships the line to the shippers.
+func (w *shippingWriter) ship(line []byte) {
+	l := &logging.LogLine{
+		Time:    time.Now(),
+		Stream:  w.stream,
+		Message: string(bytes.TrimSuffix(line, []byte{'\r'})),
+	}
+	for _, shipper := range w.shippers {
+		shipper.Ship(l)
+	}
+}
+
+func (w *shippingWriter) Close() error {
+	w.lock.Lock()
+	if len(w.partial) > 0 {
+		w.ship(w.partial)
+		w.partial = nil
+	}
+	w.lock.Unlock()
+
+	return w.rotator.Close()
+}
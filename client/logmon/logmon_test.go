@@ -2,17 +2,24 @@ package logmon
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -75,6 +82,105 @@ func TestLogmon_Start_rotate(t *testing.T) {
 	require.NoError(lm.Stop())
 }
 
+// asserts that the log lines are shipped to the sinks, in addition to being
+// written to the rotated files.
+func TestLogmon_Start_sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	require := require.New(t)
+	var stdoutFifoPath, stderrFifoPath string
+
+	dir := t.TempDir()
+
+	if runtime.GOOS == "windows" {
+		stdoutFifoPath = "//./pipe/test-sinks.stdout"
+		stderrFifoPath = "//./pipe/test-sinks.stderr"
+	} else {
+		stdoutFifoPath = filepath.Join(dir, "stdout.fifo")
+		stderrFifoPath = filepath.Join(dir, "stderr.fifo")
+	}
+
+	var lock sync.Mutex
+	var received []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var lines []map[string]interface{}
+		require.NoError(json.NewDecoder(r.Body).Decode(&lines))
+
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, lines...)
+	}))
+	defer ts.Close()
+
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    stdoutFifoPath,
+		StderrLogFile: "stderr",
+		StderrFifo:    stderrFifoPath,
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		Metadata: &logging.LogMetadata{
+			AllocID: uuid.Generate(),
+			Task:    "web",
+		},
+		Sinks: []*structs.LogSink{{
+			Type:      structs.LogSinkTypeHTTP,
+			Address:   ts.URL,
+			BatchWait: 10 * time.Millisecond,
+		}},
+	}
+
+	lm := NewLogMon(testlog.HCLogger(t))
+	require.NoError(lm.Start(cfg))
+
+	stdout, err := fifo.OpenWriter(stdoutFifoPath)
+	require.NoError(err)
+	stderr, err := fifo.OpenWriter(stderrFifoPath)
+	require.NoError(err)
+
+	// Lines written in several parts are shipped once complete
+	_, err = stdout.Write([]byte("hello\nwor"))
+	require.NoError(err)
+	_, err = stdout.Write([]byte("ld\n"))
+	require.NoError(err)
+	_, err = stderr.Write([]byte("oops\n"))
+	require.NoError(err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(received) == 3, fmt.Errorf("received %v", received)
+	}, func(err error) {
+		require.NoError(err)
+	})
+
+	lock.Lock()
+	messages := make(map[string]string)
+	for _, line := range received {
+		require.Equal(cfg.Metadata.AllocID, line["alloc_id"])
+		require.Equal("web", line["task"])
+		messages[line["message"].(string)] = line["stream"].(string)
+	}
+	lock.Unlock()
+	require.Equal(map[string]string{"hello": "stdout", "world": "stdout", "oops": "stderr"}, messages)
+
+	// The lines are still written to the rotated files
+	testutil.WaitForResult(func() (bool, error) {
+		raw, err := ioutil.ReadFile(filepath.Join(dir, "stdout.0"))
+		if err != nil {
+			return false, err
+		}
+		return "hello\nworld\n" == string(raw), fmt.Errorf("unexpected stdout %q", string(raw))
+	}, func(err error) {
+		require.NoError(err)
+	})
+
+	require.NoError(stdout.Close())
+	require.NoError(stderr.Close())
+	require.NoError(lm.Stop())
+}
+
 // asserts that calling Start twice restarts the log rotator and that any logs
 // published while the listener was unavailable are received.
 func TestLogmon_Start_restart_flusheslogs(t *testing.T) {
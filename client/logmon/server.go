@@ -1,10 +1,14 @@
 package logmon
 
 import (
+	"time"
+
 	"golang.org/x/net/context"
 
 	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	"github.com/hashicorp/nomad/client/logmon/proto"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 type logmonServer struct {
@@ -21,6 +25,14 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 		MaxFileSizeMB: int(req.MaxFileSizeMb),
 		StdoutFifo:    req.StdoutFifo,
 		StderrFifo:    req.StderrFifo,
+		Metadata: &logging.LogMetadata{
+			AllocID:   req.AllocId,
+			Namespace: req.Namespace,
+			JobID:     req.JobId,
+			Group:     req.Group,
+			Task:      req.Task,
+		},
+		Sinks: logSinksFromProto(req.Sinks),
 	}
 
 	err := s.impl.Start(cfg)
@@ -34,3 +46,23 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 func (s *logmonServer) Stop(ctx context.Context, req *proto.StopRequest) (*proto.StopResponse, error) {
 	return &proto.StopResponse{}, s.impl.Stop()
 }
+
+func logSinksFromProto(sinks []*proto.LogSink) []*structs.LogSink {
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	out := make([]*structs.LogSink, len(sinks))
+	for i, sink := range sinks {
+		out[i] = &structs.LogSink{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Tag:        sink.Tag,
+			Headers:    sink.Headers,
+			BatchSize:  int(sink.BatchSize),
+			BatchWait:  time.Duration(sink.BatchWaitNanos),
+			BufferSize: int(sink.BufferSize),
+		}
+	}
+	return out
+}
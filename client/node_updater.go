@@ -274,6 +274,29 @@ func (c *Client) updateNodeFromDriverLocked(name string, info *structs.DriverInf
 	return hasChanged
 }
 
+// updateNodeFromHostVolume adds a dynamic host volume to the node once it has
+// been created, or removes it from the node if the volume is nil. A volume is
+// only removed if it still has the given ID, so that deleting a volume never
+// removes another volume with the same name.
+func (c *Client) updateNodeFromHostVolume(name, id string, vol *structs.ClientHostVolumeConfig) {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	if vol == nil {
+		if existing, ok := c.config.Node.HostVolumes[name]; !ok || existing.ID != id {
+			return
+		}
+		delete(c.config.Node.HostVolumes, name)
+	} else {
+		if c.config.Node.HostVolumes == nil {
+			c.config.Node.HostVolumes = make(map[string]*structs.ClientHostVolumeConfig)
+		}
+		c.config.Node.HostVolumes[name] = vol
+	}
+
+	c.updateNodeLocked()
+}
+
 // updateNodeFromFingerprint updates the node with the result of
 // fingerprinting the node from the diff that was created
 func (c *Client) updateNodeFromDevices(devices []*structs.NodeDeviceResource) {
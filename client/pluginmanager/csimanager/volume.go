@@ -373,3 +373,53 @@ func (v *volumeManager) UnmountVolume(ctx context.Context, volID, remoteID, allo
 
 	return err
 }
+
+// ExpandVolume expands a volume published for an allocation on the node after
+// it has been expanded by the controller plugin, e.g. to resize its
+// filesystem. It returns the new capacity of the volume, which is zero if the
+// plugin did not report it.
+func (v *volumeManager) ExpandVolume(ctx context.Context, volID, remoteID, allocID string, usage *UsageOptions, capacity *csi.CapacityRange) (int64, error) {
+	logger := v.logger.With("volume_id", volID, "alloc_id", allocID)
+	ctx = hclog.WithContext(ctx, logger)
+
+	capability, err := csi.VolumeCapabilityFromStructs(usage.AttachmentMode, usage.AccessMode, usage.MountOptions)
+	if err != nil {
+		return 0, err
+	}
+
+	req := &csi.NodeExpandVolumeRequest{
+		ExternalID:       remoteID,
+		CapacityRange:    capacity,
+		TargetPath:       v.targetForVolume(v.containerMountPoint, volID, allocID, usage),
+		VolumeCapability: capability,
+	}
+	if v.requiresStaging {
+		req.StagingTargetPath = v.stagingDirForVolume(v.containerMountPoint, volID, usage)
+	}
+
+	// CSI NodeExpandVolume errors for timeout, codes.Unavailable and
+	// codes.ResourceExhausted are retried; all other errors are fatal.
+	resp, err := v.plugin.NodeExpandVolume(ctx, req,
+		grpc_retry.WithPerRetryTimeout(DefaultMountActionTimeout),
+		grpc_retry.WithMax(3),
+		grpc_retry.WithBackoff(grpc_retry.BackoffExponential(100*time.Millisecond)),
+	)
+
+	event := structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemStorage).
+		SetMessage("Expand volume").
+		AddDetail("volume_id", volID)
+	if err == nil {
+		event.AddDetail("success", "true")
+	} else {
+		event.AddDetail("success", "false")
+		event.AddDetail("error", err.Error())
+	}
+
+	v.eventer(event)
+
+	if err != nil {
+		return 0, err
+	}
+	return resp.CapacityBytes, nil
+}
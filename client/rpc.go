@@ -24,6 +24,7 @@ type rpcEndpoints struct {
 	FileSystem  *FileSystem
 	Allocations *Allocations
 	Agent       *Agent
+	HostVolume  *HostVolume
 }
 
 // ClientRPC is used to make a local, client only RPC call
@@ -263,6 +264,7 @@ func (c *Client) setupClientRpc(rpcs map[string]interface{}) {
 		c.endpoints.FileSystem = NewFileSystemEndpoint(c)
 		c.endpoints.Allocations = NewAllocationsEndpoint(c)
 		c.endpoints.Agent = NewAgentEndpoint(c)
+		c.endpoints.HostVolume = &HostVolume{c}
 		c.setupClientRpcServer(c.rpcServer)
 	}
 
@@ -277,6 +279,7 @@ func (c *Client) setupClientRpcServer(server *rpc.Server) {
 	server.Register(c.endpoints.FileSystem)
 	server.Register(c.endpoints.Allocations)
 	server.Register(c.endpoints.Agent)
+	server.Register(c.endpoints.HostVolume)
 }
 
 // rpcConnListener is a long lived function that listens for new connections
@@ -0,0 +1,220 @@
+// Package checks implements the health checks which the Nomad client executes
+// on behalf of services registered using the Nomad service provider.
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// maxOutputSize is the maximum number of bytes of an HTTP response body
+	// which will be read and included within a check result output.
+	maxOutputSize = 1024
+)
+
+// Query is everything needed to perform a single execution of a health check.
+type Query struct {
+
+	// Type is the check type and is either structs.ServiceCheckHTTP or
+	// structs.ServiceCheckTCP.
+	Type string
+
+	// Address and Port identify the endpoint which is being checked.
+	Address string
+	Port    int
+
+	// Timeout is the maximum time a single check execution can take before
+	// being considered failed.
+	Timeout time.Duration
+
+	// Protocol, Method, Path, Headers, Body, and TLSSkipVerify are only used
+	// by HTTP checks.
+	Protocol      string
+	Method        string
+	Path          string
+	Headers       map[string][]string
+	Body          string
+	TLSSkipVerify bool
+}
+
+// GetQuery builds the query for the passed service check, which should be
+// executed against the passed address and port.
+func GetQuery(check *structs.ServiceCheck, address string, port int) *Query {
+	return &Query{
+		Type:          check.Type,
+		Address:       address,
+		Port:          port,
+		Timeout:       check.Timeout,
+		Protocol:      check.Protocol,
+		Method:        check.Method,
+		Path:          check.Path,
+		Headers:       check.Header,
+		Body:          check.Body,
+		TLSSkipVerify: check.TLSSkipVerify,
+	}
+}
+
+// Result is the outcome of a single execution of a health check.
+type Result struct {
+
+	// Status is structs.ServiceCheckPassing, structs.ServiceCheckWarning, or
+	// structs.ServiceCheckCritical.
+	Status string
+
+	// Output describes the result in a human-readable form.
+	Output string
+}
+
+// Checker is the interface used to execute health checks.
+type Checker interface {
+	// Do executes the check described by the query and returns its result.
+	// The context can be used to cancel an in-flight check.
+	Do(ctx context.Context, q *Query) *Result
+}
+
+type checker struct {
+	log        hclog.Logger
+	httpClient *http.Client
+	tlsClient  *http.Client
+}
+
+// New returns a Checker which is able to execute HTTP and TCP checks.
+func New(log hclog.Logger) Checker {
+	return &checker{
+		log:        log.Named("checks"),
+		httpClient: &http.Client{},
+		tlsClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		},
+	}
+}
+
+// Do implements the Checker interface.
+func (c *checker) Do(ctx context.Context, q *Query) *Result {
+	ctx, cancel := context.WithTimeout(ctx, q.Timeout)
+	defer cancel()
+
+	switch q.Type {
+	case structs.ServiceCheckHTTP:
+		return c.checkHTTP(ctx, q)
+	case structs.ServiceCheckTCP:
+		return c.checkTCP(ctx, q)
+	default:
+		return &Result{
+			Status: structs.ServiceCheckCritical,
+			Output: fmt.Sprintf("unsupported check type %q", q.Type),
+		}
+	}
+}
+
+// checkTCP passes when a TCP connection can be established to the target.
+func (c *checker) checkTCP(ctx context.Context, q *Query) *Result {
+	addr := net.JoinHostPort(q.Address, strconv.Itoa(q.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return &Result{
+			Status: structs.ServiceCheckCritical,
+			Output: err.Error(),
+		}
+	}
+	_ = conn.Close()
+
+	return &Result{
+		Status: structs.ServiceCheckPassing,
+		Output: fmt.Sprintf("TCP connect %s: success", addr),
+	}
+}
+
+// checkHTTP uses the same semantics as Consul HTTP checks; any 2xx response
+// code is passing, 429 is a warning, and anything else is critical.
+func (c *checker) checkHTTP(ctx context.Context, q *Query) *Result {
+	protocol := q.Protocol
+	if protocol == "" {
+		protocol = "http"
+	}
+
+	u := url.URL{
+		Scheme: protocol,
+		Host:   net.JoinHostPort(q.Address, strconv.Itoa(q.Port)),
+	}
+
+	// The path can include a query string, so parse it and merge the results
+	// into the base URL.
+	if p, err := url.Parse(q.Path); err == nil {
+		u.Path = p.Path
+		u.RawQuery = p.RawQuery
+	} else {
+		u.Path = q.Path
+	}
+
+	method := q.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), strings.NewReader(q.Body))
+	if err != nil {
+		return &Result{
+			Status: structs.ServiceCheckCritical,
+			Output: err.Error(),
+		}
+	}
+
+	for header, values := range q.Headers {
+		for _, value := range values {
+			req.Header.Add(header, value)
+		}
+	}
+
+	// Go treats the Host header as a special case, so it must be set on the
+	// request directly to be honoured.
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	client := c.httpClient
+	if q.TLSSkipVerify {
+		client = c.tlsClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &Result{
+			Status: structs.ServiceCheckCritical,
+			Output: err.Error(),
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOutputSize))
+	if err != nil {
+		c.log.Debug("failed to read check response body", "url", u.String(), "error", err)
+	}
+
+	output := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, u.String(), resp.Status, body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return &Result{Status: structs.ServiceCheckPassing, Output: output}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &Result{Status: structs.ServiceCheckWarning, Output: output}
+	default:
+		return &Result{Status: structs.ServiceCheckCritical, Output: output}
+	}
+}
@@ -0,0 +1,119 @@
+package checks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func splitHostPort(t *testing.T, addr string) (string, int) {
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+	return host, port
+}
+
+func TestChecker_Do_HTTP(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthy":
+			if r.Header.Get("X-Check") != "nomad" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		case "/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	host, port := splitHostPort(t, ts.Listener.Addr().String())
+
+	testCases := []struct {
+		name           string
+		path           string
+		expectedStatus string
+	}{
+		{
+			name:           "passing",
+			path:           "/healthy",
+			expectedStatus: structs.ServiceCheckPassing,
+		},
+		{
+			name:           "warning",
+			path:           "/busy",
+			expectedStatus: structs.ServiceCheckWarning,
+		},
+		{
+			name:           "critical",
+			path:           "/broken",
+			expectedStatus: structs.ServiceCheckCritical,
+		},
+	}
+
+	c := New(hclog.NewNullLogger())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := c.Do(context.Background(), &Query{
+				Type:    structs.ServiceCheckHTTP,
+				Address: host,
+				Port:    port,
+				Path:    tc.path,
+				Timeout: time.Second,
+				Headers: map[string][]string{"X-Check": {"nomad"}},
+			})
+			require.Equal(t, tc.expectedStatus, res.Status, res.Output)
+			require.NotEmpty(t, res.Output)
+		})
+	}
+}
+
+func TestChecker_Do_TCP(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	host, port := splitHostPort(t, ln.Addr().String())
+	c := New(hclog.NewNullLogger())
+
+	query := &Query{
+		Type:    structs.ServiceCheckTCP,
+		Address: host,
+		Port:    port,
+		Timeout: time.Second,
+	}
+
+	res := c.Do(context.Background(), query)
+	require.Equal(t, structs.ServiceCheckPassing, res.Status, res.Output)
+
+	// Closing the listener should result in a failed check.
+	require.NoError(t, ln.Close())
+
+	res = c.Do(context.Background(), query)
+	require.Equal(t, structs.ServiceCheckCritical, res.Status, res.Output)
+}
+
+func TestChecker_Do_Unsupported(t *testing.T) {
+	ci.Parallel(t)
+
+	c := New(hclog.NewNullLogger())
+	res := c.Do(context.Background(), &Query{Type: structs.ServiceCheckScript, Timeout: time.Second})
+	require.Equal(t, structs.ServiceCheckCritical, res.Status)
+}
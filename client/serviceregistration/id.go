@@ -14,6 +14,10 @@ const (
 	// nomadTaskPrefix is the prefix that scopes Nomad registered services
 	// for tasks.
 	nomadTaskPrefix = nomadServicePrefix + "-task-"
+
+	// nomadCheckPrefix is the prefix that scopes Nomad registered checks for
+	// services.
+	nomadCheckPrefix = nomadServicePrefix + "-check-"
 )
 
 // MakeAllocServiceID creates a unique ID for identifying an alloc service in
@@ -25,3 +29,12 @@ func MakeAllocServiceID(allocID, taskName string, service *structs.Service) stri
 	return fmt.Sprintf("%s%s-%s-%s-%s",
 		nomadTaskPrefix, allocID, taskName, service.Name, service.PortLabel)
 }
+
+// MakeCheckID creates a unique ID for a check within a service registration
+// provider. Both Nomad and Consul solutions use the same ID format to provide
+// consistency.
+//
+// Example Check ID: _nomad-check-434ae42f9a57c5705344974ac38de2aee0ee089d
+func MakeCheckID(serviceID string, check *structs.ServiceCheck) string {
+	return fmt.Sprintf("%s%s", nomadCheckPrefix, check.Hash(serviceID))
+}
@@ -2,6 +2,7 @@ package serviceregistration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
@@ -34,3 +35,21 @@ func Test_MakeAllocServiceID(t *testing.T) {
 		})
 	}
 }
+
+func Test_MakeCheckID(t *testing.T) {
+	check := &structs.ServiceCheck{
+		Name:     "redis-alive",
+		Type:     structs.ServiceCheckTCP,
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+	}
+
+	serviceID := "_nomad-task-7ac7c672-1824-6f06-644c-4c249e1578b9-cache-redis-db"
+
+	id := MakeCheckID(serviceID, check)
+	require.Equal(t, "_nomad-check-"+check.Hash(serviceID), id)
+
+	// Modifying the check definition must result in a new ID.
+	check.Interval = 20 * time.Second
+	require.NotEqual(t, id, MakeCheckID(serviceID, check))
+}
@@ -0,0 +1,416 @@
+package nsd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// serviceChecks tracks the health checks being executed for a single service
+// registration.
+type serviceChecks struct {
+
+	// workload is the name of the task or group which registered the service
+	// as returned by WorkloadServices.Name.
+	workload string
+
+	// checkIDs is the set of check IDs being executed. It is used to identify
+	// whether an updated service requires the checks to be restarted.
+	checkIDs map[string]struct{}
+
+	// onUpdate maps the check ID to the on_update value of the check.
+	onUpdate map[string]string
+
+	// cancelFn stops all the check routines for the service.
+	cancelFn context.CancelFunc
+
+	// lock protects the fields below. It is held while updating the
+	// registration via RPC, so that removing the service cannot race with
+	// an in-flight status update and resurrect the registration.
+	lock sync.Mutex
+
+	// registration is the latest registration, including check statuses,
+	// which has been sent to the servers.
+	registration *structs.ServiceRegistration
+
+	// dirty indicates the servers may hold a check status which differs from
+	// the registration, so the next check result must be sent regardless of
+	// whether the status changed.
+	dirty bool
+
+	// removed indicates the service has been deregistered and no further
+	// updates should be sent.
+	removed bool
+}
+
+// checkDefinition couples a service check with the query used to execute it.
+type checkDefinition struct {
+	id    string
+	check *structs.ServiceCheck
+	query *checks.Query
+}
+
+// generateCheckDefinitions builds the check definitions for each check within
+// the service, resolving the address each check should target.
+func generateCheckDefinitions(serviceID string, serviceSpec *structs.Service,
+	workload *serviceregistration.WorkloadServices) ([]*checkDefinition, error) {
+
+	definitions := make([]*checkDefinition, 0, len(serviceSpec.Checks))
+
+	for _, check := range serviceSpec.Checks {
+		portLabel := check.PortLabel
+		if portLabel == "" {
+			portLabel = serviceSpec.PortLabel
+		}
+
+		// Mirror the Consul provider; checks default to the host address
+		// unless the service has a custom address.
+		addrMode := check.AddressMode
+		if addrMode == "" {
+			if serviceSpec.Address != "" {
+				addrMode = structs.AddressModeAuto
+			} else {
+				addrMode = structs.AddressModeHost
+			}
+		}
+
+		ip, port, err := serviceregistration.GetAddress(
+			serviceSpec.Address, addrMode, portLabel, workload.Networks,
+			workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get address for check %q: %v", check.Name, err)
+		}
+
+		definitions = append(definitions, &checkDefinition{
+			id:    serviceregistration.MakeCheckID(serviceID, check),
+			check: check,
+			query: checks.GetQuery(check, ip, port),
+		})
+	}
+
+	return definitions, nil
+}
+
+// initialCheckStatus returns the status a check has before it has been
+// executed. Like Consul, checks are critical unless configured otherwise.
+func initialCheckStatus(check *structs.ServiceCheck) string {
+	if check.InitialStatus != "" {
+		return check.InitialStatus
+	}
+	return structs.ServiceCheckCritical
+}
+
+// preserveCheckStatus copies the known check statuses of already running
+// checks onto the passed registrations. This ensures updating a service, such
+// as when a canary is promoted, does not reset its health.
+func (s *ServiceRegistrationHandler) preserveCheckStatus(allocID string, registrations []*structs.ServiceRegistration) {
+	s.checksLock.RLock()
+	defer s.checksLock.RUnlock()
+
+	for _, reg := range registrations {
+		sc, ok := s.allocChecks[allocID][reg.ID]
+		if !ok {
+			continue
+		}
+
+		sc.lock.Lock()
+		copyCheckStatus(sc.registration, reg)
+		sc.lock.Unlock()
+	}
+}
+
+// copyCheckStatus copies the status of each check within src onto the check
+// with a matching ID within dst. It returns whether any status was modified.
+func copyCheckStatus(src, dst *structs.ServiceRegistration) bool {
+	var modified bool
+	for _, dstCheck := range dst.Checks {
+		for _, srcCheck := range src.Checks {
+			if srcCheck.ID != dstCheck.ID {
+				continue
+			}
+			if srcCheck.Status != dstCheck.Status {
+				modified = true
+			}
+			dstCheck.Status = srcCheck.Status
+			dstCheck.Output = srcCheck.Output
+			dstCheck.Timestamp = srcCheck.Timestamp
+		}
+	}
+	return modified
+}
+
+// startChecks starts executing the checks for the passed registrations. If
+// the checks of a service are already running with an identical definition
+// they are left untouched, otherwise they are restarted.
+func (s *ServiceRegistrationHandler) startChecks(workload *serviceregistration.WorkloadServices,
+	registrations []*structs.ServiceRegistration, definitions [][]*checkDefinition) {
+
+	s.checksLock.Lock()
+	defer s.checksLock.Unlock()
+
+	for i, reg := range registrations {
+		defs := definitions[i]
+
+		existing, ok := s.allocChecks[workload.AllocID][reg.ID]
+
+		// If the service no longer has checks, stop any that were previously
+		// running.
+		if len(defs) == 0 {
+			if ok {
+				existing.stop()
+				delete(s.allocChecks[workload.AllocID], reg.ID)
+			}
+			continue
+		}
+
+		checkIDs := make(map[string]struct{}, len(defs))
+		onUpdate := make(map[string]string, len(defs))
+		for _, def := range defs {
+			checkIDs[def.id] = struct{}{}
+			onUpdate[def.id] = def.check.OnUpdate
+		}
+
+		// The checks are unchanged, so only update the stored registration.
+		// A check may have changed status since preserveCheckStatus was
+		// called, in which case the servers need to be updated again.
+		if ok && equalCheckIDs(existing.checkIDs, checkIDs) {
+			existing.lock.Lock()
+			newReg := reg.Copy()
+			if copyCheckStatus(existing.registration, newReg) {
+				existing.dirty = true
+			}
+			existing.registration = newReg
+			existing.lock.Unlock()
+			continue
+		}
+
+		if ok {
+			existing.stop()
+		}
+
+		ctx, cancel := context.WithCancel(s.checksCtx)
+
+		sc := &serviceChecks{
+			workload:     workload.Name(),
+			checkIDs:     checkIDs,
+			onUpdate:     onUpdate,
+			cancelFn:     cancel,
+			registration: reg.Copy(),
+		}
+
+		if s.allocChecks[workload.AllocID] == nil {
+			s.allocChecks[workload.AllocID] = make(map[string]*serviceChecks)
+		}
+		s.allocChecks[workload.AllocID][reg.ID] = sc
+
+		for _, def := range defs {
+			go s.runCheck(ctx, sc, def)
+		}
+	}
+}
+
+// stopChecks stops the checks running for the identified service
+// registration. It blocks until any in-flight status update has completed.
+func (s *ServiceRegistrationHandler) stopChecks(allocID, serviceID string) {
+	s.checksLock.Lock()
+	sc, ok := s.allocChecks[allocID][serviceID]
+	if ok {
+		delete(s.allocChecks[allocID], serviceID)
+		if len(s.allocChecks[allocID]) == 0 {
+			delete(s.allocChecks, allocID)
+		}
+	}
+	s.checksLock.Unlock()
+
+	if ok {
+		sc.stop()
+	}
+}
+
+// stop cancels the check routines and ensures no further status updates are
+// sent to the servers.
+func (sc *serviceChecks) stop() {
+	sc.cancelFn()
+	sc.lock.Lock()
+	sc.removed = true
+	sc.lock.Unlock()
+}
+
+// runCheck executes a single check at its configured interval until the
+// context is cancelled.
+func (s *ServiceRegistrationHandler) runCheck(ctx context.Context, sc *serviceChecks, def *checkDefinition) {
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	// successes and failures track the number of consecutive results, so the
+	// success_before_passing and failures_before_critical thresholds can be
+	// honoured.
+	var successes, failures int
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		result := s.checker.Do(ctx, def.query)
+		if ctx.Err() != nil {
+			return
+		}
+
+		status := result.Status
+
+		switch status {
+		case structs.ServiceCheckPassing:
+			successes++
+			failures = 0
+			if successes < def.check.SuccessBeforePassing {
+				status = ""
+			}
+		case structs.ServiceCheckCritical:
+			failures++
+			successes = 0
+			if failures < def.check.FailuresBeforeCritical {
+				status = ""
+			}
+		default:
+			successes, failures = 0, 0
+		}
+
+		if status != "" {
+			s.setCheckStatus(sc, def.id, status, result.Output)
+		}
+
+		timer.Reset(def.check.Interval)
+	}
+}
+
+// setCheckStatus records the result of a check. The servers are only updated
+// when the status of the check has changed, to avoid writing to Raft for
+// every check execution.
+func (s *ServiceRegistrationHandler) setCheckStatus(sc *serviceChecks, checkID, status, output string) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	if sc.removed {
+		return
+	}
+
+	var changed bool
+	for _, check := range sc.registration.Checks {
+		if check.ID != checkID {
+			continue
+		}
+		check.Output = output
+		if check.Status != status {
+			check.Status = status
+			check.Timestamp = time.Now().UnixNano()
+			changed = true
+		}
+	}
+
+	if !changed && !sc.dirty {
+		return
+	}
+
+	args := structs.ServiceRegistrationUpsertRequest{
+		Services: []*structs.ServiceRegistration{sc.registration.Copy()},
+		WriteRequest: structs.WriteRequest{
+			Region:    s.cfg.Region,
+			AuthToken: s.cfg.NodeSecret,
+		},
+	}
+
+	var resp structs.ServiceRegistrationUpsertResponse
+
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		s.log.Error("failed to update service registration check status",
+			"error", err, "service_id", sc.registration.ID, "check_id", checkID)
+		sc.dirty = true
+		return
+	}
+	sc.dirty = false
+}
+
+// allocRegistrations builds the registrations, including the check statuses,
+// for all services with checks within the allocation.
+func (s *ServiceRegistrationHandler) allocRegistrations(allocID string) *serviceregistration.AllocRegistration {
+	s.checksLock.RLock()
+	defer s.checksLock.RUnlock()
+
+	services, ok := s.allocChecks[allocID]
+	if !ok {
+		return nil
+	}
+
+	allocReg := &serviceregistration.AllocRegistration{
+		Tasks: make(map[string]*serviceregistration.ServiceRegistrations),
+	}
+
+	for serviceID, sc := range services {
+		sc.lock.Lock()
+		reg := sc.registration.Copy()
+		sc.lock.Unlock()
+
+		treg, ok := allocReg.Tasks[sc.workload]
+		if !ok {
+			treg = &serviceregistration.ServiceRegistrations{
+				Services: make(map[string]*serviceregistration.ServiceRegistration),
+			}
+			allocReg.Tasks[sc.workload] = treg
+		}
+
+		sreg := &serviceregistration.ServiceRegistration{
+			ServiceID:     serviceID,
+			CheckIDs:      make(map[string]struct{}, len(reg.Checks)),
+			CheckOnUpdate: make(map[string]string, len(reg.Checks)),
+			Service: &api.AgentService{
+				ID:      reg.ID,
+				Service: reg.ServiceName,
+				Tags:    reg.Tags,
+				Address: reg.Address,
+				Port:    reg.Port,
+			},
+			Checks: make([]*api.AgentCheck, 0, len(reg.Checks)),
+		}
+
+		for _, check := range reg.Checks {
+			sreg.CheckIDs[check.ID] = struct{}{}
+			sreg.CheckOnUpdate[check.ID] = sc.onUpdate[check.ID]
+			sreg.Checks = append(sreg.Checks, &api.AgentCheck{
+				CheckID:     check.ID,
+				Name:        check.Name,
+				Type:        check.Type,
+				Status:      check.Status,
+				Output:      check.Output,
+				ServiceID:   reg.ID,
+				ServiceName: reg.ServiceName,
+			})
+		}
+
+		treg.Services[serviceID] = sreg
+	}
+
+	return allocReg
+}
+
+// equalCheckIDs returns whether the two sets of check IDs are identical.
+func equalCheckIDs(a, b map[string]struct{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package nsd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockChecker is a checks.Checker implementation which returns a configurable
+// status for every check.
+type mockChecker struct {
+	status string
+	l      sync.Mutex
+}
+
+func (m *mockChecker) Do(_ context.Context, _ *checks.Query) *checks.Result {
+	m.l.Lock()
+	defer m.l.Unlock()
+	return &checks.Result{Status: m.status, Output: "mock output"}
+}
+
+func (m *mockChecker) setStatus(status string) {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.status = status
+}
+
+func mockWorkloadWithChecks() *serviceregistration.WorkloadServices {
+	workload := mockWorkload()
+	workload.Services[0].Checks = []*structs.ServiceCheck{
+		{
+			Name:     "redis-alive",
+			Type:     structs.ServiceCheckTCP,
+			Interval: 10 * time.Millisecond,
+			Timeout:  time.Second,
+		},
+	}
+	return workload
+}
+
+// checkStatuses returns the status of every check registered for the
+// allocation, keyed by the check name.
+func checkStatuses(t *testing.T, h *ServiceRegistrationHandler, allocID string) map[string]string {
+	allocReg, err := h.AllocRegistrations(allocID)
+	require.NoError(t, err)
+
+	statuses := make(map[string]string)
+	if allocReg == nil {
+		return statuses
+	}
+	for _, treg := range allocReg.Tasks {
+		for _, sreg := range treg.Services {
+			for _, check := range sreg.Checks {
+				statuses[check.Name] = check.Status
+			}
+		}
+	}
+	return statuses
+}
+
+func TestServiceRegistrationHandler_Checks(t *testing.T) {
+	ci.Parallel(t)
+
+	checker := &mockChecker{status: structs.ServiceCheckCritical}
+	mockRPC := mockRPC{callCounts: map[string]int{}}
+
+	h := NewServiceRegistrationHandler(hclog.NewNullLogger(), &ServiceRegistrationHandlerCfg{
+		Enabled: true,
+		RPCFn:   mockRPC.RPC,
+		Checker: checker,
+	}).(*ServiceRegistrationHandler)
+	defer h.Shutdown()
+
+	workload := mockWorkloadWithChecks()
+	require.NoError(t, h.RegisterWorkload(workload))
+
+	// Only the service with a check should be tracked, and the check should
+	// start critical. The initial status matches the check result, so no
+	// further RPCs should be made.
+	require.Equal(t, map[string]string{"redis-alive": structs.ServiceCheckCritical},
+		checkStatuses(t, h, workload.AllocID))
+	require.Equal(t, 1, mockRPC.count(structs.ServiceRegistrationUpsertRPCMethod))
+
+	// Once the check passes, the registration should be updated.
+	checker.setStatus(structs.ServiceCheckPassing)
+
+	testutil.WaitForResult(func() (bool, error) {
+		statuses := checkStatuses(t, h, workload.AllocID)
+		if statuses["redis-alive"] != structs.ServiceCheckPassing {
+			return false, fmt.Errorf("unexpected check statuses: %v", statuses)
+		}
+		if n := mockRPC.count(structs.ServiceRegistrationUpsertRPCMethod); n != 2 {
+			return false, fmt.Errorf("unexpected upsert count: %v", n)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	// Re-registering the workload, such as when canary tags change, should
+	// not reset the status of the running checks.
+	require.NoError(t, h.RegisterWorkload(workload))
+	require.Equal(t, map[string]string{"redis-alive": structs.ServiceCheckPassing},
+		checkStatuses(t, h, workload.AllocID))
+
+	// Removing the workload should stop the checks.
+	h.RemoveWorkload(workload)
+	require.Empty(t, checkStatuses(t, h, workload.AllocID))
+}
+
+func TestServiceRegistrationHandler_Checks_Thresholds(t *testing.T) {
+	ci.Parallel(t)
+
+	checker := &mockChecker{status: structs.ServiceCheckPassing}
+	mockRPC := mockRPC{callCounts: map[string]int{}}
+
+	h := NewServiceRegistrationHandler(hclog.NewNullLogger(), &ServiceRegistrationHandlerCfg{
+		Enabled: true,
+		RPCFn:   mockRPC.RPC,
+		Checker: checker,
+	}).(*ServiceRegistrationHandler)
+	defer h.Shutdown()
+
+	// Use a large threshold and a short interval, so we can observe the
+	// check remaining critical despite passing results.
+	workload := mockWorkloadWithChecks()
+	workload.Services[0].Checks[0].SuccessBeforePassing = 1000
+
+	require.NoError(t, h.RegisterWorkload(workload))
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, map[string]string{"redis-alive": structs.ServiceCheckCritical},
+		checkStatuses(t, h, workload.AllocID))
+	require.Equal(t, 1, mockRPC.count(structs.ServiceRegistrationUpsertRPCMethod))
+}
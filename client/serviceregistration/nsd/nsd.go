@@ -1,13 +1,16 @@
 package nsd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -25,6 +28,20 @@ type ServiceRegistrationHandler struct {
 	// shutDownCh coordinates shutting down the handler and any long-running
 	// processes, such as the RPC retry.
 	shutDownCh chan struct{}
+
+	// checker is used to execute the health checks defined within services.
+	checker checks.Checker
+
+	// checksCtx is the parent context of all running checks and is cancelled
+	// when the handler is shutdown.
+	checksCtx    context.Context
+	checksCancel context.CancelFunc
+
+	// allocChecks tracks the services which have running checks, keyed by
+	// the allocation ID and then the service registration ID. It must only
+	// be accessed while holding checksLock.
+	allocChecks map[string]map[string]*serviceChecks
+	checksLock  sync.RWMutex
 }
 
 // ServiceRegistrationHandlerCfg holds critical information used during the
@@ -49,6 +66,10 @@ type ServiceRegistrationHandlerCfg struct {
 	// server service registration RPC calls. This RPC function has basic retry
 	// functionality.
 	RPCFn func(method string, args, resp interface{}) error
+
+	// Checker is used to execute service health checks. It is optional and
+	// defaults to the HTTP and TCP checker within the checks package.
+	Checker checks.Checker
 }
 
 // NewServiceRegistrationHandler returns a ready to use
@@ -56,11 +77,25 @@ type ServiceRegistrationHandlerCfg struct {
 // interface.
 func NewServiceRegistrationHandler(
 	log hclog.Logger, cfg *ServiceRegistrationHandlerCfg) serviceregistration.Handler {
+
+	log = log.Named("service_registration.nomad")
+
+	checker := cfg.Checker
+	if checker == nil {
+		checker = checks.New(log)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &ServiceRegistrationHandler{
 		cfg:                 cfg,
-		log:                 log.Named("service_registration.nomad"),
+		log:                 log,
 		registrationEnabled: cfg.Enabled,
 		shutDownCh:          make(chan struct{}),
+		checker:             checker,
+		checksCtx:           ctx,
+		checksCancel:        cancel,
+		allocChecks:         make(map[string]map[string]*serviceChecks),
 	}
 }
 
@@ -77,16 +112,18 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 	var mErr multierror.Error
 
 	registrations := make([]*structs.ServiceRegistration, len(workload.Services))
+	checkDefinitions := make([][]*checkDefinition, len(workload.Services))
 
 	// Iterate over the services and generate a hydrated registration object for
 	// each. All services are part of a single allocation, therefore we cannot
 	// have one failure without all becoming a failure.
 	for i, serviceSpec := range workload.Services {
-		serviceRegistration, err := s.generateNomadServiceRegistration(serviceSpec, workload)
+		serviceRegistration, definitions, err := s.generateNomadServiceRegistration(serviceSpec, workload)
 		if err != nil {
 			mErr.Errors = append(mErr.Errors, err)
 		} else if mErr.ErrorOrNil() == nil {
 			registrations[i] = serviceRegistration
+			checkDefinitions[i] = definitions
 		}
 	}
 
@@ -95,6 +132,10 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 		return err
 	}
 
+	// Services which are being updated may already have running checks, in
+	// which case we do not want to reset their status.
+	s.preserveCheckStatus(workload.AllocID, registrations)
+
 	args := structs.ServiceRegistrationUpsertRequest{
 		Services: registrations,
 		WriteRequest: structs.WriteRequest{
@@ -105,7 +146,14 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 
 	var resp structs.ServiceRegistrationUpsertResponse
 
-	return s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp)
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		return err
+	}
+
+	// Only start executing checks once the services are registered, so that
+	// status updates always have a registration to modify.
+	s.startChecks(workload, registrations, checkDefinitions)
+	return nil
 }
 
 // RemoveWorkload iterates the services and removes them from the service
@@ -116,6 +164,12 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 // allocations which, when stopped need their registrations removed.
 func (s *ServiceRegistrationHandler) RemoveWorkload(workload *serviceregistration.WorkloadServices) {
 	for _, serviceSpec := range workload.Services {
+
+		// Stop the checks before deleting the registration, so an in-flight
+		// check status update cannot recreate the deleted registration.
+		s.stopChecks(workload.AllocID,
+			serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec))
+
 		go s.removeWorkload(workload, serviceSpec)
 	}
 }
@@ -240,28 +294,34 @@ func (s *ServiceRegistrationHandler) dedupUpdatedWorkload(
 	return oldCopy, newCopy
 }
 
-// AllocRegistrations is currently a noop implementation as the Nomad provider
-// does not support health check which is the sole subsystem caller of this
-// function.
-func (s *ServiceRegistrationHandler) AllocRegistrations(_ string) (*serviceregistration.AllocRegistration, error) {
-	return nil, nil
+// AllocRegistrations returns the registrations of the allocation's services
+// which have checks, along with the current status of each check. It is used
+// by the allocation health tracker. Services without checks are not included
+// and a nil registration is returned if the allocation has no checks.
+func (s *ServiceRegistrationHandler) AllocRegistrations(allocID string) (*serviceregistration.AllocRegistration, error) {
+	return s.allocRegistrations(allocID), nil
 }
 
-// UpdateTTL is currently a noop implementation as the Nomad provider does not
-// support health check which is the sole subsystem caller of this function.
+// UpdateTTL is a noop implementation as the Nomad provider does not support
+// script checks which are the sole subsystem caller of this function.
 func (s *ServiceRegistrationHandler) UpdateTTL(_, _, _, _ string) error {
 	return nil
 }
 
 // Shutdown is used to initiate shutdown of the handler. This is specifically
-// used to exit any routines running retry functions without leaving them
-// orphaned.
-func (s *ServiceRegistrationHandler) Shutdown() { close(s.shutDownCh) }
+// used to exit any routines running retry functions or checks without leaving
+// them orphaned.
+func (s *ServiceRegistrationHandler) Shutdown() {
+	s.checksCancel()
+	close(s.shutDownCh)
+}
 
 // generateNomadServiceRegistration is a helper to build the Nomad specific
-// registration object on a per-service basis.
+// registration object on a per-service basis. It also returns the definitions
+// of the checks which need to be executed for the service.
 func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
-	serviceSpec *structs.Service, workload *serviceregistration.WorkloadServices) (*structs.ServiceRegistration, error) {
+	serviceSpec *structs.Service, workload *serviceregistration.WorkloadServices) (
+	*structs.ServiceRegistration, []*checkDefinition, error) {
 
 	// Service address modes default to auto.
 	addrMode := serviceSpec.AddressMode
@@ -274,7 +334,7 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		serviceSpec.Address, addrMode, serviceSpec.PortLabel, workload.Networks,
 		workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get address for service %q: %v", serviceSpec.Name, err)
+		return nil, nil, fmt.Errorf("unable to get address for service %q: %v", serviceSpec.Name, err)
 	}
 
 	// Build the tags to use for this registration which is a result of whether
@@ -289,8 +349,27 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		copy(tags, serviceSpec.Tags)
 	}
 
+	id := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec)
+
+	definitions, err := generateCheckDefinitions(id, serviceSpec, workload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate checks for service %q: %v", serviceSpec.Name, err)
+	}
+
+	// Checks start with their initial status, which is updated once they
+	// have been executed.
+	var checkStatuses []*structs.ServiceRegistrationCheck
+	for _, def := range definitions {
+		checkStatuses = append(checkStatuses, &structs.ServiceRegistrationCheck{
+			ID:     def.id,
+			Name:   def.check.Name,
+			Type:   def.check.Type,
+			Status: initialCheckStatus(def.check),
+		})
+	}
+
 	return &structs.ServiceRegistration{
-		ID:          serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec),
+		ID:          id,
 		ServiceName: serviceSpec.Name,
 		NodeID:      s.cfg.NodeID,
 		JobID:       workload.JobID,
@@ -300,5 +379,6 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		Tags:        tags,
 		Address:     ip,
 		Port:        port,
-	}, nil
+		Checks:      checkStatuses,
+	}, definitions, nil
 }
@@ -539,6 +539,13 @@ func (mr *mockRPC) calls() map[string]int {
 	return mr.callCounts
 }
 
+// count returns the number of calls made to the passed RPC method.
+func (mr *mockRPC) count(method string) int {
+	mr.l.RLock()
+	defer mr.l.RUnlock()
+	return mr.callCounts[method]
+}
+
 // RPC mocks the server RPCs, acting as though any request succeeds.
 func (mr *mockRPC) RPC(method string, _, _ interface{}) error {
 	switch method {
@@ -38,6 +38,19 @@ func NewHandlerWrapper(
 	}
 }
 
+// ProviderHandler returns the handler implementation for the passed service
+// registration provider. This allows callers, such as the allocation health
+// watcher, to query check results from the provider which owns the checks. An
+// unknown provider results in the Consul handler, mirroring RemoveWorkload.
+func (h *HandlerWrapper) ProviderHandler(provider string) serviceregistration.Handler {
+	switch provider {
+	case structs.ServiceProviderNomad:
+		return h.nomadServiceProvider
+	default:
+		return h.consulServiceProvider
+	}
+}
+
 // RegisterWorkload wraps the serviceregistration.Handler RegisterWorkload
 // function. It determines which backend provider to call and passes the
 // workload unless the provider is unknown, in which case an error will be
@@ -12,6 +12,7 @@ import (
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -374,6 +375,50 @@ func TestStateDB_DynamicRegistry(t *testing.T) {
 	})
 }
 
+func TestStateDB_DynamicHostVolumes(t *testing.T) {
+	ci.Parallel(t)
+
+	testDB(t, func(t *testing.T, db StateDB) {
+		require := require.New(t)
+
+		// Getting nonexistent volumes should return nothing
+		vols, err := db.GetDynamicHostVolumes()
+		require.NoError(err)
+		require.Empty(vols)
+
+		// Putting volumes should work
+		vol1 := &cstructs.HostVolumeState{
+			ID: "vol-1",
+			CreateReq: &cstructs.ClientHostVolumeCreateRequest{
+				ID:         "vol-1",
+				Name:       "data",
+				PluginID:   "mkdir",
+				Parameters: map[string]string{"mode": "0755"},
+			},
+			HostPath: "/var/nomad/host_volumes/vol-1",
+		}
+		vol2 := &cstructs.HostVolumeState{
+			ID:        "vol-2",
+			CreateReq: &cstructs.ClientHostVolumeCreateRequest{ID: "vol-2", Name: "logs"},
+		}
+		require.NoError(db.PutDynamicHostVolume(vol1))
+		require.NoError(db.PutDynamicHostVolume(vol2))
+
+		vols, err = db.GetDynamicHostVolumes()
+		require.NoError(err)
+		require.ElementsMatch([]*cstructs.HostVolumeState{vol1, vol2}, vols)
+
+		// Deleting a volume should only delete that volume, and deleting it
+		// again should be a noop
+		require.NoError(db.DeleteDynamicHostVolume(vol2.ID))
+		require.NoError(db.DeleteDynamicHostVolume(vol2.ID))
+
+		vols, err = db.GetDynamicHostVolumes()
+		require.NoError(err)
+		require.Equal([]*cstructs.HostVolumeState{vol1}, vols)
+	})
+}
+
 // TestStateDB_Upgrade asserts calling Upgrade on new databases always
 // succeeds.
 func TestStateDB_Upgrade(t *testing.T) {
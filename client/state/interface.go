@@ -5,6 +5,7 @@ import (
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -81,6 +82,18 @@ type StateDB interface {
 	// PutDynamicPluginRegistryState is used to store the dynamic plugin manager's state.
 	PutDynamicPluginRegistryState(state *dynamicplugins.RegistryState) error
 
+	// GetDynamicHostVolumes is used to retrieve the dynamic host volumes
+	// created on the client.
+	GetDynamicHostVolumes() ([]*cstructs.HostVolumeState, error)
+
+	// PutDynamicHostVolume is used to store a dynamic host volume created
+	// on the client.
+	PutDynamicHostVolume(vol *cstructs.HostVolumeState) error
+
+	// DeleteDynamicHostVolume is used to delete a dynamic host volume. No
+	// error is returned if it does not exist.
+	DeleteDynamicHostVolume(id string) error
+
 	// Close the database. Unsafe for further use after calling regardless
 	// of return value.
 	Close() error
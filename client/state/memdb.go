@@ -8,6 +8,7 @@ import (
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -36,6 +37,9 @@ type MemDB struct {
 	// dynamicmanager -> registry-state
 	dynamicManagerPs *dynamicplugins.RegistryState
 
+	// volume_id -> value
+	hostVolumes map[string]*cstructs.HostVolumeState
+
 	logger hclog.Logger
 
 	mu sync.RWMutex
@@ -49,6 +53,7 @@ func NewMemDB(logger hclog.Logger) *MemDB {
 		networkStatus:  make(map[string]*structs.AllocNetworkStatus),
 		localTaskState: make(map[string]map[string]*state.LocalState),
 		taskState:      make(map[string]map[string]*structs.TaskState),
+		hostVolumes:    make(map[string]*cstructs.HostVolumeState),
 		logger:         logger,
 	}
 }
@@ -227,6 +232,31 @@ func (m *MemDB) PutDynamicPluginRegistryState(ps *dynamicplugins.RegistryState)
 	return nil
 }
 
+func (m *MemDB) GetDynamicHostVolumes() ([]*cstructs.HostVolumeState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vols := make([]*cstructs.HostVolumeState, 0, len(m.hostVolumes))
+	for _, vol := range m.hostVolumes {
+		vols = append(vols, vol)
+	}
+	return vols, nil
+}
+
+func (m *MemDB) PutDynamicHostVolume(vol *cstructs.HostVolumeState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hostVolumes[vol.ID] = vol
+	return nil
+}
+
+func (m *MemDB) DeleteDynamicHostVolume(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.hostVolumes, id)
+	return nil
+}
+
 func (m *MemDB) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -11,6 +11,7 @@ import (
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/boltdd"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"go.etcd.io/bbolt"
@@ -39,6 +40,9 @@ drivermanager/
 
 dynamicplugins/
 |--> registry_state -> *dynamicplugins.RegistryState
+
+host_volumes/
+|--> <volume-id> -> *cstructs.HostVolumeState
 */
 
 var (
@@ -95,6 +99,10 @@ var (
 
 	// registryStateKey is the key at which dynamic plugin registry state is stored
 	registryStateKey = []byte("registry_state")
+
+	// hostVolumeBucketName is the bucket name containing the dynamic host
+	// volumes, keyed by their ID
+	hostVolumeBucketName = []byte("host_volumes")
 )
 
 // taskBucketName returns the bucket name for the given task name.
@@ -717,6 +725,58 @@ func (s *BoltStateDB) GetDynamicPluginRegistryState() (*dynamicplugins.RegistryS
 	return ps, nil
 }
 
+// PutDynamicHostVolume stores a dynamic host volume or returns an error.
+func (s *BoltStateDB) PutDynamicHostVolume(vol *cstructs.HostVolumeState) error {
+	return s.db.Update(func(tx *boltdd.Tx) error {
+		volBkt, err := tx.CreateBucketIfNotExists(hostVolumeBucketName)
+		if err != nil {
+			return err
+		}
+		return volBkt.Put([]byte(vol.ID), vol)
+	})
+}
+
+// GetDynamicHostVolumes retrieves all the dynamic host volumes or returns an
+// error.
+func (s *BoltStateDB) GetDynamicHostVolumes() ([]*cstructs.HostVolumeState, error) {
+	var vols []*cstructs.HostVolumeState
+
+	err := s.db.View(func(tx *boltdd.Tx) error {
+		volBkt := tx.Bucket(hostVolumeBucketName)
+		if volBkt == nil {
+			// No volumes
+			return nil
+		}
+
+		c := volBkt.BoltBucket().Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			vol := &cstructs.HostVolumeState{}
+			if err := volBkt.Get(k, vol); err != nil {
+				return fmt.Errorf("failed to read host volume %q: %v", string(k), err)
+			}
+			vols = append(vols, vol)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vols, nil
+}
+
+// DeleteDynamicHostVolume deletes a dynamic host volume if it exists.
+func (s *BoltStateDB) DeleteDynamicHostVolume(id string) error {
+	return s.db.Update(func(tx *boltdd.Tx) error {
+		volBkt := tx.Bucket(hostVolumeBucketName)
+		if volBkt == nil {
+			return nil
+		}
+		return volBkt.Delete([]byte(id))
+	})
+}
+
 // init initializes metadata entries in a newly created state database.
 func (s *BoltStateDB) init() error {
 	return s.db.Update(func(tx *boltdd.Tx) error {
@@ -303,6 +303,49 @@ func (req *ClientCSIControllerDeleteVolumeRequest) ToCSIRequest() *csi.Controlle
 
 type ClientCSIControllerDeleteVolumeResponse struct{}
 
+// ClientCSIControllerExpandVolumeRequest the RPC made from the server to a
+// Nomad client to tell a CSI controller plugin on that client to perform
+// ControllerExpandVolume
+type ClientCSIControllerExpandVolumeRequest struct {
+	ExternalVolumeID string
+	CapacityMin      int64
+	CapacityMax      int64
+	Secrets          structs.CSISecrets
+
+	// VolumeCapability and MountOptions describe how the volume is
+	// currently used, if it is in use
+	VolumeCapability *structs.CSIVolumeCapability
+	MountOptions     *structs.CSIMountOptions
+
+	CSIControllerQuery
+}
+
+func (req *ClientCSIControllerExpandVolumeRequest) ToCSIRequest() (*csi.ControllerExpandVolumeRequest, error) {
+	creq := &csi.ControllerExpandVolumeRequest{
+		ExternalVolumeID: req.ExternalVolumeID,
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: req.CapacityMin,
+			LimitBytes:    req.CapacityMax,
+		},
+		Secrets: req.Secrets,
+	}
+
+	if req.VolumeCapability != nil {
+		ccap, err := csi.VolumeCapabilityFromStructs(
+			req.VolumeCapability.AttachmentMode, req.VolumeCapability.AccessMode, req.MountOptions)
+		if err != nil {
+			return nil, err
+		}
+		creq.VolumeCapability = ccap
+	}
+	return creq, nil
+}
+
+type ClientCSIControllerExpandVolumeResponse struct {
+	CapacityBytes         int64
+	NodeExpansionRequired bool
+}
+
 // ClientCSIControllerListVolumesVolumeRequest the RPC made from the server to
 // a Nomad client to tell a CSI controller plugin on that client to perform
 // ListVolumes
@@ -419,3 +462,29 @@ type ClientCSINodeDetachVolumeRequest struct {
 }
 
 type ClientCSINodeDetachVolumeResponse struct{}
+
+// ClientCSINodeExpandVolumeRequest is the RPC made from the server to a
+// Nomad client to tell a CSI node plugin on that client to perform
+// NodeExpandVolume on a volume it has published.
+type ClientCSINodeExpandVolumeRequest struct {
+	PluginID   string // ID of the plugin that manages the volume (required)
+	VolumeID   string // ID of the volume to be expanded (required)
+	AllocID    string // ID of an allocation the volume is published for (required)
+	NodeID     string // ID of the Nomad client targeted
+	ExternalID string // External ID of the volume to be expanded (required)
+
+	// These fields should match the claim of the allocation so that we can
+	// find the mount points on the client
+	AttachmentMode structs.CSIVolumeAttachmentMode
+	AccessMode     structs.CSIVolumeAccessMode
+	ReadOnly       bool
+	MountOptions   *structs.CSIMountOptions
+
+	// CapacityMin and CapacityMax are the requested capacity of the volume
+	CapacityMin int64
+	CapacityMax int64
+}
+
+type ClientCSINodeExpandVolumeResponse struct {
+	CapacityBytes int64
+}
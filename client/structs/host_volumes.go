@@ -0,0 +1,69 @@
+package structs
+
+// ClientHostVolumeCreateRequest is the RPC made from the server to a Nomad
+// client to create a dynamic host volume with a host volume plugin.
+type ClientHostVolumeCreateRequest struct {
+	// ID is the ID of the volume, generated by the servers (required)
+	ID string
+
+	// Name is the name the volume is fingerprinted with (required)
+	Name string
+
+	// PluginID is the host volume plugin used to create the volume (required)
+	PluginID string
+
+	// NodeID is the ID of the Nomad client targeted
+	NodeID string
+
+	// RequestedCapacityMinBytes and RequestedCapacityMaxBytes are passed to
+	// the plugin as is
+	RequestedCapacityMinBytes int64
+	RequestedCapacityMaxBytes int64
+
+	// Parameters are passed to the plugin as is
+	Parameters map[string]string
+}
+
+// ClientHostVolumeCreateResponse is the response object for a host volume
+// create request.
+type ClientHostVolumeCreateResponse struct {
+	// HostPath is the path of the volume on the client
+	HostPath string
+
+	// CapacityBytes is the capacity of the volume reported by the plugin
+	CapacityBytes int64
+}
+
+// ClientHostVolumeDeleteRequest is the RPC made from the server to a Nomad
+// client to delete a dynamic host volume with the plugin that created it.
+type ClientHostVolumeDeleteRequest struct {
+	// ID is the ID of the volume (required)
+	ID string
+
+	// Name is the name the volume is fingerprinted with
+	Name string
+
+	// PluginID is the host volume plugin used to create the volume (required)
+	PluginID string
+
+	// NodeID is the ID of the Nomad client targeted
+	NodeID string
+
+	// HostPath is the path of the volume on the client
+	HostPath string
+
+	// Parameters are the parameters the volume was created with
+	Parameters map[string]string
+}
+
+// ClientHostVolumeDeleteResponse is the response object for a host volume
+// delete request.
+type ClientHostVolumeDeleteResponse struct{}
+
+// HostVolumeState is the state of a dynamic host volume stored by the client,
+// so that the volume is fingerprinted again when the client restarts.
+type HostVolumeState struct {
+	ID        string
+	CreateReq *ClientHostVolumeCreateRequest
+	HostPath  string
+}
@@ -34,6 +34,46 @@ type ClientStatsResponse struct {
 	structs.QueryMeta
 }
 
+// ArtifactCacheResponse is used to return the content of the artifact cache
+// of a node.
+type ArtifactCacheResponse struct {
+	Cache *ArtifactCacheStats
+	structs.QueryMeta
+}
+
+// ArtifactCacheStats is the content of the artifact cache of a node.
+type ArtifactCacheStats struct {
+	// Size is the size of the cached artifacts in bytes.
+	Size int64
+
+	// MaxSize is the maximum size of the cache in bytes, which is 0 if the
+	// cache is disabled.
+	MaxSize int64
+
+	// Entries are the cached artifacts, from the most to the least recently
+	// used.
+	Entries []*ArtifactCacheEntry
+}
+
+// ArtifactCacheEntry is an artifact cached by a node.
+type ArtifactCacheEntry struct {
+	// Source is the source URL of the artifact, without its query string
+	// and credentials.
+	Source string
+
+	// Checksum is the checksum option pinning the artifact.
+	Checksum string
+
+	// Size is the size of the artifact in bytes.
+	Size int64
+
+	// LastUsed is the last time the artifact was copied to a task.
+	LastUsed time.Time
+
+	// InUse is whether the artifact is being copied to a task.
+	InUse bool
+}
+
 // MonitorRequest is used to request and stream logs from a client node.
 type MonitorRequest struct {
 	// LogLevel is the log level filter we want to stream logs on
@@ -153,6 +193,15 @@ type FsLogsRequest struct {
 	// Follow follows logs.
 	Follow bool
 
+	// Since and Until restrict the logs to the lines written within the
+	// given time range. When Since is set, the logs are streamed from the
+	// lines written at that time regardless of Offset and Origin.
+	Since time.Time
+	Until time.Time
+
+	// Grep is a regular expression the streamed log lines must match.
+	Grep string
+
 	structs.QueryOptions
 }
 
@@ -179,6 +228,9 @@ type AllocExecRequest struct {
 	// Cmd is the command to be executed
 	Cmd []string
 
+	// Action is the name of the task action to run instead of Cmd
+	Action string
+
 	structs.QueryOptions
 }
 
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+// Ensure ACLAuthMethodCommand satisfies the cli.Command interface.
+var _ cli.Command = &ACLAuthMethodCommand{}
+
+// ACLAuthMethodCommand implements cli.Command.
+type ACLAuthMethodCommand struct {
+	Meta
+}
+
+// Help satisfies the cli.Command Help function.
+func (a *ACLAuthMethodCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL auth methods. Auth
+  methods allow users to log in to Nomad using an external identity provider,
+  and receive an ACL token whose permissions are determined by the binding
+  rules of the auth method.
+
+  Create an ACL auth method:
+
+      $ nomad acl auth-method create -name="name" -type="OIDC" \
+          -max-token-ttl="1h" -token-locality="local" -config=@config.json
+
+  List all ACL auth methods:
+
+      $ nomad acl auth-method list
+
+  Lookup a specific ACL auth method:
+
+      $ nomad acl auth-method info <acl_auth_method_name>
+
+  Update an ACL auth method:
+
+      $ nomad acl auth-method update -default=true <acl_auth_method_name>
+
+  Delete an ACL auth method:
+
+      $ nomad acl auth-method delete <acl_auth_method_name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis satisfies the cli.Command Synopsis function.
+func (a *ACLAuthMethodCommand) Synopsis() string { return "Interact with ACL auth methods" }
+
+// Name returns the name of this command.
+func (a *ACLAuthMethodCommand) Name() string { return "acl auth-method" }
+
+// Run satisfies the cli.Command Run function.
+func (a *ACLAuthMethodCommand) Run(_ []string) int { return cli.RunResultHelp }
+
+// formatAuthMethod formats and converts the ACL auth method API object into a
+// string KV representation suitable for console output.
+func formatAuthMethod(authMethod *api.ACLAuthMethod) string {
+	return formatKV([]string{
+		fmt.Sprintf("Name|%s", authMethod.Name),
+		fmt.Sprintf("Type|%s", authMethod.Type),
+		fmt.Sprintf("Locality|%s", authMethod.TokenLocality),
+		fmt.Sprintf("Max Token TTL|%s", authMethod.MaxTokenTTL.String()),
+		fmt.Sprintf("Default|%t", authMethod.Default),
+		fmt.Sprintf("Create Index|%d", authMethod.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", authMethod.ModifyIndex),
+	})
+}
+
+// formatAuthMethodConfig formats the configuration of the ACL auth method into
+// a string KV representation suitable for console output.
+func formatAuthMethodConfig(config *api.ACLAuthMethodConfig) string {
+	return formatKV([]string{
+		fmt.Sprintf("OIDC Discovery URL|%s", config.OIDCDiscoveryURL),
+		fmt.Sprintf("OIDC Client ID|%s", config.OIDCClientID),
+		fmt.Sprintf("OIDC Client Secret|%s", config.OIDCClientSecret),
+		fmt.Sprintf("OIDC Scopes|%s", strings.Join(config.OIDCScopes, ",")),
+		fmt.Sprintf("Bound Audiences|%s", strings.Join(config.BoundAudiences, ",")),
+		fmt.Sprintf("Allowed Redirect URIs|%s", strings.Join(config.AllowedRedirectURIs, ",")),
+		fmt.Sprintf("Discovery CA PEM|%s", strings.Join(config.DiscoveryCaPem, ",")),
+		fmt.Sprintf("Signing Algorithms|%s", strings.Join(config.SigningAlgs, ",")),
+		fmt.Sprintf("Claim Mappings|%s", formatMap(config.ClaimMappings)),
+		fmt.Sprintf("List Claim Mappings|%s", formatMap(config.ListClaimMappings)),
+	})
+}
+
+// formatAuthMethodWithConfig formats the ACL auth method and its
+// configuration for console output.
+func formatAuthMethodWithConfig(authMethod *api.ACLAuthMethod) string {
+	out := formatAuthMethod(authMethod)
+	if authMethod.Config != nil {
+		out += "\n\n[bold]Auth Method Config[reset]\n\n" + formatAuthMethodConfig(authMethod.Config)
+	}
+	return out
+}
+
+// formatMap formats a map of strings as a comma separated list of key=value
+// pairs.
+func formatMap(m map[string]string) string {
+	out := make([]string, 0, len(m))
+	for k, v := range m {
+		out = append(out, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
+
+// parseAuthMethodConfig parses the auth method configuration passed via the
+// -config flag. The configuration is JSON encoded and can be read from a file
+// by prefixing the path with "@".
+func parseAuthMethodConfig(raw string) (*api.ACLAuthMethodConfig, error) {
+	data := []byte(raw)
+	if strings.HasPrefix(raw, "@") {
+		fileData, err := ioutil.ReadFile(raw[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %v", err)
+		}
+		data = fileData
+	}
+
+	var config api.ACLAuthMethodConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	return &config, nil
+}
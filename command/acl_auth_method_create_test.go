@@ -0,0 +1,86 @@
+package command
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodCreateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLAuthMethodCreateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Test the basic validation on the command.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "this-command-does-not-take-args"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method name must be specified using the -name flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{
+		"-address=" + url, "-name=acl-auth-method-cli-test", "-max-token-ttl=1h", "-token-locality=foo"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method token locality must be set to either 'local' or 'global'")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Write the auth method config to a file, so that we can test the file
+	// reading functionality.
+	configFile, err := os.CreateTemp("", "nomad-acl-auth-method-")
+	require.NoError(t, err)
+	defer os.Remove(configFile.Name())
+
+	_, err = configFile.WriteString(`{"OIDCDiscoveryURL":"http://example.com","OIDCClientID":"mock","AllowedRedirectURIs":["http://localhost:4649/oidc/callback"]}`)
+	require.NoError(t, err)
+	require.NoError(t, configFile.Close())
+
+	// Create the auth method.
+	args := []string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-name=acl-auth-method-cli-test",
+		"-max-token-ttl=1h", "-token-locality=local", "-default", "-config=@" + configFile.Name(),
+	}
+	require.Equal(t, 0, cmd.Run(args))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "acl-auth-method-cli-test")
+	require.Contains(t, s, "http://example.com")
+	require.Contains(t, s, "Auth Method Config")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Creating a second default auth method should fail.
+	args = []string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-name=acl-auth-method-cli-test-2",
+		"-max-token-ttl=1h", "-token-locality=local", "-default",
+		`-config={"OIDCDiscoveryURL":"http://example.com","OIDCClientID":"mock","AllowedRedirectURIs":["foo"]}`,
+	}
+	require.Equal(t, 1, cmd.Run(args))
+	require.Contains(t, ui.ErrorWriter.String(), "default auth method already exists")
+}
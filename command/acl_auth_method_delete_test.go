@@ -0,0 +1,59 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodDeleteCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLAuthMethodDeleteCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Try and delete more than one ACL auth method.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "acl-auth-method-1", "acl-auth-method-2"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Try deleting an auth method that does not exist.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "acl-auth-method-1"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method not found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL auth method.
+	authMethod := mock.ACLAuthMethod()
+	err := srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod})
+	require.NoError(t, err)
+
+	// Delete the existing ACL auth method.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, authMethod.Name}))
+	require.Contains(t, ui.OutputWriter.String(), fmt.Sprintf("ACL auth method %s successfully deleted", authMethod.Name))
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodListCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLAuthMethodListCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Perform a list straight away without any auth methods held in state.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID}))
+	require.Contains(t, ui.OutputWriter.String(), "No ACL auth methods found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL auth method.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Default = true
+	err := srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod})
+	require.NoError(t, err)
+
+	// Perform a listing to get the created auth method.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, authMethod.Name)
+	require.Contains(t, s, "OIDC")
+	require.Contains(t, s, "true")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Perform a listing using the JSON output format.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "-json"}))
+	require.Contains(t, ui.OutputWriter.String(), `"Name": "`+authMethod.Name+`"`)
+}
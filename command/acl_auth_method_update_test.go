@@ -0,0 +1,80 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodUpdateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLAuthMethodUpdateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Try calling the command without setting an auth method name.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Try calling the command without any flags to update.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "acl-auth-method-1"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Please provide at least one flag to update the ACL auth method")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Try updating an auth method that does not exist.
+	require.Equal(t, 1, cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-max-token-ttl=2h", "acl-auth-method-1"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL auth method not found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL auth method.
+	authMethod := mock.ACLAuthMethod()
+	err := srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod})
+	require.NoError(t, err)
+
+	// Update the max token TTL and make the auth method the default.
+	require.Equal(t, 0, cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-max-token-ttl=2h", "-default", authMethod.Name}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "2h0m0s")
+	require.Contains(t, s, "Default       = true")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Explicitly unset the default flag, ensuring the max token TTL is not
+	// modified.
+	require.Equal(t, 0, cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-default=false", authMethod.Name}))
+	s = ui.OutputWriter.String()
+	require.Contains(t, s, "2h0m0s")
+	require.Contains(t, s, "Default       = false")
+}
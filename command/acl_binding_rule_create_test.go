@@ -0,0 +1,84 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleCreateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLBindingRuleCreateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Test the basic validation on the command.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "this-command-does-not-take-args"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule auth method must be specified using the -auth-method flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-auth-method=auth0"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule bind type must be specified using the -bind-type flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-auth-method=auth0", "-bind-type=role"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule bind name must be specified using the -bind-name flag")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Creating a binding rule for an auth method that does not exist should
+	// fail.
+	args := []string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-auth-method=auth0",
+		"-bind-type=role", "-bind-name=engineering", `-selector="engineering" in list.roles`,
+	}
+	require.Equal(t, 1, cmd.Run(args))
+	require.Contains(t, ui.ErrorWriter.String(), "cannot find auth method auth0")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create the auth method and try again.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = "auth0"
+	err := srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod})
+	require.NoError(t, err)
+
+	require.Equal(t, 0, cmd.Run(args))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "Auth Method  = auth0")
+	require.Contains(t, s, "Bind Name    = engineering")
+	require.Contains(t, s, `Selector     = "\"engineering\" in list.roles"`)
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleDeleteCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLBindingRuleDeleteCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Try and delete more than one ACL binding rule.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "acl-binding-rule-1", "acl-binding-rule-2"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Try deleting a binding rule that does not exist.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "acl-binding-rule-1"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule not found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL binding rule.
+	bindingRule := mock.ACLBindingRule()
+	err := srv.Agent.Server().State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLBindingRule{bindingRule}, true)
+	require.NoError(t, err)
+
+	// Delete the existing ACL binding rule.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, bindingRule.ID}))
+	require.Contains(t, ui.OutputWriter.String(), fmt.Sprintf("ACL binding rule %s successfully deleted", bindingRule.ID))
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleInfoCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLBindingRuleInfoCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Perform a lookup without specifying a binding rule ID.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Perform a lookup of a binding rule that does not exist.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "acl-binding-rule-1"}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL binding rule not found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL binding rule.
+	bindingRule := mock.ACLBindingRule()
+	err := srv.Agent.Server().State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLBindingRule{bindingRule}, true)
+	require.NoError(t, err)
+
+	// Look up the binding rule.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, bindingRule.ID}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, bindingRule.ID)
+	require.Contains(t, s, "mocked-acl-binding-rule")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Look up the binding rule using the JSON output format.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "-json", bindingRule.ID}))
+	require.Contains(t, ui.OutputWriter.String(), `"BindName": "engineering"`)
+}
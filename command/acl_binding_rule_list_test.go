@@ -0,0 +1,60 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleListCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLBindingRuleListCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Perform a list straight away without any binding rules held in state.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID}))
+	require.Contains(t, ui.OutputWriter.String(), "No ACL binding rules found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL binding rule.
+	bindingRule := mock.ACLBindingRule()
+	err := srv.Agent.Server().State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLBindingRule{bindingRule}, true)
+	require.NoError(t, err)
+
+	// Perform a listing to get the created binding rule.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, bindingRule.ID)
+	require.Contains(t, s, "auth0")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Perform a listing using the JSON output format.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "-json"}))
+	require.Contains(t, ui.OutputWriter.String(), `"ID": "`+bindingRule.ID+`"`)
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleUpdateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLBindingRuleUpdateCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Try calling the command without setting a binding rule ID.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Try calling the command without any flags to update.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "acl-binding-rule-1"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Please provide at least one flag to update the ACL binding rule")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an auth method and binding rule.
+	authMethod := mock.ACLAuthMethod()
+	err := srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod})
+	require.NoError(t, err)
+
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.AuthMethod = authMethod.Name
+	err = srv.Agent.Server().State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLBindingRule{bindingRule}, false)
+	require.NoError(t, err)
+
+	// Update the description and remove the selector.
+	require.Equal(t, 0, cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID,
+		"-description=updated-description", "-selector=", bindingRule.ID}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "updated-description")
+	require.Contains(t, s, `Selector     = ""`)
+	require.Contains(t, s, "Bind Name    = engineering")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Switch the binding rule to a management bind type, which should clear
+	// the bind name.
+	require.Equal(t, 0, cmd.Run([]string{
+		"-address=" + url, "-token=" + rootACLToken.SecretID, "-bind-type=management", bindingRule.ID}))
+	s = ui.OutputWriter.String()
+	require.Contains(t, s, "Bind Type    = management")
+	require.Contains(t, s, "Bind Name    = <none>")
+}
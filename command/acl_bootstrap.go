@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
@@ -153,18 +154,40 @@ func formatKVACLToken(token *api.ACLToken) string {
 		fmt.Sprintf("Global|%v", token.Global),
 	}
 
-	// Special case the policy output
+	// Special case the policy and role output
 	if token.Type == "management" {
-		output = append(output, "Policies|n/a")
+		output = append(output, "Policies|n/a", "Roles|n/a")
 	} else {
-		output = append(output, fmt.Sprintf("Policies|%v", token.Policies))
+		output = append(output,
+			fmt.Sprintf("Policies|%v", token.Policies),
+			fmt.Sprintf("Roles|%v", formatACLTokenRoleLinks(token.Roles)),
+		)
 	}
 
 	// Add the generic output
 	output = append(output,
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
+		fmt.Sprintf("Expiry Time|%s", expiryTimeString(token.ExpirationTime)),
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	)
 	return formatKV(output)
 }
+
+// expiryTimeString returns the expiry time of an ACL token, or "<none>" if the
+// token does not expire.
+func expiryTimeString(t *time.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+	return t.String()
+}
+
+// formatACLTokenRoleLinks returns the IDs of the roles the token is linked to.
+func formatACLTokenRoleLinks(roleLinks []*api.ACLTokenRoleLink) []string {
+	roleIDs := make([]string, 0, len(roleLinks))
+	for _, roleLink := range roleLinks {
+		roleIDs = append(roleIDs, roleLink.ID)
+	}
+	return roleIDs
+}
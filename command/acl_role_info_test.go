@@ -0,0 +1,82 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleInfoCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	// Ensure we have a bootstrap token.
+	rootACLToken := srv.RootToken
+	require.NotNil(t, rootACLToken)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLRoleInfoCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Perform a lookup without specifying an ID.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes one argument: <acl_role_id>")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Perform a lookup specifying a random ID.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, uuid.Generate()}))
+	require.Contains(t, ui.ErrorWriter.String(), "ACL role not found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Create an ACL policy that can be referenced within the ACL role.
+	aclPolicy := structs.ACLPolicy{
+		Name:  "acl-role-policy-cli-test",
+		Rules: acl.PolicyWrite,
+	}
+	err := srv.Agent.Server().State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{&aclPolicy})
+	require.NoError(t, err)
+
+	// Create an ACL role referencing the previously created policy.
+	aclRole := structs.ACLRole{
+		ID:       uuid.Generate(),
+		Name:     "acl-role-cli-test",
+		Policies: []*structs.ACLRolePolicyLink{{Name: aclPolicy.Name}},
+	}
+	err = srv.Agent.Server().State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLRole{&aclRole}, false)
+	require.NoError(t, err)
+
+	// Look up the ACL role using its ID.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, aclRole.ID}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, "ID           = "+aclRole.ID)
+	require.Contains(t, s, "Name         = acl-role-cli-test")
+	require.Contains(t, s, "Policies     = acl-role-policy-cli-test")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Look up the ACL role using its name.
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-token=" + rootACLToken.SecretID, "-by-name", aclRole.Name}))
+	require.Contains(t, ui.OutputWriter.String(), "ID           = "+aclRole.ID)
+}
@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
@@ -36,6 +37,19 @@ Create Options:
   -policy=""
     Specifies a policy to associate with the token. Can be specified multiple times,
     but only with client type tokens.
+
+  -role-id=""
+    ID of a role to use for this token. Can be specified multiple times, but only
+    with client type tokens.
+
+  -role-name=""
+    Name of a role to use for this token. Can be specified multiple times, but
+    only with client type tokens.
+
+  -ttl
+    Specifies the time-to-live of the created ACL token. This takes the form of
+    a time duration such as "5m" and "1h". By default, tokens will be created
+    without a TTL and therefore never expire.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -43,10 +57,13 @@ Create Options:
 func (c *ACLTokenCreateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"name":   complete.PredictAnything,
-			"type":   complete.PredictAnything,
-			"global": complete.PredictNothing,
-			"policy": complete.PredictAnything,
+			"name":      complete.PredictAnything,
+			"type":      complete.PredictAnything,
+			"global":    complete.PredictNothing,
+			"policy":    complete.PredictAnything,
+			"role-id":   complete.PredictAnything,
+			"role-name": complete.PredictAnything,
+			"ttl":       complete.PredictAnything,
 		})
 }
 
@@ -61,18 +78,27 @@ func (c *ACLTokenCreateCommand) Synopsis() string {
 func (c *ACLTokenCreateCommand) Name() string { return "acl token create" }
 
 func (c *ACLTokenCreateCommand) Run(args []string) int {
-	var name, tokenType string
+	var name, tokenType, ttl string
 	var global bool
-	var policies []string
+	var policies, roleIDs, roleNames []string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&name, "name", "", "")
 	flags.StringVar(&tokenType, "type", "client", "")
 	flags.BoolVar(&global, "global", false, "")
+	flags.StringVar(&ttl, "ttl", "", "")
 	flags.Var((funcVar)(func(s string) error {
 		policies = append(policies, s)
 		return nil
 	}), "policy", "")
+	flags.Var((funcVar)(func(s string) error {
+		roleIDs = append(roleIDs, s)
+		return nil
+	}), "role-id", "")
+	flags.Var((funcVar)(func(s string) error {
+		roleNames = append(roleNames, s)
+		return nil
+	}), "role-name", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -90,9 +116,21 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		Name:     name,
 		Type:     tokenType,
 		Policies: policies,
+		Roles:    generateACLTokenRoleLinks(roleNames, roleIDs),
 		Global:   global,
 	}
 
+	// If the user set a TTL flag value, convert this to a time duration and
+	// add it to our token request object.
+	if ttl != "" {
+		ttlDuration, err := time.ParseDuration(ttl)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse TTL as time duration: %s", err))
+			return 1
+		}
+		tk.ExpirationTTL = ttlDuration
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -111,3 +149,28 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 	c.Ui.Output(formatKVACLToken(token))
 	return 0
 }
+
+// generateACLTokenRoleLinks takes the command input role links by name and
+// ID and converts this to the relevant API objects. Duplicate entries are
+// removed; the leader resolves the names to IDs.
+func generateACLTokenRoleLinks(roleNames, roleIDs []string) []*api.ACLTokenRoleLink {
+	var tokenLinks []*api.ACLTokenRoleLink
+
+	seenNames := make(map[string]struct{}, len(roleNames))
+	for _, name := range roleNames {
+		if _, ok := seenNames[name]; !ok {
+			seenNames[name] = struct{}{}
+			tokenLinks = append(tokenLinks, &api.ACLTokenRoleLink{Name: name})
+		}
+	}
+
+	seenIDs := make(map[string]struct{}, len(roleIDs))
+	for _, id := range roleIDs {
+		if _, ok := seenIDs[id]; !ok {
+			seenIDs[id] = struct{}{}
+			tokenLinks = append(tokenLinks, &api.ACLTokenRoleLink{ID: id})
+		}
+	}
+
+	return tokenLinks
+}
@@ -40,4 +40,23 @@ func TestACLTokenCreateCommand(t *testing.T) {
 	if !strings.Contains(out, "[foo]") {
 		t.Fatalf("bad: %v", out)
 	}
+	assert.Contains(out, "Expiry Time  = <none>")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Request to create a new token with an invalid TTL
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-type=management", "-ttl=soon"})
+	assert.Equal(1, code)
+	assert.Contains(ui.ErrorWriter.String(), "Failed to parse TTL as time duration")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Request to create a new token with a valid TTL
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-type=management", "-ttl=10m"})
+	assert.Equal(0, code)
+	out = ui.OutputWriter.String()
+	assert.NotContains(out, "Expiry Time  = <none>")
+	assert.Contains(out, "Expiry Time")
 }
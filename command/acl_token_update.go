@@ -35,6 +35,14 @@ Update Options:
   -policy=""
     Specifies a policy to associate with the token. Can be specified multiple times,
     but only with client type tokens.
+
+  -role-id=""
+    ID of a role to use for this token. Can be specified multiple times, but only
+    with client type tokens.
+
+  -role-name=""
+    Name of a role to use for this token. Can be specified multiple times, but
+    only with client type tokens.
 `
 
 	return strings.TrimSpace(helpText)
@@ -43,10 +51,12 @@ Update Options:
 func (c *ACLTokenUpdateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"name":   complete.PredictAnything,
-			"type":   complete.PredictAnything,
-			"global": complete.PredictNothing,
-			"policy": complete.PredictAnything,
+			"name":      complete.PredictAnything,
+			"type":      complete.PredictAnything,
+			"global":    complete.PredictNothing,
+			"policy":    complete.PredictAnything,
+			"role-id":   complete.PredictAnything,
+			"role-name": complete.PredictAnything,
 		})
 }
 
@@ -63,7 +73,7 @@ func (*ACLTokenUpdateCommand) Name() string { return "acl token update" }
 func (c *ACLTokenUpdateCommand) Run(args []string) int {
 	var name, tokenType string
 	var global bool
-	var policies []string
+	var policies, roleIDs, roleNames []string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&name, "name", "", "")
@@ -73,6 +83,14 @@ func (c *ACLTokenUpdateCommand) Run(args []string) int {
 		policies = append(policies, s)
 		return nil
 	}), "policy", "")
+	flags.Var((funcVar)(func(s string) error {
+		roleIDs = append(roleIDs, s)
+		return nil
+	}), "role-id", "")
+	flags.Var((funcVar)(func(s string) error {
+		roleNames = append(roleNames, s)
+		return nil
+	}), "role-name", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -119,6 +137,10 @@ func (c *ACLTokenUpdateCommand) Run(args []string) int {
 		token.Policies = policies
 	}
 
+	if len(roleIDs) != 0 || len(roleNames) != 0 {
+		token.Roles = generateACLTokenRoleLinks(roleNames, roleIDs)
+	}
+
 	// Update the token
 	updatedToken, _, err := client.ACLTokens().Update(token, nil)
 	if err != nil {
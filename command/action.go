@@ -0,0 +1,273 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type ActionCommand struct {
+	Meta
+
+	Stdout io.WriteCloser
+	Stderr io.WriteCloser
+}
+
+func (c *ActionCommand) Help() string {
+	helpText := `
+Usage: nomad action [options] <action>
+
+  Run an action defined by a task of a job in one of the task's running
+  allocations, and stream its output. The exit code of the command is the exit
+  code of the action.
+
+  When ACLs are enabled, this command requires a token with the 'read-job' and
+  'list-jobs' capabilities, and either the 'alloc-action' or 'alloc-exec'
+  capability for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Action Options:
+
+  -job <job-id>
+    Sets the job defining the action. Required.
+
+  -group <group-name>
+    Sets the group of the task defining the action. Only required if the
+    action is defined by several tasks of the job.
+
+  -task <task-name>
+    Sets the task defining the action. Only required if the action is defined
+    by several tasks of the job.
+
+  -alloc <alloc-id>
+    Sets the allocation to run the action in. Defaults to a random running
+    allocation of the task's group.
+  `
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ActionCommand) Synopsis() string {
+	return "Run an action defined by a task"
+}
+
+func (c *ActionCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Jobs]
+			}),
+			"-group": complete.PredictAnything,
+			"-task":  complete.PredictAnything,
+			"-alloc": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Allocs]
+			}),
+		})
+}
+
+func (c *ActionCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ActionCommand) Name() string { return "action" }
+
+func (c *ActionCommand) Run(args []string) int {
+	var jobID, group, task, allocID string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&jobID, "job", "", "")
+	flags.StringVar(&group, "group", "", "")
+	flags.StringVar(&task, "task", "", "")
+	flags.StringVar(&allocID, "alloc", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <action>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	if jobID == "" {
+		c.Ui.Error("A job ID is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	// Find the task defining the action
+	actions, _, err := client.Jobs().Actions(jobID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job actions: %v", err))
+		return 1
+	}
+	action, err := findJobAction(actions, jobID, group, task, name)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var allocStub *api.AllocationListStub
+	if allocID != "" {
+		allocStub, err = getJobGroupAllocByPrefix(client, jobID, action.TaskGroup, allocID)
+	} else {
+		allocStub, err = getRandomJobGroupAlloc(client, jobID, action.TaskGroup)
+	}
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	q := &api.QueryOptions{Namespace: allocStub.Namespace}
+	alloc, _, err := client.Allocations().Info(allocStub.ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	if c.Stdout == nil {
+		c.Stdout = os.Stdout
+	}
+	if c.Stderr == nil {
+		c.Stderr = os.Stderr
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		for range signalCh {
+			cancelFn()
+		}
+	}()
+
+	code, err := client.Allocations().ExecAction(ctx, alloc, action.Task, action.Name,
+		c.Stdout, c.Stderr, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running action %q: %v", name, err))
+		return 1
+	}
+
+	return code
+}
+
+// findJobAction returns the action with the given name, optionally filtered
+// by the group and task defining it. It fails unless exactly one task of the
+// job defines a matching action.
+func findJobAction(actions []*api.JobAction, jobID, group, task, name string) (*api.JobAction, error) {
+	var matches []*api.JobAction
+	for _, a := range actions {
+		if a.Name != name || (group != "" && a.TaskGroup != group) || (task != "" && a.Task != task) {
+			continue
+		}
+		matches = append(matches, a)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("Action %q not found in job %q", name, jobID)
+	case 1:
+		return matches[0], nil
+	}
+
+	tasks := make([]string, 0, len(matches))
+	for _, a := range matches {
+		tasks = append(tasks, fmt.Sprintf("%s.%s", a.TaskGroup, a.Task))
+	}
+	sort.Strings(tasks)
+	return nil, fmt.Errorf("Action %q is defined by multiple tasks, please specify -group and -task: %s",
+		name, strings.Join(tasks, ", "))
+}
+
+// getJobGroupAllocByPrefix returns the allocation of a job's group with the
+// given ID prefix.
+func getJobGroupAllocByPrefix(client *api.Client, jobID, group, allocID string) (*api.AllocationListStub, error) {
+	if len(allocID) == 1 {
+		return nil, fmt.Errorf("Alloc ID must contain at least two characters")
+	}
+
+	allocs, _, err := client.Allocations().PrefixList(sanitizeUUIDPrefix(allocID))
+	if err != nil {
+		return nil, fmt.Errorf("Error querying allocation: %v", err)
+	}
+	if len(allocs) == 0 {
+		return nil, fmt.Errorf("No allocation(s) with prefix or id %q found", allocID)
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, false, shortId)
+		return nil, fmt.Errorf("Prefix matched multiple allocations\n\n%s", out)
+	}
+
+	alloc := allocs[0]
+	if alloc.JobID != jobID || alloc.TaskGroup != group {
+		return nil, fmt.Errorf("Allocation %q is not an allocation of group %q of job %q",
+			limit(alloc.ID, shortId), group, jobID)
+	}
+	return alloc, nil
+}
+
+// getRandomJobGroupAlloc returns a random running allocation of a job's
+// group.
+func getRandomJobGroupAlloc(client *api.Client, jobID, group string) (*api.AllocationListStub, error) {
+	allocs, _, err := client.Jobs().Allocations(jobID, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job %q: %v", jobID, err)
+	}
+
+	var runningAllocs []*api.AllocationListStub
+	for _, alloc := range allocs {
+		if alloc.TaskGroup == group && alloc.ClientStatus == api.AllocClientStatusRunning {
+			runningAllocs = append(runningAllocs, alloc)
+		}
+	}
+	if len(runningAllocs) == 0 {
+		return nil, fmt.Errorf("No running allocations of group %q of job %q", group, jobID)
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return runningAllocs[r.Intn(len(runningAllocs))], nil
+}
@@ -0,0 +1,165 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+// static check
+var _ cli.Command = &ActionCommand{}
+
+func TestActionCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	cases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			"action missing",
+			[]string{"-job", "example"},
+			`This command takes one argument: <action>`,
+		},
+		{
+			"job missing",
+			[]string{"flush-cache"},
+			`A job ID is required`,
+		},
+		{
+			"job not found",
+			[]string{"-address=" + url, "-job", "example", "flush-cache"},
+			`Error querying job actions`,
+		},
+		{
+			"connection failure",
+			[]string{"-address=nope", "-job", "example", "flush-cache"},
+			`Error querying job actions`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &ActionCommand{Meta: Meta{Ui: ui}}
+
+			code := cmd.Run(c.args)
+			require.Equal(t, 1, code)
+			require.Contains(t, ui.ErrorWriter.String(), c.expectedError)
+		})
+	}
+}
+
+func TestActionCommand_findJobAction(t *testing.T) {
+	ci.Parallel(t)
+
+	actions := []*api.JobAction{
+		{TaskGroup: "web", Task: "app", Name: "flush-cache"},
+		{TaskGroup: "web", Task: "app", Name: "rotate-logs"},
+		{TaskGroup: "cache", Task: "redis", Name: "flush-cache"},
+	}
+
+	_, err := findJobAction(actions, "example", "", "", "missing")
+	require.EqualError(t, err, `Action "missing" not found in job "example"`)
+
+	_, err = findJobAction(actions, "example", "", "", "flush-cache")
+	require.EqualError(t, err, `Action "flush-cache" is defined by multiple tasks, please specify -group and -task: cache.redis, web.app`)
+
+	action, err := findJobAction(actions, "example", "cache", "", "flush-cache")
+	require.NoError(t, err)
+	require.Equal(t, actions[2], action)
+
+	action, err = findJobAction(actions, "example", "", "", "rotate-logs")
+	require.NoError(t, err)
+	require.Equal(t, actions[1], action)
+}
+
+func TestActionCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for a node to be ready
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+
+		for _, node := range nodes {
+			if _, ok := node.Drivers["mock_driver"]; ok &&
+				node.Status == structs.NodeStatusReady {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no ready nodes")
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	jobID := uuid.Generate()
+	job := testJob(jobID)
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "10s",
+		"exec_command": map[string]interface{}{
+			"run_for":       "1ms",
+			"exit_code":     21,
+			"stdout_string": "cache flushed\n",
+		},
+	}
+	job.TaskGroups[0].Tasks[0].Actions = []*api.Action{
+		{Name: "flush-cache", Command: "redis-cli", Args: []string{"FLUSHALL"}},
+	}
+	resp, _, err := client.Jobs().Register(job, nil)
+	require.NoError(t, err)
+
+	evalUi := cli.NewMockUi()
+	code := waitForSuccess(evalUi, client, fullId, t, resp.EvalID)
+	require.Equal(t, 0, code, "failed to get status - output: %v", evalUi.ErrorWriter.String())
+
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations(jobID, false, nil)
+		if err != nil {
+			return false, fmt.Errorf("failed to get allocations: %v", err)
+		}
+		if len(allocs) == 0 {
+			return false, fmt.Errorf("no allocations yet")
+		}
+		if allocs[0].ClientStatus != "running" {
+			return false, fmt.Errorf("alloc is not running yet: %v", allocs[0].ClientStatus)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	// The output and exit code of the action are returned
+	ui := cli.NewMockUi()
+	var stdout, stderr bufferCloser
+	cmd := &ActionCommand{
+		Meta:   Meta{Ui: ui},
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	code = cmd.Run([]string{"-address=" + url, "-job", jobID, "flush-cache"})
+	require.Equal(t, 21, code, "output: %v", ui.ErrorWriter.String())
+	require.Equal(t, "cache flushed", strings.TrimSpace(stdout.String()))
+
+	// Unknown actions aren't run
+	ui = cli.NewMockUi()
+	cmd = &ActionCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-job", jobID, "-task", "other", "flush-cache"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), `Action "flush-cache" not found`)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -558,3 +559,287 @@ func TestHTTP_OneTimeToken(t *testing.T) {
 		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
 	})
 }
+
+func TestHTTPServer_ACLRoleRequests(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+
+		// Create the policies the ACL roles will link to.
+		policy1 := mock.ACLPolicy()
+		policy1.Name = "foo"
+		policy2 := mock.ACLPolicy()
+		policy2.Name = "bar"
+		policyArgs := structs.ACLPolicyUpsertRequest{
+			Policies: []*structs.ACLPolicy{policy1, policy2},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var policyResp structs.GenericResponse
+		require.NoError(t, s.Agent.RPC("ACL.UpsertPolicies", &policyArgs, &policyResp))
+
+		// Create an ACL role using the HTTP API.
+		aclRole := mock.ACLRole()
+		aclRole.ID = ""
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/role", encodeReq(aclRole))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLRoleRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		createdRole := obj.(*structs.ACLRole)
+		require.NotEmpty(t, createdRole.ID)
+		require.Equal(t, aclRole.Name, createdRole.Name)
+
+		// List the ACL roles.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/roles", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLRoleListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.ACLRoleListStub), 1)
+
+		// Read the ACL role by its ID.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/role/"+createdRole.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, createdRole.Hash, obj.(*structs.ACLRole).Hash)
+
+		// Read the ACL role by its name.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/role/name/"+createdRole.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, createdRole.ID, obj.(*structs.ACLRole).ID)
+
+		// Update the ACL role using a mismatched path ID; this should fail.
+		updatedRole := createdRole.Copy()
+		updatedRole.Description = "updated-description"
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/role/not-the-id", encodeReq(updatedRole))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "does not match request path")
+
+		// Update the ACL role correctly.
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/role/"+createdRole.ID, encodeReq(updatedRole))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, "updated-description", obj.(*structs.ACLRole).Description)
+
+		// Delete the ACL role.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/role/"+createdRole.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Reading the deleted role should result in a not found error.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/role/"+createdRole.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "ACL role not found")
+	})
+}
+
+func TestHTTPServer_ACLAuthMethodRequests(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+
+		// Create an ACL auth method using the HTTP API.
+		authMethod := mock.ACLAuthMethod()
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/auth-method", encodeReq(authMethod))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLAuthMethodRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		createdMethod := obj.(*structs.ACLAuthMethod)
+		require.Equal(t, authMethod.Name, createdMethod.Name)
+
+		// List the ACL auth methods. This does not require a token.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-methods", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.ACLAuthMethodListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.ACLAuthMethodStub), 1)
+
+		// Read the ACL auth method by its name.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-method/"+createdMethod.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, createdMethod.Hash, obj.(*structs.ACLAuthMethod).Hash)
+
+		// Update the ACL auth method using a mismatched path name; this
+		// should fail.
+		updatedMethod := createdMethod.Copy()
+		updatedMethod.MaxTokenTTL = 2 * time.Hour
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/auth-method/not-the-name", encodeReq(updatedMethod))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "does not match request path")
+
+		// Update the ACL auth method correctly.
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/auth-method/"+createdMethod.Name, encodeReq(updatedMethod))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Hour, obj.(*structs.ACLAuthMethod).MaxTokenTTL)
+
+		// Delete the ACL auth method.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/auth-method/"+createdMethod.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Reading the deleted auth method should result in a not found error.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/auth-method/"+createdMethod.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "ACL auth method not found")
+	})
+}
+
+func TestHTTPServer_ACLBindingRuleRequests(t *testing.T) {
+	ci.Parallel(t)
+	httpACLTest(t, nil, func(s *TestAgent) {
+
+		// Create the auth method the ACL binding rule will link to.
+		authMethod := mock.ACLAuthMethod()
+		authMethodArgs := structs.ACLAuthMethodsUpsertRequest{
+			AuthMethods: []*structs.ACLAuthMethod{authMethod},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: s.RootToken.SecretID,
+			},
+		}
+		var authMethodResp structs.ACLAuthMethodsUpsertResponse
+		require.NoError(t, s.Agent.RPC(structs.ACLUpsertAuthMethodsRPCMethod, &authMethodArgs, &authMethodResp))
+
+		// Create an ACL binding rule using the HTTP API.
+		bindingRule := mock.ACLBindingRule()
+		bindingRule.ID = ""
+		bindingRule.AuthMethod = authMethod.Name
+		req, err := http.NewRequest(http.MethodPut, "/v1/acl/binding-rule", encodeReq(bindingRule))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLBindingRuleRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		createdRule := obj.(*structs.ACLBindingRule)
+		require.NotEmpty(t, createdRule.ID)
+		require.Equal(t, authMethod.Name, createdRule.AuthMethod)
+
+		// List the ACL binding rules.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rules", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLBindingRuleListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.ACLBindingRuleListStub), 1)
+
+		// Read the ACL binding rule by its ID.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rule/"+createdRule.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, createdRule.Hash, obj.(*structs.ACLBindingRule).Hash)
+
+		// Update the ACL binding rule using a mismatched path ID; this
+		// should fail.
+		updatedRule := createdRule.Copy()
+		updatedRule.Description = "updated-description"
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/binding-rule/not-the-id", encodeReq(updatedRule))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "does not match request path")
+
+		// Update the ACL binding rule correctly.
+		req, err = http.NewRequest(http.MethodPost, "/v1/acl/binding-rule/"+createdRule.ID, encodeReq(updatedRule))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, "updated-description", obj.(*structs.ACLBindingRule).Description)
+
+		// Delete the ACL binding rule.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/acl/binding-rule/"+createdRule.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Reading the deleted binding rule should result in a not found
+		// error.
+		req, err = http.NewRequest(http.MethodGet, "/v1/acl/binding-rule/"+createdRule.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "ACL binding rule not found")
+	})
+}
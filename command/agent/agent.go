@@ -15,6 +15,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/dustin/go-humanize"
 	consulapi "github.com/hashicorp/consul/api"
 	log "github.com/hashicorp/go-hclog"
 	uuidparse "github.com/hashicorp/go-uuid"
@@ -238,6 +239,12 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.ACL.ReplicationToken != "" {
 		conf.ReplicationToken = agentConfig.ACL.ReplicationToken
 	}
+	if agentConfig.ACL.TokenMinExpirationTTL != 0 {
+		conf.ACLTokenMinExpirationTTL = agentConfig.ACL.TokenMinExpirationTTL
+	}
+	if agentConfig.ACL.TokenMaxExpirationTTL != 0 {
+		conf.ACLTokenMaxExpirationTTL = agentConfig.ACL.TokenMaxExpirationTTL
+	}
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
@@ -374,6 +381,13 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.CSIPluginGCThreshold = dur
 	}
+	if gcThreshold := agentConfig.Server.ACLTokenGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.ACLTokenExpirationGCThreshold = dur
+	}
 
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
@@ -436,6 +450,24 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		return nil, fmt.Errorf("deploy_query_rate_limit must be greater than 0")
 	}
 
+	// Set the job submission size limit
+	if size := agentConfig.Server.JobMaxSourceSize; size != nil {
+		limit, err := humanize.ParseBytes(*size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse job_max_source_size: %v", err)
+		}
+		conf.JobMaxSourceSize = int(limit)
+	}
+
+	// Set the directory file event sinks are confined to. Without a data
+	// directory, as in dev mode, file sinks stay disabled unless configured.
+	if conf.DataDir != "" {
+		conf.EventSinkFileDir = filepath.Join(conf.DataDir, "event_sinks")
+	}
+	if dir := agentConfig.Server.EventSinkFileDir; dir != "" {
+		conf.EventSinkFileDir = dir
+	}
+
 	// Add Enterprise license configs
 	conf.LicenseEnv = agentConfig.Server.LicenseEnv
 	conf.LicensePath = agentConfig.Server.LicensePath
@@ -570,6 +602,8 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.DataDir != "" {
 		conf.StateDir = filepath.Join(agentConfig.DataDir, "client")
 		conf.AllocDir = filepath.Join(agentConfig.DataDir, "alloc")
+		conf.HostVolumesDir = filepath.Join(agentConfig.DataDir, "host_volumes")
+		conf.HostVolumePluginDir = filepath.Join(agentConfig.DataDir, "host_volume_plugins")
 	}
 	if agentConfig.Client.StateDir != "" {
 		conf.StateDir = agentConfig.Client.StateDir
@@ -577,6 +611,12 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.Client.AllocDir != "" {
 		conf.AllocDir = agentConfig.Client.AllocDir
 	}
+	if agentConfig.Client.HostVolumesDir != "" {
+		conf.HostVolumesDir = agentConfig.Client.HostVolumesDir
+	}
+	if agentConfig.Client.HostVolumePluginDir != "" {
+		conf.HostVolumePluginDir = agentConfig.Client.HostVolumePluginDir
+	}
 	if agentConfig.Client.NetworkInterface != "" {
 		conf.NetworkInterface = agentConfig.Client.NetworkInterface
 	}
@@ -620,6 +660,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.Node.Name = agentConfig.NodeName
 	conf.Node.Meta = agentConfig.Client.Meta
 	conf.Node.NodeClass = agentConfig.Client.NodeClass
+	conf.Node.NodePool = agentConfig.Client.NodePool
 
 	// Set up the HTTP advertise address
 	conf.Node.HTTPAddr = agentConfig.AdvertiseAddrs.HTTP
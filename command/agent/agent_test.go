@@ -472,6 +472,42 @@ func TestAgent_ServerConfig_RaftMultiplier_Bad(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_JobMaxSourceSize(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		size   *string
+		exp    int
+		expErr string
+	}{
+		{size: nil, exp: 1e6},
+		{size: helper.StringToPtr("2MB"), exp: 2e6},
+		{size: helper.StringToPtr("0"), exp: 0},
+		{size: helper.StringToPtr("lots"), expErr: "failed to parse job_max_source_size"},
+	}
+
+	for _, tc := range cases {
+		v := "default"
+		if tc.size != nil {
+			v = *tc.size
+		}
+		t.Run(v, func(t *testing.T) {
+			conf := DevConfig(nil)
+			require.NoError(t, conf.normalizeAddrs())
+
+			conf.Server.JobMaxSourceSize = tc.size
+
+			serverConf, err := convertServerConfig(conf)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, serverConf.JobMaxSourceSize)
+		})
+	}
+}
+
 func TestAgent_ClientConfig(t *testing.T) {
 	ci.Parallel(t)
 	conf := DefaultConfig()
@@ -1326,3 +1362,25 @@ func TestAgent_ProxyRPC_Dev(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 }
+
+func TestAgent_ServerConfig_EventSinkFileDir(t *testing.T) {
+	ci.Parallel(t)
+
+	// Dev agents have no data directory, so file sinks are disabled
+	conf := DevConfig(nil)
+	require.NoError(t, conf.normalizeAddrs())
+	serverConf, err := convertServerConfig(conf)
+	require.NoError(t, err)
+	require.Empty(t, serverConf.EventSinkFileDir)
+
+	// The directory defaults to being within the server data directory
+	conf.DataDir = "/var/lib/nomad"
+	serverConf, err = convertServerConfig(conf)
+	require.NoError(t, err)
+	require.Equal(t, "/var/lib/nomad/server/event_sinks", serverConf.EventSinkFileDir)
+
+	conf.Server.EventSinkFileDir = "/var/log/nomad/events"
+	serverConf, err = convertServerConfig(conf)
+	require.NoError(t, err)
+	require.Equal(t, "/var/log/nomad/events", serverConf.EventSinkFileDir)
+}
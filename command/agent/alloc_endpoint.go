@@ -149,9 +149,19 @@ func (s *HTTPServer) allocStop(allocID string, resp http.ResponseWriter, req *ht
 		}
 	}
 
+	unpin := false
+	if unpinQS := req.URL.Query().Get("unpin"); unpinQS != "" {
+		var err error
+		unpin, err = strconv.ParseBool(unpinQS)
+		if err != nil {
+			return nil, fmt.Errorf("unpin value is not a boolean: %v", err)
+		}
+	}
+
 	sr := &structs.AllocStopRequest{
 		AllocID:         allocID,
 		NoShutdownDelay: noShutdownDelay,
+		Unpin:           unpin,
 	}
 	s.parseWriteRequest(req, &sr.WriteRequest)
 
@@ -439,12 +449,18 @@ func (s *HTTPServer) allocStats(allocID string, resp http.ResponseWriter, req *h
 func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Build the request and parse the ACL token
 	task := req.URL.Query().Get("task")
+	action := req.URL.Query().Get("action")
 	cmdJsonStr := req.URL.Query().Get("command")
 	var command []string
-	err := json.Unmarshal([]byte(cmdJsonStr), &command)
-	if err != nil {
-		// this shouldn't happen, []string is always be serializable to json
-		return nil, fmt.Errorf("failed to marshal command into json: %v", err)
+	var err error
+
+	// Task actions are run without a command
+	if action == "" || cmdJsonStr != "" {
+		err = json.Unmarshal([]byte(cmdJsonStr), &command)
+		if err != nil {
+			// this shouldn't happen, []string is always be serializable to json
+			return nil, fmt.Errorf("failed to marshal command into json: %v", err)
+		}
 	}
 
 	ttyB := false
@@ -459,6 +475,7 @@ func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *ht
 		AllocID: allocID,
 		Task:    task,
 		Cmd:     command,
+		Action:  action,
 		Tty:     ttyB,
 	}
 	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
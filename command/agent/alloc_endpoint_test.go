@@ -417,6 +417,22 @@ func TestHTTP_AllocStop(t *testing.T) {
 			require.Equal(a.Index, headerIndex)
 		}
 
+		// Test that the replacement can be unpinned
+		{
+			// Make the HTTP request
+			req, err := http.NewRequest("POST", "/v1/allocation/"+alloc.ID+"/stop?unpin=true", nil)
+			require.NoError(err)
+			respW := httptest.NewRecorder()
+
+			// Make the request
+			_, err = s.Server.AllocSpecificRequest(respW, req)
+			require.NoError(err)
+
+			out, err := state.AllocByID(nil, alloc.ID)
+			require.NoError(err)
+			require.True(out.DesiredTransition.ShouldUnpin())
+		}
+
 		// Test that we 404 when the allocid is invalid
 		{
 			// Make the HTTP request
@@ -103,6 +103,7 @@ func (c *Command) readConfig() *Config {
 	flags.StringVar(&cmdConfig.Client.StateDir, "state-dir", "", "")
 	flags.StringVar(&cmdConfig.Client.AllocDir, "alloc-dir", "", "")
 	flags.StringVar(&cmdConfig.Client.NodeClass, "node-class", "", "")
+	flags.StringVar(&cmdConfig.Client.NodePool, "node-pool", "", "")
 	flags.StringVar(&servers, "servers", "", "")
 	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
 	flags.StringVar(&cmdConfig.Client.NetworkInterface, "network-interface", "", "")
@@ -365,6 +366,17 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 				return false
 			}
 		}
+
+		if pool := config.Client.NodePool; pool != "" {
+			if pool == structs.NodePoolAll {
+				c.Ui.Error(fmt.Sprintf("Invalid node pool: node is not allowed to register in node pool %q", structs.NodePoolAll))
+				return false
+			}
+			if !structs.ValidNodePoolName(pool) {
+				c.Ui.Error(fmt.Sprintf("Invalid node pool: %q", pool))
+				return false
+			}
+		}
 	}
 
 	if err := config.Server.DefaultSchedulerConfig.Validate(); err != nil {
@@ -610,6 +622,7 @@ func (c *Command) AutocompleteFlags() complete.Flags {
 		"-state-dir":                     complete.PredictDirs("*"),
 		"-alloc-dir":                     complete.PredictDirs("*"),
 		"-node-class":                    complete.PredictAnything,
+		"-node-pool":                     complete.PredictAnything,
 		"-servers":                       complete.PredictAnything,
 		"-meta":                          complete.PredictAnything,
 		"-config":                        configFilePredictor,
@@ -1382,6 +1395,10 @@ Client Options:
     Mark this node as a member of a node-class. This can be used to label
     similar node types.
 
+  -node-pool
+    Register this node in the given node pool. Jobs are only placed on nodes
+    of the node pool they target. Defaults to the "default" node pool.
+
   -meta
     User specified metadata to associated with the node. Each instance of -meta
     parses a single KEY=VALUE pair. Repeat the meta flag for each key/value pair
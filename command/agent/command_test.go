@@ -289,6 +289,26 @@ func TestIsValidConfig(t *testing.T) {
 			},
 			err: "Datacenter contains",
 		},
+		{
+			name: "InvalidNodePool",
+			conf: Config{
+				Client: &ClientConfig{
+					Enabled:  true,
+					NodePool: "not@valid",
+				},
+			},
+			err: "Invalid node pool",
+		},
+		{
+			name: "NodePoolAll",
+			conf: Config{
+				Client: &ClientConfig{
+					Enabled:  true,
+					NodePool: "all",
+				},
+			},
+			err: "not allowed to register in node pool",
+		},
 		{
 			name: "RelativeDir",
 			conf: Config{
@@ -197,6 +197,9 @@ type ClientConfig struct {
 	// NodeClass is used to group the node by class
 	NodeClass string `hcl:"node_class"`
 
+	// NodePool is the node pool the node belongs to
+	NodePool string `hcl:"node_pool"`
+
 	// Options is used for configuration of nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -288,6 +291,14 @@ type ClientConfig struct {
 	// available to jobs running on this node.
 	HostVolumes []*structs.ClientHostVolumeConfig `hcl:"host_volume"`
 
+	// HostVolumesDir is the directory in which the built-in host volume
+	// plugin creates dynamic host volumes
+	HostVolumesDir string `hcl:"host_volumes_dir"`
+
+	// HostVolumePluginDir is the directory searched for external host volume
+	// plugins
+	HostVolumePluginDir string `hcl:"host_volume_plugin_dir"`
+
 	// CNIPath is the path to search for CNI plugins, multiple paths can be
 	// specified colon delimited
 	CNIPath string `hcl:"cni_path"`
@@ -350,6 +361,18 @@ type ACLConfig struct {
 	PolicyTTL    time.Duration
 	PolicyTTLHCL string `hcl:"policy_ttl" json:"-"`
 
+	// TokenMinExpirationTTL is used to enforce the lowest acceptable value for
+	// ACL token expiration. This is used by the Nomad servers to validate ACL
+	// tokens with an expiration value set upon creation.
+	TokenMinExpirationTTL    time.Duration
+	TokenMinExpirationTTLHCL string `hcl:"token_min_expiration_ttl" json:"-"`
+
+	// TokenMaxExpirationTTL is used to enforce the highest acceptable value
+	// for ACL token expiration. This is used by the Nomad servers to validate
+	// ACL tokens with an expiration value set upon creation.
+	TokenMaxExpirationTTL    time.Duration
+	TokenMaxExpirationTTLHCL string `hcl:"token_max_expiration_ttl" json:"-"`
+
 	// ReplicationToken is used by servers to replicate tokens and policies
 	// from the authoritative region. This must be a valid management token
 	// within the authoritative region.
@@ -431,6 +454,10 @@ type ServerConfig struct {
 	// GCed but the threshold can be used to filter by age.
 	CSIPluginGCThreshold string `hcl:"csi_plugin_gc_threshold"`
 
+	// ACLTokenGCThreshold controls how long an ACL token must be expired
+	// before it is garbage collected.
+	ACLTokenGCThreshold string `hcl:"acl_token_gc_threshold"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace    time.Duration
@@ -535,6 +562,15 @@ type ServerConfig struct {
 
 	// RaftBoltConfig configures boltdb as used by raft.
 	RaftBoltConfig *RaftBoltConfig `hcl:"raft_boltdb"`
+
+	// JobMaxSourceSize limits the size of the original job source and
+	// variables stored with each job version, such as "1MB". Submissions over
+	// the limit are not stored, and a size of 0 disables storing them.
+	JobMaxSourceSize *string `hcl:"job_max_source_size"`
+
+	// EventSinkFileDir is the directory file event sinks must write under.
+	// Defaults to "event_sinks" within the server data directory.
+	EventSinkFileDir string `hcl:"event_sink_file_dir"`
 }
 
 // RaftBoltConfig is used in servers to configure parameters of the boltdb
@@ -1470,6 +1506,18 @@ func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	if b.PolicyTTLHCL != "" {
 		result.PolicyTTLHCL = b.PolicyTTLHCL
 	}
+	if b.TokenMinExpirationTTL != 0 {
+		result.TokenMinExpirationTTL = b.TokenMinExpirationTTL
+	}
+	if b.TokenMinExpirationTTLHCL != "" {
+		result.TokenMinExpirationTTLHCL = b.TokenMinExpirationTTLHCL
+	}
+	if b.TokenMaxExpirationTTL != 0 {
+		result.TokenMaxExpirationTTL = b.TokenMaxExpirationTTL
+	}
+	if b.TokenMaxExpirationTTLHCL != "" {
+		result.TokenMaxExpirationTTLHCL = b.TokenMaxExpirationTTLHCL
+	}
 	if b.ReplicationToken != "" {
 		result.ReplicationToken = b.ReplicationToken
 	}
@@ -1526,6 +1574,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.CSIPluginGCThreshold != "" {
 		result.CSIPluginGCThreshold = b.CSIPluginGCThreshold
 	}
+	if b.ACLTokenGCThreshold != "" {
+		result.ACLTokenGCThreshold = b.ACLTokenGCThreshold
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
@@ -1614,6 +1665,14 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		}
 	}
 
+	if b.JobMaxSourceSize != nil {
+		result.JobMaxSourceSize = helper.StringToPtr(*b.JobMaxSourceSize)
+	}
+
+	if b.EventSinkFileDir != "" {
+		result.EventSinkFileDir = b.EventSinkFileDir
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
@@ -1643,9 +1702,18 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.AllocDir != "" {
 		result.AllocDir = b.AllocDir
 	}
+	if b.HostVolumesDir != "" {
+		result.HostVolumesDir = b.HostVolumesDir
+	}
+	if b.HostVolumePluginDir != "" {
+		result.HostVolumePluginDir = b.HostVolumePluginDir
+	}
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
+	if b.NodePool != "" {
+		result.NodePool = b.NodePool
+	}
 	if b.NetworkInterface != "" {
 		result.NetworkInterface = b.NetworkInterface
 	}
@@ -45,6 +45,7 @@ var basicConfig = &Config{
 		AllocDir:  "/tmp/alloc",
 		Servers:   []string{"a.b.c:80", "127.0.0.1:1234"},
 		NodeClass: "linux-medium-64bit",
+		NodePool:  "dev",
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -87,6 +88,8 @@ var basicConfig = &Config{
 		HostVolumes: []*structs.ClientHostVolumeConfig{
 			{Name: "tmp", Path: "/tmp"},
 		},
+		HostVolumesDir:      "/tmp/host_volumes",
+		HostVolumePluginDir: "/tmp/host_volume_plugins",
 		CNIPath:             "/tmp/cni_path",
 		BridgeNetworkName:   "custom_bridge_name",
 		BridgeNetworkSubnet: "custom_bridge_subnet",
@@ -107,6 +110,7 @@ var basicConfig = &Config{
 		DeploymentGCThreshold:     "12h",
 		CSIVolumeClaimGCThreshold: "12h",
 		CSIPluginGCThreshold:      "12h",
+		ACLTokenGCThreshold:       "12h",
 		HeartbeatGrace:            30 * time.Second,
 		HeartbeatGraceHCL:         "30s",
 		MinHeartbeatTTL:           33 * time.Second,
@@ -143,12 +147,16 @@ var basicConfig = &Config{
 		LicensePath: "/tmp/nomad.hclic",
 	},
 	ACL: &ACLConfig{
-		Enabled:          true,
-		TokenTTL:         60 * time.Second,
-		TokenTTLHCL:      "60s",
-		PolicyTTL:        60 * time.Second,
-		PolicyTTLHCL:     "60s",
-		ReplicationToken: "foobar",
+		Enabled:                  true,
+		TokenTTL:                 60 * time.Second,
+		TokenTTLHCL:              "60s",
+		PolicyTTL:                60 * time.Second,
+		PolicyTTLHCL:             "60s",
+		TokenMinExpirationTTLHCL: "1h",
+		TokenMinExpirationTTL:    1 * time.Hour,
+		TokenMaxExpirationTTLHCL: "100h",
+		TokenMaxExpirationTTL:    100 * time.Hour,
+		ReplicationToken:         "foobar",
 	},
 	Audit: &config.AuditConfig{
 		Enabled: helper.BoolToPtr(true),
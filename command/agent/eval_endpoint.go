@@ -8,10 +8,33 @@ import (
 )
 
 func (s *HTTPServer) EvalsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
-	if req.Method != "GET" {
+	switch req.Method {
+	case "GET":
+		return s.evalsList(resp, req)
+	case "DELETE":
+		return s.evalsDelete(resp, req)
+	default:
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
+}
+
+func (s *HTTPServer) evalsDelete(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.EvalBatchDeleteRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.EvalBatchDeleteResponse
+	if err := s.agent.RPC("Eval.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
 
+func (s *HTTPServer) evalsList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	args := structs.EvalListRequest{}
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
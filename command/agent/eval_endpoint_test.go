@@ -59,6 +59,48 @@ func TestHTTP_EvalList(t *testing.T) {
 	})
 }
 
+func TestHTTP_EvalDelete(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		job := mock.Job()
+		job.EvalsPaused = true
+		require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, job))
+		eval1 := mock.Eval()
+		eval1.JobID = job.ID
+		eval2 := mock.Eval()
+		err := state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval1, eval2})
+		require.NoError(t, err)
+
+		args := structs.EvalBatchDeleteRequest{
+			Filter: "Status == \"pending\"",
+		}
+		req, err := http.NewRequest("DELETE", "/v1/evaluations", encodeReq(args))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.EvalsRequest(respW, req)
+		require.NoError(t, err)
+
+		// Only the eval of the paused job is deleted
+		require.Equal(t, 1, obj.(structs.EvalBatchDeleteResponse).Count)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		out, err := state.EvalByID(nil, eval1.ID)
+		require.NoError(t, err)
+		require.Nil(t, out)
+		out, err = state.EvalByID(nil, eval2.ID)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+
+		// Other methods are rejected
+		req, err = http.NewRequest("PUT", "/v1/evaluations", nil)
+		require.NoError(t, err)
+		_, err = s.Server.EvalsRequest(httptest.NewRecorder(), req)
+		require.ErrorContains(t, err, ErrInvalidMethod)
+	})
+}
+
 func TestHTTP_EvalPrefixList(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -202,3 +202,70 @@ func TestEventStream_QueryParse(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register the sink, whose ID is taken from the path
+		sink := &structs.EventSink{
+			Type:    structs.SinkWebhook,
+			Address: "http://127.0.0.1:8080/events",
+		}
+		req, err := http.NewRequest("PUT", "/v1/event/sink/webhook", encodeReq(sink))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.EventSinkSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// The sink ID must match the path
+		sink.ID = "other"
+		req, err = http.NewRequest("PUT", "/v1/event/sink/webhook", encodeReq(sink))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.EventSinkSpecificRequest(respW, req)
+		require.EqualError(t, err, "Event sink ID does not match request path")
+
+		// List the sinks
+		req, err = http.NewRequest("GET", "/v1/event/sinks", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err := s.Server.EventSinksRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		sinks := obj.([]*structs.EventSink)
+		require.Len(t, sinks, 1)
+		require.Equal(t, "webhook", sinks[0].ID)
+
+		// Query the sink
+		req, err = http.NewRequest("GET", "/v1/event/sink/webhook", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.EventSinkSpecificRequest(respW, req)
+		require.NoError(t, err)
+		out := obj.(*structs.EventSink)
+		require.Equal(t, sink.Address, out.Address)
+		require.Equal(t, map[structs.Topic][]string{structs.TopicAll: {"*"}}, out.Topics)
+
+		// Delete the sink
+		req, err = http.NewRequest("DELETE", "/v1/event/sink/webhook", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.EventSinkSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Query the deleted sink
+		req, err = http.NewRequest("GET", "/v1/event/sink/webhook", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.EventSinkSpecificRequest(respW, req)
+		require.EqualError(t, err, "Event sink not found")
+	})
+}
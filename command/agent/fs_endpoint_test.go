@@ -251,6 +251,15 @@ func TestHTTP_FS_Logs_MissingParams(t *testing.T) {
 		require.Equal(respW.Body.String(), logTypeNotPresentErr.Error())
 		require.Equal(400, respW.Code)
 
+		// Invalid time range
+		req, err = http.NewRequest("GET", "/v1/client/fs/logs/foo?task=foo&type=stdout&since=yesterday", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		s.Server.mux.ServeHTTP(respW, req)
+		require.Equal(400, respW.Code)
+		require.Contains(respW.Body.String(), "error parsing since")
+
 		// case where all parameters are set but alloc isn't found
 		req, err = http.NewRequest("GET", "/v1/client/fs/logs/foo?task=foo&type=stdout", nil)
 		require.NoError(err)
@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HostVolumeSpecificRequest dispatches GET, PUT and DELETE for dynamic host
+// volumes
+func (s *HTTPServer) HostVolumeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Tokenize the suffix of the path to get the volume id
+	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/volume/host/")
+	tokens := strings.Split(reqSuffix, "/")
+	if len(tokens) != 1 || tokens[0] == "" {
+		return nil, CodedError(404, resourceNotFoundErr)
+	}
+	id := tokens[0]
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.hostVolumeGet(id, resp, req)
+	case http.MethodPut, http.MethodPost:
+		if id == "create" {
+			return s.hostVolumeCreate(resp, req)
+		}
+		return nil, CodedError(404, resourceNotFoundErr)
+	case http.MethodDelete:
+		return s.hostVolumeDelete(id, resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) hostVolumesList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.HostVolumeListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	args.Prefix = req.URL.Query().Get("prefix")
+	args.NodeID = req.URL.Query().Get("node_id")
+
+	var out structs.HostVolumeListResponse
+	if err := s.agent.RPC(structs.HostVolumeListRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out.Volumes, nil
+}
+
+func (s *HTTPServer) hostVolumeGet(id string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.HostVolumeGetRequest{
+		ID: id,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.HostVolumeGetResponse
+	if err := s.agent.RPC(structs.HostVolumeGetRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Volume == nil {
+		return nil, CodedError(404, "volume not found")
+	}
+
+	return out.Volume, nil
+}
+
+func (s *HTTPServer) hostVolumeCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.HostVolumeCreateRequest{}
+	if err := decodeBody(req, &args); err != nil {
+		return err, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.HostVolumeCreateResponse
+	if err := s.agent.RPC(structs.HostVolumeCreateRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) hostVolumeDelete(id string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.HostVolumeDeleteRequest{
+		VolumeIDs: []string{id},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.HostVolumeDeleteResponse
+	if err := s.agent.RPC(structs.HostVolumeDeleteRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -57,6 +57,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/allocations"):
 		jobName := strings.TrimSuffix(path, "/allocations")
 		return s.jobAllocations(resp, req, jobName)
+	case strings.HasSuffix(path, "/evaluations/pause"):
+		jobName := strings.TrimSuffix(path, "/evaluations/pause")
+		return s.jobEvalPause(resp, req, jobName)
 	case strings.HasSuffix(path, "/evaluations"):
 		jobName := strings.TrimSuffix(path, "/evaluations")
 		return s.jobEvaluations(resp, req, jobName)
@@ -75,6 +78,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobName := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
@@ -90,6 +96,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/scale"):
 		jobName := strings.TrimSuffix(path, "/scale")
 		return s.jobScale(resp, req, jobName)
+	case strings.HasSuffix(path, "/actions"):
+		jobName := strings.TrimSuffix(path, "/actions")
+		return s.jobActions(resp, req, jobName)
 	case strings.HasSuffix(path, "/services"):
 		jobName := strings.TrimSuffix(path, "/services")
 		return s.jobServiceRegistrations(resp, req, jobName)
@@ -301,6 +310,35 @@ func (s *HTTPServer) jobDeployments(resp http.ResponseWriter, req *http.Request,
 	return out.Deployments, nil
 }
 
+func (s *HTTPServer) jobActions(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.JobSpecificRequest{
+		JobID: jobName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleJobResponse
+	if err := s.agent.RPC("Job.GetJob", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Job == nil {
+		return nil, CodedError(404, "job not found")
+	}
+
+	actions := out.Job.Actions()
+	if actions == nil {
+		actions = make([]*structs.JobAction, 0)
+	}
+	return actions, nil
+}
+
 func (s *HTTPServer) jobLatestDeployment(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -415,6 +453,7 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 		PolicyOverride: args.PolicyOverride,
 		PreserveCounts: args.PreserveCounts,
 		EvalPriority:   args.EvalPriority,
+		Submission:     ApiJobSubmissionToStructs(args.Submission),
 		WriteRequest:   *writeReq,
 	}
 
@@ -601,6 +640,43 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	versionStr := req.URL.Query().Get("version")
+	if versionStr == "" {
+		return nil, CodedError(400, "version must be specified")
+	}
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err))
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID:   jobName,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC("Job.GetJobSubmission", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job submission not found")
+	}
+
+	return out.Submission, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -659,6 +735,35 @@ func (s *HTTPServer) jobStable(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobEvalPause(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var pauseRequest structs.JobEvalPauseRequest
+	if err := decodeBody(req, &pauseRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if pauseRequest.JobID == "" {
+		return nil, CodedError(400, "JobID must be specified")
+	}
+	if pauseRequest.JobID != jobName {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+
+	s.parseWriteRequest(req, &pauseRequest.WriteRequest)
+
+	var out structs.JobEvalPauseResponse
+	if err := s.agent.RPC("Job.EvalPause", &pauseRequest, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) jobSummaryRequest(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
 	args := structs.JobSummaryRequest{
 		JobID: name,
@@ -894,6 +999,22 @@ func namespaceForJob(jobNamespace *string, queryNamespace, apiNamespace string)
 	return structs.DefaultNamespace
 }
 
+// ApiJobSubmissionToStructs converts the job source given by the API into
+// its struct form. The identifying fields are set by the server.
+func ApiJobSubmissionToStructs(sub *api.JobSubmission) *structs.JobSubmission {
+	if sub == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source:        sub.Source,
+		Format:        sub.Format,
+		VariableFlags: helper.CopyMapStringString(sub.VariableFlags),
+		Variables:     sub.Variables,
+		VariableEnvs:  helper.CopyMapStringString(sub.VariableEnvs),
+		Files:         helper.CopyMapStringString(sub.Files),
+	}
+}
+
 func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
@@ -916,6 +1037,14 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Affinities:     ApiAffinitiesToStructs(job.Affinities),
 	}
 
+	if job.NodePool != nil {
+		j.NodePool = *job.NodePool
+	}
+
+	if job.SchedulerAlgorithm != nil {
+		j.SchedulerAlgorithm = structs.SchedulerAlgorithm(*job.SchedulerAlgorithm)
+	}
+
 	// Update has been pushed into the task groups. stagger and max_parallel are
 	// preserved at the job level, but all other values are discarded. The job.Update
 	// api value is merged into TaskGroups already in api.Canonicalize
@@ -943,6 +1072,9 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 			SpecType:        *job.Periodic.SpecType,
 			ProhibitOverlap: *job.Periodic.ProhibitOverlap,
 			TimeZone:        *job.Periodic.TimeZone,
+			Specs:           helper.CopySliceString(job.Periodic.Specs),
+			ExcludeDates:    helper.CopySliceString(job.Periodic.ExcludeDates),
+			ExcludeCrons:    helper.CopySliceString(job.Periodic.ExcludeCrons),
 		}
 
 		if job.Periodic.Spec != nil {
@@ -1016,6 +1148,14 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		tg.MaxClientDisconnect = taskGroup.MaxClientDisconnect
 	}
 
+	if taskGroup.StickyHost != nil {
+		tg.StickyHost = *taskGroup.StickyHost
+	}
+
+	if taskGroup.SchedulerAlgorithm != nil {
+		tg.SchedulerAlgorithm = structs.SchedulerAlgorithm(*taskGroup.SchedulerAlgorithm)
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1180,6 +1320,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.LogConfig = &structs.LogConfig{
 		MaxFiles:      *apiTask.LogConfig.MaxFiles,
 		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
+		Sinks:         apiLogSinksToStructs(apiTask.LogConfig.Sinks),
 	}
 
 	if len(apiTask.Artifacts) > 0 {
@@ -1196,6 +1337,18 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		}
 	}
 
+	if len(apiTask.Actions) > 0 {
+		structsTask.Actions = make([]*structs.Action, 0, len(apiTask.Actions))
+		for _, action := range apiTask.Actions {
+			structsTask.Actions = append(structsTask.Actions,
+				&structs.Action{
+					Name:    action.Name,
+					Command: action.Command,
+					Args:    helper.CopySliceString(action.Args),
+				})
+		}
+	}
+
 	if apiTask.Vault != nil {
 		structsTask.Vault = &structs.Vault{
 			Policies:     apiTask.Vault.Policies,
@@ -1713,7 +1866,30 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 	return &structs.LogConfig{
 		MaxFiles:      dereferenceInt(in.MaxFiles),
 		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
+		Sinks:         apiLogSinksToStructs(in.Sinks),
+	}
+}
+
+func apiLogSinksToStructs(in []*api.LogSink) []*structs.LogSink {
+	if len(in) == 0 {
+		return nil
 	}
+
+	out := make([]*structs.LogSink, len(in))
+	for i, sink := range in {
+		out[i] = &structs.LogSink{
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Tag:        sink.Tag,
+			Headers:    helper.CopyMapStringString(sink.Headers),
+			BatchSize:  dereferenceInt(sink.BatchSize),
+			BufferSize: dereferenceInt(sink.BufferSize),
+		}
+		if sink.BatchWait != nil {
+			out[i].BatchWait = *sink.BatchWait
+		}
+	}
+	return out
 }
 
 func dereferenceInt(in *int) int {
@@ -1773,7 +1949,9 @@ func ApiAffinityToStructs(a1 *api.Affinity) *structs.Affinity {
 func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	ret := &structs.Spread{}
 	ret.Attribute = a1.Attribute
+	ret.Attributes = helper.CopySliceString(a1.Attributes)
 	ret.Weight = *a1.Weight
+	ret.MaxSkew = a1.MaxSkew
 	if a1.SpreadTarget != nil {
 		ret.SpreadTarget = make([]*structs.SpreadTarget, len(a1.SpreadTarget))
 		for i, st := range a1.SpreadTarget {
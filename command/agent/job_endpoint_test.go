@@ -1484,6 +1484,52 @@ func TestHTTP_JobDeployments(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobActions(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job
+		j := mock.Job()
+		j.TaskGroups[0].Tasks[0].Actions = []*structs.Action{
+			{Name: "flush-cache", Command: "redis-cli", Args: []string{"FLUSHALL"}},
+		}
+		args := structs.JobRegisterRequest{
+			Job: j,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/job/"+j.ID+"/actions", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check the response
+		require.Equal(t, []*structs.JobAction{{
+			TaskGroup: "web",
+			Task:      "web",
+			Name:      "flush-cache",
+			Command:   "redis-cli",
+			Args:      []string{"FLUSHALL"},
+		}}, obj)
+		require.NotZero(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Unknown jobs aren't found
+		req, err = http.NewRequest("GET", "/v1/job/unknown/actions", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, 404, err.(HTTPCodedError).Code())
+	})
+}
+
 func TestHTTP_JobDeployment(t *testing.T) {
 	ci.Parallel(t)
 	assert := assert.New(t)
@@ -1605,6 +1651,56 @@ func TestHTTP_JobVersions(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobSubmission(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register the job along with its source
+		job := MockJob()
+		args := api.JobRegisterRequest{
+			Job: job,
+			Submission: &api.JobSubmission{
+				Source:        `job "example" { datacenters = [var.dc] }`,
+				Format:        api.JobSubmissionFormatHCL2,
+				VariableFlags: map[string]string{"dc": "dc1"},
+				Variables:     `dc = "dc2"`,
+			},
+			WriteRequest: api.WriteRequest{Region: "global"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/jobs", encodeReq(args))
+		require.NoError(t, err)
+		_, err = s.Server.JobsRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+
+		// Make the HTTP request
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission?version=0", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check the response
+		sub := obj.(*structs.JobSubmission)
+		require.Equal(t, args.Submission.Source, sub.Source)
+		require.Equal(t, args.Submission.Format, sub.Format)
+		require.Equal(t, args.Submission.VariableFlags, sub.VariableFlags)
+		require.Equal(t, args.Submission.Variables, sub.Variables)
+		require.Equal(t, *job.ID, sub.JobID)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Versions without a source are not found
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission?version=1", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, "job submission not found")
+
+		// The version is required
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, "version must be specified")
+	})
+}
+
 func TestHTTP_PeriodicForce(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1935,6 +2031,55 @@ func TestHTTP_JobStable(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobEvalPause(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job
+		job := mock.Job()
+		regReq := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var regResp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &regReq, &regResp))
+
+		args := structs.JobEvalPauseRequest{
+			JobID:  job.ID,
+			Paused: true,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+
+		// Make the HTTP request
+		req, err := http.NewRequest("PUT", "/v1/job/"+job.ID+"/evaluations/pause", encodeReq(args))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check the response and index
+		pauseResp := obj.(structs.JobEvalPauseResponse)
+		require.NotZero(t, pauseResp.Index)
+		require.NotEmpty(t, respW.Result().Header.Get("X-Nomad-Index"))
+
+		// Check the job is paused
+		out, err := s.Agent.server.State().JobByID(nil, job.Namespace, job.ID)
+		require.NoError(t, err)
+		require.True(t, out.EvalsPaused)
+
+		// A mismatched job ID is rejected
+		req, err = http.NewRequest("PUT", "/v1/job/other/evaluations/pause", encodeReq(args))
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.ErrorContains(t, err, "Job ID does not match")
+	})
+}
+
 func TestJobs_ParsingWriteRequest(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2371,6 +2516,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		Priority:    helper.IntToPtr(50),
 		AllAtOnce:   helper.BoolToPtr(true),
 		Datacenters: []string{"dc1", "dc2"},
+		NodePool:    helper.StringToPtr("dev"),
 		Constraints: []*api.Constraint{
 			{
 				LTarget: "a",
@@ -2378,6 +2524,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				Operand: "c",
 			},
 		},
+		SchedulerAlgorithm: helper.StringToPtr("binpack"),
 		Affinities: []*api.Affinity{
 			{
 				LTarget: "a",
@@ -2407,6 +2554,11 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 			},
+			{
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     helper.Int8ToPtr(50),
+				MaxSkew:    1,
+			},
 		},
 		Periodic: &api.PeriodicConfig{
 			Enabled:         helper.BoolToPtr(true),
@@ -2449,6 +2601,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Operand: "z",
 					},
 				},
+				SchedulerAlgorithm: helper.StringToPtr("spread"),
 				Affinities: []*api.Affinity{
 					{
 						LTarget: "x",
@@ -2706,6 +2859,16 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						LogConfig: &api.LogConfig{
 							MaxFiles:      helper.IntToPtr(10),
 							MaxFileSizeMB: helper.IntToPtr(100),
+							Sinks: []*api.LogSink{
+								{
+									Type:       "http",
+									Address:    "https://logs.example.com",
+									Headers:    map[string]string{"X-Token": "secret"},
+									BatchSize:  helper.IntToPtr(50),
+									BatchWait:  helper.TimeToPtr(5 * time.Second),
+									BufferSize: helper.IntToPtr(1000),
+								},
+							},
 						},
 						Artifacts: []*api.TaskArtifact{
 							{
@@ -2717,6 +2880,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								RelativeDest: helper.StringToPtr("dest"),
 							},
 						},
+						Actions: []*api.Action{
+							{
+								Name:    "flush-cache",
+								Command: "redis-cli",
+								Args:    []string{"FLUSHALL"},
+							},
+						},
 						Vault: &api.Vault{
 							Namespace:    helper.StringToPtr("ns1"),
 							Policies:     []string{"a", "b", "c"},
@@ -2771,6 +2941,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		Priority:       50,
 		AllAtOnce:      true,
 		Datacenters:    []string{"dc1", "dc2"},
+		NodePool:       "dev",
 		Constraints: []*structs.Constraint{
 			{
 				LTarget: "a",
@@ -2778,6 +2949,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 				Operand: "c",
 			},
 		},
+		SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
 		Affinities: []*structs.Affinity{
 			{
 				LTarget: "a",
@@ -2797,6 +2969,11 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					},
 				},
 			},
+			{
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     50,
+				MaxSkew:    1,
+			},
 		},
 		Update: structs.UpdateStrategy{
 			Stagger:     1 * time.Second,
@@ -2843,6 +3020,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Operand: "z",
 					},
 				},
+				SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
 				Affinities: []*structs.Affinity{
 					{
 						LTarget: "x",
@@ -3111,6 +3289,16 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						LogConfig: &structs.LogConfig{
 							MaxFiles:      10,
 							MaxFileSizeMB: 100,
+							Sinks: []*structs.LogSink{
+								{
+									Type:       "http",
+									Address:    "https://logs.example.com",
+									Headers:    map[string]string{"X-Token": "secret"},
+									BatchSize:  50,
+									BatchWait:  5 * time.Second,
+									BufferSize: 1000,
+								},
+							},
 						},
 						Artifacts: []*structs.TaskArtifact{
 							{
@@ -3122,6 +3310,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								RelativeDest: "dest",
 							},
 						},
+						Actions: []*structs.Action{
+							{
+								Name:    "flush-cache",
+								Command: "redis-cli",
+								Args:    []string{"FLUSHALL"},
+							},
+						},
 						Vault: &structs.Vault{
 							Namespace:    "ns1",
 							Policies:     []string{"a", "b", "c"},
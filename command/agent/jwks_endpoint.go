@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// jwkKeyTypeOKP and jwkCurveEd25519 are the JWK key type and curve of the
+	// Ed25519 public keys which verify workload identities, as defined by
+	// RFC 8037.
+	jwkKeyTypeOKP   = "OKP"
+	jwkCurveEd25519 = "Ed25519"
+)
+
+// JSONWebKeySet is the set of public keys which verify the workload
+// identities signed by the servers, as defined by RFC 7517.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey is a single public key within a JSONWebKeySet.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+// JWKSRequest is used to publish the public keys of the keyring as a JSON
+// Web Key Set, so that third parties can verify workload identities. It is
+// callable via the /.well-known/jwks.json HTTP API and requires no ACL
+// token.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var reply structs.KeyringListPublicResponse
+	if err := s.agent.RPC(structs.KeyringListPublicRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	jwks := &JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(reply.PublicKeys))}
+	for _, pubKey := range reply.PublicKeys {
+		jwks.Keys = append(jwks.Keys, JSONWebKey{
+			KeyType:   jwkKeyTypeOKP,
+			Curve:     jwkCurveEd25519,
+			X:         base64.RawURLEncoding.EncodeToString(pubKey.PublicKey),
+			KeyID:     pubKey.KeyID,
+			Algorithm: pubKey.Algorithm,
+			Use:       pubKey.Use,
+		})
+	}
+	return jwks, nil
+}
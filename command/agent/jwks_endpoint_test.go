@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPServer_JWKSRequest(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+
+		// Wait for the keyring to be initialized, so a key is published.
+		var keyID string
+		testutil.WaitForResult(func() (bool, error) {
+			keyMeta, err := s.Agent.server.State().GetActiveRootKeyMeta(nil)
+			if err != nil || keyMeta == nil {
+				return false, err
+			}
+			keyID = keyMeta.KeyID
+			return true, nil
+		}, func(err error) {
+			t.Fatalf("keyring was not initialized: %v", err)
+		})
+
+		req, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JWKSRequest(respW, req)
+		require.NoError(t, err)
+
+		jwks := obj.(*JSONWebKeySet)
+		require.Len(t, jwks.Keys, 1)
+		require.Equal(t, keyID, jwks.Keys[0].KeyID)
+		require.Equal(t, "OKP", jwks.Keys[0].KeyType)
+		require.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+		require.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+
+		x, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+		require.NoError(t, err)
+		require.Len(t, x, 32)
+
+		// Only GET requests are supported.
+		req, err = http.NewRequest(http.MethodPut, "/.well-known/jwks.json", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JWKSRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+	})
+}
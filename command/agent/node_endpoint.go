@@ -140,10 +140,20 @@ func (s *HTTPServer) nodeToggleDrain(resp http.ResponseWriter, req *http.Request
 	if drainRequest.DrainSpec != nil {
 		args.DrainStrategy = &structs.DrainStrategy{
 			DrainSpec: structs.DrainSpec{
-				Deadline:         drainRequest.DrainSpec.Deadline,
-				IgnoreSystemJobs: drainRequest.DrainSpec.IgnoreSystemJobs,
+				Deadline:              drainRequest.DrainSpec.Deadline,
+				IgnoreSystemJobs:      drainRequest.DrainSpec.IgnoreSystemJobs,
+				JobOrder:              drainRequest.DrainSpec.JobOrder,
+				OrderByPriority:       drainRequest.DrainSpec.OrderByPriority,
+				MaxParallelMigrations: drainRequest.DrainSpec.MaxParallelMigrations,
 			},
 		}
+		if window := drainRequest.DrainSpec.Window; window != nil {
+			args.DrainStrategy.Window = &structs.DrainWindow{
+				Cron:     window.Cron,
+				Duration: window.Duration,
+				TimeZone: window.TimeZone,
+			}
+		}
 	}
 	s.parseWriteRequest(req, &args.WriteRequest)
 
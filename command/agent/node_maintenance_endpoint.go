@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) NodeMaintenanceRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.nodeMaintenanceList(resp, req)
+	case "PUT", "POST":
+		return s.nodeMaintenanceRegister(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) NodeMaintenanceSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/node/maintenance/")
+	switch {
+	case strings.HasSuffix(path, "/cancel"):
+		planID := strings.TrimSuffix(path, "/cancel")
+		return s.nodeMaintenanceCancel(resp, req, planID)
+	case strings.HasSuffix(path, "/done"):
+		planID := strings.TrimSuffix(path, "/done")
+		return s.nodeMaintenanceNodeDone(resp, req, planID)
+	}
+
+	if len(path) == 0 {
+		return nil, CodedError(400, "Missing Maintenance Plan ID")
+	}
+	switch req.Method {
+	case "GET":
+		return s.nodeMaintenanceQuery(resp, req, path)
+	case "DELETE":
+		return s.nodeMaintenanceDelete(resp, req, path)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) nodeMaintenanceList(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.MaintenancePlanListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.MaintenancePlanListResponse
+	if err := s.agent.RPC(structs.MaintenancePlanListRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Plans == nil {
+		out.Plans = make([]*structs.MaintenancePlanListStub, 0)
+	}
+	return out.Plans, nil
+}
+
+func (s *HTTPServer) nodeMaintenanceRegister(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Parse the maintenance plan
+	var plan structs.MaintenancePlan
+	if err := decodeBody(req, &plan); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	args := structs.MaintenancePlanRegisterRequest{
+		Plan: &plan,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.MaintenancePlanRegisterResponse
+	if err := s.agent.RPC(structs.MaintenancePlanRegisterRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+func (s *HTTPServer) nodeMaintenanceQuery(resp http.ResponseWriter, req *http.Request,
+	planID string) (interface{}, error) {
+	args := structs.MaintenancePlanSpecificRequest{
+		PlanID: planID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleMaintenancePlanResponse
+	if err := s.agent.RPC(structs.MaintenancePlanGetPlanRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Plan == nil {
+		return nil, CodedError(404, "Maintenance plan not found")
+	}
+	return out.Plan, nil
+}
+
+func (s *HTTPServer) nodeMaintenanceCancel(resp http.ResponseWriter, req *http.Request,
+	planID string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.MaintenancePlanCancelRequest{
+		PlanID: planID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC(structs.MaintenancePlanCancelRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) nodeMaintenanceNodeDone(resp http.ResponseWriter, req *http.Request,
+	planID string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.MaintenancePlanNodeDoneRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	args.PlanID = planID
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC(structs.MaintenancePlanNodeDoneRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) nodeMaintenanceDelete(resp http.ResponseWriter, req *http.Request,
+	planID string) (interface{}, error) {
+
+	args := structs.MaintenancePlanDeleteRequest{
+		PlanIDs: []string{planID},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC(structs.MaintenancePlanDeleteRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,99 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodeMaintenance(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		node := mock.Node()
+		node.NodeClass = "maintenance"
+		args := structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var nodeResp structs.NodeUpdateResponse
+		require.NoError(t, s.Agent.RPC("Node.Register", &args, &nodeResp))
+
+		// Register the maintenance plan
+		plan := &structs.MaintenancePlan{
+			Name:     "upgrade",
+			Selector: &structs.MaintenanceSelector{NodeClass: "maintenance"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/node/maintenance", encodeReq(plan))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.NodeMaintenanceRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		planID := obj.(structs.MaintenancePlanRegisterResponse).PlanID
+		require.NotEmpty(t, planID)
+
+		// List the maintenance plans
+		req, err = http.NewRequest("GET", "/v1/node/maintenance", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.NodeMaintenanceRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		stubs := obj.([]*structs.MaintenancePlanListStub)
+		require.Len(t, stubs, 1)
+		require.Equal(t, planID, stubs[0].ID)
+		require.Equal(t, 1, stubs[0].NodesTotal)
+
+		// Query the maintenance plan
+		req, err = http.NewRequest("GET", "/v1/node/maintenance/"+planID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.NodeMaintenanceSpecificRequest(respW, req)
+		require.NoError(t, err)
+		out := obj.(*structs.MaintenancePlan)
+		require.Equal(t, "upgrade", out.Name)
+		require.Equal(t, node.ID, out.Nodes[0].NodeID)
+
+		// Nodes outside of the plan can't be marked done
+		doneReq := &structs.MaintenancePlanNodeDoneRequest{NodeIDs: []string{"unknown"}}
+		req, err = http.NewRequest("PUT", "/v1/node/maintenance/"+planID+"/done", encodeReq(doneReq))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodeMaintenanceSpecificRequest(respW, req)
+		require.ErrorContains(t, err, "is not part of the maintenance plan")
+
+		// Cancel the maintenance plan
+		req, err = http.NewRequest("PUT", "/v1/node/maintenance/"+planID+"/cancel", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodeMaintenanceSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Delete the maintenance plan
+		req, err = http.NewRequest("DELETE", "/v1/node/maintenance/"+planID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodeMaintenanceSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		// Query the deleted maintenance plan
+		req, err = http.NewRequest("GET", "/v1/node/maintenance/"+planID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodeMaintenanceSpecificRequest(respW, req)
+		require.EqualError(t, err, "Maintenance plan not found")
+	})
+}
@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) NodePoolsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.NodePoolListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.NodePoolListResponse
+	if err := s.agent.RPC(structs.NodePoolListRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.NodePools == nil {
+		out.NodePools = make([]*structs.NodePool, 0)
+	}
+	return out.NodePools, nil
+}
+
+func (s *HTTPServer) NodePoolSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/node/pool/")
+	if len(name) == 0 {
+		return nil, CodedError(400, "Missing Node Pool Name")
+	}
+	switch req.Method {
+	case "GET":
+		return s.nodePoolQuery(resp, req, name)
+	case "PUT", "POST":
+		return s.nodePoolUpdate(resp, req, name)
+	case "DELETE":
+		return s.nodePoolDelete(resp, req, name)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) NodePoolCreateRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	return s.nodePoolUpdate(resp, req, "")
+}
+
+func (s *HTTPServer) nodePoolQuery(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+	args := structs.NodePoolSpecificRequest{
+		Name: poolName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleNodePoolResponse
+	if err := s.agent.RPC(structs.NodePoolGetNodePoolRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.NodePool == nil {
+		return nil, CodedError(404, "Node pool not found")
+	}
+	return out.NodePool, nil
+}
+
+func (s *HTTPServer) nodePoolUpdate(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+	// Parse the node pool
+	var pool structs.NodePool
+	if err := decodeBody(req, &pool); err != nil {
+		return nil, CodedError(500, err.Error())
+	}
+
+	// Ensure the node pool name matches
+	if poolName != "" && pool.Name != poolName {
+		return nil, CodedError(400, "Node pool name does not match request path")
+	}
+
+	// Format the request
+	args := structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{&pool},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC(structs.NodePoolUpsertNodePoolsRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) nodePoolDelete(resp http.ResponseWriter, req *http.Request,
+	poolName string) (interface{}, error) {
+
+	args := structs.NodePoolDeleteRequest{
+		Names: []string{poolName},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC(structs.NodePoolDeleteNodePoolsRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
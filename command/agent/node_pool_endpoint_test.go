@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodePoolList(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		args := structs.NodePoolUpsertRequest{
+			NodePools:    []*structs.NodePool{{Name: "dev"}, {Name: "prod"}},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(t, s.Agent.RPC(structs.NodePoolUpsertNodePoolsRPCMethod, &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/node/pools", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NodePoolsRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check for the index
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal(t, "true", respW.HeaderMap.Get("X-Nomad-KnownLeader"))
+
+		// Check the output (the 2 we register + the built-in ones)
+		require.Len(t, obj.([]*structs.NodePool), 4)
+	})
+}
+
+func TestHTTP_NodePoolQuery(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/node/pool/"+structs.NodePoolDefault, nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal(t, structs.NodePoolDefault, obj.(*structs.NodePool).Name)
+
+		// Query a missing node pool
+		req, err = http.NewRequest("GET", "/v1/node/pool/unknown", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.EqualError(t, err, "Node pool not found")
+	})
+}
+
+func TestHTTP_NodePoolCreateUpdateDelete(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		pool := &structs.NodePool{
+			Name:        "dev",
+			Description: "development nodes",
+		}
+
+		// Create the node pool
+		req, err := http.NewRequest("PUT", "/v1/node/pool", encodeReq(pool))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.NodePoolCreateRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out, err := s.Agent.server.State().NodePoolByName(nil, pool.Name)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		require.Equal(t, "development nodes", out.Description)
+
+		// Update the node pool
+		pool.Description = "updated"
+		req, err = http.NewRequest("PUT", "/v1/node/pool/dev", encodeReq(pool))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		out, err = s.Agent.server.State().NodePoolByName(nil, pool.Name)
+		require.NoError(t, err)
+		require.Equal(t, "updated", out.Description)
+
+		// Mismatched names are rejected
+		req, err = http.NewRequest("PUT", "/v1/node/pool/prod", encodeReq(pool))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.Error(t, err)
+
+		// Delete the node pool
+		req, err = http.NewRequest("DELETE", "/v1/node/pool/dev", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out, err = s.Agent.server.State().NodePoolByName(nil, pool.Name)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	})
+}
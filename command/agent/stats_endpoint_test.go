@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -123,3 +124,29 @@ func TestClientStatsRequest_ACL(t *testing.T) {
 		}
 	})
 }
+
+func TestClientArtifactCacheRequest(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/client/artifacts", nil)
+		require.NoError(t, err)
+
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.ClientArtifactCacheRequest(respW, req)
+		require.NoError(t, err)
+
+		cache := obj.(*cstructs.ArtifactCacheStats)
+		require.Equal(t, int64(10_000_000_000), cache.MaxSize)
+		require.Empty(t, cache.Entries)
+
+		req, err = http.NewRequest("GET", fmt.Sprintf("/v1/client/artifacts?node_id=%s", uuid.Generate()), nil)
+		require.NoError(t, err)
+
+		srv := s.server
+		s.server = nil
+		_, err = s.Server.ClientArtifactCacheRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unknown node")
+		s.server = srv
+	})
+}
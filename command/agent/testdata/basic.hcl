@@ -42,6 +42,7 @@ client {
   alloc_dir  = "/tmp/alloc"
   servers    = ["a.b.c:80", "127.0.0.1:1234"]
   node_class = "linux-medium-64bit"
+  node_pool  = "dev"
 
   meta {
     foo = "bar"
@@ -96,6 +97,9 @@ client {
     path = "/tmp"
   }
 
+  host_volumes_dir       = "/tmp/host_volumes"
+  host_volume_plugin_dir = "/tmp/host_volume_plugins"
+
   cni_path              = "/tmp/cni_path"
   bridge_network_name   = "custom_bridge_name"
   bridge_network_subnet = "custom_bridge_subnet"
@@ -116,6 +120,7 @@ server {
   deployment_gc_threshold       = "12h"
   csi_volume_claim_gc_threshold = "12h"
   csi_plugin_gc_threshold       = "12h"
+  acl_token_gc_threshold        = "12h"
   heartbeat_grace               = "30s"
   min_heartbeat_ttl             = "33s"
   max_heartbeats_per_second     = 11.0
@@ -153,10 +158,12 @@ server {
 }
 
 acl {
-  enabled           = true
-  token_ttl         = "60s"
-  policy_ttl        = "60s"
-  replication_token = "foobar"
+  enabled                  = true
+  token_ttl                = "60s"
+  policy_ttl               = "60s"
+  token_min_expiration_ttl = "1h"
+  token_max_expiration_ttl = "100h"
+  replication_token        = "foobar"
 }
 
 audit {
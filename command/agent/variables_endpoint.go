@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariablesListRequest performs a listing of variable metadata using the
+// structs.VariablesListRPCMethod RPC endpoint and is callable via the
+// /v1/vars HTTP API.
+func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// The endpoint only supports GET requests.
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.VariablesListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var reply structs.VariablesListResponse
+	if err := s.agent.RPC(structs.VariablesListRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if reply.Data == nil {
+		reply.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return reply.Data, nil
+}
+
+// VariableSpecificRequest is callable via the /v1/var/ HTTP API and handles
+// variable reads, upserts, and deletions.
+func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
+	if path == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing variable path")
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.variableQuery(resp, req, path)
+	case http.MethodPut, http.MethodPost:
+		return s.variableUpsert(resp, req, path)
+	case http.MethodDelete:
+		return s.variableDelete(resp, req, path)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) variableQuery(
+	resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+
+	args := structs.VariablesReadRequest{Path: path}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var reply structs.VariablesReadResponse
+	if err := s.agent.RPC(structs.VariablesReadRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	if reply.Data == nil {
+		return nil, CodedError(http.StatusNotFound, structs.ErrVariableNotFound.Error())
+	}
+	return reply.Data, nil
+}
+
+func (s *HTTPServer) variableUpsert(
+	resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+
+	var variable structs.VariableDecrypted
+	if err := decodeBody(req, &variable); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	variable.Path = path
+
+	args := structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: &variable,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	cas, ok, err := parseVariableCAS(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		args.Op = structs.VarOpCAS
+		args.Var.ModifyIndex = cas
+	}
+
+	var reply structs.VariablesApplyResponse
+	if err := s.agent.RPC(structs.VariablesApplyRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+
+	if reply.IsConflict() {
+		return variableConflict(resp, &reply), nil
+	}
+	return reply.Output, nil
+}
+
+func (s *HTTPServer) variableDelete(
+	resp http.ResponseWriter, req *http.Request, path string) (interface{}, error) {
+
+	args := structs.VariablesApplyRequest{
+		Op:  structs.VarOpDelete,
+		Var: &structs.VariableDecrypted{VariableMetadata: structs.VariableMetadata{Path: path}},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	cas, ok, err := parseVariableCAS(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		args.Op = structs.VarOpDeleteCAS
+		args.Var.ModifyIndex = cas
+	}
+
+	var reply structs.VariablesApplyResponse
+	if err := s.agent.RPC(structs.VariablesApplyRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	setIndex(resp, reply.Index)
+
+	if reply.IsConflict() {
+		return variableConflict(resp, &reply), nil
+	}
+	return nil, nil
+}
+
+// variableConflict writes the conflict status code and returns the
+// conflicting variable, which is empty if the variable does not exist.
+func variableConflict(resp http.ResponseWriter, reply *structs.VariablesApplyResponse) interface{} {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusConflict)
+
+	if reply.Conflict == nil {
+		return &structs.VariableDecrypted{}
+	}
+	return reply.Conflict
+}
+
+// parseVariableCAS parses the check-and-set index from the "cas" query
+// parameter, returning whether it was set.
+func parseVariableCAS(req *http.Request) (uint64, bool, error) {
+	params := req.URL.Query()
+	if _, ok := params["cas"]; !ok {
+		return 0, false, nil
+	}
+	cas, err := strconv.ParseUint(params.Get("cas"), 10, 64)
+	if err != nil {
+		return 0, false, CodedError(http.StatusBadRequest, fmt.Sprintf("failed to parse cas value: %v", err))
+	}
+	return cas, true, nil
+}
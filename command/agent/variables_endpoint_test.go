@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPServer_Variables(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+
+		// Wait for the keyring to be initialized, so variables can be
+		// written.
+		testutil.WaitForResult(func() (bool, error) {
+			keyMeta, err := s.Agent.server.State().GetActiveRootKeyMeta(nil)
+			return keyMeta != nil, err
+		}, func(err error) {
+			t.Fatalf("keyring was not initialized: %v", err)
+		})
+
+		// Write a variable.
+		variable := &structs.VariableDecrypted{Items: structs.VariableItems{"password": "hunter2"}}
+		req, err := http.NewRequest(http.MethodPut, "/v1/var/app/creds", encodeReq(variable))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(t, err)
+		written := obj.(*structs.VariableDecrypted)
+		require.Equal(t, "app/creds", written.Path)
+		require.Equal(t, structs.DefaultNamespace, written.Namespace)
+		require.NotZero(t, respW.Header().Get("X-Nomad-Index"))
+
+		// Read the variable.
+		req, err = http.NewRequest(http.MethodGet, "/v1/var/app/creds", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, variable.Items, obj.(*structs.VariableDecrypted).Items)
+
+		// List the variables by prefix.
+		req, err = http.NewRequest(http.MethodGet, "/v1/vars?prefix=app", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariablesListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.VariableMetadata), 1)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/vars?prefix=other", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariablesListRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.VariableMetadata), 0)
+
+		// A check-and-set with a stale index should conflict.
+		req, err = http.NewRequest(http.MethodPut, "/v1/var/app/creds?cas=1", encodeReq(variable))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusConflict, respW.Code)
+		require.Equal(t, written.ModifyIndex, obj.(*structs.VariableDecrypted).ModifyIndex)
+
+		// Delete the variable and ensure it is no longer found.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/var/app/creds", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/var/app/creds", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Equal(t, http.StatusNotFound, err.(HTTPCodedError).Code())
+
+		// A malformed check-and-set index should be rejected.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/var/app/creds?cas=foo", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadRequest, err.(HTTPCodedError).Code())
+	})
+}
@@ -25,6 +25,10 @@ Usage: nomad alloc <subcommand> [options] [args]
 
       $ nomad alloc logs -f <alloc-id> <task>
 
+  Reschedule an allocation to any node, breaking sticky host pinning:
+
+      $ nomad alloc reschedule -unpin <alloc-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -63,6 +63,25 @@ Logs Specific Options:
   -c
     Sets the tail location in number of bytes relative to the end of the logs.
 
+  -since <time>
+    Only show the lines written at or after the given time, which is either an
+    RFC3339 timestamp or a duration relative to now such as "2h". Can't be used
+    with -tail.
+
+  -until <time>
+    Only show the lines written at or before the given time, which is either an
+    RFC3339 timestamp or a duration relative to now such as "30m". Can't be
+    used with -f.
+
+  -grep <regexp>
+    Only show the lines matching the given regular expression. The lines are
+    filtered by the client running the allocation.
+
+  The time of a line is read from the line itself when it starts with a
+  timestamp, or when it is a JSON object with a "time", "timestamp", "ts" or
+  "@timestamp" field. Otherwise, it is estimated from the index that the client
+  keeps of the time each log file was written at.
+
   Note that the -no-color option applies to Nomad's own output. If the task's
   logs include terminal escape sequences for color codes, Nomad will not
   remove them.
@@ -86,6 +105,9 @@ func (l *AllocLogsCommand) AutocompleteFlags() complete.Flags {
 			"-tail":    complete.PredictAnything,
 			"-n":       complete.PredictAnything,
 			"-c":       complete.PredictAnything,
+			"-since":   complete.PredictAnything,
+			"-until":   complete.PredictAnything,
+			"-grep":    complete.PredictAnything,
 		})
 }
 
@@ -109,7 +131,7 @@ func (l *AllocLogsCommand) Name() string { return "alloc logs" }
 func (l *AllocLogsCommand) Run(args []string) int {
 	var verbose, job, tail, stderr, follow bool
 	var numLines, numBytes int64
-	var task string
+	var task, since, until, grep string
 
 	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
 	flags.Usage = func() { l.Ui.Output(l.Help()) }
@@ -121,12 +143,43 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	flags.Int64Var(&numLines, "n", -1, "")
 	flags.Int64Var(&numBytes, "c", -1, "")
 	flags.StringVar(&task, "task", "", "")
+	flags.StringVar(&since, "since", "", "")
+	flags.StringVar(&until, "until", "", "")
+	flags.StringVar(&grep, "grep", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 	args = flags.Args()
 
+	var search *api.LogSearch
+	if since != "" || until != "" || grep != "" {
+		search = &api.LogSearch{Grep: grep}
+		now := time.Now()
+
+		var err error
+		if since != "" {
+			if tail {
+				l.Ui.Error("The -since and -tail options can't be used together")
+				return 1
+			}
+			if search.Since, err = parseLogsTime(since, now); err != nil {
+				l.Ui.Error(fmt.Sprintf("Invalid -since value: %v", err))
+				return 1
+			}
+		}
+		if until != "" {
+			if follow {
+				l.Ui.Error("The -until and -f options can't be used together")
+				return 1
+			}
+			if search.Until, err = parseLogsTime(until, now); err != nil {
+				l.Ui.Error(fmt.Sprintf("Invalid -until value: %v", err))
+				return 1
+			}
+		}
+	}
+
 	if numArgs := len(args); numArgs < 1 {
 		if job {
 			l.Ui.Error("A job ID is required")
@@ -222,7 +275,7 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	var r io.ReadCloser
 	var readErr error
 	if !tail {
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginStart, 0)
+		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginStart, 0, search)
 		if readErr != nil {
 			readErr = fmt.Errorf("Error reading file: %v", readErr)
 		}
@@ -241,7 +294,7 @@ func (l *AllocLogsCommand) Run(args []string) int {
 			numLines = defaultTailLines
 		}
 
-		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginEnd, offset)
+		r, readErr = l.followFile(client, alloc, follow, task, logType, api.OriginEnd, offset, search)
 
 		// If numLines is set, wrap the reader
 		if numLines != -1 {
@@ -269,12 +322,12 @@ func (l *AllocLogsCommand) Run(args []string) int {
 }
 
 // followFile outputs the contents of the file to stdout relative to the end of
-// the file.
+// the file. If search is not nil, only the lines matching it are output.
 func (l *AllocLogsCommand) followFile(client *api.Client, alloc *api.Allocation,
-	follow bool, task, logType, origin string, offset int64) (io.ReadCloser, error) {
+	follow bool, task, logType, origin string, offset int64, search *api.LogSearch) (io.ReadCloser, error) {
 
 	cancel := make(chan struct{})
-	frames, errCh := client.AllocFS().Logs(alloc, follow, task, logType, origin, offset, cancel, nil)
+	frames, errCh := client.AllocFS().SearchLogs(alloc, follow, task, logType, origin, offset, search, cancel, nil)
 	select {
 	case err := <-errCh:
 		return nil, err
@@ -299,6 +352,23 @@ func (l *AllocLogsCommand) followFile(client *api.Client, alloc *api.Allocation,
 	return r, nil
 }
 
+// parseLogsTime parses the value of the -since and -until options, which is
+// either an RFC3339 timestamp or a duration relative to now.
+func parseLogsTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp nor a duration", value)
+	}
+	if d < 0 {
+		return time.Time{}, fmt.Errorf("duration %q must not be negative", value)
+	}
+	return now.Add(-d), nil
+}
+
 func lookupAllocTask(alloc *api.Allocation) (string, error) {
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 	if tg == nil {
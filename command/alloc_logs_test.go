@@ -3,6 +3,7 @@ package command
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -10,6 +11,7 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLogsCommand_Implements(t *testing.T) {
@@ -68,6 +70,43 @@ func TestLogsCommand_Fails(t *testing.T) {
 	if out := ui.ErrorWriter.String(); !strings.Contains(out, "No allocation(s) with prefix or id") {
 		t.Fatalf("expected not found error, got: %s", out)
 	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid search
+	if code := cmd.Run([]string{"-address=" + url, "-since=yesterday", "foobar"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Invalid -since value") {
+		t.Fatalf("expected invalid since error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=" + url, "-f", "-until=1h", "foobar"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-until and -f options can't be used together") {
+		t.Fatalf("expected conflicting options error, got: %s", out)
+	}
+}
+
+func TestLogsCommand_parseLogsTime(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	since, err := parseLogsTime("2022-05-31T08:30:00Z", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, 5, 31, 8, 30, 0, 0, time.UTC), since)
+
+	since, err = parseLogsTime("90m", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-90*time.Minute), since)
+
+	_, err = parseLogsTime("-1h", now)
+	require.EqualError(t, err, `duration "-1h" must not be negative`)
+
+	_, err = parseLogsTime("yesterday", now)
+	require.EqualError(t, err, `"yesterday" is neither an RFC3339 timestamp nor a duration`)
 }
 
 func TestLogsCommand_AutocompleteArgs(t *testing.T) {
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocRescheduleCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &AllocRescheduleCommand{}
+}
+
+func TestAllocReschedule_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	require := require.New(t)
+	ui := cli.NewMockUi()
+	cmd := &AllocRescheduleCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(1, cmd.Run([]string{"some", "garbage", "args"}))
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	require.Equal(1, cmd.Run([]string{"-address=nope", "foobar"}))
+	require.Contains(ui.ErrorWriter.String(), "Error querying allocation")
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing alloc
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "-unpin", "26470238-5CF2-438F-8772-DC67CFB0705C"}))
+	require.Contains(ui.ErrorWriter.String(), "No allocation(s) with prefix or id")
+	ui.ErrorWriter.Reset()
+
+	// Fail on identifier with too few characters
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "2"}))
+	require.Contains(ui.ErrorWriter.String(), "must contain at least two characters")
+}
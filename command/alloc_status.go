@@ -247,6 +247,24 @@ func formatAllocShortInfo(alloc *api.Allocation, client *api.Client) string {
 	return formatKV(basic)
 }
 
+// formatAllocPinning returns whether the replacement of the allocation is
+// pinned to its node, or an empty string if its task group does not use
+// sticky_host.
+func formatAllocPinning(alloc *api.Allocation, uuidLength int) string {
+	if alloc.Job == nil {
+		return ""
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || tg.StickyHost == nil || !*tg.StickyHost {
+		return ""
+	}
+
+	if alloc.DesiredTransition.ShouldUnpin() {
+		return "unpinned"
+	}
+	return fmt.Sprintf("pinned to node %s", limit(alloc.NodeID, uuidLength))
+}
+
 func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength int, verbose bool) (string, error) {
 	var formattedCreateTime, formattedModifyTime string
 
@@ -305,6 +323,9 @@ func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength
 			basic = append(basic, reschedInfo)
 		}
 	}
+	if pinning := formatAllocPinning(alloc, uuidLength); pinning != "" {
+		basic = append(basic, fmt.Sprintf("Sticky Host|%s", pinning))
+	}
 	if alloc.NextAllocation != "" {
 		basic = append(basic,
 			fmt.Sprintf("Replacement Alloc ID|%s", limit(alloc.NextAllocation, uuidLength)))
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -295,6 +296,37 @@ func TestAllocStatusCommand_RescheduleInfo(t *testing.T) {
 	require.Regexp(regexp.MustCompile(".*Reschedule Attempts\\s*=\\s*1/2"), out)
 }
 
+func TestAllocStatusCommand_StickyHost(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &AllocStatusCommand{Meta: Meta{Ui: ui}}
+	require := require.New(t)
+	state := srv.Agent.Server().State()
+
+	// Allocations of task groups using sticky_host show their pinning
+	a := mock.Alloc()
+	a.Metrics = &structs.AllocMetric{}
+	a.Job.TaskGroups[0].StickyHost = true
+	a2 := mock.Alloc()
+	a2.Metrics = &structs.AllocMetric{}
+	a2.Job = a.Job
+	a2.JobID = a.JobID
+	a2.DesiredTransition.Unpin = helper.BoolToPtr(true)
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{a, a2}))
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, a.ID}))
+	out := ui.OutputWriter.String()
+	require.Regexp(regexp.MustCompile(fmt.Sprintf("Sticky Host\\s*=\\s*pinned to node %s", a.NodeID[:8])), out)
+	ui.OutputWriter.Reset()
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, a2.ID}))
+	out = ui.OutputWriter.String()
+	require.Regexp(regexp.MustCompile("Sticky Host\\s*=\\s*unpinned"), out)
+}
+
 func TestAllocStatusCommand_ScoreMetrics(t *testing.T) {
 	ci.Parallel(t)
 	srv, client, url := testServer(t, true, nil)
@@ -474,7 +506,7 @@ func TestAllocStatusCommand_CSIVolumes(t *testing.T) {
 			Segments: map[string]string{"foo": "bar"},
 		}},
 	}}
-	err = state.UpsertCSIVolume(1002, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1002, vols)
 	require.NoError(t, err)
 
 	// Upsert the job and alloc
@@ -77,11 +77,71 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"acl auth-method": func() (cli.Command, error) {
+			return &ACLAuthMethodCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method create": func() (cli.Command, error) {
+			return &ACLAuthMethodCreateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method delete": func() (cli.Command, error) {
+			return &ACLAuthMethodDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method info": func() (cli.Command, error) {
+			return &ACLAuthMethodInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method list": func() (cli.Command, error) {
+			return &ACLAuthMethodListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl auth-method update": func() (cli.Command, error) {
+			return &ACLAuthMethodUpdateCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl bootstrap": func() (cli.Command, error) {
 			return &ACLBootstrapCommand{
 				Meta: meta,
 			}, nil
 		},
+		"acl binding-rule": func() (cli.Command, error) {
+			return &ACLBindingRuleCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule create": func() (cli.Command, error) {
+			return &ACLBindingRuleCreateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule delete": func() (cli.Command, error) {
+			return &ACLBindingRuleDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule info": func() (cli.Command, error) {
+			return &ACLBindingRuleInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule list": func() (cli.Command, error) {
+			return &ACLBindingRuleListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl binding-rule update": func() (cli.Command, error) {
+			return &ACLBindingRuleUpdateCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl policy": func() (cli.Command, error) {
 			return &ACLPolicyCommand{
 				Meta: meta,
@@ -107,6 +167,36 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"acl role": func() (cli.Command, error) {
+			return &ACLRoleCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role create": func() (cli.Command, error) {
+			return &ACLRoleCreateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role delete": func() (cli.Command, error) {
+			return &ACLRoleDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role info": func() (cli.Command, error) {
+			return &ACLRoleInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role list": func() (cli.Command, error) {
+			return &ACLRoleListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"acl role update": func() (cli.Command, error) {
+			return &ACLRoleUpdateCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl token": func() (cli.Command, error) {
 			return &ACLTokenCommand{
 				Meta: meta,
@@ -157,6 +247,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc reschedule": func() (cli.Command, error) {
+			return &AllocRescheduleCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc stop": func() (cli.Command, error) {
 			return &AllocStopCommand{
 				Meta: meta,
@@ -187,6 +282,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"action": func() (cli.Command, error) {
+			return &ActionCommand{
+				Meta: meta,
+			}, nil
+		},
 		"agent": func() (cli.Command, error) {
 			return &agent.Command{
 				Version:    version.GetVersion(),
@@ -265,6 +365,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"eval delete": func() (cli.Command, error) {
+			return &EvalDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval list": func() (cli.Command, error) {
 			return &EvalListCommand{
 				Meta: meta,
@@ -280,6 +385,36 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"event": func() (cli.Command, error) {
+			return &EventCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink": func() (cli.Command, error) {
+			return &EventSinkCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink deregister": func() (cli.Command, error) {
+			return &EventSinkDeregisterCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink list": func() (cli.Command, error) {
+			return &EventSinkListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink register": func() (cli.Command, error) {
+			return &EventSinkRegisterCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink status": func() (cli.Command, error) {
+			return &EventSinkStatusCommand{
+				Meta: meta,
+			}, nil
+		},
 		"exec": func() (cli.Command, error) {
 			return &AllocExecCommand{
 				Meta: meta,
@@ -415,6 +550,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"login": func() (cli.Command, error) {
+			return &LoginCommand{
+				Meta: meta,
+			}, nil
+		},
 		"logs": func() (cli.Command, error) {
 			return &AllocLogsCommand{
 				Meta: meta,
@@ -465,6 +605,61 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node maintenance": func() (cli.Command, error) {
+			return &NodeMaintenanceCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node maintenance cancel": func() (cli.Command, error) {
+			return &NodeMaintenanceCancelCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node maintenance delete": func() (cli.Command, error) {
+			return &NodeMaintenanceDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node maintenance done": func() (cli.Command, error) {
+			return &NodeMaintenanceDoneCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node maintenance run": func() (cli.Command, error) {
+			return &NodeMaintenanceRunCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node maintenance status": func() (cli.Command, error) {
+			return &NodeMaintenanceStatusCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool": func() (cli.Command, error) {
+			return &NodePoolCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool apply": func() (cli.Command, error) {
+			return &NodePoolApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool delete": func() (cli.Command, error) {
+			return &NodePoolDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool info": func() (cli.Command, error) {
+			return &NodePoolInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"node pool list": func() (cli.Command, error) {
+			return &NodePoolListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node-drain": func() (cli.Command, error) {
 			return &NodeDrainCommand{
 				Meta: meta,
@@ -572,6 +767,17 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 			}, nil
 		},
 
+		"operator scheduler": func() (cli.Command, error) {
+			return &OperatorSchedulerCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator scheduler simulate": func() (cli.Command, error) {
+			return &OperatorSchedulerSimulateCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"operator snapshot": func() (cli.Command, error) {
 			return &OperatorSnapshotCommand{
 				Meta: meta,
@@ -834,6 +1040,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"var": func() (cli.Command, error) {
+			return &VarCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var get": func() (cli.Command, error) {
+			return &VarGetCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var list": func() (cli.Command, error) {
+			return &VarListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var purge": func() (cli.Command, error) {
+			return &VarPurgeCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var put": func() (cli.Command, error) {
+			return &VarPutCommand{
+				Meta: meta,
+			}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &VersionCommand{
 				Version: version.GetVersion(),
@@ -23,6 +23,14 @@ Usage: nomad eval <subcommand> [options] [args]
 
       $ nomad eval status <eval-id>
 
+  List blocked evaluations and why they are blocked:
+
+      $ nomad eval list -status blocked
+
+  Delete pending and blocked evaluations:
+
+      $ nomad eval delete <eval-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type EvalDeleteCommand struct {
+	Meta
+}
+
+func (c *EvalDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad eval delete [options] <evaluation>...
+
+  Delete is used to delete pending and blocked evaluations, either by ID or by
+  matching a filter expression. Evaluations can only be deleted while the eval
+  broker is paused, or while the evaluations of their job are paused with the
+  "nomad job eval -pause" command. When deleting by filter, matching
+  evaluations that cannot be deleted are skipped.
+
+  When ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Eval Delete Options:
+
+  -filter
+    Specifies an expression used to select the evaluations to delete. It
+    cannot be combined with evaluation IDs.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalDeleteCommand) Synopsis() string {
+	return "Delete pending and blocked evaluations"
+}
+
+func (c *EvalDeleteCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-filter": complete.PredictAnything,
+		})
+}
+
+func (c *EvalDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Evals, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Evals]
+	})
+}
+
+func (c *EvalDeleteCommand) Name() string { return "eval delete" }
+
+func (c *EvalDeleteCommand) Run(args []string) int {
+	var filter string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&filter, "filter", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got either evaluation IDs or a filter
+	args = flags.Args()
+	if len(args) == 0 && filter == "" {
+		c.Ui.Error("This command takes either evaluation IDs or the -filter flag")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if len(args) != 0 && filter != "" {
+		c.Ui.Error("Evaluation IDs cannot be combined with the -filter flag")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Evaluation IDs may be given as prefixes, so resolve them to full IDs
+	evalIDs := make([]string, 0, len(args))
+	for _, prefix := range args {
+		evalID, err := c.resolveEvalID(client, prefix)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		evalIDs = append(evalIDs, evalID)
+	}
+
+	req := &api.EvalDeleteRequest{
+		EvalIDs: evalIDs,
+		Filter:  filter,
+	}
+	resp, _, err := client.Evaluations().DeleteOpts(req, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting evaluations: %s", err))
+		return 1
+	}
+
+	noun := "evaluations"
+	if resp.Count == 1 {
+		noun = "evaluation"
+	}
+	c.Ui.Output(fmt.Sprintf("Successfully deleted %d %s", resp.Count, noun))
+	return 0
+}
+
+// resolveEvalID returns the full ID of the evaluation matching the prefix.
+func (c *EvalDeleteCommand) resolveEvalID(client *api.Client, prefix string) (string, error) {
+	if len(prefix) == 1 {
+		return "", fmt.Errorf("Identifier %q must contain at least two characters.", prefix)
+	}
+
+	evalID := sanitizeUUIDPrefix(prefix)
+	evals, _, err := client.Evaluations().PrefixList(evalID)
+	if err != nil {
+		return "", fmt.Errorf("Error querying evaluation: %v", err)
+	}
+
+	switch len(evals) {
+	case 0:
+		return "", fmt.Errorf("No evaluation(s) with prefix %q found", prefix)
+	case 1:
+		return evals[0].ID, nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple evaluations", prefix)
+	}
+}
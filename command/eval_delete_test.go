@@ -0,0 +1,42 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalDeleteCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &EvalDeleteCommand{}
+}
+
+func TestEvalDeleteCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &EvalDeleteCommand{Meta: Meta{Ui: ui}}
+
+	// Fails without evaluation IDs or a filter
+	require.Equal(t, 1, cmd.Run([]string{}))
+	require.Contains(t, ui.ErrorWriter.String(), "either evaluation IDs or the -filter flag")
+	ui.ErrorWriter.Reset()
+
+	// Fails with both evaluation IDs and a filter
+	require.Equal(t, 1, cmd.Run([]string{"-filter", `Status == "pending"`, "12345678"}))
+	require.Contains(t, ui.ErrorWriter.String(), "cannot be combined with the -filter flag")
+	ui.ErrorWriter.Reset()
+
+	// Fails on an unknown evaluation
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "12345678-abcd-efab-cdef-123456789abc"}))
+	require.Contains(t, ui.ErrorWriter.String(), "No evaluation(s) with prefix")
+	ui.ErrorWriter.Reset()
+
+	// Deleting by filter without matches succeeds
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-filter", `Status == "pending"`}))
+	require.Contains(t, ui.OutputWriter.String(), "Successfully deleted 0 evaluations")
+}
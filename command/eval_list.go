@@ -36,13 +36,15 @@ Eval List Options:
     Where to start pagination.
 
   -filter
-    Specifies an expression used to filter query results.
+    Specifies an expression used to filter query results. It can be combined
+    with the -job and -status flags.
 
   -job
     Only show evaluations for this job ID.
 
   -status
-    Only show evaluations with this status.
+    Only show evaluations with this status. When blocked evaluations are
+    listed, the reason each one is blocked is shown.
 
   -json
     Output the evaluation in its JSON format.
@@ -130,11 +132,18 @@ func (c *EvalListCommand) Run(args []string) int {
 		NextToken: pageToken,
 		Params:    map[string]string{},
 	}
-	if filterJobID != "" {
-		opts.Params["job"] = filterJobID
-	}
-	if filterStatus != "" {
-		opts.Params["status"] = filterStatus
+
+	// The job and status query parameters cannot be combined with a filter
+	// expression, so fold them into the expression instead
+	if filter != "" {
+		opts.Filter = evalListFilter(filter, filterJobID, filterStatus)
+	} else {
+		if filterJobID != "" {
+			opts.Params["job"] = filterJobID
+		}
+		if filterStatus != "" {
+			opts.Params["status"] = filterStatus
+		}
 	}
 
 	evals, qm, err := client.Evaluations().List(opts)
@@ -167,8 +176,20 @@ func (c *EvalListCommand) Run(args []string) int {
 		length = fullId
 	}
 
+	// Show why evaluations are blocked if any are listed
+	var hasBlocked bool
+	for _, eval := range evals {
+		if eval.Status == "blocked" {
+			hasBlocked = true
+			break
+		}
+	}
+
 	out := make([]string, len(evals)+1)
 	out[0] = "ID|Priority|Triggered By|Job ID|Status|Placement Failures"
+	if hasBlocked {
+		out[0] += "|Blocked Reason"
+	}
 	for i, eval := range evals {
 		failures, _ := evalFailureStatus(eval)
 		out[i+1] = fmt.Sprintf("%s|%d|%s|%s|%s|%s",
@@ -179,6 +200,9 @@ func (c *EvalListCommand) Run(args []string) int {
 			eval.Status,
 			failures,
 		)
+		if hasBlocked {
+			out[i+1] += "|" + evalBlockedReason(eval)
+		}
 	}
 	c.Ui.Output(formatList(out))
 
@@ -192,6 +216,23 @@ Results have been paginated. To get the next page run:
 	return 0
 }
 
+// evalListFilter returns the filter expression combined with the job ID and
+// status filters, if set.
+func evalListFilter(filter, jobID, status string) string {
+	if jobID == "" && status == "" {
+		return filter
+	}
+
+	exprs := []string{fmt.Sprintf("(%s)", filter)}
+	if jobID != "" {
+		exprs = append(exprs, fmt.Sprintf("JobID == %q", jobID))
+	}
+	if status != "" {
+		exprs = append(exprs, fmt.Sprintf("Status == %q", status))
+	}
+	return strings.Join(exprs, " and ")
+}
+
 // argsWithoutPageToken strips out of the -page-token argument and
 // returns the joined string
 func argsWithoutPageToken(osArgs []string) string {
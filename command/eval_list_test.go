@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEvalList_ArgsWithoutPageToken(t *testing.T) {
@@ -60,3 +61,13 @@ func TestEvalList_ArgsWithoutPageToken(t *testing.T) {
 	}
 
 }
+
+func TestEvalList_Filter(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t, `Priority > 50`, evalListFilter(`Priority > 50`, "", ""))
+	require.Equal(t, `(Priority > 50) and JobID == "example"`,
+		evalListFilter(`Priority > 50`, "example", ""))
+	require.Equal(t, `(Priority > 50) and JobID == "example" and Status == "blocked"`,
+		evalListFilter(`Priority > 50`, "example", "blocked"))
+}
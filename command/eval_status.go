@@ -233,6 +233,10 @@ func (c *EvalStatusCommand) Run(args []string) int {
 		fmt.Sprintf("Priority|%d", eval.Priority),
 		fmt.Sprintf("Placement Failures|%s", failureString))
 
+	if reason := evalBlockedReason(eval); reason != "" {
+		basic = append(basic, fmt.Sprintf("Blocked Reason|%s", reason))
+	}
+
 	if !eval.WaitUntil.IsZero() {
 		basic = append(basic,
 			fmt.Sprintf("Wait Until|%s", formatTime(eval.WaitUntil)))
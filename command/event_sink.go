@@ -0,0 +1,122 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type EventSinkCommand struct {
+	Meta
+}
+
+func (c *EventSinkCommand) Help() string {
+	helpText := `
+Usage: nomad event sink <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with event sinks. An event
+  sink subscribes to topics of the event stream, and the leader delivers the
+  matching events to a webhook or to a file local to the leader. The leader
+  tracks the events delivered to each sink, so that the delivery resumes
+  where it stopped after an outage of the destination or a leader election.
+  Events are delivered at least once.
+
+  Register an event sink appending all the events to a file:
+
+      $ nomad event sink register -type=file -path=/var/log/nomad/events.json all-events
+
+  List the event sinks:
+
+      $ nomad event sink list
+
+  Display the status of an event sink:
+
+      $ nomad event sink status <id>
+
+  Deregister an event sink:
+
+      $ nomad event sink deregister <id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EventSinkCommand) Synopsis() string {
+	return "Interact with event sinks"
+}
+
+func (c *EventSinkCommand) Name() string { return "event sink" }
+
+func (c *EventSinkCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// EventSinkPredictor returns an event sink ID predictor.
+func EventSinkPredictor(factory ApiClientFactory) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		sinks, _, err := client.EventSinks().List(nil)
+		if err != nil {
+			return []string{}
+		}
+
+		var ids []string
+		for _, sink := range sinks {
+			if strings.HasPrefix(sink.ID, a.Last) {
+				ids = append(ids, sink.ID)
+			}
+		}
+		return ids
+	})
+}
+
+// formatEventSinkTopics returns the topics of an event sink in the
+// Topic:Key format of the event stream.
+func formatEventSinkTopics(topics map[api.Topic][]string) string {
+	var out []string
+	for topic, keys := range topics {
+		for _, key := range keys {
+			out = append(out, fmt.Sprintf("%s:%s", topic, key))
+		}
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
+
+// formatEventSinkDestination returns the address or the path of an event
+// sink, depending on its type.
+func formatEventSinkDestination(sink *api.EventSink) string {
+	if sink.Type == api.SinkFile {
+		return sink.Path
+	}
+	return sink.Address
+}
+
+func formatEventSinkList(sinks []*api.EventSink) string {
+	if len(sinks) == 0 {
+		return "No event sinks found"
+	}
+
+	rows := make([]string, len(sinks)+1)
+	rows[0] = "ID|Type|Destination|Topics|Latest Index"
+	for i, sink := range sinks {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%d",
+			sink.ID,
+			sink.Type,
+			formatEventSinkDestination(sink),
+			formatEventSinkTopics(sink.Topics),
+			sink.LatestIndex,
+		)
+	}
+	return formatList(rows)
+}
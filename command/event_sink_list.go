@@ -0,0 +1,102 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type EventSinkListCommand struct {
+	Meta
+}
+
+func (c *EventSinkListCommand) Help() string {
+	helpText := `
+Usage: nomad event sink list [options]
+
+  List is used to list the event sinks along with the index of the last
+  events delivered to them.
+
+  If ACLs are enabled, this command requires a management ACL token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+List Options:
+
+  -json
+    Output the event sinks in a JSON format.
+
+  -t
+    Format and display the event sinks using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EventSinkListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *EventSinkListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *EventSinkListCommand) Synopsis() string {
+	return "List event sinks"
+}
+
+func (c *EventSinkListCommand) Name() string { return "event sink list" }
+
+func (c *EventSinkListCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	sinks, _, err := client.EventSinks().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving event sinks: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, sinks)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatEventSinkList(sinks))
+	return 0
+}
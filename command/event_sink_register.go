@@ -0,0 +1,155 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type EventSinkRegisterCommand struct {
+	Meta
+}
+
+func (c *EventSinkRegisterCommand) Help() string {
+	helpText := `
+Usage: nomad event sink register [options] <id>
+
+  Register is used to register a new event sink or update an existing one.
+  The leader delivers the events matching the topics of the sink to its
+  destination. A new sink receives the events published after it is
+  registered, while the delivery to an updated sink resumes where it
+  stopped.
+
+  The events of the sink are limited to the namespace given with the
+  -namespace flag, or to all namespaces with "*".
+
+  If ACLs are enabled, this command requires a management ACL token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Register Options:
+
+  -type
+    The type of destination of the sink, either "webhook" or "file".
+    Defaults to "webhook".
+
+  -url
+    The URL that a webhook sink sends events to with HTTP POST requests.
+
+  -header
+    An HTTP header to set on the requests of a webhook sink, in the form
+    of "Key=Value". This flag can be specified multiple times.
+
+  -path
+    The absolute path of the file, on the leader, that a file sink appends
+    events to as newline delimited JSON. The path must be within the
+    event_sink_file_dir of the servers.
+
+  -topic
+    A topic and key to subscribe to, in the form of "Topic:Key" as for the
+    event stream. This flag can be specified multiple times. Defaults to
+    all the topics.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EventSinkRegisterCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-type":   complete.PredictSet(string(api.SinkWebhook), string(api.SinkFile)),
+			"-url":    complete.PredictAnything,
+			"-header": complete.PredictAnything,
+			"-path":   complete.PredictFiles("*"),
+			"-topic":  complete.PredictAnything,
+		})
+}
+
+func (c *EventSinkRegisterCommand) AutocompleteArgs() complete.Predictor {
+	return EventSinkPredictor(c.Meta.Client)
+}
+
+func (c *EventSinkRegisterCommand) Synopsis() string {
+	return "Register or update an event sink"
+}
+
+func (c *EventSinkRegisterCommand) Name() string { return "event sink register" }
+
+func (c *EventSinkRegisterCommand) Run(args []string) int {
+	var sinkType, sinkURL, path string
+	var headers, topics flaghelper.StringFlag
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&sinkType, "type", string(api.SinkWebhook), "")
+	flags.StringVar(&sinkURL, "url", "", "")
+	flags.Var(&headers, "header", "")
+	flags.StringVar(&path, "path", "", "")
+	flags.Var(&topics, "topic", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	sink := &api.EventSink{
+		ID:        args[0],
+		Type:      api.SinkType(sinkType),
+		Namespace: c.Meta.namespace,
+		Address:   sinkURL,
+		Path:      path,
+	}
+
+	for _, header := range headers {
+		k, v, ok := strings.Cut(header, "=")
+		if !ok || k == "" {
+			c.Ui.Error(fmt.Sprintf("Invalid header %q, must be in the form of Key=Value", header))
+			return 1
+		}
+		if sink.Headers == nil {
+			sink.Headers = make(map[string]string)
+		}
+		sink.Headers[k] = v
+	}
+
+	for _, topic := range topics {
+		t, key, ok := strings.Cut(topic, ":")
+		if !ok {
+			key = "*"
+		}
+		if t == "" || key == "" || strings.Contains(key, ":") {
+			c.Ui.Error(fmt.Sprintf("Invalid topic %q, must be in the form of Topic:Key", topic))
+			return 1
+		}
+		if sink.Topics == nil {
+			sink.Topics = make(map[api.Topic][]string)
+		}
+		sink.Topics[api.Topic(t)] = append(sink.Topics[api.Topic(t)], key)
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.EventSinks().Register(sink, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error registering event sink: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully registered event sink %q!", sink.ID))
+	return 0
+}
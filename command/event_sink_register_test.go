@@ -0,0 +1,111 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSinkRegisterCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &EventSinkRegisterCommand{}
+}
+
+func TestEventSinkRegisterCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &EventSinkRegisterCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-header=invalid", "sink"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), `Invalid header "invalid"`)
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-topic=Job:a:b", "sink"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), `Invalid topic "Job:a:b"`)
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "-url=http://127.0.0.1:8080", "sink"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error registering event sink")
+}
+
+func TestEventSinkRegisterCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &EventSinkRegisterCommand{Meta: Meta{Ui: ui}}
+
+	// Invalid sinks are rejected by the server
+	code := cmd.Run([]string{"-address=" + url, "-type=file", "-path=events.json", "file"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "must be absolute")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-namespace=*",
+		"-url=http://127.0.0.1:8080/events", "-header=X-Token=secret",
+		"-topic=Job:example", "-topic=Node", "webhook"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully registered event sink "webhook"`)
+
+	sink, _, err := client.EventSinks().Info("webhook", nil)
+	require.NoError(t, err)
+	require.Equal(t, api.SinkWebhook, sink.Type)
+	require.Equal(t, "*", sink.Namespace)
+	require.Equal(t, map[string]string{"X-Token": "secret"}, sink.Headers)
+	require.Equal(t, map[api.Topic][]string{
+		api.TopicJob:  {"example"},
+		api.TopicNode: {"*"},
+	}, sink.Topics)
+
+	// Check the list and status output
+	ui = cli.NewMockUi()
+	listCmd := &EventSinkListCommand{Meta: Meta{Ui: ui}}
+	code = listCmd.Run([]string{"-address=" + url})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "webhook")
+	require.Contains(t, out, "Job:example,Node:*")
+
+	ui = cli.NewMockUi()
+	statusCmd := &EventSinkStatusCommand{Meta: Meta{Ui: ui}}
+	code = statusCmd.Run([]string{"-address=" + url, "webhook"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out = ui.OutputWriter.String()
+	require.Contains(t, out, "http://127.0.0.1:8080/events")
+	require.Contains(t, out, "X-Token")
+	require.NotContains(t, out, "secret")
+
+	ui = cli.NewMockUi()
+	statusCmd = &EventSinkStatusCommand{Meta: Meta{Ui: ui}}
+	code = statusCmd.Run([]string{"-address=" + url, "-t", "{{ .Type }}", "webhook"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Equal(t, "webhook", strings.TrimSpace(ui.OutputWriter.String()))
+
+	// Deregister the sink
+	ui = cli.NewMockUi()
+	deregisterCmd := &EventSinkDeregisterCommand{Meta: Meta{Ui: ui}}
+	code = deregisterCmd.Run([]string{"-address=" + url, "webhook"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully deregistered event sink "webhook"`)
+
+	ui = cli.NewMockUi()
+	listCmd = &EventSinkListCommand{Meta: Meta{Ui: ui}}
+	code = listCmd.Run([]string{"-address=" + url})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "No event sinks found")
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type EventSinkStatusCommand struct {
+	Meta
+}
+
+func (c *EventSinkStatusCommand) Help() string {
+	helpText := `
+Usage: nomad event sink status [options] <id>
+
+  Status is used to display the configuration of an event sink along with the
+  index of the last events delivered to it.
+
+  If ACLs are enabled, this command requires a management ACL token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Status Options:
+
+  -json
+    Output the event sink in a JSON format.
+
+  -t
+    Format and display the event sink using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EventSinkStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *EventSinkStatusCommand) AutocompleteArgs() complete.Predictor {
+	return EventSinkPredictor(c.Meta.Client)
+}
+
+func (c *EventSinkStatusCommand) Synopsis() string {
+	return "Display the status of an event sink"
+}
+
+func (c *EventSinkStatusCommand) Name() string { return "event sink status" }
+
+func (c *EventSinkStatusCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	sink, _, err := client.EventSinks().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving event sink: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, sink)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	basic := []string{
+		fmt.Sprintf("ID|%s", sink.ID),
+		fmt.Sprintf("Type|%s", sink.Type),
+		fmt.Sprintf("Destination|%s", formatEventSinkDestination(sink)),
+		fmt.Sprintf("Namespace|%s", sink.Namespace),
+		fmt.Sprintf("Topics|%s", formatEventSinkTopics(sink.Topics)),
+		fmt.Sprintf("Latest Index|%d", sink.LatestIndex),
+	}
+
+	// Events are only lost if the sink fell behind the event buffer
+	if sink.LostEventsIndex != 0 {
+		basic = append(basic, fmt.Sprintf("Lost Events After Index|%d", sink.LostEventsIndex))
+	}
+
+	// Only display the header names, as their values may be secrets
+	if len(sink.Headers) > 0 {
+		names := make([]string, 0, len(sink.Headers))
+		for name := range sink.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		basic = append(basic, fmt.Sprintf("Headers|%s", strings.Join(names, ",")))
+	}
+
+	c.Ui.Output(formatKV(basic))
+	return 0
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -259,6 +260,45 @@ func evalFailureStatus(eval *api.Evaluation) (string, bool) {
 	return text, hasFailures
 }
 
+// evalBlockedReason returns why a blocked evaluation is blocked, or an empty
+// string if the evaluation is not blocked. Classes marked as eligible had
+// feasible nodes that were exhausted, while ineligible classes had none.
+func evalBlockedReason(eval *api.Evaluation) string {
+	if eval == nil || eval.Status != "blocked" {
+		return ""
+	}
+
+	if eval.QuotaLimitReached != "" {
+		return fmt.Sprintf("quota %q limit reached", eval.QuotaLimitReached)
+	}
+	if eval.EscapedComputedClass {
+		return "constraints escape computed node classes"
+	}
+
+	var exhausted, ineligible []string
+	for class, eligible := range eval.ClassEligibility {
+		if eligible {
+			exhausted = append(exhausted, class)
+		} else {
+			ineligible = append(ineligible, class)
+		}
+	}
+	sort.Strings(exhausted)
+	sort.Strings(ineligible)
+
+	var reasons []string
+	if len(exhausted) != 0 {
+		reasons = append(reasons, "exhausted classes: "+strings.Join(exhausted, ", "))
+	}
+	if len(ineligible) != 0 {
+		reasons = append(reasons, "ineligible classes: "+strings.Join(ineligible, ", "))
+	}
+	if len(reasons) == 0 {
+		return "awaiting capacity"
+	}
+	return strings.Join(reasons, "; ")
+}
+
 // LineLimitReader wraps another reader and provides `tail -n` like behavior.
 // LineLimitReader buffers up to the searchLimit and returns `-n` number of
 // lines. After those lines have been returned, LineLimitReader streams the
@@ -424,10 +464,14 @@ func (j *JobGetter) ApiJobWithArgs(jpath string, vars []string, varfiles []strin
 	j.VarFiles = varfiles
 	j.Strict = strict
 
-	return j.Get(jpath)
+	_, job, err := j.Get(jpath)
+	return job, err
 }
 
-func (j *JobGetter) Get(jpath string) (*api.Job, error) {
+// Get reads and parses the jobspec at jpath. Along with the job, it returns
+// the source and variables the job was parsed from, so that they can be
+// stored with the registered job version.
+func (j *JobGetter) Get(jpath string) (*api.JobSubmission, *api.Job, error) {
 	var jobfile io.Reader
 	pathName := filepath.Base(jpath)
 	switch jpath {
@@ -440,23 +484,23 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		pathName = "stdin"
 	default:
 		if len(jpath) == 0 {
-			return nil, fmt.Errorf("Error jobfile path has to be specified.")
+			return nil, nil, fmt.Errorf("Error jobfile path has to be specified.")
 		}
 
 		jobFile, err := os.CreateTemp("", "jobfile")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		defer os.Remove(jobFile.Name())
 
 		if err := jobFile.Close(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Get the pwd
 		pwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		client := &gg.Client{
@@ -469,24 +513,36 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		}
 
 		if err := client.Get(); err != nil {
-			return nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
+			return nil, nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
 		} else {
 			file, err := os.Open(jobFile.Name())
 			if err != nil {
-				return nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
+				return nil, nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
 			}
 			defer file.Close()
 			jobfile = file
 		}
 	}
 
+	// Read the whole job file so that its source can be submitted along with
+	// the job
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, jobfile); err != nil {
+		return nil, nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+	src := buf.Bytes()
+
 	// Parse the JobFile
 	var jobStruct *api.Job
 	var err error
+	sub := &api.JobSubmission{Source: string(src)}
 	switch {
 	case j.HCL1:
-		jobStruct, err = jobspec.Parse(jobfile)
+		sub.Format = api.JobSubmissionFormatHCL1
+		jobStruct, err = jobspec.Parse(bytes.NewReader(src))
 	case j.JSON:
+		sub.Format = api.JobSubmissionFormatJSON
+
 		// Support JSON files with both a top-level Job key as well as
 		// ones without.
 		eitherJob := struct {
@@ -494,8 +550,8 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			api.Job
 		}{}
 
-		if err := json.NewDecoder(jobfile).Decode(&eitherJob); err != nil {
-			return nil, fmt.Errorf("Failed to parse JSON job: %w", err)
+		if err := json.Unmarshal(src, &eitherJob); err != nil {
+			return nil, nil, fmt.Errorf("Failed to parse JSON job: %w", err)
 		}
 
 		if eitherJob.NestedJob != nil {
@@ -504,33 +560,75 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			jobStruct = &eitherJob.Job
 		}
 	default:
-		var buf bytes.Buffer
-		_, err = io.Copy(&buf, jobfile)
-		if err != nil {
-			return nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
-		}
+		sub.Format = api.JobSubmissionFormatHCL2
+
+		// Record the files and environment variables the job is rendered
+		// with, so the submission captures everything the job depends on
+		files := make(map[string]string)
+		envs := make(map[string]string)
 		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
 			Path:     pathName,
-			Body:     buf.Bytes(),
+			Body:     src,
 			ArgVars:  j.Vars,
 			AllowFS:  true,
 			VarFiles: j.VarFiles,
 			Envs:     os.Environ(),
 			Strict:   j.Strict,
+			ReadFileHook: func(path, content string) {
+				files[path] = content
+			},
+			EnvVarHook: func(name, value string) {
+				envs[name] = value
+			},
 		})
+		if len(files) > 0 {
+			sub.Files = files
+		}
+		if len(envs) > 0 {
+			sub.VariableEnvs = envs
+		}
 
 		if err != nil {
-			if _, merr := jobspec.Parse(&buf); merr == nil {
-				return nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
+			if _, merr := jobspec.Parse(bytes.NewReader(src)); merr == nil {
+				return nil, nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
 			}
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
+		return nil, nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
+	}
+
+	if sub.Format == api.JobSubmissionFormatHCL2 {
+		if sub.VariableFlags, sub.Variables, err = j.submissionVariables(); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return jobStruct, nil
+	return sub, jobStruct, nil
+}
+
+// submissionVariables returns the -var flags as a map and the concatenated
+// content of the -var-file flags, as used to parse an HCL2 job.
+func (j *JobGetter) submissionVariables() (map[string]string, string, error) {
+	var flags map[string]string
+	if len(j.Vars) > 0 {
+		flags = make(map[string]string, len(j.Vars))
+	}
+	for _, v := range j.Vars {
+		key, value, _ := strings.Cut(v, "=")
+		flags[key] = value
+	}
+
+	var files []string
+	for _, path := range j.VarFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("Error reading variable file %q: %v", path, err)
+		}
+		files = append(files, string(content))
+	}
+	return flags, strings.Join(files, "\n"), nil
 }
 
 // mergeAutocompleteFlags is used to join multiple flag completion sets.
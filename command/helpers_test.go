@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -47,6 +48,61 @@ func TestHelpers_FormatList(t *testing.T) {
 	}
 }
 
+func TestHelpers_EvalBlockedReason(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name     string
+		eval     *api.Evaluation
+		expected string
+	}{
+		{
+			name:     "not blocked",
+			eval:     &api.Evaluation{Status: "pending"},
+			expected: "",
+		},
+		{
+			name: "quota",
+			eval: &api.Evaluation{
+				Status:            "blocked",
+				QuotaLimitReached: "default",
+			},
+			expected: `quota "default" limit reached`,
+		},
+		{
+			name: "escaped",
+			eval: &api.Evaluation{
+				Status:               "blocked",
+				EscapedComputedClass: true,
+			},
+			expected: "constraints escape computed node classes",
+		},
+		{
+			name: "class eligibility",
+			eval: &api.Evaluation{
+				Status: "blocked",
+				ClassEligibility: map[string]bool{
+					"v1:2": true,
+					"v1:1": true,
+					"v1:3": false,
+				},
+			},
+			expected: "exhausted classes: v1:1, v1:2; ineligible classes: v1:3",
+		},
+		{
+			name:     "capacity",
+			eval:     &api.Evaluation{Status: "blocked"},
+			expected: "awaiting capacity",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, evalBlockedReason(tc.eval))
+		})
+	}
+}
+
 func TestHelpers_NodeID(t *testing.T) {
 	ci.Parallel(t)
 	srv, _, _ := testServer(t, false, nil)
@@ -376,6 +432,92 @@ job "example" {
 	require.Equal(t, expected, j.Datacenters)
 }
 
+// TestJobGetter_Submission asserts the job source and variables are returned
+// along with the parsed job
+func TestJobGetter_Submission(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+variable "dc" {}
+variable "region" {}
+
+job "example" {
+  datacenters = [var.dc]
+  region      = var.region
+}
+`
+	hclf, err := ioutil.TempFile("", "hcl")
+	require.NoError(t, err)
+	defer os.Remove(hclf.Name())
+	defer hclf.Close()
+	_, err = hclf.WriteString(hcl)
+	require.NoError(t, err)
+
+	vf, err := ioutil.TempFile("", "var.hcl")
+	require.NoError(t, err)
+	defer os.Remove(vf.Name())
+	defer vf.Close()
+	_, err = vf.WriteString(`region = "west"`)
+	require.NoError(t, err)
+
+	getter := &JobGetter{
+		Vars:     []string{"dc=dc1"},
+		VarFiles: []string{vf.Name()},
+		Strict:   true,
+	}
+	sub, j, err := getter.Get(hclf.Name())
+	require.NoError(t, err)
+	require.Equal(t, []string{"dc1"}, j.Datacenters)
+	require.Equal(t, "west", *j.Region)
+
+	require.Equal(t, hcl, sub.Source)
+	require.Equal(t, api.JobSubmissionFormatHCL2, sub.Format)
+	require.Equal(t, map[string]string{"dc": "dc1"}, sub.VariableFlags)
+	require.Equal(t, `region = "west"`, sub.Variables)
+
+	// JSON jobs are submitted without variables
+	js := `{"Job": {"ID": "example"}}`
+	sub, j, err = (&JobGetter{JSON: true, testStdin: strings.NewReader(js)}).Get("-")
+	require.NoError(t, err)
+	require.Equal(t, "example", *j.ID)
+	require.Equal(t, js, sub.Source)
+	require.Equal(t, api.JobSubmissionFormatJSON, sub.Format)
+	require.Nil(t, sub.VariableFlags)
+	require.Empty(t, sub.Variables)
+}
+
+// TestJobGetter_Submission_FilesAndEnvs asserts the files read by the job and
+// the environment variables setting its variables are part of the submission
+func TestJobGetter_Submission_FilesAndEnvs(t *testing.T) {
+	// Not parallel as the test sets environment variables
+	t.Setenv("NOMAD_VAR_dc", "dc1")
+	t.Setenv("NOMAD_VAR_unused", "value")
+
+	dir := t.TempDir()
+	regionPath := filepath.Join(dir, "region.txt")
+	require.NoError(t, os.WriteFile(regionPath, []byte("west"), 0644))
+
+	hcl := fmt.Sprintf(`
+variable "dc" {}
+
+job "example" {
+  datacenters = [var.dc]
+  region      = file(%q)
+}
+`, regionPath)
+	path := filepath.Join(dir, "example.nomad")
+	require.NoError(t, os.WriteFile(path, []byte(hcl), 0644))
+
+	sub, j, err := (&JobGetter{}).Get(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dc1"}, j.Datacenters)
+	require.Equal(t, "west", *j.Region)
+
+	require.Equal(t, map[string]string{regionPath: "west"}, sub.Files)
+	require.Equal(t, map[string]string{"dc": "dc1"}, sub.VariableEnvs)
+	require.Nil(t, sub.VariableFlags)
+}
+
 func TestJobGetter_HCL2_Variables_StrictFalse(t *testing.T) {
 	ci.Parallel(t)
 
@@ -23,6 +23,11 @@ Usage: nomad job eval [options] <job_id>
   operators to force the scheduler to create new allocations under certain
   scenarios.
 
+  The -pause and -resume flags instead pause or resume the evaluations of the
+  job. While paused, evaluations of the job are held by the eval broker rather
+  than being processed by the schedulers. Resuming releases any held
+  evaluations.
+
   When ACLs are enabled, this command requires a token with the 'submit-job'
   capability for the job's namespace.
 
@@ -36,6 +41,12 @@ Eval Options:
     Force reschedule failed allocations even if they are not currently
     eligible for rescheduling.
 
+  -pause
+    Pause the evaluations of the job instead of forcing an evaluation.
+
+  -resume
+    Resume the evaluations of the job instead of forcing an evaluation.
+
   -detach
     Return immediately instead of entering monitor mode. The ID
     of the evaluation created will be printed to the screen, which can be
@@ -55,6 +66,8 @@ func (c *JobEvalCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-force-reschedule": complete.PredictNothing,
+			"-pause":            complete.PredictNothing,
+			"-resume":           complete.PredictNothing,
 			"-detach":           complete.PredictNothing,
 			"-verbose":          complete.PredictNothing,
 		})
@@ -78,11 +91,13 @@ func (c *JobEvalCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobEvalCommand) Name() string { return "job eval" }
 
 func (c *JobEvalCommand) Run(args []string) int {
-	var detach, verbose bool
+	var detach, verbose, pause, resume bool
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&c.forceRescheduling, "force-reschedule", false, "")
+	flags.BoolVar(&pause, "pause", false, "")
+	flags.BoolVar(&resume, "resume", false, "")
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 
@@ -98,6 +113,17 @@ func (c *JobEvalCommand) Run(args []string) int {
 		return 1
 	}
 
+	if pause && resume {
+		c.Ui.Error("The -pause and -resume flags cannot be used together")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if (pause || resume) && c.forceRescheduling {
+		c.Ui.Error("The -force-reschedule flag cannot be used with -pause or -resume")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -113,6 +139,20 @@ func (c *JobEvalCommand) Run(args []string) int {
 	// Call eval endpoint
 	jobID := args[0]
 
+	if pause || resume {
+		if _, err := client.Jobs().EvalPause(jobID, pause, nil); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error updating job evaluations: %s", err))
+			return 1
+		}
+
+		if pause {
+			c.Ui.Output(fmt.Sprintf("Evaluations of job %q paused", jobID))
+		} else {
+			c.Ui.Output(fmt.Sprintf("Evaluations of job %q resumed", jobID))
+		}
+		return 0
+	}
+
 	opts := api.EvalOptions{
 		ForceReschedule: c.forceRescheduling,
 	}
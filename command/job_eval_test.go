@@ -43,6 +43,23 @@ func TestJobEvalCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 
+	// Fails when pausing and resuming at once
+	if code := cmd.Run([]string{"-pause", "-resume", "example"}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "cannot be used together") {
+		t.Fatalf("unexpected error: %v", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails when pausing with -force-reschedule
+	if code := cmd.Run([]string{"-pause", "-force-reschedule", "example"}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "cannot be used with -pause or -resume") {
+		t.Fatalf("unexpected error: %v", out)
+	}
+	ui.ErrorWriter.Reset()
 }
 
 func TestJobEvalCommand_Run(t *testing.T) {
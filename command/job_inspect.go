@@ -32,6 +32,12 @@ Inspect Options:
   -version <job version>
     Display the job at the given job version.
 
+  -hcl
+    Display the original source the job version was submitted with instead
+    of the job. The source is only available if the job was registered with
+    "nomad job run". When combined with -json or -t, the source and the HCL2
+    variables it was rendered with are formatted instead.
+
   -json
     Output the job in its JSON format.
 
@@ -49,6 +55,7 @@ func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-version": complete.PredictAnything,
+			"-hcl":     complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
 		})
@@ -72,12 +79,13 @@ func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
+	var json, hcl bool
 	var tmpl, versionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&hcl, "hcl", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&versionStr, "version", "", "")
 
@@ -154,6 +162,28 @@ func (c *JobInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	if hcl {
+		sub, err := getJobSubmission(client, *job.Namespace, *job.ID, *job.Version)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error inspecting job source: %s", err))
+			return 1
+		}
+
+		if json || len(tmpl) > 0 {
+			out, err := Format(json, tmpl, sub)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+
+			c.Ui.Output(out)
+			return 0
+		}
+
+		c.Ui.Output(strings.TrimSuffix(sub.Source, "\n"))
+		return 0
+	}
+
 	// If output format is specified, format and output the data
 	if json || len(tmpl) > 0 {
 		out, err := Format(json, tmpl, job)
@@ -212,3 +242,17 @@ func getJob(client *api.Client, namespace, jobID string, version *uint64) (*api.
 
 	return nil, fmt.Errorf("job %q with version %d couldn't be found", jobID, *version)
 }
+
+// getJobSubmission retrieves the source the job was submitted with at the
+// given version.
+func getJobSubmission(client *api.Client, namespace, jobID string, version uint64) (*api.JobSubmission, error) {
+	q := &api.QueryOptions{Namespace: namespace}
+	sub, _, err := client.Jobs().Submission(jobID, int(version), q)
+	if err != nil {
+		if strings.Contains(err.Error(), "job submission not found") {
+			return nil, fmt.Errorf("job %q has no source stored for version %d", jobID, version)
+		}
+		return nil, err
+	}
+	return sub, nil
+}
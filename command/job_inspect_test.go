@@ -10,6 +10,7 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInspectCommand_Implements(t *testing.T) {
@@ -61,6 +62,42 @@ func TestInspectCommand_Fails(t *testing.T) {
 	}
 }
 
+func TestInspectCommand_HCL(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:        `job "example" {}` + "\n",
+		Format:        structs.JobSubmissionFormatHCL2,
+		VariableFlags: map[string]string{"dc": "dc1"},
+	}
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+
+	ui := cli.NewMockUi()
+	cmd := &JobInspectCommand{Meta: Meta{Ui: ui}}
+
+	// Outputs the source
+	code := cmd.Run([]string{"-address=" + url, "-hcl", job.ID})
+	require.Zero(t, code, ui.ErrorWriter.String())
+	require.Equal(t, `job "example" {}`+"\n", ui.OutputWriter.String())
+	ui.OutputWriter.Reset()
+
+	// Formats the submission with a template
+	code = cmd.Run([]string{"-address=" + url, "-hcl", "-t", "{{.VariableFlags.dc}}", job.ID})
+	require.Zero(t, code, ui.ErrorWriter.String())
+	require.Equal(t, "dc1\n", ui.OutputWriter.String())
+
+	// Fails for a version without a source
+	job2 := job.Copy()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job2))
+	code = cmd.Run([]string{"-address=" + url, "-hcl", job.ID})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "has no source stored for version 1")
+}
+
 func TestInspectCommand_AutocompleteArgs(t *testing.T) {
 	ci.Parallel(t)
 	assert := assert.New(t)
@@ -163,7 +163,7 @@ func (c *JobPlanCommand) Run(args []string) int {
 
 	path := args[0]
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.Get(path)
+	_, job, err := c.JobGetter.Get(path)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 255
@@ -412,10 +412,41 @@ func formatDryRun(resp *api.JobPlanResponse, job *api.Job) string {
 		}
 	}
 
+	if resp.Annotations != nil && len(resp.Annotations.SchedulerAlgorithms) > 0 {
+		out += fmt.Sprintf("[green]- Scheduler algorithm: %s.\n", formatSchedulerAlgorithms(resp.Annotations.SchedulerAlgorithms))
+	}
+
 	out = strings.TrimSuffix(out, "\n")
 	return out
 }
 
+// formatSchedulerAlgorithms returns the scheduler algorithm used to place the
+// job, or the algorithm of each task group when they differ.
+func formatSchedulerAlgorithms(algorithms map[string]api.SchedulerAlgorithm) string {
+	tgs := make([]string, 0, len(algorithms))
+	for tg := range algorithms {
+		tgs = append(tgs, tg)
+	}
+	sort.Strings(tgs)
+
+	uniform := true
+	for _, tg := range tgs {
+		if algorithms[tg] != algorithms[tgs[0]] {
+			uniform = false
+			break
+		}
+	}
+	if uniform {
+		return string(algorithms[tgs[0]])
+	}
+
+	out := make([]string, 0, len(tgs))
+	for _, tg := range tgs {
+		out = append(out, fmt.Sprintf("%s (group %q)", algorithms[tg], tg))
+	}
+	return strings.Join(out, ", ")
+}
+
 // formatJobDiff produces an annotated diff of the job. If verbose mode is
 // set, added or deleted task groups and tasks are expanded.
 func formatJobDiff(job *api.JobDiff, verbose bool) string {
@@ -271,3 +271,16 @@ func TestPlanCommad_JSON(t *testing.T) {
 	require.Equal(t, 255, code)
 	require.Contains(t, ui.ErrorWriter.String(), "Error during plan: Put")
 }
+
+func TestPlanCommand_FormatSchedulerAlgorithms(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t, "spread", formatSchedulerAlgorithms(map[string]api.SchedulerAlgorithm{
+		"web": api.SchedulerAlgorithmSpread,
+		"api": api.SchedulerAlgorithmSpread,
+	}))
+	require.Equal(t, `spread (group "api"), binpack (group "web")`, formatSchedulerAlgorithms(map[string]api.SchedulerAlgorithm{
+		"web": api.SchedulerAlgorithmBinpack,
+		"api": api.SchedulerAlgorithmSpread,
+	}))
+}
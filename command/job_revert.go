@@ -43,6 +43,10 @@ Revert Options:
    The Vault token used to verify that the caller has access to the Vault
    policies in the targeted version of the job.
 
+  -hcl
+    Display the original source the job version being reverted to was
+    submitted with before reverting.
+
   -verbose
     Display full information.
 `
@@ -57,6 +61,7 @@ func (c *JobRevertCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-detach":  complete.PredictNothing,
+			"-hcl":     complete.PredictNothing,
 			"-verbose": complete.PredictNothing,
 		})
 }
@@ -79,12 +84,13 @@ func (c *JobRevertCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobRevertCommand) Name() string { return "job revert" }
 
 func (c *JobRevertCommand) Run(args []string) int {
-	var detach, verbose bool
+	var detach, verbose, hcl bool
 	var consulToken, vaultToken string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&hcl, "hcl", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.StringVar(&consulToken, "consul-token", "", "")
 	flags.StringVar(&vaultToken, "vault-token", "", "")
@@ -154,6 +160,18 @@ func (c *JobRevertCommand) Run(args []string) int {
 		}
 	}
 
+	// Display the source of the version being reverted to. A missing source
+	// does not prevent the revert.
+	if hcl {
+		sub, err := getJobSubmission(client, jobs[0].JobSummary.Namespace, jobs[0].ID, revertVersion)
+		if err != nil {
+			c.Ui.Warn(fmt.Sprintf("Unable to display job source: %s", err))
+		} else {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]Reverting to the source of version %d:[reset]\n", revertVersion)))
+			c.Ui.Output(strings.TrimSuffix(sub.Source, "\n") + "\n")
+		}
+	}
+
 	// Prefix lookup matched a single job
 	q := &api.WriteOptions{Namespace: jobs[0].JobSummary.Namespace}
 	resp, _, err := client.Jobs().Revert(jobs[0].ID, revertVersion, nil, q, consulToken, vaultToken)
@@ -569,6 +569,10 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 		summaries[0] = "Pending|Running|Dead"
 		summaries[1] = fmt.Sprintf("%d|%d|%d",
 			summary.Children.Pending, summary.Children.Running, summary.Children.Dead)
+		if periodic {
+			summaries[0] += "|Skipped"
+			summaries[1] += fmt.Sprintf("|%d", summary.Children.Skipped)
+		}
 		c.Ui.Output(formatList(summaries))
 	}
 
@@ -0,0 +1,100 @@
+package command
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/freeport"
+	"github.com/hashicorp/nomad/lib/auth/oidc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Build a test server with ACLs enabled.
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.ACL.Enabled = true
+	})
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &LoginCommand{
+		Meta: Meta{
+			Ui:          ui,
+			flagAddress: url,
+		},
+	}
+
+	// Test the basic validation on the command.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "this-command-does-not-take-args"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "-type=SAML"}))
+	require.Contains(t, ui.ErrorWriter.String(), `Unsupported authentication type "SAML"`)
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Attempt to login without a method name when no default exists.
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), "Must specify an auth method name, no default found")
+
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Start the mock OIDC provider and configure a default auth method which
+	// uses it, along with a binding rule granting management privileges.
+	oidcTestProvider := oidc.NewTestProvider(t)
+
+	ports := freeport.MustTake(1)
+	defer freeport.Return(ports)
+	callbackAddr := fmt.Sprintf("127.0.0.1:%d", ports[0])
+
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Default = true
+	authMethod.Config = &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    oidcTestProvider.Issuer(),
+		OIDCClientID:        oidcTestProvider.ClientID(),
+		OIDCClientSecret:    oidcTestProvider.ClientSecret(),
+		AllowedRedirectURIs: []string{"http://" + callbackAddr + oidc.CallbackPath},
+	}
+	authMethod.SetHash()
+	require.NoError(t, srv.Agent.Server().State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.AuthMethod = authMethod.Name
+	bindingRule.Selector = ""
+	bindingRule.BindType = structs.ACLBindingRuleBindTypeManagement
+	bindingRule.BindName = ""
+	bindingRule.SetHash()
+	require.NoError(t, srv.Agent.Server().State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLBindingRule{bindingRule}, false))
+
+	// Rather than opening a browser, visit the auth URL directly. The
+	// provider redirects to the local callback server, which completes the
+	// login.
+	cmd.openURL = func(authURL string) error {
+		resp, err := http.Get(authURL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-oidc-callback-addr=" + callbackAddr}))
+	s := ui.OutputWriter.String()
+	require.Contains(t, s, fmt.Sprintf("Successfully logged in via OIDC and %s", authMethod.Name))
+	require.Contains(t, s, "Type         = management")
+	require.Contains(t, s, "OIDC-"+authMethod.Name)
+}
@@ -82,6 +82,28 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 
 	c.Ui.Output(formatNamespaceBasics(ns))
 
+	if ns.NodePoolConfiguration != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Node Pool Configuration[reset]"))
+		npConfig := ns.NodePoolConfiguration
+		npConfigOut := []string{
+			fmt.Sprintf("Default|%s", npConfig.Default),
+		}
+		if len(npConfig.Allowed) > 0 {
+			npConfigOut = append(npConfigOut, fmt.Sprintf("Allowed|%s", strings.Join(npConfig.Allowed, ", ")))
+		}
+		if len(npConfig.Denied) > 0 {
+			npConfigOut = append(npConfigOut, fmt.Sprintf("Denied|%s", strings.Join(npConfig.Denied, ", ")))
+		}
+		c.Ui.Output(formatKV(npConfigOut))
+	}
+
+	if ns.SchedulerConfiguration != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Scheduler Configuration[reset]"))
+		c.Ui.Output(formatKV([]string{
+			fmt.Sprintf("Scheduler Algorithm|%s", ns.SchedulerConfiguration.SchedulerAlgorithm),
+		}))
+	}
+
 	if len(ns.Meta) > 0 {
 		c.Ui.Output(c.Colorize().Color("\n[bold]Metadata[reset]"))
 		var meta []string
@@ -68,6 +68,69 @@ func TestNamespaceStatusCommand_Good(t *testing.T) {
 	}
 }
 
+func TestNamespaceStatusCommand_NodePoolConfiguration(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NamespaceStatusCommand{Meta: Meta{Ui: ui}}
+
+	// Create a namespace with a node pool configuration
+	ns := &api.Namespace{
+		Name: "foo",
+		NodePoolConfiguration: &api.NamespaceNodePoolConfiguration{
+			Default: "dev",
+			Denied:  []string{"prod", "gpu-*"},
+		},
+	}
+	_, err := client.Namespaces().Register(ns, nil)
+	assert.Nil(t, err)
+
+	// Check status on namespace
+	if code := cmd.Run([]string{"-address=" + url, ns.Name}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+
+	out := ui.OutputWriter.String()
+	assert.Contains(t, out, "Node Pool Configuration")
+	assert.Contains(t, out, "Default = dev")
+	assert.Contains(t, out, "Denied  = prod, gpu-*")
+	assert.NotContains(t, out, "Allowed")
+}
+
+func TestNamespaceStatusCommand_SchedulerConfiguration(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NamespaceStatusCommand{Meta: Meta{Ui: ui}}
+
+	// Create a namespace with a scheduler configuration
+	ns := &api.Namespace{
+		Name: "foo",
+		SchedulerConfiguration: &api.NamespaceSchedulerConfiguration{
+			SchedulerAlgorithm: api.SchedulerAlgorithmSpread,
+		},
+	}
+	_, err := client.Namespaces().Register(ns, nil)
+	assert.Nil(t, err)
+
+	// Check status on namespace
+	if code := cmd.Run([]string{"-address=" + url, ns.Name}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+
+	out := ui.OutputWriter.String()
+	assert.Contains(t, out, "Scheduler Configuration")
+	assert.Contains(t, out, "Scheduler Algorithm = spread")
+}
+
 func TestNamespaceStatusCommand_Good_Quota(t *testing.T) {
 	ci.Parallel(t)
 
@@ -32,6 +32,14 @@ Usage: nomad node <subcommand> [options] [args]
 
       $ nomad node drain -enable -deadline 4h <node-id>
 
+  Drain the nodes of a node class one at a time for a rolling maintenance:
+
+      $ nomad node maintenance run -node-class <class>
+
+  List the node pools nodes may be partitioned into:
+
+      $ nomad node pool list
+
   Please see the individual subcommand help for detailed usage information.
 `
 
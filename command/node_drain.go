@@ -68,6 +68,32 @@ Node Drain Options:
     Ignore system allows the drain to complete without stopping system job
     allocations. By default system jobs are stopped last.
 
+  -job-order <job1,job2,...>
+    Comma separated list of job IDs whose allocations are migrated first, in
+    order. The allocations of a job are only migrated once those of the jobs
+    before it have left the node.
+
+  -order-by-priority
+    Migrate the allocations of higher priority jobs before those of lower
+    priority jobs. Jobs listed in -job-order are migrated first.
+
+  -max-parallel-migrations <n>
+    Maximum number of allocations that may be migrating across the whole
+    cluster at once while this node drains. An allocation is migrating until
+    its replacement is healthy. Defaults to no limit.
+
+  -window-cron <cron>
+    Cron expression for the start of the maintenance windows in which the drain
+    may start. The node is marked ineligible immediately, but allocations are
+    only migrated once a window opens and the deadline is measured from the
+    start of that window. Requires -window-duration.
+
+  -window-duration <duration>
+    How long each maintenance window lasts.
+
+  -window-timezone <timezone>
+    Time zone the -window-cron expression is evaluated in. Defaults to UTC.
+
   -keep-ineligible
     Keep ineligible will maintain the node's scheduling ineligibility even if
     the drain is being disabled. This is useful when an existing drain is being
@@ -96,18 +122,24 @@ func (c *NodeDrainCommand) Synopsis() string {
 func (c *NodeDrainCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-disable":         complete.PredictNothing,
-			"-enable":          complete.PredictNothing,
-			"-deadline":        complete.PredictAnything,
-			"-detach":          complete.PredictNothing,
-			"-force":           complete.PredictNothing,
-			"-no-deadline":     complete.PredictNothing,
-			"-ignore-system":   complete.PredictNothing,
-			"-keep-ineligible": complete.PredictNothing,
-			"-m":               complete.PredictNothing,
-			"-meta":            complete.PredictNothing,
-			"-self":            complete.PredictNothing,
-			"-yes":             complete.PredictNothing,
+			"-disable":                 complete.PredictNothing,
+			"-enable":                  complete.PredictNothing,
+			"-deadline":                complete.PredictAnything,
+			"-detach":                  complete.PredictNothing,
+			"-force":                   complete.PredictNothing,
+			"-no-deadline":             complete.PredictNothing,
+			"-ignore-system":           complete.PredictNothing,
+			"-job-order":               complete.PredictAnything,
+			"-order-by-priority":       complete.PredictNothing,
+			"-max-parallel-migrations": complete.PredictAnything,
+			"-window-cron":             complete.PredictAnything,
+			"-window-duration":         complete.PredictAnything,
+			"-window-timezone":         complete.PredictAnything,
+			"-keep-ineligible":         complete.PredictNothing,
+			"-m":                       complete.PredictNothing,
+			"-meta":                    complete.PredictNothing,
+			"-self":                    complete.PredictNothing,
+			"-yes":                     complete.PredictNothing,
 		})
 }
 
@@ -131,8 +163,9 @@ func (c *NodeDrainCommand) Name() string { return "node-drain" }
 func (c *NodeDrainCommand) Run(args []string) int {
 	var enable, disable, detach, force,
 		noDeadline, ignoreSystem, keepIneligible,
-		self, autoYes, monitor bool
-	var deadline, message string
+		self, autoYes, monitor, orderByPriority bool
+	var deadline, message, jobOrder, windowCron, windowDuration, windowTimeZone string
+	var maxParallelMigrations int
 	var metaVars flaghelper.StringFlag
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
@@ -144,6 +177,12 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	flags.BoolVar(&force, "force", false, "Force immediate drain")
 	flags.BoolVar(&noDeadline, "no-deadline", false, "Drain node with no deadline")
 	flags.BoolVar(&ignoreSystem, "ignore-system", false, "Do not drain system job allocations from the node")
+	flags.StringVar(&jobOrder, "job-order", "", "Jobs to migrate first, in order")
+	flags.BoolVar(&orderByPriority, "order-by-priority", false, "Migrate higher priority jobs first")
+	flags.IntVar(&maxParallelMigrations, "max-parallel-migrations", 0, "Maximum allocations migrating across the cluster")
+	flags.StringVar(&windowCron, "window-cron", "", "Cron expression for the start of drain windows")
+	flags.StringVar(&windowDuration, "window-duration", "", "Duration of each drain window")
+	flags.StringVar(&windowTimeZone, "window-timezone", "", "Time zone of the drain window cron expression")
 	flags.BoolVar(&keepIneligible, "keep-ineligible", false, "Do not update the nodes scheduling eligibility")
 	flags.BoolVar(&self, "self", false, "")
 	flags.BoolVar(&autoYes, "yes", false, "Automatic yes to prompts.")
@@ -178,11 +217,28 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	}
 
 	// Validate a compatible set of flags were set
-	if disable && (deadline != "" || force || noDeadline || ignoreSystem) {
+	if disable && (deadline != "" || force || noDeadline || ignoreSystem ||
+		jobOrder != "" || orderByPriority || maxParallelMigrations != 0 ||
+		windowCron != "" || windowDuration != "" || windowTimeZone != "") {
 		c.Ui.Error("-disable can't be combined with flags configuring drain strategy")
 		c.Ui.Error(commandErrorText(c))
 		return 1
 	}
+	if maxParallelMigrations < 0 {
+		c.Ui.Error("-max-parallel-migrations must not be negative")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if (windowCron == "") != (windowDuration == "") {
+		c.Ui.Error("-window-cron and -window-duration must be set together")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if windowTimeZone != "" && windowCron == "" {
+		c.Ui.Error("-window-timezone requires -window-cron")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
 	if deadline != "" && (force || noDeadline) {
 		c.Ui.Error("-deadline can't be combined with -force or -no-deadline")
 		c.Ui.Error(commandErrorText(c))
@@ -216,6 +272,25 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		d = defaultDrainDuration
 	}
 
+	// Parse the drain window
+	var window *api.DrainWindow
+	if windowCron != "" {
+		dur, err := time.ParseDuration(windowDuration)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse window duration %q: %v", windowDuration, err))
+			return 1
+		}
+		if dur <= 0 {
+			c.Ui.Error("A positive window duration must be given")
+			return 1
+		}
+		window = &api.DrainWindow{
+			Cron:     windowCron,
+			Duration: dur,
+			TimeZone: windowTimeZone,
+		}
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -306,8 +381,16 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	var spec *api.DrainSpec
 	if enable {
 		spec = &api.DrainSpec{
-			Deadline:         d,
-			IgnoreSystemJobs: ignoreSystem,
+			Deadline:              d,
+			IgnoreSystemJobs:      ignoreSystem,
+			OrderByPriority:       orderByPriority,
+			MaxParallelMigrations: maxParallelMigrations,
+			Window:                window,
+		}
+		for _, jobID := range strings.Split(jobOrder, ",") {
+			if jobID = strings.TrimSpace(jobID); jobID != "" {
+				spec.JobOrder = append(spec.JobOrder, jobID)
+			}
 		}
 	}
 
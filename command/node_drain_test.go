@@ -96,6 +96,52 @@ func TestNodeDrainCommand_Detach(t *testing.T) {
 	require.NotNil(node.DrainStrategy)
 }
 
+func TestNodeDrainCommand_Window(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+	server, client, url := testServer(t, true, func(c *agent.Config) {
+		c.NodeName = "drain_window_node"
+	})
+	defer server.Shutdown()
+
+	// Wait for a node to appear
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		nodeID = nodes[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Use a window that is not open now
+	start := time.Now().UTC().Add(2 * time.Hour)
+	cron := fmt.Sprintf("%d %d * * *", start.Minute(), start.Hour())
+
+	ui := cli.NewMockUi()
+	cmd := &NodeDrainCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-self", "-enable", "-detach",
+		"-job-order=db, web", "-order-by-priority", "-max-parallel-migrations=2",
+		"-window-cron=" + cron, "-window-duration=30m"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	node, _, err := client.Nodes().Info(nodeID, nil)
+	require.Nil(err)
+	require.NotNil(node.DrainStrategy)
+	require.Equal([]string{"db", "web"}, node.DrainStrategy.JobOrder)
+	require.True(node.DrainStrategy.OrderByPriority)
+	require.Equal(2, node.DrainStrategy.MaxParallelMigrations)
+	require.Equal(&api.DrainWindow{Cron: cron, Duration: 30 * time.Minute}, node.DrainStrategy.Window)
+	require.True(node.DrainStrategy.WindowStart.After(time.Now()))
+	require.Contains(formatDrain(node), "waiting for window")
+}
+
 func TestNodeDrainCommand_Monitor(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -370,7 +416,9 @@ func TestNodeDrainCommand_Fails(t *testing.T) {
 	ui.ErrorWriter.Reset()
 
 	// Fail on disable being used with drain strategy flags
-	for _, flag := range []string{"-force", "-no-deadline", "-ignore-system"} {
+	for _, flag := range []string{"-force", "-no-deadline", "-ignore-system",
+		"-job-order=web", "-order-by-priority", "-max-parallel-migrations=2",
+		"-window-cron=@daily", "-window-duration=1h", "-window-timezone=UTC"} {
 		if code := cmd.Run([]string{"-address=" + url, "-disable", flag, "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
 			t.Fatalf("expected exit 1, got: %d", code)
 		}
@@ -400,6 +448,27 @@ func TestNodeDrainCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 
+	// Fail on setting a negative migration limit
+	if code := cmd.Run([]string{"-address=" + url, "-enable", "-max-parallel-migrations=-1", "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "must not be negative") {
+		t.Fatalf("got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fail on setting an incomplete drain window
+	for _, flags := range [][]string{{"-window-cron=@daily"}, {"-window-duration=1h"}, {"-window-timezone=UTC"}} {
+		args := append([]string{"-address=" + url, "-enable"}, flags...)
+		if code := cmd.Run(append(args, "12345678-abcd-efab-cdef-123456789abc")); code != 1 {
+			t.Fatalf("expected exit 1, got: %d", code)
+		}
+		if out := ui.ErrorWriter.String(); !strings.Contains(out, "-window-") {
+			t.Fatalf("got: %s", out)
+		}
+		ui.ErrorWriter.Reset()
+	}
+
 	// Fail on setting a bad deadline
 	for _, flag := range []string{"-deadline=0s", "-deadline=-1s"} {
 		if code := cmd.Run([]string{"-address=" + url, "-enable", flag, "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
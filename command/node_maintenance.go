@@ -0,0 +1,167 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type NodeMaintenanceCommand struct {
+	Meta
+}
+
+func (c *NodeMaintenanceCommand) Help() string {
+	helpText := `
+Usage: nomad node maintenance <subcommand> [options] [args]
+
+  This command groups subcommands for rolling node maintenance. A maintenance
+  plan selects a set of nodes, which the leader drains batch by batch. Once
+  the maintenance of a drained node is done, the node is marked eligible for
+  scheduling again before the next batch starts.
+
+  Start a maintenance plan for the nodes of a node class, two at a time:
+
+      $ nomad node maintenance run -node-class=web -concurrency=2
+
+  View the progress of maintenance plans:
+
+      $ nomad node maintenance status [<plan-id>]
+
+  Signal that the maintenance of a drained node is done:
+
+      $ nomad node maintenance done <plan-id> <node-id>
+
+  Cancel a maintenance plan:
+
+      $ nomad node maintenance cancel <plan-id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMaintenanceCommand) Synopsis() string {
+	return "Interact with node maintenance plans"
+}
+
+func (c *NodeMaintenanceCommand) Name() string { return "node maintenance" }
+
+func (c *NodeMaintenanceCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// MaintenancePlanPredictor returns a maintenance plan ID predictor.
+func MaintenancePlanPredictor(factory ApiClientFactory) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		plans, _, err := client.NodeMaintenance().PrefixList(sanitizeUUIDPrefix(a.Last), nil)
+		if err != nil {
+			return []string{}
+		}
+
+		ids := make([]string, len(plans))
+		for i, plan := range plans {
+			ids[i] = plan.ID
+		}
+		return ids
+	})
+}
+
+// lookupMaintenancePlan returns the maintenance plan whose ID matches the
+// given prefix.
+func lookupMaintenancePlan(client *api.Client, prefix string) (*api.MaintenancePlan, error) {
+	if len(prefix) == 1 {
+		return nil, fmt.Errorf("Identifier must contain at least two characters.")
+	}
+
+	plans, _, err := client.NodeMaintenance().PrefixList(sanitizeUUIDPrefix(prefix), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying maintenance plans: %s", err)
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("No maintenance plan(s) with prefix or id %q found", prefix)
+	}
+	if len(plans) > 1 {
+		return nil, fmt.Errorf("Prefix matched multiple maintenance plans\n\n%s",
+			formatMaintenancePlanList(plans, 8))
+	}
+
+	plan, _, err := client.NodeMaintenance().Info(plans[0].ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying maintenance plan: %s", err)
+	}
+	return plan, nil
+}
+
+// formatMaintenancePlanList formats a list of maintenance plans.
+func formatMaintenancePlanList(plans []*api.MaintenancePlanListStub, length int) string {
+	if len(plans) == 0 {
+		return "No maintenance plans found"
+	}
+
+	// Show the most recent plans first
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreateIndex > plans[j].CreateIndex })
+
+	rows := make([]string, len(plans)+1)
+	rows[0] = "ID|Name|Status|Nodes Complete|Created"
+	for i, plan := range plans {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%d/%d|%s",
+			limit(plan.ID, length),
+			plan.Name,
+			plan.Status,
+			plan.NodesComplete,
+			plan.NodesTotal,
+			formatUnixNanoTime(plan.CreateTime))
+	}
+	return formatList(rows)
+}
+
+// formatMaintenancePlan formats a single maintenance plan.
+func formatMaintenancePlan(plan *api.MaintenancePlan, length int) string {
+	completeOn := plan.CompleteOn
+	if completeOn == "" {
+		completeOn = api.MaintenanceCompleteOnSignal
+	}
+
+	basic := []string{
+		fmt.Sprintf("ID|%s", limit(plan.ID, length)),
+		fmt.Sprintf("Name|%s", plan.Name),
+		fmt.Sprintf("Status|%s", plan.Status),
+		fmt.Sprintf("Description|%s", plan.StatusDescription),
+		fmt.Sprintf("Concurrency|%d", plan.Concurrency),
+		fmt.Sprintf("Complete On|%s", completeOn),
+		fmt.Sprintf("Created|%s", formatUnixNanoTime(plan.CreateTime)),
+		fmt.Sprintf("Modified|%s", formatUnixNanoTime(plan.ModifyTime)),
+	}
+	if gate := plan.Gate; gate != nil {
+		basic = append(basic,
+			fmt.Sprintf("Gate Delay|%s", gate.Delay),
+			fmt.Sprintf("Gate Healthy Allocations|%v", gate.HealthyAllocs))
+	}
+	out := formatKV(basic)
+
+	out += "\n\n[bold]Nodes[reset]\n"
+	if len(plan.Nodes) == 0 {
+		return out + "No nodes"
+	}
+
+	rows := make([]string, len(plan.Nodes)+1)
+	rows[0] = "Node ID|Batch|Status|Description"
+	for i, node := range plan.Nodes {
+		rows[i+1] = fmt.Sprintf("%s|%d|%s|%s",
+			limit(node.NodeID, length),
+			node.Batch+1,
+			node.Status,
+			node.StatusDescription)
+	}
+	return out + formatList(rows)
+}
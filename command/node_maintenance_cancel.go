@@ -0,0 +1,83 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeMaintenanceCancelCommand struct {
+	Meta
+}
+
+func (c *NodeMaintenanceCancelCommand) Help() string {
+	helpText := `
+Usage: nomad node maintenance cancel [options] <plan-id>
+
+  Cancel is used to stop a running maintenance plan. No more nodes are drained
+  once the plan is cancelled. Nodes that are being drained or waiting for
+  their maintenance are left as they are and must be made eligible again with
+  the 'nomad node eligibility' command.
+
+  If ACLs are enabled, this command requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMaintenanceCancelCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *NodeMaintenanceCancelCommand) AutocompleteArgs() complete.Predictor {
+	return MaintenancePlanPredictor(c.Meta.Client)
+}
+
+func (c *NodeMaintenanceCancelCommand) Synopsis() string {
+	return "Cancel a running maintenance plan"
+}
+
+func (c *NodeMaintenanceCancelCommand) Name() string { return "node maintenance cancel" }
+
+func (c *NodeMaintenanceCancelCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <plan-id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	plan, err := lookupMaintenancePlan(client, args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if _, err := client.NodeMaintenance().Cancel(plan.ID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error cancelling maintenance plan: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Cancelled maintenance plan %q", plan.ID))
+	return 0
+}
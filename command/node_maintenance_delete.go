@@ -0,0 +1,81 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeMaintenanceDeleteCommand struct {
+	Meta
+}
+
+func (c *NodeMaintenanceDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad node maintenance delete [options] <plan-id>
+
+  Delete is used to remove a maintenance plan that is no longer running.
+  Running plans must be cancelled before they can be deleted.
+
+  If ACLs are enabled, this command requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMaintenanceDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *NodeMaintenanceDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return MaintenancePlanPredictor(c.Meta.Client)
+}
+
+func (c *NodeMaintenanceDeleteCommand) Synopsis() string {
+	return "Delete a maintenance plan"
+}
+
+func (c *NodeMaintenanceDeleteCommand) Name() string { return "node maintenance delete" }
+
+func (c *NodeMaintenanceDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <plan-id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	plan, err := lookupMaintenancePlan(client, args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if _, err := client.NodeMaintenance().Delete(plan.ID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting maintenance plan: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted maintenance plan %q!", plan.ID))
+	return 0
+}
@@ -0,0 +1,108 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeMaintenanceDoneCommand struct {
+	Meta
+}
+
+func (c *NodeMaintenanceDoneCommand) Help() string {
+	helpText := `
+Usage: nomad node maintenance done [options] <plan-id> <node-id>...
+
+  Done is used to signal that the maintenance of one or more nodes of a
+  maintenance plan is done. Once the drain of a node completed, the leader
+  marks it eligible for scheduling again. Only nodes that are being drained or
+  waiting for their maintenance can be marked done.
+
+  If ACLs are enabled, this command requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMaintenanceDoneCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *NodeMaintenanceDoneCommand) AutocompleteArgs() complete.Predictor {
+	return MaintenancePlanPredictor(c.Meta.Client)
+}
+
+func (c *NodeMaintenanceDoneCommand) Synopsis() string {
+	return "Signal that the maintenance of nodes is done"
+}
+
+func (c *NodeMaintenanceDoneCommand) Name() string { return "node maintenance done" }
+
+func (c *NodeMaintenanceDoneCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a plan and at least one node
+	args = flags.Args()
+	if l := len(args); l < 2 {
+		c.Ui.Error("This command takes at least two arguments: <plan-id> <node-id>...")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	plan, err := lookupMaintenancePlan(client, args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Resolve the node ID prefixes against the nodes of the plan
+	nodeIDs := make([]string, 0, len(args)-1)
+	for _, prefix := range args[1:] {
+		prefix = sanitizeUUIDPrefix(prefix)
+
+		var matches []string
+		for _, node := range plan.Nodes {
+			if strings.HasPrefix(node.NodeID, prefix) {
+				matches = append(matches, node.NodeID)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			c.Ui.Error(fmt.Sprintf("No node with prefix or id %q in maintenance plan %q", prefix, plan.ID))
+			return 1
+		case 1:
+			nodeIDs = append(nodeIDs, matches[0])
+		default:
+			c.Ui.Error(fmt.Sprintf("Prefix %q matched multiple nodes of maintenance plan %q", prefix, plan.ID))
+			return 1
+		}
+	}
+
+	if _, err := client.NodeMaintenance().NodeDone(plan.ID, nodeIDs, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error marking nodes done: %s", err))
+		return 1
+	}
+
+	for _, nodeID := range nodeIDs {
+		c.Ui.Output(fmt.Sprintf("Marked maintenance of node %q done", nodeID))
+	}
+	return 0
+}
@@ -0,0 +1,224 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type NodeMaintenanceRunCommand struct {
+	Meta
+}
+
+func (c *NodeMaintenanceRunCommand) Help() string {
+	helpText := `
+Usage: nomad node maintenance run [options]
+
+  Run is used to start a rolling maintenance of the nodes matching the given
+  selector. The nodes are split in batches of the given concurrency. The
+  leader drains the nodes of a batch and waits for their maintenance to be
+  done before marking them eligible for scheduling again and moving on to the
+  next batch.
+
+  The maintenance of a node is done once an operator signals it with the
+  'nomad node maintenance done' command or, if -complete-on=reregister is set,
+  once the node registers again after its drain completed.
+
+  If ACLs are enabled, this command requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Run Options:
+
+  -name <name>
+    Name of the maintenance plan.
+
+  -node-class <class>
+    Only select the nodes of the given node class.
+
+  -datacenter <datacenter>
+    Only select the nodes of the given datacenter.
+
+  -meta <key>=<value>
+    Only select the nodes with the given node metadata. Can be used multiple
+    times.
+
+  -filter <expression>
+    Only select the nodes matching the given filter expression.
+
+  -concurrency <count>
+    Number of nodes drained at the same time. Defaults to 1.
+
+  -complete-on <signal|reregister>
+    How the maintenance of a drained node is considered done. Defaults to
+    "signal".
+
+  -gate-delay <duration>
+    Time to wait after a batch completes before the next batch starts.
+
+  -gate-healthy-allocs
+    Wait for the allocations migrated off the nodes of the previous batches to
+    be healthy before starting the next batch.
+
+  -deadline <duration>
+    Set the deadline by which all allocations must be moved off each node.
+    Remaining allocations after the deadline are forced removed from the node.
+    If unspecified, a default deadline of one hour is applied.
+
+  -no-deadline
+    No deadline allows the allocations to drain off the nodes without being
+    force stopped after a certain deadline.
+
+  -ignore-system
+    Ignore system allows the drain to complete without stopping system job
+    allocations. By default system jobs are stopped last.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMaintenanceRunCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-name":                complete.PredictAnything,
+			"-node-class":          complete.PredictAnything,
+			"-datacenter":          complete.PredictAnything,
+			"-meta":                complete.PredictAnything,
+			"-filter":              complete.PredictAnything,
+			"-concurrency":         complete.PredictAnything,
+			"-complete-on":         complete.PredictSet(api.MaintenanceCompleteOnSignal, api.MaintenanceCompleteOnReregister),
+			"-gate-delay":          complete.PredictAnything,
+			"-gate-healthy-allocs": complete.PredictNothing,
+			"-deadline":            complete.PredictAnything,
+			"-no-deadline":         complete.PredictNothing,
+			"-ignore-system":       complete.PredictNothing,
+		})
+}
+
+func (c *NodeMaintenanceRunCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeMaintenanceRunCommand) Synopsis() string {
+	return "Start a rolling maintenance of a set of nodes"
+}
+
+func (c *NodeMaintenanceRunCommand) Name() string { return "node maintenance run" }
+
+func (c *NodeMaintenanceRunCommand) Run(args []string) int {
+	var name, nodeClass, datacenter, filter, completeOn, deadline string
+	var gateDelay time.Duration
+	var concurrency int
+	var gateHealthyAllocs, noDeadline, ignoreSystem bool
+	var metaVars flaghelper.StringFlag
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&name, "name", "", "")
+	flags.StringVar(&nodeClass, "node-class", "", "")
+	flags.StringVar(&datacenter, "datacenter", "", "")
+	flags.Var(&metaVars, "meta", "")
+	flags.StringVar(&filter, "filter", "", "")
+	flags.IntVar(&concurrency, "concurrency", 1, "")
+	flags.StringVar(&completeOn, "complete-on", api.MaintenanceCompleteOnSignal, "")
+	flags.DurationVar(&gateDelay, "gate-delay", 0, "")
+	flags.BoolVar(&gateHealthyAllocs, "gate-healthy-allocs", false, "")
+	flags.StringVar(&deadline, "deadline", "", "")
+	flags.BoolVar(&noDeadline, "no-deadline", false, "")
+	flags.BoolVar(&ignoreSystem, "ignore-system", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if concurrency < 1 {
+		c.Ui.Error("-concurrency must be at least 1")
+		return 1
+	}
+
+	if deadline != "" && noDeadline {
+		c.Ui.Error("-deadline can't be combined with -no-deadline")
+		return 1
+	}
+
+	// Parse the duration
+	d := defaultDrainDuration
+	if noDeadline {
+		d = 0
+	} else if deadline != "" {
+		dur, err := time.ParseDuration(deadline)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse deadline %q: %v", deadline, err))
+			return 1
+		}
+		if dur <= 0 {
+			c.Ui.Error("A positive drain duration must be given")
+			return 1
+		}
+		d = dur
+	}
+
+	plan := &api.MaintenancePlan{
+		Name: name,
+		Selector: &api.MaintenanceSelector{
+			NodeClass:  nodeClass,
+			Datacenter: datacenter,
+			Filter:     filter,
+		},
+		Concurrency: concurrency,
+		CompleteOn:  completeOn,
+		DrainSpec: &api.DrainSpec{
+			Deadline:         d,
+			IgnoreSystemJobs: ignoreSystem,
+		},
+	}
+	for _, m := range metaVars {
+		if len(m) == 0 {
+			continue
+		}
+		if plan.Selector.Meta == nil {
+			plan.Selector.Meta = make(map[string]string)
+		}
+		kv := strings.SplitN(m, "=", 2)
+		if len(kv) == 2 {
+			plan.Selector.Meta[kv[0]] = kv[1]
+		} else {
+			plan.Selector.Meta[kv[0]] = ""
+		}
+	}
+	if gateDelay != 0 || gateHealthyAllocs {
+		plan.Gate = &api.MaintenanceGate{
+			Delay:         gateDelay,
+			HealthyAllocs: gateHealthyAllocs,
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.NodeMaintenance().Register(plan, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error starting maintenance plan: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Started maintenance plan %q", resp.PlanID))
+	return 0
+}
@@ -0,0 +1,160 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMaintenanceRunCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodeMaintenanceRunCommand{}
+}
+
+func TestNodeMaintenanceRunCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodeMaintenanceRunCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-concurrency=0"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-concurrency must be at least 1")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-deadline=1h", "-no-deadline"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-deadline can't be combined with -no-deadline")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error starting maintenance plan")
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodeMaintenanceRunCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for a node to appear
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		nodeID = nodes[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	ui := cli.NewMockUi()
+	cmd := &NodeMaintenanceRunCommand{Meta: Meta{Ui: ui}}
+
+	// No node matches the selector
+	code := cmd.Run([]string{"-address=" + url, "-node-class=unknown"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "no nodes match the maintenance plan selector")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-name=upgrade", "-no-deadline", "-gate-delay=1m"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Started maintenance plan")
+
+	plans, _, err := client.NodeMaintenance().List(nil)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	planID := plans[0].ID
+
+	plan, _, err := client.NodeMaintenance().Info(planID, nil)
+	require.NoError(t, err)
+	require.Equal(t, "upgrade", plan.Name)
+	require.Zero(t, plan.DrainSpec.Deadline)
+	require.Equal(t, &api.MaintenanceGate{Delay: time.Minute}, plan.Gate)
+	require.Len(t, plan.Nodes, 1)
+	require.Equal(t, nodeID, plan.Nodes[0].NodeID)
+
+	// Check the status output
+	ui = cli.NewMockUi()
+	statusCmd := &NodeMaintenanceStatusCommand{Meta: Meta{Ui: ui}}
+	code = statusCmd.Run([]string{"-address=" + url})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "upgrade")
+
+	ui = cli.NewMockUi()
+	statusCmd = &NodeMaintenanceStatusCommand{Meta: Meta{Ui: ui}}
+	code = statusCmd.Run([]string{"-address=" + url, "-verbose", planID[:8]})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, planID)
+	require.Contains(t, out, nodeID)
+
+	ui = cli.NewMockUi()
+	statusCmd = &NodeMaintenanceStatusCommand{Meta: Meta{Ui: ui}}
+	code = statusCmd.Run([]string{"-address=" + url, "-t", "{{ .Name }}", planID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Equal(t, "upgrade", strings.TrimSpace(ui.OutputWriter.String()))
+
+	// Running plans can't be deleted
+	ui = cli.NewMockUi()
+	deleteCmd := &NodeMaintenanceDeleteCommand{Meta: Meta{Ui: ui}}
+	code = deleteCmd.Run([]string{"-address=" + url, planID})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "is running and can not be deleted")
+
+	// Cancel and delete the plan
+	ui = cli.NewMockUi()
+	cancelCmd := &NodeMaintenanceCancelCommand{Meta: Meta{Ui: ui}}
+	code = cancelCmd.Run([]string{"-address=" + url, planID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Cancelled maintenance plan")
+
+	ui = cli.NewMockUi()
+	deleteCmd = &NodeMaintenanceDeleteCommand{Meta: Meta{Ui: ui}}
+	code = deleteCmd.Run([]string{"-address=" + url, planID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Successfully deleted maintenance plan")
+
+	plans, _, err = client.NodeMaintenance().List(nil)
+	require.NoError(t, err)
+	require.Empty(t, plans)
+}
+
+func TestNodeMaintenanceDoneCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &NodeMaintenanceDoneCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"plan-id"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "abcd", "efgh"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "No maintenance plan(s) with prefix or id")
+}
@@ -0,0 +1,138 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeMaintenanceStatusCommand struct {
+	Meta
+}
+
+func (c *NodeMaintenanceStatusCommand) Help() string {
+	helpText := `
+Usage: nomad node maintenance status [options] [<plan-id>]
+
+  Status is used to display the progress of maintenance plans. If no plan ID
+  is given, a list of all the maintenance plans is displayed. If a plan ID is
+  given, the details of the plan and of each of its nodes are displayed.
+
+  If ACLs are enabled, this command requires a token with the 'node:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Status Options:
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the maintenance plans in a JSON format.
+
+  -t
+    Format and display the maintenance plans using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMaintenanceStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodeMaintenanceStatusCommand) AutocompleteArgs() complete.Predictor {
+	return MaintenancePlanPredictor(c.Meta.Client)
+}
+
+func (c *NodeMaintenanceStatusCommand) Synopsis() string {
+	return "Display the progress of maintenance plans"
+}
+
+func (c *NodeMaintenanceStatusCommand) Name() string { return "node maintenance status" }
+
+func (c *NodeMaintenanceStatusCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got at most one argument
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes either no arguments or one: <plan-id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if len(args) == 0 {
+		plans, _, err := client.NodeMaintenance().List(nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying maintenance plans: %s", err))
+			return 1
+		}
+
+		if json || len(tmpl) > 0 {
+			out, err := Format(json, tmpl, plans)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+
+			c.Ui.Output(out)
+			return 0
+		}
+
+		c.Ui.Output(formatMaintenancePlanList(plans, length))
+		return 0
+	}
+
+	plan, err := lookupMaintenancePlan(client, args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, plan)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatMaintenancePlan(plan, length)))
+	return 0
+}
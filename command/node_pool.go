@@ -0,0 +1,144 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type NodePoolCommand struct {
+	Meta
+}
+
+func (c *NodePoolCommand) Help() string {
+	helpText := `
+Usage: nomad node pool <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with node pools. Node pools
+  partition the client nodes of a cluster, and jobs are only placed on the
+  nodes of the node pool they target.
+
+  Create or update a node pool:
+
+      $ nomad node pool apply <path>
+
+  List node pools:
+
+      $ nomad node pool list
+
+  View the details of a node pool:
+
+      $ nomad node pool info <name>
+
+  Delete a node pool:
+
+      $ nomad node pool delete <name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolCommand) Synopsis() string {
+	return "Interact with node pools"
+}
+
+func (c *NodePoolCommand) Name() string { return "node pool" }
+
+func (c *NodePoolCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// NodePoolPredictor returns a node pool predictor that can optionally filter
+// specific node pools.
+func NodePoolPredictor(factory ApiClientFactory, filter map[string]struct{}) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		pools, _, err := client.NodePools().PrefixList(a.Last, nil)
+		if err != nil {
+			return []string{}
+		}
+
+		var names []string
+		for _, pool := range pools {
+			if _, ok := filter[pool.Name]; !ok {
+				names = append(names, pool.Name)
+			}
+		}
+		return names
+	})
+}
+
+// formatNodePoolList formats a list of node pools.
+func formatNodePoolList(pools []*api.NodePool) string {
+	if len(pools) == 0 {
+		return "No node pools found"
+	}
+
+	// Sort the output by node pool name
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+
+	rows := make([]string, len(pools)+1)
+	rows[0] = "Name|Description"
+	for i, pool := range pools {
+		rows[i+1] = fmt.Sprintf("%s|%s",
+			pool.Name,
+			pool.Description)
+	}
+	return formatList(rows)
+}
+
+// formatNodePool formats a single node pool.
+func formatNodePool(pool *api.NodePool) string {
+	basic := []string{
+		fmt.Sprintf("Name|%s", pool.Name),
+		fmt.Sprintf("Description|%s", pool.Description),
+	}
+	out := formatKV(basic)
+
+	if len(pool.Meta) > 0 {
+		out += "\n\n[bold]Metadata[reset]\n" + formatNodePoolMeta(pool.Meta)
+	}
+
+	if sc := pool.SchedulerConfiguration; sc != nil {
+		algorithm := "<none>"
+		if sc.SchedulerAlgorithm != "" {
+			algorithm = string(sc.SchedulerAlgorithm)
+		}
+		memOversub := "<none>"
+		if sc.MemoryOversubscriptionEnabled != nil {
+			memOversub = fmt.Sprintf("%v", *sc.MemoryOversubscriptionEnabled)
+		}
+		schedConfig := []string{
+			fmt.Sprintf("Scheduler Algorithm|%s", algorithm),
+			fmt.Sprintf("Memory Oversubscription Enabled|%s", memOversub),
+		}
+		out += "\n\n[bold]Scheduler Configuration[reset]\n" + formatKV(schedConfig)
+	}
+
+	return out
+}
+
+// formatNodePoolMeta formats the metadata of a node pool sorted by key.
+func formatNodePoolMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := make([]string, len(keys))
+	for i, k := range keys {
+		rows[i] = fmt.Sprintf("%s|%s", k, meta[k])
+	}
+	return formatKV(rows)
+}
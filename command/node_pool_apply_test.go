@@ -0,0 +1,161 @@
+package command
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolApplyCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodePoolApplyCommand{}
+}
+
+func TestNodePoolApplyCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing file
+	code = cmd.Run([]string{"-address=nope", "/does/not/exist.hcl"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Failed to read file")
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePoolApplyCommand_Good(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	spec := `
+node_pool "prod" {
+  description = "Production nodes"
+
+  meta {
+    owner = "ops"
+  }
+
+  scheduler_config {
+    scheduler_algorithm             = "spread"
+    memory_oversubscription_enabled = true
+  }
+}
+`
+	file := filepath.Join(t.TempDir(), "pool.hcl")
+	require.NoError(t, ioutil.WriteFile(file, []byte(spec), 0600))
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, file})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully applied node pool "prod"!`)
+
+	pool, _, err := client.NodePools().Info("prod", nil)
+	require.NoError(t, err)
+	require.Equal(t, "Production nodes", pool.Description)
+	require.Equal(t, map[string]string{"owner": "ops"}, pool.Meta)
+	require.NotNil(t, pool.SchedulerConfiguration)
+	require.Equal(t, api.SchedulerAlgorithmSpread, pool.SchedulerConfiguration.SchedulerAlgorithm)
+	require.True(t, *pool.SchedulerConfiguration.MemoryOversubscriptionEnabled)
+}
+
+func TestNodePoolApplyCommand_JSON(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	spec := `{"Name": "dev", "Description": "Development nodes"}`
+	file := filepath.Join(t.TempDir(), "pool.json")
+	require.NoError(t, ioutil.WriteFile(file, []byte(spec), 0600))
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-json", file})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	pool, _, err := client.NodePools().Info("dev", nil)
+	require.NoError(t, err)
+	require.Equal(t, "Development nodes", pool.Description)
+}
+
+func TestNodePoolApplyCommand_ParseSpec(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name        string
+		input       string
+		expected    *api.NodePool
+		expectedErr string
+	}{
+		{
+			name:  "minimal",
+			input: `node_pool "prod" {}`,
+			expected: &api.NodePool{
+				Name: "prod",
+			},
+		},
+		{
+			name: "full",
+			input: `
+node_pool "prod" {
+  description = "Production nodes"
+  meta {
+    owner = "ops"
+  }
+  scheduler_config {
+    scheduler_algorithm = "binpack"
+  }
+}`,
+			expected: &api.NodePool{
+				Name:        "prod",
+				Description: "Production nodes",
+				Meta:        map[string]string{"owner": "ops"},
+				SchedulerConfiguration: &api.NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm: api.SchedulerAlgorithmBinpack,
+				},
+			},
+		},
+		{
+			name:        "missing block",
+			input:       `description = "foo"`,
+			expectedErr: "expected exactly one node_pool block",
+		},
+		{
+			name:        "missing label",
+			input:       `node_pool { description = "foo" }`,
+			expectedErr: "exactly one label",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool, err := parseNodePoolSpec([]byte(tc.input))
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.True(t, strings.Contains(err.Error(), tc.expectedErr), err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, pool)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolDeleteCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodePoolDeleteCommand{}
+}
+
+func TestNodePoolDeleteCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "foo"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error deleting node pool")
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePoolDeleteCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{Name: "prod"}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "prod"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `Successfully deleted node pool "prod"!`)
+
+	pools, _, err := client.NodePools().List(nil)
+	require.NoError(t, err)
+	require.Len(t, pools, 2)
+
+	// Built-in node pools can't be deleted
+	code = cmd.Run([]string{"-address=" + url, api.NodePoolDefault})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "not allowed")
+}
+
+func TestNodePoolDeleteCommand_AutocompleteArgs(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{Name: "prod"}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	args := complete.Args{Last: ""}
+	predictor := cmd.AutocompleteArgs()
+
+	res := predictor.Predict(args)
+	require.Equal(t, []string{"prod"}, res)
+}
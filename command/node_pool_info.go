@@ -0,0 +1,103 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodePoolInfoCommand struct {
+	Meta
+}
+
+func (c *NodePoolInfoCommand) Help() string {
+	helpText := `
+Usage: nomad node pool info [options] <name>
+
+  Info is used to fetch information on an existing node pool.
+
+  If ACLs are enabled, this command requires a token with the 'node:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Info Options:
+
+  -json
+    Output the node pool in a JSON format.
+
+  -t
+    Format and display the node pool using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *NodePoolInfoCommand) AutocompleteArgs() complete.Predictor {
+	return NodePoolPredictor(c.Meta.Client, nil)
+}
+
+func (c *NodePoolInfoCommand) Synopsis() string {
+	return "Fetch information on an existing node pool"
+}
+
+func (c *NodePoolInfoCommand) Name() string { return "node pool info" }
+
+func (c *NodePoolInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	pool, _, err := client.NodePools().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving node pool: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, pool)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatNodePool(pool)))
+	return 0
+}
@@ -0,0 +1,70 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolInfoCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodePoolInfoCommand{}
+}
+
+func TestNodePoolInfoCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodePoolInfoCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "foo"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error retrieving node pool")
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePoolInfoCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{
+		Name:        "prod",
+		Description: "Production nodes",
+		Meta:        map[string]string{"owner": "ops"},
+		SchedulerConfiguration: &api.NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            api.SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolInfoCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "prod"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Production nodes")
+	require.Contains(t, out, "owner = ops")
+	require.Contains(t, out, "Scheduler Algorithm             = spread")
+	require.Contains(t, out, "Memory Oversubscription Enabled = true")
+	ui.OutputWriter.Reset()
+
+	// Unknown node pool
+	code = cmd.Run([]string{"-address=" + url, "unknown"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Node pool not found")
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolListCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &NodePoolListCommand{}
+}
+
+func TestNodePoolListCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &NodePoolListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error retrieving node pools")
+	ui.ErrorWriter.Reset()
+}
+
+func TestNodePoolListCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{
+		Name:        "prod",
+		Description: "Production nodes",
+	}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &NodePoolListCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Name")
+	require.Contains(t, out, api.NodePoolAll)
+	require.Contains(t, out, api.NodePoolDefault)
+	require.Contains(t, out, "Production nodes")
+	ui.OutputWriter.Reset()
+
+	// List json
+	code = cmd.Run([]string{"-address=" + url, "-json"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `"Name": "prod"`)
+}
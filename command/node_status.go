@@ -453,6 +453,9 @@ func formatDrain(n *api.Node) string {
 		if n.DrainStrategy.IgnoreSystemJobs {
 			b.WriteString("; ignoring system jobs")
 		}
+		if n.DrainStrategy.WindowStart.After(time.Now()) {
+			fmt.Fprintf(b, "; waiting for window at %s", formatTime(n.DrainStrategy.WindowStart))
+		}
 		return b.String()
 	}
 
@@ -479,6 +482,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		fmt.Sprintf("ID|%s", node.ID),
 		fmt.Sprintf("Name|%s", node.Name),
 		fmt.Sprintf("Class|%s", node.NodeClass),
+		fmt.Sprintf("Node Pool|%s", node.NodePool),
 		fmt.Sprintf("DC|%s", node.Datacenter),
 		fmt.Sprintf("Drain|%v", formatDrain(node)),
 		fmt.Sprintf("Eligibility|%s", node.SchedulingEligibility),
@@ -533,6 +537,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		c.outputNodeNetworkInfo(node)
 		c.outputNodeCSIVolumeInfo(client, node, runningAllocs)
 		c.outputNodeDriverInfo(node)
+		c.outputNodeArtifactCache(client, node)
 	}
 
 	// Emit node events
@@ -657,6 +662,34 @@ func (c *NodeStatusCommand) outputNodeNetworkInfo(node *api.Node) {
 	}
 }
 
+func (c *NodeStatusCommand) outputNodeArtifactCache(client *api.Client, node *api.Node) {
+
+	// Ignore an error, all we're going to do is omit the cache
+	cache, err := client.Nodes().ArtifactCache(node.ID, nil)
+	if err != nil || cache.MaxSize == 0 {
+		return
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Artifact Cache[reset]"))
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Size|%s", humanize.Bytes(uint64(cache.Size))),
+		fmt.Sprintf("Max Size|%s", humanize.Bytes(uint64(cache.MaxSize))),
+	}))
+
+	if len(cache.Entries) == 0 {
+		return
+	}
+
+	output := make([]string, 0, len(cache.Entries)+1)
+	output = append(output, "Source|Checksum|Size|Last Used|In Use")
+	for _, e := range cache.Entries {
+		output = append(output, fmt.Sprintf("%s|%s|%s|%s|%t",
+			e.Source, e.Checksum, humanize.Bytes(uint64(e.Size)), formatTime(e.LastUsed), e.InUse))
+	}
+	c.Ui.Output("")
+	c.Ui.Output(formatList(output))
+}
+
 func (c *NodeStatusCommand) outputNodeCSIVolumeInfo(client *api.Client, node *api.Node, runningAllocs []*api.Allocation) {
 
 	// Duplicate nodeCSIVolumeNames to sort by name but also index volume names to ids
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorSchedulerCommand struct {
+	Meta
+}
+
+func (f *OperatorSchedulerCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler <subcommand> [options]
+
+  This command groups subcommands for interacting with the Nomad schedulers.
+
+  Simulate losing the nodes of datacenter "dc2":
+
+      $ nomad operator scheduler simulate -remove-datacenter=dc2
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *OperatorSchedulerCommand) Synopsis() string {
+	return "Interact with the Nomad schedulers"
+}
+
+func (f *OperatorSchedulerCommand) Name() string { return "operator scheduler" }
+
+func (f *OperatorSchedulerCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
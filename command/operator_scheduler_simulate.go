@@ -0,0 +1,454 @@
+package command
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerSimulateCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *OperatorSchedulerSimulateCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options]
+
+  Runs the schedulers offline against a copy of the cluster state after
+  applying hypothetical changes to it, and reports the resulting placements,
+  placement failures and utilization of each datacenter. Nothing is submitted
+  to the cluster.
+
+  The state is read from a snapshot file with -snapshot, or by replaying the
+  raft logs of a Nomad data directory with -data-dir. Otherwise a snapshot of
+  the live state is retrieved from the servers, which requires a management
+  token if ACLs are enabled.
+
+  Removed nodes are marked as down so that their allocations are replaced.
+
+  To simulate losing the nodes of datacenter "dc2":
+
+    $ nomad operator scheduler simulate -remove-datacenter=dc2
+
+  To simulate registering 20 copies of a job against a snapshot file:
+
+    $ nomad operator scheduler simulate -snapshot=backup.snap \
+        -job=example.nomad.hcl -job-count=20
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Simulate Options:
+
+  -snapshot=<path>
+    Read the state from a snapshot file saved with "nomad operator snapshot save".
+
+  -data-dir=<path>
+    Read the state by replaying the raft logs of a Nomad data directory. The
+    data directory can't be in use by a running Nomad server.
+
+  -stale
+    Allow the snapshot of the live state to be retrieved from any server.
+
+  -remove-node=<node id>
+    Remove the node with the given ID or ID prefix. May be specified multiple
+    times.
+
+  -remove-datacenter=<datacenter>
+    Remove all the nodes of the datacenter. May be specified multiple times.
+
+  -job=<path>
+    Register the job of the given jobspec file. May be specified multiple times.
+
+  -job-count=<count>
+    Register the given number of copies of each job. Copies are suffixed with
+    their index. Defaults to 1.
+
+  -scheduler-algorithm=<binpack|spread>
+    Simulate with the given scheduler algorithm.
+
+  -memory-oversubscription=<true|false>
+    Simulate with memory oversubscription enabled or disabled.
+
+  -preempt-batch-scheduler=<true|false>
+  -preempt-service-scheduler=<true|false>
+  -preempt-sysbatch-scheduler=<true|false>
+  -preempt-system-scheduler=<true|false>
+    Simulate with preemption enabled or disabled for the scheduler.
+
+  -json
+    Output the simulation result in its JSON format.
+
+  -verbose
+    Display the details of the placement failures.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-snapshot":                   complete.PredictFiles("*"),
+			"-data-dir":                   complete.PredictDirs("*"),
+			"-stale":                      complete.PredictNothing,
+			"-remove-node":                complete.PredictAnything,
+			"-remove-datacenter":          complete.PredictAnything,
+			"-job":                        complete.PredictFiles("*"),
+			"-job-count":                  complete.PredictAnything,
+			"-scheduler-algorithm":        complete.PredictSet(string(structs.SchedulerAlgorithmBinpack), string(structs.SchedulerAlgorithmSpread)),
+			"-memory-oversubscription":    complete.PredictSet("true", "false"),
+			"-preempt-batch-scheduler":    complete.PredictSet("true", "false"),
+			"-preempt-service-scheduler":  complete.PredictSet("true", "false"),
+			"-preempt-sysbatch-scheduler": complete.PredictSet("true", "false"),
+			"-preempt-system-scheduler":   complete.PredictSet("true", "false"),
+			"-json":                       complete.PredictNothing,
+			"-verbose":                    complete.PredictNothing,
+		})
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSchedulerSimulateCommand) Synopsis() string {
+	return "Simulate scheduling against a copy of the cluster state"
+}
+
+func (c *OperatorSchedulerSimulateCommand) Name() string { return "operator scheduler simulate" }
+
+func (c *OperatorSchedulerSimulateCommand) Run(args []string) int {
+	var snapshotPath, dataDir, algorithm string
+	var stale, jsonOutput, verbose bool
+	var jobCount int
+	var removeNodes, removeDCs, jobPaths flaghelper.StringFlag
+	var memOversub, preemptBatch, preemptService, preemptSysBatch, preemptSystem flaghelper.BoolValue
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&snapshotPath, "snapshot", "", "")
+	flags.StringVar(&dataDir, "data-dir", "", "")
+	flags.BoolVar(&stale, "stale", false, "")
+	flags.Var(&removeNodes, "remove-node", "")
+	flags.Var(&removeDCs, "remove-datacenter", "")
+	flags.Var(&jobPaths, "job", "")
+	flags.IntVar(&jobCount, "job-count", 1, "")
+	flags.StringVar(&algorithm, "scheduler-algorithm", "", "")
+	flags.Var(&memOversub, "memory-oversubscription", "")
+	flags.Var(&preemptBatch, "preempt-batch-scheduler", "")
+	flags.Var(&preemptService, "preempt-service-scheduler", "")
+	flags.Var(&preemptSysBatch, "preempt-sysbatch-scheduler", "")
+	flags.Var(&preemptSystem, "preempt-system-scheduler", "")
+	flags.BoolVar(&jsonOutput, "json", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if snapshotPath != "" && dataDir != "" {
+		c.Ui.Error("Only one of -snapshot and -data-dir can be set")
+		return 1
+	}
+	if jobCount < 1 {
+		c.Ui.Error("-job-count must be at least 1")
+		return 1
+	}
+	switch structs.SchedulerAlgorithm(algorithm) {
+	case "", structs.SchedulerAlgorithmBinpack, structs.SchedulerAlgorithmSpread:
+	default:
+		c.Ui.Error(fmt.Sprintf("Invalid -scheduler-algorithm %q", algorithm))
+		return 1
+	}
+
+	// Parse the jobs first, so that mistakes are reported before loading a
+	// potentially large state
+	var jobs []*structs.Job
+	for _, path := range jobPaths {
+		apiJob, err := c.JobGetter.ApiJob(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+			return 1
+		}
+		job := agent.ApiJobToStructJob(apiJob)
+		job.Canonicalize()
+		if err := job.Validate(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Job %q is invalid: %s", job.ID, err))
+			return 1
+		}
+		jobs = append(jobs, simulationJobCopies(job, jobCount)...)
+	}
+
+	store, source, err := c.loadState(snapshotPath, dataDir, stale)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	sim := &scheduler.Simulation{
+		RemoveDatacenters: removeDCs,
+		Jobs:              jobs,
+	}
+	for _, prefix := range removeNodes {
+		nodeID, err := simulationNodeID(store, prefix)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		sim.RemoveNodes = append(sim.RemoveNodes, nodeID)
+	}
+
+	configChanged := false
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "scheduler-algorithm", "memory-oversubscription", "preempt-batch-scheduler",
+			"preempt-service-scheduler", "preempt-sysbatch-scheduler", "preempt-system-scheduler":
+			configChanged = true
+		}
+	})
+	if configChanged {
+		_, config, err := store.SchedulerConfig()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading scheduler configuration: %s", err))
+			return 1
+		}
+		if config == nil {
+			config = &structs.SchedulerConfiguration{
+				SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+				PreemptionConfig:   structs.PreemptionConfig{SystemSchedulerEnabled: true},
+			}
+		}
+		copied := *config
+		config = &copied
+		if algorithm != "" {
+			config.SchedulerAlgorithm = structs.SchedulerAlgorithm(algorithm)
+		}
+		memOversub.Merge(&config.MemoryOversubscriptionEnabled)
+		preemptBatch.Merge(&config.PreemptionConfig.BatchSchedulerEnabled)
+		preemptService.Merge(&config.PreemptionConfig.ServiceSchedulerEnabled)
+		preemptSysBatch.Merge(&config.PreemptionConfig.SysBatchSchedulerEnabled)
+		preemptSystem.Merge(&config.PreemptionConfig.SystemSchedulerEnabled)
+		sim.SchedulerConfig = config
+	}
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   "simulate",
+		Level:  hclog.Warn,
+		Output: os.Stderr,
+	})
+	result, err := sim.Run(logger, store)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running simulation: %s", err))
+		return 1
+	}
+
+	if jsonOutput {
+		out, err := Format(true, "", result)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]==> Simulated against %s[reset]", source)))
+	c.Ui.Output(formatSimulationResult(result, verbose))
+	return 0
+}
+
+// loadState loads the state to simulate against, and returns a description
+// of where it was loaded from.
+func (c *OperatorSchedulerSimulateCommand) loadState(snapshotPath, dataDir string, stale bool) (*state.StateStore, string, error) {
+	switch {
+	case dataDir != "":
+		raftPath, err := raftutil.FindRaftDir(dataDir)
+		if err != nil {
+			return nil, "", err
+		}
+		fsm, err := raftutil.NewFSM(raftPath)
+		if err != nil {
+			return nil, "", err
+		}
+		defer fsm.Close()
+
+		index, _, err := fsm.ApplyAll()
+		if err != nil {
+			return nil, "", err
+		}
+		return fsm.State(), fmt.Sprintf("data directory %q at index %d", dataDir, index), nil
+
+	case snapshotPath != "":
+		f, err := os.Open(snapshotPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("Error opening snapshot file: %s", err)
+		}
+		defer f.Close()
+		return restoreSimulationState(f, fmt.Sprintf("snapshot %q", snapshotPath))
+
+	default:
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil, "", fmt.Errorf("Error initializing client: %s", err)
+		}
+		snap, err := client.Operator().Snapshot(&api.QueryOptions{AllowStale: stale})
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to get snapshot file: %v", err)
+		}
+		defer snap.Close()
+		return restoreSimulationState(snap, "live state")
+	}
+}
+
+func restoreSimulationState(r io.Reader, source string) (*state.StateStore, string, error) {
+	store, meta, err := raftutil.RestoreFromArchive(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("Failed to read archive file: %s", err)
+	}
+	return store, fmt.Sprintf("%s at index %d", source, meta.Index), nil
+}
+
+// simulationJobCopies returns count copies of the job, suffixed with their
+// index when there is more than one.
+func simulationJobCopies(job *structs.Job, count int) []*structs.Job {
+	if count == 1 {
+		return []*structs.Job{job}
+	}
+	jobs := make([]*structs.Job, 0, count)
+	for i := 0; i < count; i++ {
+		copied := job.Copy()
+		copied.ID = fmt.Sprintf("%s-%d", job.ID, i)
+		copied.Name = fmt.Sprintf("%s-%d", job.Name, i)
+		jobs = append(jobs, copied)
+	}
+	return jobs
+}
+
+// simulationNodeID resolves a node ID prefix against the state.
+func simulationNodeID(store *state.StateStore, prefix string) (string, error) {
+	iter, err := store.NodesByIDPrefix(nil, prefix)
+	if err != nil {
+		return "", fmt.Errorf("Error looking up node %q: %s", prefix, err)
+	}
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.Node).ID)
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("No node(s) with prefix %q found", prefix)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple nodes: %s", prefix, strings.Join(ids, ", "))
+	}
+}
+
+func formatSimulationResult(result *scheduler.SimulationResult, verbose bool) string {
+	var out strings.Builder
+
+	if len(result.RemovedNodes) > 0 {
+		out.WriteString(fmt.Sprintf("\nRemoved %d node(s)\n", len(result.RemovedNodes)))
+	}
+
+	out.WriteString("\nEvaluations\n")
+	if len(result.Evaluations) == 0 {
+		out.WriteString("No evaluations were triggered\n")
+	} else {
+		rows := make([]string, 1, len(result.Evaluations)+1)
+		rows[0] = "Job ID|Namespace|Type|Triggered By|Placed|Stopped|Failed"
+		for _, eval := range result.Evaluations {
+			rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%d|%d|%d",
+				eval.JobID, eval.Namespace, eval.JobType, eval.TriggeredBy,
+				sumCounts(eval.Placed), sumCounts(eval.Stopped), eval.Failed()))
+		}
+		out.WriteString(formatList(rows) + "\n")
+	}
+
+	var failures []string
+	for _, eval := range result.Evaluations {
+		if eval.Error != "" {
+			failures = append(failures, fmt.Sprintf("Job %q: %s\n", eval.JobID, eval.Error))
+		}
+		tgs := make([]string, 0, len(eval.FailedTGAllocs))
+		for tg := range eval.FailedTGAllocs {
+			tgs = append(tgs, tg)
+		}
+		sort.Strings(tgs)
+		for _, tg := range tgs {
+			metric := eval.FailedTGAllocs[tg]
+			failure := fmt.Sprintf("Job %q Task Group %q (failed to place %d allocation(s))\n",
+				eval.JobID, tg, metric.CoalescedFailures+1)
+			if verbose {
+				if apiMetric, err := simulationAPIMetric(metric); err == nil {
+					failure += strings.TrimSuffix(formatAllocMetrics(apiMetric, false, "  "), "\n") + "\n"
+				}
+			}
+			failures = append(failures, failure)
+		}
+	}
+	if len(failures) > 0 {
+		out.WriteString("\nPlacement Failures\n")
+		out.WriteString(strings.Join(failures, ""))
+	}
+
+	out.WriteString("\nUtilization\n")
+	rows := make([]string, 1, len(result.Utilization)+1)
+	rows[0] = "Datacenter|Nodes|CPU (MHz)|Memory (MiB)"
+	for _, u := range result.Utilization {
+		rows = append(rows, fmt.Sprintf("%s|%d|%s|%s", u.Datacenter, u.Nodes,
+			formatUtilization(u.CPU, u.CPUCapacity),
+			formatUtilization(u.MemoryMB, u.MemoryCapacityMB)))
+	}
+	out.WriteString(formatList(rows))
+	return out.String()
+}
+
+func sumCounts(counts map[string]int) int {
+	sum := 0
+	for _, count := range counts {
+		sum += count
+	}
+	return sum
+}
+
+func formatUtilization(used, capacity int64) string {
+	if capacity == 0 {
+		return fmt.Sprintf("%d/%d", used, capacity)
+	}
+	return fmt.Sprintf("%d/%d (%.0f%%)", used, capacity, float64(used)/float64(capacity)*100)
+}
+
+// simulationAPIMetric converts the metric of a failed placement to its API
+// counterpart, which shares its JSON encoding, to reuse its formatting.
+func simulationAPIMetric(metric *structs.AllocMetric) (*api.AllocationMetric, error) {
+	buf, err := json.Marshal(metric)
+	if err != nil {
+		return nil, err
+	}
+	var out api.AllocationMetric
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerSimulateCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorSchedulerSimulateCommand{}
+}
+
+func TestOperatorSchedulerSimulateCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	snapPath := generateSnapshotFile(t, func(srv *agent.TestAgent, _ *api.Client, _ string) {
+		store := srv.Agent.Server().State()
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	})
+
+	jobPath := filepath.Join(t.TempDir(), "example.nomad.hcl")
+	require.NoError(t, os.WriteFile(jobPath, []byte(`
+job "example" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    count = 2
+
+    task "web" {
+      driver = "exec"
+
+      config {
+        command = "/bin/date"
+      }
+
+      resources {
+        cpu    = 1000
+        memory = 256
+      }
+    }
+  }
+}
+`), 0600))
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-snapshot=" + snapPath, "-job=" + jobPath, "-job-count=2", "-verbose"})
+	require.Zero(t, code, ui.ErrorWriter.String())
+
+	// The node fits 3 of the 4 allocations
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "Simulated against snapshot")
+	require.Regexp(t, `example-0\s+default\s+service\s+job-register\s+2\s+0\s+0`, out)
+	require.Regexp(t, `example-1\s+default\s+service\s+job-register\s+1\s+0\s+1`, out)
+	require.Contains(t, out, `Job "example-1" Task Group "web" (failed to place 1 allocation(s))`)
+	require.Contains(t, out, `Dimension "cpu" exhausted on 1 nodes`)
+	require.Regexp(t, `dc1\s+1\s+3000/3900 \(77%\)\s+768/7936 \(10%\)`, out)
+
+	// Removing the node leaves no capacity
+	ui = cli.NewMockUi()
+	cmd = &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-snapshot=" + snapPath, "-remove-node=" + node.ID[:8], "-job=" + jobPath})
+	require.Zero(t, code, ui.ErrorWriter.String())
+
+	out = ui.OutputWriter.String()
+	require.Contains(t, out, "Removed 1 node(s)")
+	require.Regexp(t, `example\s+default\s+service\s+job-register\s+0\s+0\s+2`, out)
+
+	// Invalid flags are rejected
+	ui = cli.NewMockUi()
+	cmd = &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-snapshot=" + snapPath, "-data-dir=/tmp"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Only one of -snapshot and -data-dir can be set")
+
+	ui = cli.NewMockUi()
+	cmd = &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-snapshot=" + snapPath, "-remove-node=ffffffff"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), `No node(s) with prefix "ffffffff" found`)
+}
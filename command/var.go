@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (c *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  are encrypted key/value items stored by Nomad, which can be read by the
+  templates of tasks without an external secret store.
+
+  List variables:
+
+      $ nomad var list
+
+  Create or update a variable:
+
+      $ nomad var put secret/creds username=admin password=hunter2
+
+  Read a variable:
+
+      $ nomad var get secret/creds
+
+  Delete a variable:
+
+      $ nomad var purge secret/creds
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarCommand) Name() string { return "var" }
+
+func (c *VarCommand) Synopsis() string { return "Interact with variables" }
+
+func (c *VarCommand) Run(_ []string) int { return cli.RunResultHelp }
+
+// formatVariable returns the human readable representation of a variable,
+// including its items.
+func formatVariable(v *api.Variable) string {
+	out := []string{
+		fmt.Sprintf("Namespace|%s", v.Namespace),
+		fmt.Sprintf("Path|%s", v.Path),
+		fmt.Sprintf("Create Time|%s", formatUnixNanoTime(v.CreateTime)),
+		fmt.Sprintf("Modify Time|%s", formatUnixNanoTime(v.ModifyTime)),
+		fmt.Sprintf("Check Index|%d", v.ModifyIndex),
+	}
+
+	keys := make([]string, 0, len(v.Items))
+	for k := range v.Items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, fmt.Sprintf("%s|%s", k, v.Items[k]))
+	}
+
+	return formatKV(out) + "\n\n" + formatKV(items)
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarGetCommand struct {
+	Meta
+}
+
+func (c *VarGetCommand) Help() string {
+	helpText := `
+Usage: nomad var get [options] <path>
+
+  Get is used to read the variable at the given path, including its items.
+
+  If ACLs are enabled, this command requires a token with the 'read'
+  variables capability for the path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Get Options:
+
+  -item <key>
+    Output only the value of the item with the given key.
+
+  -json
+    Output the variable in JSON format.
+
+  -t
+    Format and display the variable using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarGetCommand) Synopsis() string {
+	return "Read a variable"
+}
+
+func (c *VarGetCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-item": complete.PredictAnything,
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarGetCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarGetCommand) Name() string { return "var get" }
+
+func (c *VarGetCommand) Run(args []string) int {
+	var json bool
+	var tmpl, item string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&item, "item", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	variable, _, err := client.Variables().Read(args[0], nil)
+	if err != nil {
+		if err == api.ErrVariableNotFound {
+			c.Ui.Error(fmt.Sprintf("No variable found at path %q", args[0]))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error reading variable: %s", err))
+		return 1
+	}
+
+	if item != "" {
+		value, ok := variable.Items[item]
+		if !ok {
+			c.Ui.Error(fmt.Sprintf("Variable %q has no item %q", args[0], item))
+			return 1
+		}
+		c.Ui.Output(value)
+		return 0
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, variable)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatVariable(variable))
+	return 0
+}
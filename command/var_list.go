@@ -0,0 +1,129 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarListCommand struct {
+	Meta
+}
+
+func (c *VarListCommand) Help() string {
+	helpText := `
+Usage: nomad var list [options] [<prefix>]
+
+  List is used to list the variables, optionally only those whose path begins
+  with the given prefix. The items of the variables are not returned.
+
+  If ACLs are enabled, this command requires a token with the 'list'
+  variables capability for the listed paths. Variables the token does not
+  have access to are filtered from the results.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+List Options:
+
+  -json
+    Output the variables in JSON format.
+
+  -t
+    Format and display the variables using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarListCommand) Synopsis() string {
+	return "List variables"
+}
+
+func (c *VarListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarListCommand) Name() string { return "var list" }
+
+func (c *VarListCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("This command takes at most one argument: <prefix>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var prefix string
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	list, _, err := client.Variables().PrefixList(prefix, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing variables: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, list)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(list) == 0 {
+		c.Ui.Output("No variables found")
+		return 0
+	}
+
+	c.Ui.Output(formatVariableList(list, c.Meta.namespace == api.AllNamespacesNamespace))
+	return 0
+}
+
+func formatVariableList(list []*api.VariableMetadata, withNamespace bool) string {
+	header := "Path|Last Updated"
+	if withNamespace {
+		header = "Namespace|" + header
+	}
+
+	rows := []string{header}
+	for _, v := range list {
+		row := fmt.Sprintf("%s|%s", v.Path, formatUnixNanoTime(v.ModifyTime))
+		if withNamespace {
+			row = v.Namespace + "|" + row
+		}
+		rows = append(rows, row)
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarPutCommand struct {
+	Meta
+}
+
+func (c *VarPutCommand) Help() string {
+	helpText := `
+Usage: nomad var put [options] <path> <key>=<value> [<key>=<value>...]
+
+  Put is used to create or update the variable at the given path. The items of
+  the variable are replaced by those passed as arguments.
+
+  Paths under "nomad/jobs/<job_id>" are readable by the templates of the tasks
+  of the matching job, without any further configuration.
+
+  If ACLs are enabled, this command requires a token with the 'write'
+  variables capability for the path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Put Options:
+
+  -check-index <index>
+    Only update the variable if its current modify index matches the given
+    index. An index of 0 only creates the variable if it does not exist.
+
+  -json
+    Output the written variable in JSON format.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarPutCommand) Synopsis() string {
+	return "Create or update a variable"
+}
+
+func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-check-index": complete.PredictAnything,
+			"-json":        complete.PredictNothing,
+		})
+}
+
+func (c *VarPutCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarPutCommand) Name() string { return "var put" }
+
+func (c *VarPutCommand) Run(args []string) int {
+	var json bool
+	var checkIndex int64
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.Int64Var(&checkIndex, "check-index", -1, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		c.Ui.Error("This command takes at least two arguments: <path> and <key>=<value>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	variable := api.NewVariable(args[0])
+	for _, arg := range args[1:] {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			c.Ui.Error(fmt.Sprintf("Invalid item %q: must be in the form <key>=<value>", arg))
+			return 1
+		}
+		variable.Items[key] = value
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var out *api.Variable
+	switch {
+	case checkIndex < 0:
+		out, _, err = client.Variables().Create(variable, nil)
+	default:
+		variable.ModifyIndex = uint64(checkIndex)
+		out, _, err = client.Variables().Update(variable, nil)
+	}
+	if err != nil {
+		if conflict, ok := err.(api.ErrCASConflict); ok {
+			c.Ui.Error(fmt.Sprintf("Check-index %d does not match the current modify index %d of the variable",
+				checkIndex, conflict.Conflict.ModifyIndex))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error writing variable: %s", err))
+		return 1
+	}
+
+	if json {
+		formatted, err := Format(json, "", out)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(formatted)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote variable %q", out.Path))
+	return 0
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarCommand{}
+	var _ cli.Command = &VarGetCommand{}
+	var _ cli.Command = &VarListCommand{}
+	var _ cli.Command = &VarPurgeCommand{}
+	var _ cli.Command = &VarPutCommand{}
+}
+
+func TestVarCommands_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+	testutil.WaitForLeader(t, srv.Agent.RPC)
+
+	// Wait for the keyring to be initialized, so variables can be written.
+	testutil.WaitForResult(func() (bool, error) {
+		_, _, err := client.Variables().List(nil)
+		if err != nil {
+			return false, err
+		}
+		keyMeta, err := srv.Agent.Server().State().GetActiveRootKeyMeta(nil)
+		return keyMeta != nil, err
+	}, func(err error) {
+		t.Fatalf("keyring was not initialized: %v", err)
+	})
+
+	ui := cli.NewMockUi()
+	meta := Meta{Ui: ui}
+
+	// Put requires items.
+	put := &VarPutCommand{Meta: meta}
+	require.Equal(t, 1, put.Run([]string{"-address=" + url, "app/creds"}))
+	require.Contains(t, ui.ErrorWriter.String(), "This command takes at least two arguments")
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 1, put.Run([]string{"-address=" + url, "app/creds", "invalid"}))
+	require.Contains(t, ui.ErrorWriter.String(), "must be in the form <key>=<value>")
+	ui.ErrorWriter.Reset()
+
+	// Write the variable.
+	require.Equal(t, 0, put.Run([]string{"-address=" + url, "app/creds", "username=admin", "password=a=b"}))
+	require.Contains(t, ui.OutputWriter.String(), `Successfully wrote variable "app/creds"`)
+	ui.OutputWriter.Reset()
+
+	// A check-and-set create should fail as the variable now exists.
+	require.Equal(t, 1, put.Run([]string{"-address=" + url, "-check-index=0", "app/creds", "username=other"}))
+	require.Contains(t, ui.ErrorWriter.String(), "does not match the current modify index")
+	ui.ErrorWriter.Reset()
+
+	// Read a single item.
+	get := &VarGetCommand{Meta: meta}
+	require.Equal(t, 0, get.Run([]string{"-address=" + url, "-item=password", "app/creds"}))
+	require.Equal(t, "a=b\n", ui.OutputWriter.String())
+	ui.OutputWriter.Reset()
+
+	// Read the whole variable.
+	require.Equal(t, 0, get.Run([]string{"-address=" + url, "app/creds"}))
+	require.Contains(t, ui.OutputWriter.String(), "username")
+	require.Contains(t, ui.OutputWriter.String(), "admin")
+	ui.OutputWriter.Reset()
+
+	// List the variables.
+	list := &VarListCommand{Meta: meta}
+	require.Equal(t, 0, list.Run([]string{"-address=" + url, "app"}))
+	require.Contains(t, ui.OutputWriter.String(), "app/creds")
+	ui.OutputWriter.Reset()
+
+	require.Equal(t, 0, list.Run([]string{"-address=" + url, "other"}))
+	require.Contains(t, ui.OutputWriter.String(), "No variables found")
+	ui.OutputWriter.Reset()
+
+	// Purge the variable and ensure it is no longer found.
+	purge := &VarPurgeCommand{Meta: meta}
+	require.Equal(t, 0, purge.Run([]string{"-address=" + url, "app/creds"}))
+	require.Contains(t, ui.OutputWriter.String(), `Successfully purged variable "app/creds"`)
+	ui.OutputWriter.Reset()
+
+	require.Equal(t, 1, get.Run([]string{"-address=" + url, "app/creds"}))
+	require.Contains(t, ui.ErrorWriter.String(), `No variable found at path "app/creds"`)
+}
@@ -17,13 +17,17 @@ func (c *VolumeCreateCommand) Help() string {
 	helpText := `
 Usage: nomad volume create [options] <input>
 
-  Creates a volume in an external storage provider and registers it in Nomad.
+  Creates a volume in an external storage provider and registers it in Nomad,
+  or asks a Nomad client to create a dynamic host volume when the volume type
+  is "host".
 
   If the supplied path is "-" the volume file is read from stdin. Otherwise, it
   is read from the file at the supplied path.
 
   When ACLs are enabled, this command requires a token with the
-  'csi-write-volume' capability for the volume's namespace.
+  'csi-write-volume' capability for the volume's namespace, or the
+  'host-volume-write' capability for host volumes, which is not granted by
+  the 'write' namespace policy.
 
 General Options:
 
@@ -98,6 +102,8 @@ func (c *VolumeCreateCommand) Run(args []string) int {
 	case "csi":
 		code := c.csiCreate(client, ast)
 		return code
+	case "host":
+		return c.hostVolumeCreate(client, ast)
 	default:
 		c.Ui.Error(fmt.Sprintf("Error unknown volume type: %s", volType))
 		return 1
@@ -25,9 +25,12 @@ Usage: nomad volume delete [options] <vol id>
   unpublished. If the volume no longer exists, this command will silently
   return without an error.
 
+  Dynamic host volumes are deleted from their node with -type host. Deleting
+  will fail if the volume is still in use by an allocation.
+
   When ACLs are enabled, this command requires a token with the
   'csi-write-volume' and 'csi-read-volume' capabilities for the volume's
-  namespace.
+  namespace, or the 'host-volume-write' capability for host volumes.
 
 General Options:
 
@@ -38,13 +41,18 @@ Delete Options:
   -secret
     Secrets to pass to the plugin to delete the snapshot. Accepts multiple
     flags in the form -secret key=value
+
+  -type <type>
+    Type of the volume to delete, either "csi" (the default) or "host".
 `
 	return strings.TrimSpace(helpText)
 }
 
 func (c *VolumeDeleteCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
-		complete.Flags{})
+		complete.Flags{
+			"-type": predictVolumeType,
+		})
 }
 
 func (c *VolumeDeleteCommand) AutocompleteArgs() complete.Predictor {
@@ -77,9 +85,11 @@ func (c *VolumeDeleteCommand) Name() string { return "volume delete" }
 
 func (c *VolumeDeleteCommand) Run(args []string) int {
 	var secretsArgs flaghelper.StringFlag
+	var typeArg string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.Var(&secretsArgs, "secret", "secrets for snapshot, ex. -secret key=value")
+	flags.StringVar(&typeArg, "type", "csi", "type of volume (csi or host)")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing arguments %s", err))
@@ -102,6 +112,15 @@ func (c *VolumeDeleteCommand) Run(args []string) int {
 		return 1
 	}
 
+	switch typeArg {
+	case "csi":
+	case "host":
+		return c.deleteHostVolume(client, volID)
+	default:
+		c.Ui.Error(fmt.Sprintf("Error unknown volume type: %s", typeArg))
+		return 1
+	}
+
 	secrets := api.CSISecrets{}
 	for _, kv := range secretsArgs {
 		s := strings.Split(kv, "=")
@@ -125,3 +144,30 @@ func (c *VolumeDeleteCommand) Run(args []string) int {
 	c.Ui.Output(fmt.Sprintf("Successfully deleted volume %q!", volID))
 	return 0
 }
+
+func (c *VolumeDeleteCommand) deleteHostVolume(client *api.Client, volID string) int {
+	// Prefix search for the volume
+	vols, _, err := client.HostVolumes().List("", &api.QueryOptions{Prefix: volID})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying volumes: %s", err))
+		return 1
+	}
+	if len(vols) == 0 {
+		c.Ui.Error(fmt.Sprintf("No volumes(s) with prefix or ID %q found", volID))
+		return 1
+	}
+	if len(vols) > 1 {
+		c.Ui.Error(fmt.Sprintf("Prefix %q matched multiple volumes", volID))
+		return 1
+	}
+
+	client.SetNamespace(vols[0].Namespace)
+	_, err = client.HostVolumes().Delete(vols[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted volume %q!", vols[0].ID))
+	return 0
+}
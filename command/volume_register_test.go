@@ -203,3 +203,55 @@ topology_request {
 
 	}
 }
+
+func TestHostVolumeDecode(t *testing.T) {
+	ci.Parallel(t)
+
+	ast, err := hcl.ParseString(`
+namespace    = "prod"
+name         = "data"
+type         = "host"
+plugin_id    = "mkdir"
+node_pool    = "prod"
+capacity_min = "10GiB"
+capacity_max = "20G"
+
+constraint {
+  attribute = "${attr.kernel.name}"
+  value     = "linux"
+}
+
+parameters {
+  mode = "0750"
+  uid  = "1000"
+}
+`)
+	require.NoError(t, err)
+
+	vol, err := decodeHostVolume(ast)
+	require.NoError(t, err)
+	require.Equal(t, &api.HostVolume{
+		Namespace:                 "prod",
+		Name:                      "data",
+		PluginID:                  "mkdir",
+		NodePool:                  "prod",
+		RequestedCapacityMinBytes: 10737418240,
+		RequestedCapacityMaxBytes: 20000000000,
+		Constraints: []*api.Constraint{{
+			LTarget: "${attr.kernel.name}",
+			RTarget: "linux",
+		}},
+		Parameters: map[string]string{"mode": "0750", "uid": "1000"},
+	}, vol)
+
+	ast, err = hcl.ParseString(`
+name = "data"
+constraint {
+  attribute = "${attr.kernel.name}"
+  bogus     = "linux"
+}
+`)
+	require.NoError(t, err)
+	_, err = decodeHostVolume(ast)
+	require.ErrorContains(t, err, "invalid key: bogus")
+}
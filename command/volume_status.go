@@ -21,12 +21,13 @@ func (c *VolumeStatusCommand) Help() string {
 	helpText := `
 Usage: nomad volume status [options] <id>
 
-  Display status information about a CSI volume. If no volume id is given, a
-  list of all volumes will be displayed.
+  Display status information about a CSI volume, or a dynamic host volume
+  with -type host. If no volume id is given, a list of all volumes will be
+  displayed.
 
   When ACLs are enabled, this command requires a token with the
   'csi-read-volume' and 'csi-list-volumes' capability for the volume's
-  namespace.
+  namespace, or the 'host-volume-read' capability for host volumes.
 
 General Options:
 
@@ -35,7 +36,7 @@ General Options:
 Status Options:
 
   -type <type>
-    List only volumes of type <type>.
+    List only volumes of type <type>, either "csi" (the default) or "host".
 
   -short
     Display short output. Used only when a single volume is being
@@ -127,12 +128,13 @@ func (c *VolumeStatusCommand) Run(args []string) int {
 		id = args[0]
 	}
 
-	code := c.csiStatus(client, id)
-	if code != 0 {
-		return code
+	switch typeArg {
+	case "", "csi":
+		return c.csiStatus(client, id)
+	case "host":
+		return c.hostVolumeStatus(client, id)
+	default:
+		c.Ui.Error(fmt.Sprintf("Error unknown volume type: %s", typeArg))
+		return 1
 	}
-
-	// Extend this section with other volume implementations
-
-	return 0
 }
@@ -0,0 +1,146 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+)
+
+func (c *VolumeStatusCommand) hostVolumeStatus(client *api.Client, id string) int {
+	// Invoke list mode if no volume id
+	if id == "" {
+		return c.listHostVolumes(client)
+	}
+
+	// Prefix search for the volume
+	vols, _, err := client.HostVolumes().List("", &api.QueryOptions{Prefix: id})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying volumes: %s", err))
+		return 1
+	}
+	if len(vols) == 0 {
+		c.Ui.Error(fmt.Sprintf("No volumes(s) with prefix or ID %q found", id))
+		return 1
+	}
+	if len(vols) > 1 {
+		out, err := c.hostVolumeFormatStubs(vols)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error formatting: %s", err))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple volumes\n\n%s", out))
+		return 1
+	}
+
+	// Try querying the volume
+	client.SetNamespace(vols[0].Namespace)
+	vol, _, err := client.HostVolumes().Info(vols[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying volume: %s", err))
+		return 1
+	}
+
+	str, err := c.hostVolumeFormat(vol)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error formatting volume: %s", err))
+		return 1
+	}
+	c.Ui.Output(str)
+
+	return 0
+}
+
+func (c *VolumeStatusCommand) listHostVolumes(client *api.Client) int {
+	vols, _, err := client.HostVolumes().List("", nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying volumes: %s", err))
+		return 1
+	}
+
+	if len(vols) == 0 {
+		// No output if we have no volumes
+		c.Ui.Error("No dynamic host volumes")
+		return 0
+	}
+
+	str, err := c.hostVolumeFormatStubs(vols)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error formatting: %s", err))
+		return 1
+	}
+	c.Ui.Output(str)
+	return 0
+}
+
+func (c *VolumeStatusCommand) hostVolumeFormatStubs(vols []*api.HostVolumeStub) (string, error) {
+	// Sort the output by volume id
+	sort.Slice(vols, func(i, j int) bool { return vols[i].ID < vols[j].ID })
+
+	if c.json || len(c.template) > 0 {
+		out, err := Format(c.json, c.template, vols)
+		if err != nil {
+			return "", fmt.Errorf("format error: %v", err)
+		}
+		return out, nil
+	}
+
+	rows := make([]string, len(vols)+1)
+	rows[0] = "ID|Name|Namespace|Plugin ID|Node ID|Node Pool|State"
+	for i, v := range vols {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s",
+			limit(v.ID, c.length),
+			v.Name,
+			v.Namespace,
+			v.PluginID,
+			limit(v.NodeID, c.length),
+			v.NodePool,
+			v.State,
+		)
+	}
+	return formatList(rows), nil
+}
+
+func (c *VolumeStatusCommand) hostVolumeFormat(vol *api.HostVolume) (string, error) {
+	if c.json || len(c.template) > 0 {
+		out, err := Format(c.json, c.template, vol)
+		if err != nil {
+			return "", fmt.Errorf("format error: %v", err)
+		}
+		return out, nil
+	}
+
+	capacity := "<none>"
+	if vol.CapacityBytes > 0 {
+		capacity = humanize.IBytes(uint64(vol.CapacityBytes))
+	}
+
+	output := []string{
+		fmt.Sprintf("ID|%s", vol.ID),
+		fmt.Sprintf("Name|%s", vol.Name),
+		fmt.Sprintf("Namespace|%s", vol.Namespace),
+		fmt.Sprintf("Plugin ID|%s", vol.PluginID),
+		fmt.Sprintf("Node ID|%s", vol.NodeID),
+		fmt.Sprintf("Node Pool|%s", vol.NodePool),
+		fmt.Sprintf("Capacity|%s", capacity),
+		fmt.Sprintf("State|%s", vol.State),
+		fmt.Sprintf("Host Path|%s", vol.HostPath),
+	}
+
+	// Exit early
+	if c.short {
+		return formatKV(output), nil
+	}
+
+	full := []string{formatKV(output)}
+
+	// Format the allocs
+	banner := c.Colorize().Color("\n[bold]Allocations[reset]")
+	allocs := formatAllocListStubs(vol.Allocations, c.verbose, c.length)
+	full = append(full, banner)
+	full = append(full, allocs)
+
+	return strings.Join(full, "\n"), nil
+}
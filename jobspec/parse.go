@@ -303,7 +303,9 @@ func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 		// Check for invalid keys
 		valid := []string{
 			"attribute",
+			"attributes",
 			"weight",
+			"max_skew",
 			"target",
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
@@ -58,6 +58,8 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"scaling",
 			"stop_after_client_disconnect",
 			"max_client_disconnect",
+			"sticky_host",
+			"scheduler_algorithm",
 		}
 		if err := checkHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -69,6 +69,8 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"migrate",
 		"name",
 		"namespace",
+		"node_pool",
+		"scheduler_algorithm",
 		"parameterized",
 		"periodic",
 		"priority",
@@ -230,8 +232,11 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 	valid := []string{
 		"enabled",
 		"cron",
+		"crons",
 		"prohibit_overlap",
 		"time_zone",
+		"exclude_dates",
+		"exclude_crons",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return err
@@ -251,6 +256,11 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 		m["Spec"] = cron
 	}
 
+	// If "crons" is provided, set the type to "cron" as well.
+	if _, ok := m["crons"]; ok {
+		m["SpecType"] = api.PeriodicSpecCron
+	}
+
 	// Build the constraint
 	var p api.PeriodicConfig
 	if err := mapstructure.WeakDecode(m, &p); err != nil {
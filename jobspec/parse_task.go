@@ -28,6 +28,7 @@ var (
 	}
 
 	normalTaskKeys = append(commonTaskKeys,
+		"action",
 		"artifact",
 		"constraint",
 		"affinity",
@@ -96,6 +97,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	if err := hcl.DecodeObject(&m, item.Val); err != nil {
 		return nil, err
 	}
+	delete(m, "action")
 	delete(m, "artifact")
 	delete(m, "config")
 	delete(m, "constraint")
@@ -256,6 +258,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		valid := []string{
 			"max_files",
 			"max_file_size",
+			"sink",
 		}
 		if err := checkHCLKeys(logsBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "logs ->")
@@ -265,11 +268,22 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 			return nil, err
 		}
 
+		delete(m, "sink")
+
 		var log api.LogConfig
 		if err := mapstructure.WeakDecode(m, &log); err != nil {
 			return nil, err
 		}
 
+		// Parse the log sinks
+		if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+			if so := ot.List.Filter("sink"); len(so.Items) > 0 {
+				if err := parseLogSinks(&log.Sinks, so); err != nil {
+					return nil, multierror.Prefix(err, "logs -> sink ->")
+				}
+			}
+		}
+
 		t.LogConfig = &log
 	}
 
@@ -280,6 +294,13 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		}
 	}
 
+	// Parse actions
+	if o := listVal.Filter("action"); len(o.Items) > 0 {
+		if err := parseActions(&t.Actions, o); err != nil {
+			return nil, multierror.Prefix(err, "action ->")
+		}
+	}
+
 	// Parse templates
 	if o := listVal.Filter("template"); len(o.Items) > 0 {
 		if err := parseTemplates(&t.Templates, o); err != nil {
@@ -431,6 +452,110 @@ func parseArtifactOption(result map[string]string, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseLogSinks(result *[]*api.LogSink, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"type",
+			"address",
+			"tag",
+			"headers",
+			"batch_size",
+			"batch_wait",
+			"buffer_size",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		delete(m, "headers")
+
+		var sink api.LogSink
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &sink,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		var headerList *ast.ObjectList
+		if ot, ok := o.Val.(*ast.ObjectType); ok {
+			headerList = ot.List
+		} else {
+			return fmt.Errorf("sink should be an object")
+		}
+
+		if ho := headerList.Filter("headers"); len(ho.Items) > 0 {
+			if len(ho.Items) > 1 {
+				return fmt.Errorf("only one 'headers' block allowed per sink")
+			}
+
+			var hm map[string]interface{}
+			if err := hcl.DecodeObject(&hm, ho.Items[0].Val); err != nil {
+				return multierror.Prefix(err, "headers: ")
+			}
+			if err := mapstructure.WeakDecode(hm, &sink.Headers); err != nil {
+				return multierror.Prefix(err, "headers: ")
+			}
+		}
+
+		*result = append(*result, &sink)
+	}
+
+	return nil
+}
+
+func parseActions(result *[]*api.Action, list *ast.ObjectList) error {
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		if l := len(item.Keys); l == 0 {
+			return fmt.Errorf("action missing name")
+		} else if l > 1 {
+			return fmt.Errorf("action should only have one name")
+		}
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("action '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"command",
+			"args",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s',", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		action := &api.Action{Name: n}
+		if err := mapstructure.WeakDecode(m, action); err != nil {
+			return err
+		}
+
+		*result = append(*result, action)
+	}
+
+	return nil
+}
+
 func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
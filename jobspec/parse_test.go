@@ -35,16 +35,18 @@ func TestParse(t *testing.T) {
 		{
 			"basic.hcl",
 			&api.Job{
-				ID:          stringToPtr("binstore-storagelocker"),
-				Name:        stringToPtr("binstore-storagelocker"),
-				Type:        stringToPtr("batch"),
-				Priority:    intToPtr(52),
-				AllAtOnce:   boolToPtr(true),
-				Datacenters: []string{"us2", "eu1"},
-				Region:      stringToPtr("fooregion"),
-				Namespace:   stringToPtr("foonamespace"),
-				ConsulToken: stringToPtr("abc"),
-				VaultToken:  stringToPtr("foo"),
+				ID:                 stringToPtr("binstore-storagelocker"),
+				Name:               stringToPtr("binstore-storagelocker"),
+				Type:               stringToPtr("batch"),
+				Priority:           intToPtr(52),
+				AllAtOnce:          boolToPtr(true),
+				Datacenters:        []string{"us2", "eu1"},
+				NodePool:           stringToPtr("dev"),
+				SchedulerAlgorithm: stringToPtr("binpack"),
+				Region:             stringToPtr("fooregion"),
+				Namespace:          stringToPtr("foonamespace"),
+				ConsulToken:        stringToPtr("abc"),
+				VaultToken:         stringToPtr("foo"),
 
 				Meta: map[string]string{
 					"foo": "bar",
@@ -87,6 +89,11 @@ func TestParse(t *testing.T) {
 							},
 						},
 					},
+					{
+						Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+						Weight:     int8ToPtr(50),
+						MaxSkew:    1,
+					},
 				},
 
 				Update: &api.UpdateStrategy{
@@ -120,8 +127,9 @@ func TestParse(t *testing.T) {
 					},
 
 					{
-						Name:  stringToPtr("binsl"),
-						Count: intToPtr(5),
+						Name:               stringToPtr("binsl"),
+						Count:              intToPtr(5),
+						SchedulerAlgorithm: stringToPtr("spread"),
 						Constraints: []*api.Constraint{
 							{
 								LTarget: "kernel.os",
@@ -534,6 +542,45 @@ func TestParse(t *testing.T) {
 			false,
 		},
 
+		{
+			"periodic-crons.hcl",
+			&api.Job{
+				ID:   stringToPtr("foo"),
+				Name: stringToPtr("foo"),
+				Periodic: &api.PeriodicConfig{
+					SpecType: stringToPtr(api.PeriodicSpecCron),
+					Specs: []string{
+						"0 9 * * MON-FRI",
+						"0 12 * * SAT,SUN",
+					},
+					ExcludeDates: []string{"2022-12-25", "2023-01-01"},
+					ExcludeCrons: []string{"* 0-6 * * *"},
+				},
+			},
+			false,
+		},
+
+		{
+			"tg-sticky-host.hcl",
+			&api.Job{
+				ID:   stringToPtr("kafka"),
+				Name: stringToPtr("kafka"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:       stringToPtr("broker"),
+						StickyHost: boolToPtr(true),
+						Tasks: []*api.Task{
+							{
+								Name:   "broker",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+
 		{
 			"specify-job.hcl",
 			&api.Job{
@@ -1818,6 +1865,81 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"task-log-sinks.hcl",
+			&api.Job{
+				ID:   stringToPtr("task-log-sinks"),
+				Name: stringToPtr("task-log-sinks"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								LogConfig: &api.LogConfig{
+									MaxFiles:      intToPtr(5),
+									MaxFileSizeMB: intToPtr(20),
+									Sinks: []*api.LogSink{
+										{
+											Type:    "syslog",
+											Address: "udp://127.0.0.1:514",
+											Tag:     "web",
+										},
+										{
+											Type:       "http",
+											Address:    "https://logs.example.com/ingest",
+											BatchSize:  intToPtr(50),
+											BatchWait:  timeToPtr(5 * time.Second),
+											BufferSize: intToPtr(1000),
+											Headers: map[string]string{
+												"Authorization": "Bearer token",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-actions.hcl",
+			&api.Job{
+				ID:   stringToPtr("task-actions"),
+				Name: stringToPtr("task-actions"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								Actions: []*api.Action{
+									{
+										Name:    "flush-cache",
+										Command: "/bin/redis-cli",
+										Args:    []string{"FLUSHALL"},
+									},
+									{
+										Name:    "rotate-logs",
+										Command: "/usr/sbin/logrotate",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-actions-duplicate.hcl",
+			nil,
+			true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -1,12 +1,14 @@
 job "binstore-storagelocker" {
-  region       = "fooregion"
-  namespace    = "foonamespace"
-  type         = "batch"
-  priority     = 52
-  all_at_once  = true
-  datacenters  = ["us2", "eu1"]
-  consul_token = "abc"
-  vault_token  = "foo"
+  region              = "fooregion"
+  namespace           = "foonamespace"
+  type                = "batch"
+  priority            = 52
+  all_at_once         = true
+  datacenters         = ["us2", "eu1"]
+  node_pool           = "dev"
+  scheduler_algorithm = "binpack"
+  consul_token        = "abc"
+  vault_token         = "foo"
 
   meta {
     foo = "bar"
@@ -43,6 +45,12 @@ job "binstore-storagelocker" {
     }
   }
 
+  spread {
+    attributes = ["${node.datacenter}", "${meta.rack}"]
+    weight     = 50
+    max_skew   = 1
+  }
+
   update {
     stagger           = "60s"
     max_parallel      = 2
@@ -68,7 +76,8 @@ job "binstore-storagelocker" {
   }
 
   group "binsl" {
-    count = 5
+    count               = 5
+    scheduler_algorithm = "spread"
 
     volume "foo" {
       type   = "host"
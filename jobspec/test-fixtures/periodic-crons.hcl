@@ -0,0 +1,10 @@
+job "foo" {
+  periodic {
+    crons = [
+      "0 9 * * MON-FRI",
+      "0 12 * * SAT,SUN",
+    ]
+    exclude_dates = ["2022-12-25", "2023-01-01"]
+    exclude_crons = ["* 0-6 * * *"]
+  }
+}
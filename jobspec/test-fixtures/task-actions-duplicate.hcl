@@ -0,0 +1,15 @@
+job "task-actions-duplicate" {
+  group "group" {
+    task "task" {
+      driver = "docker"
+
+      action "flush-cache" {
+        command = "/bin/redis-cli"
+      }
+
+      action "flush-cache" {
+        command = "/bin/memcflush"
+      }
+    }
+  }
+}
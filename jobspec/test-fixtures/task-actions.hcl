@@ -0,0 +1,16 @@
+job "task-actions" {
+  group "group" {
+    task "task" {
+      driver = "docker"
+
+      action "flush-cache" {
+        command = "/bin/redis-cli"
+        args    = ["FLUSHALL"]
+      }
+
+      action "rotate-logs" {
+        command = "/usr/sbin/logrotate"
+      }
+    }
+  }
+}
@@ -0,0 +1,30 @@
+job "task-log-sinks" {
+  group "group" {
+    task "task" {
+      driver = "docker"
+
+      logs {
+        max_files     = 5
+        max_file_size = 20
+
+        sink {
+          type    = "syslog"
+          address = "udp://127.0.0.1:514"
+          tag     = "web"
+        }
+
+        sink {
+          type        = "http"
+          address     = "https://logs.example.com/ingest"
+          batch_size  = 50
+          batch_wait  = "5s"
+          buffer_size = 1000
+
+          headers {
+            Authorization = "Bearer token"
+          }
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,9 @@
+job "kafka" {
+  group "broker" {
+    sticky_host = true
+
+    task "broker" {
+      driver = "docker"
+    }
+  }
+}
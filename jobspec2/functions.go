@@ -114,6 +114,27 @@ func Functions(basedir string, allowFS bool) map[string]function.Function {
 	return funcs
 }
 
+// hookFileFunc wraps a file function so that hook is called with the path and
+// content of each file it reads.
+func hookFileFunc(fn function.Function, hook func(path, content string)) function.Function {
+	spec := &function.Spec{
+		Params:   fn.Params(),
+		VarParam: fn.VarParam(),
+		Type:     fn.ReturnTypeForValues,
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			val, err := fn.Call(args)
+			if err != nil {
+				return val, err
+			}
+
+			hook(args[0].AsString(), val.AsString())
+			return val, nil
+		},
+	}
+
+	return function.New(spec)
+}
+
 func guardFS(allowFS bool, fn function.Function) function.Function {
 	if allowFS {
 		return fn
@@ -71,6 +71,14 @@ type ParseConfig struct {
 
 	Strict bool
 
+	// ReadFileHook, if set, is called with the path and content of each file
+	// read by the file function.
+	ReadFileHook func(path, content string)
+
+	// EnvVarHook, if set, is called with the name and value of each
+	// environment variable used to set an input variable.
+	EnvVarHook func(name, value string)
+
 	// parsedVarFiles represent parsed HCL AST of the passed EnvVars
 	parsedVarFiles []*hcl.File
 }
@@ -330,6 +330,42 @@ job "example" {
 	})
 }
 
+func TestParse_Hooks(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+variable "dc" {}
+
+job "example" {
+  datacenters = [var.dc]
+  region      = file("parse_test.go")
+}
+`
+
+	files := map[string]string{}
+	envs := map[string]string{}
+	_, err := ParseWithConfig(&ParseConfig{
+		Path:    "input.hcl",
+		Body:    []byte(hcl),
+		AllowFS: true,
+		Envs:    []string{"NOMAD_VAR_dc=dc1", "NOMAD_VAR_unused=value", "OTHER=value"},
+		ReadFileHook: func(path, content string) {
+			files[path] = content
+		},
+		EnvVarHook: func(name, value string) {
+			envs[name] = value
+		},
+	})
+	require.NoError(t, err)
+
+	expected, err := ioutil.ReadFile("parse_test.go")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"parse_test.go": string(expected)}, files)
+
+	// Only the environment variables setting input variables are reported
+	require.Equal(t, map[string]string{"dc": "dc1"}, envs)
+}
+
 func TestParseDynamic(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1051,3 +1087,46 @@ func TestWaitConfig(t *testing.T) {
 	require.Equal(t, 5*time.Second, *tmpl.Wait.Min)
 	require.Equal(t, 60*time.Second, *tmpl.Wait.Max)
 }
+
+func TestParse_TaskActions(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+job "example" {
+  group "group" {
+    task "task" {
+      driver = "docker"
+
+      action "flush-cache" {
+        command = "/bin/redis-cli"
+        args    = ["FLUSHALL"]
+      }
+
+      action "rotate-logs" {
+        command = "/usr/sbin/logrotate"
+      }
+    }
+  }
+}
+`
+
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "input.hcl",
+		Body:    []byte(hcl),
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	expected := []*api.Action{
+		{
+			Name:    "flush-cache",
+			Command: "/bin/redis-cli",
+			Args:    []string{"FLUSHALL"},
+		},
+		{
+			Name:    "rotate-logs",
+			Command: "/usr/sbin/logrotate",
+		},
+	}
+	require.Equal(t, expected, job.TaskGroups[0].Tasks[0].Actions)
+}
@@ -302,8 +302,14 @@ func (c *jobConfig) decodeJob(content *hcl.BodyContent, ctx *hcl.EvalContext) hc
 func (c *jobConfig) EvalContext() *hcl.EvalContext {
 	vars, _ := c.InputVariables.Values()
 	locals, _ := c.LocalVariables.Values()
+
+	funcs := Functions(c.ParseConfig.BaseDir, c.ParseConfig.AllowFS)
+	if hook := c.ParseConfig.ReadFileHook; hook != nil && c.ParseConfig.AllowFS {
+		funcs["file"] = hookFileFunc(funcs["file"], hook)
+	}
+
 	return &hcl.EvalContext{
-		Functions: Functions(c.ParseConfig.BaseDir, c.ParseConfig.AllowFS),
+		Functions: funcs,
 		Variables: map[string]cty.Value{
 			inputVariablesAccessor: cty.ObjectVal(vars),
 			localsAccessor:         cty.ObjectVal(locals),
@@ -506,6 +506,9 @@ func (c *jobConfig) collectInputVariableValues(env []string, files []*hcl.File,
 			// this variable was not defined in the hcl files, let's skip it !
 			continue
 		}
+		if hook := c.ParseConfig.EnvVarHook; hook != nil {
+			hook(name, value)
+		}
 
 		fakeFilename := fmt.Sprintf("<value for var.%s from env>", name)
 		expr, moreDiags := expressionFromVariableDefinition(fakeFilename, value, variable.Type)
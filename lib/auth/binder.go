@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// interpolationRegex matches the "${value.<name>}" placeholders which may be
+// used within a binding rule bind name.
+var interpolationRegex = regexp.MustCompile(`\$\{value\.([^}]+)\}`)
+
+// BinderStateStore is the subset of the state store used by the Binder to
+// look up the binding rules and roles.
+type BinderStateStore interface {
+	GetACLBindingRulesByAuthMethod(ws memdb.WatchSet, authMethod string) (memdb.ResultIterator, error)
+	GetACLRoleByName(ws memdb.WatchSet, roleName string) (*structs.ACLRole, error)
+}
+
+// Binder is responsible for collecting the ACL roles and policies to be
+// linked to an ACL token, based on the verified identity of a user and the
+// binding rules of the auth method the user logged in with.
+type Binder struct {
+	store BinderStateStore
+}
+
+// NewBinder returns a Binder which looks up binding rules and roles within
+// the passed state store.
+func NewBinder(store BinderStateStore) *Binder {
+	return &Binder{store: store}
+}
+
+// Identity is the verified identity of a user as returned by an auth method.
+type Identity struct {
+
+	// Claims are the claims of the user which have been mapped according to
+	// the auth method claim mappings. Binding rule selectors are evaluated
+	// against the claims.
+	Claims *Claims
+}
+
+// Claims holds the mapped claims of a user. The struct tags expose the
+// fields to binding rule selectors as "value.<name>" and "list.<name>".
+type Claims struct {
+	Value map[string]string   `bexpr:"value"`
+	List  map[string][]string `bexpr:"list"`
+}
+
+// Bindings contains the ACL roles and policies to be linked to a token
+// created by a login.
+type Bindings struct {
+	Management bool
+	Roles      []*structs.ACLTokenRoleLink
+	Policies   []string
+}
+
+// None indicates that the resulting bindings would not give the created ACL
+// token any permissions.
+func (b *Bindings) None() bool {
+	if b == nil {
+		return true
+	}
+	return !b.Management && len(b.Roles) == 0 && len(b.Policies) == 0
+}
+
+// Bind collects the ACL roles and policies to be linked to the ACL token
+// created by a login, by evaluating the binding rules of the auth method
+// against the identity of the user. Binding rules which reference a role
+// that does not exist are skipped.
+func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, identity *Identity) (*Bindings, error) {
+
+	iter, err := b.store.GetACLBindingRulesByAuthMethod(nil, authMethod.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := new(Bindings)
+	roles := make(map[string]struct{})
+	policies := make(map[string]struct{})
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+
+		matched, err := doesSelectorMatch(rule.Selector, identity.Claims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate binding rule %s: %v", rule.ID, err)
+		}
+		if !matched {
+			continue
+		}
+
+		if rule.BindType == structs.ACLBindingRuleBindTypeManagement {
+			bindings.Management = true
+			continue
+		}
+
+		bindName, err := interpolateBindName(rule.BindName, identity.Claims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to interpolate binding rule %s bind name: %v", rule.ID, err)
+		}
+
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypeRole:
+			role, err := b.store.GetACLRoleByName(nil, bindName)
+			if err != nil {
+				return nil, err
+			}
+			if role == nil {
+				continue
+			}
+			if _, ok := roles[role.ID]; !ok {
+				roles[role.ID] = struct{}{}
+				bindings.Roles = append(bindings.Roles, &structs.ACLTokenRoleLink{ID: role.ID})
+			}
+		case structs.ACLBindingRuleBindTypePolicy:
+			if _, ok := policies[bindName]; !ok {
+				policies[bindName] = struct{}{}
+				bindings.Policies = append(bindings.Policies, bindName)
+			}
+		}
+	}
+
+	// A management token cannot be linked to roles or policies, and already
+	// holds all permissions.
+	if bindings.Management {
+		bindings.Roles = nil
+		bindings.Policies = nil
+	}
+
+	return bindings, nil
+}
+
+// doesSelectorMatch checks that a single binding rule selector matches the
+// claims. An empty selector matches all claims.
+func doesSelectorMatch(selector string, claims *Claims) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+
+	eval, err := bexpr.CreateEvaluator(selector)
+	if err != nil {
+		return false, err
+	}
+	return eval.Evaluate(claims)
+}
+
+// interpolateBindName replaces the "${value.<name>}" placeholders within a
+// bind name with the matching claim value. An error is returned if a claim
+// referenced by a placeholder is missing.
+func interpolateBindName(bindName string, claims *Claims) (string, error) {
+	var missing []string
+
+	out := interpolationRegex.ReplaceAllStringFunc(bindName, func(match string) string {
+		name := interpolationRegex.FindStringSubmatch(match)[1]
+		value, ok := claims.Value[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("claims %v not found", missing)
+	}
+	return out, nil
+}
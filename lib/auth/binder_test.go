@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinder_Bind(t *testing.T) {
+	ci.Parallel(t)
+
+	testStore := state.TestStateStore(t)
+	testBind := NewBinder(testStore)
+
+	// Create an auth method and an ACL role, which the binding rules can
+	// reference.
+	authMethod := mock.ACLAuthMethod()
+	require.NoError(t, testStore.UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	policy := mock.ACLPolicy()
+	require.NoError(t, testStore.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{policy}))
+
+	role := &structs.ACLRole{
+		ID:       "9e7e4a5b-2a73-0c5c-dc03-8c2b3ee6d0a3",
+		Name:     "engineering",
+		Policies: []*structs.ACLRolePolicyLink{{Name: policy.Name}},
+	}
+	require.NoError(t, testStore.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLRole{role}, false))
+
+	// Without any binding rules, the bindings should be empty.
+	identity := &Identity{
+		Claims: &Claims{
+			Value: map[string]string{"team": "platform"},
+			List:  map[string][]string{"groups": {"engineering"}},
+		},
+	}
+	bindings, err := testBind.Bind(authMethod, identity)
+	require.NoError(t, err)
+	require.True(t, bindings.None())
+
+	// Create binding rules which link to the role, an interpolated policy
+	// name, and a rule which does not match.
+	bindingRules := []*structs.ACLBindingRule{
+		{
+			ID:         "e9cce9dc-f327-41ed-8b44-7d1c0b0f2a12",
+			AuthMethod: authMethod.Name,
+			Selector:   `"engineering" in list.groups`,
+			BindType:   structs.ACLBindingRuleBindTypeRole,
+			BindName:   "engineering",
+		},
+		{
+			ID:         "2df7d4b2-bf15-4a64-8a88-05d2e1ae3d0c",
+			AuthMethod: authMethod.Name,
+			Selector:   `value.team == "platform"`,
+			BindType:   structs.ACLBindingRuleBindTypePolicy,
+			BindName:   "${value.team}-policy",
+		},
+		{
+			ID:         "74d8a1c4-7b9a-4b0c-a4d6-ea2d5f67a0a1",
+			AuthMethod: authMethod.Name,
+			Selector:   `"admins" in list.groups`,
+			BindType:   structs.ACLBindingRuleBindTypeManagement,
+		},
+		{
+			ID:         "4e2a2f8e-6c11-4ad6-9a63-5b0a10a3f2c7",
+			AuthMethod: authMethod.Name,
+			BindType:   structs.ACLBindingRuleBindTypeRole,
+			BindName:   "role-which-does-not-exist",
+		},
+	}
+	require.NoError(t, testStore.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 40, bindingRules, false))
+
+	bindings, err = testBind.Bind(authMethod, identity)
+	require.NoError(t, err)
+	require.False(t, bindings.Management)
+	require.Equal(t, []*structs.ACLTokenRoleLink{{ID: role.ID}}, bindings.Roles)
+	require.Equal(t, []string{"platform-policy"}, bindings.Policies)
+
+	// A matching management rule results in a management binding, without
+	// any roles or policies.
+	identity.Claims.List["groups"] = append(identity.Claims.List["groups"], "admins")
+	bindings, err = testBind.Bind(authMethod, identity)
+	require.NoError(t, err)
+	require.True(t, bindings.Management)
+	require.Empty(t, bindings.Roles)
+	require.Empty(t, bindings.Policies)
+	require.False(t, bindings.None())
+
+	// A bind name which references a missing claim results in an error.
+	delete(identity.Claims.Value, "team")
+	identity.Claims.List["groups"] = []string{}
+	bindingRules[1].Selector = ""
+	bindingRules[1].SetHash()
+	require.NoError(t, testStore.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 50, bindingRules[1:2], false))
+
+	_, err = testBind.Bind(authMethod, identity)
+	require.ErrorContains(t, err, "claims [team] not found")
+}
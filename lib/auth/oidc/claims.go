@@ -0,0 +1,107 @@
+package oidc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// SelectorData maps the claims of a verified ID token to the identity used to
+// evaluate the binding rules of the auth method. Only the claims named within
+// the auth method claim mappings are exposed. Claim names starting with a "/"
+// are treated as a JSON pointer, which allows selecting nested claims.
+func SelectorData(authMethod *structs.ACLAuthMethod, claims map[string]interface{}) (*auth.Identity, error) {
+	identity := &auth.Identity{
+		Claims: &auth.Claims{
+			Value: make(map[string]string),
+			List:  make(map[string][]string),
+		},
+	}
+	if authMethod.Config == nil {
+		return identity, nil
+	}
+
+	for claimName, name := range authMethod.Config.ClaimMappings {
+		raw, ok := lookupClaim(claims, claimName)
+		if !ok {
+			continue
+		}
+		value, ok := stringifyClaimValue(raw)
+		if !ok {
+			return nil, fmt.Errorf("claim %q cannot be converted to a string", claimName)
+		}
+		identity.Claims.Value[name] = value
+	}
+
+	for claimName, name := range authMethod.Config.ListClaimMappings {
+		raw, ok := lookupClaim(claims, claimName)
+		if !ok {
+			continue
+		}
+
+		// A single value is accepted for a list claim, since identity
+		// providers commonly collapse single item lists.
+		rawList, isList := raw.([]interface{})
+		if !isList {
+			rawList = []interface{}{raw}
+		}
+
+		values := make([]string, 0, len(rawList))
+		for _, rawItem := range rawList {
+			value, ok := stringifyClaimValue(rawItem)
+			if !ok {
+				return nil, fmt.Errorf("list claim %q contains a value which cannot be converted to a string", claimName)
+			}
+			values = append(values, value)
+		}
+		identity.Claims.List[name] = values
+	}
+
+	return identity, nil
+}
+
+// lookupClaim returns the claim value identified by the claim name, which is
+// either a top level claim name or a JSON pointer.
+func lookupClaim(claims map[string]interface{}, claimName string) (interface{}, bool) {
+	if !strings.HasPrefix(claimName, "/") {
+		value, ok := claims[claimName]
+		return value, ok
+	}
+
+	var current interface{} = claims
+	for _, token := range strings.Split(claimName[1:], "/") {
+
+		// Unescape the reference tokens as defined by RFC 6901.
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[token]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// stringifyClaimValue converts a scalar claim value to a string.
+func stringifyClaimValue(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,50 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorData(t *testing.T) {
+	ci.Parallel(t)
+
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Config.ClaimMappings = map[string]string{
+		"email":          "email",
+		"/nested/team":   "team",
+		"email_verified": "verified",
+		"missing":        "missing",
+	}
+	authMethod.Config.ListClaimMappings = map[string]string{
+		"groups": "groups",
+		"role":   "roles",
+	}
+
+	claims := map[string]interface{}{
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"nested":         map[string]interface{}{"team": "platform"},
+		"groups":         []interface{}{"engineering", "admins"},
+		"role":           "developer",
+	}
+
+	identity, err := SelectorData(authMethod, claims)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"email":    "alice@example.com",
+		"team":     "platform",
+		"verified": "true",
+	}, identity.Claims.Value)
+	require.Equal(t, map[string][]string{
+		"groups": {"engineering", "admins"},
+		"roles":  {"developer"},
+	}, identity.Claims.List)
+
+	// A claim which cannot be converted to a string results in an error.
+	claims["email"] = map[string]interface{}{"not": "a string"}
+	_, err = SelectorData(authMethod, claims)
+	require.ErrorContains(t, err, `claim "email" cannot be converted to a string`)
+}
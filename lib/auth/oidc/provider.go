@@ -0,0 +1,400 @@
+package oidc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	// discoveryPath is the path, relative to the discovery URL, of the OIDC
+	// provider metadata document.
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// defaultSigningAlg is the ID token signing algorithm allowed when the
+	// auth method does not configure any.
+	defaultSigningAlg = "RS256"
+
+	// requestTimeout is the timeout of the HTTP requests made to the OIDC
+	// provider.
+	requestTimeout = 30 * time.Second
+
+	// keySetMinRefreshInterval is the minimum time between fetches of the
+	// key set of the OIDC provider, so that ID tokens signed by unknown keys
+	// cannot be used to make the servers hammer the provider.
+	keySetMinRefreshInterval = 10 * time.Second
+)
+
+// providerMetadata is the subset of the OIDC provider metadata, as defined by
+// the OpenID Connect Discovery specification, used by the Provider.
+type providerMetadata struct {
+	Issuer   string `json:"issuer"`
+	AuthURL  string `json:"authorization_endpoint"`
+	TokenURL string `json:"token_endpoint"`
+	JWKSURL  string `json:"jwks_uri"`
+}
+
+// Provider performs the OIDC authorization code flow against the identity
+// provider configured by an auth method and verifies the returned ID token.
+type Provider struct {
+	config     *structs.ACLAuthMethodConfig
+	httpClient *http.Client
+	metadata   *providerMetadata
+
+	// keySet is the cached JSON Web Key Set of the identity provider which
+	// is used to verify ID tokens. It is refreshed when an ID token is
+	// signed by an unknown key, at most once every keySetMinRefreshInterval.
+	keySet        *jose.JSONWebKeySet
+	keySetFetched time.Time
+	keySetLock    sync.Mutex
+}
+
+// NewProvider performs the OIDC discovery of the identity provider detailed
+// by the auth method config and returns a Provider for it.
+func NewProvider(ctx context.Context, config *structs.ACLAuthMethodConfig) (*Provider, error) {
+	if config == nil {
+		return nil, errors.New("missing auth method config")
+	}
+
+	httpClient, err := newHTTPClient(config.DiscoveryCaPem)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		config:     config.Copy(),
+		httpClient: httpClient,
+	}
+
+	discoveryURL := strings.TrimSuffix(config.OIDCDiscoveryURL, "/")
+
+	var metadata providerMetadata
+	if err := p.getJSON(ctx, discoveryURL+discoveryPath, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %v", err)
+	}
+
+	// The issuer must match the discovery URL, so the provider cannot
+	// impersonate another issuer.
+	if strings.TrimSuffix(metadata.Issuer, "/") != discoveryURL {
+		return nil, fmt.Errorf("OIDC provider issuer %q does not match discovery URL %q",
+			metadata.Issuer, config.OIDCDiscoveryURL)
+	}
+	if metadata.AuthURL == "" || metadata.TokenURL == "" || metadata.JWKSURL == "" {
+		return nil, errors.New("OIDC provider metadata is missing required endpoints")
+	}
+	p.metadata = &metadata
+
+	return p, nil
+}
+
+// newHTTPClient returns the HTTP client used to communicate with the OIDC
+// provider. If CA certificates are passed, they are used instead of the
+// system pool to verify the provider.
+func newHTTPClient(caPEMs []string) (*http.Client, error) {
+	transport := cleanhttp.DefaultPooledTransport()
+
+	if len(caPEMs) > 0 {
+		pool := x509.NewCertPool()
+		for _, caPEM := range caPEMs {
+			if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+				return nil, errors.New("failed to parse discovery CA PEM")
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   requestTimeout,
+	}, nil
+}
+
+// AuthURL returns the URL of the identity provider which the user must visit
+// in order to authenticate. The identity provider redirects to the redirect
+// URI once the user has authenticated, passing the state and an
+// authorization code. The nonce is embedded within the ID token.
+func (p *Provider) AuthURL(redirectURI, state, nonce string) (string, error) {
+	if !helper.SliceStringContains(p.config.AllowedRedirectURIs, redirectURI) {
+		return "", fmt.Errorf("redirect URI %q is not allowed", redirectURI)
+	}
+	return p.oauth2Config(redirectURI).AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce)), nil
+}
+
+// Exchange exchanges the authorization code for an ID token, verifies it
+// and returns its claims. The nonce must match the nonce passed when
+// generating the auth URL.
+func (p *Provider) Exchange(ctx context.Context, code, redirectURI, nonce string) (map[string]interface{}, error) {
+	if !helper.SliceStringContains(p.config.AllowedRedirectURIs, redirectURI) {
+		return nil, fmt.Errorf("redirect URI %q is not allowed", redirectURI)
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.httpClient)
+	token, err := p.oauth2Config(redirectURI).Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("OIDC provider did not return an ID token")
+	}
+	return p.VerifyIDToken(ctx, rawIDToken, nonce)
+}
+
+// oauth2Config returns the OAuth2 client configuration of the provider.
+func (p *Provider) oauth2Config(redirectURI string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.config.OIDCClientID,
+		ClientSecret: p.config.OIDCClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.metadata.AuthURL,
+			TokenURL: p.metadata.TokenURL,
+		},
+		RedirectURL: redirectURI,
+		Scopes:      append([]string{"openid"}, p.config.OIDCScopes...),
+	}
+}
+
+// VerifyIDToken verifies the signature, issuer, audience, expiry and nonce
+// of the raw ID token and returns its claims.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (map[string]interface{}, error) {
+	token, err := jwt.ParseSigned(rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ID token: %v", err)
+	}
+	if len(token.Headers) != 1 {
+		return nil, errors.New("ID token must have exactly one signature")
+	}
+	header := token.Headers[0]
+
+	allowedAlgs := p.config.SigningAlgs
+	if len(allowedAlgs) == 0 {
+		allowedAlgs = []string{defaultSigningAlg}
+	}
+	if !helper.SliceStringContains(allowedAlgs, header.Algorithm) {
+		return nil, fmt.Errorf("ID token signed with unsupported algorithm %q", header.Algorithm)
+	}
+
+	keys, err := p.verificationKeys(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		registered jwt.Claims
+		claims     map[string]interface{}
+		verified   bool
+	)
+	for _, key := range keys {
+		if err := token.Claims(key, &registered, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, errors.New("failed to verify ID token signature")
+	}
+
+	expected := jwt.Expected{
+		Issuer: p.metadata.Issuer,
+		Time:   time.Now(),
+	}
+	if err := registered.ValidateWithLeeway(expected, jwt.DefaultLeeway); err != nil {
+		return nil, fmt.Errorf("invalid ID token: %v", err)
+	}
+	if registered.Expiry == nil {
+		return nil, errors.New("invalid ID token: missing expiry")
+	}
+
+	if !p.audienceValid(registered.Audience) {
+		return nil, errors.New("invalid ID token: audience does not match")
+	}
+
+	if claimNonce, _ := claims["nonce"].(string); claimNonce != nonce {
+		return nil, errors.New("invalid ID token: nonce does not match")
+	}
+
+	return claims, nil
+}
+
+// audienceValid checks the audience of the ID token. If the auth method
+// configures bound audiences, one of them must be present. Otherwise, the
+// audience must contain the OIDC client ID.
+func (p *Provider) audienceValid(audience jwt.Audience) bool {
+	if len(p.config.BoundAudiences) == 0 {
+		return audience.Contains(p.config.OIDCClientID)
+	}
+	for _, aud := range p.config.BoundAudiences {
+		if audience.Contains(aud) {
+			return true
+		}
+	}
+	return false
+}
+
+// verificationKeys returns the keys of the identity provider which may have
+// signed a token with the passed key ID. The key set is refreshed if it does
+// not contain the key ID and was not fetched within the minimum refresh
+// interval.
+func (p *Provider) verificationKeys(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
+	p.keySetLock.Lock()
+	defer p.keySetLock.Unlock()
+
+	if p.keySet != nil {
+		if keys := lookupKeys(p.keySet, keyID); len(keys) > 0 {
+			return keys, nil
+		}
+	}
+
+	if time.Since(p.keySetFetched) < keySetMinRefreshInterval {
+		return nil, fmt.Errorf("failed to find OIDC provider key %q", keyID)
+	}
+
+	// Record the attempt before fetching, so a failing provider is not
+	// retried on every request either.
+	p.keySetFetched = time.Now()
+
+	var keySet jose.JSONWebKeySet
+	if err := p.getJSON(ctx, p.metadata.JWKSURL, &keySet); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC provider keys: %v", err)
+	}
+	p.keySet = &keySet
+
+	keys := lookupKeys(p.keySet, keyID)
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("failed to find OIDC provider key %q", keyID)
+	}
+	return keys, nil
+}
+
+// lookupKeys returns the keys within the key set matching the key ID. All
+// keys are returned if the key ID is empty.
+func lookupKeys(keySet *jose.JSONWebKeySet, keyID string) []jose.JSONWebKey {
+	if keyID == "" {
+		return keySet.Keys
+	}
+	return keySet.Key(keyID)
+}
+
+// getJSON performs a GET request against the URL and decodes the JSON
+// response body into out.
+func (p *Provider) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ProviderCache caches the Provider of each auth method, so that the OIDC
+// discovery is not performed on every login. A cached Provider is replaced
+// when the auth method is modified.
+type ProviderCache struct {
+	providers map[string]*cachedProvider
+	lock      sync.Mutex
+
+	// discovery ensures a single OIDC discovery is in flight for each auth
+	// method version. It is performed without holding the lock, so a slow
+	// identity provider does not block logins using other auth methods.
+	discovery singleflight.Group
+}
+
+type cachedProvider struct {
+	provider    *Provider
+	modifyIndex uint64
+}
+
+// NewProviderCache returns an empty ProviderCache.
+func NewProviderCache() *ProviderCache {
+	return &ProviderCache{
+		providers: make(map[string]*cachedProvider),
+	}
+}
+
+// Get returns the Provider for the auth method, performing the OIDC
+// discovery if the auth method has not been seen before or has been
+// modified.
+func (c *ProviderCache) Get(ctx context.Context, authMethod *structs.ACLAuthMethod) (*Provider, error) {
+	if provider := c.lookup(authMethod); provider != nil {
+		return provider, nil
+	}
+
+	key := fmt.Sprintf("%s/%d", authMethod.Name, authMethod.ModifyIndex)
+	provider, err, _ := c.discovery.Do(key, func() (interface{}, error) {
+		if provider := c.lookup(authMethod); provider != nil {
+			return provider, nil
+		}
+
+		provider, err := NewProvider(ctx, authMethod.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		// Do not replace the provider of a newer version of the auth method
+		// which was discovered concurrently.
+		if cached, ok := c.providers[authMethod.Name]; !ok || cached.modifyIndex <= authMethod.ModifyIndex {
+			c.providers[authMethod.Name] = &cachedProvider{
+				provider:    provider,
+				modifyIndex: authMethod.ModifyIndex,
+			}
+		}
+		return provider, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return provider.(*Provider), nil
+}
+
+// lookup returns the cached Provider for the version of the auth method, or
+// nil if it has not been discovered.
+func (c *ProviderCache) lookup(authMethod *structs.ACLAuthMethod) *Provider {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cached, ok := c.providers[authMethod.Name]; ok && cached.modifyIndex == authMethod.ModifyIndex {
+		return cached.provider
+	}
+	return nil
+}
+
+// Delete removes the Provider of the named auth method from the cache.
+func (c *ProviderCache) Delete(authMethodName string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.providers, authMethodName)
+}
@@ -0,0 +1,164 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testProviderConfig(testProvider *TestProvider) *structs.ACLAuthMethodConfig {
+	return &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    testProvider.Issuer(),
+		OIDCClientID:        testProvider.ClientID(),
+		OIDCClientSecret:    testProvider.ClientSecret(),
+		AllowedRedirectURIs: []string{"http://127.0.0.1:4649/oidc/callback"},
+	}
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	ci.Parallel(t)
+
+	testProvider := NewTestProvider(t)
+	config := testProviderConfig(testProvider)
+	redirectURI := config.AllowedRedirectURIs[0]
+
+	provider, err := NewProvider(context.Background(), config)
+	require.NoError(t, err)
+
+	// A redirect URI which is not allowed should be rejected.
+	_, err = provider.AuthURL("http://evil.example.com", "state", "nonce")
+	require.ErrorContains(t, err, "is not allowed")
+
+	nonce := uuid.Generate()
+	authURL, err := provider.AuthURL(redirectURI, "my-state", nonce)
+	require.NoError(t, err)
+
+	parsedURL, err := url.Parse(authURL)
+	require.NoError(t, err)
+	require.Equal(t, testProvider.Issuer()+"/authorize", parsedURL.Scheme+"://"+parsedURL.Host+parsedURL.Path)
+	require.Equal(t, nonce, parsedURL.Query().Get("nonce"))
+	require.Equal(t, "my-state", parsedURL.Query().Get("state"))
+	require.Equal(t, testProvider.ClientID(), parsedURL.Query().Get("client_id"))
+
+	// Visit the auth URL, which redirects with the authorization code.
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := httpClient.Get(authURL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	redirect, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "my-state", redirect.Query().Get("state"))
+	code := redirect.Query().Get("code")
+	require.Equal(t, testProvider.ExpectedAuthCode(), code)
+
+	testProvider.SetCustomClaims(map[string]interface{}{"team": "platform"})
+
+	// Exchanging the code with the wrong nonce should fail.
+	_, err = provider.Exchange(context.Background(), code, redirectURI, "wrong-nonce")
+	require.ErrorContains(t, err, "nonce does not match")
+
+	// Exchanging an invalid code should fail.
+	_, err = provider.Exchange(context.Background(), "invalid-code", redirectURI, nonce)
+	require.ErrorContains(t, err, "failed to exchange authorization code")
+
+	claims, err := provider.Exchange(context.Background(), code, redirectURI, nonce)
+	require.NoError(t, err)
+	require.Equal(t, "platform", claims["team"])
+	require.Equal(t, "alice@example.com", claims["sub"])
+
+	// An ID token which does not contain the bound audience should fail.
+	config.BoundAudiences = []string{"nomad"}
+	provider, err = NewProvider(context.Background(), config)
+	require.NoError(t, err)
+	_, err = provider.Exchange(context.Background(), code, redirectURI, nonce)
+	require.ErrorContains(t, err, "audience does not match")
+
+	testProvider.SetAudience("nomad")
+	_, err = provider.Exchange(context.Background(), code, redirectURI, nonce)
+	require.NoError(t, err)
+
+	// An ID token signed with an algorithm which is not allowed should fail.
+	config.SigningAlgs = []string{"ES256"}
+	provider, err = NewProvider(context.Background(), config)
+	require.NoError(t, err)
+	_, err = provider.Exchange(context.Background(), code, redirectURI, nonce)
+	require.ErrorContains(t, err, "unsupported algorithm")
+}
+
+func TestNewProvider_IssuerMismatch(t *testing.T) {
+	ci.Parallel(t)
+
+	testProvider := NewTestProvider(t)
+	config := testProviderConfig(testProvider)
+	config.OIDCDiscoveryURL = testProvider.Issuer() + "/other"
+
+	_, err := NewProvider(context.Background(), config)
+	require.Error(t, err)
+}
+
+func TestProvider_VerificationKeys_RateLimit(t *testing.T) {
+	ci.Parallel(t)
+
+	testProvider := NewTestProvider(t)
+	provider, err := NewProvider(context.Background(), testProviderConfig(testProvider))
+	require.NoError(t, err)
+
+	// The first lookup fetches the key set.
+	_, err = provider.verificationKeys(context.Background(), "unknown-key")
+	require.ErrorContains(t, err, "failed to find OIDC provider key")
+	require.Equal(t, 1, testProvider.KeySetRequests())
+
+	// Lookups of unknown keys within the minimum refresh interval must not
+	// fetch the key set again.
+	for i := 0; i < 5; i++ {
+		_, err = provider.verificationKeys(context.Background(), "unknown-key")
+		require.ErrorContains(t, err, "failed to find OIDC provider key")
+	}
+	require.Equal(t, 1, testProvider.KeySetRequests())
+
+	// Once the interval has passed, the key set is refreshed.
+	provider.keySetLock.Lock()
+	provider.keySetFetched = time.Now().Add(-keySetMinRefreshInterval)
+	provider.keySetLock.Unlock()
+
+	_, err = provider.verificationKeys(context.Background(), "unknown-key")
+	require.ErrorContains(t, err, "failed to find OIDC provider key")
+	require.Equal(t, 2, testProvider.KeySetRequests())
+}
+
+func TestProviderCache(t *testing.T) {
+	ci.Parallel(t)
+
+	testProvider := NewTestProvider(t)
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Config = testProviderConfig(testProvider)
+
+	cache := NewProviderCache()
+
+	provider1, err := cache.Get(context.Background(), authMethod)
+	require.NoError(t, err)
+	provider2, err := cache.Get(context.Background(), authMethod)
+	require.NoError(t, err)
+	require.Same(t, provider1, provider2)
+
+	// Modifying the auth method should result in a new provider.
+	authMethod.ModifyIndex++
+	provider3, err := cache.Get(context.Background(), authMethod)
+	require.NoError(t, err)
+	require.NotSame(t, provider1, provider3)
+
+	cache.Delete(authMethod.Name)
+	provider4, err := cache.Get(context.Background(), authMethod)
+	require.NoError(t, err)
+	require.NotSame(t, provider3, provider4)
+}
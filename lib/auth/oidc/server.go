@@ -0,0 +1,119 @@
+package oidc
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+)
+
+const (
+	// CallbackPath is the path of the callback server which the identity
+	// provider redirects to once the user has authenticated.
+	CallbackPath = "/oidc/callback"
+
+	callbackSuccessPage = `<!DOCTYPE html>
+<html>
+<head><title>Nomad OIDC Login</title></head>
+<body><p>Signed in via your OIDC provider. You can now close this window and return to the terminal.</p></body>
+</html>
+`
+)
+
+// CallbackResult holds the parameters passed by the identity provider to the
+// callback server.
+type CallbackResult struct {
+	State string
+	Code  string
+}
+
+// CallbackServer is started by the CLI login command and receives the
+// redirect of the identity provider once the user has authenticated.
+type CallbackServer struct {
+	ln          net.Listener
+	server      *http.Server
+	redirectURI string
+	nonce       string
+
+	successCh chan *CallbackResult
+	errCh     chan error
+}
+
+// NewCallbackServer starts a callback server listening on the passed
+// address. The caller must call Close once the login has completed.
+func NewCallbackServer(addr string) (*CallbackServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start OIDC callback server: %v", err)
+	}
+
+	s := &CallbackServer{
+		ln:          ln,
+		redirectURI: fmt.Sprintf("http://%s%s", addr, CallbackPath),
+		nonce:       uuid.Generate(),
+		successCh:   make(chan *CallbackResult, 1),
+		errCh:       make(chan error, 1),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(CallbackPath, s.handleCallback)
+	s.server = &http.Server{Handler: mux}
+
+	go func() { _ = s.server.Serve(ln) }()
+	return s, nil
+}
+
+// RedirectURI is the URI which must be passed to the identity provider, so it
+// redirects to this callback server.
+func (s *CallbackServer) RedirectURI() string { return s.redirectURI }
+
+// Nonce is the randomly generated client nonce of this login.
+func (s *CallbackServer) Nonce() string { return s.nonce }
+
+// SuccessCh receives the callback parameters once the identity provider has
+// redirected to the callback server.
+func (s *CallbackServer) SuccessCh() <-chan *CallbackResult { return s.successCh }
+
+// ErrorCh receives any error returned by the identity provider.
+func (s *CallbackServer) ErrorCh() <-chan error { return s.errCh }
+
+// Close stops the callback server.
+func (s *CallbackServer) Close() error { return s.server.Close() }
+
+func (s *CallbackServer) handleCallback(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+
+	if errCode := q.Get("error"); errCode != "" {
+		err := fmt.Errorf("OIDC provider returned an error: %s: %s", errCode, q.Get("error_description"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		s.sendError(err)
+		return
+	}
+
+	result := &CallbackResult{
+		State: q.Get("state"),
+		Code:  q.Get("code"),
+	}
+	if result.State == "" || result.Code == "" {
+		err := fmt.Errorf("OIDC provider callback is missing the state or code")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		s.sendError(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(callbackSuccessPage))
+
+	select {
+	case s.successCh <- result:
+	default:
+	}
+}
+
+func (s *CallbackServer) sendError(err error) {
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
@@ -0,0 +1,245 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// TestProvider is a minimal OIDC identity provider used within tests. It
+// implements the discovery, authorization, token and key set endpoints, and
+// signs ID tokens containing the configured claims.
+type TestProvider struct {
+	t      testing.TB
+	server *httptest.Server
+
+	key   *rsa.PrivateKey
+	keyID string
+
+	clientID     string
+	clientSecret string
+
+	lock         sync.Mutex
+	expectedCode string
+	nonce        string
+	subject      string
+	audience     []string
+	customClaims map[string]interface{}
+	keySetGets   int
+}
+
+// NewTestProvider starts a TestProvider. The provider is stopped when the
+// test completes.
+func NewTestProvider(t testing.TB) *TestProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate test provider key: %v", err)
+	}
+
+	p := &TestProvider{
+		t:            t,
+		key:          key,
+		keyID:        uuid.Generate(),
+		clientID:     "nomad-test-client",
+		clientSecret: uuid.Generate(),
+		expectedCode: uuid.Generate(),
+		subject:      "alice@example.com",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, p.handleDiscovery)
+	mux.HandleFunc("/authorize", p.handleAuthorize)
+	mux.HandleFunc("/token", p.handleToken)
+	mux.HandleFunc("/jwks", p.handleJWKS)
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// Issuer returns the issuer and discovery URL of the provider.
+func (p *TestProvider) Issuer() string { return p.server.URL }
+
+// ClientID returns the OAuth client ID accepted by the provider.
+func (p *TestProvider) ClientID() string { return p.clientID }
+
+// ClientSecret returns the OAuth client secret accepted by the provider.
+func (p *TestProvider) ClientSecret() string { return p.clientSecret }
+
+// ExpectedAuthCode returns the authorization code accepted by the token
+// endpoint and passed to the redirect URI by the authorization endpoint.
+func (p *TestProvider) ExpectedAuthCode() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.expectedCode
+}
+
+// SetExpectedAuthNonce sets the nonce embedded within the ID tokens. It is
+// also set when the authorization endpoint is visited.
+func (p *TestProvider) SetExpectedAuthNonce(nonce string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.nonce = nonce
+}
+
+// SetAudience overrides the audience of the ID tokens, which defaults to the
+// client ID.
+func (p *TestProvider) SetAudience(audience ...string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.audience = audience
+}
+
+// SetCustomClaims sets additional claims to embed within the ID tokens.
+func (p *TestProvider) SetCustomClaims(claims map[string]interface{}) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.customClaims = claims
+}
+
+// KeySetRequests returns the number of times the key set has been fetched.
+func (p *TestProvider) KeySetRequests() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.keySetGets
+}
+
+func (p *TestProvider) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	p.writeJSON(w, map[string]interface{}{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.Issuer() + "/authorize",
+		"token_endpoint":                        p.Issuer() + "/token",
+		"jwks_uri":                              p.Issuer() + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{string(jose.RS256)},
+	})
+}
+
+// handleAuthorize immediately redirects to the redirect URI as if the user
+// had authenticated.
+func (p *TestProvider) handleAuthorize(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	if q.Get("client_id") != p.clientID {
+		http.Error(w, "invalid client ID", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect URI", http.StatusBadRequest)
+		return
+	}
+
+	p.SetExpectedAuthNonce(q.Get("nonce"))
+
+	redirectQuery := redirectURI.Query()
+	redirectQuery.Set("state", q.Get("state"))
+	redirectQuery.Set("code", p.ExpectedAuthCode())
+	redirectURI.RawQuery = redirectQuery.Encode()
+
+	http.Redirect(w, req, redirectURI.String(), http.StatusFound)
+}
+
+func (p *TestProvider) handleToken(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	clientID, clientSecret, ok := req.BasicAuth()
+	if !ok {
+		clientID, clientSecret = req.PostForm.Get("client_id"), req.PostForm.Get("client_secret")
+	}
+	if clientID != p.clientID || clientSecret != p.clientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		p.writeJSON(w, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if req.PostForm.Get("code") != p.ExpectedAuthCode() {
+		w.WriteHeader(http.StatusBadRequest)
+		p.writeJSON(w, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := p.signIDToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.writeJSON(w, map[string]interface{}{
+		"access_token": uuid.Generate(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (p *TestProvider) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	p.lock.Lock()
+	p.keySetGets++
+	p.lock.Unlock()
+
+	p.writeJSON(w, jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       &p.key.PublicKey,
+			KeyID:     p.keyID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}},
+	})
+}
+
+// signIDToken returns a signed ID token containing the configured claims.
+func (p *TestProvider) signIDToken() (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", p.keyID),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	audience := p.audience
+	if len(audience) == 0 {
+		audience = []string{p.clientID}
+	}
+
+	now := time.Now()
+	claims := jwt.Claims{
+		Issuer:    p.Issuer(),
+		Subject:   p.subject,
+		Audience:  audience,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(now.Add(5 * time.Minute)),
+	}
+
+	custom := map[string]interface{}{"nonce": p.nonce}
+	for k, v := range p.customClaims {
+		custom[k] = v
+	}
+
+	return jwt.Signed(signer).Claims(claims).Claims(custom).CompactSerialize()
+}
+
+func (p *TestProvider) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		p.t.Errorf("failed to encode test provider response: %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package nomad
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -31,10 +33,85 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 		return nil, err
 	}
 
+	// Workload identities are JWTs rather than the UUIDs of ACL tokens, so
+	// cannot be looked up as such.
+	if isWorkloadIdentity(secretID) {
+		return s.resolveWorkloadIdentity(snap, secretID)
+	}
+
 	// Resolve the ACL
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// isWorkloadIdentity returns whether the secret is formatted as a JWT, rather
+// than the UUID of an ACL token.
+func isWorkloadIdentity(secretID string) bool {
+	return strings.Count(secretID, ".") == 2
+}
+
+// resolveWorkloadIdentity is used to resolve an ACL object from a workload
+// identity signed by the leader. The identity is granted an implicit policy
+// which allows reading the variables of its own job.
+func (s *Server) resolveWorkloadIdentity(snap *state.StateSnapshot, token string) (*acl.ACL, error) {
+	claims, err := s.workloadIdentityClaims(snap, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compile and cache the ACL object for the job, which is shared by all of
+	// its workloads.
+	cacheKey := "workload-identity:" + claims.Namespace + ":" + claims.JobID
+	if aclRaw, ok := s.aclCache.Get(cacheKey); ok {
+		return aclRaw.(*acl.ACL), nil
+	}
+
+	aclObj, err := acl.NewACL(false, []*acl.Policy{workloadIdentityPolicy(claims)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct ACL: %v", err)
+	}
+	s.aclCache.Add(cacheKey, aclObj)
+	return aclObj, nil
+}
+
+// workloadIdentityClaims verifies the workload identity and returns its
+// claims. The identity is only valid while the allocation it was signed for
+// is not terminal.
+func (s *Server) workloadIdentityClaims(snap *state.StateSnapshot, token string) (*structs.IdentityClaims, error) {
+	claims, err := s.encrypter.VerifyClaims(token)
+	if err != nil {
+		s.logger.Debug("failed to verify workload identity", "error", err)
+		return nil, structs.ErrTokenNotFound
+	}
+
+	alloc, err := snap.AllocByID(nil, claims.AllocationID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil || alloc.TerminalStatus() ||
+		alloc.Namespace != claims.Namespace || alloc.JobID != claims.JobID {
+		return nil, structs.ErrTokenNotFound
+	}
+	return claims, nil
+}
+
+// workloadIdentityPolicy returns the implicit policy of a workload identity,
+// which allows reading the variables of its job.
+func workloadIdentityPolicy(claims *structs.IdentityClaims) *acl.Policy {
+	jobPath := structs.VariablesJobPath(claims.JobID)
+	capabilities := []string{acl.VariablesCapabilityRead, acl.VariablesCapabilityList}
+	return &acl.Policy{
+		Namespaces: []*acl.NamespacePolicy{{
+			Name: claims.Namespace,
+			Variables: &acl.VariablesPolicy{
+				Paths: []*acl.VariablesPathPolicy{
+					{PathSpec: jobPath, Capabilities: capabilities},
+					{PathSpec: jobPath + "/*", Capabilities: capabilities},
+				},
+			},
+		}},
+	}
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a snapshot of state,
 // using a cache to avoid parsing and ACL construction when possible. It is split from resolveToken
 // to simplify testing.
@@ -54,6 +131,9 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		if token == nil {
 			return nil, structs.ErrTokenNotFound
 		}
+		if token.IsExpired(time.Now().UTC()) {
+			return nil, structs.ErrTokenExpired
+		}
 	}
 
 	// Check if this is a management token
@@ -61,9 +141,17 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		return acl.ManagementACL, nil
 	}
 
+	// Get the names of all associated policies, including those linked via
+	// the token's roles. Roles are resolved on each request so that an update
+	// to a role takes effect on all its linked tokens immediately.
+	policyNames, err := tokenPolicyNames(snap, token)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all associated policies
-	policies := make([]*structs.ACLPolicy, 0, len(token.Policies))
-	for _, policyName := range token.Policies {
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for _, policyName := range policyNames {
 		policy, err := snap.ACLPolicyByName(nil, policyName)
 		if err != nil {
 			return nil, err
@@ -85,6 +173,41 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 	return aclObj, nil
 }
 
+// tokenPolicyNames returns the de-duplicated names of the policies the token
+// is linked to, either directly or via its roles. Roles that do not exist are
+// ignored, since they don't grant any more privilege.
+func tokenPolicyNames(snap *state.StateSnapshot, token *structs.ACLToken) ([]string, error) {
+	if len(token.Roles) == 0 {
+		return token.Policies, nil
+	}
+
+	seen := make(map[string]struct{}, len(token.Policies))
+	names := make([]string, 0, len(token.Policies))
+	add := func(name string) {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	for _, policyName := range token.Policies {
+		add(policyName)
+	}
+	for _, roleLink := range token.Roles {
+		role, err := snap.GetACLRoleByID(nil, roleLink.ID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		for _, policyLink := range role.Policies {
+			add(policyLink.Name)
+		}
+	}
+	return names, nil
+}
+
 // ResolveSecretToken is used to translate an ACL Token Secret ID into
 // an ACLToken object, nil if ACLs are disabled, or an error.
 func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error) {
@@ -1,6 +1,10 @@
 package nomad
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -15,6 +19,8 @@ import (
 	policy "github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -29,6 +35,10 @@ const (
 	// aclBootstrapReset is the file name to create in the data dir. It's only contents
 	// should be the reset index
 	aclBootstrapReset = "acl-bootstrap-reset"
+
+	// oidcLoginStateTTL is how long the user has to authenticate with the
+	// identity provider once an OIDC login has been started.
+	oidcLoginStateTTL = 10 * time.Minute
 )
 
 // ACL endpoint is used for manipulating ACL tokens and policies
@@ -148,8 +158,13 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 			return structs.ErrTokenNotFound
 		}
 
-		policies = make(map[string]struct{}, len(token.Policies))
-		for _, p := range token.Policies {
+		policyNames, err := a.requestACLTokenPolicyNames(token)
+		if err != nil {
+			return err
+		}
+
+		policies = make(map[string]struct{}, len(policyNames))
+		for _, p := range policyNames {
 			policies[p] = struct{}{}
 		}
 	}
@@ -232,8 +247,13 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.S
 			return structs.ErrTokenNotFound
 		}
 
+		policyNames, err := a.requestACLTokenPolicyNames(token)
+		if err != nil {
+			return err
+		}
+
 		found := false
-		for _, p := range token.Policies {
+		for _, p := range policyNames {
 			if p == args.Name {
 				found = true
 				break
@@ -289,7 +309,24 @@ func (a *ACL) requestACLToken(secretID string) (*structs.ACLToken, error) {
 		return nil, err
 	}
 
-	return snap.ACLTokenBySecretID(nil, secretID)
+	token, err := snap.ACLTokenBySecretID(nil, secretID)
+	if err != nil {
+		return nil, err
+	}
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, structs.ErrTokenExpired
+	}
+	return token, nil
+}
+
+// requestACLTokenPolicyNames returns the names of the policies the token is
+// linked to, either directly or via its roles.
+func (a *ACL) requestACLTokenPolicyNames(token *structs.ACLToken) ([]string, error) {
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return tokenPolicyNames(snap, token)
 }
 
 // GetPolicies is used to get a set of policies
@@ -314,7 +351,15 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 		return structs.ErrTokenNotFound
 	}
 	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
-		return structs.ErrPermissionDenied
+		// The token may also query the policies linked to via its roles.
+		policyNames, err := a.requestACLTokenPolicyNames(token)
+		if err != nil {
+			return err
+		}
+		tokenWithRolePolicies := &structs.ACLToken{Type: token.Type, Policies: policyNames}
+		if !tokenWithRolePolicies.PolicySubset(args.Names) {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Setup the blocking query
@@ -513,31 +558,52 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 
 	// Validate each token
 	for idx, token := range args.Tokens {
-		if err := token.Validate(); err != nil {
-			return structs.NewErrRPCCodedf(400, "token %d invalid: %v", idx, err)
-		}
 
-		// Generate an accessor and secret ID if new
-		if token.AccessorID == "" {
-			token.AccessorID = uuid.Generate()
-			token.SecretID = uuid.Generate()
-			token.CreateTime = time.Now().UTC()
+		// Store any existing token found, so we can perform the correct
+		// update validation.
+		var existingToken *structs.ACLToken
 
+		// Generate an accessor and secret ID if new, otherwise lookup the
+		// existing token.
+		isNew := token.AccessorID == ""
+		if isNew {
+			token.Canonicalize()
 		} else {
-			// Verify the token exists
-			out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
+			existingToken, err = state.ACLTokenByAccessorID(nil, token.AccessorID)
 			if err != nil {
 				return structs.NewErrRPCCodedf(400, "token lookup failed: %v", err)
 			}
-			if out == nil {
+		}
+
+		if err := token.Validate(a.srv.config.ACLTokenMinExpirationTTL,
+			a.srv.config.ACLTokenMaxExpirationTTL, existingToken); err != nil {
+			return structs.NewErrRPCCodedf(400, "token %d invalid: %v", idx, err)
+		}
+
+		if !isNew {
+			// Verify the token exists
+			if existingToken == nil {
 				return structs.NewErrRPCCodedf(404, "cannot find token %s", token.AccessorID)
 			}
 
 			// Cannot toggle the "Global" mode
-			if token.Global != out.Global {
+			if token.Global != existingToken.Global {
 				return structs.NewErrRPCCodedf(400, "cannot toggle global mode of %s", token.AccessorID)
 			}
+
+			// The expiration of a token cannot be modified, so carry it over
+			// from the existing token.
+			token.ExpirationTime = existingToken.ExpirationTime
+			token.ExpirationTTL = existingToken.ExpirationTTL
+		}
+
+		// Resolve the role links, which may reference a role by its ID or
+		// name, to the role ID.
+		roleLinks, err := resolveTokenRoleLinks(state, token.Roles)
+		if err != nil {
+			return structs.NewErrRPCCodedf(400, "token %d invalid: %v", idx, err)
 		}
+		token.Roles = roleLinks
 
 		// Compute the token hash
 		token.SetHash()
@@ -1022,3 +1088,1366 @@ func (a *ACL) ExpireOneTimeTokens(args *structs.OneTimeTokenExpireRequest, reply
 	reply.Index = index
 	return nil
 }
+
+// resolveTokenRoleLinks resolves the role links of a token, which may
+// reference a role by its ID or name, to links which only hold the role ID.
+// The role name is discarded since operators can rename roles. An error is
+// returned if a linked role does not exist.
+func resolveTokenRoleLinks(snap *state.StateSnapshot, roleLinks []*structs.ACLTokenRoleLink) ([]*structs.ACLTokenRoleLink, error) {
+	if len(roleLinks) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(roleLinks))
+	resolved := make([]*structs.ACLTokenRoleLink, 0, len(roleLinks))
+
+	for _, roleLink := range roleLinks {
+		var (
+			role *structs.ACLRole
+			err  error
+		)
+
+		// The ID takes precedence over the name, since it is immutable.
+		switch {
+		case roleLink.ID != "":
+			role, err = snap.GetACLRoleByID(nil, roleLink.ID)
+		case roleLink.Name != "":
+			role, err = snap.GetACLRoleByName(nil, roleLink.Name)
+		default:
+			return nil, fmt.Errorf("role link must specify an ID or name")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("role lookup failed: %v", err)
+		}
+		if role == nil {
+			if roleLink.ID != "" {
+				return nil, fmt.Errorf("cannot find role %s", roleLink.ID)
+			}
+			return nil, fmt.Errorf("cannot find role %s", roleLink.Name)
+		}
+
+		// Drop duplicate links, which may occur if the same role is linked
+		// via both its ID and name.
+		if _, ok := seen[role.ID]; ok {
+			continue
+		}
+		seen[role.ID] = struct{}{}
+		resolved = append(resolved, &structs.ACLTokenRoleLink{ID: role.ID})
+	}
+
+	return resolved, nil
+}
+
+// UpsertRoles is used to create or update a set of ACL roles. Roles are
+// matched by their ID; a role without an ID is created.
+func (a *ACL) UpsertRoles(
+	args *structs.ACLRolesUpsertRequest,
+	reply *structs.ACLRolesUpsertResponse) error {
+
+	// Ensure ACLs are enabled, and always flow modification requests to the
+	// authoritative region.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertRolesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.ACLRoles) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one role")
+	}
+
+	// Snapshot the state so we can perform lookups against the ID and policy
+	// links if needed.
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each role and compute the hash
+	for idx, role := range args.ACLRoles {
+		if err := role.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "role %d invalid: %v", idx, err)
+		}
+
+		// Ensure the policies linked to from the role exist within state.
+		if !args.AllowMissingPolicies {
+			if err := stateSnapshot.ValidateACLRolePolicyLinks(role); err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "role %d invalid: %v", idx, err)
+			}
+		}
+
+		// If the caller has passed a role ID, this call is considered an
+		// update to an existing role. We should therefore ensure it is found
+		// within state.
+		if role.ID != "" {
+			out, err := stateSnapshot.GetACLRoleByID(nil, role.ID)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "role lookup failed: %v", err)
+			}
+			if out == nil {
+				return structs.NewErrRPCCodedf(http.StatusNotFound, "cannot find role %s", role.ID)
+			}
+		}
+
+		// Ensure the name is not already in use by another role.
+		existing, err := stateSnapshot.GetACLRoleByName(nil, role.Name)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "role lookup failed: %v", err)
+		}
+		if existing != nil && existing.ID != role.ID {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "role with name %s already exists", role.Name)
+		}
+
+		role.Canonicalize()
+		role.SetHash()
+	}
+
+	// Update via Raft
+	out, index, err := a.srv.raftApply(structs.ACLRolesUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, role := range args.ACLRoles {
+		lookupRole, err := stateSnapshot.GetACLRoleByID(nil, role.ID)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "role lookup failed: %v", err)
+		}
+		reply.ACLRoles = append(reply.ACLRoles, lookupRole)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteRolesByID is used to batch delete ACL roles using the ID as the
+// deletion key. Tokens linked to a deleted role lose the permissions granted
+// by it.
+func (a *ACL) DeleteRolesByID(
+	args *structs.ACLRolesDeleteByIDRequest,
+	reply *structs.ACLRolesDeleteByIDResponse) error {
+
+	// Ensure ACLs are enabled, and always flow modification requests to the
+	// authoritative region.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteRolesByIDRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.ACLRoleIDs) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one role")
+	}
+
+	// Update via Raft
+	out, index, err := a.srv.raftApply(structs.ACLRolesDeleteByIDRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return structs.NewErrRPCCoded(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListRoles is used to list ACL roles within state. A management token can
+// list all roles; any other token can only list the roles it is linked to.
+func (a *ACL) ListRoles(
+	args *structs.ACLRolesListRequest,
+	reply *structs.ACLRolesListResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLListRolesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_roles"}, time.Now())
+
+	// Resolve the token and ensure it has some form of permissions.
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine the roles that may be listed
+	mgt := acl.IsManagement()
+	roles := make(map[string]struct{})
+	if !mgt {
+		token, err := a.requestACLToken(args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+		for _, roleLink := range token.Roles {
+			roles[roleLink.ID] = struct{}{}
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			var (
+				err  error
+				iter memdb.ResultIterator
+			)
+
+			// If the operator supplied a prefix, perform a prefix search.
+			// Otherwise, list all ACL roles in state.
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = stateStore.GetACLRoleByIDPrefix(ws, prefix)
+			} else {
+				iter, err = stateStore.GetACLRoles(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Iterate all the results returned from state and convert them to
+			// the list stub format.
+			var stubs []*structs.ACLRoleListStub
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				role := raw.(*structs.ACLRole)
+				if _, ok := roles[role.ID]; ok || mgt {
+					stubs = append(stubs, role.Stub())
+				}
+			}
+
+			// Populate the response using the stubs, ensuring a non-nil
+			// array is returned.
+			reply.ACLRoles = stubs
+			if reply.ACLRoles == nil {
+				reply.ACLRoles = []*structs.ACLRoleListStub{}
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLRoles, &reply.QueryMeta)
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRolesByID is used to get a set of ACL roles that are identified by
+// their ID. This is primarily used by the ACL role replication process and
+// by clients resolving the roles linked to a token.
+func (a *ACL) GetRolesByID(
+	args *structs.ACLRolesByIDRequest,
+	reply *structs.ACLRolesByIDResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLGetRolesByIDRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_roles_id"}, time.Now())
+
+	// For client typed tokens, allow them to query any roles associated with
+	// that token. This is used by clients which are resolving the roles to
+	// enforce.
+	token, err := a.requestACLToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+	if token.Type != structs.ACLManagementToken && !tokenRoleSubset(token, args.ACLRoleIDs) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.ACLRoles = make(map[string]*structs.ACLRole, len(args.ACLRoleIDs))
+
+			// Look for the ACL role and add this to our mapping if we have
+			// found it.
+			for _, roleID := range args.ACLRoleIDs {
+				out, err := stateStore.GetACLRoleByID(ws, roleID)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.ACLRoles[out.ID] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLRoles, &reply.QueryMeta)
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// tokenRoleSubset checks if the given set of role IDs are all linked to the
+// token.
+func tokenRoleSubset(token *structs.ACLToken, roleIDs []string) bool {
+	linked := make(map[string]struct{}, len(token.Roles))
+	for _, roleLink := range token.Roles {
+		linked[roleLink.ID] = struct{}{}
+	}
+	for _, roleID := range roleIDs {
+		if _, ok := linked[roleID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// GetRoleByID is used to look up an individual ACL role using its ID.
+func (a *ACL) GetRoleByID(
+	args *structs.ACLRoleByIDRequest,
+	reply *structs.ACLRoleByIDResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLGetRoleByIDRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role_id"}, time.Now())
+
+	// Resolve the token and ensure it has some form of permissions.
+	token, err := a.requestACLToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	// A client token can only read the roles it is linked to.
+	if token.Type != structs.ACLManagementToken && !tokenRoleSubset(token, []string{args.RoleID}) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the ACL role.
+			out, err := stateStore.GetACLRoleByID(ws, args.RoleID)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the ACL role was
+			// found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLRoles)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			// We didn't encounter an error looking up the index; set the ACL
+			// role on the reply and exit successfully.
+			reply.ACLRole = out
+			return nil
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRoleByName is used to look up an individual ACL role using its name.
+func (a *ACL) GetRoleByName(
+	args *structs.ACLRoleByNameRequest,
+	reply *structs.ACLRoleByNameResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLGetRoleByNameRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role_name"}, time.Now())
+
+	// Resolve the token and ensure it has some form of permissions.
+	token, err := a.requestACLToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the ACL role.
+			out, err := stateStore.GetACLRoleByName(ws, args.RoleName)
+			if err != nil {
+				return err
+			}
+
+			// A client token can only read the roles it is linked to. The
+			// check is performed here, since the ID of the role is only known
+			// once it has been looked up.
+			if out != nil && token.Type != structs.ACLManagementToken &&
+				!tokenRoleSubset(token, []string{out.ID}) {
+				return structs.ErrPermissionDenied
+			}
+
+			// Set the index correctly depending on whether the ACL role was
+			// found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLRoles)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			// We didn't encounter an error looking up the index; set the ACL
+			// role on the reply and exit successfully.
+			reply.ACLRole = out
+			return nil
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertAuthMethods is used to create or update a set of auth methods. Auth
+// methods are matched by their name.
+func (a *ACL) UpsertAuthMethods(
+	args *structs.ACLAuthMethodsUpsertRequest,
+	reply *structs.ACLAuthMethodsUpsertResponse) error {
+
+	// Ensure ACLs are enabled, and always flow modification requests to the
+	// authoritative region.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.AuthMethods) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one auth method")
+	}
+
+	// Snapshot the state so we can perform lookups against the existing auth
+	// methods.
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Only a single auth method can be the default. Track the default set by
+	// this request, so that it can be compared with the default in state.
+	var defaultMethod string
+
+	// Validate each auth method and compute the hash
+	for idx, authMethod := range args.AuthMethods {
+		if err := authMethod.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method %d invalid: %v", idx, err)
+		}
+
+		if authMethod.Default {
+			if defaultMethod != "" {
+				return structs.NewErrRPCCoded(http.StatusBadRequest, "only one auth method can be the default")
+			}
+			defaultMethod = authMethod.Name
+
+			existingDefault, err := stateSnapshot.GetDefaultACLAuthMethod(nil)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method lookup failed: %v", err)
+			}
+			if existingDefault != nil && existingDefault.Name != authMethod.Name {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest,
+					"default auth method already exists: %s", existingDefault.Name)
+			}
+		}
+
+		// Retain the create time of an existing auth method.
+		existing, err := stateSnapshot.GetACLAuthMethodByName(nil, authMethod.Name)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method lookup failed: %v", err)
+		}
+		if existing != nil {
+			authMethod.CreateTime = existing.CreateTime
+		}
+
+		authMethod.Canonicalize()
+		authMethod.SetHash()
+	}
+
+	// Update via Raft
+	out, index, err := a.srv.raftApply(structs.ACLAuthMethodsUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, authMethod := range args.AuthMethods {
+		lookupAuthMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, authMethod.Name)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method lookup failed: %v", err)
+		}
+		reply.AuthMethods = append(reply.AuthMethods, lookupAuthMethod)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to batch delete auth methods using their name.
+// The binding rules of the deleted auth methods are also deleted. Tokens
+// created by a deleted auth method remain valid until they expire.
+func (a *ACL) DeleteAuthMethods(
+	args *structs.ACLAuthMethodsDeleteRequest,
+	reply *structs.ACLAuthMethodsDeleteResponse) error {
+
+	// Ensure ACLs are enabled, and always flow modification requests to the
+	// authoritative region.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one auth method")
+	}
+
+	// Update via Raft
+	out, index, err := a.srv.raftApply(structs.ACLAuthMethodsDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return structs.NewErrRPCCoded(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+
+	for _, name := range args.Names {
+		a.srv.oidcProviderCache.Delete(name)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods within state. The listing
+// does not include the auth method configuration and does not require an ACL
+// token, so that users can discover the auth methods available to log in
+// with.
+func (a *ACL) ListAuthMethods(
+	args *structs.ACLAuthMethodsListRequest,
+	reply *structs.ACLAuthMethodsListResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLListAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			iter, err := stateStore.GetACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			// Iterate all the results returned from state and convert them to
+			// the list stub format, ensuring a non-nil array is returned.
+			reply.AuthMethods = []*structs.ACLAuthMethodStub{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.AuthMethods = append(reply.AuthMethods, raw.(*structs.ACLAuthMethod).Stub())
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLAuthMethods, &reply.QueryMeta)
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethod is used to look up an individual auth method using its name.
+// The auth method contains the OIDC client secret, so it can only be read by
+// management tokens.
+func (a *ACL) GetAuthMethod(
+	args *structs.ACLAuthMethodGetRequest,
+	reply *structs.ACLAuthMethodGetResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLGetAuthMethodRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method_name"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the auth method.
+			out, err := stateStore.GetACLAuthMethodByName(ws, args.MethodName)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the auth method
+			// was found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLAuthMethods)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			reply.AuthMethod = out
+			return nil
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethods is used to get a set of auth methods identified by their
+// name. This is used by the auth method replication process.
+func (a *ACL) GetAuthMethods(
+	args *structs.ACLAuthMethodsGetRequest,
+	reply *structs.ACLAuthMethodsGetResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLGetAuthMethodsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.AuthMethods = make(map[string]*structs.ACLAuthMethod, len(args.Names))
+
+			// Look for the auth method and add this to our mapping if we
+			// have found it.
+			for _, name := range args.Names {
+				out, err := stateStore.GetACLAuthMethodByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.AuthMethods[out.Name] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLAuthMethods, &reply.QueryMeta)
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertBindingRules is used to create or update a set of binding rules.
+// Binding rules are matched by their ID; a rule without an ID is created.
+func (a *ACL) UpsertBindingRules(
+	args *structs.ACLBindingRulesUpsertRequest,
+	reply *structs.ACLBindingRulesUpsertResponse) error {
+
+	// Ensure ACLs are enabled, and always flow modification requests to the
+	// authoritative region.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLUpsertBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.ACLBindingRules) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one binding rule")
+	}
+
+	// Snapshot the state so we can perform lookups against the ID and auth
+	// method if needed.
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule and compute the hash
+	for idx, bindingRule := range args.ACLBindingRules {
+		if err := bindingRule.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "binding rule %d invalid: %v", idx, err)
+		}
+
+		// Ensure the auth method the binding rule belongs to exists within
+		// state.
+		if !args.AllowMissingAuthMethods {
+			authMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, bindingRule.AuthMethod)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method lookup failed: %v", err)
+			}
+			if authMethod == nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest,
+					"binding rule %d invalid: cannot find auth method %s", idx, bindingRule.AuthMethod)
+			}
+		}
+
+		// If the caller has passed a rule ID, this call is considered an
+		// update to an existing rule. We should therefore ensure it is found
+		// within state, and retain its create time.
+		if bindingRule.ID != "" {
+			out, err := stateSnapshot.GetACLBindingRule(nil, bindingRule.ID)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "binding rule lookup failed: %v", err)
+			}
+			if out == nil {
+				return structs.NewErrRPCCodedf(http.StatusNotFound, "cannot find binding rule %s", bindingRule.ID)
+			}
+			bindingRule.CreateTime = out.CreateTime
+		}
+
+		bindingRule.Canonicalize()
+		bindingRule.SetHash()
+	}
+
+	// Update via Raft
+	out, index, err := a.srv.raftApply(structs.ACLBindingRulesUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, bindingRule := range args.ACLBindingRules {
+		lookupBindingRule, err := stateSnapshot.GetACLBindingRule(nil, bindingRule.ID)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "binding rule lookup failed: %v", err)
+		}
+		reply.ACLBindingRules = append(reply.ACLBindingRules, lookupBindingRule)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteBindingRules is used to batch delete binding rules using their ID.
+func (a *ACL) DeleteBindingRules(
+	args *structs.ACLBindingRulesDeleteRequest,
+	reply *structs.ACLBindingRulesDeleteResponse) error {
+
+	// Ensure ACLs are enabled, and always flow modification requests to the
+	// authoritative region.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward(structs.ACLDeleteBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.ACLBindingRuleIDs) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify as least one binding rule")
+	}
+
+	// Update via Raft
+	out, index, err := a.srv.raftApply(structs.ACLBindingRulesDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return structs.NewErrRPCCoded(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListBindingRules is used to list the binding rules within state.
+func (a *ACL) ListBindingRules(
+	args *structs.ACLBindingRulesListRequest,
+	reply *structs.ACLBindingRulesListResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLListBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			var (
+				err  error
+				iter memdb.ResultIterator
+			)
+
+			// If the operator supplied a prefix, perform a prefix search.
+			// Otherwise, list all binding rules in state.
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = stateStore.GetACLBindingRulesByIDPrefix(ws, prefix)
+			} else {
+				iter, err = stateStore.GetACLBindingRules(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Iterate all the results returned from state and convert them to
+			// the list stub format, ensuring a non-nil array is returned.
+			reply.ACLBindingRules = []*structs.ACLBindingRuleListStub{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.ACLBindingRules = append(reply.ACLBindingRules, raw.(*structs.ACLBindingRule).Stub())
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLBindingRules, &reply.QueryMeta)
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetBindingRule is used to look up an individual binding rule using its ID.
+func (a *ACL) GetBindingRule(
+	args *structs.ACLBindingRuleRequest,
+	reply *structs.ACLBindingRuleResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLGetBindingRuleRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Perform a lookup for the binding rule.
+			out, err := stateStore.GetACLBindingRule(ws, args.ACLBindingRuleID)
+			if err != nil {
+				return err
+			}
+
+			// Set the index correctly depending on whether the binding rule
+			// was found.
+			switch out {
+			case nil:
+				index, err := stateStore.Index(state.TableACLBindingRules)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			default:
+				reply.Index = out.ModifyIndex
+			}
+
+			reply.ACLBindingRule = out
+			return nil
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetBindingRules is used to get a set of binding rules identified by their
+// ID. This is used by the binding rule replication process.
+func (a *ACL) GetBindingRules(
+	args *structs.ACLBindingRulesRequest,
+	reply *structs.ACLBindingRulesResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward(structs.ACLGetBindingRulesRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			// Instantiate the output map to the correct maximum length.
+			reply.ACLBindingRules = make(map[string]*structs.ACLBindingRule, len(args.ACLBindingRuleIDs))
+
+			// Look for the binding rule and add this to our mapping if we
+			// have found it.
+			for _, ruleID := range args.ACLBindingRuleIDs {
+				out, err := stateStore.GetACLBindingRule(ws, ruleID)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.ACLBindingRules[out.ID] = out
+				}
+			}
+
+			// Use the index table to populate the query meta as we have no
+			// way of tracking the max index on deletes.
+			return a.srv.setReplyQueryMeta(stateStore, state.TableACLBindingRules, &reply.QueryMeta)
+		},
+	}
+
+	return a.srv.blockingRPC(&opts)
+}
+
+// OIDCAuthURL starts the OIDC login workflow. It returns the URL of the
+// identity provider of the auth method, which the user must visit in order to
+// authenticate. This does not require an ACL token.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC auth-url request: %v", err)
+	}
+
+	if done, err := a.srv.forward(structs.ACLOIDCAuthURLRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	authMethod, err := a.oidcAuthMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+
+	provider, err := a.srv.oidcProviderCache.Get(a.srv.shutdownCtx, authMethod)
+	if err != nil {
+		return err
+	}
+
+	// The state is passed back by the identity provider along with the
+	// authorization code. It binds the login to the client nonce, which is
+	// only known to the client, and carries the nonce embedded in the ID
+	// token, so both are verified when the login is completed.
+	state, nonce, err := a.newOIDCLoginState(authMethod.Name, args.RedirectURI, args.ClientNonce)
+	if err != nil {
+		return err
+	}
+
+	authURL, err := provider.AuthURL(args.RedirectURI, state, nonce)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC auth-url request: %v", err)
+	}
+
+	reply.AuthURL = authURL
+	return nil
+}
+
+// OIDCCompleteAuth completes the OIDC login workflow. The authorization code
+// returned by the identity provider is exchanged for an ID token, whose
+// claims are evaluated against the binding rules of the auth method. If any
+// rule matches, an ACL token which expires after the max token TTL of the
+// auth method is created. This does not require an ACL token.
+func (a *ACL) OIDCCompleteAuth(args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLLoginResponse) error {
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC complete-auth request: %v", err)
+	}
+
+	if done, err := a.srv.forward(structs.ACLOIDCCompleteAuthRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	authMethod, err := a.oidcAuthMethod(args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+
+	// Global tokens must be created within the authoritative region, so
+	// forward the request if needed.
+	if authMethod.TokenLocalityIsGlobal() && a.srv.config.Region != a.srv.config.AuthoritativeRegion {
+		args.Region = a.srv.config.AuthoritativeRegion
+		_, err := a.srv.forward(structs.ACLOIDCCompleteAuthRPCMethod, args, args, reply)
+		return err
+	}
+
+	provider, err := a.srv.oidcProviderCache.Get(a.srv.shutdownCtx, authMethod)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := a.verifyOIDCLoginState(args)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to complete OIDC login: %v", err)
+	}
+
+	claims, err := provider.Exchange(a.srv.shutdownCtx, args.Code, args.RedirectURI, nonce)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to complete OIDC login: %v", err)
+	}
+
+	identity, err := oidc.SelectorData(authMethod, claims)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to complete OIDC login: %v", err)
+	}
+
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	bindings, err := auth.NewBinder(stateSnapshot).Bind(authMethod, identity)
+	if err != nil {
+		return err
+	}
+	if bindings.None() {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "no role or policy bindings matched")
+	}
+
+	now := time.Now().UTC()
+	expirationTime := now.Add(authMethod.MaxTokenTTL)
+
+	token := &structs.ACLToken{
+		AccessorID:     uuid.Generate(),
+		SecretID:       uuid.Generate(),
+		Name:           "OIDC-" + authMethod.Name,
+		Type:           structs.ACLClientToken,
+		Policies:       bindings.Policies,
+		Roles:          bindings.Roles,
+		Global:         authMethod.TokenLocalityIsGlobal(),
+		CreateTime:     now,
+		ExpirationTime: &expirationTime,
+	}
+	if bindings.Management {
+		token.Type = structs.ACLManagementToken
+	}
+	token.SetHash()
+
+	tokenArgs := structs.ACLTokenUpsertRequest{
+		Tokens:       []*structs.ACLToken{token},
+		WriteRequest: structs.WriteRequest{Region: args.Region},
+	}
+
+	out, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, &tokenArgs)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to pick up the
+	// proper create / modify indexes.
+	stateSnapshot, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	reply.ACLToken, err = stateSnapshot.ACLTokenByAccessorID(nil, token.AccessorID)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// oidcAuthMethod looks up the named auth method and ensures it is an OIDC
+// auth method.
+func (a *ACL) oidcAuthMethod(name string) (*structs.ACLAuthMethod, error) {
+	stateSnapshot, err := a.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	authMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, name)
+	if err != nil {
+		return nil, err
+	}
+	if authMethod == nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusNotFound, "auth method %s not found", name)
+	}
+	if authMethod.Type != structs.ACLAuthMethodTypeOIDC {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest, "auth method %s is not an OIDC auth method", name)
+	}
+	return authMethod, nil
+}
+
+// oidcLoginState is the state of an OIDC login, which is passed to the
+// identity provider encrypted with the keyring and returned along with the
+// authorization code. Encrypting the state means any server can verify it.
+type oidcLoginState struct {
+	AuthMethod  string
+	RedirectURI string
+	ClientNonce string
+	Nonce       string
+	ExpiresAt   time.Time
+}
+
+// newOIDCLoginState returns the encrypted state of a new OIDC login, along
+// with the nonce which the identity provider must embed in the ID token.
+func (a *ACL) newOIDCLoginState(authMethod, redirectURI, clientNonce string) (string, string, error) {
+	loginState := oidcLoginState{
+		AuthMethod:  authMethod,
+		RedirectURI: redirectURI,
+		ClientNonce: clientNonce,
+		Nonce:       uuid.Generate(),
+		ExpiresAt:   time.Now().UTC().Add(oidcLoginStateTTL),
+	}
+
+	cleartext, err := json.Marshal(&loginState)
+	if err != nil {
+		return "", "", err
+	}
+	ciphertext, keyID, err := a.srv.encrypter.Encrypt(cleartext)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt OIDC login state: %v", err)
+	}
+
+	state := keyID + "." + base64.RawURLEncoding.EncodeToString(ciphertext)
+	return state, loginState.Nonce, nil
+}
+
+// verifyOIDCLoginState decrypts the state of the OIDC login and ensures it
+// was generated for the auth method, redirect URI and client nonce of the
+// request. It returns the nonce which must be embedded in the ID token.
+func (a *ACL) verifyOIDCLoginState(args *structs.ACLOIDCCompleteAuthRequest) (string, error) {
+	keyID, encoded, ok := strings.Cut(args.State, ".")
+	if !ok {
+		return "", errors.New("invalid state")
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.New("invalid state")
+	}
+	cleartext, err := a.srv.encrypter.Decrypt(ciphertext, keyID)
+	if err != nil {
+		return "", errors.New("invalid state")
+	}
+
+	var loginState oidcLoginState
+	if err := json.Unmarshal(cleartext, &loginState); err != nil {
+		return "", errors.New("invalid state")
+	}
+
+	if loginState.AuthMethod != args.AuthMethodName ||
+		loginState.RedirectURI != args.RedirectURI ||
+		subtle.ConstantTimeCompare([]byte(loginState.ClientNonce), []byte(args.ClientNonce)) != 1 {
+		return "", errors.New("state does not match the login request")
+	}
+	if time.Now().UTC().After(loginState.ExpiresAt) {
+		return "", errors.New("state has expired")
+	}
+	return loginState.Nonce, nil
+}
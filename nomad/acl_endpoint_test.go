@@ -3,6 +3,8 @@ package nomad
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -1507,6 +1510,74 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	assert.Equal(t, created, out)
 }
 
+func TestACLEndpoint_UpsertTokens_Expiration(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a token with a TTL outside the allowed bounds.
+	token := mock.ACLToken()
+	token.AccessorID = ""
+	token.ExpirationTTL = 100 * 24 * time.Hour
+
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{token},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.ErrorContains(t, err, "expiration time cannot be less than")
+
+	// Create a token with a valid TTL, which should have its expiration time
+	// set from the create time.
+	token.AccessorID = ""
+	token.ExpirationTime = nil
+	token.ExpirationTTL = 10 * time.Minute
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp))
+	require.Len(t, resp.Tokens, 1)
+
+	created := resp.Tokens[0]
+	require.NotNil(t, created.ExpirationTime)
+	require.Equal(t, created.CreateTime.Add(10*time.Minute), *created.ExpirationTime)
+	require.Equal(t, 10*time.Minute, created.ExpirationTTL)
+
+	// Updating the token without specifying the expiration should retain it.
+	update := created.Copy()
+	update.Name = "updated-name"
+	update.ExpirationTime = nil
+	update.ExpirationTTL = 0
+	req.Tokens = []*structs.ACLToken{update}
+
+	var updateResp structs.ACLTokenUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &updateResp))
+	require.Len(t, updateResp.Tokens, 1)
+	require.Equal(t, "updated-name", updateResp.Tokens[0].Name)
+	require.Equal(t, created.ExpirationTime, updateResp.Tokens[0].ExpirationTime)
+
+	// Updating the token TTL should fail.
+	update = created.Copy()
+	update.ExpirationTTL = time.Hour
+	req.Tokens = []*structs.ACLToken{update}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &updateResp)
+	require.ErrorContains(t, err, "cannot update expiration TTL")
+
+	// Once expired, the token should be rejected when resolved.
+	expiredTime := time.Now().UTC().Add(-time.Minute)
+	expired := created.Copy()
+	expired.ExpirationTime = &expiredTime
+	require.NoError(t, s1.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{expired}))
+
+	_, err = s1.ResolveToken(created.SecretID)
+	require.ErrorIs(t, err, structs.ErrTokenExpired)
+}
+
 func TestACLEndpoint_UpsertTokens_Invalid(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1685,3 +1756,781 @@ func TestACLEndpoint_OneTimeToken(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, ott)
 }
+
+func TestACLEndpoint_UpsertRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Create the ACL role without an ID, so that one is generated.
+	aclRole := mock.ACLRole()
+	aclRole.ID = ""
+	aclRoleReq := &structs.ACLRolesUpsertRequest{
+		ACLRoles: []*structs.ACLRole{aclRole},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRolesUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Len(t, aclRoleResp.ACLRoles, 1)
+	require.NotEmpty(t, aclRoleResp.ACLRoles[0].ID)
+	require.Equal(t, aclRole.Name, aclRoleResp.ACLRoles[0].Name)
+
+	// Update the role description using its generated ID.
+	updatedRole := aclRoleResp.ACLRoles[0].Copy()
+	updatedRole.Description = "updated-description"
+	aclRoleReq.ACLRoles = []*structs.ACLRole{updatedRole}
+	var aclRoleResp2 structs.ACLRolesUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq, &aclRoleResp2))
+	require.Len(t, aclRoleResp2.ACLRoles, 1)
+	require.Equal(t, "updated-description", aclRoleResp2.ACLRoles[0].Description)
+	require.Equal(t, aclRoleResp.ACLRoles[0].CreateIndex, aclRoleResp2.ACLRoles[0].CreateIndex)
+
+	// Creating a role which links to a policy that does not exist should fail.
+	invalidRole := mock.ACLRole()
+	invalidRole.ID = ""
+	invalidRole.Policies = []*structs.ACLRolePolicyLink{{Name: "not-a-policy"}}
+	aclRoleReq.ACLRoles = []*structs.ACLRole{invalidRole}
+	var aclRoleResp3 structs.ACLRolesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq, &aclRoleResp3)
+	require.ErrorContains(t, err, "policy not found")
+
+	// Creating a role using the name of an existing role should fail.
+	duplicateRole := mock.ACLRole()
+	duplicateRole.ID = ""
+	duplicateRole.Name = aclRole.Name
+	aclRoleReq.ACLRoles = []*structs.ACLRole{duplicateRole}
+	var aclRoleResp4 structs.ACLRolesUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq, &aclRoleResp4)
+	require.ErrorContains(t, err, "already exists")
+
+	// Updating a role which does not exist should fail.
+	aclRoleReq.ACLRoles = []*structs.ACLRole{mock.ACLRole()}
+	var aclRoleResp5 structs.ACLRolesUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq, &aclRoleResp5)
+	require.ErrorContains(t, err, "cannot find role")
+
+	// A non-management token should not be able to create roles.
+	aclRoleReq.ACLRoles = []*structs.ACLRole{invalidRole}
+	aclRoleReq.AuthToken = uuid.Generate()
+	var aclRoleResp6 structs.ACLRolesUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertRolesRPCMethod, aclRoleReq, &aclRoleResp6)
+	require.ErrorContains(t, err, structs.ErrTokenNotFound.Error())
+}
+
+func TestACLEndpoint_DeleteRolesByID(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Create two ACL roles and put these directly into state.
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// Delete one of the ACL roles.
+	aclRoleReq := &structs.ACLRolesDeleteByIDRequest{
+		ACLRoleIDs: []string{aclRoles[0].ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRolesDeleteByIDResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLDeleteRolesByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.NotZero(t, aclRoleResp.Index)
+
+	out, err := testServer.fsm.State().GetACLRoleByID(nil, aclRoles[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Try to delete the previously deleted ACL role; this should fail.
+	var aclRoleResp2 structs.ACLRolesDeleteByIDResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLDeleteRolesByIDRPCMethod, aclRoleReq, &aclRoleResp2)
+	require.ErrorContains(t, err, "ACL role not found")
+}
+
+func TestACLEndpoint_ListRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Create two ACL roles and put these directly into state.
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// A management token can list all roles.
+	aclRoleReq := &structs.ACLRolesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRolesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListRolesRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Len(t, aclRoleResp.ACLRoles, 2)
+
+	// A client token can only list the roles it is linked to.
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRoles[1].ID}}
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLToken{token}))
+
+	aclRoleReq.AuthToken = token.SecretID
+	var aclRoleResp2 structs.ACLRolesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLListRolesRPCMethod, aclRoleReq, &aclRoleResp2))
+	require.Len(t, aclRoleResp2.ACLRoles, 1)
+	require.Equal(t, aclRoles[1].ID, aclRoleResp2.ACLRoles[0].ID)
+}
+
+func TestACLEndpoint_GetRoleByID_GetRoleByName(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Create two ACL roles and put these directly into state.
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(structs.MsgTypeTestSetup, 20, aclRoles, false))
+
+	// Look up the first role by its ID.
+	aclRoleReq := &structs.ACLRoleByIDRequest{
+		RoleID: aclRoles[0].ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleResp structs.ACLRoleByIDResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByIDRPCMethod, aclRoleReq, &aclRoleResp))
+	require.Equal(t, aclRoles[0].Hash, aclRoleResp.ACLRole.Hash)
+
+	// Look up the second role by its name.
+	aclRoleNameReq := &structs.ACLRoleByNameRequest{
+		RoleName: aclRoles[1].Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var aclRoleNameResp structs.ACLRoleByNameResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByNameRPCMethod, aclRoleNameReq, &aclRoleNameResp))
+	require.Equal(t, aclRoles[1].Hash, aclRoleNameResp.ACLRole.Hash)
+
+	// A client token linked to the first role can only read that role.
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: aclRoles[0].ID}}
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLToken{token}))
+
+	aclRoleReq.AuthToken = token.SecretID
+	var aclRoleResp2 structs.ACLRoleByIDResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByIDRPCMethod, aclRoleReq, &aclRoleResp2))
+	require.Equal(t, aclRoles[0].ID, aclRoleResp2.ACLRole.ID)
+
+	aclRoleNameReq.AuthToken = token.SecretID
+	var aclRoleNameResp2 structs.ACLRoleByNameResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLGetRoleByNameRPCMethod, aclRoleNameReq, &aclRoleNameResp2)
+	require.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_UpsertTokens_Roles(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	aclRole := mock.ACLRole()
+	require.NoError(t, testServer.fsm.State().UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLRole{aclRole}, false))
+
+	// Create a token which links to the role by both its name and ID. The
+	// links should be resolved to a single link using the ID.
+	token := mock.ACLToken()
+	token.AccessorID = ""
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{Name: aclRole.Name}, {ID: aclRole.ID}}
+
+	tokenReq := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{token},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var tokenResp structs.ACLTokenUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", tokenReq, &tokenResp))
+	require.Len(t, tokenResp.Tokens, 1)
+	require.Equal(t, []*structs.ACLTokenRoleLink{{ID: aclRole.ID}}, tokenResp.Tokens[0].Roles)
+
+	// The token should be able to read the policies linked via its role.
+	policyReq := &structs.ACLPolicySetRequest{
+		Names: []string{policy1.Name, policy2.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: tokenResp.Tokens[0].SecretID,
+		},
+	}
+	var policyResp structs.ACLPolicySetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetPolicies", policyReq, &policyResp))
+	require.Len(t, policyResp.Policies, 2)
+
+	// Creating a token linked to a role which does not exist should fail.
+	invalidToken := mock.ACLToken()
+	invalidToken.AccessorID = ""
+	invalidToken.Policies = nil
+	invalidToken.Roles = []*structs.ACLTokenRoleLink{{Name: "not-a-role"}}
+	tokenReq.Tokens = []*structs.ACLToken{invalidToken}
+	var tokenResp2 structs.ACLTokenUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", tokenReq, &tokenResp2)
+	require.ErrorContains(t, err, "cannot find role not-a-role")
+}
+
+func TestACLEndpoint_UpsertAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create a default auth method.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Default = true
+	authMethodReq := &structs.ACLAuthMethodsUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{authMethod},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var authMethodResp structs.ACLAuthMethodsUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq, &authMethodResp))
+	require.Len(t, authMethodResp.AuthMethods, 1)
+	require.Equal(t, authMethod.Name, authMethodResp.AuthMethods[0].Name)
+	require.NotZero(t, authMethodResp.AuthMethods[0].CreateIndex)
+
+	// Update the auth method; the create time and index must be retained.
+	updatedAuthMethod := authMethodResp.AuthMethods[0].Copy()
+	updatedAuthMethod.MaxTokenTTL = 2 * time.Hour
+	authMethodReq.AuthMethods = []*structs.ACLAuthMethod{updatedAuthMethod}
+	var authMethodResp2 structs.ACLAuthMethodsUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq, &authMethodResp2))
+	require.Len(t, authMethodResp2.AuthMethods, 1)
+	require.Equal(t, 2*time.Hour, authMethodResp2.AuthMethods[0].MaxTokenTTL)
+	require.Equal(t, authMethodResp.AuthMethods[0].CreateIndex, authMethodResp2.AuthMethods[0].CreateIndex)
+	require.Equal(t, authMethodResp.AuthMethods[0].CreateTime, authMethodResp2.AuthMethods[0].CreateTime)
+
+	// Creating a second default auth method should fail.
+	secondDefault := mock.ACLAuthMethod()
+	secondDefault.Default = true
+	authMethodReq.AuthMethods = []*structs.ACLAuthMethod{secondDefault}
+	var authMethodResp3 structs.ACLAuthMethodsUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq, &authMethodResp3)
+	require.ErrorContains(t, err, "default auth method already exists")
+
+	// Creating an invalid auth method should fail.
+	invalidAuthMethod := mock.ACLAuthMethod()
+	invalidAuthMethod.MaxTokenTTL = 0
+	authMethodReq.AuthMethods = []*structs.ACLAuthMethod{invalidAuthMethod}
+	var authMethodResp4 structs.ACLAuthMethodsUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq, &authMethodResp4)
+	require.ErrorContains(t, err, "max token TTL must be greater than zero")
+
+	// A non-management token should not be able to create auth methods.
+	token := mock.ACLToken()
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLToken{token}))
+	authMethodReq.AuthMethods = []*structs.ACLAuthMethod{mock.ACLAuthMethod()}
+	authMethodReq.AuthToken = token.SecretID
+	var authMethodResp5 structs.ACLAuthMethodsUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertAuthMethodsRPCMethod, authMethodReq, &authMethodResp5)
+	require.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_DeleteAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create two auth methods and put these directly into state.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Delete the first auth method.
+	authMethodReq := &structs.ACLAuthMethodsDeleteRequest{
+		Names: []string{authMethods[0].Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var authMethodResp structs.ACLAuthMethodsDeleteResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLDeleteAuthMethodsRPCMethod, authMethodReq, &authMethodResp))
+	require.NotZero(t, authMethodResp.Index)
+
+	out, err := testServer.fsm.State().GetACLAuthMethodByName(nil, authMethods[0].Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Deleting the same auth method again should fail.
+	var authMethodResp2 structs.ACLAuthMethodsDeleteResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLDeleteAuthMethodsRPCMethod, authMethodReq, &authMethodResp2)
+	require.ErrorContains(t, err, "ACL auth method not found")
+}
+
+func TestACLEndpoint_ListAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create two auth methods and put these directly into state.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	authMethods[0].Default = true
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Listing auth methods does not require a token, so that users can find
+	// the auth method to log in with.
+	authMethodReq := &structs.ACLAuthMethodsListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var authMethodResp structs.ACLAuthMethodsListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLListAuthMethodsRPCMethod, authMethodReq, &authMethodResp))
+	require.Len(t, authMethodResp.AuthMethods, 2)
+	require.Equal(t, uint64(10), authMethodResp.Index)
+
+	var defaults []string
+	for _, stub := range authMethodResp.AuthMethods {
+		if stub.Default {
+			defaults = append(defaults, stub.Name)
+		}
+	}
+	require.Equal(t, []string{authMethods[0].Name}, defaults)
+}
+
+func TestACLEndpoint_GetAuthMethod_GetAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create two auth methods and put these directly into state.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Look up a single auth method by its name.
+	authMethodReq := &structs.ACLAuthMethodGetRequest{
+		MethodName: authMethods[0].Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var authMethodResp structs.ACLAuthMethodGetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLGetAuthMethodRPCMethod, authMethodReq, &authMethodResp))
+	require.Equal(t, authMethods[0].Hash, authMethodResp.AuthMethod.Hash)
+
+	// Looking up an auth method which does not exist returns nil.
+	authMethodReq.MethodName = "not-an-auth-method"
+	var authMethodResp2 structs.ACLAuthMethodGetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLGetAuthMethodRPCMethod, authMethodReq, &authMethodResp2))
+	require.Nil(t, authMethodResp2.AuthMethod)
+
+	// Look up both auth methods as the replication process does.
+	authMethodsReq := &structs.ACLAuthMethodsGetRequest{
+		Names: []string{authMethods[0].Name, authMethods[1].Name, "not-an-auth-method"},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var authMethodsResp structs.ACLAuthMethodsGetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLGetAuthMethodsRPCMethod, authMethodsReq, &authMethodsResp))
+	require.Len(t, authMethodsResp.AuthMethods, 2)
+
+	// The auth methods contain the client secret, so a non-management token
+	// must not be able to read them.
+	token := mock.ACLToken()
+	require.NoError(t, testServer.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLToken{token}))
+	authMethodReq.AuthToken = token.SecretID
+	var authMethodResp3 structs.ACLAuthMethodGetResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLGetAuthMethodRPCMethod, authMethodReq, &authMethodResp3)
+	require.ErrorContains(t, err, structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_UpsertBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Creating a binding rule for an auth method which does not exist should
+	// fail.
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.ID = ""
+	bindingRuleReq := &structs.ACLBindingRulesUpsertRequest{
+		ACLBindingRules: []*structs.ACLBindingRule{bindingRule},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var bindingRuleResp structs.ACLBindingRulesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp)
+	require.ErrorContains(t, err, "cannot find auth method")
+
+	// Create the auth method and try again; an ID should be generated.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = bindingRule.AuthMethod
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLUpsertBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp))
+	require.Len(t, bindingRuleResp.ACLBindingRules, 1)
+	require.NotEmpty(t, bindingRuleResp.ACLBindingRules[0].ID)
+
+	// Update the binding rule using its generated ID.
+	updatedBindingRule := bindingRuleResp.ACLBindingRules[0].Copy()
+	updatedBindingRule.Description = "updated-description"
+	bindingRuleReq.ACLBindingRules = []*structs.ACLBindingRule{updatedBindingRule}
+	var bindingRuleResp2 structs.ACLBindingRulesUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLUpsertBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp2))
+	require.Len(t, bindingRuleResp2.ACLBindingRules, 1)
+	require.Equal(t, "updated-description", bindingRuleResp2.ACLBindingRules[0].Description)
+	require.Equal(t, bindingRuleResp.ACLBindingRules[0].CreateIndex, bindingRuleResp2.ACLBindingRules[0].CreateIndex)
+
+	// Updating a binding rule which does not exist should fail.
+	bindingRuleReq.ACLBindingRules = []*structs.ACLBindingRule{mock.ACLBindingRule()}
+	var bindingRuleResp3 structs.ACLBindingRulesUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp3)
+	require.ErrorContains(t, err, "cannot find binding rule")
+
+	// Creating an invalid binding rule should fail.
+	invalidBindingRule := mock.ACLBindingRule()
+	invalidBindingRule.ID = ""
+	invalidBindingRule.Selector = "value.team =="
+	bindingRuleReq.ACLBindingRules = []*structs.ACLBindingRule{invalidBindingRule}
+	var bindingRuleResp4 structs.ACLBindingRulesUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLUpsertBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp4)
+	require.ErrorContains(t, err, "invalid selector")
+}
+
+func TestACLEndpoint_DeleteBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create two binding rules and put these directly into state.
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, bindingRules, true))
+
+	// Delete the first binding rule.
+	bindingRuleReq := &structs.ACLBindingRulesDeleteRequest{
+		ACLBindingRuleIDs: []string{bindingRules[0].ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var bindingRuleResp structs.ACLBindingRulesDeleteResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLDeleteBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp))
+	require.NotZero(t, bindingRuleResp.Index)
+
+	// Deleting the same binding rule again should fail.
+	var bindingRuleResp2 structs.ACLBindingRulesDeleteResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLDeleteBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp2)
+	require.ErrorContains(t, err, "ACL binding rule not found")
+}
+
+func TestACLEndpoint_ListBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create two binding rules and put these directly into state.
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, bindingRules, true))
+
+	bindingRuleReq := &structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var bindingRuleResp structs.ACLBindingRulesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLListBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp))
+	require.Len(t, bindingRuleResp.ACLBindingRules, 2)
+	require.Equal(t, uint64(10), bindingRuleResp.Index)
+
+	// Perform a listing using an ID prefix.
+	bindingRuleReq.Prefix = bindingRules[1].ID[:8]
+	var bindingRuleResp2 structs.ACLBindingRulesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLListBindingRulesRPCMethod, bindingRuleReq, &bindingRuleResp2))
+	require.Len(t, bindingRuleResp2.ACLBindingRules, 1)
+	require.Equal(t, bindingRules[1].ID, bindingRuleResp2.ACLBindingRules[0].ID)
+}
+
+func TestACLEndpoint_GetBindingRule_GetBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Create two binding rules and put these directly into state.
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, bindingRules, true))
+
+	bindingRuleReq := &structs.ACLBindingRuleRequest{
+		ACLBindingRuleID: bindingRules[0].ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var bindingRuleResp structs.ACLBindingRuleResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLGetBindingRuleRPCMethod, bindingRuleReq, &bindingRuleResp))
+	require.Equal(t, bindingRules[0].Hash, bindingRuleResp.ACLBindingRule.Hash)
+
+	bindingRulesReq := &structs.ACLBindingRulesRequest{
+		ACLBindingRuleIDs: []string{bindingRules[0].ID, bindingRules[1].ID},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: rootACLToken.SecretID,
+		},
+	}
+	var bindingRulesResp structs.ACLBindingRulesResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLGetBindingRulesRPCMethod, bindingRulesReq, &bindingRulesResp))
+	require.Len(t, bindingRulesResp.ACLBindingRules, 2)
+}
+
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Start the mock OIDC provider, which issues ID tokens for a user in the
+	// engineering group.
+	oidcTestProvider := oidc.NewTestProvider(t)
+	oidcTestProvider.SetCustomClaims(map[string]interface{}{
+		"groups": []string{"engineering"},
+	})
+
+	redirectURI := "http://127.0.0.1:4649/oidc/callback"
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Config = &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    oidcTestProvider.Issuer(),
+		OIDCClientID:        oidcTestProvider.ClientID(),
+		OIDCClientSecret:    oidcTestProvider.ClientSecret(),
+		AllowedRedirectURIs: []string{redirectURI},
+		ListClaimMappings:   map[string]string{"groups": "roles"},
+	}
+	authMethod.SetHash()
+	require.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	// Create a policy and bind it to users in the engineering group.
+	policy := mock.ACLPolicy()
+	require.NoError(t, testServer.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{policy}))
+
+	bindingRule := mock.ACLBindingRule()
+	bindingRule.AuthMethod = authMethod.Name
+	bindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+	bindingRule.BindName = policy.Name
+	bindingRule.SetHash()
+	require.NoError(t, testServer.fsm.State().UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLBindingRule{bindingRule}, false))
+
+	// Requesting an auth URL using a redirect URI which is not allowed should
+	// fail.
+	clientNonce := uuid.Generate()
+	authURLReq := &structs.ACLOIDCAuthURLRequest{
+		AuthMethodName: authMethod.Name,
+		RedirectURI:    "http://evil.example.com/oidc/callback",
+		ClientNonce:    clientNonce,
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var authURLResp structs.ACLOIDCAuthURLResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLOIDCAuthURLRPCMethod, authURLReq, &authURLResp)
+	require.ErrorContains(t, err, "is not allowed")
+
+	// Request the auth URL, which does not require a token.
+	authURLReq.RedirectURI = redirectURI
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLOIDCAuthURLRPCMethod, authURLReq, &authURLResp))
+	require.True(t, strings.HasPrefix(authURLResp.AuthURL, oidcTestProvider.Issuer()))
+
+	// Visit the auth URL, which redirects with the state and code.
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := httpClient.Get(authURLResp.AuthURL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	redirect, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	// Completing the login with the wrong nonce should fail.
+	completeAuthReq := &structs.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: authMethod.Name,
+		ClientNonce:    uuid.Generate(),
+		State:          redirect.Query().Get("state"),
+		Code:           redirect.Query().Get("code"),
+		RedirectURI:    redirectURI,
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var completeAuthResp structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, completeAuthReq, &completeAuthResp)
+	require.ErrorContains(t, err, "failed to complete OIDC login")
+
+	// Completing the login with a state which was not issued by the servers
+	// should fail.
+	completeAuthReq.ClientNonce = clientNonce
+	completeAuthReq.State = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, completeAuthReq, &completeAuthResp)
+	require.ErrorContains(t, err, "invalid state")
+
+	// Completing the login with the state of another login, which was
+	// started using a different client nonce, should fail.
+	otherAuthURLReq := *authURLReq
+	otherAuthURLReq.ClientNonce = uuid.Generate()
+	var otherAuthURLResp structs.ACLOIDCAuthURLResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLOIDCAuthURLRPCMethod, &otherAuthURLReq, &otherAuthURLResp))
+	otherAuthURL, err := url.Parse(otherAuthURLResp.AuthURL)
+	require.NoError(t, err)
+	completeAuthReq.State = otherAuthURL.Query().Get("state")
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, completeAuthReq, &completeAuthResp)
+	require.ErrorContains(t, err, "state does not match")
+
+	// Complete the login with the correct nonce and state, which should
+	// create a token linked to the bound policy.
+	completeAuthReq.State = redirect.Query().Get("state")
+	require.NoError(t, msgpackrpc.CallWithCodec(
+		codec, structs.ACLOIDCCompleteAuthRPCMethod, completeAuthReq, &completeAuthResp))
+	require.NotZero(t, completeAuthResp.Index)
+
+	token := completeAuthResp.ACLToken
+	require.NotNil(t, token)
+	require.Equal(t, structs.ACLClientToken, token.Type)
+	require.Equal(t, []string{policy.Name}, token.Policies)
+	require.False(t, token.Global)
+	require.NotNil(t, token.ExpirationTime)
+	require.WithinDuration(t, time.Now().Add(authMethod.MaxTokenTTL), *token.ExpirationTime, time.Minute)
+
+	// The token can be resolved by the servers.
+	aclObj, err := testServer.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, aclObj)
+
+	// Users who do not match any binding rule cannot log in.
+	oidcTestProvider.SetCustomClaims(map[string]interface{}{
+		"groups": []string{"marketing"},
+	})
+	completeAuthReq.Code = oidcTestProvider.ExpectedAuthCode()
+	var completeAuthResp2 structs.ACLLoginResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteAuthRPCMethod, completeAuthReq, &completeAuthResp2)
+	require.ErrorContains(t, err, "no role or policy bindings matched")
+
+	// Logging in using an auth method which does not exist should fail.
+	authURLReq.AuthMethodName = "not-an-auth-method"
+	var authURLResp2 structs.ACLOIDCAuthURLResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCAuthURLRPCMethod, authURLReq, &authURLResp2)
+	require.ErrorContains(t, err, "not found")
+}
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResolveACLToken(t *testing.T) {
@@ -109,6 +111,117 @@ func TestResolveACLToken_LeaderToken(t *testing.T) {
 	}
 }
 
+func TestResolveACLToken_WorkloadIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	alloc := mock.Alloc()
+	require.NoError(t, s1.State().UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	claims := structs.NewIdentityClaims(alloc.Job, alloc, "web", time.Now())
+	token, _, err := s1.encrypter.SignClaims(claims)
+	require.NoError(t, err)
+
+	// The identity should be able to read the variables of its job only.
+	aclObj, err := s1.ResolveToken(token)
+	require.NoError(t, err)
+	require.NotNil(t, aclObj)
+	require.False(t, aclObj.IsManagement())
+
+	jobPath := structs.VariablesJobPath(alloc.JobID)
+	require.True(t, aclObj.AllowVariableOperation(alloc.Namespace, jobPath, acl.VariablesCapabilityRead))
+	require.True(t, aclObj.AllowVariableOperation(alloc.Namespace, jobPath+"/web", acl.VariablesCapabilityList))
+	require.False(t, aclObj.AllowVariableOperation(alloc.Namespace, jobPath, acl.VariablesCapabilityWrite))
+	require.False(t, aclObj.AllowVariableOperation(alloc.Namespace, "nomad/jobs/other", acl.VariablesCapabilityRead))
+	require.False(t, aclObj.AllowVariableOperation("other", jobPath, acl.VariablesCapabilityRead))
+	require.False(t, aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilitySubmitJob))
+
+	// A tampered identity should not resolve.
+	_, err = s1.ResolveToken(token + "x")
+	require.Equal(t, structs.ErrTokenNotFound, err)
+
+	// Once the allocation is terminal, the identity should no longer resolve.
+	stopped := alloc.Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	require.NoError(t, s1.State().UpsertAllocs(structs.MsgTypeTestSetup, 1010, []*structs.Allocation{stopped}))
+
+	_, err = s1.ResolveToken(token)
+	require.Equal(t, structs.ErrTokenNotFound, err)
+}
+
+func TestResolveACLToken_Roles(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create mock state store and cache
+	testState := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	require.NoError(t, err)
+
+	// Create two policies; one granting read on the default namespace and the
+	// other granting read on the nodes.
+	policy1 := mock.ACLPolicy()
+	policy1.Rules = `namespace "default" { policy = "read" }`
+	policy1.SetHash()
+	policy2 := mock.ACLPolicy()
+	policy2.Rules = `node { policy = "read" }`
+	policy2.SetHash()
+	require.NoError(t, testState.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Create a role linked to the first policy and a token linked only to the
+	// role.
+	role := mock.ACLRole()
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: policy1.Name}}
+	role.SetHash()
+	require.NoError(t, testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLRole{role}, false))
+
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []*structs.ACLTokenRoleLink{{ID: role.ID}}
+	token.SetHash()
+	require.NoError(t, testState.UpsertACLTokens(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLToken{token}))
+
+	// Resolve the token; it should inherit the permissions of the role.
+	snap, err := testState.Snapshot()
+	require.NoError(t, err)
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(t, err)
+	require.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	require.False(t, aclObj.AllowNodeRead())
+
+	// Update the role to also link to the second policy. The update should
+	// immediately take effect on the linked token.
+	updatedRole := role.Copy()
+	updatedRole.Policies = append(updatedRole.Policies, &structs.ACLRolePolicyLink{Name: policy2.Name})
+	updatedRole.SetHash()
+	require.NoError(t, testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLRole{updatedRole}, false))
+
+	snap, err = testState.Snapshot()
+	require.NoError(t, err)
+	aclObj, err = resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(t, err)
+	require.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	require.True(t, aclObj.AllowNodeRead())
+
+	// Delete the role; the token should lose all its permissions.
+	require.NoError(t, testState.DeleteACLRolesByID(
+		structs.MsgTypeTestSetup, 50, []string{role.ID}))
+
+	snap, err = testState.Snapshot()
+	require.NoError(t, err)
+	aclObj, err = resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(t, err)
+	require.False(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	require.False(t, aclObj.AllowNodeRead())
+}
+
 func TestResolveSecretToken(t *testing.T) {
 	ci.Parallel(t)
 
@@ -132,3 +245,39 @@ func TestResolveSecretToken(t *testing.T) {
 	}
 
 }
+
+func TestResolveACLToken_Expired(t *testing.T) {
+	ci.Parallel(t)
+
+	testState := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	require.NoError(t, err)
+
+	// Create a token which has already expired, and one which has not.
+	policy := mock.ACLPolicy()
+	expiredTime := time.Now().UTC().Add(-time.Minute)
+	expiredToken := mock.ACLToken()
+	expiredToken.Policies = []string{policy.Name}
+	expiredToken.ExpirationTime = &expiredTime
+
+	validTime := time.Now().UTC().Add(time.Hour)
+	validToken := mock.ACLToken()
+	validToken.Policies = []string{policy.Name}
+	validToken.ExpirationTime = &validTime
+
+	require.NoError(t, testState.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 100, []*structs.ACLPolicy{policy}))
+	require.NoError(t, testState.UpsertACLTokens(
+		structs.MsgTypeTestSetup, 110, []*structs.ACLToken{expiredToken, validToken}))
+
+	snap, err := testState.Snapshot()
+	require.NoError(t, err)
+
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, expiredToken.SecretID)
+	require.Equal(t, structs.ErrTokenExpired, err)
+	require.Nil(t, aclObj)
+
+	aclObj, err = resolveTokenFromSnapshotCache(snap, cache, validToken.SecretID)
+	require.NoError(t, err)
+	require.NotNil(t, aclObj)
+}
@@ -326,6 +326,7 @@ func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopRes
 			args.AllocID: {
 				Migrate:         helper.BoolToPtr(true),
 				NoShutdownDelay: helper.BoolToPtr(args.NoShutdownDelay),
+				Unpin:           helper.BoolToPtr(args.Unpin),
 			},
 		},
 	}
@@ -126,7 +126,7 @@ func TestAllocEndpoint_List_PaginationFiltering(t *testing.T) {
 
 	state := s1.fsm.State()
 
-	require.NoError(t, state.UpsertNamespaces(1099, []*structs.Namespace{
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1099, []*structs.Namespace{
 		{Name: "non-default"},
 	}))
 
@@ -648,7 +648,7 @@ func TestAllocEndpoint_List_AllNamespaces_OSS(t *testing.T) {
 	// two namespaces
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 900, []*structs.Namespace{ns1, ns2}))
 
 	// Create the allocations
 	uuid1 := uuid.Generate()
@@ -1113,6 +1113,38 @@ func TestAllocEndpoint_UpdateDesiredTransition(t *testing.T) {
 	require.True(*out2.DesiredTransition.Migrate)
 }
 
+func TestAllocEndpoint_Stop_Unpin(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc := mock.Alloc()
+	state := s1.fsm.State()
+	require.Nil(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	req := &structs.AllocStopRequest{
+		AllocID: alloc.ID,
+		Unpin:   true,
+	}
+	req.Namespace = structs.DefaultNamespace
+	req.Region = alloc.Job.Region
+
+	var resp structs.AllocStopResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp))
+	require.NotZero(resp.Index)
+
+	// The allocation is migrated and its replacement is not pinned
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.True(out.DesiredTransition.ShouldMigrate())
+	require.True(out.DesiredTransition.ShouldUnpin())
+}
+
 func TestAllocEndpoint_Stop_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -1187,7 +1219,7 @@ func TestAllocEndpoint_List_AllNamespaces_ACL_OSS(t *testing.T) {
 	// two namespaces
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(900, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 900, []*structs.Namespace{ns1, ns2}))
 
 	// Create the allocations
 	alloc1 := mock.Alloc()
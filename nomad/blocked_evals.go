@@ -397,6 +397,54 @@ func (b *BlockedEvals) Untrack(jobID, namespace string) {
 	}
 }
 
+// UntrackEvals causes the passed blocked evaluations to be no longer tracked.
+// UntrackEvals is called when blocked evaluations are deleted.
+func (b *BlockedEvals) UntrackEvals(evalIDs []string) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	// Do nothing if not enabled
+	if !b.enabled {
+		return
+	}
+
+	for _, evalID := range evalIDs {
+		// System evals are tracked by node in addition to being captured
+		if w, ok := b.system.Get(evalID); ok {
+			b.system.Remove(w.eval)
+		}
+
+		if w, ok := b.captured[evalID]; ok {
+			b.untrackJobLocked(w.eval)
+			delete(b.captured, evalID)
+			b.stats.Unblock(w.eval)
+			if w.eval.QuotaLimitReached != "" {
+				b.stats.TotalQuotaLimit--
+			}
+		}
+
+		if w, ok := b.escaped[evalID]; ok {
+			b.untrackJobLocked(w.eval)
+			delete(b.escaped, evalID)
+			b.stats.TotalEscaped--
+			b.stats.Unblock(w.eval)
+			if w.eval.QuotaLimitReached != "" {
+				b.stats.TotalQuotaLimit--
+			}
+		}
+	}
+}
+
+// untrackJobLocked removes the job of the evaluation from the set of blocked
+// jobs if the evaluation is the one tracked for it. It must be called with the
+// lock held.
+func (b *BlockedEvals) untrackJobLocked(eval *structs.Evaluation) {
+	nsID := structs.NewNamespacedID(eval.JobID, eval.Namespace)
+	if b.jobs[nsID] == eval.ID {
+		delete(b.jobs, nsID)
+	}
+}
+
 // Unblock causes any evaluation that could potentially make progress on a
 // capacity change on the passed computed node class to be enqueued into the
 // eval broker.
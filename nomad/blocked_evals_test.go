@@ -729,3 +729,42 @@ func TestBlockedEvals_SystemDisableFlush(t *testing.T) {
 	require.Empty(blocked.system.byJob)
 	require.Empty(blocked.system.byNode)
 }
+
+func TestBlockedEvals_UntrackEvals(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	blocked, _ := testBlockedEvals(t)
+
+	// Create a captured, an escaped and a system blocked eval
+	e := mock.BlockedEval()
+	e.ClassEligibility = map[string]bool{"v1:123": false}
+	e2 := mock.BlockedEval()
+	e2.EscapedComputedClass = true
+	e2.QuotaLimitReached = "foo"
+	e3 := mock.BlockedEval()
+	e3.Type = structs.JobTypeSystem
+	e3.NodeID = "node-1"
+	blocked.Block(e)
+	blocked.Block(e2)
+	blocked.Block(e3)
+
+	blockedStats := blocked.Stats()
+	require.Equal(3, blockedStats.TotalBlocked)
+	require.Equal(1, blockedStats.TotalEscaped)
+	require.Equal(1, blockedStats.TotalQuotaLimit)
+
+	// Untrack the evals and verify they are no longer tracked
+	blocked.UntrackEvals([]string{e.ID, e2.ID, e3.ID})
+
+	blockedStats = blocked.Stats()
+	require.Equal(0, blockedStats.TotalBlocked)
+	require.Equal(0, blockedStats.TotalEscaped)
+	require.Equal(0, blockedStats.TotalQuotaLimit)
+
+	// A new eval of an untracked job can be blocked again
+	e4 := mock.BlockedEval()
+	e4.JobID = e.JobID
+	blocked.Block(e4)
+	require.Equal(1, blocked.Stats().TotalBlocked)
+}
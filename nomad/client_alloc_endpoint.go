@@ -299,6 +299,10 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 		handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
 		return
 	}
+	if args.Action != "" && args.Tty {
+		handleStreamResultError(errors.New("actions can't be run with a tty"), helper.Int64ToPtr(400), encoder)
+		return
+	}
 
 	// Retrieve the allocation
 	snap, err := a.srv.State().Snapshot()
@@ -321,8 +325,10 @@ func (a *ClientAllocations) exec(conn io.ReadWriteCloser) {
 	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
-		// client ultimately checks if AllocNodeExec is required
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) &&
+		!(args.Action != "" && aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocAction)) {
+		// client ultimately checks if AllocNodeExec is required, including
+		// for task actions
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
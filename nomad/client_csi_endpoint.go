@@ -84,6 +84,19 @@ func (a *ClientCSI) ControllerDeleteVolume(args *cstructs.ClientCSIControllerDel
 	return nil
 }
 
+func (a *ClientCSI) ControllerExpandVolume(args *cstructs.ClientCSIControllerExpandVolumeRequest, reply *cstructs.ClientCSIControllerExpandVolumeResponse) error {
+	defer metrics.MeasureSince([]string{"nomad", "client_csi_controller", "expand_volume"}, time.Now())
+
+	err := a.sendCSIControllerRPC(args.PluginID,
+		"CSI.ControllerExpandVolume",
+		"ClientCSI.ControllerExpandVolume",
+		args, reply)
+	if err != nil {
+		return fmt.Errorf("controller expand volume: %v", err)
+	}
+	return nil
+}
+
 func (a *ClientCSI) ControllerListVolumes(args *cstructs.ClientCSIControllerListVolumesRequest, reply *cstructs.ClientCSIControllerListVolumesResponse) error {
 	defer metrics.MeasureSince([]string{"nomad", "client_csi_controller", "list_volumes"}, time.Now())
 
@@ -205,6 +218,34 @@ func (a *ClientCSI) NodeDetachVolume(args *cstructs.ClientCSINodeDetachVolumeReq
 
 }
 
+func (a *ClientCSI) NodeExpandVolume(args *cstructs.ClientCSINodeExpandVolumeRequest, reply *cstructs.ClientCSINodeExpandVolumeResponse) error {
+	defer metrics.MeasureSince([]string{"nomad", "client_csi_node", "expand_volume"}, time.Now())
+
+	// Make sure Node is valid and new enough to support RPC
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	_, err = getNodeForRpc(snap, args.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := a.srv.getNodeConn(args.NodeID)
+	if !ok {
+		return findNodeConnAndForward(a.srv, args.NodeID, "ClientCSI.NodeExpandVolume", args, reply)
+	}
+
+	// Make the RPC
+	err = NodeRpc(state.Session, "CSI.NodeExpandVolume", args, reply)
+	if err != nil {
+		return fmt.Errorf("node expand volume: %v", err)
+	}
+	return nil
+}
+
 // clientIDsForController returns a shuffled list of client IDs where the
 // controller plugin is expected to be running.
 func (a *ClientCSI) clientIDsForController(pluginID string) ([]string, error) {
@@ -40,6 +40,10 @@ type MockClientCSI struct {
 	NextListExternalSnapshotsError    error
 	NextListExternalSnapshotsResponse *cstructs.ClientCSIControllerListSnapshotsResponse
 	NextNodeDetachError               error
+	NextExpandError                   error
+	NextExpandResponse                *cstructs.ClientCSIControllerExpandVolumeResponse
+	NextNodeExpandError               error
+	LastNodeExpandRequest             *cstructs.ClientCSINodeExpandVolumeRequest
 }
 
 func newMockClientCSI() *MockClientCSI {
@@ -49,6 +53,7 @@ func newMockClientCSI() *MockClientCSI {
 		NextListExternalResponse:          &cstructs.ClientCSIControllerListVolumesResponse{},
 		NextCreateSnapshotResponse:        &cstructs.ClientCSIControllerCreateSnapshotResponse{},
 		NextListExternalSnapshotsResponse: &cstructs.ClientCSIControllerListSnapshotsResponse{},
+		NextExpandResponse:                &cstructs.ClientCSIControllerExpandVolumeResponse{},
 	}
 }
 
@@ -93,10 +98,21 @@ func (c *MockClientCSI) ControllerListSnapshots(req *cstructs.ClientCSIControlle
 	return c.NextListExternalSnapshotsError
 }
 
+func (c *MockClientCSI) ControllerExpandVolume(req *cstructs.ClientCSIControllerExpandVolumeRequest, resp *cstructs.ClientCSIControllerExpandVolumeResponse) error {
+	*resp = *c.NextExpandResponse
+	return c.NextExpandError
+}
+
 func (c *MockClientCSI) NodeDetachVolume(req *cstructs.ClientCSINodeDetachVolumeRequest, resp *cstructs.ClientCSINodeDetachVolumeResponse) error {
 	return c.NextNodeDetachError
 }
 
+func (c *MockClientCSI) NodeExpandVolume(req *cstructs.ClientCSINodeExpandVolumeRequest, resp *cstructs.ClientCSINodeExpandVolumeResponse) error {
+	c.LastNodeExpandRequest = req
+	resp.CapacityBytes = req.CapacityMin
+	return c.NextNodeExpandError
+}
+
 func TestClientCSIController_AttachVolume_Local(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -261,6 +277,24 @@ func TestClientCSIController_DeleteVolume_Forwarded(t *testing.T) {
 	require.Contains(err.Error(), "no plugins registered for type")
 }
 
+func TestClientCSIController_ExpandVolume_Local(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+	codec, cleanup := setupLocal(t)
+	defer cleanup()
+
+	req := &cstructs.ClientCSIControllerExpandVolumeRequest{
+		ExternalVolumeID:   "test",
+		CapacityMin:        100,
+		CSIControllerQuery: cstructs.CSIControllerQuery{PluginID: "minnie"},
+	}
+
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientCSI.ControllerExpandVolume", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "no plugins registered for type")
+}
+
 func TestClientCSIController_ListVolumes_Local(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -527,6 +561,7 @@ func setupLocal(t *testing.T) (rpc.ClientCodec, func()) {
 	mockCSI.NextCreateSnapshotError = fmt.Errorf("no plugins registered for type")
 	mockCSI.NextDeleteSnapshotError = fmt.Errorf("no plugins registered for type")
 	mockCSI.NextListExternalSnapshotsError = fmt.Errorf("no plugins registered for type")
+	mockCSI.NextExpandError = fmt.Errorf("no plugins registered for type")
 
 	c1, cleanupC1 := client.TestClientWithRPCs(t,
 		func(c *config.Config) {
@@ -74,3 +74,60 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	// Make the RPC
 	return NodeRpc(state.Session, "ClientStats.Stats", args, reply)
 }
+
+func (s *ClientStats) ArtifactCache(args *nstructs.NodeSpecificRequest, reply *structs.ArtifactCacheResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hope
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := s.srv.forward("ClientStats.ArtifactCache", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_stats", "artifact_cache"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	// Verify the arguments.
+	if args.NodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Check if the node even exists and is compatible with NodeRpc
+	snap, err := s.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Make sure Node is new enough to support RPC
+	_, err = getNodeForRpc(snap, args.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := s.srv.getNodeConn(args.NodeID)
+	if !ok {
+
+		// Determine the Server that has a connection to the node.
+		srv, err := s.srv.serverWithNodeConn(args.NodeID, s.srv.Region())
+		if err != nil {
+			return err
+		}
+
+		if srv == nil {
+			return nstructs.ErrNoNodeConn
+		}
+
+		return s.srv.forwardServer(srv, "ClientStats.ArtifactCache", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "ClientStats.ArtifactCache", args, reply)
+}
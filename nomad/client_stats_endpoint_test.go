@@ -224,3 +224,42 @@ func TestClientStats_Stats_Remote(t *testing.T) {
 	require.Nil(err)
 	require.NotNil(resp.HostStats)
 }
+
+func TestClientStats_ArtifactCache_Local(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &structs.NodeSpecificRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	var resp cstructs.ArtifactCacheResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientStats.ArtifactCache", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing")
+
+	// Fetch the response setting the node id
+	req.NodeID = c.NodeID()
+	var resp2 cstructs.ArtifactCacheResponse
+	err = msgpackrpc.CallWithCodec(codec, "ClientStats.ArtifactCache", req, &resp2)
+	require.NoError(t, err)
+	require.NotNil(t, resp2.Cache)
+}
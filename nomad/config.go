@@ -196,6 +196,14 @@ type Config struct {
 	// one-time tokens.
 	OneTimeTokenGCInterval time.Duration
 
+	// ACLTokenExpirationGCInterval is how often we dispatch a job to GC
+	// expired ACL tokens.
+	ACLTokenExpirationGCInterval time.Duration
+
+	// ACLTokenExpirationGCThreshold controls how long an ACL token must be
+	// expired before it is eligible for GC.
+	ACLTokenExpirationGCThreshold time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -270,6 +278,11 @@ type Config struct {
 	// ACLEnabled controls if ACL enforcement and management is enabled.
 	ACLEnabled bool
 
+	// ACLTokenMinExpirationTTL and ACLTokenMaxExpirationTTL are used to
+	// enforce the bounds of the expiration time of ACL tokens.
+	ACLTokenMinExpirationTTL time.Duration
+	ACLTokenMaxExpirationTTL time.Duration
+
 	// ReplicationBackoff is how much we backoff when replication errors.
 	// This is a tunable knob for testing primarily.
 	ReplicationBackoff time.Duration
@@ -349,6 +362,15 @@ type Config struct {
 	// DeploymentQueryRateLimit is in queries per second and is used by the
 	// DeploymentWatcher to throttle the amount of simultaneously deployments
 	DeploymentQueryRateLimit float64
+
+	// JobMaxSourceSize is the maximum size in bytes of the job source and
+	// variables stored with each job version. Larger submissions are dropped
+	// with a warning, and 0 disables storing submissions.
+	JobMaxSourceSize int
+
+	// EventSinkFileDir is the directory file event sinks must write under.
+	// File sinks are rejected when it is empty.
+	EventSinkFileDir string
 }
 
 // DefaultConfig returns the default configuration. Only used as the basis for
@@ -385,6 +407,10 @@ func DefaultConfig() *Config {
 		CSIVolumeClaimGCInterval:         5 * time.Minute,
 		CSIVolumeClaimGCThreshold:        5 * time.Minute,
 		OneTimeTokenGCInterval:           10 * time.Minute,
+		ACLTokenExpirationGCInterval:     5 * time.Minute,
+		ACLTokenExpirationGCThreshold:    1 * time.Hour,
+		ACLTokenMinExpirationTTL:         1 * time.Minute,
+		ACLTokenMaxExpirationTTL:         24 * time.Hour,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -423,6 +449,7 @@ func DefaultConfig() *Config {
 			},
 		},
 		DeploymentQueryRateLimit: deploymentwatcher.LimitStateQueriesPerSecond,
+		JobMaxSourceSize:         1e6,
 	}
 
 	// Enable all known schedulers by default
@@ -58,6 +58,10 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.csiPluginGC(eval)
 	case structs.CoreJobOneTimeTokenGC:
 		return c.expiredOneTimeTokenGC(eval)
+	case structs.CoreJobLocalTokenExpiredGC:
+		return c.expiredACLTokenGC(eval, false)
+	case structs.CoreJobGlobalTokenExpiredGC:
+		return c.expiredACLTokenGC(eval, true)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -85,6 +89,12 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.expiredOneTimeTokenGC(eval); err != nil {
 		return err
 	}
+	if err := c.expiredACLTokenGC(eval, false); err != nil {
+		return err
+	}
+	if err := c.expiredACLTokenGC(eval, true); err != nil {
+		return err
+	}
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
 	return c.nodeGC(eval)
@@ -780,6 +790,60 @@ func (c *CoreScheduler) expiredOneTimeTokenGC(eval *structs.Evaluation) error {
 	return c.srv.RPC("ACL.ExpireOneTimeTokens", req, &structs.GenericResponse{})
 }
 
+// expiredACLTokenGC is used to garbage collect expired ACL tokens. Local and
+// global tokens are handled separately, as global tokens can only be deleted
+// within the authoritative region.
+func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation, global bool) error {
+
+	// Tokens can only exist when ACLs are enabled, and global tokens are
+	// owned by the authoritative region.
+	if !c.srv.config.ACLEnabled {
+		return nil
+	}
+	if global && c.srv.config.AuthoritativeRegion != c.srv.Region() {
+		return nil
+	}
+
+	tokenScope := "local"
+	if global {
+		tokenScope = "global"
+	}
+
+	// Unless the GC was forced, only collect tokens which have been expired
+	// for longer than the threshold.
+	cutoff := time.Now().UTC()
+	if eval.JobID != structs.CoreJobForceGC {
+		cutoff = cutoff.Add(-1 * c.srv.config.ACLTokenExpirationGCThreshold)
+	}
+
+	// Limit the number of tokens deleted in a single pass, so that the raft
+	// entry remains a reasonable size. Any remaining tokens will be collected
+	// by the next invocation.
+	accessorIDs, err := c.snap.ACLTokensByExpired(global, cutoff, maxIdsPerReap)
+	if err != nil {
+		return err
+	}
+	if len(accessorIDs) == 0 {
+		return nil
+	}
+
+	c.logger.Debug("expired ACL token GC found eligible tokens",
+		"scope", tokenScope, "num", len(accessorIDs))
+
+	req := &structs.ACLTokenDeleteRequest{
+		AccessorIDs: accessorIDs,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	if err := c.srv.RPC("ACL.DeleteTokens", req, &structs.GenericResponse{}); err != nil {
+		c.logger.Error("expired ACL token GC failed", "scope", tokenScope, "error", err)
+		return err
+	}
+	return nil
+}
+
 // getThreshold returns the index threshold for determining whether an
 // object is old enough to GC
 func (c *CoreScheduler) getThreshold(eval *structs.Evaluation, objectName, configName string, configThreshold time.Duration) uint64 {
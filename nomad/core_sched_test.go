@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
@@ -2510,3 +2512,131 @@ func TestCoreScheduler_FailLoop(t *testing.T) {
 			out.TriggeredBy)
 	}
 }
+
+func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, rootACLToken, testServerShutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer testServerShutdown()
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Subscribe to ACL token events, so we can check the deletion events are
+	// emitted by the garbage collector.
+	publisher, err := testServer.State().EventBroker()
+	require.NoError(t, err)
+	sub, err := publisher.Subscribe(&stream.SubscribeRequest{
+		Token:  rootACLToken.SecretID,
+		Topics: map[structs.Topic][]string{structs.TopicACLToken: {"*"}},
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	now := time.Now().UTC()
+	expiredTime := now.Add(-2 * testServer.config.ACLTokenExpirationGCThreshold)
+	recentlyExpiredTime := now.Add(-1 * time.Minute)
+	futureTime := now.Add(time.Hour)
+
+	// Create local and global tokens with a variety of expiration times.
+	expiredLocal := mock.ACLToken()
+	expiredLocal.ExpirationTime = &expiredTime
+
+	recentlyExpiredLocal := mock.ACLToken()
+	recentlyExpiredLocal.ExpirationTime = &recentlyExpiredTime
+
+	notExpiredLocal := mock.ACLToken()
+	notExpiredLocal.ExpirationTime = &futureTime
+
+	expiredGlobal := mock.ACLToken()
+	expiredGlobal.Global = true
+	expiredGlobal.ExpirationTime = &expiredTime
+
+	require.NoError(t, testServer.State().UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{
+		expiredLocal, recentlyExpiredLocal, notExpiredLocal, expiredGlobal}))
+
+	// Run the local token GC, which should only remove the local token which
+	// has been expired for longer than the threshold.
+	snap, err := testServer.State().Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(testServer, snap)
+	require.NoError(t, core.Process(testServer.coreJobEval(structs.CoreJobLocalTokenExpiredGC, 1001)))
+
+	for _, tc := range []struct {
+		token   *structs.ACLToken
+		deleted bool
+	}{
+		{expiredLocal, true},
+		{recentlyExpiredLocal, false},
+		{notExpiredLocal, false},
+		{expiredGlobal, false},
+	} {
+		out, err := testServer.State().ACLTokenByAccessorID(nil, tc.token.AccessorID)
+		require.NoError(t, err)
+		require.Equal(t, tc.deleted, out == nil, "token %s", tc.token.AccessorID)
+	}
+
+	// Check the deletion event was emitted.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := sub.Next(ctx)
+	require.NoError(t, err)
+	require.Len(t, events.Events, 1)
+	require.Equal(t, structs.TypeACLTokenDeleted, events.Events[0].Type)
+	require.Equal(t, expiredLocal.AccessorID, events.Events[0].Key)
+
+	// Run the global token GC, which should remove the expired global token.
+	snap, err = testServer.State().Snapshot()
+	require.NoError(t, err)
+	core = NewCoreScheduler(testServer, snap)
+	require.NoError(t, core.Process(testServer.coreJobEval(structs.CoreJobGlobalTokenExpiredGC, 1002)))
+
+	out, err := testServer.State().ACLTokenByAccessorID(nil, expiredGlobal.AccessorID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// A forced GC ignores the threshold and removes all expired tokens.
+	snap, err = testServer.State().Snapshot()
+	require.NoError(t, err)
+	core = NewCoreScheduler(testServer, snap)
+	require.NoError(t, core.Process(testServer.coreJobEval(structs.CoreJobForceGC, 1003)))
+
+	out, err = testServer.State().ACLTokenByAccessorID(nil, recentlyExpiredLocal.AccessorID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = testServer.State().ACLTokenByAccessorID(nil, notExpiredLocal.AccessorID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestCoreScheduler_ExpiredACLTokenGC_NonAuthoritativeRegion(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerShutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+	})
+	defer testServerShutdown()
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	expiredTime := time.Now().UTC().Add(-2 * testServer.config.ACLTokenExpirationGCThreshold)
+	expiredGlobal := mock.ACLToken()
+	expiredGlobal.Global = true
+	expiredGlobal.ExpirationTime = &expiredTime
+
+	require.NoError(t, testServer.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{expiredGlobal}))
+
+	// Global tokens are owned by the authoritative region, so the GC should
+	// not attempt to delete them.
+	snap, err := testServer.State().Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(testServer, snap)
+	require.NoError(t, core.Process(testServer.coreJobEval(structs.CoreJobGlobalTokenExpiredGC, 1001)))
+
+	out, err := testServer.State().ACLTokenByAccessorID(nil, expiredGlobal.AccessorID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
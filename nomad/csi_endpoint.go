@@ -323,6 +323,8 @@ func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *stru
 		return fmt.Errorf("missing volume definition")
 	}
 
+	var mErr multierror.Error
+
 	// This is the only namespace we ACL checked, force all the volumes to use it.
 	// We also validate that the plugin exists for each plugin, and validate the
 	// capabilities when the plugin has a controller.
@@ -376,6 +378,23 @@ func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *stru
 		if err := v.controllerValidateVolume(args, vol, plugin); err != nil {
 			return err
 		}
+
+		// An existing volume may have been updated with a larger
+		// requested capacity, in which case we expand it. Node
+		// expansion errors don't prevent us from recording the new
+		// capacity of the volume.
+		if existingVol != nil {
+			if vol.RequiresExpansion() &&
+				!plugin.HasControllerCapability(structs.CSIControllerSupportsExpand) {
+				return fmt.Errorf("plugin does not support expanding volumes")
+			}
+			if err := v.controllerExpandVolume(vol, plugin); err != nil {
+				return err
+			}
+			if err := v.nodeExpandVolume(vol); err != nil {
+				multierror.Append(&mErr, err)
+			}
+		}
 	}
 
 	resp, index, err := v.srv.raftApply(structs.CSIVolumeRegisterRequestType, args)
@@ -386,6 +405,9 @@ func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *stru
 	if respErr, ok := resp.(error); ok {
 		return respErr
 	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
 
 	reply.Index = index
 	v.srv.setQueryMeta(&reply.QueryMeta)
@@ -930,11 +952,17 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 	regArgs := &structs.CSIVolumeRegisterRequest{WriteRequest: args.WriteRequest}
 
 	type validated struct {
-		vol    *structs.CSIVolume
-		plugin *structs.CSIPlugin
+		vol      *structs.CSIVolume
+		plugin   *structs.CSIPlugin
+		existing bool
 	}
 	validatedVols := []validated{}
 
+	snap, err := v.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
 	// This is the only namespace we ACL checked, force all the volumes to use it.
 	// We also validate that the plugin exists for each plugin, and validate the
 	// capabilities when the plugin has a controller.
@@ -945,6 +973,23 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 		if err = vol.Validate(); err != nil {
 			return err
 		}
+
+		// If the volume already exists, we merge the update onto a copy
+		// of it as in Register, and expand the volume instead of creating
+		// it if the requested capacity has grown.
+		existingVol, err := snap.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+		if err != nil {
+			return err
+		}
+		if existingVol != nil {
+			existingVol = existingVol.Copy()
+			err = existingVol.Merge(vol)
+			if err != nil {
+				return err
+			}
+			*vol = *existingVol
+		}
+
 		plugin, err := v.pluginValidateVolume(regArgs, vol)
 		if err != nil {
 			return err
@@ -952,11 +997,16 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 		if !plugin.ControllerRequired {
 			return fmt.Errorf("plugin has no controller")
 		}
-		if !plugin.HasControllerCapability(structs.CSIControllerSupportsCreateDelete) {
+		if existingVol != nil {
+			if vol.RequiresExpansion() &&
+				!plugin.HasControllerCapability(structs.CSIControllerSupportsExpand) {
+				return fmt.Errorf("plugin does not support expanding volumes")
+			}
+		} else if !plugin.HasControllerCapability(structs.CSIControllerSupportsCreateDelete) {
 			return fmt.Errorf("plugin does not support creating volumes")
 		}
 
-		validatedVols = append(validatedVols, validated{vol, plugin})
+		validatedVols = append(validatedVols, validated{vol, plugin, existingVol != nil})
 	}
 
 	// Attempt to create all the validated volumes and write only successfully
@@ -973,6 +1023,23 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 	var mErr multierror.Error
 
 	for _, valid := range validatedVols {
+		if valid.existing {
+			err = v.controllerExpandVolume(valid.vol, valid.plugin)
+			if err != nil {
+				multierror.Append(&mErr, err)
+				continue
+			}
+			// the volume has been expanded by the storage provider even
+			// if the nodes fail to expand it, so we need to record its
+			// new capacity either way
+			regArgs.Volumes = append(regArgs.Volumes, valid.vol)
+			err = v.nodeExpandVolume(valid.vol)
+			if err != nil {
+				multierror.Append(&mErr, err)
+			}
+			continue
+		}
+
 		err = v.createVolume(valid.vol, valid.plugin)
 		if err != nil {
 			multierror.Append(&mErr, err)
@@ -1030,6 +1097,92 @@ func (v *CSIVolume) createVolume(vol *structs.CSIVolume, plugin *structs.CSIPlug
 	return nil
 }
 
+// controllerExpandVolume expands the volume in the external storage provider
+// if its requested minimum capacity has grown past its current capacity, and
+// updates the volume with the resulting capacity.
+func (v *CSIVolume) controllerExpandVolume(vol *structs.CSIVolume, plugin *structs.CSIPlugin) error {
+	if !vol.RequiresExpansion() {
+		return nil
+	}
+
+	method := "ClientCSI.ControllerExpandVolume"
+	cReq := &cstructs.ClientCSIControllerExpandVolumeRequest{
+		ExternalVolumeID: vol.ExternalID,
+		CapacityMin:      vol.RequestedCapacityMin,
+		CapacityMax:      vol.RequestedCapacityMax,
+		Secrets:          vol.Secrets,
+	}
+	if vol.InUse() {
+		cReq.VolumeCapability = &structs.CSIVolumeCapability{
+			AccessMode:     vol.AccessMode,
+			AttachmentMode: vol.AttachmentMode,
+		}
+		cReq.MountOptions = vol.MountOptions
+	}
+	cReq.PluginID = plugin.ID
+	cResp := &cstructs.ClientCSIControllerExpandVolumeResponse{}
+	err := v.srv.RPC(method, cReq, cResp)
+	if err != nil {
+		return fmt.Errorf("could not expand volume %q: %w", vol.ID, err)
+	}
+
+	v.logger.Debug("expanded volume", "volume_id", vol.ID,
+		"capacity", cResp.CapacityBytes,
+		"node_expansion_required", cResp.NodeExpansionRequired)
+
+	vol.Capacity = cResp.CapacityBytes
+	vol.NodeExpansionRequired = cResp.NodeExpansionRequired
+	return nil
+}
+
+// nodeExpandVolume expands the volume on every node where it's currently
+// staged, after the controller has expanded it in the storage provider.
+func (v *CSIVolume) nodeExpandVolume(vol *structs.CSIVolume) error {
+	if !vol.NodeExpansionRequired {
+		return nil
+	}
+
+	var mErr multierror.Error
+	expand := func(claim *structs.CSIVolumeClaim) {
+		if claim == nil || claim.State != structs.CSIVolumeClaimStateTaken ||
+			claim.AccessMode == structs.CSIVolumeAccessModeUnknown {
+			return
+		}
+		req := &cstructs.ClientCSINodeExpandVolumeRequest{
+			PluginID:       vol.PluginID,
+			VolumeID:       vol.ID,
+			ExternalID:     vol.RemoteID(),
+			AllocID:        claim.AllocationID,
+			NodeID:         claim.NodeID,
+			AttachmentMode: claim.AttachmentMode,
+			AccessMode:     claim.AccessMode,
+			ReadOnly:       claim.Mode == structs.CSIVolumeClaimRead,
+			MountOptions:   vol.MountOptions,
+			CapacityMin:    vol.RequestedCapacityMin,
+			CapacityMax:    vol.RequestedCapacityMax,
+		}
+		err := v.srv.RPC("ClientCSI.NodeExpandVolume",
+			req, &cstructs.ClientCSINodeExpandVolumeResponse{})
+		if err != nil {
+			multierror.Append(&mErr, fmt.Errorf(
+				"could not expand volume %q on node %q: %w", vol.ID, claim.NodeID, err))
+		}
+	}
+
+	for _, claim := range vol.ReadClaims {
+		expand(claim)
+	}
+	for _, claim := range vol.WriteClaims {
+		expand(claim)
+	}
+
+	err := mErr.ErrorOrNil()
+	if err == nil {
+		vol.NodeExpansionRequired = false
+	}
+	return err
+}
+
 func (v *CSIVolume) Delete(args *structs.CSIVolumeDeleteRequest, reply *structs.CSIVolumeDeleteResponse) error {
 	if done, err := v.srv.forward("CSIVolume.Delete", args, args, reply); done {
 		return err
@@ -47,7 +47,7 @@ func TestCSIVolumeEndpoint_Get(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err := state.UpsertCSIVolume(999, vols)
+	err := state.UpsertCSIVolume(structs.MsgTypeTestSetup, 999, vols)
 	require.NoError(t, err)
 
 	// Create the register request
@@ -97,7 +97,7 @@ func TestCSIVolumeEndpoint_Get_ACL(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err := state.UpsertCSIVolume(999, vols)
+	err := state.UpsertCSIVolume(structs.MsgTypeTestSetup, 999, vols)
 	require.NoError(t, err)
 
 	// Create the register request
@@ -132,7 +132,7 @@ func TestCSIVolumeEndpoint_Register(t *testing.T) {
 
 	// Create the register request
 	ns := mock.Namespace()
-	store.UpsertNamespaces(900, []*structs.Namespace{ns})
+	store.UpsertNamespaces(structs.MsgTypeTestSetup, 900, []*structs.Namespace{ns})
 
 	// Create the node and plugin
 	node := mock.Node()
@@ -291,7 +291,7 @@ func TestCSIVolumeEndpoint_Claim(t *testing.T) {
 		}},
 	}}
 	index++
-	err = state.UpsertCSIVolume(index, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, vols)
 	require.NoError(t, err)
 
 	// Verify that the volume exists, and is healthy
@@ -430,7 +430,7 @@ func TestCSIVolumeEndpoint_ClaimWithController(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err = state.UpsertCSIVolume(1003, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1003, vols)
 	require.NoError(t, err)
 
 	alloc := mock.BatchAlloc()
@@ -550,7 +550,7 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 			}
 
 			index++
-			err = state.UpsertCSIVolume(index, []*structs.CSIVolume{vol})
+			err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol})
 			require.NoError(t, err)
 
 			// setup: create an alloc that will claim our volume
@@ -571,7 +571,7 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 
 			index++
 			claim.State = structs.CSIVolumeClaimStateTaken
-			err = state.CSIVolumeClaim(index, ns, volID, claim)
+			err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, volID, claim)
 			require.NoError(t, err)
 
 			// test: unpublish and check the results
@@ -657,7 +657,7 @@ func TestCSIVolumeEndpoint_List(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err = state.UpsertCSIVolume(1002, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1002, vols)
 	require.NoError(t, err)
 
 	// Query everything in the namespace
@@ -702,7 +702,7 @@ func TestCSIVolumeEndpoint_ListAllNamespaces(t *testing.T) {
 	ns0 := structs.DefaultNamespace
 	ns1 := "namespace-1"
 	ns2 := "namespace-2"
-	err := state.UpsertNamespaces(1000, []*structs.Namespace{{Name: ns1}, {Name: ns2}})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{{Name: ns1}, {Name: ns2}})
 	require.NoError(t, err)
 
 	// Create volumes in multiple namespaces.
@@ -736,7 +736,7 @@ func TestCSIVolumeEndpoint_ListAllNamespaces(t *testing.T) {
 		}},
 	},
 	}
-	err = state.UpsertCSIVolume(1001, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1001, vols)
 	require.NoError(t, err)
 
 	// Lookup volumes in all namespaces
@@ -801,7 +801,7 @@ func TestCSIVolumeEndpoint_List_PaginationFiltering(t *testing.T) {
 	plugin := mock.CSIPlugin()
 
 	// Create namespaces.
-	err := state.UpsertNamespaces(999, []*structs.Namespace{{Name: nonDefaultNS}})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 999, []*structs.Namespace{{Name: nonDefaultNS}})
 	require.NoError(t, err)
 
 	for i, m := range mocks {
@@ -815,7 +815,7 @@ func TestCSIVolumeEndpoint_List_PaginationFiltering(t *testing.T) {
 			volume.Namespace = m.namespace
 		}
 		index := 1000 + uint64(i)
-		require.NoError(t, state.UpsertCSIVolume(index, []*structs.CSIVolume{volume}))
+		require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{volume}))
 	}
 
 	cases := []struct {
@@ -1108,6 +1108,155 @@ func TestCSIVolumeEndpoint_Create(t *testing.T) {
 	require.Equal(t, map[string]string{"rack": "R1"}, vol.Topologies[0].Segments)
 }
 
+func TestCSIVolumeEndpoint_Create_Expand(t *testing.T) {
+	ci.Parallel(t)
+	var err error
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+
+	testutil.WaitForLeader(t, srv.RPC)
+
+	fake := newMockClientCSI()
+	fake.NextExpandResponse = &cstructs.ClientCSIControllerExpandVolumeResponse{
+		CapacityBytes:         100,
+		NodeExpansionRequired: true,
+	}
+
+	client, cleanup := client.TestClientWithRPCs(t,
+		func(c *cconfig.Config) {
+			c.Servers = []string{srv.config.RPCAddr.String()}
+		},
+		map[string]interface{}{"CSI": fake},
+	)
+	defer cleanup()
+
+	node := client.Node()
+	node.Attributes["nomad.version"] = "0.11.0" // client RPCs not supported on early versions
+
+	req0 := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp0 structs.NodeUpdateResponse
+	err = client.RPC("Node.Register", req0, &resp0)
+	require.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := srv.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a client")
+	})
+
+	ns := structs.DefaultNamespace
+
+	state := srv.fsm.State()
+	codec := rpcClient(t, srv)
+	index := uint64(1000)
+
+	node.CSIControllerPlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID: "minnie",
+			Healthy:  true,
+			ControllerInfo: &structs.CSIControllerInfo{
+				SupportsAttachDetach: true,
+				SupportsCreateDelete: true,
+				SupportsExpand:       true,
+			},
+			RequiresControllerPlugin: true,
+		},
+	}
+	node.CSINodePlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID: "minnie",
+			Healthy:  true,
+			NodeInfo: &structs.CSINodeInfo{SupportsExpand: true},
+		},
+	}
+	index++
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, index, node))
+
+	// setup: an existing volume with a claim by a running alloc
+	volID := uuid.Generate()
+	capabilities := []*structs.CSIVolumeCapability{{
+		AccessMode:     structs.CSIVolumeAccessModeSingleNodeWriter,
+		AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+	}}
+	vol := &structs.CSIVolume{
+		ID:                    volID,
+		Name:                  "vol",
+		Namespace:             ns,
+		PluginID:              "minnie",
+		ExternalID:            "vol-12345",
+		ControllerRequired:    true,
+		Capacity:              42,
+		RequestedCapacityMin:  42,
+		RequestedCapabilities: capabilities,
+	}
+	index++
+	require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol}))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	index++
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+
+	claim := &structs.CSIVolumeClaim{
+		AllocationID:   alloc.ID,
+		NodeID:         node.ID,
+		Mode:           structs.CSIVolumeClaimWrite,
+		AccessMode:     structs.CSIVolumeAccessModeSingleNodeWriter,
+		AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+		State:          structs.CSIVolumeClaimStateTaken,
+	}
+	index++
+	require.NoError(t, state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, volID, claim))
+
+	// test: shrinking the volume is rejected
+	req := &structs.CSIVolumeCreateRequest{
+		Volumes: []*structs.CSIVolume{{
+			ID:                    volID,
+			Name:                  "vol",
+			PluginID:              "minnie",
+			RequestedCapacityMin:  10,
+			RequestedCapacityMax:  20,
+			RequestedCapabilities: capabilities,
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: ns,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "CSIVolume.Create", req, &structs.CSIVolumeCreateResponse{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "volume requested capacity update was not compatible with existing capacity")
+
+	// test: growing the requested capacity expands the volume
+	req.Volumes = []*structs.CSIVolume{{
+		ID:                    volID,
+		Name:                  "vol",
+		PluginID:              "minnie",
+		RequestedCapacityMin:  100,
+		RequestedCapabilities: capabilities,
+	}}
+	err = msgpackrpc.CallWithCodec(codec, "CSIVolume.Create", req, &structs.CSIVolumeCreateResponse{})
+	require.NoError(t, err)
+
+	vol, err = state.CSIVolumeByID(nil, ns, volID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), vol.Capacity)
+	require.Equal(t, int64(100), vol.RequestedCapacityMin)
+	require.False(t, vol.NodeExpansionRequired)
+	require.Len(t, vol.WriteClaims, 1)
+
+	require.NotNil(t, fake.LastNodeExpandRequest)
+	require.Equal(t, alloc.ID, fake.LastNodeExpandRequest.AllocID)
+	require.Equal(t, "vol-12345", fake.LastNodeExpandRequest.ExternalID)
+	require.Equal(t, int64(100), fake.LastNodeExpandRequest.CapacityMin)
+}
+
 func TestCSIVolumeEndpoint_Delete(t *testing.T) {
 	ci.Parallel(t)
 	var err error
@@ -1181,7 +1330,7 @@ func TestCSIVolumeEndpoint_Delete(t *testing.T) {
 		Secrets:   structs.CSISecrets{"mysecret": "secretvalue"},
 	}}
 	index++
-	err = state.UpsertCSIVolume(index, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, vols)
 	require.NoError(t, err)
 
 	// Delete volumes
@@ -1405,7 +1554,7 @@ func TestCSIVolumeEndpoint_CreateSnapshot(t *testing.T) {
 		ExternalID:     "vol-12345",
 	}}
 	index++
-	require.NoError(t, state.UpsertCSIVolume(index, vols))
+	require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, vols))
 
 	// Create the snapshot request
 	req1 := &structs.CSISnapshotCreateRequest{
@@ -1881,7 +2030,7 @@ func TestCSI_RPCVolumeAndPluginLookup(t *testing.T) {
 			ControllerRequired: false,
 		},
 	}
-	err = state.UpsertCSIVolume(1002, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1002, vols)
 	require.NoError(t, err)
 
 	// has controller
@@ -50,3 +50,51 @@ func (m *MockNodeTracker) events() []*MockNodeTrackerEvent {
 
 	return m.Events
 }
+
+type MockJobWatcher struct {
+	Jobs      map[structs.NamespacedID]struct{}
+	Refreshes int
+	sync.Mutex
+}
+
+func NewMockJobWatcher() *MockJobWatcher {
+	return &MockJobWatcher{
+		Jobs: make(map[structs.NamespacedID]struct{}),
+	}
+}
+
+func (m *MockJobWatcher) RegisterJobs(jobs []structs.NamespacedID) {
+	m.Lock()
+	defer m.Unlock()
+	for _, jns := range jobs {
+		m.Jobs[jns] = struct{}{}
+	}
+}
+
+func (m *MockJobWatcher) Refresh() {
+	m.Lock()
+	defer m.Unlock()
+	m.Refreshes++
+}
+
+func (m *MockJobWatcher) Drain() <-chan *DrainRequest {
+	return nil
+}
+
+func (m *MockJobWatcher) Migrated() <-chan []*structs.Allocation {
+	return nil
+}
+
+func (m *MockJobWatcher) registered() map[structs.NamespacedID]struct{} {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.Jobs
+}
+
+func (m *MockJobWatcher) refreshes() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.Refreshes
+}
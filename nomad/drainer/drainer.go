@@ -134,6 +134,10 @@ type NodeDrainer struct {
 	// nodes is the set of draining nodes
 	nodes map[string]*drainingNode
 
+	// windowTimers holds the timers that start the drain of nodes waiting for
+	// their drain window to open.
+	windowTimers map[string]*time.Timer
+
 	// nodeWatcher watches for nodes to transition in and out of drain state.
 	nodeWatcher DrainingNodeWatcher
 	nodeFactory DrainingNodeWatcherFactory
@@ -213,11 +217,16 @@ func (n *NodeDrainer) flush(state *state.StateStore) {
 		n.state = state
 	}
 
+	for _, timer := range n.windowTimers {
+		timer.Stop()
+	}
+
 	n.ctx, n.exitFn = context.WithCancel(context.Background())
 	n.jobWatcher = n.jobFactory(n.ctx, n.queryLimiter, n.state, n.logger)
 	n.nodeWatcher = n.nodeFactory(n.ctx, n.queryLimiter, n.state, n.logger, n)
 	n.deadlineNotifier = n.deadlineNotifierFactory(n.ctx)
 	n.nodes = make(map[string]*drainingNode, 32)
+	n.windowTimers = make(map[string]*time.Timer)
 }
 
 // run is a long lived event handler that receives changes from the relevant
@@ -3,7 +3,9 @@ package drainer
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
@@ -31,6 +33,10 @@ type DrainingJobWatcher interface {
 	// RegisterJob is used to start watching a draining job
 	RegisterJobs(job []structs.NamespacedID)
 
+	// Refresh is used to re-evaluate the watched jobs even though their
+	// allocations have not changed, such as when a drain window opens.
+	Refresh()
+
 	// Drain is used to emit allocations that should be drained.
 	Drain() <-chan *DrainRequest
 
@@ -107,13 +113,27 @@ func (w *drainingJobWatcher) RegisterJobs(jobs []structs.NamespacedID) {
 	}
 
 	if updated {
-		w.queryCancel()
-
-		// Create a new query context
-		w.queryCtx, w.queryCancel = context.WithCancel(w.ctx)
+		w.resetQuery()
 	}
 }
 
+// Refresh cancels the running blocking query so the watched jobs are handled
+// again.
+func (w *drainingJobWatcher) Refresh() {
+	w.l.Lock()
+	defer w.l.Unlock()
+	w.resetQuery()
+}
+
+// resetQuery cancels the running blocking query and creates a new query
+// context. The caller must hold the lock.
+func (w *drainingJobWatcher) resetQuery() {
+	w.queryCancel()
+
+	// Create a new query context
+	w.queryCtx, w.queryCancel = context.WithCancel(w.ctx)
+}
+
 // Drain returns the channel that emits allocations to drain.
 func (w *drainingJobWatcher) Drain() <-chan *DrainRequest {
 	return w.drainCh
@@ -234,6 +254,14 @@ func (w *drainingJobWatcher) watch() {
 			}
 		}
 
+		// Hold back allocations that must wait for the drain order or the
+		// migration limit of their node
+		allDrain, err = filterDrains(snap, allDrain)
+		if err != nil {
+			w.logger.Error("failed to apply the drain order", "error", err)
+			allDrain = nil
+		}
+
 		if len(allDrain) != 0 {
 			// Create the request
 			req := NewDrainRequest(allDrain)
@@ -343,6 +371,7 @@ func handleTaskGroup(snap *state.StateSnapshot, batch bool, tg *structs.TaskGrou
 	allocs []*structs.Allocation, lastHandledIndex uint64, result *jobResult) error {
 
 	// Determine how many allocations can be drained
+	now := time.Now()
 	drainingNodes := make(map[string]bool, 4)
 	healthy := 0
 	remainingDrainingAlloc := false
@@ -359,7 +388,9 @@ func handleTaskGroup(snap *state.StateSnapshot, batch bool, tg *structs.TaskGrou
 			}
 
 			// Check if the node exists and whether it has a drain strategy
-			onDrainingNode = node != nil && node.DrainStrategy != nil
+			// whose window is open
+			onDrainingNode = node != nil && node.DrainStrategy != nil &&
+				node.DrainStrategy.WindowOpen(now)
 			drainingNodes[alloc.NodeID] = onDrainingNode
 		}
 
@@ -420,6 +451,150 @@ func handleTaskGroup(snap *state.StateSnapshot, batch bool, tg *structs.TaskGrou
 	return nil
 }
 
+// filterDrains returns the allocations that may be marked for migration now.
+// Allocations on nodes with a drain order are held back while allocations of
+// jobs ordered before theirs remain on the node, and allocations on nodes with
+// a migration limit are held back while the cluster is migrating as many
+// allocations as the limit allows.
+func filterDrains(snap *state.StateSnapshot, allocs []*structs.Allocation) ([]*structs.Allocation, error) {
+	nodes := make(map[string]*structs.Node, 4)
+	limited := false
+	for _, alloc := range allocs {
+		if _, ok := nodes[alloc.NodeID]; ok {
+			continue
+		}
+		node, err := snap.NodeByID(nil, alloc.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		nodes[alloc.NodeID] = node
+		if node != nil && node.DrainStrategy != nil && node.DrainStrategy.MaxParallelMigrations > 0 {
+			limited = true
+		}
+	}
+
+	// Capture the rank of each allocation and the lowest rank remaining on
+	// each ordered node
+	ranks := make(map[string]int, len(allocs))
+	minRanks := make(map[string]int, len(nodes))
+	for nodeID, node := range nodes {
+		if node == nil || node.DrainStrategy == nil || !node.DrainStrategy.Ordered() {
+			continue
+		}
+		minRank, err := minRemainingRank(snap, node)
+		if err != nil {
+			return nil, err
+		}
+		minRanks[nodeID] = minRank
+	}
+
+	filtered := make([]*structs.Allocation, 0, len(allocs))
+	for _, alloc := range allocs {
+		minRank, ok := minRanks[alloc.NodeID]
+		if !ok {
+			filtered = append(filtered, alloc)
+			continue
+		}
+		rank := nodes[alloc.NodeID].DrainStrategy.JobRank(alloc.Job)
+		if rank <= minRank {
+			ranks[alloc.ID] = rank
+			filtered = append(filtered, alloc)
+		}
+	}
+
+	if !limited {
+		return filtered, nil
+	}
+
+	// Prefer the allocations that come first in the drain order when there
+	// is not enough room for all of them
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return ranks[filtered[i].ID] < ranks[filtered[j].ID]
+	})
+
+	migrating, err := migratingAllocs(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := filtered[:0]
+	for _, alloc := range filtered {
+		node := nodes[alloc.NodeID]
+		if node != nil && node.DrainStrategy != nil {
+			limit := node.DrainStrategy.MaxParallelMigrations
+			if limit > 0 && migrating >= limit {
+				continue
+			}
+		}
+		allowed = append(allowed, alloc)
+		migrating++
+	}
+	return allowed, nil
+}
+
+// minRemainingRank returns the lowest drain rank of the migratable
+// allocations remaining on the node.
+func minRemainingRank(snap *state.StateSnapshot, node *structs.Node) (int, error) {
+	allocs, err := snap.AllocsByNode(nil, node.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	minRank := -1
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() || alloc.Job == nil || alloc.Job.Type != structs.JobTypeService {
+			continue
+		}
+		rank := node.DrainStrategy.JobRank(alloc.Job)
+		if minRank == -1 || rank < minRank {
+			minRank = rank
+		}
+	}
+	return minRank, nil
+}
+
+// migratingAllocs returns the number of allocations across all draining nodes
+// that are marked for migration and whose replacement is not yet healthy.
+func migratingAllocs(snap *state.StateSnapshot) (int, error) {
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	migrating := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if node.DrainStrategy == nil {
+			continue
+		}
+
+		allocs, err := snap.AllocsByNode(nil, node.ID)
+		if err != nil {
+			return 0, err
+		}
+		for _, alloc := range allocs {
+			if !alloc.DesiredTransition.ShouldMigrate() {
+				continue
+			}
+			if !alloc.TerminalStatus() {
+				migrating++
+				continue
+			}
+			if alloc.NextAllocation == "" {
+				continue
+			}
+			next, err := snap.AllocByID(nil, alloc.NextAllocation)
+			if err != nil {
+				return 0, err
+			}
+			if next != nil && !next.TerminalStatus() && !next.DeploymentStatus.IsHealthy() {
+				migrating++
+			}
+		}
+	}
+	return migrating, nil
+}
+
 // getJobAllocs returns all allocations for draining jobs
 func (w *drainingJobWatcher) getJobAllocs(ctx context.Context, minIndex uint64) (map[structs.NamespacedID][]*structs.Allocation, uint64, error) {
 	if err := w.limiter.Wait(ctx); err != nil {
@@ -742,3 +742,175 @@ func TestHandleTaskGroup_GarbageCollectedNode(t *testing.T) {
 	require.Empty(res.migrated)
 	require.True(res.done)
 }
+
+// TestHandleTaskGroup_DrainWindow asserts allocations on a node waiting for its
+// drain window are not drained.
+func TestHandleTaskGroup_DrainWindow(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := state.TestStateStore(t)
+	n := mock.Node()
+	n.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline: 5 * time.Minute,
+		},
+		WindowStart: time.Now().Add(time.Hour),
+	}
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 100, n))
+
+	job := mock.Job()
+	require.Nil(state.UpsertJob(structs.MsgTypeTestSetup, 101, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		a := mock.Alloc()
+		a.Job = job
+		a.TaskGroup = job.TaskGroups[0].Name
+		a.NodeID = n.ID
+		a.DeploymentStatus = &structs.AllocDeploymentStatus{
+			Healthy: helper.BoolToPtr(true),
+		}
+		allocs = append(allocs, a)
+	}
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 102, allocs))
+
+	snap, err := state.Snapshot()
+	require.Nil(err)
+
+	res := newJobResult()
+	require.Nil(handleTaskGroup(snap, false, job.TaskGroups[0], allocs, 101, res))
+	require.Empty(res.drain)
+	require.True(res.done)
+
+	// Open the window
+	drain := n.DrainStrategy.Copy()
+	drain.WindowStart = time.Now().Add(-time.Minute)
+	require.Nil(state.UpdateNodeDrain(structs.MsgTypeTestSetup, 103, n.ID, drain, false, 0, nil, nil, ""))
+
+	snap, err = state.Snapshot()
+	require.Nil(err)
+
+	res = newJobResult()
+	require.Nil(handleTaskGroup(snap, false, job.TaskGroups[0], allocs, 101, res))
+	require.Len(res.drain, 1)
+	require.False(res.done)
+}
+
+// TestFilterDrains_JobOrder asserts allocations are held back until the
+// allocations of jobs earlier in the drain order have left the node.
+func TestFilterDrains_JobOrder(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := state.TestStateStore(t)
+	low, high, first := mock.Job(), mock.Job(), mock.Job()
+	low.Priority = 10
+	high.Priority = 90
+	first.Priority = 10
+
+	n := mock.Node()
+	n.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline:        time.Hour,
+			JobOrder:        []string{first.ID},
+			OrderByPriority: true,
+		},
+	}
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 100, n))
+
+	var allocs []*structs.Allocation
+	for _, job := range []*structs.Job{low, high, first} {
+		a := mock.Alloc()
+		a.JobID = job.ID
+		a.Job = job
+		a.NodeID = n.ID
+		allocs = append(allocs, a)
+	}
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 101, allocs))
+
+	// The job listed in the order goes first
+	snap, err := state.Snapshot()
+	require.Nil(err)
+	drain, err := filterDrains(snap, allocs)
+	require.Nil(err)
+	require.Len(drain, 1)
+	require.Equal(first.ID, drain[0].JobID)
+
+	// Then the higher priority job, even while the listed job is migrating
+	stopped := allocs[2].Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 102, []*structs.Allocation{stopped}))
+
+	snap, err = state.Snapshot()
+	require.Nil(err)
+	drain, err = filterDrains(snap, allocs[:2])
+	require.Nil(err)
+	require.Len(drain, 1)
+	require.Equal(high.ID, drain[0].JobID)
+}
+
+// TestFilterDrains_MaxParallelMigrations asserts no more allocations are
+// drained than the node's migration limit allows across the cluster.
+func TestFilterDrains_MaxParallelMigrations(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := state.TestStateStore(t)
+	limited, other := mock.Node(), mock.Node()
+	limited.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline:              time.Hour,
+			MaxParallelMigrations: 3,
+		},
+	}
+	other.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline: time.Hour,
+		},
+	}
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 100, limited))
+	require.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 101, other))
+
+	job := mock.Job()
+	newAlloc := func(node *structs.Node) *structs.Allocation {
+		a := mock.Alloc()
+		a.JobID = job.ID
+		a.Job = job
+		a.NodeID = node.ID
+		return a
+	}
+
+	// One alloc migrating off the other node, and one migrated alloc whose
+	// replacement is healthy
+	migrating := newAlloc(other)
+	migrating.DesiredTransition.Migrate = helper.BoolToPtr(true)
+	replacement := newAlloc(limited)
+	replacement.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: helper.BoolToPtr(true),
+	}
+	migrated := newAlloc(other)
+	migrated.DesiredTransition.Migrate = helper.BoolToPtr(true)
+	migrated.DesiredStatus = structs.AllocDesiredStatusStop
+	migrated.NextAllocation = replacement.ID
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 102,
+		[]*structs.Allocation{migrating, replacement, migrated}))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 4; i++ {
+		allocs = append(allocs, newAlloc(limited))
+	}
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 103, allocs))
+
+	snap, err := state.Snapshot()
+	require.Nil(err)
+	drain, err := filterDrains(snap, allocs)
+	require.Nil(err)
+	require.Len(drain, 2)
+
+	// Allocs on nodes without a limit are not held back
+	unlimited := []*structs.Allocation{newAlloc(other), newAlloc(other)}
+	drain, err = filterDrains(snap, append(allocs, unlimited...))
+	require.Nil(err)
+	require.Len(drain, 4)
+}
@@ -2,6 +2,7 @@ package drainer
 
 import (
 	"context"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
@@ -37,6 +38,7 @@ func (n *NodeDrainer) Remove(nodeID string) {
 	// Remove it from being tracked and remove it from the dealiner
 	delete(n.nodes, nodeID)
 	n.deadlineNotifier.Remove(nodeID)
+	n.stopWindowTimer(nodeID)
 }
 
 // Update updates the node, either updating the tracked version or starting to
@@ -67,6 +69,16 @@ func (n *NodeDrainer) Update(node *structs.Node) {
 		n.deadlineNotifier.Remove(node.ID)
 	}
 
+	// Wait for the drain window to open before migrating anything off the
+	// node. The deadline is measured from the start of the window.
+	n.stopWindowTimer(node.ID)
+	if now := time.Now(); !node.DrainStrategy.WindowOpen(now) {
+		start := node.DrainStrategy.WindowStart
+		n.logger.Debug("node is waiting for its drain window", "node_id", node.ID, "window_start", start)
+		n.windowTimers[node.ID] = time.AfterFunc(start.Sub(now), n.windowOpenFn(n.ctx, node.ID))
+		return
+	}
+
 	// TODO Test this
 	// Register interest in the draining jobs.
 	jobs, err := draining.DrainingJobs()
@@ -115,6 +127,47 @@ func (n *NodeDrainer) Update(node *structs.Node) {
 	}
 }
 
+// stopWindowTimer stops waiting for the drain window of the node. The caller
+// must hold the lock.
+func (n *NodeDrainer) stopWindowTimer(nodeID string) {
+	if timer, ok := n.windowTimers[nodeID]; ok {
+		timer.Stop()
+		delete(n.windowTimers, nodeID)
+	}
+}
+
+// windowOpenFn returns the function that starts the drain of a node once its
+// drain window opens.
+func (n *NodeDrainer) windowOpenFn(ctx context.Context, nodeID string) func() {
+	return func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		n.l.RLock()
+		store := n.state
+		n.l.RUnlock()
+
+		node, err := store.NodeByID(nil, nodeID)
+		if err != nil {
+			n.logger.Error("failed to lookup node for drain window", "node_id", nodeID, "error", err)
+			return
+		}
+		if node == nil || node.DrainStrategy == nil {
+			return
+		}
+
+		n.logger.Debug("drain window opened", "node_id", nodeID)
+		n.Update(node)
+
+		// Jobs already watched for other nodes have to be handled again to
+		// pick up the allocations on this node.
+		n.l.RLock()
+		n.jobWatcher.Refresh()
+		n.l.RUnlock()
+	}
+}
+
 // nodeDrainWatcher is used to watch nodes that are entering, leaving or
 // changing their drain strategy.
 type nodeDrainWatcher struct {
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -180,3 +181,52 @@ func TestNodeDrainWatcher_Update(t *testing.T) {
 	require.Contains(tracked, n.ID)
 	require.Equal(s2, tracked[n.ID].DrainStrategy)
 }
+
+// TestNodeDrainer_DrainWindow asserts a node waiting for its drain window is
+// tracked but its jobs are only watched once the window opens.
+func TestNodeDrainer_DrainWindow(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	store := state.TestStateStore(t)
+	n := mock.Node()
+	n.DrainStrategy = &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline: time.Hour,
+		},
+		WindowStart: time.Now().Add(500 * time.Millisecond),
+	}
+	require.Nil(store.UpsertNode(structs.MsgTypeTestSetup, 100, n))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = n.ID
+	require.Nil(store.UpsertJob(structs.MsgTypeTestSetup, 101, alloc.Job))
+	require.Nil(store.UpsertAllocs(structs.MsgTypeTestSetup, 102, []*structs.Allocation{alloc}))
+
+	jobWatcher := NewMockJobWatcher()
+	jobFactory := func(context.Context, *rate.Limiter, *state.StateStore, log.Logger) DrainingJobWatcher {
+		return jobWatcher
+	}
+	nodeFactory := func(context.Context, *rate.Limiter, *state.StateStore, log.Logger, NodeTracker) DrainingNodeWatcher {
+		return nil
+	}
+	drainer := &NodeDrainer{
+		logger:                  testlog.HCLogger(t),
+		jobFactory:              jobFactory,
+		nodeFactory:             nodeFactory,
+		deadlineNotifierFactory: GetDeadlineNotifier,
+	}
+	drainer.flush(store)
+	defer drainer.exitFn()
+
+	drainer.Update(n)
+	require.Contains(drainer.TrackedNodes(), n.ID)
+	require.Empty(jobWatcher.registered())
+
+	testutil.WaitForResult(func() (bool, error) {
+		return len(jobWatcher.registered()) == 1, nil
+	}, func(err error) {
+		t.Fatal("jobs were not registered once the drain window opened")
+	})
+	require.Equal(1, jobWatcher.refreshes())
+}
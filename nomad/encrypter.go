@@ -0,0 +1,442 @@
+package nomad
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// keystoreDir is the directory, within the server data directory, where
+	// root key material is persisted.
+	keystoreDir = "keystore"
+
+	// keystoreFileExt is the extension of the files within the keystore.
+	keystoreFileExt = ".nks.json"
+)
+
+// errKeyringNotInitialized is returned when the leader has not yet created
+// the first root key.
+var errKeyringNotInitialized = errors.New("keyring has not been initialized yet")
+
+// Encrypter is the keyring for encrypting and decrypting variables, and for
+// signing and verifying workload identities. Root key metadata is replicated via Raft, but the key material is held only within
+// the memory and local keystore of each server.
+type Encrypter struct {
+	srv          *Server
+	keystorePath string
+
+	keyring map[string]*keyset
+	lock    sync.RWMutex
+}
+
+// keyset is a root key and the cipher and signing key built from it.
+type keyset struct {
+	rootKey    *structs.RootKey
+	cipher     cipher.AEAD
+	privateKey ed25519.PrivateKey
+}
+
+// NewEncrypter loads or creates a new local keystore and returns an
+// encryption keyring with the keys it finds. An empty keystore path results
+// in keys only being held in memory.
+func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
+	encrypter := &Encrypter{
+		srv:          srv,
+		keystorePath: keystorePath,
+		keyring:      make(map[string]*keyset),
+	}
+	if keystorePath == "" {
+		return encrypter, nil
+	}
+
+	if err := os.MkdirAll(keystorePath, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore: %v", err)
+	}
+	if err := encrypter.loadKeystore(); err != nil {
+		return nil, err
+	}
+	return encrypter, nil
+}
+
+func (e *Encrypter) loadKeystore() error {
+	files, err := ioutil.ReadDir(e.keystorePath)
+	if err != nil {
+		return fmt.Errorf("failed to read keystore: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), keystoreFileExt) {
+			continue
+		}
+		rootKey, err := e.loadKeyFromStore(filepath.Join(e.keystorePath, file.Name()))
+		if err != nil {
+			return err
+		}
+		if err := e.addCipher(rootKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encrypt encrypts the cleartext using the active root key, returning the
+// ciphertext and the ID of the key used. The ciphertext is prefixed with the
+// nonce.
+func (e *Encrypter) Encrypt(cleartext []byte) ([]byte, string, error) {
+	keyset, err := e.activeKeyset()
+	if err != nil {
+		return nil, "", err
+	}
+
+	nonce := make([]byte, keyset.cipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return keyset.cipher.Seal(nonce, nonce, cleartext, nil), keyset.rootKey.Meta.KeyID, nil
+}
+
+// Decrypt decrypts the ciphertext using the root key with the given ID.
+func (e *Encrypter) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := keyset.cipher.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	return keyset.cipher.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
+
+// SignClaims signs the identity claims using the active root key, returning
+// the signed JWT and the ID of the key used. The key ID is set as the "kid"
+// header so the signature can be verified with the published public key.
+func (e *Encrypter) SignClaims(claims *structs.IdentityClaims) (string, string, error) {
+	keyset, err := e.activeKeyset()
+	if err != nil {
+		return "", "", err
+	}
+
+	keyID := keyset.rootKey.Meta.KeyID
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = keyID
+
+	signed, err := token.SignedString(keyset.privateKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign claims: %v", err)
+	}
+	return signed, keyID, nil
+}
+
+// VerifyClaims verifies the signature of the JWT using the root key which
+// signed it, and returns its identity claims.
+func (e *Encrypter) VerifyClaims(signed string) (*structs.IdentityClaims, error) {
+	token, err := jwt.ParseWithClaims(signed, &structs.IdentityClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
+		}
+		keyID, ok := token.Header["kid"].(string)
+		if !ok || keyID == "" {
+			return nil, fmt.Errorf("missing key ID header")
+		}
+
+		e.lock.RLock()
+		defer e.lock.RUnlock()
+
+		keyset, err := e.keysetByIDLocked(keyID)
+		if err != nil {
+			return nil, err
+		}
+		return keyset.privateKey.Public(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify token: %v", err)
+	}
+
+	claims, ok := token.Claims.(*structs.IdentityClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("failed to verify token: invalid token")
+	}
+	return claims, nil
+}
+
+// GetPublicKey returns the public key derived from the root key with the
+// given ID, which verifies the workload identities it signed.
+func (e *Encrypter) GetPublicKey(keyID string) (*structs.KeyringPublicKey, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &structs.KeyringPublicKey{
+		KeyID:      keyID,
+		PublicKey:  keyset.privateKey.Public().(ed25519.PublicKey),
+		Algorithm:  structs.PubKeyAlgEdDSA,
+		Use:        structs.PubKeyUseSig,
+		CreateTime: keyset.rootKey.Meta.CreateTime,
+	}, nil
+}
+
+// AddKey stores the root key in the keystore and adds it to the keyring.
+func (e *Encrypter) AddKey(rootKey *structs.RootKey) error {
+	if err := rootKey.Meta.Validate(); err != nil {
+		return err
+	}
+	if err := e.addCipher(rootKey); err != nil {
+		return err
+	}
+	return e.saveKeyToStore(rootKey)
+}
+
+// GetKey returns the root key with the given ID.
+func (e *Encrypter) GetKey(keyID string) (*structs.RootKey, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return keyset.rootKey.Copy(), nil
+}
+
+// hasKey returns whether the keyring holds the root key with the given ID.
+func (e *Encrypter) hasKey(keyID string) bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	_, ok := e.keyring[keyID]
+	return ok
+}
+
+// addCipher builds the cipher for the root key and adds it to the keyring.
+func (e *Encrypter) addCipher(rootKey *structs.RootKey) error {
+	if rootKey == nil || rootKey.Meta == nil {
+		return fmt.Errorf("missing root key metadata")
+	}
+
+	var aead cipher.AEAD
+
+	switch rootKey.Meta.Algorithm {
+	case structs.EncryptionAlgorithmAES256GCM:
+		block, err := aes.NewCipher(rootKey.Key)
+		if err != nil {
+			return fmt.Errorf("could not create cipher: %v", err)
+		}
+		aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("could not create cipher: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid algorithm %s", rootKey.Meta.Algorithm)
+	}
+
+	// The signing key is derived from the root key, so that it never needs
+	// to be replicated separately.
+	if len(rootKey.Key) != ed25519.SeedSize {
+		return fmt.Errorf("could not create signing key: invalid key size %d", len(rootKey.Key))
+	}
+	privateKey := ed25519.NewKeyFromSeed(rootKey.Key)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.keyring[rootKey.Meta.KeyID] = &keyset{
+		rootKey:    rootKey.Copy(),
+		cipher:     aead,
+		privateKey: privateKey,
+	}
+	return nil
+}
+
+// activeKeyset returns the keyset of the active root key.
+func (e *Encrypter) activeKeyset() (*keyset, error) {
+	keyMeta, err := e.srv.fsm.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		return nil, err
+	}
+	if keyMeta == nil {
+		return nil, errKeyringNotInitialized
+	}
+
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.keysetByIDLocked(keyMeta.KeyID)
+}
+
+func (e *Encrypter) keysetByIDLocked(keyID string) (*keyset, error) {
+	keyset, ok := e.keyring[keyID]
+	if !ok {
+		return nil, fmt.Errorf("no such key %q in keyring", keyID)
+	}
+	return keyset, nil
+}
+
+// saveKeyToStore persists the root key to the keystore. This is a no-op when
+// the keystore is held only in memory.
+func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
+	if e.keystorePath == "" {
+		return nil
+	}
+
+	buf, err := json.Marshal(rootKey)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(e.keystorePath, rootKey.Meta.KeyID+keystoreFileExt)
+	if err := ioutil.WriteFile(path, buf, 0600); err != nil {
+		return fmt.Errorf("failed to write key to keystore: %v", err)
+	}
+	return nil
+}
+
+// loadKeyFromStore reads a root key from the keystore.
+func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key from keystore: %v", err)
+	}
+
+	var rootKey structs.RootKey
+	if err := json.Unmarshal(raw, &rootKey); err != nil {
+		return nil, fmt.Errorf("failed to decode key %q from keystore: %v", path, err)
+	}
+	if err := rootKey.Meta.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid key %q in keystore: %v", path, err)
+	}
+	return &rootKey, nil
+}
+
+// keyringReplicator fetches root key material which is missing from the
+// local keyring from the other servers in the region.
+type keyringReplicator struct {
+	srv       *Server
+	encrypter *Encrypter
+	logger    log.Logger
+}
+
+func newKeyringReplicator(srv *Server, e *Encrypter) *keyringReplicator {
+	return &keyringReplicator{
+		srv:       srv,
+		encrypter: e,
+		logger:    srv.logger.Named("keyring.replicator"),
+	}
+}
+
+// run watches the root key metadata held in state and replicates the key
+// material of any key not found within the local keyring. It is run on all
+// servers until the context is cancelled.
+func (krr *keyringReplicator) run(ctx context.Context) {
+	krr.logger.Debug("starting encryption key replication")
+	defer krr.logger.Debug("exiting key replication")
+
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		store := krr.srv.fsm.State()
+		ws := memdb.NewWatchSet()
+		ws.Add(store.AbandonCh())
+
+		iter, err := store.RootKeyMetas(ws)
+		if err != nil {
+			krr.logger.Error("failed to fetch keyring", "error", err)
+			continue
+		}
+
+		failed := false
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			keyMeta := raw.(*structs.RootKeyMeta)
+			if krr.encrypter.hasKey(keyMeta.KeyID) {
+				continue
+			}
+			if err := krr.replicateKey(keyMeta); err != nil {
+				krr.logger.Error("failed to replicate key", "key_id", keyMeta.KeyID, "error", err)
+				failed = true
+			}
+		}
+
+		// If any key could not be fetched, retry shortly rather than waiting
+		// for the keyring to change.
+		if failed {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		if err := ws.WatchCtx(ctx); err == context.Canceled {
+			return
+		}
+	}
+}
+
+// replicateKey fetches the root key with the given metadata from a peer and
+// adds it to the local keyring.
+func (krr *keyringReplicator) replicateKey(keyMeta *structs.RootKeyMeta) error {
+	req := &structs.KeyringGetRootKeyRequest{
+		KeyID: keyMeta.KeyID,
+		QueryOptions: structs.QueryOptions{
+			Region: krr.srv.config.Region,
+		},
+	}
+
+	self := fmt.Sprintf("%s.%s", krr.srv.config.NodeName, krr.srv.config.Region)
+
+	krr.srv.peerLock.RLock()
+	peers := make([]*serverParts, 0, len(krr.srv.localPeers))
+	for _, peer := range krr.srv.localPeers {
+		if peer.Name == self {
+			continue
+		}
+		peers = append(peers, peer)
+	}
+	krr.srv.peerLock.RUnlock()
+
+	var lastErr error
+	for _, peer := range peers {
+		var resp structs.KeyringGetRootKeyResponse
+		err := krr.srv.forwardServer(peer, structs.KeyringGetRootKeyRPCMethod, req, &resp)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.Key == nil {
+			lastErr = fmt.Errorf("server %s does not hold the key", peer.Name)
+			continue
+		}
+		return krr.encrypter.AddKey(resp.Key)
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no peers available")
+	}
+	return lastErr
+}
@@ -0,0 +1,146 @@
+package nomad
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncrypter_LoadSave exercises round-tripping keys to disk
+func TestEncrypter_LoadSave(t *testing.T) {
+	ci.Parallel(t)
+
+	tmpDir := t.TempDir()
+	encrypter, err := NewEncrypter(&Server{}, tmpDir)
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, encrypter.AddKey(key))
+
+	// A new encrypter using the same keystore should load the key.
+	encrypter, err = NewEncrypter(&Server{}, tmpDir)
+	require.NoError(t, err)
+
+	out, err := encrypter.GetKey(key.Meta.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, key.Key, out.Key)
+	require.Equal(t, key.Meta.KeyID, out.Meta.KeyID)
+}
+
+func TestEncrypter_EncryptDecrypt(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, shutdown := TestServer(t, nil)
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	// Wait for the leader to initialize the keyring.
+	var keyID string
+	testutil.WaitForResult(func() (bool, error) {
+		keyMeta, err := srv.State().GetActiveRootKeyMeta(nil)
+		if err != nil || keyMeta == nil {
+			return false, err
+		}
+		keyID = keyMeta.KeyID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("keyring was not initialized: %v", err)
+	})
+
+	ciphertext, outKeyID, err := srv.encrypter.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, keyID, outKeyID)
+	require.NotContains(t, string(ciphertext), "hello")
+
+	cleartext, err := srv.encrypter.Decrypt(ciphertext, outKeyID)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(cleartext))
+
+	// Tampered ciphertext should fail to decrypt.
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = srv.encrypter.Decrypt(ciphertext, outKeyID)
+	require.Error(t, err)
+
+	_, err = srv.encrypter.Decrypt(ciphertext, "unknown")
+	require.Error(t, err)
+}
+
+func TestEncrypter_KeyringReplication(t *testing.T) {
+	ci.Parallel(t)
+
+	srv1, cleanupSRV1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.NumSchedulers = 0
+	})
+	defer cleanupSRV1()
+	srv2, cleanupSRV2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.NumSchedulers = 0
+	})
+	defer cleanupSRV2()
+	TestJoin(t, srv1, srv2)
+	testutil.WaitForLeader(t, srv1.RPC)
+
+	// Both servers should end up holding the material of the active key.
+	testutil.WaitForResult(func() (bool, error) {
+		for _, srv := range []*Server{srv1, srv2} {
+			keyMeta, err := srv.State().GetActiveRootKeyMeta(nil)
+			if err != nil || keyMeta == nil {
+				return false, err
+			}
+			if !srv.encrypter.hasKey(keyMeta.KeyID) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("key was not replicated: %v", err)
+	})
+}
+
+func TestEncrypter_SignVerifyClaims(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, shutdown := TestServer(t, nil)
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	waitForKeyring(t, srv)
+
+	alloc := mock.Alloc()
+	claims := structs.NewIdentityClaims(alloc.Job, alloc, "web", time.Now())
+
+	token, keyID, err := srv.encrypter.SignClaims(claims)
+	require.NoError(t, err)
+	require.NotEmpty(t, keyID)
+	require.True(t, isWorkloadIdentity(token))
+
+	out, err := srv.encrypter.VerifyClaims(token)
+	require.NoError(t, err)
+	require.Equal(t, alloc.Namespace, out.Namespace)
+	require.Equal(t, alloc.JobID, out.JobID)
+	require.Equal(t, alloc.ID, out.AllocationID)
+	require.Equal(t, alloc.TaskGroup, out.TaskGroup)
+	require.Equal(t, "web", out.TaskName)
+
+	// The public key should verify the signature.
+	pubKey, err := srv.encrypter.GetPublicKey(keyID)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(pubKey.PublicKey, []byte(parts[0]+"."+parts[1]), sig))
+
+	// A tampered token should fail verification.
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"nomad_job_id":"other"}`))
+	_, err = srv.encrypter.VerifyClaims(strings.Join(parts, "."))
+	require.Error(t, err)
+}
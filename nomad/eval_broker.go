@@ -65,6 +65,14 @@ type EvalBroker struct {
 	// ready tracks the ready jobs by scheduler in a priority queue
 	ready map[string]PendingEvaluations
 
+	// pausedJobs is the set of jobs whose evaluations are paused. It is kept
+	// when the broker is flushed, since it mirrors the state store rather
+	// than the queued evaluations.
+	pausedJobs map[structs.NamespacedID]struct{}
+
+	// paused tracks the evaluations held for paused jobs by JobID
+	paused map[structs.NamespacedID][]*structs.Evaluation
+
 	// unack is a map of evalID to an un-acknowledged evaluation
 	unack map[string]*unackEval
 
@@ -78,7 +86,7 @@ type EvalBroker struct {
 	requeue map[string]*structs.Evaluation
 
 	// timeWait has evaluations that are waiting for time to elapse
-	timeWait map[string]*time.Timer
+	timeWait map[string]*waitingEval
 
 	// delayedEvalCancelFunc is used to stop the long running go routine
 	// that processes delayed evaluations
@@ -104,6 +112,12 @@ type EvalBroker struct {
 	l sync.RWMutex
 }
 
+// waitingEval tracks an evaluation waiting to be enqueued along with its timer
+type waitingEval struct {
+	eval  *structs.Evaluation
+	timer *time.Timer
+}
+
 // unackEval tracks an unacknowledged evaluation along with the Nack timer
 type unackEval struct {
 	Eval      *structs.Evaluation
@@ -136,10 +150,12 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		jobEvals:             make(map[structs.NamespacedID]string),
 		blocked:              make(map[structs.NamespacedID]PendingEvaluations),
 		ready:                make(map[string]PendingEvaluations),
+		pausedJobs:           make(map[structs.NamespacedID]struct{}),
+		paused:               make(map[structs.NamespacedID][]*structs.Evaluation),
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
-		timeWait:             make(map[string]*time.Timer),
+		timeWait:             make(map[string]*waitingEval),
 		initialNackDelay:     initialNackDelay,
 		subsequentNackDelay:  subsequentNackDelay,
 		delayHeap:            delayheap.NewDelayHeap(),
@@ -260,7 +276,7 @@ func (b *EvalBroker) processWaitingEnqueue(eval *structs.Evaluation) {
 	timer := time.AfterFunc(eval.Wait, func() {
 		b.enqueueWaiting(eval)
 	})
-	b.timeWait[eval.ID] = timer
+	b.timeWait[eval.ID] = &waitingEval{eval: eval, timer: timer}
 	b.stats.TotalWaiting += 1
 }
 
@@ -269,6 +285,11 @@ func (b *EvalBroker) enqueueWaiting(eval *structs.Evaluation) {
 	b.l.Lock()
 	defer b.l.Unlock()
 
+	// The evaluation was removed while waiting
+	if _, ok := b.timeWait[eval.ID]; !ok {
+		return
+	}
+
 	delete(b.timeWait, eval.ID)
 	b.stats.TotalWaiting -= 1
 
@@ -287,6 +308,15 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 		ID:        eval.JobID,
 		Namespace: eval.Namespace,
 	}
+
+	// Hold the evaluation if the evaluations of the job are paused.
+	// Evaluations that reached the delivery limit are still failed.
+	if _, ok := b.pausedJobs[namespacedID]; ok && queue != failedQueue {
+		b.paused[namespacedID] = append(b.paused[namespacedID], eval)
+		b.stats.TotalPaused += 1
+		return
+	}
+
 	pendingEval := b.jobEvals[namespacedID]
 	if pendingEval == "" {
 		b.jobEvals[namespacedID] = eval.ID
@@ -327,6 +357,180 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 	}
 }
 
+// SetJobEvalsPaused is used to pause or resume the evaluations of a job. While
+// paused, evaluations of the job are held by the broker instead of being made
+// available to the schedulers. Resuming enqueues any held evaluations.
+func (b *EvalBroker) SetJobEvalsPaused(namespace, jobID string, paused bool) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	namespacedID := structs.NewNamespacedID(jobID, namespace)
+	if paused {
+		b.pausedJobs[namespacedID] = struct{}{}
+		b.holdReadyLocked(namespacedID)
+		return
+	}
+
+	delete(b.pausedJobs, namespacedID)
+	b.releasePausedLocked(namespacedID)
+}
+
+// SetPausedJobs replaces the set of jobs whose evaluations are paused. It is
+// used to restore the set from the state store when leadership is gained.
+func (b *EvalBroker) SetPausedJobs(jobs []structs.NamespacedID) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.pausedJobs = make(map[structs.NamespacedID]struct{}, len(jobs))
+	for _, job := range jobs {
+		b.pausedJobs[job] = struct{}{}
+	}
+
+	for namespacedID := range b.paused {
+		if _, ok := b.pausedJobs[namespacedID]; !ok {
+			b.releasePausedLocked(namespacedID)
+		}
+	}
+}
+
+// holdReadyLocked moves the ready evaluation of the given job, if any, into the
+// held evaluations. It must be called with the lock held.
+func (b *EvalBroker) holdReadyLocked(namespacedID structs.NamespacedID) {
+	evalID := b.jobEvals[namespacedID]
+	if evalID == "" {
+		return
+	}
+
+	for queue, pending := range b.ready {
+		if queue == failedQueue {
+			continue
+		}
+		for i, eval := range pending {
+			if eval.ID != evalID {
+				continue
+			}
+
+			heap.Remove(&pending, i)
+			b.ready[queue] = pending
+			b.stats.TotalReady -= 1
+			b.stats.ByScheduler[queue].Ready -= 1
+
+			b.paused[namespacedID] = append(b.paused[namespacedID], eval)
+			b.stats.TotalPaused += 1
+			return
+		}
+	}
+}
+
+// jobPausedLocked returns whether the evaluations of the evaluation's job are
+// paused. It must be called with the lock held.
+func (b *EvalBroker) jobPausedLocked(eval *structs.Evaluation) bool {
+	_, ok := b.pausedJobs[structs.NewNamespacedID(eval.JobID, eval.Namespace)]
+	return ok
+}
+
+// releasePausedLocked enqueues the evaluations held for the given job. It must
+// be called with the lock held.
+func (b *EvalBroker) releasePausedLocked(namespacedID structs.NamespacedID) {
+	held := b.paused[namespacedID]
+	delete(b.paused, namespacedID)
+	b.stats.TotalPaused -= len(held)
+
+	for _, eval := range held {
+		b.enqueueLocked(eval, eval.Type)
+	}
+}
+
+// RemovePaused removes the given evaluations from the broker if they belong to
+// a job whose evaluations are paused, including evaluations still waiting to
+// be enqueued. It is used when such evaluations are deleted, and ignores any
+// evaluation that is ready or outstanding.
+func (b *EvalBroker) RemovePaused(evalIDs []string) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if len(b.pausedJobs) == 0 {
+		return
+	}
+
+	remove := make(map[string]struct{}, len(evalIDs))
+	for _, id := range evalIDs {
+		remove[id] = struct{}{}
+	}
+
+	// Drop the evaluations that are waiting to be enqueued
+	for id := range remove {
+		if eval, ok := b.stats.DelayedEvals[id]; ok && b.jobPausedLocked(eval) {
+			b.delayHeap.Remove(&evalWrapper{eval})
+			delete(b.stats.DelayedEvals, id)
+			delete(b.evals, id)
+			b.stats.TotalWaiting -= 1
+		}
+		if w, ok := b.timeWait[id]; ok && b.jobPausedLocked(w.eval) {
+			w.timer.Stop()
+			delete(b.timeWait, id)
+			delete(b.evals, id)
+			b.stats.TotalWaiting -= 1
+		}
+	}
+
+	for namespacedID := range b.pausedJobs {
+		// Drop the evaluations queued behind the job's pending evaluation
+		if blocked := b.blocked[namespacedID]; len(blocked) != 0 {
+			var kept PendingEvaluations
+			for _, eval := range blocked {
+				if _, ok := remove[eval.ID]; ok {
+					delete(b.evals, eval.ID)
+					b.stats.TotalBlocked -= 1
+					continue
+				}
+				kept = append(kept, eval)
+			}
+			if len(kept) == 0 {
+				delete(b.blocked, namespacedID)
+			} else {
+				heap.Init(&kept)
+				b.blocked[namespacedID] = kept
+			}
+		}
+
+		// Drop the held evaluations
+		var kept []*structs.Evaluation
+		for _, eval := range b.paused[namespacedID] {
+			if _, ok := remove[eval.ID]; !ok {
+				kept = append(kept, eval)
+				continue
+			}
+
+			delete(b.evals, eval.ID)
+			b.stats.TotalPaused -= 1
+
+			// A held evaluation that was re-enqueued after a Nack still
+			// holds the slot for its job, so hand it to the next evaluation.
+			if b.jobEvals[namespacedID] == eval.ID {
+				delete(b.jobEvals, namespacedID)
+				if blocked := b.blocked[namespacedID]; len(blocked) != 0 {
+					next := heap.Pop(&blocked).(*structs.Evaluation)
+					if len(blocked) > 0 {
+						b.blocked[namespacedID] = blocked
+					} else {
+						delete(b.blocked, namespacedID)
+					}
+					b.stats.TotalBlocked -= 1
+					b.stats.TotalPaused += 1
+					kept = append(kept, next)
+				}
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(b.paused, namespacedID)
+		} else {
+			b.paused[namespacedID] = kept
+		}
+	}
+}
+
 // Dequeue is used to perform a blocking dequeue. The next available evalution
 // is returned as well as a unique token identifier for this dequeue. The token
 // changes on leadership election to ensure a Dequeue prior to a leadership
@@ -709,7 +913,7 @@ func (b *EvalBroker) flush() {
 
 	// Cancel any time wait evals
 	for _, wait := range b.timeWait {
-		wait.Stop()
+		wait.timer.Stop()
 	}
 
 	// Cancel the delayed evaluations goroutine
@@ -725,14 +929,16 @@ func (b *EvalBroker) flush() {
 	b.stats.TotalUnacked = 0
 	b.stats.TotalBlocked = 0
 	b.stats.TotalWaiting = 0
+	b.stats.TotalPaused = 0
 	b.stats.DelayedEvals = make(map[string]*structs.Evaluation)
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.blocked = make(map[structs.NamespacedID]PendingEvaluations)
 	b.ready = make(map[string]PendingEvaluations)
+	b.paused = make(map[structs.NamespacedID][]*structs.Evaluation)
 	b.unack = make(map[string]*unackEval)
-	b.timeWait = make(map[string]*time.Timer)
+	b.timeWait = make(map[string]*waitingEval)
 	b.delayHeap = delayheap.NewDelayHeap()
 }
 
@@ -822,6 +1028,7 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats.TotalUnacked = b.stats.TotalUnacked
 	stats.TotalBlocked = b.stats.TotalBlocked
 	stats.TotalWaiting = b.stats.TotalWaiting
+	stats.TotalPaused = b.stats.TotalPaused
 	for id, eval := range b.stats.DelayedEvals {
 		evalCopy := *eval
 		stats.DelayedEvals[id] = &evalCopy
@@ -848,6 +1055,7 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 			metrics.SetGauge([]string{"nomad", "broker", "total_unacked"}, float32(stats.TotalUnacked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_blocked"}, float32(stats.TotalBlocked))
 			metrics.SetGauge([]string{"nomad", "broker", "total_waiting"}, float32(stats.TotalWaiting))
+			metrics.SetGauge([]string{"nomad", "broker", "total_paused"}, float32(stats.TotalPaused))
 			for _, eval := range stats.DelayedEvals {
 				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "eval_waiting"},
 					float32(time.Until(eval.WaitUntil).Seconds()),
@@ -874,6 +1082,7 @@ type BrokerStats struct {
 	TotalUnacked int
 	TotalBlocked int
 	TotalWaiting int
+	TotalPaused  int
 	DelayedEvals map[string]*structs.Evaluation
 	ByScheduler  map[string]*SchedulerStats
 }
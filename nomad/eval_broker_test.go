@@ -1506,3 +1506,125 @@ func TestEvalBroker_NamespacedJobs(t *testing.T) {
 	require.Equal(1, len(b.blocked))
 
 }
+
+func TestEvalBroker_JobEvalsPaused(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	// Enqueue an eval and pause the job before it is dequeued
+	eval := mock.Eval()
+	b.Enqueue(eval)
+	b.SetJobEvalsPaused(eval.Namespace, eval.JobID, true)
+
+	stats := b.Stats()
+	require.Equal(0, stats.TotalReady)
+	require.Equal(1, stats.TotalPaused)
+	require.Equal(0, stats.ByScheduler[eval.Type].Ready)
+
+	// New evals of the job are held as well
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	b.Enqueue(eval2)
+
+	// Evals of other jobs are unaffected
+	eval3 := mock.Eval()
+	b.Enqueue(eval3)
+
+	stats = b.Stats()
+	require.Equal(1, stats.TotalReady)
+	require.Equal(2, stats.TotalPaused)
+
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval3, out)
+
+	// Resuming enqueues the held evals, serialized per job
+	b.SetJobEvalsPaused(eval.Namespace, eval.JobID, false)
+
+	stats = b.Stats()
+	require.Equal(1, stats.TotalReady)
+	require.Equal(1, stats.TotalBlocked)
+	require.Equal(0, stats.TotalPaused)
+
+	out, _, err = b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval, out)
+}
+
+func TestEvalBroker_SetPausedJobs(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval := mock.Eval()
+	eval2 := mock.Eval()
+	b.SetPausedJobs([]structs.NamespacedID{
+		structs.NewNamespacedID(eval.JobID, eval.Namespace),
+		structs.NewNamespacedID(eval2.JobID, eval2.Namespace),
+	})
+	b.Enqueue(eval)
+	b.Enqueue(eval2)
+
+	stats := b.Stats()
+	require.Equal(0, stats.TotalReady)
+	require.Equal(2, stats.TotalPaused)
+
+	// Dropping a job from the set releases its evals
+	b.SetPausedJobs([]structs.NamespacedID{
+		structs.NewNamespacedID(eval.JobID, eval.Namespace),
+	})
+
+	stats = b.Stats()
+	require.Equal(1, stats.TotalReady)
+	require.Equal(1, stats.TotalPaused)
+
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval2, out)
+}
+
+func TestEvalBroker_RemovePaused(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	// Enqueue a ready eval, an eval blocked behind it and a waiting eval
+	eval := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval.JobID
+	eval3 := mock.Eval()
+	eval3.JobID = eval.JobID
+	eval3.Wait = time.Hour
+	b.Enqueue(eval)
+	b.Enqueue(eval2)
+	b.Enqueue(eval3)
+
+	// Evals of jobs that are not paused are not removed
+	b.RemovePaused([]string{eval.ID, eval2.ID, eval3.ID})
+	stats := b.Stats()
+	require.Equal(1, stats.TotalReady)
+	require.Equal(1, stats.TotalBlocked)
+	require.Equal(1, stats.TotalWaiting)
+
+	b.SetJobEvalsPaused(eval.Namespace, eval.JobID, true)
+	b.RemovePaused([]string{eval.ID, eval3.ID})
+
+	// The blocked eval takes over the held slot of the removed eval
+	stats = b.Stats()
+	require.Equal(0, stats.TotalReady)
+	require.Equal(0, stats.TotalBlocked)
+	require.Equal(0, stats.TotalWaiting)
+	require.Equal(1, stats.TotalPaused)
+
+	b.SetJobEvalsPaused(eval.Namespace, eval.JobID, false)
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(err)
+	require.Equal(eval2, out)
+}
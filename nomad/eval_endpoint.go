@@ -6,6 +6,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-bexpr"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	multierror "github.com/hashicorp/go-multierror"
@@ -399,6 +400,139 @@ func (e *Eval) Reap(args *structs.EvalDeleteRequest,
 	return nil
 }
 
+// Delete is used by operators to delete pending and blocked evaluations, either
+// by ID or by matching a filter expression. Evaluations can only be deleted
+// while the eval broker is paused or the evaluations of their job are paused,
+// which ensures they are not being processed by a scheduler.
+func (e *Eval) Delete(args *structs.EvalBatchDeleteRequest, reply *structs.EvalBatchDeleteResponse) error {
+	if done, err := e.srv.forward("Eval.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "delete"}, time.Now())
+
+	// This action requires a management token
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	switch {
+	case len(args.EvalIDs) == 0 && args.Filter == "":
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			"evaluation IDs or a filter must be specified")
+	case len(args.EvalIDs) > 0 && args.Filter != "":
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			"evaluation IDs and a filter cannot be specified together")
+	}
+
+	snap, err := e.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	_, schedConfig, err := snap.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	brokerPaused := schedConfig != nil && schedConfig.PauseEvalBroker
+
+	var evalIDs []string
+	ws := memdb.NewWatchSet()
+
+	if args.Filter != "" {
+		evaluator, err := bexpr.CreateEvaluator(args.Filter)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest,
+				"failed to read filter expression: %v", err)
+		}
+
+		iter, err := snap.Evals(ws, false)
+		if err != nil {
+			return err
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			eval := raw.(*structs.Evaluation)
+			match, err := evaluator.Evaluate(eval)
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest,
+					"failed to evaluate filter expression: %v", err)
+			}
+
+			// Evaluations that match the filter but cannot be deleted are
+			// skipped rather than failing the request
+			if match && e.evalDeletable(snap, eval, brokerPaused) == nil {
+				evalIDs = append(evalIDs, eval.ID)
+			}
+		}
+	} else {
+		for _, evalID := range args.EvalIDs {
+			eval, err := snap.EvalByID(ws, evalID)
+			if err != nil {
+				return err
+			}
+			if eval == nil {
+				return structs.NewErrRPCCodedf(http.StatusNotFound,
+					"evaluation %q not found", evalID)
+			}
+			if err := e.evalDeletable(snap, eval, brokerPaused); err != nil {
+				return err
+			}
+			evalIDs = append(evalIDs, eval.ID)
+		}
+	}
+
+	if len(evalIDs) == 0 {
+		return nil
+	}
+
+	// Update via Raft
+	req := structs.EvalDeleteRequest{
+		Evals:        evalIDs,
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := e.srv.raftApply(structs.EvalDeleteRequestType, &req)
+	if err != nil {
+		return err
+	}
+
+	reply.Count = len(evalIDs)
+	reply.Index = index
+	return nil
+}
+
+// evalDeletable returns an error if the evaluation cannot be deleted by an
+// operator.
+func (e *Eval) evalDeletable(snap *state.StateSnapshot, eval *structs.Evaluation, brokerPaused bool) error {
+	if eval.Status != structs.EvalStatusPending && eval.Status != structs.EvalStatusBlocked {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"evaluation %q has status %q: only pending and blocked evaluations can be deleted",
+			eval.ID, eval.Status)
+	}
+
+	// The broker is flushed while paused, so no evaluation is being processed
+	if brokerPaused {
+		return nil
+	}
+
+	if _, ok := e.srv.evalBroker.Outstanding(eval.ID); ok {
+		return structs.NewErrRPCCodedf(http.StatusConflict,
+			"evaluation %q is being processed", eval.ID)
+	}
+
+	job, err := snap.JobByID(nil, eval.Namespace, eval.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil || !job.EvalsPaused {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"evaluation %q cannot be deleted unless the eval broker or the evaluations of job %q are paused",
+			eval.ID, eval.JobID)
+	}
+	return nil
+}
+
 // List is used to get a list of the evaluations in the system
 func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListResponse) error {
 	if done, err := e.srv.forward("Eval.List", args, args, reply); done {
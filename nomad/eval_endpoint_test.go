@@ -687,6 +687,193 @@ func TestEvalEndpoint_Reap(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_Delete(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	state := s1.fsm.State()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a job with a pending, a blocked and a complete eval
+	job := mock.Job()
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	eval1 := mock.Eval()
+	eval1.JobID = job.ID
+	eval2 := mock.Eval()
+	eval2.JobID = job.ID
+	eval2.Status = structs.EvalStatusBlocked
+	eval3 := mock.Eval()
+	eval3.JobID = job.ID
+	eval3.Status = structs.EvalStatusComplete
+
+	// Upsert the complete eval first, since it cancels blocked evals
+	require.NoError(state.UpsertEvals(structs.MsgTypeTestSetup, 1001,
+		[]*structs.Evaluation{eval3}))
+	require.NoError(state.UpsertEvals(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Evaluation{eval1, eval2}))
+
+	// Either IDs or a filter must be given
+	req := &structs.EvalBatchDeleteRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.EvalBatchDeleteResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.ErrorContains(err, "must be specified")
+
+	req.EvalIDs = []string{eval1.ID}
+	req.Filter = "Status == \"pending\""
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.ErrorContains(err, "cannot be specified together")
+
+	// Evals cannot be deleted unless they are paused
+	req.Filter = ""
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.ErrorContains(err, "cannot be deleted unless")
+
+	// Pause the evals of the job
+	pauseReq := &structs.JobEvalPauseRequest{
+		JobID:  job.ID,
+		Paused: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var pauseResp structs.JobEvalPauseResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.EvalPause", pauseReq, &pauseResp))
+
+	// Complete evals cannot be deleted
+	req.EvalIDs = []string{eval3.ID}
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.ErrorContains(err, "only pending and blocked evaluations")
+
+	// Unknown evals are reported
+	req.EvalIDs = []string{uuid.Generate()}
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.ErrorContains(err, "not found")
+
+	// Delete the pending eval by ID
+	req.EvalIDs = []string{eval1.ID}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp))
+	require.Equal(1, resp.Count)
+	require.NotZero(resp.Index)
+
+	ws := memdb.NewWatchSet()
+	out, err := state.EvalByID(ws, eval1.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// Delete by filter, which skips the complete eval
+	req.EvalIDs = nil
+	req.Filter = fmt.Sprintf("JobID == %q", job.ID)
+	resp = structs.EvalBatchDeleteResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp))
+	require.Equal(1, resp.Count)
+
+	out, err = state.EvalByID(ws, eval2.ID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = state.EvalByID(ws, eval3.ID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestEvalEndpoint_Delete_BrokerPaused(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	state := s1.fsm.State()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	eval1 := mock.Eval()
+	require.NoError(state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval1}))
+
+	// Pause the eval broker
+	_, config, err := state.SchedulerConfig()
+	require.NoError(err)
+	configReq := &structs.SchedulerSetConfigRequest{
+		Config:       *config,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	configReq.Config.PauseEvalBroker = true
+	var configResp structs.SchedulerSetConfigurationResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", configReq, &configResp))
+	require.False(s1.evalBroker.Enabled())
+	require.False(s1.blockedEvals.Enabled())
+
+	// Any pending eval can be deleted while the broker is paused
+	req := &structs.EvalBatchDeleteRequest{
+		EvalIDs:      []string{eval1.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.EvalBatchDeleteResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp))
+	require.Equal(1, resp.Count)
+
+	// Resume the eval broker and check pending evals are restored
+	eval2 := mock.Eval()
+	require.NoError(state.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval2}))
+
+	configReq.Config.PauseEvalBroker = false
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.SchedulerSetConfiguration", configReq, &configResp))
+	require.True(s1.evalBroker.Enabled())
+	require.True(s1.blockedEvals.Enabled())
+	require.Equal(1, s1.evalBroker.Stats().TotalReady)
+}
+
+func TestEvalEndpoint_Delete_ACL(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	state := s1.fsm.State()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	job.EvalsPaused = true
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+	eval1 := mock.Eval()
+	eval1.JobID = job.ID
+	require.NoError(state.UpsertEvals(structs.MsgTypeTestSetup, 1001, []*structs.Evaluation{eval1}))
+
+	req := &structs.EvalBatchDeleteRequest{
+		EvalIDs:      []string{eval1.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Expect failure for request without a token
+	var resp structs.EvalBatchDeleteResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Expect failure for request with a non-management token
+	token := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+	req.AuthToken = token.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Delete with a management token
+	req.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Delete", req, &resp))
+	require.Equal(1, resp.Count)
+}
+
 func TestEvalEndpoint_List(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2,12 +2,20 @@ package nomad
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-bexpr"
+	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/eventsink"
+	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -41,12 +49,23 @@ func (e *Event) stream(conn io.ReadWriteCloser) {
 		return
 	}
 
+	// Validate the filter expression before subscribing so an invalid
+	// expression is reported as a bad request
+	if args.Filter != "" {
+		if _, err := bexpr.CreateEvaluator(args.Filter); err != nil {
+			handleJsonResultError(fmt.Errorf("failed to read filter expression: %v", err),
+				helper.Int64ToPtr(http.StatusBadRequest), encoder)
+			return
+		}
+	}
+
 	// Generate the subscription request
 	subReq := &stream.SubscribeRequest{
 		Token:     args.AuthToken,
 		Topics:    args.Topics,
 		Index:     uint64(args.Index),
 		Namespace: args.Namespace,
+		Filter:    args.Filter,
 	}
 
 	// Get the servers broker and subscribe
@@ -147,6 +166,158 @@ OUTER:
 
 }
 
+// UpsertSink is used to register or update an event sink. Sinks receive
+// events regardless of ACLs, so managing them requires a management token.
+func (e *Event) UpsertSink(args *structs.EventSinkUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := e.srv.forward("Event.UpsertSink", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event", "upsert_sink"}, time.Now())
+
+	// Check management level permissions
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Sink == nil {
+		return errors.New("missing event sink")
+	}
+
+	args.Sink.Canonicalize()
+	if err := args.Sink.Validate(); err != nil {
+		return fmt.Errorf("invalid event sink: %v", err)
+	}
+	if args.Sink.Type == structs.SinkFile {
+		if err := eventsink.CheckFilePath(e.srv.config.EventSinkFileDir, args.Sink.Path); err != nil {
+			return fmt.Errorf("invalid event sink: %v", err)
+		}
+	}
+
+	// Update via Raft
+	resp, index, err := e.srv.raftApply(structs.EventSinkUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// DeleteSink is used to delete event sinks.
+func (e *Event) DeleteSink(args *structs.EventSinkDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := e.srv.forward("Event.DeleteSink", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event", "delete_sink"}, time.Now())
+
+	// Check management level permissions
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.IDs) == 0 {
+		return errors.New("must specify at least one event sink to delete")
+	}
+
+	// Update via Raft
+	resp, index, err := e.srv.raftApply(structs.EventSinkDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// GetSink is used to query a specific event sink.
+func (e *Event) GetSink(args *structs.EventSinkSpecificRequest, reply *structs.EventSinkResponse) error {
+	if done, err := e.srv.forward("Event.GetSink", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event", "get_sink"}, time.Now())
+
+	// Check management level permissions
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Look for the sink
+			out, err := s.EventSinkByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+
+			// Use the last index that affected the event sinks table, as
+			// storing the progress of a sink doesn't change its modify index
+			index, err := s.Index(state.TableEventSinks)
+			if err != nil {
+				return err
+			}
+			reply.Sink = out
+			reply.Index = helper.Max(1, index)
+			return nil
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
+// ListSinks is used to list the event sinks.
+func (e *Event) ListSinks(args *structs.EventSinkListRequest, reply *structs.EventSinkListResponse) error {
+	if done, err := e.srv.forward("Event.ListSinks", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event", "list_sinks"}, time.Now())
+
+	// Check management level permissions
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Iterate over all the sinks
+			iter, err := s.EventSinks(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.Sinks = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.Sinks = append(reply.Sinks, raw.(*structs.EventSink))
+			}
+
+			// Use the last index that affected the event sinks table
+			index, err := s.Index(state.TableEventSinks)
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Max(1, index)
+			return nil
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
 func (e *Event) forwardStreamingRPC(region string, method string, args interface{}, in io.ReadWriteCloser) error {
 	server, err := e.srv.findRegionServer(region)
 	if err != nil {
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -207,6 +209,92 @@ OUTER:
 	}
 }
 
+// TestEventStream_Filter asserts only the events whose payload match the
+// filter expression of the request are streamed
+func TestEventStream_Filter(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.EnableEventBroker = true
+	})
+	defer cleanupS1()
+
+	handler, err := s1.StreamingRpcHandler("Event.Stream")
+	require.Nil(t, err)
+
+	publisher, err := s1.State().EventBroker()
+	require.NoError(t, err)
+
+	subscribe := func(filter string) (*codec.Decoder, func()) {
+		p1, p2 := net.Pipe()
+		go handler(p2)
+
+		req := structs.EventStreamRequest{
+			Topics: map[structs.Topic][]string{"*": {"*"}},
+			QueryOptions: structs.QueryOptions{
+				Region: s1.Region(),
+				Filter: filter,
+			},
+		}
+		encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+		require.NoError(t, encoder.Encode(req))
+
+		return codec.NewDecoder(p1, structs.MsgpackHandle), func() {
+			p1.Close()
+			p2.Close()
+		}
+	}
+
+	// An invalid filter expression is a bad request
+	decoder, cleanup := subscribe("ID ==")
+	var msg structs.EventStreamWrapper
+	require.NoError(t, decoder.Decode(&msg))
+	require.NotNil(t, msg.Error)
+	require.Contains(t, msg.Error.Error(), "failed to read filter expression")
+	require.Equal(t, int64(400), *msg.Error.Code)
+	cleanup()
+
+	node1, node2 := mock.Node(), mock.Node()
+	decoder, cleanup = subscribe(fmt.Sprintf("ID == %q", node2.ID))
+	defer cleanup()
+
+	msgCh := make(chan *structs.EventStreamWrapper)
+	go func() {
+		for {
+			var msg structs.EventStreamWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				return
+			}
+			msgCh <- &msg
+		}
+	}()
+
+	publisher.Publish(&structs.Events{Index: 1, Events: []structs.Event{{Topic: "test", Key: node1.ID, Payload: node1}}})
+	publisher.Publish(&structs.Events{Index: 2, Events: []structs.Event{{Topic: "test", Key: node2.ID, Payload: node2}}})
+
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			t.Fatal("timeout waiting for event stream")
+		case msg := <-msgCh:
+			require.Nil(t, msg.Error)
+
+			// ignore heartbeat
+			if bytes.Equal(msg.Event.Data, stream.JsonHeartbeat.Data) {
+				continue
+			}
+
+			var events structs.Events
+			require.NoError(t, json.Unmarshal(msg.Event.Data, &events))
+			require.Equal(t, uint64(2), events.Index)
+			require.Len(t, events.Events, 1)
+			require.Equal(t, node2.ID, events.Events[0].Key)
+			return
+		}
+	}
+}
+
 // TestEventStream_RegionForward tests event streaming from one server
 // to another in a different region
 func TestEventStream_RegionForward(t *testing.T) {
@@ -625,3 +713,164 @@ OUTER:
 		}
 	}
 }
+
+func TestEvent_Sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	sinkDir := t.TempDir()
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.EnableEventBroker = true
+		c.EventSinkFileDir = sinkDir
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Invalid sinks are rejected
+	path := filepath.Join(sinkDir, "events.json")
+	req := &structs.EventSinkUpsertRequest{
+		Sink: &structs.EventSink{
+			ID:   "file",
+			Type: structs.SinkFile,
+			Path: "events.json",
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Event.UpsertSink", req, &resp)
+	require.ErrorContains(t, err, "invalid event sink")
+
+	// File sinks must be within the configured directory
+	req.Sink.Path = filepath.Join(t.TempDir(), "events.json")
+	err = msgpackrpc.CallWithCodec(codec, "Event.UpsertSink", req, &resp)
+	require.ErrorContains(t, err, "must be within")
+
+	req.Sink.Path = path
+	req.Sink.Topics = map[structs.Topic][]string{structs.TopicNode: {"*"}}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Event.UpsertSink", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	getReq := &structs.EventSinkSpecificRequest{
+		ID:           "file",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.EventSinkResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Event.GetSink", getReq, &getResp))
+	require.NotNil(t, getResp.Sink)
+	require.Equal(t, path, getResp.Sink.Path)
+	require.Equal(t, structs.DefaultNamespace, getResp.Sink.Namespace)
+
+	// The leader delivers the events of the sink topics to the file
+	node := mock.Node()
+	nodeReg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var nodeResp structs.NodeUpdateResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", nodeReg, &nodeResp))
+
+	testutil.WaitForResult(func() (bool, error) {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		if !strings.Contains(string(raw), node.ID) {
+			return false, fmt.Errorf("node event not delivered")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// The progress of the sink is stored
+	testutil.WaitForResult(func() (bool, error) {
+		sink, err := s1.fsm.State().EventSinkByID(nil, "file")
+		if err != nil {
+			return false, err
+		}
+		if sink.LatestIndex < nodeResp.Index {
+			return false, fmt.Errorf("latest index %d, expected %d", sink.LatestIndex, nodeResp.Index)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	listReq := &structs.EventSinkListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.EventSinkListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Event.ListSinks", listReq, &listResp))
+	require.Len(t, listResp.Sinks, 1)
+
+	// Delete the sink
+	deleteReq := &structs.EventSinkDeleteRequest{
+		IDs:          []string{"file"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var deleteResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Event.DeleteSink", deleteReq, &deleteResp))
+
+	err = msgpackrpc.CallWithCodec(codec, "Event.DeleteSink", deleteReq, &deleteResp)
+	require.EqualError(t, err, `event sink "file" not found`)
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Event.ListSinks", listReq, &listResp))
+	require.Empty(t, listResp.Sinks)
+}
+
+func TestEvent_Sinks_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Event sinks receive all the events, so they require a management token
+	token := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1001, "operator",
+		mock.NodePolicy(acl.PolicyWrite))
+
+	req := &structs.EventSinkUpsertRequest{
+		Sink: mock.EventSink(),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Event.UpsertSink", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Event.UpsertSink", req, &resp))
+
+	listReq := &structs.EventSinkListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var listResp structs.EventSinkListResponse
+	err = msgpackrpc.CallWithCodec(codec, "Event.ListSinks", listReq, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	listReq.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Event.ListSinks", listReq, &listResp))
+	require.Len(t, listResp.Sinks, 1)
+
+	getReq := &structs.EventSinkSpecificRequest{
+		ID:           req.Sink.ID,
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var getResp structs.EventSinkResponse
+	err = msgpackrpc.CallWithCodec(codec, "Event.GetSink", getReq, &getResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	deleteReq := &structs.EventSinkDeleteRequest{
+		IDs: []string{req.Sink.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var deleteResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "Event.DeleteSink", deleteReq, &deleteResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
@@ -0,0 +1,26 @@
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// eventSinkManagerShim implements the eventsink.RaftApplier interface
+// required by the event sink manager.
+type eventSinkManagerShim struct {
+	s *Server
+}
+
+func (e eventSinkManagerShim) UpdateEventSinksProgress(progress map[string]*structs.EventSinkProgress) (uint64, error) {
+	args := &structs.EventSinkProgressRequest{
+		Progress:     progress,
+		WriteRequest: structs.WriteRequest{Region: e.s.config.Region},
+	}
+	resp, index, err := e.s.raftApply(structs.BatchEventSinkUpdateProgressType, args)
+	if err != nil {
+		return index, err
+	}
+	if fsmErr, ok := resp.(error); ok && fsmErr != nil {
+		return index, fsmErr
+	}
+	return index, nil
+}
@@ -0,0 +1,24 @@
+package eventsink
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// RaftApplier contains methods for updating event sinks via Raft.
+type RaftApplier interface {
+	// UpdateEventSinksProgress stores the delivery progress of each sink.
+	UpdateEventSinksProgress(progress map[string]*structs.EventSinkProgress) (uint64, error)
+}
+
+// SinkWriter delivers events to the destination of an event sink.
+type SinkWriter interface {
+	// Send delivers the events to the destination. The events are only
+	// considered delivered once Send returns without error, so it may be
+	// called again with the same events.
+	Send(ctx context.Context, events *structs.Events) error
+
+	// Close releases the resources of the writer.
+	Close() error
+}
@@ -0,0 +1,299 @@
+package eventsink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// LimitStateQueriesPerSecond is the number of state queries allowed per
+	// second. The delivery progress of the sinks is stored frequently, while
+	// their configuration rarely changes.
+	LimitStateQueriesPerSecond = 1.0
+
+	// ProgressUpdateInterval is the interval at which the delivery progress
+	// of the sinks is stored in Raft. Events delivered after the last stored
+	// progress are delivered again after a leader election.
+	ProgressUpdateInterval = 5 * time.Second
+
+	// stateReadErrorDelay is the delay to apply before retrying reading state
+	// when there is an error.
+	stateReadErrorDelay = 1 * time.Second
+)
+
+// Manager delivers events to the registered event sinks. It should only be
+// enabled on the leader, which subscribes to its event broker on behalf of
+// each sink and periodically stores the delivery progress of the sinks.
+type Manager struct {
+	enabled bool
+	logger  log.Logger
+
+	// raft is used to store the delivery progress of the sinks.
+	raft RaftApplier
+
+	// newWriter returns the writer of a sink destination.
+	newWriter func(*structs.EventSink) (SinkWriter, error)
+
+	// progressInterval is the interval at which the progress is stored.
+	progressInterval time.Duration
+
+	// state is the state that is watched for state changes.
+	state *state.StateStore
+
+	// queryLimiter is used to limit the rate of blocking queries
+	queryLimiter *rate.Limiter
+
+	// ctx and exitFn are used to cancel the manager
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.Mutex
+}
+
+// NewManager returns an event sink manager that is used to deliver events to
+// the event sinks. File sinks are confined to fileDir.
+func NewManager(logger log.Logger, raft RaftApplier, stateQueriesPerSecond float64,
+	progressInterval time.Duration, fileDir string) *Manager {
+
+	return &Manager{
+		logger: logger.Named("event_sink_manager"),
+		raft:   raft,
+		newWriter: func(sink *structs.EventSink) (SinkWriter, error) {
+			return NewSinkWriter(sink, fileDir)
+		},
+		progressInterval: progressInterval,
+		queryLimiter:     rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 1),
+	}
+}
+
+// SetEnabled is used to control if the manager is enabled. The manager
+// should only be enabled on the active leader. When being enabled the state
+// is passed in as it is no longer valid once a leader election has taken
+// place.
+func (m *Manager) SetEnabled(enabled bool, state *state.StateStore) {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	wasEnabled := m.enabled
+	m.enabled = enabled
+
+	if state != nil {
+		m.state = state
+	}
+
+	// Stop the current delivery
+	if m.exitFn != nil {
+		m.exitFn()
+		m.exitFn = nil
+	}
+
+	// If we are enabled, launch the watch loop
+	if enabled {
+		m.ctx, m.exitFn = context.WithCancel(context.Background())
+		sinks := &sinkSet{sinks: make(map[string]*managedSink)}
+		go m.watch(m.ctx, m.state, sinks)
+		go m.trackProgress(m.ctx, sinks)
+	} else if wasEnabled {
+		m.logger.Trace("event sink manager disabled")
+	}
+}
+
+// sinkSet is the set of sinks delivered to while the manager is enabled.
+type sinkSet struct {
+	sinks map[string]*managedSink
+	l     sync.Mutex
+}
+
+// watch is the long lived go-routine that starts, restarts and stops the
+// delivery to the sinks as they are registered, updated and deleted.
+func (m *Manager) watch(ctx context.Context, store *state.StateStore, sinks *sinkSet) {
+	broker, err := store.EventBroker()
+	if err != nil {
+		m.logger.Warn("event broker is disabled, events will not be delivered to event sinks")
+		return
+	}
+
+	// Stop all the sinks once the manager is disabled
+	defer func() {
+		sinks.l.Lock()
+		defer sinks.l.Unlock()
+		for id, sink := range sinks.sinks {
+			sink.stop()
+			delete(sinks.sinks, id)
+		}
+	}()
+
+	timer, stop := helper.NewSafeTimer(stateReadErrorDelay)
+	defer stop()
+
+	index := uint64(1)
+	for {
+		resp, nextIndex, err := m.getSinks(ctx, store, index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			m.logger.Error("error watching event sinks", "index", index, "error", err)
+			timer.Reset(stateReadErrorDelay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				continue
+			}
+		}
+
+		index = nextIndex
+		m.reconcile(ctx, broker, sinks, resp)
+	}
+}
+
+// getSinks blocks until the event sinks change after the given index.
+func (m *Manager) getSinks(ctx context.Context, store *state.StateStore, minIndex uint64) ([]*structs.EventSink, uint64, error) {
+	if err := m.queryLimiter.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	resp, index, err := store.BlockingQuery(getSinksImpl, minIndex, ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return resp.([]*structs.EventSink), index, nil
+}
+
+// getSinksImpl returns all the event sinks along with the index of the
+// event sinks table.
+func getSinksImpl(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	iter, err := store.EventSinks(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sinks []*structs.EventSink
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sinks = append(sinks, raw.(*structs.EventSink))
+	}
+
+	index, err := store.Index(state.TableEventSinks)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sinks, helper.Max(1, index), nil
+}
+
+// reconcile starts the delivery to the new sinks, restarts it for the
+// updated sinks and stops it for the deleted sinks.
+func (m *Manager) reconcile(ctx context.Context, broker *stream.EventBroker, sinks *sinkSet, desired []*structs.EventSink) {
+	sinks.l.Lock()
+	defer sinks.l.Unlock()
+
+	seen := make(map[string]struct{}, len(desired))
+	for _, sink := range desired {
+		seen[sink.ID] = struct{}{}
+
+		// Storing the progress doesn't modify the sink
+		existing, ok := sinks.sinks[sink.ID]
+		if ok && existing.sink.ModifyIndex == sink.ModifyIndex {
+			continue
+		}
+
+		// Resume the delivery of updated sinks where it stopped
+		start := structs.EventSinkProgress{
+			LatestIndex:        sink.LatestIndex,
+			LatestIndexBatches: sink.LatestIndexBatches,
+			LostEventsIndex:    sink.LostEventsIndex,
+		}
+		if ok {
+			existing.stop()
+			start = latestProgress(start, existing.Progress())
+			m.logger.Debug("restarting event sink", "sink_id", sink.ID)
+		} else {
+			m.logger.Debug("starting event sink", "sink_id", sink.ID)
+		}
+
+		managed := newManagedSink(sink.Copy(), broker, m.logger, m.newWriter, start)
+		managed.start(ctx)
+		sinks.sinks[sink.ID] = managed
+	}
+
+	for id, sink := range sinks.sinks {
+		if _, ok := seen[id]; !ok {
+			m.logger.Debug("stopping event sink", "sink_id", id)
+			sink.stop()
+			delete(sinks.sinks, id)
+		}
+	}
+}
+
+// latestProgress returns the furthest of the two delivery progresses of a
+// sink.
+func latestProgress(a, b structs.EventSinkProgress) structs.EventSinkProgress {
+	latest := a
+	if b.LatestIndex > a.LatestIndex ||
+		b.LatestIndex == a.LatestIndex && b.LatestIndexBatches > a.LatestIndexBatches {
+		latest = b
+	}
+	latest.LostEventsIndex = helper.Max(a.LostEventsIndex, b.LostEventsIndex)
+	return latest
+}
+
+// trackProgress is the long lived go-routine that periodically stores the
+// delivery progress of the sinks.
+func (m *Manager) trackProgress(ctx context.Context, sinks *sinkSet) {
+	ticker := time.NewTicker(m.progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.updateProgress(sinks); err != nil && ctx.Err() == nil {
+			m.logger.Error("failed to store event sink progress", "error", err)
+		}
+	}
+}
+
+// updateProgress stores the progress of the sinks that delivered events
+// since their progress was last stored.
+func (m *Manager) updateProgress(sinks *sinkSet) error {
+	sinks.l.Lock()
+	progress := make(map[string]*structs.EventSinkProgress)
+	updated := make(map[*managedSink]structs.EventSinkProgress)
+	for id, sink := range sinks.sinks {
+		if delivered := sink.Progress(); delivered != sink.committed {
+			progress[id] = &delivered
+			updated[sink] = delivered
+		}
+	}
+	sinks.l.Unlock()
+
+	if len(progress) == 0 {
+		return nil
+	}
+
+	if _, err := m.raft.UpdateEventSinksProgress(progress); err != nil {
+		return err
+	}
+
+	sinks.l.Lock()
+	defer sinks.l.Unlock()
+	for sink, delivered := range updated {
+		sink.committed = delivered
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+package eventsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRaft applies the progress updates of the manager directly to the
+// state store, at the index following the latest one.
+type mockRaft struct {
+	state *state.StateStore
+}
+
+func (r *mockRaft) UpdateEventSinksProgress(progress map[string]*structs.EventSinkProgress) (uint64, error) {
+	index, err := r.state.LatestIndex()
+	if err != nil {
+		return 0, err
+	}
+	index++
+	return index, r.state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, index, progress)
+}
+
+// mockWriter records the events it receives. It fails to deliver events
+// while failures is positive.
+type mockWriter struct {
+	events   []uint64
+	failures int
+	closed   bool
+	l        sync.Mutex
+}
+
+func (w *mockWriter) Send(_ context.Context, events *structs.Events) error {
+	w.l.Lock()
+	defer w.l.Unlock()
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("destination unavailable")
+	}
+	w.events = append(w.events, events.Index)
+	return nil
+}
+
+func (w *mockWriter) Close() error {
+	w.l.Lock()
+	defer w.l.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *mockWriter) delivered() []uint64 {
+	w.l.Lock()
+	defer w.l.Unlock()
+	return append([]uint64(nil), w.events...)
+}
+
+func testManager(t *testing.T) (*Manager, *mockRaft, map[string]*mockWriter, *sync.Mutex) {
+	store := state.TestStateStoreCfg(t, state.TestStateStorePublisher(t))
+	raft := &mockRaft{state: store}
+
+	var l sync.Mutex
+	writers := make(map[string]*mockWriter)
+
+	m := NewManager(testlog.HCLogger(t), raft, 100, 10*time.Millisecond, t.TempDir())
+	m.newWriter = func(sink *structs.EventSink) (SinkWriter, error) {
+		l.Lock()
+		defer l.Unlock()
+		w := &mockWriter{}
+		writers[fmt.Sprintf("%s@%d", sink.ID, sink.ModifyIndex)] = w
+		return w, nil
+	}
+	m.SetEnabled(true, store)
+	t.Cleanup(func() { m.SetEnabled(false, nil) })
+	return m, raft, writers, &l
+}
+
+func waitForWriter(t *testing.T, writers map[string]*mockWriter, l *sync.Mutex, key string) *mockWriter {
+	var w *mockWriter
+	testutil.WaitForResult(func() (bool, error) {
+		l.Lock()
+		defer l.Unlock()
+		w = writers[key]
+		return w != nil, fmt.Errorf("writer %s not created", key)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	return w
+}
+
+func waitForProgress(t *testing.T, store *state.StateStore, id string, index uint64) {
+	testutil.WaitForResult(func() (bool, error) {
+		sink, err := store.EventSinkByID(nil, id)
+		if err != nil {
+			return false, err
+		}
+		if sink.LatestIndex != index {
+			return false, fmt.Errorf("latest index %d, expected %d", sink.LatestIndex, index)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestManager_DeliverEvents(t *testing.T) {
+	ci.Parallel(t)
+	m, raft, writers, l := testManager(t)
+	store := raft.state
+
+	// Events published before the sink is registered are not delivered
+	require.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 10, mock.Node()))
+
+	sink := mock.EventSink()
+	sink.Topics = map[structs.Topic][]string{structs.TopicNode: {"*"}}
+	require.NoError(t, store.UpsertEventSink(structs.MsgTypeTestSetup, 20, sink))
+	w := waitForWriter(t, writers, l, sink.ID+"@20")
+
+	// Only the events of the sink topics are delivered
+	require.NoError(t, store.UpsertJob(structs.JobRegisterRequestType, 30, mock.Job()))
+	require.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 40, mock.Node()))
+
+	testutil.WaitForResult(func() (bool, error) {
+		delivered := w.delivered()
+		return len(delivered) == 1, fmt.Errorf("delivered %v", delivered)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, []uint64{40}, w.delivered())
+	waitForProgress(t, store, sink.ID, 40)
+
+	// Updating the sink resumes the delivery where it stopped
+	require.NoError(t, store.UpsertEventSink(structs.MsgTypeTestSetup, 50, sink.Copy()))
+	w2 := waitForWriter(t, writers, l, sink.ID+"@50")
+	require.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 60, mock.Node()))
+
+	testutil.WaitForResult(func() (bool, error) {
+		delivered := w2.delivered()
+		return len(delivered) == 1, fmt.Errorf("delivered %v", delivered)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, []uint64{60}, w2.delivered())
+	waitForProgress(t, store, sink.ID, 60)
+
+	w.l.Lock()
+	require.True(t, w.closed)
+	w.l.Unlock()
+
+	// Deleting the sink stops the delivery
+	require.NoError(t, store.DeleteEventSinks(structs.MsgTypeTestSetup, 70, []string{sink.ID}))
+	testutil.WaitForResult(func() (bool, error) {
+		w2.l.Lock()
+		defer w2.l.Unlock()
+		return w2.closed, errors.New("writer not closed")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Disabling the manager doesn't fail without sinks
+	m.SetEnabled(false, nil)
+}
+
+func TestManager_RetryDelivery(t *testing.T) {
+	ci.Parallel(t)
+	m, raft, writers, l := testManager(t)
+	store := raft.state
+
+	sink := mock.EventSink()
+	require.NoError(t, store.UpsertEventSink(structs.MsgTypeTestSetup, 20, sink))
+	w := waitForWriter(t, writers, l, sink.ID+"@20")
+
+	w.l.Lock()
+	w.failures = 1
+	w.l.Unlock()
+
+	require.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 30, mock.Node()))
+	require.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 40, mock.Node()))
+
+	// The events are delivered in order once the destination recovers
+	testutil.WaitForResult(func() (bool, error) {
+		delivered := w.delivered()
+		return len(delivered) == 2, fmt.Errorf("delivered %v", delivered)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, []uint64{30, 40}, w.delivered())
+	waitForProgress(t, store, sink.ID, 40)
+
+	// After a leader election, the delivery resumes from the stored progress
+	m.SetEnabled(false, nil)
+	require.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 50, mock.Node()))
+
+	l.Lock()
+	delete(writers, sink.ID+"@20")
+	l.Unlock()
+	m.SetEnabled(true, store)
+
+	w2 := waitForWriter(t, writers, l, sink.ID+"@20")
+	testutil.WaitForResult(func() (bool, error) {
+		delivered := w2.delivered()
+		return len(delivered) == 1, fmt.Errorf("delivered %v", delivered)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, []uint64{50}, w2.delivered())
+}
+
+func TestManager_DeliverEvents_SameIndex(t *testing.T) {
+	ci.Parallel(t)
+	_, raft, writers, l := testManager(t)
+	store := raft.state
+
+	sink := mock.EventSink()
+	require.NoError(t, store.UpsertEventSink(structs.MsgTypeTestSetup, 20, sink))
+	w := waitForWriter(t, writers, l, sink.ID+"@20")
+
+	// A single Raft operation may publish several sets of events, such as a
+	// job and its evaluation, which are all delivered
+	broker, err := store.EventBroker()
+	require.NoError(t, err)
+	broker.Publish(&structs.Events{Index: 30, Events: []structs.Event{{Topic: structs.TopicJob, Index: 30}}})
+	broker.Publish(&structs.Events{Index: 30, Events: []structs.Event{{Topic: structs.TopicEvaluation, Index: 30}}})
+
+	testutil.WaitForResult(func() (bool, error) {
+		delivered := w.delivered()
+		return len(delivered) == 2, fmt.Errorf("delivered %v", delivered)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, []uint64{30, 30}, w.delivered())
+}
+
+// testBroker returns an event broker holding the published events, once
+// they have all been appended to its buffer.
+func testBroker(t *testing.T, bufferSize int64, published ...*structs.Events) *stream.EventBroker {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	broker, err := stream.NewEventBroker(ctx, nil, stream.EventBrokerCfg{EventBufferSize: bufferSize})
+	require.NoError(t, err)
+
+	for _, events := range published {
+		broker.Publish(events)
+	}
+
+	last := published[len(published)-1].Index
+	testutil.WaitForResult(func() (bool, error) {
+		sub, err := broker.Subscribe(&stream.SubscribeRequest{Index: last, StartExactlyAtIndex: true})
+		if err != nil {
+			return false, err
+		}
+		sub.Unsubscribe()
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	return broker
+}
+
+func TestManagedSink_ResumeSameIndex(t *testing.T) {
+	ci.Parallel(t)
+
+	// One of the sets of events with index 30 was delivered before the sink
+	// resubscribes, so only the other one is delivered again.
+	broker := testBroker(t, 100,
+		&structs.Events{Index: 30, Events: []structs.Event{{Topic: structs.TopicJob, Index: 30}}},
+		&structs.Events{Index: 30, Events: []structs.Event{{Topic: structs.TopicEvaluation, Index: 30}}},
+		&structs.Events{Index: 40, Events: []structs.Event{{Topic: structs.TopicNode, Index: 40}}},
+	)
+
+	w := &mockWriter{}
+	sink := mock.EventSink()
+	managed := newManagedSink(sink, broker, testlog.HCLogger(t),
+		func(*structs.EventSink) (SinkWriter, error) { return w, nil },
+		structs.EventSinkProgress{LatestIndex: 30, LatestIndexBatches: 1})
+	managed.start(context.Background())
+	defer managed.stop()
+
+	testutil.WaitForResult(func() (bool, error) {
+		delivered := w.delivered()
+		return len(delivered) == 2, fmt.Errorf("delivered %v", delivered)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Equal(t, []uint64{30, 40}, w.delivered())
+	require.Equal(t, structs.EventSinkProgress{LatestIndex: 40, LatestIndexBatches: 1}, managed.Progress())
+}
+
+func TestManagedSink_LostEvents(t *testing.T) {
+	ci.Parallel(t)
+
+	// The events following index 30, which were delivered, have left the
+	// buffer of the broker.
+	broker := testBroker(t, 2,
+		&structs.Events{Index: 30, Events: []structs.Event{{Topic: structs.TopicNode, Index: 30}}},
+		&structs.Events{Index: 40, Events: []structs.Event{{Topic: structs.TopicNode, Index: 40}}},
+		&structs.Events{Index: 50, Events: []structs.Event{{Topic: structs.TopicNode, Index: 50}}},
+		&structs.Events{Index: 60, Events: []structs.Event{{Topic: structs.TopicNode, Index: 60}}},
+	)
+
+	w := &mockWriter{}
+	sink := mock.EventSink()
+	managed := newManagedSink(sink, broker, testlog.HCLogger(t),
+		func(*structs.EventSink) (SinkWriter, error) { return w, nil },
+		structs.EventSinkProgress{LatestIndex: 30, LatestIndexBatches: 1})
+	managed.start(context.Background())
+	defer managed.stop()
+
+	// The loss is recorded and the delivery resumes from the oldest events
+	// of the buffer.
+	testutil.WaitForResult(func() (bool, error) {
+		delivered := w.delivered()
+		return len(delivered) > 0 && delivered[len(delivered)-1] == 60, fmt.Errorf("delivered %v", delivered)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.NotContains(t, w.delivered(), uint64(30))
+	require.Equal(t, uint64(30), managed.Progress().LostEventsIndex)
+}
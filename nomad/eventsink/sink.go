@@ -0,0 +1,252 @@
+package eventsink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// deliveryBaseBackoff and deliveryMaxBackoff bound the exponential back
+	// off applied when events fail to be delivered to a sink.
+	deliveryBaseBackoff = 1 * time.Second
+	deliveryMaxBackoff  = 1 * time.Minute
+)
+
+// managedSink delivers the events of the broker to a single event sink.
+type managedSink struct {
+	sink   *structs.EventSink
+	broker *stream.EventBroker
+	logger log.Logger
+
+	// newWriter returns the writer of the sink destination.
+	newWriter func(*structs.EventSink) (SinkWriter, error)
+
+	// progress is the delivery progress of the sink.
+	progress     structs.EventSinkProgress
+	progressLock sync.Mutex
+
+	// committed is the delivery progress of the sink that is stored in
+	// Raft. It is only accessed by the progress tracker.
+	committed structs.EventSinkProgress
+
+	// failures is the number of consecutive failures used to back off.
+	failures int
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+func newManagedSink(sink *structs.EventSink, broker *stream.EventBroker, logger log.Logger,
+	newWriter func(*structs.EventSink) (SinkWriter, error), start structs.EventSinkProgress) *managedSink {
+
+	return &managedSink{
+		sink:      sink,
+		broker:    broker,
+		logger:    logger.With("sink_id", sink.ID, "sink_type", sink.Type),
+		newWriter: newWriter,
+		progress:  start,
+		committed: structs.EventSinkProgress{
+			LatestIndex:        sink.LatestIndex,
+			LatestIndexBatches: sink.LatestIndexBatches,
+			LostEventsIndex:    sink.LostEventsIndex,
+		},
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Progress returns the delivery progress of the sink.
+func (m *managedSink) Progress() structs.EventSinkProgress {
+	m.progressLock.Lock()
+	defer m.progressLock.Unlock()
+	return m.progress
+}
+
+// delivered records the delivery of a set of events.
+func (m *managedSink) delivered(index uint64) {
+	m.progressLock.Lock()
+	defer m.progressLock.Unlock()
+
+	if index == m.progress.LatestIndex {
+		m.progress.LatestIndexBatches++
+	} else {
+		m.progress.LatestIndex = index
+		m.progress.LatestIndexBatches = 1
+	}
+}
+
+// lost records that the events following the ones last delivered were
+// lost. The events with the latest index are no longer tracked, as the ones
+// that were not delivered are among the lost events.
+func (m *managedSink) lost() {
+	m.progressLock.Lock()
+	defer m.progressLock.Unlock()
+	m.progress.LostEventsIndex = m.progress.LatestIndex
+	m.progress.LatestIndexBatches = 0
+}
+
+// start launches the delivery of the events in the background.
+func (m *managedSink) start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	go m.run(ctx)
+}
+
+// stop stops the delivery of the events and waits for it to exit.
+func (m *managedSink) stop() {
+	m.cancel()
+	<-m.doneCh
+}
+
+func (m *managedSink) run(ctx context.Context) {
+	defer close(m.doneCh)
+
+	var writer SinkWriter
+	for {
+		var err error
+		writer, err = m.newWriter(m.sink)
+		if err == nil {
+			break
+		}
+
+		m.logger.Error("failed to setup event sink", "error", err)
+		if m.backoff(ctx) {
+			return
+		}
+	}
+	defer writer.Close()
+	m.failures = 0
+
+	for {
+		sub, resume, err := m.subscribe()
+		if err == nil {
+			err = m.deliver(ctx, sub, writer, resume)
+			sub.Unsubscribe()
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, stream.ErrSubscriptionClosed):
+			continue
+		}
+
+		m.logger.Error("failed to subscribe to events", "error", err)
+		if m.backoff(ctx) {
+			return
+		}
+	}
+}
+
+// subscribe subscribes to the events following the ones last delivered and
+// returns the progress to resume from. A single Raft operation may publish
+// several sets of events with the same index, so when some of them have been
+// delivered the subscription starts at their index, and the sets already
+// delivered are skipped. If the events to resume from are no longer in the
+// buffer of the broker, the events that were not delivered are lost, which
+// is recorded in the progress of the sink, and the subscription starts at the
+// oldest events in the buffer.
+func (m *managedSink) subscribe() (*stream.Subscription, structs.EventSinkProgress, error) {
+	resume := m.Progress()
+
+	req := &stream.SubscribeRequest{
+		Index:     resume.LatestIndex + 1,
+		Namespace: m.sink.Namespace,
+		Topics:    m.sink.Topics,
+	}
+	if resume.LatestIndexBatches == 0 {
+		// Nothing has been delivered at the index, so the events following
+		// it can't be told apart from events that have left the buffer.
+		sub, err := m.broker.Subscribe(req)
+		return sub, resume, err
+	}
+
+	req.Index = resume.LatestIndex
+	req.StartExactlyAtIndex = true
+	sub, err := m.broker.Subscribe(req)
+	if !errors.Is(err, stream.ErrIndexNotInBuffer) {
+		return sub, resume, err
+	}
+
+	m.logger.Warn("events were lost as they left the event buffer before being delivered",
+		"delivered_index", resume.LatestIndex)
+	metrics.IncrCounterWithLabels([]string{"nomad", "event_sink", "lost_events"}, 1,
+		[]metrics.Label{{Name: "sink_id", Value: m.sink.ID}})
+	m.lost()
+
+	req.StartExactlyAtIndex = false
+	sub, err = m.broker.Subscribe(req)
+	return sub, m.Progress(), err
+}
+
+// deliver sends the events of the subscription to the writer until an
+// error occurs. Events that fail to be delivered are retried until they are
+// delivered or the context is cancelled.
+func (m *managedSink) deliver(ctx context.Context, sub *stream.Subscription, writer SinkWriter, resume structs.EventSinkProgress) error {
+	skip := resume.LatestIndexBatches
+	for {
+		events, err := sub.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Skip the events that were already delivered, along with the events
+		// preceding the resume index which the broker returns when the index
+		// is past its latest events. When no set of events with the resume
+		// index is tracked, all of them are skipped.
+		if events.Index < resume.LatestIndex {
+			continue
+		}
+		if events.Index == resume.LatestIndex {
+			if resume.LatestIndexBatches == 0 {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+		}
+
+		for {
+			err := writer.Send(ctx, &events)
+			if err == nil {
+				break
+			}
+
+			m.logger.Warn("failed to deliver events", "index", events.Index, "error", err)
+			if m.backoff(ctx) {
+				return ctx.Err()
+			}
+		}
+
+		m.failures = 0
+		m.delivered(events.Index)
+	}
+}
+
+// backoff waits with an exponential back off after a failure. It returns
+// true if the context was cancelled while waiting.
+func (m *managedSink) backoff(ctx context.Context) bool {
+	backoff := (1 << m.failures) * deliveryBaseBackoff
+	if backoff > deliveryMaxBackoff {
+		backoff = deliveryMaxBackoff
+	} else {
+		m.failures++
+	}
+
+	timer, stop := helper.NewSafeTimer(backoff)
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+		return true
+	case <-timer.C:
+		return false
+	}
+}
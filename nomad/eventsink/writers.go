@@ -0,0 +1,197 @@
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// webhookTimeout is the timeout of the requests sent to webhook sinks.
+	webhookTimeout = 30 * time.Second
+)
+
+// NewSinkWriter returns the writer delivering events to the destination of
+// the given sink. File sinks must write under fileDir.
+func NewSinkWriter(sink *structs.EventSink, fileDir string) (SinkWriter, error) {
+	switch sink.Type {
+	case structs.SinkWebhook:
+		return newWebhookWriter(sink), nil
+	case structs.SinkFile:
+		return newFileWriter(sink, fileDir)
+	default:
+		return nil, fmt.Errorf("unsupported event sink type %q", sink.Type)
+	}
+}
+
+// encodeEvents returns the JSON encoding of the events, as they are written
+// to the event stream.
+func encodeEvents(events *structs.Events) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf, structs.JsonHandleWithExtensions)
+	if err := enc.Encode(events); err != nil {
+		return nil, fmt.Errorf("failed to encode events: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// webhookWriter POSTs each set of events as a JSON object to the address of
+// a webhook sink.
+type webhookWriter struct {
+	address string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookWriter(sink *structs.EventSink) *webhookWriter {
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = webhookTimeout
+
+	return &webhookWriter{
+		address: sink.Address,
+		headers: sink.Headers,
+		client:  client,
+	}
+}
+
+func (w *webhookWriter) Send(ctx context.Context, events *structs.Events) error {
+	body, err := encodeEvents(events)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response code from webhook: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *webhookWriter) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
+
+// fileWriter appends each set of events as a line of JSON to the file of a
+// file sink.
+type fileWriter struct {
+	f *os.File
+}
+
+func newFileWriter(sink *structs.EventSink, fileDir string) (*fileWriter, error) {
+	// Check the path again as the sink may have been registered by a server
+	// with a different configuration, or a symlink may have been added since.
+	if err := CheckFilePath(fileDir, sink.Path); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sink.Path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create event sink directory: %v", err)
+	}
+
+	f, err := os.OpenFile(sink.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event sink file: %v", err)
+	}
+	return &fileWriter{f: f}, nil
+}
+
+func (w *fileWriter) Send(_ context.Context, events *structs.Events) error {
+	line, err := encodeEvents(events)
+	if err != nil {
+		return err
+	}
+
+	line = append(line, '\n')
+	if _, err := w.f.Write(line); err != nil {
+		return err
+	}
+
+	// Flush the events to disk before they are considered delivered
+	return w.f.Sync()
+}
+
+func (w *fileWriter) Close() error {
+	return w.f.Close()
+}
+
+// CheckFilePath returns an error if the path of a file sink does not resolve
+// to a file under dir. Symlinks in the existing part of either path are
+// resolved, so they cannot be used to escape dir.
+func CheckFilePath(dir, path string) error {
+	if dir == "" {
+		return fmt.Errorf("file event sinks are disabled as no event sink file directory is configured")
+	}
+
+	root, err := resolvePath(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve event sink file directory: %v", err)
+	}
+	target, err := resolvePath(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve event sink path: %v", err)
+	}
+
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("event sink path %q must be within %q", path, dir)
+	}
+	return nil
+}
+
+// resolvePath returns the absolute path with the symlinks of its longest
+// existing prefix resolved.
+func resolvePath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	var rest []string
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(append([]string{path}, rest...)...), nil
+		}
+		rest = append([]string{filepath.Base(path)}, rest...)
+		path = parent
+	}
+}
@@ -0,0 +1,137 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookWriter(t *testing.T) {
+	ci.Parallel(t)
+
+	var status int
+	var received []uint64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		var events struct {
+			Index  uint64
+			Events []map[string]interface{}
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&events))
+		require.Len(t, events.Events, 2)
+		received = append(received, events.Index)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	sink := mock.EventSink()
+	sink.Address = ts.URL
+	sink.Headers = map[string]string{"X-Token": "secret"}
+	w, err := NewSinkWriter(sink, "")
+	require.NoError(t, err)
+	defer w.Close()
+
+	status = http.StatusOK
+	require.NoError(t, w.Send(context.Background(), mock.Events(10)))
+
+	// Unsuccessful responses fail the delivery
+	status = http.StatusServiceUnavailable
+	err = w.Send(context.Background(), mock.Events(20))
+	require.EqualError(t, err, "unexpected response code from webhook: 503")
+	require.Equal(t, []uint64{10, 20}, received)
+}
+
+func TestFileWriter(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events", "events.json")
+	sink := &structs.EventSink{
+		ID:   "file",
+		Type: structs.SinkFile,
+		Path: path,
+	}
+
+	w, err := NewSinkWriter(sink, dir)
+	require.NoError(t, err)
+	require.NoError(t, w.Send(context.Background(), mock.Events(10)))
+	require.NoError(t, w.Close())
+
+	// Events are appended to the existing file
+	w, err = NewSinkWriter(sink, dir)
+	require.NoError(t, err)
+	require.NoError(t, w.Send(context.Background(), mock.Events(20)))
+	require.NoError(t, w.Close())
+
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	require.Len(t, lines, 2)
+
+	for i, line := range lines {
+		var events structs.Events
+		require.NoError(t, json.Unmarshal([]byte(line), &events))
+		require.Equal(t, uint64(10*(i+1)), events.Index)
+		require.Len(t, events.Events, 2)
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
+
+func TestCheckFilePath(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(dir, "link")))
+
+	cases := []struct {
+		name   string
+		dir    string
+		path   string
+		expErr string
+	}{
+		{name: "within", dir: dir, path: filepath.Join(dir, "events", "events.json")},
+		{name: "disabled", dir: "", path: filepath.Join(dir, "events.json"), expErr: "file event sinks are disabled"},
+		{name: "outside", dir: dir, path: filepath.Join(outside, "events.json"), expErr: "must be within"},
+		{name: "directory", dir: dir, path: dir, expErr: "must be within"},
+		{name: "dot dot", dir: dir, path: filepath.Join(dir, "..", "events.json"), expErr: "must be within"},
+		{name: "symlink", dir: dir, path: filepath.Join(dir, "link", "events.json"), expErr: "must be within"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckFilePath(tc.dir, tc.path)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+
+	// The writer refuses to open files outside the directory
+	_, err := NewSinkWriter(&structs.EventSink{
+		ID:   "file",
+		Type: structs.SinkFile,
+		Path: filepath.Join(outside, "events.json"),
+	}, dir)
+	require.ErrorContains(t, err, "must be within")
+	require.NoFileExists(t, filepath.Join(outside, "events.json"))
+}
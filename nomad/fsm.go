@@ -54,6 +54,15 @@ const (
 	ScalingEventsSnapshot                SnapshotType = 19
 	EventSinkSnapshot                    SnapshotType = 20
 	ServiceRegistrationSnapshot          SnapshotType = 21
+	VariablesSnapshot                    SnapshotType = 22
+	RootKeyMetaSnapshot                  SnapshotType = 23
+	ACLRoleSnapshot                      SnapshotType = 24
+	ACLAuthMethodSnapshot                SnapshotType = 25
+	ACLBindingRuleSnapshot               SnapshotType = 26
+	NodePoolSnapshot                     SnapshotType = 27
+	JobSubmissionSnapshot                SnapshotType = 28
+	MaintenancePlanSnapshot              SnapshotType = 29
+	HostVolumeSnapshot                   SnapshotType = 30
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
 )
@@ -257,7 +266,7 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	case structs.ACLTokenBootstrapRequestType:
 		return n.applyACLTokenBootstrap(msgType, buf[1:], log.Index)
 	case structs.AutopilotRequestType:
-		return n.applyAutopilotUpdate(buf[1:], log.Index)
+		return n.applyAutopilotUpdate(msgType, buf[1:], log.Index)
 	case structs.UpsertNodeEventsType:
 		return n.applyUpsertNodeEvent(msgType, buf[1:], log.Index)
 	case structs.JobBatchDeregisterRequestType:
@@ -269,7 +278,7 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	case structs.BatchNodeUpdateDrainRequestType:
 		return n.applyBatchDrainUpdate(msgType, buf[1:], log.Index)
 	case structs.SchedulerConfigRequestType:
-		return n.applySchedulerConfigUpdate(buf[1:], log.Index)
+		return n.applySchedulerConfigUpdate(msgType, buf[1:], log.Index)
 	case structs.NodeBatchDeregisterRequestType:
 		return n.applyDeregisterNodeBatch(msgType, buf[1:], log.Index)
 	case structs.ClusterMetadataRequestType:
@@ -279,27 +288,27 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	case structs.ServiceIdentityAccessorDeregisterRequestType:
 		return n.applyDeregisterSIAccessor(buf[1:], log.Index)
 	case structs.CSIVolumeRegisterRequestType:
-		return n.applyCSIVolumeRegister(buf[1:], log.Index)
+		return n.applyCSIVolumeRegister(msgType, buf[1:], log.Index)
 	case structs.CSIVolumeDeregisterRequestType:
-		return n.applyCSIVolumeDeregister(buf[1:], log.Index)
+		return n.applyCSIVolumeDeregister(msgType, buf[1:], log.Index)
 	case structs.CSIVolumeClaimRequestType:
-		return n.applyCSIVolumeClaim(buf[1:], log.Index)
+		return n.applyCSIVolumeClaim(msgType, buf[1:], log.Index)
 	case structs.ScalingEventRegisterRequestType:
 		return n.applyUpsertScalingEvent(buf[1:], log.Index)
 	case structs.CSIVolumeClaimBatchRequestType:
-		return n.applyCSIVolumeBatchClaim(buf[1:], log.Index)
+		return n.applyCSIVolumeBatchClaim(msgType, buf[1:], log.Index)
 	case structs.CSIPluginDeleteRequestType:
-		return n.applyCSIPluginDelete(buf[1:], log.Index)
+		return n.applyCSIPluginDelete(msgType, buf[1:], log.Index)
 	case structs.NamespaceUpsertRequestType:
-		return n.applyNamespaceUpsert(buf[1:], log.Index)
+		return n.applyNamespaceUpsert(msgType, buf[1:], log.Index)
 	case structs.NamespaceDeleteRequestType:
-		return n.applyNamespaceDelete(buf[1:], log.Index)
-	// COMPAT(1.0): These messages were added and removed during the 1.0-beta
-	// series and should not be immediately reused for other purposes
-	case structs.EventSinkUpsertRequestType,
-		structs.EventSinkDeleteRequestType,
-		structs.BatchEventSinkUpdateProgressType:
-		return nil
+		return n.applyNamespaceDelete(msgType, buf[1:], log.Index)
+	case structs.EventSinkUpsertRequestType:
+		return n.applyEventSinkUpsert(msgType, buf[1:], log.Index)
+	case structs.EventSinkDeleteRequestType:
+		return n.applyEventSinkDelete(msgType, buf[1:], log.Index)
+	case structs.BatchEventSinkUpdateProgressType:
+		return n.applyEventSinkProgressUpdate(msgType, buf[1:], log.Index)
 	case structs.OneTimeTokenUpsertRequestType:
 		return n.applyOneTimeTokenUpsert(msgType, buf[1:], log.Index)
 	case structs.OneTimeTokenDeleteRequestType:
@@ -312,6 +321,38 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeleteServiceRegistrationByID(msgType, buf[1:], log.Index)
 	case structs.ServiceRegistrationDeleteByNodeIDRequestType:
 		return n.applyDeleteServiceRegistrationByNodeID(msgType, buf[1:], log.Index)
+	case structs.VarApplyStateRequestType:
+		return n.applyVariableOperation(msgType, buf[1:], log.Index)
+	case structs.RootKeyMetaUpsertRequestType:
+		return n.applyRootKeyMetaUpsert(msgType, buf[1:], log.Index)
+	case structs.ACLRolesUpsertRequestType:
+		return n.applyACLRolesUpsert(msgType, buf[1:], log.Index)
+	case structs.ACLRolesDeleteByIDRequestType:
+		return n.applyACLRolesDeleteByID(msgType, buf[1:], log.Index)
+	case structs.ACLAuthMethodsUpsertRequestType:
+		return n.applyACLAuthMethodsUpsert(msgType, buf[1:], log.Index)
+	case structs.ACLAuthMethodsDeleteRequestType:
+		return n.applyACLAuthMethodsDelete(msgType, buf[1:], log.Index)
+	case structs.ACLBindingRulesUpsertRequestType:
+		return n.applyACLBindingRulesUpsert(msgType, buf[1:], log.Index)
+	case structs.ACLBindingRulesDeleteRequestType:
+		return n.applyACLBindingRulesDelete(msgType, buf[1:], log.Index)
+	case structs.NodePoolUpsertRequestType:
+		return n.applyNodePoolUpsert(msgType, buf[1:], log.Index)
+	case structs.NodePoolDeleteRequestType:
+		return n.applyNodePoolDelete(msgType, buf[1:], log.Index)
+	case structs.PeriodicLaunchSkipRequestType:
+		return n.applyPeriodicLaunchSkip(msgType, buf[1:], log.Index)
+	case structs.JobEvalPauseRequestType:
+		return n.applyJobEvalPause(msgType, buf[1:], log.Index)
+	case structs.MaintenancePlanUpsertRequestType:
+		return n.applyMaintenancePlanUpsert(msgType, buf[1:], log.Index)
+	case structs.MaintenancePlanDeleteRequestType:
+		return n.applyMaintenancePlanDelete(msgType, buf[1:], log.Index)
+	case structs.HostVolumeUpsertRequestType:
+		return n.applyHostVolumeUpsert(msgType, buf[1:], log.Index)
+	case structs.HostVolumeDeleteRequestType:
+		return n.applyHostVolumeDelete(msgType, buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -518,7 +559,7 @@ func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index
 	 */
 	req.Job.Canonicalize()
 
-	if err := n.state.UpsertJob(msgType, index, req.Job); err != nil {
+	if err := n.state.UpsertJobWithSubmission(msgType, index, req.Submission, req.Job); err != nil {
 		n.logger.Error("UpsertJob failed", "error", err)
 		return err
 	}
@@ -554,7 +595,7 @@ func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index
 				Namespace: req.Namespace,
 				Launch:    time.Now(),
 			}
-			if err := n.state.UpsertPeriodicLaunch(index, launch); err != nil {
+			if err := n.state.UpsertPeriodicLaunch(msgType, index, launch); err != nil {
 				n.logger.Error("UpsertPeriodicLaunch failed", "error", err)
 				return err
 			}
@@ -585,7 +626,7 @@ func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index
 				Namespace: req.Namespace,
 				Launch:    t,
 			}
-			if err := n.state.UpsertPeriodicLaunch(index, launch); err != nil {
+			if err := n.state.UpsertPeriodicLaunch(msgType, index, launch); err != nil {
 				n.logger.Error("UpsertPeriodicLaunch failed", "error", err)
 				return err
 			}
@@ -711,6 +752,10 @@ func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, pu
 		// the job was updated to be non-periodic, thus checking if it is periodic
 		// doesn't ensure we clean it up properly.
 		n.state.DeletePeriodicLaunchTxn(index, namespace, jobID, tx)
+
+		// The evaluation pause is stored on the job, so it no longer applies
+		// once the job is purged.
+		n.evalBroker.SetJobEvalsPaused(namespace, jobID, false)
 	} else {
 		// Get the current job and mark it as stopped and re-insert it.
 		ws := memdb.NewWatchSet()
@@ -791,6 +836,11 @@ func (n *nomadFSM) applyDeleteEval(buf []byte, index uint64) interface{} {
 		n.logger.Error("DeleteEval failed", "error", err)
 		return err
 	}
+
+	// Deleted evaluations may still be held for paused jobs or tracked as
+	// blocked, so stop tracking them.
+	n.evalBroker.RemovePaused(req.Evals)
+	n.blockedEvals.UntrackEvals(req.Evals)
 	return nil
 }
 
@@ -1103,6 +1153,23 @@ func (n *nomadFSM) applyJobStability(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyJobEvalPause is used to pause or resume the evaluations of a job
+func (n *nomadFSM) applyJobEvalPause(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_eval_pause"}, time.Now())
+	var req structs.JobEvalPauseRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateJobEvalPause(msgType, index, req.Namespace, req.JobID, req.Paused); err != nil {
+		n.logger.Error("UpdateJobEvalPause failed", "error", err)
+		return err
+	}
+
+	n.evalBroker.SetJobEvalsPaused(req.Namespace, req.JobID, req.Paused)
+	return nil
+}
+
 // applyACLPolicyUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLPolicyUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_upsert"}, time.Now())
@@ -1223,7 +1290,7 @@ func (n *nomadFSM) applyOneTimeTokenExpire(msgType structs.MessageType, buf []by
 	return nil
 }
 
-func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyAutopilotUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.AutopilotSetConfigRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
@@ -1231,16 +1298,16 @@ func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "autopilot"}, time.Now())
 
 	if req.CAS {
-		act, err := n.state.AutopilotCASConfig(index, req.Config.ModifyIndex, &req.Config)
+		act, err := n.state.AutopilotCASConfig(msgType, index, req.Config.ModifyIndex, &req.Config)
 		if err != nil {
 			return err
 		}
 		return act
 	}
-	return n.state.AutopilotSetConfig(index, &req.Config)
+	return n.state.AutopilotSetConfig(msgType, index, &req.Config)
 }
 
-func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applySchedulerConfigUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.SchedulerSetConfigRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
@@ -1250,23 +1317,23 @@ func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interfac
 	req.Config.Canonicalize()
 
 	if req.CAS {
-		applied, err := n.state.SchedulerCASConfig(index, req.Config.ModifyIndex, &req.Config)
+		applied, err := n.state.SchedulerCASConfig(msgType, index, req.Config.ModifyIndex, &req.Config)
 		if err != nil {
 			return err
 		}
 		return applied
 	}
-	return n.state.SchedulerSetConfig(index, &req.Config)
+	return n.state.SchedulerSetConfig(msgType, index, &req.Config)
 }
 
-func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeRegister(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIVolumeRegisterRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_register"}, time.Now())
 
-	if err := n.state.UpsertCSIVolume(index, req.Volumes); err != nil {
+	if err := n.state.UpsertCSIVolume(msgType, index, req.Volumes); err != nil {
 		n.logger.Error("CSIVolumeRegister failed", "error", err)
 		return err
 	}
@@ -1274,14 +1341,14 @@ func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{}
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeDeregister(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIVolumeDeregisterRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_deregister"}, time.Now())
 
-	if err := n.state.CSIVolumeDeregister(index, req.RequestNamespace(), req.VolumeIDs, req.Force); err != nil {
+	if err := n.state.CSIVolumeDeregister(msgType, index, req.RequestNamespace(), req.VolumeIDs, req.Force); err != nil {
 		n.logger.Error("CSIVolumeDeregister failed", "error", err)
 		return err
 	}
@@ -1289,7 +1356,7 @@ func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeBatchClaim(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var batch *structs.CSIVolumeClaimBatchRequest
 	if err := structs.Decode(buf, &batch); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
@@ -1297,7 +1364,7 @@ func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_batch_claim"}, time.Now())
 
 	for _, req := range batch.Claims {
-		err := n.state.CSIVolumeClaim(index, req.RequestNamespace(),
+		err := n.state.CSIVolumeClaim(msgType, index, req.RequestNamespace(),
 			req.VolumeID, req.ToClaim())
 		if err != nil {
 			n.logger.Error("CSIVolumeClaim for batch failed", "error", err)
@@ -1307,28 +1374,28 @@ func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeClaim(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeClaim(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIVolumeClaimRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_claim"}, time.Now())
 
-	if err := n.state.CSIVolumeClaim(index, req.RequestNamespace(), req.VolumeID, req.ToClaim()); err != nil {
+	if err := n.state.CSIVolumeClaim(msgType, index, req.RequestNamespace(), req.VolumeID, req.ToClaim()); err != nil {
 		n.logger.Error("CSIVolumeClaim failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (n *nomadFSM) applyCSIPluginDelete(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIPluginDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIPluginDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_plugin_delete"}, time.Now())
 
-	if err := n.state.DeleteCSIPlugin(index, req.ID); err != nil {
+	if err := n.state.DeleteCSIPlugin(msgType, index, req.ID); err != nil {
 		// "plugin in use" is an error for the state store but not for typical
 		// callers, so reduce log noise by not logging that case here
 		if err.Error() != "plugin in use" {
@@ -1340,7 +1407,7 @@ func (n *nomadFSM) applyCSIPluginDelete(buf []byte, index uint64) interface{} {
 }
 
 // applyNamespaceUpsert is used to upsert a set of namespaces
-func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyNamespaceUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_upsert"}, time.Now())
 	var req structs.NamespaceUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -1362,7 +1429,7 @@ func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
 		}
 	}
 
-	if err := n.state.UpsertNamespaces(index, req.Namespaces); err != nil {
+	if err := n.state.UpsertNamespaces(msgType, index, req.Namespaces); err != nil {
 		n.logger.Error("UpsertNamespaces failed", "error", err)
 		return err
 	}
@@ -1376,14 +1443,14 @@ func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
 }
 
 // applyNamespaceDelete is used to delete a set of namespaces
-func (n *nomadFSM) applyNamespaceDelete(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyNamespaceDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_delete"}, time.Now())
 	var req structs.NamespaceDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.DeleteNamespaces(index, req.Namespaces); err != nil {
+	if err := n.state.DeleteNamespaces(msgType, index, req.Namespaces); err != nil {
 		n.logger.Error("DeleteNamespaces failed", "error", err)
 	}
 
@@ -1666,9 +1733,14 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
-		// COMPAT(1.0): Allow 1.0-beta clusterers to gracefully handle
 		case EventSinkSnapshot:
-			return nil
+			sink := new(structs.EventSink)
+			if err := dec.Decode(sink); err != nil {
+				return err
+			}
+			if err := restore.EventSinkRestore(sink); err != nil {
+				return err
+			}
 
 		case ServiceRegistrationSnapshot:
 
@@ -1685,6 +1757,87 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case VariablesSnapshot:
+			variable := new(structs.VariableEncrypted)
+			if err := dec.Decode(variable); err != nil {
+				return err
+			}
+			if err := restore.VariablesRestore(variable); err != nil {
+				return err
+			}
+
+		case RootKeyMetaSnapshot:
+			keyMeta := new(structs.RootKeyMeta)
+			if err := dec.Decode(keyMeta); err != nil {
+				return err
+			}
+			if err := restore.RootKeyMetaRestore(keyMeta); err != nil {
+				return err
+			}
+
+		case ACLRoleSnapshot:
+			aclRole := new(structs.ACLRole)
+			if err := dec.Decode(aclRole); err != nil {
+				return err
+			}
+			if err := restore.ACLRoleRestore(aclRole); err != nil {
+				return err
+			}
+
+		case ACLAuthMethodSnapshot:
+			authMethod := new(structs.ACLAuthMethod)
+			if err := dec.Decode(authMethod); err != nil {
+				return err
+			}
+			if err := restore.ACLAuthMethodRestore(authMethod); err != nil {
+				return err
+			}
+
+		case ACLBindingRuleSnapshot:
+			bindingRule := new(structs.ACLBindingRule)
+			if err := dec.Decode(bindingRule); err != nil {
+				return err
+			}
+			if err := restore.ACLBindingRuleRestore(bindingRule); err != nil {
+				return err
+			}
+
+		case NodePoolSnapshot:
+			pool := new(structs.NodePool)
+			if err := dec.Decode(pool); err != nil {
+				return err
+			}
+			if err := restore.NodePoolRestore(pool); err != nil {
+				return err
+			}
+
+		case JobSubmissionSnapshot:
+			sub := new(structs.JobSubmission)
+			if err := dec.Decode(sub); err != nil {
+				return err
+			}
+			if err := restore.JobSubmissionRestore(sub); err != nil {
+				return err
+			}
+
+		case MaintenancePlanSnapshot:
+			plan := new(structs.MaintenancePlan)
+			if err := dec.Decode(plan); err != nil {
+				return err
+			}
+			if err := restore.MaintenancePlanRestore(plan); err != nil {
+				return err
+			}
+
+		case HostVolumeSnapshot:
+			vol := new(structs.HostVolume)
+			if err := dec.Decode(vol); err != nil {
+				return err
+			}
+			if err := restore.HostVolumeRestore(vol); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -1944,6 +2097,306 @@ func (n *nomadFSM) applyDeleteServiceRegistrationByNodeID(msgType structs.Messag
 	return nil
 }
 
+func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_operation"}, time.Now())
+	var req structs.VarApplyStateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	switch req.Op {
+	case structs.VarOpSet:
+		return n.state.VarSet(index, &req)
+	case structs.VarOpDelete:
+		return n.state.VarDelete(index, &req)
+	case structs.VarOpDeleteCAS:
+		return n.state.VarDeleteCAS(index, &req)
+	case structs.VarOpCAS:
+		return n.state.VarSetCAS(index, &req)
+	default:
+		err := fmt.Errorf("Invalid variable operation '%s'", req.Op)
+		n.logger.Warn("Invalid variable operation", "operation", req.Op)
+		return err
+	}
+}
+
+func (n *nomadFSM) applyRootKeyMetaUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_upsert"}, time.Now())
+	var req structs.KeyringUpdateRootKeyMetaRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertRootKeyMeta(index, req.RootKeyMeta); err != nil {
+		n.logger.Error("UpsertRootKeyMeta failed", "error", err)
+		return err
+	}
+
+	// Any server which is missing the key material will fetch it from its
+	// peers via the keyring replicator.
+	return nil
+}
+
+// applyACLRolesUpsert is used to apply an ACL role upsert Raft log.
+func (n *nomadFSM) applyACLRolesUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_upsert"}, time.Now())
+	var req structs.ACLRolesUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLRoles(msgType, index, req.ACLRoles, req.AllowMissingPolicies); err != nil {
+		n.logger.Error("UpsertACLRoles failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyACLRolesDeleteByID is used to apply an ACL role delete Raft log.
+func (n *nomadFSM) applyACLRolesDeleteByID(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_delete_by_id"}, time.Now())
+	var req structs.ACLRolesDeleteByIDRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLRolesByID(msgType, index, req.ACLRoleIDs); err != nil {
+		n.logger.Error("DeleteACLRolesByID failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyACLAuthMethodsUpsert is used to apply an auth method upsert Raft log.
+func (n *nomadFSM) applyACLAuthMethodsUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_upsert"}, time.Now())
+	var req structs.ACLAuthMethodsUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLAuthMethods(msgType, index, req.AuthMethods); err != nil {
+		n.logger.Error("UpsertACLAuthMethods failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyACLAuthMethodsDelete is used to apply an auth method delete Raft log.
+func (n *nomadFSM) applyACLAuthMethodsDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_delete"}, time.Now())
+	var req structs.ACLAuthMethodsDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLAuthMethods(msgType, index, req.Names); err != nil {
+		n.logger.Error("DeleteACLAuthMethods failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyACLBindingRulesUpsert is used to apply a binding rule upsert Raft log.
+func (n *nomadFSM) applyACLBindingRulesUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_upsert"}, time.Now())
+	var req structs.ACLBindingRulesUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLBindingRules(msgType, index, req.ACLBindingRules, req.AllowMissingAuthMethods); err != nil {
+		n.logger.Error("UpsertACLBindingRules failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyACLBindingRulesDelete is used to apply a binding rule delete Raft log.
+func (n *nomadFSM) applyACLBindingRulesDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_delete"}, time.Now())
+	var req structs.ACLBindingRulesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLBindingRules(msgType, index, req.ACLBindingRuleIDs); err != nil {
+		n.logger.Error("DeleteACLBindingRules failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyNodePoolUpsert is used to apply a node pool upsert Raft log.
+func (n *nomadFSM) applyNodePoolUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_upsert"}, time.Now())
+	var req structs.NodePoolUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertNodePools(msgType, index, req.NodePools); err != nil {
+		n.logger.Error("UpsertNodePools failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyNodePoolDelete is used to apply a node pool delete Raft log.
+func (n *nomadFSM) applyNodePoolDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_delete"}, time.Now())
+	var req structs.NodePoolDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteNodePools(msgType, index, req.Names); err != nil {
+		n.logger.Error("DeleteNodePools failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyMaintenancePlanUpsert is used to apply a maintenance plan upsert Raft
+// log.
+func (n *nomadFSM) applyMaintenancePlanUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_maintenance_plan_upsert"}, time.Now())
+	var req structs.MaintenancePlanUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertMaintenancePlans(msgType, index, req.Plans); err != nil {
+		n.logger.Error("UpsertMaintenancePlans failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyMaintenancePlanDelete is used to apply a maintenance plan delete Raft
+// log.
+func (n *nomadFSM) applyMaintenancePlanDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_maintenance_plan_delete"}, time.Now())
+	var req structs.MaintenancePlanDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteMaintenancePlans(msgType, index, req.PlanIDs); err != nil {
+		n.logger.Error("DeleteMaintenancePlans failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyHostVolumeUpsert is used to apply a dynamic host volume upsert Raft
+// log.
+func (n *nomadFSM) applyHostVolumeUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_host_volume_upsert"}, time.Now())
+	var req structs.HostVolumeUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertHostVolumes(msgType, index, req.Volumes); err != nil {
+		n.logger.Error("UpsertHostVolumes failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyHostVolumeDelete is used to apply a dynamic host volume delete Raft
+// log.
+func (n *nomadFSM) applyHostVolumeDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_host_volume_delete"}, time.Now())
+	var req structs.HostVolumeDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteHostVolumes(msgType, index, req.RequestNamespace(), req.VolumeIDs); err != nil {
+		n.logger.Error("DeleteHostVolumes failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyEventSinkUpsert is used to apply an event sink upsert Raft log.
+func (n *nomadFSM) applyEventSinkUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sink_upsert"}, time.Now())
+	var req structs.EventSinkUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertEventSink(msgType, index, req.Sink); err != nil {
+		n.logger.Error("UpsertEventSink failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyEventSinkDelete is used to apply an event sink delete Raft log.
+func (n *nomadFSM) applyEventSinkDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sink_delete"}, time.Now())
+	var req structs.EventSinkDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteEventSinks(msgType, index, req.IDs); err != nil {
+		n.logger.Error("DeleteEventSinks failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyEventSinkProgressUpdate is used to apply a Raft log storing the
+// delivery progress of event sinks.
+func (n *nomadFSM) applyEventSinkProgressUpdate(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sink_progress_update"}, time.Now())
+	var req structs.EventSinkProgressRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateEventSinksProgress(msgType, index, req.Progress); err != nil {
+		n.logger.Error("UpdateEventSinksProgress failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyPeriodicLaunchSkip(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_periodic_launch_skip"}, time.Now())
+	var req structs.PeriodicLaunchSkipRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.SkipPeriodicLaunch(msgType, index, req.Namespace, req.JobID, req.Launch); err != nil {
+		n.logger.Error("SkipPeriodicLaunch failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "persist"}, time.Now())
 	// Register the nodes
@@ -2052,6 +2505,46 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistVariables(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistRootKeyMeta(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLRoles(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLBindingRules(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistNodePools(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistMaintenancePlans(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistHostVolumes(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistEventSinks(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -2610,6 +3103,228 @@ func (s *nomadSnapshot) persistServiceRegistrations(sink raft.SnapshotSink,
 	}
 }
 
+func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	variables, err := s.snap.Variables(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := variables.Next(); raw != nil; raw = variables.Next() {
+		variable := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariablesSnapshot)})
+		if err := encoder.Encode(variable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistRootKeyMeta(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	keys, err := s.snap.RootKeyMetas(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := keys.Next(); raw != nil; raw = keys.Next() {
+		key := raw.(*structs.RootKeyMeta)
+		sink.Write([]byte{byte(RootKeyMetaSnapshot)})
+		if err := encoder.Encode(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLRoles(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the ACL roles.
+	ws := memdb.NewWatchSet()
+	aclRolesIter, err := s.snap.GetACLRoles(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the ACL roles.
+	for raw := aclRolesIter.Next(); raw != nil; raw = aclRolesIter.Next() {
+		aclRole := raw.(*structs.ACLRole)
+
+		// Write out an ACL role snapshot.
+		sink.Write([]byte{byte(ACLRoleSnapshot)})
+		if err := encoder.Encode(aclRole); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLAuthMethods(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the auth methods.
+	ws := memdb.NewWatchSet()
+	authMethodsIter, err := s.snap.GetACLAuthMethods(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the auth methods.
+	for raw := authMethodsIter.Next(); raw != nil; raw = authMethodsIter.Next() {
+		authMethod := raw.(*structs.ACLAuthMethod)
+
+		// Write out an auth method snapshot.
+		sink.Write([]byte{byte(ACLAuthMethodSnapshot)})
+		if err := encoder.Encode(authMethod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLBindingRules(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the binding rules.
+	ws := memdb.NewWatchSet()
+	bindingRulesIter, err := s.snap.GetACLBindingRules(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the binding rules.
+	for raw := bindingRulesIter.Next(); raw != nil; raw = bindingRulesIter.Next() {
+		bindingRule := raw.(*structs.ACLBindingRule)
+
+		// Write out a binding rule snapshot.
+		sink.Write([]byte{byte(ACLBindingRuleSnapshot)})
+		if err := encoder.Encode(bindingRule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistNodePools(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the node pools.
+	ws := memdb.NewWatchSet()
+	poolsIter, err := s.snap.NodePools(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the node pools.
+	for raw := poolsIter.Next(); raw != nil; raw = poolsIter.Next() {
+		pool := raw.(*structs.NodePool)
+
+		// Write out a node pool snapshot.
+		sink.Write([]byte{byte(NodePoolSnapshot)})
+		if err := encoder.Encode(pool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the job submissions.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.JobSubmissions(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the job submissions.
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sub := raw.(*structs.JobSubmission)
+
+		// Write out a job submission snapshot.
+		sink.Write([]byte{byte(JobSubmissionSnapshot)})
+		if err := encoder.Encode(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistMaintenancePlans(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the maintenance plans.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.MaintenancePlans(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the maintenance plans.
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		plan := raw.(*structs.MaintenancePlan)
+
+		// Write out a maintenance plan snapshot.
+		sink.Write([]byte{byte(MaintenancePlanSnapshot)})
+		if err := encoder.Encode(plan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistHostVolumes(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the host volumes.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.HostVolumes(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the host volumes.
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		vol := raw.(*structs.HostVolume)
+
+		// Write out a host volume snapshot.
+		sink.Write([]byte{byte(HostVolumeSnapshot)})
+		if err := encoder.Encode(vol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistEventSinks(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	// Get all the event sinks.
+	ws := memdb.NewWatchSet()
+	iter, err := s.snap.EventSinks(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate all the event sinks.
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		eventSink := raw.(*structs.EventSink)
+
+		// Write out an event sink snapshot.
+		sink.Write([]byte{byte(EventSinkSnapshot)})
+		if err := encoder.Encode(eventSink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
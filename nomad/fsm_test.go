@@ -943,6 +943,38 @@ func TestFSM_BatchDeregisterJob(t *testing.T) {
 	require.Nil(jobOut2)
 }
 
+func TestFSM_PeriodicLaunchSkip(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	job := mock.PeriodicJob()
+	require.NoError(t, fsm.State().UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	launch := time.Now().Round(time.Second)
+	req := structs.PeriodicLaunchSkipRequest{
+		JobID:  job.ID,
+		Launch: launch,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.PeriodicLaunchSkipRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Verify the launch was recorded
+	ws := memdb.NewWatchSet()
+	launchOut, err := fsm.State().PeriodicLaunchByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, launchOut)
+	require.True(t, launch.Equal(launchOut.Launch))
+
+	// Verify the skip was counted on the parent summary
+	summary, err := fsm.State().JobSummaryByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), summary.Children.Skipped)
+}
+
 func TestFSM_UpdateEval(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -2043,6 +2075,42 @@ func TestFSM_JobStabilityUpdate(t *testing.T) {
 	}
 }
 
+func TestFSM_JobEvalPause(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+	fsm.evalBroker.SetEnabled(true)
+	state := fsm.State()
+
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1, job))
+
+	// Create a request to pause the evaluations of the job
+	req := &structs.JobEvalPauseRequest{
+		JobID:  job.ID,
+		Paused: true,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobEvalPauseRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Check that the job was updated
+	ws := memdb.NewWatchSet()
+	jout, err := state.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.True(t, jout.EvalsPaused)
+
+	// Check that new evaluations of the job are held by the broker
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	fsm.evalBroker.Enqueue(eval)
+	stats := fsm.evalBroker.Stats()
+	require.Equal(t, 0, stats.TotalReady)
+	require.Equal(t, 1, stats.TotalPaused)
+}
+
 func TestFSM_DeploymentPromotion(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -2652,14 +2720,14 @@ func TestFSM_SnapshotRestore_PeriodicLaunches(t *testing.T) {
 		Namespace: job1.Namespace,
 		Launch:    time.Now(),
 	}
-	state.UpsertPeriodicLaunch(1000, launch1)
+	state.UpsertPeriodicLaunch(structs.MsgTypeTestSetup, 1000, launch1)
 	job2 := mock.Job()
 	launch2 := &structs.PeriodicLaunch{
 		ID:        job2.ID,
 		Namespace: job2.Namespace,
 		Launch:    time.Now(),
 	}
-	state.UpsertPeriodicLaunch(1001, launch2)
+	state.UpsertPeriodicLaunch(structs.MsgTypeTestSetup, 1001, launch2)
 
 	// Verify the contents
 	fsm2 := testSnapshotRestore(t, fsm)
@@ -2834,7 +2902,7 @@ func TestFSM_SnapshotRestore_SchedulerConfiguration(t *testing.T) {
 			SystemSchedulerEnabled: true,
 		},
 	}
-	state.SchedulerSetConfig(1000, schedConfig)
+	state.SchedulerSetConfig(structs.MsgTypeTestSetup, 1000, schedConfig)
 
 	// Verify the contents
 	require := require.New(t)
@@ -2893,6 +2961,45 @@ func TestFSM_SnapshotRestore_ServiceRegistrations(t *testing.T) {
 	require.ElementsMatch(t, restoredRegs, serviceRegs)
 }
 
+func TestFSM_SnapshotRestore_Variables(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	variable := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "foo",
+		},
+		VariableData: structs.VariableData{
+			Data:  []byte("ciphertext"),
+			KeyID: "key-id",
+		},
+	}
+	resp := testState.VarSet(10, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable})
+	require.True(t, resp.IsOk())
+
+	keyMeta := structs.NewRootKeyMeta()
+	require.NoError(t, testState.UpsertRootKeyMeta(20, keyMeta))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	out, err := restoredState.GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, variable.Data, out.Data)
+	require.Equal(t, uint64(10), out.ModifyIndex)
+
+	restoredKeyMeta, err := restoredState.GetActiveRootKeyMeta(memdb.NewWatchSet())
+	require.NoError(t, err)
+	require.NotNil(t, restoredKeyMeta)
+	require.Equal(t, keyMeta.KeyID, restoredKeyMeta.KeyID)
+}
+
 func TestFSM_ReconcileSummaries(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -3244,7 +3351,7 @@ func TestFSM_DeleteNamespaces(t *testing.T) {
 
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	assert.Nil(fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	assert.Nil(fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	req := structs.NamespaceDeleteRequest{
 		Namespaces: []string{ns1.Name, ns2.Name},
@@ -3271,7 +3378,7 @@ func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
 	state := fsm.State()
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Verify the contents
 	fsm2 := testSnapshotRestore(t, fsm)
@@ -3287,6 +3394,99 @@ func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
 	}
 }
 
+func TestFSM_UpsertNodePools(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	req := structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{{Name: "dev"}, {Name: "prod"}},
+	}
+	buf, err := structs.Encode(structs.NodePoolUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Verify the node pools were created
+	for _, name := range []string{"dev", "prod"} {
+		out, err := fsm.State().NodePoolByName(nil, name)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+	}
+}
+
+func TestFSM_DeleteNodePools(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	pools := []*structs.NodePool{{Name: "dev"}, {Name: "prod"}}
+	require.NoError(t, fsm.State().UpsertNodePools(structs.MsgTypeTestSetup, 1000, pools))
+
+	req := structs.NodePoolDeleteRequest{
+		Names: []string{"dev"},
+	}
+	buf, err := structs.Encode(structs.NodePoolDeleteRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Verify only the requested node pool was deleted
+	out, err := fsm.State().NodePoolByName(nil, "dev")
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = fsm.State().NodePoolByName(nil, "prod")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	pool := &structs.NodePool{
+		Name:        "dev",
+		Description: "development nodes",
+		SchedulerConfiguration: &structs.NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+		},
+	}
+	require.NoError(t, state.UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool}))
+
+	// Verify the contents, including the built-in node pools
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Equal(t, pool, out)
+
+	out, err = state2.NodePoolByName(nil, structs.NodePoolDefault)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+}
+
+func TestFSM_SnapshotRestore_JobSubmissions(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:        "job \"example\" {}",
+		Format:        structs.JobSubmissionFormatHCL2,
+		VariableFlags: map[string]string{"count": "3"},
+	}
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, sub.Source, out.Source)
+	require.Equal(t, sub.VariableFlags, out.VariableFlags)
+	require.Equal(t, job.ID, out.JobID)
+}
+
 func TestFSM_UpsertServiceRegistrations(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -3311,6 +3511,44 @@ func TestFSM_UpsertServiceRegistrations(t *testing.T) {
 	assert.NotNil(t, out)
 }
 
+func TestFSM_ApplyVariableOperation(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	variable := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "foo",
+		},
+		VariableData: structs.VariableData{
+			Data:  []byte("ciphertext"),
+			KeyID: "key-id",
+		},
+	}
+
+	// Build and apply our message.
+	req := structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable}
+	buf, err := structs.Encode(structs.VarApplyStateRequestType, req)
+	require.NoError(t, err)
+	resp := fsm.Apply(makeLog(buf))
+	require.True(t, resp.(*structs.VarApplyStateResponse).IsOk())
+
+	out, err := fsm.State().GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Delete the variable.
+	req.Op = structs.VarOpDelete
+	buf, err = structs.Encode(structs.VarApplyStateRequestType, req)
+	require.NoError(t, err)
+	resp = fsm.Apply(makeLog(buf))
+	require.True(t, resp.(*structs.VarApplyStateResponse).IsOk())
+
+	out, err = fsm.State().GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
 func TestFSM_DeleteServiceRegistrationsByID(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -3570,3 +3808,335 @@ func TestFSM_EventBroker_JobRegisterFSMEvents(t *testing.T) {
 	require.Len(t, events, 1)
 	require.Equal(t, structs.TypeJobRegistered, events[0].Type)
 }
+
+func TestFSM_UpsertACLRoles(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Generate the upsert request and apply the change.
+	req := structs.ACLRolesUpsertRequest{
+		ACLRoles: []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()},
+	}
+	buf, err := structs.Encode(structs.ACLRolesUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Read out both ACL roles and perform an equality check using the hash.
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetACLRoleByName(ws, req.ACLRoles[0].Name)
+	require.NoError(t, err)
+	require.Equal(t, req.ACLRoles[0].Hash, out.Hash)
+
+	out, err = fsm.State().GetACLRoleByName(ws, req.ACLRoles[1].Name)
+	require.NoError(t, err)
+	require.Equal(t, req.ACLRoles[1].Hash, out.Hash)
+}
+
+func TestFSM_DeleteACLRolesByID(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Generate and upsert two ACL roles.
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, fsm.State().UpsertACLRoles(structs.MsgTypeTestSetup, 10, aclRoles, false))
+
+	// Build and apply our message.
+	req := structs.ACLRolesDeleteByIDRequest{ACLRoleIDs: []string{aclRoles[0].ID, aclRoles[1].ID}}
+	buf, err := structs.Encode(structs.ACLRolesDeleteByIDRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// List all ACL roles within state to ensure both have been removed.
+	ws := memdb.NewWatchSet()
+	iter, err := fsm.State().GetACLRoles(ws)
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 0, count)
+}
+
+func TestFSM_SnapshotRestore_ACLRoles(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Create the policies our ACL roles wants to link to.
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, testState.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy1, policy2}))
+
+	// Generate and upsert some ACL roles.
+	aclRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, aclRoles, false))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// List the ACL roles from restored state and ensure everything is as
+	// expected.
+	iter, err := restoredState.GetACLRoles(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var restoredACLRoles []*structs.ACLRole
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredACLRoles = append(restoredACLRoles, raw.(*structs.ACLRole))
+	}
+	require.ElementsMatch(t, restoredACLRoles, aclRoles)
+}
+
+func TestFSM_UpsertACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Generate the upsert request and apply the change.
+	req := structs.ACLAuthMethodsUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodsUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Read out both auth methods and perform an equality check using the
+	// hash.
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetACLAuthMethodByName(ws, req.AuthMethods[0].Name)
+	require.NoError(t, err)
+	require.Equal(t, req.AuthMethods[0].Hash, out.Hash)
+
+	out, err = fsm.State().GetACLAuthMethodByName(ws, req.AuthMethods[1].Name)
+	require.NoError(t, err)
+	require.Equal(t, req.AuthMethods[1].Hash, out.Hash)
+}
+
+func TestFSM_DeleteACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Generate and upsert two auth methods.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, fsm.State().UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	// Build and apply our message.
+	req := structs.ACLAuthMethodsDeleteRequest{Names: []string{authMethods[0].Name, authMethods[1].Name}}
+	buf, err := structs.Encode(structs.ACLAuthMethodsDeleteRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// List all auth methods within state to ensure both have been removed.
+	ws := memdb.NewWatchSet()
+	iter, err := fsm.State().GetACLAuthMethods(ws)
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 0, count)
+}
+
+func TestFSM_UpsertACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Create the auth method our binding rules belong to.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = "auth0"
+	require.NoError(t, fsm.State().UpsertACLAuthMethods(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLAuthMethod{authMethod}))
+
+	// Generate the upsert request and apply the change.
+	req := structs.ACLBindingRulesUpsertRequest{
+		ACLBindingRules: []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRulesUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Read out both binding rules and perform an equality check using the
+	// hash.
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().GetACLBindingRule(ws, req.ACLBindingRules[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, req.ACLBindingRules[0].Hash, out.Hash)
+
+	out, err = fsm.State().GetACLBindingRule(ws, req.ACLBindingRules[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, req.ACLBindingRules[1].Hash, out.Hash)
+}
+
+func TestFSM_DeleteACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Generate and upsert two binding rules.
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, fsm.State().UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, bindingRules, true))
+
+	// Build and apply our message.
+	req := structs.ACLBindingRulesDeleteRequest{
+		ACLBindingRuleIDs: []string{bindingRules[0].ID, bindingRules[1].ID},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRulesDeleteRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// List all binding rules within state to ensure both have been removed.
+	ws := memdb.NewWatchSet()
+	iter, err := fsm.State().GetACLBindingRules(ws)
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 0, count)
+}
+
+func TestFSM_SnapshotRestore_ACLAuthMethods_ACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Generate and upsert some auth methods and binding rules.
+	authMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, authMethods))
+
+	bindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 20, bindingRules, true))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	// List the auth methods and binding rules from restored state and ensure
+	// everything is as expected.
+	iter, err := restoredState.GetACLAuthMethods(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var restoredAuthMethods []*structs.ACLAuthMethod
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredAuthMethods = append(restoredAuthMethods, raw.(*structs.ACLAuthMethod))
+	}
+	require.ElementsMatch(t, restoredAuthMethods, authMethods)
+
+	iter, err = restoredState.GetACLBindingRules(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var restoredBindingRules []*structs.ACLBindingRule
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		restoredBindingRules = append(restoredBindingRules, raw.(*structs.ACLBindingRule))
+	}
+	require.ElementsMatch(t, restoredBindingRules, bindingRules)
+}
+
+func TestFSM_SnapshotRestore_MaintenancePlans(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	plan := &structs.MaintenancePlan{
+		ID:     "d3b3d15e-cc88-40f2-a4b4-4c3f46a1b0b5",
+		Status: structs.MaintenancePlanStatusRunning,
+	}
+	plan.Canonicalize()
+	plan.SetNodes([]*structs.Node{mock.Node()}, time.Now())
+	require.NoError(t, testState.UpsertMaintenancePlans(structs.MsgTypeTestSetup, 10,
+		[]*structs.MaintenancePlan{plan}))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	out, err := restoredState.MaintenancePlanByID(nil, plan.ID)
+	require.NoError(t, err)
+	require.Equal(t, plan, out)
+}
+
+func TestFSM_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Register the sink
+	sink := mock.EventSink()
+	buf, err := structs.Encode(structs.EventSinkUpsertRequestType,
+		structs.EventSinkUpsertRequest{Sink: sink})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, sink.Address, out.Address)
+
+	// Store its progress
+	buf, err = structs.Encode(structs.BatchEventSinkUpdateProgressType,
+		structs.EventSinkProgressRequest{Progress: map[string]*structs.EventSinkProgress{
+			sink.ID: {LatestIndex: 500, LatestIndexBatches: 2},
+		}})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(500), out.LatestIndex)
+	require.Equal(t, 2, out.LatestIndexBatches)
+
+	// Delete the sink
+	buf, err = structs.Encode(structs.EventSinkDeleteRequestType,
+		structs.EventSinkDeleteRequest{IDs: []string{sink.ID}})
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_SnapshotRestore_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	sink := mock.EventSink()
+	require.NoError(t, testState.UpsertEventSink(structs.MsgTypeTestSetup, 10, sink))
+
+	// Perform a snapshot restore.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredState := restoredFSM.State()
+
+	out, err := restoredState.EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Equal(t, sink, out)
+}
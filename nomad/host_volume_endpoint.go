@@ -0,0 +1,429 @@
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+)
+
+// HostVolume is the server RPC endpoint for dynamic host volumes
+type HostVolume struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Create creates a dynamic host volume on the node given in the request, or
+// on a node selected by the server among the ready nodes of the volume's node
+// pool that match its constraints, and stores it once the node has created it.
+func (v *HostVolume) Create(args *structs.HostVolumeCreateRequest, reply *structs.HostVolumeCreateResponse) error {
+	if done, err := v.srv.forward(structs.HostVolumeCreateRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "host_volume", "create"}, time.Now())
+
+	allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityHostVolumeWrite)
+	aclObj, err := v.srv.WriteACLObj(&args.WriteRequest, false)
+	if err != nil {
+		return err
+	}
+	if !allowVolume(aclObj, args.RequestNamespace()) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Volume == nil {
+		return fmt.Errorf("missing volume definition")
+	}
+
+	snap, err := v.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// This is the only namespace we ACL checked, force the volume to use it
+	vol := args.Volume.Copy()
+	vol.Namespace = args.RequestNamespace()
+	ns, err := snap.NamespaceByName(nil, vol.Namespace)
+	if err != nil {
+		return err
+	}
+	if ns == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "namespace %q does not exist", vol.Namespace)
+	}
+
+	// Volumes default to the node pool of their namespace, as jobs do
+	if vol.NodePool == "" && vol.NodeID == "" && ns.NodePoolConfiguration != nil {
+		vol.NodePool = ns.NodePoolConfiguration.Default
+	}
+	vol.Canonicalize()
+	if err := vol.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "volume validation failed: %v", err)
+	}
+
+	node, err := v.placeHostVolume(snap, vol, ns.NodePoolConfiguration)
+	if err != nil {
+		return fmt.Errorf("could not place volume %q: %w", vol.Name, err)
+	}
+
+	vol.ID = uuid.Generate()
+	vol.NodeID = node.ID
+	vol.NodePool = node.NodePool
+	vol.State = structs.HostVolumeStatePending
+
+	cReq := &cstructs.ClientHostVolumeCreateRequest{
+		ID:                        vol.ID,
+		Name:                      vol.Name,
+		PluginID:                  vol.PluginID,
+		NodeID:                    vol.NodeID,
+		RequestedCapacityMinBytes: vol.RequestedCapacityMinBytes,
+		RequestedCapacityMaxBytes: vol.RequestedCapacityMaxBytes,
+		Parameters:                vol.Parameters,
+	}
+	cResp := &cstructs.ClientHostVolumeCreateResponse{}
+	if err := v.srv.RPC("ClientHostVolume.Create", cReq, cResp); err != nil {
+		return err
+	}
+	vol.HostPath = cResp.HostPath
+	vol.CapacityBytes = cResp.CapacityBytes
+
+	upsertArgs := &structs.HostVolumeUpsertRequest{
+		Volumes:      []*structs.HostVolume{vol},
+		WriteRequest: args.WriteRequest,
+	}
+	out, index, err := v.srv.raftApply(structs.HostVolumeUpsertRequestType, upsertArgs)
+	if err == nil {
+		if respErr, ok := out.(error); ok && respErr != nil {
+			err = respErr
+		}
+	}
+	if err != nil {
+		v.logger.Error("raft apply failed", "error", err, "method", "upsert")
+
+		// The volume can't be tracked, so delete it from the node
+		dReq := &cstructs.ClientHostVolumeDeleteRequest{
+			ID:         vol.ID,
+			Name:       vol.Name,
+			PluginID:   vol.PluginID,
+			NodeID:     vol.NodeID,
+			HostPath:   vol.HostPath,
+			Parameters: vol.Parameters,
+		}
+		if dErr := v.srv.RPC("ClientHostVolume.Delete", dReq, &cstructs.ClientHostVolumeDeleteResponse{}); dErr != nil {
+			v.logger.Warn("failed to clean up host volume", "volume_id", vol.ID, "error", dErr)
+		}
+		return err
+	}
+
+	// Read back the volume to return its state
+	stored, err := v.srv.State().HostVolumeByID(nil, vol.Namespace, vol.ID, false)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		stored = vol
+	}
+
+	reply.Volume = stored
+	reply.Index = index
+	return nil
+}
+
+// placeHostVolume returns the node the volume must be created on. The node
+// must be ready, be in the node pool of the volume and in a pool the
+// namespace of the volume is allowed to use, have the plugin of the volume
+// and not already have a host volume with the same name.
+func (v *HostVolume) placeHostVolume(snap *state.StateSnapshot, vol *structs.HostVolume,
+	pools *structs.NamespaceNodePoolConfiguration) (*structs.Node, error) {
+
+	pluginAttr := structs.HostVolumePluginAttrPrefix + vol.PluginID + ".version"
+
+	if vol.NodePool != "" && vol.NodePool != structs.NodePoolAll && !pools.IsAllowed(vol.NodePool) {
+		return nil, fmt.Errorf("node pool %q is not allowed in namespace %q", vol.NodePool, vol.Namespace)
+	}
+
+	if vol.NodeID != "" {
+		node, err := snap.NodeByID(nil, vol.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			return nil, fmt.Errorf("%w %s", structs.ErrUnknownNode, vol.NodeID)
+		}
+		if node.Status == structs.NodeStatusDown {
+			return nil, fmt.Errorf("node %s is down", node.ID)
+		}
+		if vol.NodePool != "" && vol.NodePool != structs.NodePoolAll && node.NodePool != vol.NodePool {
+			return nil, fmt.Errorf("node %s is not in node pool %q", node.ID, vol.NodePool)
+		}
+		if !pools.IsAllowed(node.NodePool) {
+			return nil, fmt.Errorf("node pool %q of node %s is not allowed in namespace %q",
+				node.NodePool, node.ID, vol.Namespace)
+		}
+		if _, ok := node.Attributes[pluginAttr]; !ok {
+			return nil, fmt.Errorf("node %s does not have host volume plugin %q", node.ID, vol.PluginID)
+		}
+		exists, err := nodeHasHostVolumeNamed(snap, node, vol.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("node %s already has a host volume named %q", node.ID, vol.Name)
+		}
+		return node, nil
+	}
+
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := scheduler.NewEvalContext(nil, snap, &structs.Plan{}, v.logger)
+	checker := scheduler.NewConstraintChecker(ctx, vol.Constraints)
+
+	var candidates []*structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if vol.NodePool != structs.NodePoolAll && node.NodePool != vol.NodePool {
+			continue
+		}
+		if !pools.IsAllowed(node.NodePool) {
+			continue
+		}
+		if !node.Ready() {
+			continue
+		}
+		if _, ok := node.Attributes[pluginAttr]; !ok {
+			continue
+		}
+		if !checker.Feasible(node) {
+			continue
+		}
+		exists, err := nodeHasHostVolumeNamed(snap, node, vol.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+		candidates = append(candidates, node)
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New("no node meets constraints")
+	}
+	return candidates[rand.Intn(len(candidates))], nil
+}
+
+// nodeHasHostVolumeNamed returns whether the node has a host volume with the
+// given name, including the dynamic host volumes that are still being created
+// and are not fingerprinted yet. It is only used to select a node, as the
+// state store enforces the uniqueness of names when the volume is stored.
+func nodeHasHostVolumeNamed(snap *state.StateSnapshot, node *structs.Node, name string) (bool, error) {
+	if _, ok := node.HostVolumes[name]; ok {
+		return true, nil
+	}
+
+	iter, err := snap.HostVolumesByNodeID(nil, node.ID)
+	if err != nil {
+		return false, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.HostVolume).Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Delete deletes dynamic host volumes from the state store and from their
+// node. Volumes that are still claimed by allocations can not be deleted.
+func (v *HostVolume) Delete(args *structs.HostVolumeDeleteRequest, reply *structs.HostVolumeDeleteResponse) error {
+	if done, err := v.srv.forward(structs.HostVolumeDeleteRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "host_volume", "delete"}, time.Now())
+
+	allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityHostVolumeWrite)
+	aclObj, err := v.srv.WriteACLObj(&args.WriteRequest, false)
+	if err != nil {
+		return err
+	}
+	ns := args.RequestNamespace()
+	if !allowVolume(aclObj, ns) {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.VolumeIDs) == 0 {
+		return fmt.Errorf("missing volume IDs")
+	}
+
+	snap, err := v.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	var vols []*structs.HostVolume
+	for _, volID := range args.VolumeIDs {
+		vol, err := snap.HostVolumeByID(nil, ns, volID, false)
+		if err != nil {
+			return err
+		}
+		if vol == nil {
+			v.logger.Warn("host volume to be deleted was already deleted", "volume_id", volID)
+			continue
+		}
+		vols = append(vols, vol)
+	}
+
+	// The volumes are removed from the state store before their nodes, as
+	// only the state store transaction reliably checks they are not in use
+	out, index, err := v.srv.raftApply(structs.HostVolumeDeleteRequestType, args)
+	if err != nil {
+		v.logger.Error("raft apply failed", "error", err, "method", "delete")
+		return err
+	}
+	if respErr, ok := out.(error); ok && respErr != nil {
+		return respErr
+	}
+	reply.Index = index
+
+	// NOTE: deleting the volumes on their node can't be made atomic with
+	// their removal from the state store. A volume whose node is gone is
+	// only removed from the state store.
+	var mErr multierror.Error
+	for _, vol := range vols {
+		node, err := snap.NodeByID(nil, vol.NodeID)
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			v.logger.Warn("node of host volume to be deleted no longer exists",
+				"volume_id", vol.ID, "node_id", vol.NodeID)
+			continue
+		}
+
+		cReq := &cstructs.ClientHostVolumeDeleteRequest{
+			ID:         vol.ID,
+			Name:       vol.Name,
+			PluginID:   vol.PluginID,
+			NodeID:     vol.NodeID,
+			HostPath:   vol.HostPath,
+			Parameters: vol.Parameters,
+		}
+		if err := v.srv.RPC("ClientHostVolume.Delete", cReq, &cstructs.ClientHostVolumeDeleteResponse{}); err != nil {
+			v.logger.Error("failed to delete host volume from its node",
+				"volume_id", vol.ID, "node_id", vol.NodeID, "error", err)
+			mErr.Errors = append(mErr.Errors,
+				fmt.Errorf("volume %s was deleted but could not be removed from node %s: %w", vol.ID, vol.NodeID, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Get fetches detailed information about a specific dynamic host volume,
+// including the allocations that claim it.
+func (v *HostVolume) Get(args *structs.HostVolumeGetRequest, reply *structs.HostVolumeGetResponse) error {
+	if done, err := v.srv.forward(structs.HostVolumeGetRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "host_volume", "get"}, time.Now())
+
+	allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityHostVolumeRead)
+	aclObj, err := v.srv.QueryACLObj(&args.QueryOptions, false)
+	if err != nil {
+		return err
+	}
+	ns := args.RequestNamespace()
+	if !allowVolume(aclObj, ns) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.ID == "" {
+		return fmt.Errorf("missing volume ID")
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			vol, err := store.HostVolumeByID(ws, ns, args.ID, true)
+			if err != nil {
+				return err
+			}
+
+			reply.Volume = vol
+			return v.srv.replySetIndex(state.TableHostVolumes, &reply.QueryMeta)
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// List replies with the dynamic host volumes of a namespace, or of all the
+// namespaces the token can read, optionally filtered by node ID and ID
+// prefix.
+func (v *HostVolume) List(args *structs.HostVolumeListRequest, reply *structs.HostVolumeListResponse) error {
+	if done, err := v.srv.forward(structs.HostVolumeListRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "host_volume", "list"}, time.Now())
+
+	aclObj, err := v.srv.QueryACLObj(&args.QueryOptions, false)
+	if err != nil {
+		return err
+	}
+	allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityHostVolumeRead)
+	ns := args.RequestNamespace()
+	if ns != structs.AllNamespacesSentinel && !allowVolume(aclObj, ns) {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			var iter memdb.ResultIterator
+			var err error
+			switch {
+			case args.NodeID != "":
+				iter, err = store.HostVolumesByNodeID(ws, args.NodeID)
+			case ns != structs.AllNamespacesSentinel:
+				iter, err = store.HostVolumesByIDPrefix(ws, ns, args.Prefix)
+			default:
+				iter, err = store.HostVolumes(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			vols := []*structs.HostVolumeStub{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				vol := raw.(*structs.HostVolume)
+				if ns != structs.AllNamespacesSentinel && vol.Namespace != ns {
+					continue
+				}
+				if !strings.HasPrefix(vol.ID, args.Prefix) {
+					continue
+				}
+				if !allowVolume(aclObj, vol.Namespace) {
+					continue
+				}
+				vols = append(vols, vol.Stub())
+			}
+
+			reply.Volumes = vols
+			return v.srv.replySetIndex(state.TableHostVolumes, &reply.QueryMeta)
+		}}
+	return v.srv.blockingRPC(&opts)
+}
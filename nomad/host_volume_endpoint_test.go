@@ -0,0 +1,351 @@
+package nomad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostVolumeEndpoint_CreateDelete(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) { c.BootstrapExpect = 1 })
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	codec := rpcClient(t, s1)
+
+	c1, cleanupC1 := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s1.config.RPCAddr.String()}
+	})
+	defer cleanupC1()
+
+	select {
+	case <-c1.Ready():
+	case <-time.After(10 * time.Second):
+		t.Fatal("client timedout on initialize")
+	}
+	waitForNodes(t, s1, 1, 1)
+
+	// Create a volume on a node selected by the server
+	createReq := &structs.HostVolumeCreateRequest{
+		Volume: &structs.HostVolume{
+			Name:       "data",
+			Parameters: map[string]string{"mode": "0750"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var createResp structs.HostVolumeCreateResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.HostVolumeCreateRPCMethod, createReq, &createResp))
+
+	vol := createResp.Volume
+	require.NotNil(t, vol)
+	require.NotEmpty(t, vol.ID)
+	require.Equal(t, c1.NodeID(), vol.NodeID)
+	require.Equal(t, structs.HostVolumePluginMkdir, vol.PluginID)
+	require.DirExists(t, vol.HostPath)
+
+	// The volume is ready once the client has fingerprinted it
+	testutil.WaitForResult(func() (bool, error) {
+		getReq := &structs.HostVolumeGetRequest{
+			ID:           vol.ID,
+			QueryOptions: structs.QueryOptions{Region: "global", Namespace: structs.DefaultNamespace},
+		}
+		var getResp structs.HostVolumeGetResponse
+		if err := msgpackrpc.CallWithCodec(codec, structs.HostVolumeGetRPCMethod, getReq, &getResp); err != nil {
+			return false, err
+		}
+		if getResp.Volume.State != structs.HostVolumeStateReady {
+			return false, fmt.Errorf("expected volume to be ready, got %q", getResp.Volume.State)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	node, err := s1.State().NodeByID(nil, c1.NodeID())
+	require.NoError(t, err)
+	require.Equal(t, vol.ID, node.HostVolumes["data"].ID)
+
+	// The name is unique on the node
+	createReq.Volume.NodeID = c1.NodeID()
+	err = msgpackrpc.CallWithCodec(codec, structs.HostVolumeCreateRPCMethod, createReq, &createResp)
+	require.ErrorContains(t, err, `already has a host volume named "data"`)
+
+	listReq := &structs.HostVolumeListRequest{
+		NodeID:       c1.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global", Namespace: structs.DefaultNamespace},
+	}
+	var listResp structs.HostVolumeListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.HostVolumeListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Volumes, 1)
+	require.Equal(t, vol.ID, listResp.Volumes[0].ID)
+
+	// Delete the volume from the node and the state store
+	delReq := &structs.HostVolumeDeleteRequest{
+		VolumeIDs:    []string{vol.ID},
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: structs.DefaultNamespace},
+	}
+	var delResp structs.HostVolumeDeleteResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.HostVolumeDeleteRPCMethod, delReq, &delResp))
+
+	_, err = os.Stat(vol.HostPath)
+	require.True(t, os.IsNotExist(err))
+
+	stored, err := s1.State().HostVolumeByID(nil, structs.DefaultNamespace, vol.ID, false)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := s1.State().NodeByID(nil, c1.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if _, ok := node.HostVolumes["data"]; ok {
+			return false, fmt.Errorf("expected volume to be removed from the node")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestHostVolumeEndpoint_Create_Placement(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	codec := rpcClient(t, s1)
+
+	// Only the ready node with the plugin in the right pool is a candidate
+	withPlugin := mock.Node()
+	withPlugin.Attributes["plugins.host_volume.mkdir.version"] = "0.0.1"
+	noPlugin := mock.Node()
+	otherPool := mock.Node()
+	otherPool.NodePool = "other"
+	otherPool.Attributes["plugins.host_volume.mkdir.version"] = "0.0.1"
+	down := mock.Node()
+	down.Status = structs.NodeStatusDown
+	down.Attributes["plugins.host_volume.mkdir.version"] = "0.0.1"
+
+	for i, node := range []*structs.Node{withPlugin, noPlugin, otherPool, down} {
+		require.NoError(t, s1.State().UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	snap, err := s1.State().Snapshot()
+	require.NoError(t, err)
+	endpoint := s1.staticEndpoints.HostVolume
+
+	vol := &structs.HostVolume{Name: "data"}
+	vol.Canonicalize()
+	for i := 0; i < 10; i++ {
+		node, err := endpoint.placeHostVolume(snap, vol, nil)
+		require.NoError(t, err)
+		require.Equal(t, withPlugin.ID, node.ID)
+	}
+
+	// Constraints are enforced
+	vol.Constraints = []*structs.Constraint{{
+		LTarget: "${attr.kernel.name}",
+		RTarget: "windows",
+		Operand: "=",
+	}}
+	_, err = endpoint.placeHostVolume(snap, vol, nil)
+	require.EqualError(t, err, "no node meets constraints")
+
+	// The node pool "all" matches any pool
+	vol.Constraints = nil
+	vol.NodePool = structs.NodePoolAll
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		node, err := endpoint.placeHostVolume(snap, vol, nil)
+		require.NoError(t, err)
+		seen[node.ID] = true
+	}
+	require.Equal(t, map[string]bool{withPlugin.ID: true, otherPool.ID: true}, seen)
+
+	// Volumes that are still being created can't be replaced by another
+	// volume with the same name
+	pending := &structs.HostVolume{
+		ID:     uuid.Generate(),
+		Name:   "data",
+		NodeID: withPlugin.ID,
+		State:  structs.HostVolumeStatePending,
+	}
+	pending.Canonicalize()
+	require.NoError(t, s1.State().UpsertHostVolumes(structs.MsgTypeTestSetup, 200, []*structs.HostVolume{pending}))
+	snap, err = s1.State().Snapshot()
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		node, err := endpoint.placeHostVolume(snap, vol, nil)
+		require.NoError(t, err)
+		require.Equal(t, otherPool.ID, node.ID)
+	}
+
+	vol.NodeID = withPlugin.ID
+	_, err = endpoint.placeHostVolume(snap, vol, nil)
+	require.EqualError(t, err, fmt.Sprintf("node %s already has a host volume named %q", withPlugin.ID, "data"))
+
+	// A node given in the request must be in the node pool of the volume
+	vol.NodeID = otherPool.ID
+	vol.NodePool = structs.NodePoolDefault
+	_, err = endpoint.placeHostVolume(snap, vol, nil)
+	require.EqualError(t, err, fmt.Sprintf("node %s is not in node pool %q", otherPool.ID, "default"))
+
+	// And in a node pool the namespace is allowed to use
+	pools := &structs.NamespaceNodePoolConfiguration{Allowed: []string{}}
+	vol.NodePool = ""
+	_, err = endpoint.placeHostVolume(snap, vol, pools)
+	require.EqualError(t, err, fmt.Sprintf("node pool %q of node %s is not allowed in namespace %q",
+		"other", otherPool.ID, structs.DefaultNamespace))
+
+	vol.NodeID = ""
+	vol.NodePool = "other"
+	_, err = endpoint.placeHostVolume(snap, vol, pools)
+	require.EqualError(t, err, fmt.Sprintf("node pool %q is not allowed in namespace %q",
+		"other", structs.DefaultNamespace))
+
+	vol.NodePool = structs.NodePoolAll
+	_, err = endpoint.placeHostVolume(snap, vol, pools)
+	require.EqualError(t, err, "no node meets constraints")
+
+	// The pools the namespace is allowed to use are enforced on create
+	ns := mock.Namespace()
+	ns.NodePoolConfiguration = &structs.NamespaceNodePoolConfiguration{Denied: []string{"other"}}
+	require.NoError(t, s1.State().UpsertNamespaces(structs.MsgTypeTestSetup, 300, []*structs.Namespace{ns}))
+	nsReq := &structs.HostVolumeCreateRequest{
+		Volume:       &structs.HostVolume{Name: "ns-data", NodeID: otherPool.ID},
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: ns.Name},
+	}
+	var nsResp structs.HostVolumeCreateResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.HostVolumeCreateRPCMethod, nsReq, &nsResp)
+	require.ErrorContains(t, err, fmt.Sprintf("node pool %q of node %s is not allowed", "other", otherPool.ID))
+
+	// A node given in the request must have the plugin
+	req := &structs.HostVolumeCreateRequest{
+		Volume:       &structs.HostVolume{Name: "data", NodeID: noPlugin.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.HostVolumeCreateResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.HostVolumeCreateRPCMethod, req, &resp)
+	require.ErrorContains(t, err, `does not have host volume plugin "mkdir"`)
+
+	req.Volume.NodeID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, structs.HostVolumeCreateRPCMethod, req, &resp)
+	require.ErrorContains(t, err, structs.ErrUnknownNode.Error())
+
+	// Invalid volumes are rejected
+	req.Volume = &structs.HostVolume{Name: "../data"}
+	err = msgpackrpc.CallWithCodec(codec, structs.HostVolumeCreateRPCMethod, req, &resp)
+	require.ErrorContains(t, err, `invalid name "../data"`)
+}
+
+func TestHostVolumeEndpoint_Delete_InUse(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	codec := rpcClient(t, s1)
+	store := s1.fsm.State()
+
+	node := mock.Node()
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+
+	vol := &structs.HostVolume{ID: uuid.Generate(), Name: "data", NodeID: node.ID}
+	vol.Canonicalize()
+	require.NoError(t, store.UpsertHostVolumes(structs.MsgTypeTestSetup, 110, []*structs.HostVolume{vol}))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "data"},
+	}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 120, alloc.Job))
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 130, []*structs.Allocation{alloc}))
+
+	// The state store refuses to delete the claimed volume before the node
+	// is asked to delete it
+	delReq := &structs.HostVolumeDeleteRequest{
+		VolumeIDs:    []string{vol.ID},
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: structs.DefaultNamespace},
+	}
+	var delResp structs.HostVolumeDeleteResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.HostVolumeDeleteRPCMethod, delReq, &delResp)
+	require.EqualError(t, err, fmt.Sprintf("host volume %q is in use by 1 allocation(s)", vol.ID))
+
+	stored, err := store.HostVolumeByID(nil, structs.DefaultNamespace, vol.ID, false)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+}
+
+func TestHostVolumeEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	codec := rpcClient(t, s1)
+	store := s1.fsm.State()
+
+	node := mock.Node()
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, node))
+
+	vol := &structs.HostVolume{ID: uuid.Generate(), Name: "data", NodeID: node.ID}
+	vol.Canonicalize()
+	require.NoError(t, store.UpsertHostVolumes(structs.MsgTypeTestSetup, 110, []*structs.HostVolume{vol}))
+
+	readToken := mock.CreatePolicyAndToken(t, store, 120, "host-volume-read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityHostVolumeRead}))
+	otherToken := mock.CreatePolicyAndToken(t, store, 130, "other",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	getReq := &structs.HostVolumeGetRequest{
+		ID:           vol.ID,
+		QueryOptions: structs.QueryOptions{Region: "global", Namespace: structs.DefaultNamespace},
+	}
+	var getResp structs.HostVolumeGetResponse
+
+	getReq.AuthToken = otherToken.SecretID
+	err := msgpackrpc.CallWithCodec(codec, structs.HostVolumeGetRPCMethod, getReq, &getResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	getReq.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.HostVolumeGetRPCMethod, getReq, &getResp))
+	require.Equal(t, vol.ID, getResp.Volume.ID)
+
+	// Listing all namespaces only returns the volumes that can be read
+	listReq := &structs.HostVolumeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Namespace: structs.AllNamespacesSentinel},
+	}
+	var listResp structs.HostVolumeListResponse
+	listReq.AuthToken = otherToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.HostVolumeListRPCMethod, listReq, &listResp))
+	require.Empty(t, listResp.Volumes)
+
+	listReq.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.HostVolumeListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Volumes, 1)
+
+	// Deleting requires the write capability
+	delReq := &structs.HostVolumeDeleteRequest{
+		VolumeIDs:    []string{vol.ID},
+		WriteRequest: structs.WriteRequest{Region: "global", Namespace: structs.DefaultNamespace},
+	}
+	var delResp structs.HostVolumeDeleteResponse
+	delReq.AuthToken = readToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.HostVolumeDeleteRPCMethod, delReq, &delResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
@@ -65,12 +65,14 @@ func NewJobEndpoints(s *Server) *Job {
 			jobConnectHook{},
 			jobExposeCheckHook{},
 			jobImpliedConstraints{},
+			jobNodePoolMutatingHook{srv: s},
 		},
 		validators: []jobValidator{
 			jobConnectHook{},
 			jobExposeCheckHook{},
 			jobVaultHook{srv: s},
 			jobNamespaceConstraintCheckHook{srv: s},
+			jobNodePoolValidatingHook{srv: s},
 			jobValidate{},
 			&memoryOversubscriptionValidate{srv: s},
 		},
@@ -101,6 +103,20 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	}
 	args.Job = job
 
+	// Drop the job source if it exceeds the configured size limit. The job
+	// itself is registered regardless.
+	if sub := args.Submission; sub != nil {
+		limit := j.srv.config.JobMaxSourceSize
+		switch {
+		case limit <= 0:
+			args.Submission = nil
+		case sub.Size() > limit:
+			warnings = append(warnings, fmt.Errorf(
+				"job source of %d bytes exceeds the job_max_source_size of %d bytes and was not stored", sub.Size(), limit))
+			args.Submission = nil
+		}
+	}
+
 	// Attach the Nomad token's accessor ID so that deploymentwatcher
 	// can reference the token later
 	tokenID, err := j.srv.ResolveSecretToken(args.AuthToken)
@@ -609,6 +625,13 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 		WriteRequest: args.WriteRequest,
 	}
 
+	// Carry the source of the reverted version over to the new version
+	sub, err := snap.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.JobVersion)
+	if err != nil {
+		return err
+	}
+	reg.Submission = sub.Copy()
+
 	// If the request is enforcing the existing version do a check.
 	if args.EnforcePriorVersion != nil {
 		if cur.Version != *args.EnforcePriorVersion {
@@ -669,6 +692,54 @@ func (j *Job) Stable(args *structs.JobStabilityRequest, reply *structs.JobStabil
 	return nil
 }
 
+// EvalPause is used to pause or resume the evaluations of a job. While
+// paused, evaluations of the job are held by the eval broker.
+func (j *Job) EvalPause(args *structs.JobEvalPauseRequest, reply *structs.JobEvalPauseResponse) error {
+	if done, err := j.srv.forward("Job.EvalPause", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "eval_pause"}, time.Now())
+
+	// Check for submit-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for pausing evaluations")
+	}
+
+	// Lookup the job
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	job, err := snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound,
+			"job %q in namespace %q not found", args.JobID, args.RequestNamespace())
+	}
+
+	// Commit this pause request via Raft
+	_, modifyIndex, err := j.srv.raftApply(structs.JobEvalPauseRequestType, args)
+	if err != nil {
+		j.logger.Error("submitting job eval pause request failed", "error", err)
+		return err
+	}
+
+	// Setup the reply
+	reply.Index = modifyIndex
+	return nil
+}
+
 // Evaluate is used to force a job for re-evaluation
 func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegisterResponse) error {
 	if done, err := j.srv.forward("Job.Evaluate", args, args, reply); done {
@@ -1244,6 +1315,49 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// GetJobSubmission is used to retrieve the source a job version was submitted
+// with
+func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest,
+	reply *structs.JobSubmissionResponse) error {
+	if done, err := j.srv.forward("Job.GetJobSubmission", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_submission"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			out, err := store.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.Version)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Submission = out
+
+			// Use the last index that affected the job submission table
+			index, err := store.Index(state.TableJobSubmission)
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // allowedNSes returns a set (as map of ns->true) of the namespaces a token has access to.
 // Returns `nil` set if the token has access to all namespaces
 // and ErrPermissionDenied if the token has no capabilities on any namespace.
@@ -0,0 +1,67 @@
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobNodePoolMutatingHook mutates the job's node pool, setting the namespace
+// default node pool, or the default node pool, if the job does not target
+// one.
+type jobNodePoolMutatingHook struct {
+	srv *Server
+}
+
+func (jobNodePoolMutatingHook) Name() string {
+	return "node-pool-mutation"
+}
+
+func (c jobNodePoolMutatingHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	if job.NodePool != "" {
+		return job, nil, nil
+	}
+
+	job.NodePool = structs.NodePoolDefault
+
+	// The namespace is validated later on, so it may not exist yet.
+	ns, err := c.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ns != nil && ns.NodePoolConfiguration != nil && ns.NodePoolConfiguration.Default != "" {
+		job.NodePool = ns.NodePoolConfiguration.Default
+	}
+
+	return job, nil, nil
+}
+
+// jobNodePoolValidatingHook validates that the job's node pool exists and
+// that the job's namespace is allowed to use it.
+type jobNodePoolValidatingHook struct {
+	srv *Server
+}
+
+func (jobNodePoolValidatingHook) Name() string {
+	return "node-pool-validation"
+}
+
+func (c jobNodePoolValidatingHook) Validate(job *structs.Job) ([]error, error) {
+	pool, err := c.srv.State().NodePoolByName(nil, job.NodePool)
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("job %q is in nonexistent node pool %q", job.ID, job.NodePool)
+	}
+
+	ns, err := c.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns != nil && !ns.NodePoolConfiguration.IsAllowed(job.NodePool) {
+		return nil, fmt.Errorf("used node pool %q is not allowed in namespace %q", job.NodePool, ns.Name)
+	}
+
+	return nil, nil
+}
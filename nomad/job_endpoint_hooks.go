@@ -288,6 +288,13 @@ func (v *memoryOversubscriptionValidate) Validate(job *structs.Job) (warnings []
 		return nil, err
 	}
 
+	// The node pool of the job may override the cluster-wide setting.
+	pool, err := v.srv.State().NodePoolByName(nil, job.NodePool)
+	if err != nil {
+		return nil, err
+	}
+	c = c.WithNodePool(pool)
+
 	if c != nil && c.MemoryOversubscriptionEnabled {
 		return nil, nil
 	}
@@ -1196,6 +1196,94 @@ func TestJobEndpoint_Register_ParameterizedJob(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_NodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create node pools and a namespace that restricts which ones may be used
+	pools := []*structs.NodePool{{Name: "dev"}, {Name: "prod"}, {Name: "gpu"}}
+	require.NoError(t, state.UpsertNodePools(structs.MsgTypeTestSetup, 100, pools))
+
+	ns := mock.Namespace()
+	ns.NodePoolConfiguration = &structs.NamespaceNodePoolConfiguration{
+		Default: "dev",
+		Denied:  []string{"gpu"},
+	}
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 101, []*structs.Namespace{ns}))
+
+	testCases := []struct {
+		name         string
+		namespace    string
+		pool         string
+		expectedPool string
+		expectedErr  string
+	}{
+		{
+			name:         "default node pool",
+			namespace:    structs.DefaultNamespace,
+			expectedPool: structs.NodePoolDefault,
+		},
+		{
+			name:         "namespace default node pool",
+			namespace:    ns.Name,
+			expectedPool: "dev",
+		},
+		{
+			name:         "explicit node pool",
+			namespace:    ns.Name,
+			pool:         "prod",
+			expectedPool: "prod",
+		},
+		{
+			name:        "denied node pool",
+			namespace:   ns.Name,
+			pool:        "gpu",
+			expectedErr: "is not allowed in namespace",
+		},
+		{
+			name:        "nonexistent node pool",
+			namespace:   structs.DefaultNamespace,
+			pool:        "unknown",
+			expectedErr: "nonexistent node pool",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			job.Namespace = tc.namespace
+			job.NodePool = tc.pool
+
+			req := &structs.JobRegisterRequest{
+				Job: job,
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					Namespace: job.Namespace,
+				},
+			}
+			var resp structs.JobRegisterResponse
+			err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			out, err := state.JobByID(nil, job.Namespace, job.ID)
+			require.NoError(t, err)
+			require.NotNil(t, out)
+			require.Equal(t, tc.expectedPool, out.NodePool)
+		})
+	}
+}
+
 func TestJobEndpoint_Register_Dispatched(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -2124,7 +2212,7 @@ func TestJobEndpoint_Register_ValidateMemoryMax(t *testing.T) {
 	require.Contains(t, resp.Warnings, "Memory oversubscription is not enabled")
 
 	// enable now and try again
-	s1.State().SchedulerSetConfig(100, &structs.SchedulerConfiguration{
+	s1.State().SchedulerSetConfig(structs.MsgTypeTestSetup, 100, &structs.SchedulerConfiguration{
 		MemoryOversubscriptionEnabled: true,
 	})
 	resp = submitNewJob()
@@ -2215,7 +2303,7 @@ func TestJobEndpoint_Register_ACL_Namespace(t *testing.T) {
 	// Upsert namespace
 	ns := mock.Namespace()
 	ns.Name = "test"
-	err = s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns})
+	err = s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns})
 	assert.Nil(err)
 
 	// Create the register request
@@ -2919,6 +3007,74 @@ func TestJobEndpoint_Stable_ACL(t *testing.T) {
 	require.Equal(true, out.Stable)
 }
 
+func TestJobEndpoint_EvalPause(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	state := s1.fsm.State()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	pauseReq := &structs.JobEvalPauseRequest{
+		JobID:  job.ID,
+		Paused: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Expect failure for request with an invalid token
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	pauseReq.AuthToken = invalidToken.SecretID
+	var resp structs.JobEvalPauseResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.EvalPause", pauseReq, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Pause with a valid token
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+	pauseReq.AuthToken = validToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.EvalPause", pauseReq, &resp))
+	require.NotZero(resp.Index)
+
+	ws := memdb.NewWatchSet()
+	out, err := state.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.True(out.EvalsPaused)
+
+	// Evals of the job are held by the broker
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	s1.evalBroker.Enqueue(eval)
+	require.Equal(1, s1.evalBroker.Stats().TotalPaused)
+
+	// Resume with a management token
+	pauseReq.Paused = false
+	pauseReq.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.EvalPause", pauseReq, &resp))
+
+	out, err = state.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.False(out.EvalsPaused)
+	stats := s1.evalBroker.Stats()
+	require.Equal(0, stats.TotalPaused)
+	require.Equal(1, stats.TotalReady)
+
+	// Unknown jobs are reported
+	pauseReq.JobID = "unknown"
+	err = msgpackrpc.CallWithCodec(codec, "Job.EvalPause", pauseReq, &resp)
+	require.ErrorContains(err, "not found")
+}
+
 func TestJobEndpoint_Evaluate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4193,6 +4349,9 @@ func TestJobEndpoint_GetJob(t *testing.T) {
 	j.SubmitTime = 0
 	resp2.Job.SubmitTime = 0
 
+	// The job was registered in the default node pool
+	j.NodePool = structs.NodePoolDefault
+
 	if !reflect.DeepEqual(j, resp2.Job) {
 		t.Fatalf("bad: %#v %#v", job, resp2.Job)
 	}
@@ -4485,6 +4644,133 @@ func TestJobEndpoint_GetJobVersions_ACL(t *testing.T) {
 	require.Equal(versions[1].ID, job.ID)
 }
 
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.JobMaxSourceSize = 100
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job along with its source
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:        `job "example" { datacenters = [var.dc] }`,
+		Format:        structs.JobSubmissionFormatHCL2,
+		VariableFlags: map[string]string{"dc": "dc1"},
+	}
+	reg := &structs.JobRegisterRequest{
+		Job:        job,
+		Submission: sub,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	require.Empty(t, resp.Warnings)
+
+	// Register a second version with a source that exceeds the size limit
+	reg.Job = job.Copy()
+	reg.Job.Priority = 100
+	reg.Submission = &structs.JobSubmission{
+		Source: strings.Repeat("#", 101),
+		Format: structs.JobSubmissionFormatHCL2,
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	require.Contains(t, resp.Warnings, "exceeds the job_max_source_size")
+
+	// Lookup the source of both versions
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: 0,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var getResp structs.JobSubmissionResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.NotNil(t, getResp.Submission)
+	require.Equal(t, sub.Source, getResp.Submission.Source)
+	require.Equal(t, sub.VariableFlags, getResp.Submission.VariableFlags)
+	require.Equal(t, uint64(0), getResp.Submission.Version)
+
+	get.Version = 1
+	getResp = structs.JobSubmissionResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.Nil(t, getResp.Submission)
+
+	// Reverting to the first version carries its source over
+	revert := &structs.JobRevertRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Revert", revert, &resp))
+
+	get.Version = 2
+	getResp = structs.JobSubmissionResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.NotNil(t, getResp.Submission)
+	require.Equal(t, sub.Source, getResp.Submission.Source)
+	require.Equal(t, uint64(2), getResp.Submission.Version)
+}
+
+func TestJobEndpoint_GetJobSubmission_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	sub := &structs.JobSubmission{Source: "job", Format: structs.JobSubmissionFormatHCL2}
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 10, sub, job))
+
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: job.Version,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Attempt to fetch without a token should fail
+	var resp structs.JobSubmissionResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Expect failure for request with an invalid token
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	get.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Expect success for request with a valid token
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = validToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(t, resp.Submission)
+
+	// Expect success for request with a management token
+	get.AuthToken = root.SecretID
+	resp = structs.JobSubmissionResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.Equal(t, "job", resp.Submission.Source)
+}
+
 func TestJobEndpoint_GetJobVersions_Diff(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5175,7 +5461,7 @@ func TestJobEndpoint_ListJobs_PaginationFiltering(t *testing.T) {
 	}
 
 	state := s1.fsm.State()
-	require.NoError(t, state.UpsertNamespaces(999, []*structs.Namespace{{Name: "non-default"}, {Name: "other"}}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 999, []*structs.Namespace{{Name: "non-default"}, {Name: "other"}}))
 
 	for i, m := range mocks {
 		if m.name == "" {
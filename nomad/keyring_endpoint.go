@@ -0,0 +1,95 @@
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring encapsulates the root key RPC endpoint, which is used by servers to
+// replicate key material missing from their local keyring, and to publish the
+// public keys which verify workload identities.
+type Keyring struct {
+	srv *Server
+
+	// ctx provides context regarding the underlying connection, so we can
+	// perform TLS certificate validation on internal only endpoints.
+	ctx *RPCContext
+}
+
+// Get returns the root key material held by this server. It is only callable
+// by other servers and is never forwarded, as the key material is not stored
+// in Raft.
+func (k *Keyring) Get(args *structs.KeyringGetRootKeyRequest, reply *structs.KeyringGetRootKeyResponse) error {
+
+	// Ensure the connection was initiated by another server if TLS is used.
+	if err := validateTLSCertificateLevel(k.srv, k.ctx, tlsCertificateLevelServer); err != nil {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "get"}, time.Now())
+
+	if args.KeyID == "" {
+		return structs.NewErrRPCCoded(400, "root key ID is required")
+	}
+
+	// Only return keys which are known to the cluster, so the endpoint cannot
+	// be used to probe the local keystore.
+	keyMeta, err := k.srv.fsm.State().RootKeyMetaByID(nil, args.KeyID)
+	if err != nil {
+		return err
+	}
+	if keyMeta == nil {
+		return nil
+	}
+
+	rootKey, err := k.srv.encrypter.GetKey(args.KeyID)
+	if err != nil {
+		// The key might not have been replicated to this server yet, in
+		// which case the caller should try another.
+		return nil
+	}
+	rootKey.Meta = keyMeta.Copy()
+	reply.Key = rootKey
+	return nil
+}
+
+// ListPublic returns the public keys which verify the workload identities
+// signed by the root keys of the keyring. It requires no authentication, as
+// the keys are published for third parties to verify the identities.
+func (k *Keyring) ListPublic(args *structs.GenericRequest, reply *structs.KeyringListPublicResponse) error {
+	if done, err := k.srv.forward(structs.KeyringListPublicRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	return k.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+			iter, err := stateStore.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			publicKeys := []*structs.KeyringPublicKey{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				keyMeta := raw.(*structs.RootKeyMeta)
+
+				// Skip keys which have not been replicated to this server
+				// yet, as they cannot have been used to sign identities
+				// it would verify.
+				publicKey, err := k.srv.encrypter.GetPublicKey(keyMeta.KeyID)
+				if err != nil {
+					continue
+				}
+				publicKeys = append(publicKeys, publicKey)
+			}
+			reply.PublicKeys = publicKeys
+
+			return k.srv.setReplyQueryMeta(stateStore, state.TableRootKeyMeta, &reply.QueryMeta)
+		},
+	})
+}
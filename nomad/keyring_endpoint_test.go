@@ -0,0 +1,37 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringEndpoint_ListPublic(t *testing.T) {
+	ci.Parallel(t)
+
+	s, _, cleanup := TestACLServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	waitForKeyring(t, s)
+
+	keyMeta, err := s.State().GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+
+	// The public keys should be listed without an ACL token.
+	req := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{Region: DefaultRegion},
+	}
+	var resp structs.KeyringListPublicResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.KeyringListPublicRPCMethod, req, &resp))
+	require.Len(t, resp.PublicKeys, 1)
+	require.Equal(t, keyMeta.KeyID, resp.PublicKeys[0].KeyID)
+	require.Equal(t, structs.PubKeyAlgEdDSA, resp.PublicKeys[0].Algorithm)
+	require.Equal(t, structs.PubKeyUseSig, resp.PublicKeys[0].Use)
+	require.NotEmpty(t, resp.PublicKeys[0].PublicKey)
+	require.NotZero(t, resp.Index)
+}
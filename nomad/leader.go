@@ -48,6 +48,8 @@ var minJobRegisterAtomicEvalVersion = version.Must(version.NewVersion("0.12.1"))
 
 var minOneTimeAuthenticationTokenVersion = version.Must(version.NewVersion("1.1.0"))
 
+var minVariablesVersion = version.Must(version.NewVersion("1.3.2-dev"))
+
 // monitorLeadership is used to monitor if we acquire or lose our role
 // as the leader in the Raft cluster. There is some work the leader is
 // expected to do, so we must react to changes
@@ -290,7 +292,8 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	s.autopilot.Start()
 
 	// Initialize scheduler configuration
-	s.getOrCreateSchedulerConfig()
+	schedConfig := s.getOrCreateSchedulerConfig()
+	pauseEvalBroker := schedConfig != nil && schedConfig.PauseEvalBroker
 
 	// Initialize the ClusterID
 	_, _ = s.ClusterID()
@@ -302,11 +305,13 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Start the plan evaluator
 	go s.planApply()
 
-	// Enable the eval broker, since we are now the leader
-	s.evalBroker.SetEnabled(true)
+	// Enable the eval broker, since we are now the leader, unless it has
+	// been paused by an operator
+	s.evalBroker.SetEnabled(!pauseEvalBroker)
 
-	// Enable the blocked eval tracker, since we are now the leader
-	s.blockedEvals.SetEnabled(true)
+	// Enable the blocked eval tracker, since we are now the leader, unless
+	// the eval broker has been paused
+	s.blockedEvals.SetEnabled(!pauseEvalBroker)
 	s.blockedEvals.SetTimetable(s.fsm.TimeTable())
 
 	// Enable the deployment watcher, since we are now the leader
@@ -318,9 +323,17 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Enable the volume watcher, since we are now the leader
 	s.volumeWatcher.SetEnabled(true, s.State(), s.getLeaderAcl())
 
+	// Enable the maintenance watcher, since we are now the leader
+	s.maintenanceWatcher.SetEnabled(true, s.State(), s.getLeaderAcl())
+
+	// Enable the event sink manager, since we are now the leader
+	s.eventSinkManager.SetEnabled(true, s.State())
+
 	// Restore the eval broker state
-	if err := s.restoreEvals(); err != nil {
-		return err
+	if !pauseEvalBroker {
+		if err := s.restoreEvals(); err != nil {
+			return err
+		}
 	}
 
 	// Activate the vault client
@@ -378,9 +391,16 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	if s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion {
 		go s.replicateACLPolicies(stopCh)
 		go s.replicateACLTokens(stopCh)
+		go s.replicateACLRoles(stopCh)
+		go s.replicateACLAuthMethods(stopCh)
+		go s.replicateACLBindingRules(stopCh)
 		go s.replicateNamespaces(stopCh)
 	}
 
+	// Initialize the keyring used to encrypt variables, if this is the first
+	// leader of the cluster.
+	go s.initializeKeyring(stopCh)
+
 	// Setup any enterprise systems required.
 	if err := s.establishEnterpriseLeadership(stopCh); err != nil {
 		return err
@@ -554,8 +574,28 @@ func diffNamespaces(state *state.StateStore, minIndex uint64, remoteList []*stru
 // eval tracker is maintained only by the leader, so it must be restored anytime
 // a leadership transition takes place.
 func (s *Server) restoreEvals() error {
-	// Get an iterator over every evaluation
+	// Restore the jobs whose evaluations are paused before enqueuing any
+	// evaluation, so their evaluations are held by the broker
 	ws := memdb.NewWatchSet()
+	jobs, err := s.fsm.State().Jobs(ws)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs: %v", err)
+	}
+
+	var pausedJobs []structs.NamespacedID
+	for {
+		raw := jobs.Next()
+		if raw == nil {
+			break
+		}
+		job := raw.(*structs.Job)
+		if job.EvalsPaused {
+			pausedJobs = append(pausedJobs, structs.NewNamespacedID(job.ID, job.Namespace))
+		}
+	}
+	s.evalBroker.SetPausedJobs(pausedJobs)
+
+	// Get an iterator over every evaluation
 	iter, err := s.fsm.State().Evals(ws, false)
 	if err != nil {
 		return fmt.Errorf("failed to get evaluations: %v", err)
@@ -735,6 +775,15 @@ func (s *Server) restorePeriodicDispatcher() error {
 			continue
 		}
 
+		// The missed launch fell within one of the job's exclusions, so it
+		// would have been skipped anyway.
+		if job.Periodic.IsExcluded(nextLaunch) {
+			if err := s.SkipPeriodicLaunch(job, nextLaunch); err != nil {
+				logger.Error("failed to record skipped launch of periodic job", "job", job.NamespacedID(), "error", err)
+			}
+			continue
+		}
+
 		if _, err := s.periodicDispatcher.ForceRun(job.Namespace, job.ID); err != nil {
 			logger.Error("force run of periodic job failed", "job", job.NamespacedID(), "error", err)
 			return fmt.Errorf("force run of periodic job %q failed: %v", job.NamespacedID(), err)
@@ -761,6 +810,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer csiVolumeClaimGC.Stop()
 	oneTimeTokenGC := time.NewTicker(s.config.OneTimeTokenGCInterval)
 	defer oneTimeTokenGC.Stop()
+	expiredACLTokenGC := time.NewTicker(s.config.ACLTokenExpirationGCInterval)
+	defer expiredACLTokenGC.Stop()
 
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
@@ -809,6 +860,20 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobOneTimeTokenGC, index))
 			}
+		case <-expiredACLTokenGC.C:
+			if !s.config.ACLEnabled {
+				continue
+			}
+
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobLocalTokenExpiredGC, index))
+
+				// Global tokens are only garbage collected by the
+				// authoritative region, and replicated to other regions.
+				if s.config.Region == s.config.AuthoritativeRegion {
+					s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobGlobalTokenExpiredGC, index))
+				}
+			}
 		case <-stopCh:
 			return
 		}
@@ -841,7 +906,14 @@ func (s *Server) reapFailedEvaluations(stopCh chan struct{}) {
 			// Scan for a failed evaluation
 			eval, token, err := s.evalBroker.Dequeue([]string{failedQueue}, time.Second)
 			if err != nil {
-				return
+				// The broker is disabled while it is paused, so wait for it
+				// to be resumed or for leadership to be lost.
+				select {
+				case <-stopCh:
+					return
+				case <-time.After(time.Second):
+					continue
+				}
 			}
 			if eval == nil {
 				continue
@@ -1131,6 +1203,12 @@ func (s *Server) revokeLeadership() error {
 	// Disable the volume watcher
 	s.volumeWatcher.SetEnabled(false, nil, "")
 
+	// Disable the maintenance watcher
+	s.maintenanceWatcher.SetEnabled(false, nil, "")
+
+	// Disable the event sink manager
+	s.eventSinkManager.SetEnabled(false, nil)
+
 	// Disable any enterprise systems required.
 	if err := s.revokeEnterpriseLeadership(); err != nil {
 		return err
@@ -1483,6 +1561,426 @@ func diffACLPolicies(state *state.StateStore, minIndex uint64, remoteList []*str
 	return
 }
 
+// replicateACLRoles is used to replicate ACL roles from the authoritative
+// region to this region. ACL roles are always global, so that global tokens
+// linked to them can be resolved in every region.
+func (s *Server) replicateACLRoles(stopCh chan struct{}) {
+	req := structs.ACLRolesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL role replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of roles
+			var resp structs.ACLRolesListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				structs.ACLListRolesRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL roles from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLRoles(s.State(), req.MinQueryIndex, resp.ACLRoles)
+
+			// Delete roles that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLRolesDeleteByIDRequest{
+					ACLRoleIDs: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLRolesDeleteByIDRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete ACL roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated roles
+			var fetched []*structs.ACLRole
+			if len(update) > 0 {
+				req := structs.ACLRolesByIDRequest{
+					ACLRoleIDs: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLRolesByIDResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					structs.ACLGetRolesByIDRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL roles from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, role := range reply.ACLRoles {
+					fetched = append(fetched, role)
+				}
+			}
+
+			// Update local roles. The policies linked to from the roles may
+			// not yet have been replicated, so do not enforce their presence.
+			if len(fetched) > 0 {
+				args := &structs.ACLRolesUpsertRequest{
+					ACLRoles:             fetched,
+					AllowMissingPolicies: true,
+				}
+				_, _, err := s.raftApply(structs.ACLRolesUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update ACL roles", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLRoles is used to perform a two-way diff between the local roles and
+// the remote roles to determine which roles need to be deleted or updated.
+func diffACLRoles(store *state.StateStore, minIndex uint64, remoteList []*structs.ACLRoleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote roles
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local roles
+	iter, err := store.GetACLRoles(nil)
+	if err != nil {
+		panic("failed to iterate local ACL roles")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		role := raw.(*structs.ACLRole)
+		local[role.ID] = role.Hash
+	}
+
+	// Iterate over the remote roles
+	for _, rr := range remoteList {
+		remote[rr.ID] = struct{}{}
+
+		// Check if the role is missing locally
+		if localHash, ok := local[rr.ID]; !ok {
+			update = append(update, rr.ID)
+
+			// Check if the role is newer remotely and there is a hash
+			// mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.ID)
+		}
+	}
+
+	// Check if the role should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
+// replicateACLAuthMethods is used to replicate ACL auth methods from the
+// authoritative region to this region, so that users can log in to every
+// region.
+func (s *Server) replicateACLAuthMethods(stopCh chan struct{}) {
+	req := structs.ACLAuthMethodsListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL auth method replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of auth methods
+			var resp structs.ACLAuthMethodsListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				structs.ACLListAuthMethodsRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL auth methods from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLAuthMethods(s.State(), req.MinQueryIndex, resp.AuthMethods)
+
+			// Delete auth methods that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLAuthMethodsDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodsDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete ACL auth methods", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated auth methods
+			var fetched []*structs.ACLAuthMethod
+			if len(update) > 0 {
+				req := structs.ACLAuthMethodsGetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLAuthMethodsGetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					structs.ACLGetAuthMethodsRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL auth methods from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, authMethod := range reply.AuthMethods {
+					fetched = append(fetched, authMethod)
+				}
+			}
+
+			// Update local auth methods
+			if len(fetched) > 0 {
+				args := &structs.ACLAuthMethodsUpsertRequest{
+					AuthMethods: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLAuthMethodsUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update ACL auth methods", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLAuthMethods is used to perform a two-way diff between the local auth
+// methods and the remote auth methods to determine which auth methods need to
+// be deleted or updated.
+func diffACLAuthMethods(store *state.StateStore, minIndex uint64, remoteList []*structs.ACLAuthMethodStub) (delete []string, update []string) {
+	// Construct a set of the local and remote auth methods
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local auth methods
+	iter, err := store.GetACLAuthMethods(nil)
+	if err != nil {
+		panic("failed to iterate local ACL auth methods")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		authMethod := raw.(*structs.ACLAuthMethod)
+		local[authMethod.Name] = authMethod.Hash
+	}
+
+	// Iterate over the remote auth methods
+	for _, ram := range remoteList {
+		remote[ram.Name] = struct{}{}
+
+		// Check if the auth method is missing locally
+		if localHash, ok := local[ram.Name]; !ok {
+			update = append(update, ram.Name)
+
+			// Check if the auth method is newer remotely and there is a hash
+			// mis-match.
+		} else if ram.ModifyIndex > minIndex && !bytes.Equal(localHash, ram.Hash) {
+			update = append(update, ram.Name)
+		}
+	}
+
+	// Check if the auth method should be deleted
+	for lam := range local {
+		if _, ok := remote[lam]; !ok {
+			delete = append(delete, lam)
+		}
+	}
+	return
+}
+
+// replicateACLBindingRules is used to replicate ACL binding rules from the
+// authoritative region to this region.
+func (s *Server) replicateACLBindingRules(stopCh chan struct{}) {
+	req := structs.ACLBindingRulesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting ACL binding rule replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of binding rules
+			var resp structs.ACLBindingRulesListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				structs.ACLListBindingRulesRPCMethod, &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch ACL binding rules from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLBindingRules(s.State(), req.MinQueryIndex, resp.ACLBindingRules)
+
+			// Delete binding rules that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLBindingRulesDeleteRequest{
+					ACLBindingRuleIDs: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRulesDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete ACL binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated binding rules
+			var fetched []*structs.ACLBindingRule
+			if len(update) > 0 {
+				req := structs.ACLBindingRulesRequest{
+					ACLBindingRuleIDs: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLBindingRulesResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					structs.ACLGetBindingRulesRPCMethod, &req, &reply); err != nil {
+					s.logger.Error("failed to fetch ACL binding rules from authoritative region", "error", err)
+					goto ERR_WAIT
+				}
+				for _, bindingRule := range reply.ACLBindingRules {
+					fetched = append(fetched, bindingRule)
+				}
+			}
+
+			// Update local binding rules. The auth methods the rules belong
+			// to may not yet have been replicated, so do not enforce their
+			// presence.
+			if len(fetched) > 0 {
+				args := &structs.ACLBindingRulesUpsertRequest{
+					ACLBindingRules:         fetched,
+					AllowMissingAuthMethods: true,
+				}
+				_, _, err := s.raftApply(structs.ACLBindingRulesUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update ACL binding rules", "error", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLBindingRules is used to perform a two-way diff between the local
+// binding rules and the remote binding rules to determine which binding rules
+// need to be deleted or updated.
+func diffACLBindingRules(store *state.StateStore, minIndex uint64, remoteList []*structs.ACLBindingRuleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote binding rules
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local binding rules
+	iter, err := store.GetACLBindingRules(nil)
+	if err != nil {
+		panic("failed to iterate local ACL binding rules")
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		bindingRule := raw.(*structs.ACLBindingRule)
+		local[bindingRule.ID] = bindingRule.Hash
+	}
+
+	// Iterate over the remote binding rules
+	for _, rbr := range remoteList {
+		remote[rbr.ID] = struct{}{}
+
+		// Check if the binding rule is missing locally
+		if localHash, ok := local[rbr.ID]; !ok {
+			update = append(update, rbr.ID)
+
+			// Check if the binding rule is newer remotely and there is a hash
+			// mis-match.
+		} else if rbr.ModifyIndex > minIndex && !bytes.Equal(localHash, rbr.Hash) {
+			update = append(update, rbr.ID)
+		}
+	}
+
+	// Check if the binding rule should be deleted
+	for lbr := range local {
+		if _, ok := remote[lbr]; !ok {
+			delete = append(delete, lbr)
+		}
+	}
+	return
+}
+
 // replicateACLTokens is used to replicate global ACL tokens from
 // the authoritative region to this region.
 func (s *Server) replicateACLTokens(stopCh chan struct{}) {
@@ -1652,6 +2150,35 @@ func (s *Server) getOrCreateAutopilotConfig() *structs.AutopilotConfig {
 	return config
 }
 
+// handleEvalBrokerStateChange pauses or resumes the eval broker and blocked
+// eval tracker to match the scheduler configuration. It is a no-op on servers
+// that are not the leader. When resumed, the evaluations are restored from the
+// state store, since they are flushed while paused.
+func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfiguration) error {
+	if !s.IsLeader() {
+		return nil
+	}
+
+	enabled := schedConfig == nil || !schedConfig.PauseEvalBroker
+	if s.evalBroker.Enabled() == enabled {
+		return nil
+	}
+
+	s.evalBroker.SetEnabled(enabled)
+	s.blockedEvals.SetEnabled(enabled)
+	if !enabled {
+		s.logger.Info("eval broker paused")
+		return nil
+	}
+
+	s.blockedEvals.SetTimetable(s.fsm.TimeTable())
+	if err := s.restoreEvals(); err != nil {
+		return fmt.Errorf("failed to restore evaluations: %v", err)
+	}
+	s.logger.Info("eval broker resumed")
+	return nil
+}
+
 // getOrCreateSchedulerConfig is used to get the scheduler config. We create a default
 // config if it doesn't already exist for bootstrapping an empty cluster
 func (s *Server) getOrCreateSchedulerConfig() *structs.SchedulerConfiguration {
@@ -1678,6 +2205,62 @@ func (s *Server) getOrCreateSchedulerConfig() *structs.SchedulerConfiguration {
 	return config
 }
 
+// initializeKeyring creates the first root key, if the keyring has not
+// already been initialized. It retries until all servers meet the minimum
+// version, or leadership is lost.
+func (s *Server) initializeKeyring(stopCh <-chan struct{}) {
+
+	logger := s.logger.Named("keyring")
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		keyMeta, err := s.fsm.State().GetActiveRootKeyMeta(nil)
+		if err != nil {
+			logger.Error("failed to get active key", "error", err)
+			return
+		}
+		if keyMeta != nil {
+			return
+		}
+
+		if ServersMeetMinimumVersion(s.Members(), minVariablesVersion, true) {
+			break
+		}
+		logger.Warn("cannot initialize keyring until all servers are above minimum version",
+			"min_version", minVariablesVersion)
+
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+
+	rootKey, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	if err != nil {
+		logger.Error("could not initialize keyring", "error", err)
+		return
+	}
+
+	// The key material must be in the local keyring before the metadata is
+	// written to Raft, so that the key can be replicated to the other
+	// servers.
+	if err := s.encrypter.AddKey(rootKey); err != nil {
+		logger.Error("could not add initial key to keyring", "error", err)
+		return
+	}
+
+	req := structs.KeyringUpdateRootKeyMetaRequest{RootKeyMeta: rootKey.Meta}
+	if _, _, err := s.raftApply(structs.RootKeyMetaUpsertRequestType, req); err != nil {
+		logger.Error("could not initialize keyring", "error", err)
+		return
+	}
+
+	logger.Info("initialized keyring", "id", rootKey.Meta.KeyID)
+}
+
 func (s *Server) generateClusterID() (string, error) {
 	if !ServersMeetMinimumVersion(s.Members(), minClusterIDVersion, false) {
 		s.logger.Named("core").Warn("cannot initialize cluster ID until all servers are above minimum version", "min_version", minClusterIDVersion)
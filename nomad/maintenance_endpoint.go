@@ -0,0 +1,349 @@
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Maintenance endpoint is used for manipulating node maintenance plans
+type Maintenance struct {
+	srv *Server
+}
+
+// List is used to list the maintenance plans
+func (m *Maintenance) List(args *structs.MaintenancePlanListRequest, reply *structs.MaintenancePlanListResponse) error {
+	if done, err := m.srv.forward(structs.MaintenancePlanListRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Iterate over all the maintenance plans
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = s.MaintenancePlansByIDPrefix(ws, prefix)
+			} else {
+				iter, err = s.MaintenancePlans(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Plans = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.Plans = append(reply.Plans, raw.(*structs.MaintenancePlan).Stub())
+			}
+
+			// Use the last index that affected the maintenance plans table
+			index, err := s.Index(state.TableMaintenancePlans)
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Max(1, index)
+			return nil
+		}}
+	return m.srv.blockingRPC(&opts)
+}
+
+// GetPlan is used to get a specific maintenance plan
+func (m *Maintenance) GetPlan(args *structs.MaintenancePlanSpecificRequest, reply *structs.SingleMaintenancePlanResponse) error {
+	if done, err := m.srv.forward(structs.MaintenancePlanGetPlanRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance", "get_plan"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Look for the maintenance plan
+			out, err := s.MaintenancePlanByID(ws, args.PlanID)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Plan = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the maintenance plans table
+				index, err := s.Index(state.TableMaintenancePlans)
+				if err != nil {
+					return err
+				}
+				reply.Index = helper.Max(1, index)
+			}
+			return nil
+		}}
+	return m.srv.blockingRPC(&opts)
+}
+
+// Register is used to create a maintenance plan. The nodes of the plan are
+// selected when it is registered and the leader starts working through them
+// right away.
+func (m *Maintenance) Register(args *structs.MaintenancePlanRegisterRequest, reply *structs.MaintenancePlanRegisterResponse) error {
+	if done, err := m.srv.forward(structs.MaintenancePlanRegisterRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance", "register"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Plan == nil {
+		return errors.New("missing maintenance plan")
+	}
+
+	plan := args.Plan
+	plan.Canonicalize()
+	if err := plan.Validate(); err != nil {
+		return fmt.Errorf("invalid maintenance plan: %v", err)
+	}
+
+	// Select the nodes of the plan. Nodes that are down can't be worked on.
+	snap, err := m.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return err
+	}
+
+	var nodes []*structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if node.Status == structs.NodeStatusDown {
+			continue
+		}
+		ok, err := plan.Selector.Matches(node)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate node selector: %v", err)
+		}
+		if ok {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return errors.New("no nodes match the maintenance plan selector")
+	}
+
+	now := time.Now()
+	plan.ID = uuid.Generate()
+	plan.Status = structs.MaintenancePlanStatusRunning
+	plan.StatusDescription = "Maintenance plan registered"
+	plan.CreateTime = now.UnixNano()
+	plan.ModifyTime = now.UnixNano()
+	plan.CreateIndex = 0
+	plan.ModifyIndex = 0
+	plan.SetNodes(nodes, now)
+
+	out, index, err := m.srv.raftApply(structs.MaintenancePlanUpsertRequestType,
+		&structs.MaintenancePlanUpsertRequest{
+			Plans:        []*structs.MaintenancePlan{plan},
+			WriteRequest: args.WriteRequest,
+		})
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.PlanID = plan.ID
+	reply.Index = index
+	return nil
+}
+
+// Cancel is used to cancel a running maintenance plan. Nodes that are being
+// drained or waiting for their maintenance are left as they are.
+func (m *Maintenance) Cancel(args *structs.MaintenancePlanCancelRequest, reply *structs.GenericResponse) error {
+	if done, err := m.srv.forward(structs.MaintenancePlanCancelRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance", "cancel"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	return m.updatePlan(args.PlanID, &args.WriteRequest, reply, func(plan *structs.MaintenancePlan) error {
+		plan.Status = structs.MaintenancePlanStatusCancelled
+		plan.StatusDescription = "Maintenance plan cancelled by operator"
+		return nil
+	})
+}
+
+// NodeDone is used to signal that the maintenance of some nodes of a plan is
+// done, so the leader can make them eligible again.
+func (m *Maintenance) NodeDone(args *structs.MaintenancePlanNodeDoneRequest, reply *structs.GenericResponse) error {
+	if done, err := m.srv.forward(structs.MaintenancePlanNodeDoneRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance", "node_done"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.NodeIDs) == 0 {
+		return errors.New("must specify at least one node")
+	}
+
+	return m.updatePlan(args.PlanID, &args.WriteRequest, reply, func(plan *structs.MaintenancePlan) error {
+		for _, nodeID := range args.NodeIDs {
+			mn := plan.LookupNode(nodeID)
+			if mn == nil {
+				return fmt.Errorf("node %q is not part of the maintenance plan", nodeID)
+			}
+			switch mn.Status {
+			case structs.MaintenanceNodeStatusDraining, structs.MaintenanceNodeStatusWaiting:
+				mn.Done = true
+			default:
+				return fmt.Errorf("node %q is %s and can not be marked done", nodeID, mn.Status)
+			}
+		}
+		return nil
+	})
+}
+
+// Delete is used to delete maintenance plans that are no longer running
+func (m *Maintenance) Delete(args *structs.MaintenancePlanDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := m.srv.forward(structs.MaintenancePlanDeleteRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "maintenance", "delete"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := m.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.PlanIDs) == 0 {
+		return errors.New("must specify at least one maintenance plan to delete")
+	}
+
+	// Update via Raft. The state store rejects the deletion of plans that
+	// are still running.
+	out, index, err := m.srv.raftApply(structs.MaintenancePlanDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// maxMaintenancePlanUpdateAttempts is the number of times an operator update
+// of a maintenance plan is retried when the leader updates the plan
+// concurrently.
+const maxMaintenancePlanUpdateAttempts = 3
+
+// updatePlan applies the update to a copy of the running maintenance plan
+// and stores it. The update is retried if the plan was modified concurrently.
+func (m *Maintenance) updatePlan(planID string, w *structs.WriteRequest, reply *structs.GenericResponse,
+	update func(*structs.MaintenancePlan) error) error {
+
+	if planID == "" {
+		return errors.New("missing maintenance plan ID")
+	}
+
+	var err error
+	for attempt := 0; attempt < maxMaintenancePlanUpdateAttempts; attempt++ {
+		err = m.updatePlanImpl(planID, w, reply, update)
+		if !errors.Is(err, structs.ErrMaintenancePlanModified) {
+			return err
+		}
+	}
+	return err
+}
+
+func (m *Maintenance) updatePlanImpl(planID string, w *structs.WriteRequest, reply *structs.GenericResponse,
+	update func(*structs.MaintenancePlan) error) error {
+
+	snap, err := m.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	existing, err := snap.MaintenancePlanByID(nil, planID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("maintenance plan %q not found", planID)
+	}
+	if existing.Terminal() {
+		return fmt.Errorf("maintenance plan %q is %s", planID, existing.Status)
+	}
+
+	plan := existing.Copy()
+	if err := update(plan); err != nil {
+		return err
+	}
+	plan.ModifyTime = time.Now().UnixNano()
+
+	out, index, err := m.srv.raftApply(structs.MaintenancePlanUpsertRequestType,
+		&structs.MaintenancePlanUpsertRequest{
+			Plans:        []*structs.MaintenancePlan{plan},
+			WriteRequest: *w,
+		})
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
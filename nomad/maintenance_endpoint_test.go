@@ -0,0 +1,234 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceEndpoint_Register_NodeDone(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	node1 := mock.Node()
+	node1.NodeClass = "web"
+	node2 := mock.Node()
+	node2.NodeClass = "db"
+	for _, node := range []*structs.Node{node1, node2} {
+		nodeReg := &structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var nodeResp structs.NodeUpdateResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", nodeReg, &nodeResp))
+	}
+
+	// Plans must select at least one node
+	req := &structs.MaintenancePlanRegisterRequest{
+		Plan: &structs.MaintenancePlan{
+			Selector: &structs.MaintenanceSelector{NodeClass: "cache"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.MaintenancePlanRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanRegisterRPCMethod, req, &resp)
+	require.EqualError(t, err, "no nodes match the maintenance plan selector")
+
+	// Invalid plans are rejected
+	req.Plan = &structs.MaintenancePlan{CompleteOn: "never"}
+	err = msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanRegisterRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "invalid maintenance plan")
+
+	req.Plan = &structs.MaintenancePlan{
+		Name:     "web",
+		Selector: &structs.MaintenanceSelector{NodeClass: "web"},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanRegisterRPCMethod, req, &resp))
+	require.NotEmpty(t, resp.PlanID)
+	require.NotZero(t, resp.Index)
+
+	getReq := &structs.MaintenancePlanSpecificRequest{
+		PlanID:       resp.PlanID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleMaintenancePlanResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanGetPlanRPCMethod, getReq, &getResp))
+	require.NotNil(t, getResp.Plan)
+	require.Len(t, getResp.Plan.Nodes, 1)
+	require.Equal(t, node1.ID, getResp.Plan.Nodes[0].NodeID)
+
+	// The leader drains the node, which has no allocations to migrate
+	testutil.WaitForResult(func() (bool, error) {
+		plan, err := state.MaintenancePlanByID(nil, resp.PlanID)
+		if err != nil {
+			return false, err
+		}
+		if status := plan.Nodes[0].Status; status != structs.MaintenanceNodeStatusWaiting {
+			return false, fmt.Errorf("node status %q", status)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("node not drained: %v", err)
+	})
+
+	// Only nodes of the plan can be marked done
+	doneReq := &structs.MaintenancePlanNodeDoneRequest{
+		PlanID:       resp.PlanID,
+		NodeIDs:      []string{node2.ID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var doneResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanNodeDoneRPCMethod, doneReq, &doneResp)
+	require.ErrorContains(t, err, "is not part of the maintenance plan")
+
+	doneReq.NodeIDs = []string{node1.ID}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanNodeDoneRPCMethod, doneReq, &doneResp))
+
+	// The leader makes the node eligible and completes the plan
+	testutil.WaitForResult(func() (bool, error) {
+		plan, err := state.MaintenancePlanByID(nil, resp.PlanID)
+		if err != nil {
+			return false, err
+		}
+		if plan.Status != structs.MaintenancePlanStatusComplete {
+			return false, fmt.Errorf("plan status %q", plan.Status)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("plan not complete: %v", err)
+	})
+
+	out, err := state.NodeByID(nil, node1.ID)
+	require.NoError(t, err)
+	require.Nil(t, out.DrainStrategy)
+	require.Equal(t, structs.NodeSchedulingEligible, out.SchedulingEligibility)
+
+	// Complete plans can't be updated
+	err = msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanNodeDoneRPCMethod, doneReq, &doneResp)
+	require.ErrorContains(t, err, "is complete")
+}
+
+func TestMaintenanceEndpoint_Cancel_Delete(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	req := &structs.MaintenancePlanRegisterRequest{
+		Plan:         &structs.MaintenancePlan{},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.MaintenancePlanRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanRegisterRPCMethod, req, &resp))
+
+	// Running plans can't be deleted
+	deleteReq := &structs.MaintenancePlanDeleteRequest{
+		PlanIDs:      []string{resp.PlanID},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var deleteResp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanDeleteRPCMethod, deleteReq, &deleteResp)
+	require.ErrorContains(t, err, "is running and can not be deleted")
+
+	cancelReq := &structs.MaintenancePlanCancelRequest{
+		PlanID:       resp.PlanID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var cancelResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanCancelRPCMethod, cancelReq, &cancelResp))
+
+	plan, err := state.MaintenancePlanByID(nil, resp.PlanID)
+	require.NoError(t, err)
+	require.Equal(t, structs.MaintenancePlanStatusCancelled, plan.Status)
+
+	listReq := &structs.MaintenancePlanListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.MaintenancePlanListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Plans, 1)
+	require.Equal(t, structs.MaintenancePlanStatusCancelled, listResp.Plans[0].Status)
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanDeleteRPCMethod, deleteReq, &deleteResp))
+	plan, err = state.MaintenancePlanByID(nil, resp.PlanID)
+	require.NoError(t, err)
+	require.Nil(t, plan)
+}
+
+func TestMaintenanceEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, mock.Node()))
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "node-read",
+		mock.NodePolicy(acl.PolicyRead))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	// Registering requires node:write
+	req := &structs.MaintenancePlanRegisterRequest{
+		Plan: &structs.MaintenancePlan{},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var resp structs.MaintenancePlanRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanRegisterRPCMethod, req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanRegisterRPCMethod, req, &resp))
+
+	// Listing requires node:read
+	listReq := &structs.MaintenancePlanListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: invalidToken.SecretID},
+	}
+	var listResp structs.MaintenancePlanListResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanListRPCMethod, listReq, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	listReq.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Plans, 1)
+
+	getReq := &structs.MaintenancePlanSpecificRequest{
+		PlanID:       resp.PlanID,
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: invalidToken.SecretID},
+	}
+	var getResp structs.SingleMaintenancePlanResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanGetPlanRPCMethod, getReq, &getResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Cancelling requires node:write
+	cancelReq := &structs.MaintenancePlanCancelRequest{
+		PlanID: resp.PlanID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var cancelResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.MaintenancePlanCancelRPCMethod, cancelReq, &cancelResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
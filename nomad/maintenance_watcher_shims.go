@@ -0,0 +1,26 @@
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// maintenanceWatcherShim implements the maintenancewatcher.RaftApplier
+// interface required by the maintenance watcher.
+type maintenanceWatcherShim struct {
+	s *Server
+}
+
+func (m maintenanceWatcherShim) UpsertMaintenancePlans(plans []*structs.MaintenancePlan) (uint64, error) {
+	args := &structs.MaintenancePlanUpsertRequest{
+		Plans:        plans,
+		WriteRequest: structs.WriteRequest{Region: m.s.config.Region},
+	}
+	resp, index, err := m.s.raftApply(structs.MaintenancePlanUpsertRequestType, args)
+	if err != nil {
+		return index, err
+	}
+	if fsmErr, ok := resp.(error); ok && fsmErr != nil {
+		return index, fsmErr
+	}
+	return index, nil
+}
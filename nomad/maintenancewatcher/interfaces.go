@@ -0,0 +1,20 @@
+package maintenancewatcher
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodeRPC is a minimal interface of the Node endpoint used to drain nodes
+// and make them eligible again. Going through the endpoint, rather than
+// Raft directly, applies the same validation and node events as operator
+// requests.
+type NodeRPC interface {
+	UpdateDrain(args *structs.NodeUpdateDrainRequest, reply *structs.NodeDrainUpdateResponse) error
+	UpdateEligibility(args *structs.NodeUpdateEligibilityRequest, reply *structs.NodeEligibilityUpdateResponse) error
+}
+
+// RaftApplier contains methods for updating maintenance plans via Raft.
+type RaftApplier interface {
+	// UpsertMaintenancePlans stores the progress of the given plans.
+	UpsertMaintenancePlans(plans []*structs.MaintenancePlan) (uint64, error)
+}
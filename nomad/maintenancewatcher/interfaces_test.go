@@ -0,0 +1,122 @@
+package maintenancewatcher
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRPCServer applies the node updates and plan upserts of the watcher
+// directly to the state store.
+type MockRPCServer struct {
+	state *state.StateStore
+	index uint64
+
+	nextDrainError error
+	countDrain     int
+	countEnable    int
+
+	l sync.Mutex
+}
+
+func newMockRPCServer(t *testing.T) *MockRPCServer {
+	return &MockRPCServer{
+		state: state.TestStateStore(t),
+		index: 100,
+	}
+}
+
+func (srv *MockRPCServer) nextIndex() uint64 {
+	srv.l.Lock()
+	defer srv.l.Unlock()
+	srv.index++
+	return srv.index
+}
+
+func (srv *MockRPCServer) UpdateDrain(args *structs.NodeUpdateDrainRequest, reply *structs.NodeDrainUpdateResponse) error {
+	srv.l.Lock()
+	srv.countDrain++
+	err := srv.nextDrainError
+	srv.l.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return srv.state.UpdateNodeDrain(structs.MsgTypeTestSetup, srv.nextIndex(), args.NodeID,
+		args.DrainStrategy, false, time.Now().Unix(), nil, args.Meta, "")
+}
+
+func (srv *MockRPCServer) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest, reply *structs.NodeEligibilityUpdateResponse) error {
+	srv.l.Lock()
+	srv.countEnable++
+	srv.l.Unlock()
+
+	return srv.state.UpdateNodeEligibility(structs.MsgTypeTestSetup, srv.nextIndex(), args.NodeID,
+		args.Eligibility, time.Now().Unix(), nil)
+}
+
+func (srv *MockRPCServer) UpsertMaintenancePlans(plans []*structs.MaintenancePlan) (uint64, error) {
+	index := srv.nextIndex()
+	return index, srv.state.UpsertMaintenancePlans(structs.MsgTypeTestSetup, index, plans)
+}
+
+func (srv *MockRPCServer) counts() (int, int) {
+	srv.l.Lock()
+	defer srv.l.Unlock()
+	return srv.countDrain, srv.countEnable
+}
+
+// testNodes registers n ready nodes.
+func testNodes(t *testing.T, srv *MockRPCServer, n int) []*structs.Node {
+	nodes := make([]*structs.Node, n)
+	for i := range nodes {
+		node := mock.Node()
+		node.Name = string(rune('a' + i))
+		node.StatusUpdatedAt = time.Now().Unix()
+		node.AgentStartID = uuid.Generate()
+		require.NoError(t, srv.state.UpsertNode(structs.MsgTypeTestSetup, srv.nextIndex(), node))
+		nodes[i] = node
+	}
+	return nodes
+}
+
+// testPlan stores a running plan for the given nodes.
+func testPlan(t *testing.T, srv *MockRPCServer, nodes []*structs.Node,
+	fn func(*structs.MaintenancePlan)) *structs.MaintenancePlan {
+
+	plan := &structs.MaintenancePlan{
+		ID:     uuid.Generate(),
+		Status: structs.MaintenancePlanStatusRunning,
+	}
+	if fn != nil {
+		fn(plan)
+	}
+	plan.Canonicalize()
+	plan.SetNodes(nodes, time.Now())
+
+	_, err := srv.UpsertMaintenancePlans([]*structs.MaintenancePlan{plan})
+	require.NoError(t, err)
+	return plan
+}
+
+// completeDrain completes the drain of the node like the node drainer does.
+func completeDrain(t *testing.T, srv *MockRPCServer, nodeID string, updatedAt int64) {
+	updates := map[string]*structs.DrainUpdate{nodeID: {}}
+	require.NoError(t, srv.state.BatchUpdateNodeDrain(structs.MsgTypeTestSetup, srv.nextIndex(),
+		updatedAt, updates, nil))
+}
+
+// cancelDrain cancels the drain of the node like an operator does.
+func cancelDrain(t *testing.T, srv *MockRPCServer, nodeID string) {
+	require.NoError(t, srv.state.UpdateNodeDrain(structs.MsgTypeTestSetup, srv.nextIndex(), nodeID,
+		nil, false, time.Now().Unix(), nil, nil, ""))
+}
+
+var errMockDrain = errors.New("drain failed")
@@ -0,0 +1,276 @@
+package maintenancewatcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// planResults are the actions required to move a maintenance plan forward.
+type planResults struct {
+	// plan is the updated copy of the plan.
+	plan *structs.MaintenancePlan
+
+	// changed is true if the plan was updated and has to be stored.
+	changed bool
+
+	// drain are the nodes whose drain has to start.
+	drain []*structs.MaintenanceNode
+
+	// enable are the nodes which are done with their maintenance and have to
+	// be made eligible again.
+	enable []*structs.MaintenanceNode
+
+	// wait is the time after which the plan has to be reconciled again,
+	// regardless of state changes. Zero means there is no need to.
+	wait time.Duration
+}
+
+// reconcilePlan compares the maintenance plan with the state of its nodes
+// and returns the actions needed to move it forward.
+func reconcilePlan(snap *state.StateStore, plan *structs.MaintenancePlan, now time.Time) (*planResults, error) {
+	r := &planResults{plan: plan.Copy()}
+
+	// Track the progress of the nodes being worked on
+	for _, mn := range r.plan.Nodes {
+		switch mn.Status {
+		case structs.MaintenanceNodeStatusDraining, structs.MaintenanceNodeStatusWaiting:
+		default:
+			continue
+		}
+
+		node, err := snap.NodeByID(nil, mn.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			mn.SetStatus(structs.MaintenanceNodeStatusFailed, "node was deregistered", now)
+			r.changed = true
+			continue
+		}
+
+		if mn.Status == structs.MaintenanceNodeStatusDraining {
+			switch {
+			case node.DrainStrategy != nil:
+				// Still draining
+			case node.LastDrain != nil && node.LastDrain.Status == structs.DrainStatusCanceled:
+				mn.SetStatus(structs.MaintenanceNodeStatusFailed, "drain was canceled", now)
+				r.changed = true
+			default:
+				mn.DrainedAgentStartID = node.AgentStartID
+				mn.SetStatus(structs.MaintenanceNodeStatusWaiting, waitingDescription(r.plan), now)
+				r.changed = true
+			}
+		}
+
+		if mn.Status == structs.MaintenanceNodeStatusWaiting && maintenanceDone(r.plan, mn, node) {
+			r.enable = append(r.enable, mn)
+		}
+	}
+
+	// Stop as soon as a node failed, leaving the remaining nodes untouched.
+	for _, mn := range r.plan.Nodes {
+		if mn.Status == structs.MaintenanceNodeStatusFailed {
+			return r, nil
+		}
+	}
+
+	// Find the batch being worked on, which is the first with nodes that
+	// haven't completed their maintenance. Nodes being enabled now are
+	// considered complete.
+	batch := -1
+	for _, mn := range r.plan.Nodes {
+		if mn.Status == structs.MaintenanceNodeStatusComplete || r.enabling(mn) {
+			continue
+		}
+		if batch == -1 || mn.Batch < batch {
+			batch = mn.Batch
+		}
+	}
+	if batch == -1 {
+		return r, nil
+	}
+
+	var pending []*structs.MaintenanceNode
+	for _, mn := range r.plan.Nodes {
+		if mn.Batch == batch && mn.Status == structs.MaintenanceNodeStatusPending {
+			pending = append(pending, mn)
+		}
+	}
+	if len(pending) == 0 {
+		return r, nil
+	}
+
+	// Check the gate before starting the batch. When the previous batch
+	// completes with this update, the gate is checked once it is stored.
+	if batch > 0 {
+		if len(r.enable) > 0 {
+			return r, nil
+		}
+
+		wait, desc, err := checkGate(snap, r.plan, batch, now)
+		if err != nil {
+			return nil, err
+		}
+		if desc != "" {
+			r.wait = wait
+			if r.plan.StatusDescription != desc {
+				r.plan.StatusDescription = desc
+				r.changed = true
+			}
+			return r, nil
+		}
+	}
+
+	r.drain = pending
+	r.plan.StatusDescription = fmt.Sprintf("Draining batch %d of %d", batch+1, numBatches(r.plan))
+	r.changed = true
+	return r, nil
+}
+
+// enabling returns true if the node is about to be made eligible again.
+func (r *planResults) enabling(mn *structs.MaintenanceNode) bool {
+	for _, e := range r.enable {
+		if e == mn {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceDone returns true if the maintenance of a drained node is done.
+// Nodes register again whenever their fingerprint changes, so only a node
+// whose agent restarted since it was drained is considered registered again.
+func maintenanceDone(plan *structs.MaintenancePlan, mn *structs.MaintenanceNode, node *structs.Node) bool {
+	if mn.Done {
+		return true
+	}
+	return plan.CompleteOn == structs.MaintenanceCompleteOnReregister &&
+		node.Status == structs.NodeStatusReady &&
+		node.AgentStartID != "" &&
+		node.AgentStartID != mn.DrainedAgentStartID
+}
+
+// waitingDescription describes what a drained node of the plan waits for.
+func waitingDescription(plan *structs.MaintenancePlan) string {
+	if plan.CompleteOn == structs.MaintenanceCompleteOnReregister {
+		return "Drained, waiting for the node to register again"
+	}
+	return "Drained, waiting for the maintenance to be signalled done"
+}
+
+// checkGate checks whether the given batch of the plan may start. If not, it
+// returns a description of what the batch waits for and the time after which
+// the gate has to be checked again, if it depends on time only.
+func checkGate(snap *state.StateStore, plan *structs.MaintenancePlan, batch int, now time.Time) (time.Duration, string, error) {
+	if plan.Gate == nil {
+		return 0, "", nil
+	}
+
+	// Nodes of previous batches are all complete, so the last of them to
+	// complete determines when the previous batch completed.
+	var completed int64
+	for _, mn := range plan.Nodes {
+		if mn.Batch < batch {
+			completed = helper.Max(completed, mn.UpdateTime)
+		}
+	}
+	if plan.Gate.Delay > 0 {
+		start := time.Unix(0, completed).Add(plan.Gate.Delay)
+		if now.Before(start) {
+			return start.Sub(now), fmt.Sprintf("Waiting until %s to start batch %d",
+				start.UTC().Format(time.RFC3339), batch+1), nil
+		}
+	}
+
+	if plan.Gate.HealthyAllocs {
+		for _, mn := range plan.Nodes {
+			if mn.Batch >= batch {
+				continue
+			}
+			healthy, err := migrationsHealthy(snap, mn.NodeID)
+			if err != nil {
+				return 0, "", err
+			}
+			if !healthy {
+				return 0, fmt.Sprintf("Waiting for the allocations migrated off node %s to be healthy",
+					mn.NodeID), nil
+			}
+		}
+	}
+
+	return 0, "", nil
+}
+
+// migrationsHealthy returns true if the service allocations migrated off the
+// node have healthy replacements.
+func migrationsHealthy(snap *state.StateStore, nodeID string) (bool, error) {
+	allocs, err := snap.AllocsByNode(nil, nodeID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, alloc := range allocs {
+		if !alloc.DesiredTransition.ShouldMigrate() || alloc.Job == nil ||
+			alloc.Job.Type != structs.JobTypeService {
+			continue
+		}
+
+		// Allocations of jobs that were stopped since are not replaced
+		job, err := snap.JobByID(nil, alloc.Namespace, alloc.JobID)
+		if err != nil {
+			return false, err
+		}
+		if job == nil || job.Stopped() {
+			continue
+		}
+
+		if alloc.NextAllocation == "" {
+			return false, nil
+		}
+		next, err := snap.AllocByID(nil, alloc.NextAllocation)
+		if err != nil {
+			return false, err
+		}
+		if next != nil && !next.TerminalStatus() && !next.DeploymentStatus.IsHealthy() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// numBatches returns the number of batches of the plan.
+func numBatches(plan *structs.MaintenancePlan) int {
+	n := 0
+	for _, mn := range plan.Nodes {
+		n = helper.Max(n, mn.Batch+1)
+	}
+	return n
+}
+
+// updatePlanStatus sets the status of the plan once all of its nodes are
+// complete or one of them failed. It returns true if the status changed.
+func updatePlanStatus(plan *structs.MaintenancePlan) bool {
+	complete := true
+	for _, mn := range plan.Nodes {
+		switch mn.Status {
+		case structs.MaintenanceNodeStatusFailed:
+			plan.Status = structs.MaintenancePlanStatusFailed
+			plan.StatusDescription = fmt.Sprintf("Maintenance of node %s failed: %s", mn.NodeID, mn.StatusDescription)
+			return true
+		case structs.MaintenanceNodeStatusComplete:
+		default:
+			complete = false
+		}
+	}
+
+	if complete {
+		plan.Status = structs.MaintenancePlanStatusComplete
+		plan.StatusDescription = "All nodes completed their maintenance"
+		return true
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+package maintenancewatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testWatcher returns a watcher whose plans are reconciled by calling step.
+func testWatcher(t *testing.T, srv *MockRPCServer) *Watcher {
+	w := NewMaintenanceWatcher(testlog.HCLogger(t), srv, srv, 100)
+	w.state = srv.state
+	return w
+}
+
+// step reconciles the plan once and returns its stored version.
+func step(t *testing.T, w *Watcher, srv *MockRPCServer, planID string) (*structs.MaintenancePlan, time.Duration) {
+	plan, err := srv.state.MaintenancePlanByID(nil, planID)
+	require.NoError(t, err)
+
+	wait, err := w.reconcilePlan(context.Background(), srv.state, plan)
+	require.NoError(t, err)
+
+	plan, err = srv.state.MaintenancePlanByID(nil, planID)
+	require.NoError(t, err)
+	return plan, wait
+}
+
+// markDone signals that the maintenance of the node is done.
+func markDone(t *testing.T, srv *MockRPCServer, planID, nodeID string) {
+	plan, err := srv.state.MaintenancePlanByID(nil, planID)
+	require.NoError(t, err)
+
+	plan = plan.Copy()
+	plan.LookupNode(nodeID).Done = true
+	_, err = srv.UpsertMaintenancePlans([]*structs.MaintenancePlan{plan})
+	require.NoError(t, err)
+}
+
+func requireNodeStatuses(t *testing.T, plan *structs.MaintenancePlan, nodes []*structs.Node, exp ...string) {
+	t.Helper()
+	for i, node := range nodes {
+		mn := plan.LookupNode(node.ID)
+		require.Equal(t, exp[i], mn.Status, "node %s: %s", node.Name, mn.StatusDescription)
+	}
+}
+
+func TestMaintenanceWatcher_Signal(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := newMockRPCServer(t)
+	w := testWatcher(t, srv)
+	nodes := testNodes(t, srv, 3)
+	plan := testPlan(t, srv, nodes, func(p *structs.MaintenancePlan) {
+		p.Concurrency = 2
+	})
+
+	// The first batch is drained
+	out, _ := step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusDraining,
+		structs.MaintenanceNodeStatusDraining,
+		structs.MaintenanceNodeStatusPending)
+	require.Equal(t, "Draining batch 1 of 2", out.StatusDescription)
+
+	for _, node := range nodes[:2] {
+		n, err := srv.state.NodeByID(nil, node.ID)
+		require.NoError(t, err)
+		require.NotNil(t, n.DrainStrategy)
+		require.Equal(t, plan.ID, n.LastDrain.Meta["maintenance_plan_id"])
+	}
+
+	// Nothing changes while the nodes are draining
+	modifyIndex := out.ModifyIndex
+	out, _ = step(t, w, srv, plan.ID)
+	require.Equal(t, modifyIndex, out.ModifyIndex)
+
+	// Drained nodes wait for their maintenance to be done
+	completeDrain(t, srv, nodes[0].ID, time.Now().Unix())
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusWaiting,
+		structs.MaintenanceNodeStatusDraining,
+		structs.MaintenanceNodeStatusPending)
+
+	// Nodes that are done are made eligible, but the next batch waits for
+	// the whole batch to be complete
+	markDone(t, srv, plan.ID, nodes[0].ID)
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusDraining,
+		structs.MaintenanceNodeStatusPending)
+
+	n, err := srv.state.NodeByID(nil, nodes[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, structs.NodeSchedulingEligible, n.SchedulingEligibility)
+
+	// Nodes can be marked done while they are still draining
+	markDone(t, srv, plan.ID, nodes[1].ID)
+	completeDrain(t, srv, nodes[1].ID, time.Now().Unix())
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusPending)
+
+	// The next batch starts once the previous one is stored as complete
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusDraining)
+	require.Equal(t, "Draining batch 2 of 2", out.StatusDescription)
+
+	completeDrain(t, srv, nodes[2].ID, time.Now().Unix())
+	markDone(t, srv, plan.ID, nodes[2].ID)
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusComplete)
+	require.Equal(t, structs.MaintenancePlanStatusComplete, out.Status)
+
+	drains, enables := srv.counts()
+	require.Equal(t, 3, drains)
+	require.Equal(t, 3, enables)
+}
+
+func TestMaintenanceWatcher_Reregister(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := newMockRPCServer(t)
+	w := testWatcher(t, srv)
+	nodes := testNodes(t, srv, 1)
+	plan := testPlan(t, srv, nodes, func(p *structs.MaintenancePlan) {
+		p.CompleteOn = structs.MaintenanceCompleteOnReregister
+	})
+
+	out, _ := step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes, structs.MaintenanceNodeStatusDraining)
+
+	drainedAt := time.Now().Unix()
+	completeDrain(t, srv, nodes[0].ID, drainedAt)
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes, structs.MaintenanceNodeStatusWaiting)
+	require.Equal(t, nodes[0].AgentStartID, out.Nodes[0].DrainedAgentStartID)
+
+	// Registering an update without restarting isn't registering again
+	node, err := srv.state.NodeByID(nil, nodes[0].ID)
+	require.NoError(t, err)
+	node = node.Copy()
+	node.StatusUpdatedAt = drainedAt + 1
+	node.Attributes["driver.docker"] = "1"
+	require.NoError(t, srv.state.UpsertNode(structs.MsgTypeTestSetup, srv.nextIndex(), node))
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes, structs.MaintenanceNodeStatusWaiting)
+
+	// The node goes down for its maintenance and registers again once its
+	// agent restarted
+	require.NoError(t, srv.state.UpdateNodeStatus(structs.MsgTypeTestSetup, srv.nextIndex(),
+		nodes[0].ID, structs.NodeStatusDown, drainedAt+2, nil))
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes, structs.MaintenanceNodeStatusWaiting)
+
+	node = node.Copy()
+	node.Status = structs.NodeStatusReady
+	node.StatusUpdatedAt = drainedAt + 2
+	node.AgentStartID = uuid.Generate()
+	require.NoError(t, srv.state.UpsertNode(structs.MsgTypeTestSetup, srv.nextIndex(), node))
+	out, _ = step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes, structs.MaintenanceNodeStatusComplete)
+	require.Equal(t, structs.MaintenancePlanStatusComplete, out.Status)
+}
+
+func TestMaintenanceWatcher_Failures(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("drain error", func(t *testing.T) {
+		srv := newMockRPCServer(t)
+		srv.nextDrainError = errMockDrain
+		w := testWatcher(t, srv)
+		nodes := testNodes(t, srv, 2)
+		plan := testPlan(t, srv, nodes, nil)
+
+		out, _ := step(t, w, srv, plan.ID)
+		requireNodeStatuses(t, out, nodes,
+			structs.MaintenanceNodeStatusFailed,
+			structs.MaintenanceNodeStatusPending)
+		require.Equal(t, structs.MaintenancePlanStatusFailed, out.Status)
+		require.Contains(t, out.StatusDescription, errMockDrain.Error())
+	})
+
+	t.Run("drain canceled", func(t *testing.T) {
+		srv := newMockRPCServer(t)
+		w := testWatcher(t, srv)
+		nodes := testNodes(t, srv, 2)
+		plan := testPlan(t, srv, nodes, nil)
+
+		out, _ := step(t, w, srv, plan.ID)
+		requireNodeStatuses(t, out, nodes,
+			structs.MaintenanceNodeStatusDraining,
+			structs.MaintenanceNodeStatusPending)
+
+		cancelDrain(t, srv, nodes[0].ID)
+		out, _ = step(t, w, srv, plan.ID)
+		requireNodeStatuses(t, out, nodes,
+			structs.MaintenanceNodeStatusFailed,
+			structs.MaintenanceNodeStatusPending)
+		require.Equal(t, structs.MaintenancePlanStatusFailed, out.Status)
+		require.Contains(t, out.StatusDescription, "drain was canceled")
+	})
+
+	t.Run("node deregistered", func(t *testing.T) {
+		srv := newMockRPCServer(t)
+		w := testWatcher(t, srv)
+		nodes := testNodes(t, srv, 1)
+		plan := testPlan(t, srv, nodes, nil)
+
+		step(t, w, srv, plan.ID)
+		require.NoError(t, srv.state.DeleteNode(structs.MsgTypeTestSetup, srv.nextIndex(),
+			[]string{nodes[0].ID}))
+		out, _ := step(t, w, srv, plan.ID)
+		requireNodeStatuses(t, out, nodes, structs.MaintenanceNodeStatusFailed)
+		require.Equal(t, structs.MaintenancePlanStatusFailed, out.Status)
+	})
+}
+
+func TestMaintenanceWatcher_GateDelay(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := newMockRPCServer(t)
+	w := testWatcher(t, srv)
+	nodes := testNodes(t, srv, 2)
+	plan := testPlan(t, srv, nodes, func(p *structs.MaintenancePlan) {
+		p.Gate = &structs.MaintenanceGate{Delay: time.Hour}
+	})
+
+	step(t, w, srv, plan.ID)
+	markDone(t, srv, plan.ID, nodes[0].ID)
+	completeDrain(t, srv, nodes[0].ID, time.Now().Unix())
+	out, _ := step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusPending)
+
+	// The second batch waits for the delay
+	out, wait := step(t, w, srv, plan.ID)
+	requireNodeStatuses(t, out, nodes,
+		structs.MaintenanceNodeStatusComplete,
+		structs.MaintenanceNodeStatusPending)
+	require.Contains(t, out.StatusDescription, "Waiting until")
+	require.Greater(t, wait, 59*time.Minute)
+	require.LessOrEqual(t, wait, time.Hour)
+
+	// The gate opens once the delay passed
+	results, err := reconcilePlan(srv.state, out, time.Now().Add(time.Hour+time.Second))
+	require.NoError(t, err)
+	require.Len(t, results.drain, 1)
+	require.Equal(t, nodes[1].ID, results.drain[0].NodeID)
+}
+
+func TestMaintenanceWatcher_GateHealthyAllocs(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := newMockRPCServer(t)
+	nodes := testNodes(t, srv, 2)
+
+	job := mock.Job()
+	require.NoError(t, srv.state.UpsertJob(structs.MsgTypeTestSetup, srv.nextIndex(), job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = nodes[0].ID
+	alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+
+	replacement := mock.Alloc()
+	replacement.Job = job
+	replacement.JobID = job.ID
+	replacement.NodeID = nodes[1].ID
+	replacement.PreviousAllocation = alloc.ID
+	replacement.ClientStatus = structs.AllocClientStatusRunning
+
+	// The migrated allocation has no replacement yet
+	require.NoError(t, srv.state.UpsertAllocs(structs.MsgTypeTestSetup, srv.nextIndex(),
+		[]*structs.Allocation{alloc}))
+	healthy, err := migrationsHealthy(srv.state, nodes[0].ID)
+	require.NoError(t, err)
+	require.False(t, healthy)
+
+	// The replacement isn't healthy yet
+	alloc = alloc.Copy()
+	alloc.NextAllocation = replacement.ID
+	require.NoError(t, srv.state.UpsertAllocs(structs.MsgTypeTestSetup, srv.nextIndex(),
+		[]*structs.Allocation{alloc, replacement}))
+	healthy, err = migrationsHealthy(srv.state, nodes[0].ID)
+	require.NoError(t, err)
+	require.False(t, healthy)
+
+	// The replacement is healthy
+	replacement = replacement.Copy()
+	replacement.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+	require.NoError(t, srv.state.UpsertAllocs(structs.MsgTypeTestSetup, srv.nextIndex(),
+		[]*structs.Allocation{replacement}))
+	healthy, err = migrationsHealthy(srv.state, nodes[0].ID)
+	require.NoError(t, err)
+	require.True(t, healthy)
+}
+
+func TestMaintenanceWatcher_EnableDisable(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := newMockRPCServer(t)
+	w := NewMaintenanceWatcher(testlog.HCLogger(t), srv, srv, 100)
+	w.SetEnabled(true, srv.state, "")
+	defer w.SetEnabled(false, nil, "")
+
+	nodes := testNodes(t, srv, 2)
+	plan := testPlan(t, srv, nodes, nil)
+
+	// The watcher drains the first node and moves on to the second once the
+	// maintenance of the first is done
+	require.Eventually(t, func() bool {
+		out, _ := srv.state.MaintenancePlanByID(nil, plan.ID)
+		return out.Nodes[0].Status == structs.MaintenanceNodeStatusDraining
+	}, 5*time.Second, 10*time.Millisecond)
+
+	markDone(t, srv, plan.ID, nodes[0].ID)
+	completeDrain(t, srv, nodes[0].ID, time.Now().Unix())
+	require.Eventually(t, func() bool {
+		out, _ := srv.state.MaintenancePlanByID(nil, plan.ID)
+		return out.Nodes[1].Status == structs.MaintenanceNodeStatusDraining
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Once disabled, the watcher leaves the plan alone
+	w.SetEnabled(false, nil, "")
+	markDone(t, srv, plan.ID, nodes[1].ID)
+	completeDrain(t, srv, nodes[1].ID, time.Now().Unix())
+	require.Never(t, func() bool {
+		out, _ := srv.state.MaintenancePlanByID(nil, plan.ID)
+		return out.Terminal()
+	}, 300*time.Millisecond, 50*time.Millisecond)
+}
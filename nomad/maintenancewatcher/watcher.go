@@ -0,0 +1,339 @@
+package maintenancewatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// LimitStateQueriesPerSecond is the number of state queries allowed per
+	// second. Maintenance plans watch the nodes and allocations tables, which
+	// change often in busy clusters, while plans progress slowly.
+	LimitStateQueriesPerSecond = 1.0
+
+	// stateReadErrorDelay is the delay to apply before retrying reading state
+	// when there is an error.
+	stateReadErrorDelay = 1 * time.Second
+)
+
+// Watcher works through the running maintenance plans. It drains the nodes
+// of each plan batch by batch, waits for their maintenance to be done and
+// makes them eligible for scheduling again.
+type Watcher struct {
+	enabled bool
+	logger  log.Logger
+
+	// nodes is used to drain nodes and update their eligibility.
+	nodes NodeRPC
+
+	// raft is used to store the progress of the plans.
+	raft RaftApplier
+
+	// the ACL needed to send RPCs
+	leaderAcl string
+
+	// state is the state that is watched for state changes.
+	state *state.StateStore
+
+	// queryLimiter is used to limit the rate of blocking queries
+	queryLimiter *rate.Limiter
+
+	// ctx and exitFn are used to cancel the watcher
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.RWMutex
+}
+
+// NewMaintenanceWatcher returns a maintenance watcher that is used to work
+// through maintenance plans.
+func NewMaintenanceWatcher(logger log.Logger, nodes NodeRPC, raft RaftApplier, stateQueriesPerSecond float64) *Watcher {
+	return &Watcher{
+		logger:       logger.Named("maintenance_watcher"),
+		nodes:        nodes,
+		raft:         raft,
+		queryLimiter: rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 1),
+	}
+}
+
+// SetEnabled is used to control if the watcher is enabled. The watcher
+// should only be enabled on the active leader. When being enabled the state
+// and leader's ACL is passed in as it is no longer valid once a leader
+// election has taken place.
+func (w *Watcher) SetEnabled(enabled bool, state *state.StateStore, leaderAcl string) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	wasEnabled := w.enabled
+	w.enabled = enabled
+	w.leaderAcl = leaderAcl
+
+	if state != nil {
+		w.state = state
+	}
+
+	// Stop the current watch loop
+	if w.exitFn != nil {
+		w.exitFn()
+		w.exitFn = nil
+	}
+
+	// If we are enabled, launch the watch loop
+	if enabled {
+		w.ctx, w.exitFn = context.WithCancel(context.Background())
+		go w.watch(w.ctx, w.state)
+	} else if wasEnabled {
+		w.logger.Trace("maintenance watcher disabled")
+	}
+}
+
+// watch is the long lived go-routine that reconciles the running plans
+// whenever they, their nodes or the allocations change.
+func (w *Watcher) watch(ctx context.Context, store *state.StateStore) {
+	timer, stop := helper.NewSafeTimer(stateReadErrorDelay)
+	defer stop()
+
+	index := uint64(1)
+	var wait time.Duration
+	for {
+		snap, nextIndex, err := w.getPlans(ctx, store, index, wait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			w.logger.Error("error watching maintenance plans", "index", index, "error", err)
+			timer.Reset(stateReadErrorDelay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				continue
+			}
+		}
+
+		// The query doesn't block when the index already moved past, so the
+		// watcher may have been disabled in the meantime
+		if ctx.Err() != nil {
+			return
+		}
+
+		index = nextIndex
+		wait = w.reconcile(ctx, snap)
+	}
+}
+
+// getPlans blocks until the running maintenance plans, their nodes or the
+// allocations change after the given index, or until the wait expires if
+// it is not zero. It returns the state snapshot to reconcile the plans with.
+func (w *Watcher) getPlans(ctx context.Context, store *state.StateStore,
+	minIndex uint64, wait time.Duration) (*state.StateStore, uint64, error) {
+
+	if err := w.queryLimiter.Wait(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	queryCtx, cancel := ctx, context.CancelFunc(func() {})
+	if wait > 0 {
+		queryCtx, cancel = context.WithTimeout(ctx, wait)
+	}
+	defer cancel()
+
+	resp, index, err := store.BlockingQuery(getPlansImpl, minIndex, queryCtx)
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		// A gate may have opened, so reconcile with the current state
+		resp, _, err = store.BlockingQuery(getPlansImpl, 0, ctx)
+		index = minIndex
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return resp.(*state.StateStore), index, nil
+}
+
+// getPlansImpl returns the state snapshot along with the highest index of
+// the tables the running maintenance plans depend on.
+func getPlansImpl(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	iter, err := store.MaintenancePlans(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	running := false
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if !raw.(*structs.MaintenancePlan).Terminal() {
+			running = true
+			break
+		}
+	}
+
+	index, err := store.Index(state.TableMaintenancePlans)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Only watch the nodes and allocations while plans are running
+	if running {
+		if _, err := store.Nodes(ws); err != nil {
+			return nil, 0, err
+		}
+		if _, err := store.Allocs(ws, state.SortDefault); err != nil {
+			return nil, 0, err
+		}
+		for _, table := range []string{"nodes", "allocs"} {
+			tableIndex, err := store.Index(table)
+			if err != nil {
+				return nil, 0, err
+			}
+			index = helper.Max(index, tableIndex)
+		}
+	}
+
+	return store, index, nil
+}
+
+// reconcile moves all the running maintenance plans forward. It returns the
+// time after which the plans have to be reconciled again even if the state
+// did not change, or zero. It stops as soon as the context is cancelled, as
+// the watcher must only act on the leader.
+func (w *Watcher) reconcile(ctx context.Context, snap *state.StateStore) time.Duration {
+	iter, err := snap.MaintenancePlans(nil)
+	if err != nil {
+		w.logger.Error("failed to list maintenance plans", "error", err)
+		return stateReadErrorDelay
+	}
+
+	var wait time.Duration
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		plan := raw.(*structs.MaintenancePlan)
+		if plan.Terminal() {
+			continue
+		}
+		if ctx.Err() != nil {
+			return 0
+		}
+
+		planWait, err := w.reconcilePlan(ctx, snap, plan)
+		if ctx.Err() != nil {
+			return 0
+		}
+		if err != nil {
+			w.logger.Error("failed to update maintenance plan", "plan_id", plan.ID, "error", err)
+			planWait = stateReadErrorDelay
+		}
+		if planWait > 0 && (wait == 0 || planWait < wait) {
+			wait = planWait
+		}
+	}
+	return wait
+}
+
+// reconcilePlan applies the actions needed to move the plan forward and
+// stores its progress. Nothing is applied once the context is cancelled.
+func (w *Watcher) reconcilePlan(ctx context.Context, snap *state.StateStore, plan *structs.MaintenancePlan) (time.Duration, error) {
+	now := time.Now()
+	results, err := reconcilePlan(snap, plan, now)
+	if err != nil {
+		return 0, err
+	}
+	updated := results.plan
+
+	for _, mn := range results.drain {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err := w.drainNode(updated, mn.NodeID); err != nil {
+			mn.SetStatus(structs.MaintenanceNodeStatusFailed, fmt.Sprintf("failed to drain node: %v", err), now)
+			continue
+		}
+		mn.SetStatus(structs.MaintenanceNodeStatusDraining, "Draining", now)
+	}
+
+	for _, mn := range results.enable {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if err := w.enableNode(mn.NodeID); err != nil {
+			mn.SetStatus(structs.MaintenanceNodeStatusFailed, fmt.Sprintf("failed to mark node eligible: %v", err), now)
+			continue
+		}
+		mn.SetStatus(structs.MaintenanceNodeStatusComplete, "Maintenance complete", now)
+	}
+
+	changed := results.changed || len(results.enable) > 0
+	if updatePlanStatus(updated) {
+		changed = true
+	}
+	if !changed {
+		return results.wait, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	updated.ModifyTime = now.UnixNano()
+	if _, err := w.raft.UpsertMaintenancePlans([]*structs.MaintenancePlan{updated}); err != nil {
+		// The plan is reconciled again with its latest version
+		if errors.Is(err, structs.ErrMaintenancePlanModified) {
+			w.logger.Debug("maintenance plan was modified concurrently", "plan_id", plan.ID)
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	w.logger.Debug("updated maintenance plan", "plan_id", plan.ID,
+		"status", updated.Status, "status_description", updated.StatusDescription)
+	return results.wait, nil
+}
+
+// drainNode starts the drain of a node of the plan.
+func (w *Watcher) drainNode(plan *structs.MaintenancePlan, nodeID string) error {
+	w.l.RLock()
+	region, leaderAcl := w.state.Config().Region, w.leaderAcl
+	w.l.RUnlock()
+
+	args := &structs.NodeUpdateDrainRequest{
+		NodeID: nodeID,
+		DrainStrategy: &structs.DrainStrategy{
+			DrainSpec: *plan.DrainSpec.Copy(),
+		},
+		Meta: map[string]string{
+			"message":             "node maintenance",
+			"maintenance_plan_id": plan.ID,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    region,
+			AuthToken: leaderAcl,
+		},
+	}
+	return w.nodes.UpdateDrain(args, &structs.NodeDrainUpdateResponse{})
+}
+
+// enableNode makes a node eligible for scheduling again.
+func (w *Watcher) enableNode(nodeID string) error {
+	w.l.RLock()
+	region, leaderAcl := w.state.Config().Region, w.leaderAcl
+	w.l.RUnlock()
+
+	args := &structs.NodeUpdateEligibilityRequest{
+		NodeID:      nodeID,
+		Eligibility: structs.NodeSchedulingEligible,
+		WriteRequest: structs.WriteRequest{
+			Region:    region,
+			AuthToken: leaderAcl,
+		},
+	}
+	return w.nodes.UpdateEligibility(args, &structs.NodeEligibilityUpdateResponse{})
+}
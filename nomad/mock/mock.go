@@ -113,6 +113,7 @@ func Node() *structs.Node {
 			"version":  "5.6",
 		},
 		NodeClass:             "linux-medium-pci",
+		NodePool:              structs.NodePoolDefault,
 		Status:                structs.NodeStatusReady,
 		SchedulingEligibility: structs.NodeSchedulingEligible,
 	}
@@ -2068,6 +2069,71 @@ func ACLToken() *structs.ACLToken {
 	return tk
 }
 
+// ACLRole returns a valid ACL role, which links to the two policies named
+// "foo" and "bar".
+func ACLRole() *structs.ACLRole {
+	role := structs.ACLRole{
+		ID:          uuid.Generate(),
+		Name:        fmt.Sprintf("acl-role-%s", uuid.Short()),
+		Description: "mocked-test-acl-role",
+		Policies: []*structs.ACLRolePolicyLink{
+			{Name: "foo"},
+			{Name: "bar"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	role.SetHash()
+	return &role
+}
+
+// ACLAuthMethod returns a valid OIDC auth method which issues local tokens.
+func ACLAuthMethod() *structs.ACLAuthMethod {
+	maxTokenTTL, _ := time.ParseDuration("3600s")
+	method := structs.ACLAuthMethod{
+		Name:          fmt.Sprintf("acl-auth-method-%s", uuid.Short()),
+		Type:          structs.ACLAuthMethodTypeOIDC,
+		TokenLocality: structs.ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   maxTokenTTL,
+		Default:       false,
+		Config: &structs.ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			OIDCClientSecret:    "very secret secret",
+			BoundAudiences:      []string{"audience1", "audience2"},
+			AllowedRedirectURIs: []string{"foo", "bar"},
+			DiscoveryCaPem:      []string{"foo"},
+			SigningAlgs:         []string{"RS256"},
+			ClaimMappings:       map[string]string{"foo": "bar"},
+			ListClaimMappings:   map[string]string{"foo": "bar"},
+		},
+		CreateTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	method.SetHash()
+	return &method
+}
+
+// ACLBindingRule returns a valid binding rule, which links logins of the
+// "auth0" auth method to the "engineering" role.
+func ACLBindingRule() *structs.ACLBindingRule {
+	bindingRule := structs.ACLBindingRule{
+		ID:          uuid.Generate(),
+		Description: "mocked-acl-binding-rule",
+		AuthMethod:  "auth0",
+		Selector:    `"engineering" in list.roles`,
+		BindType:    structs.ACLBindingRuleBindTypeRole,
+		BindName:    "engineering",
+		CreateTime:  time.Now().UTC(),
+		ModifyTime:  time.Now().UTC(),
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	bindingRule.SetHash()
+	return &bindingRule
+}
+
 func ACLManagementToken() *structs.ACLToken {
 	return &structs.ACLToken{
 		AccessorID:  uuid.Generate(),
@@ -2241,6 +2307,17 @@ func Events(index uint64) *structs.Events {
 	}
 }
 
+// EventSink returns a webhook event sink subscribed to all the events.
+func EventSink() *structs.EventSink {
+	sink := &structs.EventSink{
+		ID:      fmt.Sprintf("webhook-%s", uuid.Generate()[:8]),
+		Type:    structs.SinkWebhook,
+		Address: "http://127.0.0.1:8080/events",
+	}
+	sink.Canonicalize()
+	return sink
+}
+
 func AllocNetworkStatus() *structs.AllocNetworkStatus {
 	return &structs.AllocNetworkStatus{
 		InterfaceName: "eth0",
@@ -126,6 +126,18 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 		args.Node.SchedulingEligibility = structs.NodeSchedulingEligible
 	}
 
+	// Default to the default node pool if unset. Nodes are not allowed to
+	// register into the built-in "all" node pool.
+	if args.Node.NodePool == "" {
+		args.Node.NodePool = structs.NodePoolDefault
+	}
+	if args.Node.NodePool == structs.NodePoolAll {
+		return fmt.Errorf("node is not allowed to register in node pool %q", structs.NodePoolAll)
+	}
+	if !structs.ValidNodePoolName(args.Node.NodePool) {
+		return fmt.Errorf("invalid node pool %q", args.Node.NodePool)
+	}
+
 	// Set the timestamp when the node is registered
 	args.Node.StatusUpdatedAt = time.Now().Unix()
 
@@ -601,6 +613,11 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	if args.NodeEvent != nil {
 		return fmt.Errorf("node event must not be set")
 	}
+	if args.DrainStrategy != nil {
+		if err := args.DrainStrategy.DrainSpec.Validate(); err != nil {
+			return err
+		}
+	}
 
 	// Look for the node
 	snap, err := n.srv.fsm.State().Snapshot()
@@ -629,9 +646,19 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 			args.DrainStrategy.StartedAt = node.DrainStrategy.StartedAt
 		}
 
+		// Wait for the next drain window, if any, before migrating
+		start := now
+		if window := args.DrainStrategy.Window; window != nil {
+			start, err = window.Next(now)
+			if err != nil {
+				return fmt.Errorf("invalid drain window: %v", err)
+			}
+			args.DrainStrategy.WindowStart = start
+		}
+
 		// Mark the deadline time
 		if args.DrainStrategy.Deadline.Nanoseconds() > 0 {
-			args.DrainStrategy.ForceDeadline = now.Add(args.DrainStrategy.Deadline)
+			args.DrainStrategy.ForceDeadline = start.Add(args.DrainStrategy.Deadline)
 		}
 	}
 
@@ -647,6 +674,12 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 		args.NodeEvent = nil
 	}
 
+	// The leader's ACL isn't stored in state, so drains started by the leader
+	// itself, such as for node maintenance plans, have no accessor to record.
+	if leaderAcl := n.srv.getLeaderAcl(); leaderAcl != "" && args.AuthToken == leaderAcl {
+		args.AuthToken = ""
+	}
+
 	// Commit this update via Raft
 	_, index, err := n.srv.raftApply(structs.NodeUpdateDrainRequestType, args)
 	if err != nil {
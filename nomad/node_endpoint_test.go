@@ -86,6 +86,71 @@ func TestClientEndpoint_Register(t *testing.T) {
 	})
 }
 
+func TestClientEndpoint_Register_NodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	testCases := []struct {
+		name         string
+		pool         string
+		expectedPool string
+		expectedErr  string
+	}{
+		{
+			name:         "empty node pool",
+			pool:         "",
+			expectedPool: structs.NodePoolDefault,
+		},
+		{
+			name:         "new node pool",
+			pool:         "dev",
+			expectedPool: "dev",
+		},
+		{
+			name:        "all node pool",
+			pool:        structs.NodePoolAll,
+			expectedErr: "not allowed to register",
+		},
+		{
+			name:        "invalid node pool",
+			pool:        "not@valid",
+			expectedErr: "invalid node pool",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node := mock.Node()
+			node.NodePool = tc.pool
+			req := &structs.NodeRegisterRequest{
+				Node:         node,
+				WriteRequest: structs.WriteRequest{Region: "global"},
+			}
+
+			var resp structs.GenericResponse
+			err := msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			out, err := s1.fsm.State().NodeByID(nil, node.ID)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPool, out.NodePool)
+
+			// The node pool is created when a node registers in it
+			pool, err := s1.fsm.State().NodePoolByName(nil, tc.expectedPool)
+			require.NoError(t, err)
+			require.NotNil(t, pool)
+		})
+	}
+}
+
 // This test asserts that we only track node connections if they are not from
 // forwarded RPCs. This is essential otherwise we will think a Yamux session to
 // a Nomad server is actually the session to the node.
@@ -1222,6 +1287,60 @@ func TestClientEndpoint_UpdatedDrainNoop(t *testing.T) {
 // TestClientEndpoint_UpdateDrain_ACL asserts that Node.UpdateDrain() enforces
 // node.write ACLs, and that token accessor ID is properly persisted in
 // Node.LastDrain.AccessorID
+func TestClientEndpoint_UpdateDrain_Window(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Disable drainer to prevent drain from completing during test
+	s1.nodeDrainer.SetEnabled(false, nil)
+
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	// An invalid window is rejected
+	req := &structs.NodeUpdateDrainRequest{
+		NodeID: node.ID,
+		DrainStrategy: &structs.DrainStrategy{
+			DrainSpec: structs.DrainSpec{
+				Deadline: time.Hour,
+				Window:   &structs.DrainWindow{Cron: "invalid", Duration: time.Hour},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.NodeDrainUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.UpdateDrain", req, &resp2)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid drain window")
+
+	// The drain waits for the next window and the deadline counts from its
+	// start
+	start := time.Now().UTC().Add(2 * time.Hour).Truncate(time.Minute)
+	req.DrainStrategy.Window = &structs.DrainWindow{
+		Cron:     fmt.Sprintf("%d %d * * *", start.Minute(), start.Hour()),
+		Duration: 30 * time.Minute,
+	}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateDrain", req, &resp2))
+
+	out, err := s1.fsm.State().NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.NotNil(out.DrainStrategy)
+	require.True(start.Equal(out.DrainStrategy.WindowStart))
+	require.True(start.Add(time.Hour).Equal(out.DrainStrategy.ForceDeadline))
+	require.False(out.DrainStrategy.WindowOpen(time.Now()))
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+}
+
 func TestClientEndpoint_UpdateDrain_ACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1934,7 +2053,7 @@ func TestClientEndpoint_GetAllocs_ACL_Namespaces(t *testing.T) {
 	allocAltNS.NodeID = node.ID
 	allocOtherNS.NodeID = node.ID
 	state := s1.fsm.State()
-	assert.Nil(state.UpsertNamespaces(1, []*structs.Namespace{ns1, ns2}), "UpsertNamespaces")
+	assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 1, []*structs.Namespace{ns1, ns2}), "UpsertNamespaces")
 	assert.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 2, node), "UpsertNode")
 	assert.Nil(state.UpsertJobSummary(3, mock.JobSummary(allocDefaultNS.JobID)), "UpsertJobSummary")
 	assert.Nil(state.UpsertJobSummary(4, mock.JobSummary(allocAltNS.JobID)), "UpsertJobSummary")
@@ -2767,7 +2886,7 @@ func TestClientEndpoint_CreateNodeEvals_MultipleNSes(t *testing.T) {
 
 	idx := uint64(3)
 	ns1 := mock.Namespace()
-	err := state.UpsertNamespaces(idx, []*structs.Namespace{ns1})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, idx, []*structs.Namespace{ns1})
 	require.NoError(t, err)
 	idx++
 
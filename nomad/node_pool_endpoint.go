@@ -0,0 +1,196 @@
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodePool endpoint is used for manipulating node pools
+type NodePool struct {
+	srv *Server
+}
+
+// List is used to list the node pools
+func (n *NodePool) List(args *structs.NodePoolListRequest, reply *structs.NodePoolListResponse) error {
+	if done, err := n.srv.forward(structs.NodePoolListRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "list"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Iterate over all the node pools
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = s.NodePoolsByNamePrefix(ws, prefix)
+			} else {
+				iter, err = s.NodePools(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.NodePools = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.NodePools = append(reply.NodePools, raw.(*structs.NodePool))
+			}
+
+			// Use the last index that affected the node pools table
+			index, err := s.Index(state.TableNodePools)
+			if err != nil {
+				return err
+			}
+			reply.Index = helper.Max(1, index)
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// GetNodePool is used to get a specific node pool
+func (n *NodePool) GetNodePool(args *structs.NodePoolSpecificRequest, reply *structs.SingleNodePoolResponse) error {
+	if done, err := n.srv.forward(structs.NodePoolGetNodePoolRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "get_node_pool"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Look for the node pool
+			out, err := s.NodePoolByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.NodePool = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the node pools table
+				index, err := s.Index(state.TableNodePools)
+				if err != nil {
+					return err
+				}
+				reply.Index = helper.Max(1, index)
+			}
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
+// UpsertNodePools is used to create or update a set of node pools
+func (n *NodePool) UpsertNodePools(args *structs.NodePoolUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := n.srv.forward(structs.NodePoolUpsertNodePoolsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "upsert_node_pools"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate there is at least one node pool
+	if len(args.NodePools) == 0 {
+		return errors.New("must specify at least one node pool")
+	}
+
+	// Validate the node pools and set the hash
+	for _, pool := range args.NodePools {
+		if err := pool.Validate(); err != nil {
+			return fmt.Errorf("invalid node pool %q: %v", pool.Name, err)
+		}
+		if pool.IsBuiltIn() {
+			return fmt.Errorf("modifying node pool %q is not allowed", pool.Name)
+		}
+
+		pool.SetHash()
+	}
+
+	// Update via Raft
+	out, index, err := n.srv.raftApply(structs.NodePoolUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteNodePools is used to delete a set of node pools
+func (n *NodePool) DeleteNodePools(args *structs.NodePoolDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := n.srv.forward(structs.NodePoolDeleteNodePoolsRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "node_pool", "delete_node_pools"}, time.Now())
+
+	// Check management permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate at least one node pool
+	if len(args.Names) == 0 {
+		return errors.New("must specify at least one node pool to delete")
+	}
+
+	for _, name := range args.Names {
+		if name == structs.NodePoolAll || name == structs.NodePoolDefault {
+			return fmt.Errorf("deleting node pool %q is not allowed", name)
+		}
+	}
+
+	// Update via Raft. The state store rejects the deletion of node pools
+	// that still have nodes or non-terminal jobs.
+	out, index, err := n.srv.raftApply(structs.NodePoolDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
@@ -0,0 +1,186 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolEndpoint_UpsertNodePools(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	req := &structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{
+			{Name: "dev", Description: "development"},
+			{
+				Name: "prod",
+				SchedulerConfiguration: &structs.NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+				},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolUpsertNodePoolsRPCMethod, req, &resp))
+	require.NotZero(t, resp.Index)
+
+	// Check we created the node pools
+	for _, name := range []string{"dev", "prod"} {
+		out, err := s1.fsm.State().NodePoolByName(nil, name)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		require.NotEmpty(t, out.Hash)
+	}
+
+	// Built-in node pools can't be modified
+	req.NodePools = []*structs.NodePool{{Name: structs.NodePoolDefault, Description: "modified"}}
+	err := msgpackrpc.CallWithCodec(codec, structs.NodePoolUpsertNodePoolsRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "not allowed")
+
+	// Invalid node pools are rejected
+	req.NodePools = []*structs.NodePool{{Name: "not@valid"}}
+	err = msgpackrpc.CallWithCodec(codec, structs.NodePoolUpsertNodePoolsRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "invalid node pool")
+}
+
+func TestNodePoolEndpoint_List_Get(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pools := []*structs.NodePool{{Name: "dev-1"}, {Name: "dev-2"}, {Name: "prod"}}
+	require.NoError(t, s1.fsm.State().UpsertNodePools(structs.MsgTypeTestSetup, 1000, pools))
+
+	// List all node pools, including the built-in ones
+	listReq := &structs.NodePoolListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.NodePoolListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.NodePools, 5)
+	require.EqualValues(t, 1000, listResp.Index)
+
+	// List by prefix
+	listReq.Prefix = "dev"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.NodePools, 2)
+
+	// Get a single node pool
+	getReq := &structs.NodePoolSpecificRequest{
+		Name:         "prod",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleNodePoolResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolGetNodePoolRPCMethod, getReq, &getResp))
+	require.NotNil(t, getResp.NodePool)
+	require.Equal(t, "prod", getResp.NodePool.Name)
+
+	// Get a missing node pool
+	getReq.Name = "unknown"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolGetNodePoolRPCMethod, getReq, &getResp))
+	require.Nil(t, getResp.NodePool)
+}
+
+func TestNodePoolEndpoint_DeleteNodePools(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pools := []*structs.NodePool{{Name: "dev"}, {Name: "prod"}}
+	require.NoError(t, s1.fsm.State().UpsertNodePools(structs.MsgTypeTestSetup, 1000, pools))
+
+	node := mock.Node()
+	node.NodePool = "prod"
+	require.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+
+	req := &structs.NodePoolDeleteRequest{
+		Names:        []string{"dev"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolDeleteNodePoolsRPCMethod, req, &resp))
+	require.NotZero(t, resp.Index)
+
+	out, err := s1.fsm.State().NodePoolByName(nil, "dev")
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Node pools with nodes can't be deleted
+	req.Names = []string{"prod"}
+	err = msgpackrpc.CallWithCodec(codec, structs.NodePoolDeleteNodePoolsRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "has at least one node")
+
+	// Built-in node pools can't be deleted
+	req.Names = []string{structs.NodePoolAll}
+	err = msgpackrpc.CallWithCodec(codec, structs.NodePoolDeleteNodePoolsRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "not allowed")
+}
+
+func TestNodePoolEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "node-read",
+		mock.NodePolicy(acl.PolicyRead))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	// Listing requires node:read
+	listReq := &structs.NodePoolListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: invalidToken.SecretID},
+	}
+	var listResp structs.NodePoolListResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.NodePoolListRPCMethod, listReq, &listResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	listReq.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.NodePools, 2)
+
+	// Writing requires a management token
+	upsertReq := &structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{{Name: "dev"}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.NodePoolUpsertNodePoolsRPCMethod, upsertReq, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	upsertReq.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolUpsertNodePoolsRPCMethod, upsertReq, &resp))
+
+	deleteReq := &structs.NodePoolDeleteRequest{
+		Names: []string{"dev"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, structs.NodePoolDeleteNodePoolsRPCMethod, deleteReq, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	deleteReq.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.NodePoolDeleteNodePoolsRPCMethod, deleteReq, &resp))
+}
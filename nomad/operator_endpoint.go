@@ -335,6 +335,18 @@ func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRe
 		reply.Updated = respBool
 	}
 	reply.Index = index
+
+	// Pause or resume the eval broker to match the applied configuration
+	if reply.Updated {
+		_, config, err := op.srv.fsm.State().SchedulerConfig()
+		if err != nil {
+			return err
+		}
+		if err := op.srv.handleEvalBrokerStateChange(config); err != nil {
+			op.logger.Error("failed to update eval broker state", "error", err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -41,6 +41,10 @@ type JobEvalDispatcher interface {
 
 	// RunningChildren returns whether the passed job has any running children.
 	RunningChildren(job *structs.Job) (bool, error)
+
+	// SkipPeriodicLaunch records that the launch of the passed periodic job
+	// at the given time was skipped.
+	SkipPeriodicLaunch(job *structs.Job, launchTime time.Time) error
 }
 
 // DispatchJob creates an evaluation for the passed job and commits both the
@@ -103,6 +107,23 @@ func (s *Server) DispatchJob(job *structs.Job) (*structs.Evaluation, error) {
 	return eval, nil
 }
 
+// SkipPeriodicLaunch commits the skipped launch of a periodic job to the raft
+// log so it is accounted for in the job summary.
+func (s *Server) SkipPeriodicLaunch(job *structs.Job, launchTime time.Time) error {
+	req := structs.PeriodicLaunchSkipRequest{
+		JobID:  job.ID,
+		Launch: launchTime,
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	fsmErr, _, err := s.raftApply(structs.PeriodicLaunchSkipRequestType, req)
+	if err, ok := fsmErr.(error); ok && err != nil {
+		return err
+	}
+	return err
+}
+
 // RunningChildren checks whether the passed job has any running children.
 func (s *Server) RunningChildren(job *structs.Job) (bool, error) {
 	state, err := s.fsm.State().Snapshot()
@@ -367,6 +388,18 @@ func (p *PeriodicDispatch) dispatch(job *structs.Job, launchTime time.Time) {
 		p.logger.Error("failed to update next launch of periodic job", "job", job.NamespacedID(), "error", err)
 	}
 
+	// If the launch falls within one of the job's exclusions, we skip the
+	// launch and record it.
+	if job.Periodic.IsExcluded(launchTime) {
+		p.logger.Debug("skipping launch of periodic job because launch time is excluded",
+			"job", job.NamespacedID(), "launch_time", launchTime)
+		p.l.Unlock()
+		if err := p.dispatcher.SkipPeriodicLaunch(job, launchTime); err != nil {
+			p.logger.Error("failed to record skipped launch of periodic job", "job", job.NamespacedID(), "error", err)
+		}
+		return
+	}
+
 	// If the job prohibits overlapping and there are running children, we skip
 	// the launch.
 	if job.Periodic.ProhibitOverlap {
@@ -21,8 +21,9 @@ import (
 )
 
 type MockJobEvalDispatcher struct {
-	Jobs map[structs.NamespacedID]*structs.Job
-	lock sync.Mutex
+	Jobs    map[structs.NamespacedID]*structs.Job
+	Skipped []time.Time
+	lock    sync.Mutex
 }
 
 func NewMockJobEvalDispatcher() *MockJobEvalDispatcher {
@@ -51,6 +52,13 @@ func (m *MockJobEvalDispatcher) RunningChildren(parent *structs.Job) (bool, erro
 	return false, nil
 }
 
+func (m *MockJobEvalDispatcher) SkipPeriodicLaunch(job *structs.Job, launchTime time.Time) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.Skipped = append(m.Skipped, launchTime)
+	return nil
+}
+
 // LaunchTimes returns the launch times of child jobs in sorted order.
 func (m *MockJobEvalDispatcher) LaunchTimes(p *PeriodicDispatch, namespace, parentID string) ([]time.Time, error) {
 	m.lock.Lock()
@@ -456,6 +464,56 @@ func TestPeriodicDispatch_Run_Multiple(t *testing.T) {
 	}
 }
 
+func TestPeriodicDispatch_Run_MultipleSpecs(t *testing.T) {
+	ci.Parallel(t)
+	p, m := testPeriodicDispatcher(t)
+
+	// Create a job whose launches come from two separate specs.
+	launch1 := time.Now().Round(1 * time.Second).Add(1 * time.Second)
+	launch2 := time.Now().Round(1 * time.Second).Add(2 * time.Second)
+	job := testPeriodicJob()
+	job.Periodic.Spec = ""
+	job.Periodic.Specs = []string{
+		strconv.Itoa(int(launch2.Unix())),
+		strconv.Itoa(int(launch1.Unix())),
+	}
+
+	require.NoError(t, p.Add(job))
+
+	time.Sleep(3 * time.Second)
+
+	// Check that the job was launched for both specs in order.
+	times, err := m.LaunchTimes(p, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{launch1, launch2}, times)
+}
+
+func TestPeriodicDispatch_Run_Excluded(t *testing.T) {
+	ci.Parallel(t)
+	p, m := testPeriodicDispatcher(t)
+
+	// Create a job whose launches all fall on an excluded date.
+	launch1 := time.Now().Round(1 * time.Second).Add(1 * time.Second)
+	launch2 := time.Now().Round(1 * time.Second).Add(2 * time.Second)
+	job := testPeriodicJob(launch1, launch2)
+	loc := job.Periodic.GetLocation()
+	job.Periodic.ExcludeDates = []string{
+		launch1.In(loc).Format(structs.PeriodicExcludeDateFormat),
+		launch2.In(loc).Format(structs.PeriodicExcludeDateFormat),
+	}
+
+	require.NoError(t, p.Add(job))
+
+	time.Sleep(3 * time.Second)
+
+	// Check that nothing was launched and both launches were skipped.
+	require.Empty(t, m.dispatchedJobs(job))
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	require.Equal(t, []time.Time{launch1, launch2}, m.Skipped)
+}
+
 func TestPeriodicDispatch_Run_SameTime(t *testing.T) {
 	ci.Parallel(t)
 	p, m := testPeriodicDispatcher(t)
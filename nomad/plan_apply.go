@@ -242,6 +242,10 @@ func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 		// to approximate the scheduling time.
 		updateAllocTimestamps(req.AllocsUpdated, now)
 
+		if err := p.signAllocIdentities(plan.Job, req.AllocsUpdated); err != nil {
+			return nil, err
+		}
+
 		for _, preemptions := range result.NodePreemptions {
 			for _, preemptedAlloc := range preemptions {
 				req.AllocsPreempted = append(req.AllocsPreempted, normalizePreemptedAlloc(preemptedAlloc, now))
@@ -367,6 +371,43 @@ func updateAllocTimestamps(allocations []*structs.Allocation, timestamp int64) {
 	}
 }
 
+// signAllocIdentities signs a workload identity for each task of the
+// allocations. Signing is skipped if the keyring has not been initialized,
+// which is the case until all servers are able to verify the identities.
+func (p *planner) signAllocIdentities(job *structs.Job, allocations []*structs.Allocation) error {
+	if job == nil || len(allocations) == 0 {
+		return nil
+	}
+
+	keyMeta, err := p.State().GetActiveRootKeyMeta(nil)
+	if err != nil {
+		return err
+	}
+	if keyMeta == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, alloc := range allocations {
+		tg := job.LookupTaskGroup(alloc.TaskGroup)
+		if tg == nil {
+			continue
+		}
+
+		alloc.SignedIdentities = make(map[string]string, len(tg.Tasks))
+		for _, task := range tg.Tasks {
+			claims := structs.NewIdentityClaims(job, alloc, task.Name, now)
+			token, keyID, err := p.encrypter.SignClaims(claims)
+			if err != nil {
+				return fmt.Errorf("failed to sign identity for task %q: %v", task.Name, err)
+			}
+			alloc.SignedIdentities[task.Name] = token
+			alloc.SigningKeyID = keyID
+		}
+	}
+	return nil
+}
+
 // asyncPlanWait is used to apply and respond to a plan async. On successful
 // commit the plan's index will be sent on the chan. On error the chan will be
 // closed.
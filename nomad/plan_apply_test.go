@@ -390,6 +390,61 @@ func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
 	assert.Equal(index, evalOut.ModifyIndex)
 }
 
+func TestPlanApply_applyPlan_SignedIdentities(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	waitForKeyring(t, s1)
+
+	node := mock.Node()
+	testRegisterNode(t, s1, node)
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(t, s1.State().UpsertJobSummary(1000, mock.JobSummary(alloc.JobID)))
+
+	eval := mock.Eval()
+	eval.JobID = alloc.JobID
+	require.NoError(t, s1.State().UpsertEvals(structs.MsgTypeTestSetup, 1010, []*structs.Evaluation{eval}))
+
+	planRes := &structs.PlanResult{
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+	plan := &structs.Plan{
+		Job:    alloc.Job,
+		EvalID: eval.ID,
+	}
+
+	snap, err := s1.State().Snapshot()
+	require.NoError(t, err)
+
+	future, err := s1.applyPlan(plan, planRes, snap)
+	require.NoError(t, err)
+	_, err = planWaitFuture(future)
+	require.NoError(t, err)
+
+	// Each task of the allocation should have an identity signed by the
+	// active root key.
+	allocOut, err := s1.fsm.State().AllocByID(nil, alloc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, allocOut)
+	require.Len(t, allocOut.SignedIdentities, len(alloc.Job.TaskGroups[0].Tasks))
+
+	keyMeta, err := s1.State().GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	require.Equal(t, keyMeta.KeyID, allocOut.SigningKeyID)
+
+	claims, err := s1.encrypter.VerifyClaims(allocOut.SignedIdentities["web"])
+	require.NoError(t, err)
+	require.Equal(t, alloc.ID, claims.AllocationID)
+	require.Equal(t, alloc.JobID, claims.JobID)
+	require.Equal(t, "web", claims.TaskName)
+}
+
 func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
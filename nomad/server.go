@@ -31,8 +31,11 @@ import (
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/helper/stats"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
+	"github.com/hashicorp/nomad/nomad/eventsink"
+	"github.com/hashicorp/nomad/nomad/maintenancewatcher"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
@@ -124,6 +127,9 @@ type Server struct {
 	// fsm is the state machine used with Raft
 	fsm *nomadFSM
 
+	// encrypter is the keyring used to encrypt and decrypt variables
+	encrypter *Encrypter
+
 	// rpcListener is used to listen for incoming connections
 	rpcListener net.Listener
 	listenerCh  chan struct{}
@@ -200,6 +206,12 @@ type Server struct {
 	// volumeWatcher is used to release volume claims
 	volumeWatcher *volumewatcher.Watcher
 
+	// maintenanceWatcher is used to work through node maintenance plans.
+	maintenanceWatcher *maintenancewatcher.Watcher
+
+	// eventSinkManager is used to deliver events to the event sinks.
+	eventSinkManager *eventsink.Manager
+
 	// evalBroker is used to manage the in-progress evaluations
 	// that are waiting to be brokered to a sub-scheduler
 	evalBroker *EvalBroker
@@ -236,6 +248,10 @@ type Server struct {
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *lru.TwoQueueCache
 
+	// oidcProviderCache caches the OIDC providers of the auth methods, so the
+	// OIDC discovery is not performed on every login.
+	oidcProviderCache *oidc.ProviderCache
+
 	// leaderAcl is the management ACL token that is valid when resolved by the
 	// current leader.
 	leaderAcl     string
@@ -268,6 +284,7 @@ type endpoints struct {
 	Job                 *Job
 	CSIVolume           *CSIVolume
 	CSIPlugin           *CSIPlugin
+	HostVolume          *HostVolume
 	Deployment          *Deployment
 	Region              *Region
 	Search              *Search
@@ -279,7 +296,10 @@ type endpoints struct {
 	Enterprise          *EnterpriseEndpoints
 	Event               *Event
 	Namespace           *Namespace
+	NodePool            *NodePool
+	Maintenance         *Maintenance
 	ServiceRegistration *ServiceRegistration
+	Variables           *Variables
 
 	// Client endpoints
 	ClientStats       *ClientStats
@@ -287,6 +307,7 @@ type endpoints struct {
 	Agent             *Agent
 	ClientAllocations *ClientAllocations
 	ClientCSI         *ClientCSI
+	ClientHostVolume  *ClientHostVolume
 }
 
 // NewServer is used to construct a new Nomad server from the
@@ -324,24 +345,25 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 
 	// Create the server
 	s := &Server{
-		config:           config,
-		consulCatalog:    consulCatalog,
-		connPool:         pool.NewPool(logger, serverRPCCache, serverMaxStreams, tlsWrap),
-		logger:           logger,
-		tlsWrap:          tlsWrap,
-		rpcServer:        rpc.NewServer(),
-		streamingRpcs:    structs.NewStreamingRpcRegistry(),
-		nodeConns:        make(map[string][]*nodeConnState),
-		peers:            make(map[string][]*serverParts),
-		localPeers:       make(map[raft.ServerAddress]*serverParts),
-		reassertLeaderCh: make(chan chan error),
-		reconcileCh:      make(chan serf.Member, 32),
-		eventCh:          make(chan serf.Event, 256),
-		evalBroker:       evalBroker,
-		blockedEvals:     NewBlockedEvals(evalBroker, logger),
-		rpcTLS:           incomingTLS,
-		aclCache:         aclCache,
-		workersEventCh:   make(chan interface{}, 1),
+		config:            config,
+		consulCatalog:     consulCatalog,
+		connPool:          pool.NewPool(logger, serverRPCCache, serverMaxStreams, tlsWrap),
+		logger:            logger,
+		tlsWrap:           tlsWrap,
+		rpcServer:         rpc.NewServer(),
+		streamingRpcs:     structs.NewStreamingRpcRegistry(),
+		nodeConns:         make(map[string][]*nodeConnState),
+		peers:             make(map[string][]*serverParts),
+		localPeers:        make(map[raft.ServerAddress]*serverParts),
+		reassertLeaderCh:  make(chan chan error),
+		reconcileCh:       make(chan serf.Member, 32),
+		eventCh:           make(chan serf.Event, 256),
+		evalBroker:        evalBroker,
+		blockedEvals:      NewBlockedEvals(evalBroker, logger),
+		rpcTLS:            incomingTLS,
+		aclCache:          aclCache,
+		oidcProviderCache: oidc.NewProviderCache(),
+		workersEventCh:    make(chan interface{}, 1),
 	}
 
 	s.shutdownCtx, s.shutdownCancel = context.WithCancel(context.Background())
@@ -350,6 +372,18 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Create the RPC handler
 	s.rpcHandler = newRpcHandler(s)
 
+	// Create the keyring used to encrypt variables. Root keys are only held
+	// in memory when running in dev mode.
+	var keystorePath string
+	if !config.DevMode && config.DataDir != "" {
+		keystorePath = filepath.Join(config.DataDir, keystoreDir)
+	}
+	encrypter, err := NewEncrypter(s, keystorePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup keyring: %v", err)
+	}
+	s.encrypter = encrypter
+
 	// Create the planner
 	planner, err := newPlanner(s)
 	if err != nil {
@@ -426,6 +460,12 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Setup the node drainer.
 	s.setupNodeDrainer()
 
+	// Setup the maintenance watcher.
+	s.setupMaintenanceWatcher()
+
+	// Setup the event sink manager.
+	s.setupEventSinkManager()
+
 	// Setup the enterprise state
 	if err := s.setupEnterprise(config); err != nil {
 		return nil, err
@@ -434,6 +474,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Monitor leadership changes
 	go s.monitorLeadership()
 
+	// Replicate root keys missing from the local keyring
+	go newKeyringReplicator(s, s.encrypter).run(s.shutdownCtx)
+
 	// Start ingesting events for Serf
 	go s.serfEventHandler()
 
@@ -1062,6 +1105,29 @@ func (s *Server) setupNodeDrainer() {
 	s.nodeDrainer = drainer.NewNodeDrainer(c)
 }
 
+// setupMaintenanceWatcher creates a maintenance watcher which will be enabled
+// when a server becomes a leader.
+func (s *Server) setupMaintenanceWatcher() {
+	s.maintenanceWatcher = maintenancewatcher.NewMaintenanceWatcher(
+		s.logger,
+		s.staticEndpoints.Node,
+		maintenanceWatcherShim{s},
+		maintenancewatcher.LimitStateQueriesPerSecond,
+	)
+}
+
+// setupEventSinkManager creates an event sink manager which will be enabled
+// when a server becomes a leader.
+func (s *Server) setupEventSinkManager() {
+	s.eventSinkManager = eventsink.NewManager(
+		s.logger,
+		eventSinkManagerShim{s},
+		eventsink.LimitStateQueriesPerSecond,
+		eventsink.ProgressUpdateInterval,
+		s.config.EventSinkFileDir,
+	)
+}
+
 // setupConsul is used to setup Server specific consul components.
 func (s *Server) setupConsul(consulConfigEntries consul.ConfigAPI, consulACLs consul.ACLsAPI) {
 	s.consulConfigEntries = NewConsulConfigsAPI(consulConfigEntries, s.logger)
@@ -1149,6 +1215,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.Job = NewJobEndpoints(s)
 		s.staticEndpoints.CSIVolume = &CSIVolume{srv: s, logger: s.logger.Named("csi_volume")}
 		s.staticEndpoints.CSIPlugin = &CSIPlugin{srv: s, logger: s.logger.Named("csi_plugin")}
+		s.staticEndpoints.HostVolume = &HostVolume{srv: s, logger: s.logger.Named("host_volume")}
 		s.staticEndpoints.Operator = &Operator{srv: s, logger: s.logger.Named("operator")}
 		s.staticEndpoints.Operator.register()
 
@@ -1159,6 +1226,9 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.System = &System{srv: s, logger: s.logger.Named("system")}
 		s.staticEndpoints.Search = &Search{srv: s, logger: s.logger.Named("search")}
 		s.staticEndpoints.Namespace = &Namespace{srv: s}
+		s.staticEndpoints.NodePool = &NodePool{srv: s}
+		s.staticEndpoints.Maintenance = &Maintenance{srv: s}
+		s.staticEndpoints.Variables = &Variables{srv: s}
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
 		// These endpoints are dynamic because they need access to the
@@ -1174,6 +1244,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.ClientAllocations = &ClientAllocations{srv: s, logger: s.logger.Named("client_allocs")}
 		s.staticEndpoints.ClientAllocations.register()
 		s.staticEndpoints.ClientCSI = &ClientCSI{srv: s, logger: s.logger.Named("client_csi")}
+		s.staticEndpoints.ClientHostVolume = &ClientHostVolume{srv: s, logger: s.logger.Named("client_host_volume")}
 
 		// Streaming endpoints
 		s.staticEndpoints.FileSystem = &FileSystem{srv: s, logger: s.logger.Named("client_fs")}
@@ -1206,6 +1277,12 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.FileSystem)
 	server.Register(s.staticEndpoints.Agent)
 	server.Register(s.staticEndpoints.Namespace)
+	_ = server.Register(s.staticEndpoints.NodePool)
+	_ = server.Register(s.staticEndpoints.Maintenance)
+	_ = server.Register(s.staticEndpoints.HostVolume)
+	_ = server.Register(s.staticEndpoints.ClientHostVolume)
+	_ = server.Register(s.staticEndpoints.Variables)
+	_ = server.Register(s.staticEndpoints.Event)
 
 	// Create new dynamic endpoints and add them to the RPC server.
 	alloc := &Alloc{srv: s, ctx: ctx, logger: s.logger.Named("alloc")}
@@ -1214,6 +1291,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	node := &Node{srv: s, ctx: ctx, logger: s.logger.Named("client")}
 	plan := &Plan{srv: s, ctx: ctx, logger: s.logger.Named("plan")}
 	serviceReg := &ServiceRegistration{srv: s, ctx: ctx}
+	keyring := &Keyring{srv: s, ctx: ctx}
 
 	// Register the dynamic endpoints
 	server.Register(alloc)
@@ -1222,6 +1300,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(node)
 	server.Register(plan)
 	_ = server.Register(serviceReg)
+	_ = server.Register(keyring)
 }
 
 // setupRaft is used to setup and initialize Raft
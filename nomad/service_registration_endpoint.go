@@ -403,9 +403,20 @@ func (s *ServiceRegistration) GetService(
 			// Set up our output after we have checked the error.
 			var services []*structs.ServiceRegistration
 
+			// Registrations which are failing their health checks should
+			// not be returned, as callers use this endpoint to discover
+			// where to route traffic.
+			filters := []paginator.Filter{
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						return raw.(*structs.ServiceRegistration).Healthy(), nil
+					},
+				},
+			}
+
 			// Build the paginator. This includes the function that is
 			// responsible for appending a registration to the services array.
-			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					services = append(services, raw.(*structs.ServiceRegistration))
 					return nil
@@ -446,7 +457,7 @@ func (s *ServiceRegistration) handleMixedAuthEndpoint(args structs.QueryOptions,
 		// Perform our ACL validation. If the object is nil, this means ACLs
 		// are not enabled, otherwise trigger the allowed namespace function.
 		if aclObj != nil {
-			if !aclObj.AllowNsOp(args.RequestNamespace(), cap) {
+			if !aclObj.AllowNsOp(args.RequestNamespace(), cap) && !s.workloadAllowed(args) {
 				return structs.ErrPermissionDenied
 			}
 		}
@@ -475,3 +486,21 @@ func (s *ServiceRegistration) handleMixedAuthEndpoint(args structs.QueryOptions,
 
 	return nil
 }
+
+// workloadAllowed returns whether the request is authenticated by a workload
+// identity from the requested namespace. Workloads are permitted to read the
+// services of their own namespace, as the nodes running them are.
+func (s *ServiceRegistration) workloadAllowed(args structs.QueryOptions) bool {
+	if !isWorkloadIdentity(args.AuthToken) {
+		return false
+	}
+	snap, err := s.srv.fsm.State().Snapshot()
+	if err != nil {
+		return false
+	}
+	claims, err := s.srv.workloadIdentityClaims(snap, args.AuthToken)
+	if err != nil {
+		return false
+	}
+	return claims.Namespace == args.RequestNamespace()
+}
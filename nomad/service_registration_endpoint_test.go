@@ -712,7 +712,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Create a policy and grab the token which has the read-job
 				// capability on the platform namespace.
@@ -765,7 +765,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Create a policy and grab the token which has the read policy
 				// on the platform namespace.
@@ -818,7 +818,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Generate a node.
 				node := mock.Node()
@@ -1174,6 +1174,58 @@ func TestServiceRegistration_GetService(t *testing.T) {
 			},
 			name: "filtering and pagination",
 		},
+		{
+			serverFn: func(t *testing.T) (*Server, *structs.ACLToken, func()) {
+				server, cleanup := TestServer(t, nil)
+				return server, nil, cleanup
+			},
+			testFn: func(t *testing.T, s *Server, _ *structs.ACLToken) {
+				codec := rpcClient(t, s)
+				testutil.WaitForLeader(t, s.RPC)
+
+				// Generate two registrations of the same service, where only
+				// the first has passing checks.
+				services := mock.ServiceRegistrations()
+				unhealthy := services[0].Copy()
+				unhealthy.ID += "_unhealthy"
+				unhealthy.Checks = []*structs.ServiceRegistrationCheck{
+					{ID: "check-1", Name: "alive", Type: structs.ServiceCheckTCP, Status: structs.ServiceCheckCritical},
+				}
+				services[0].Checks = []*structs.ServiceRegistrationCheck{
+					{ID: "check-1", Name: "alive", Type: structs.ServiceCheckTCP, Status: structs.ServiceCheckPassing},
+				}
+				require.NoError(t, s.fsm.State().UpsertServiceRegistrations(
+					structs.MsgTypeTestSetup, 10, []*structs.ServiceRegistration{services[0], unhealthy}))
+
+				// Only the healthy registration should be returned.
+				serviceRegReq := &structs.ServiceRegistrationByNameRequest{
+					ServiceName: services[0].ServiceName,
+					QueryOptions: structs.QueryOptions{
+						Namespace: services[0].Namespace,
+						Region:    s.Region(),
+					},
+				}
+				var serviceRegResp structs.ServiceRegistrationByNameResponse
+				err := msgpackrpc.CallWithCodec(
+					codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+				require.NoError(t, err)
+				require.Len(t, serviceRegResp.Services, 1)
+				require.Equal(t, services[0].ID, serviceRegResp.Services[0].ID)
+
+				// Once the check passes, both should be returned.
+				unhealthy = unhealthy.Copy()
+				unhealthy.Checks[0].Status = structs.ServiceCheckPassing
+				require.NoError(t, s.fsm.State().UpsertServiceRegistrations(
+					structs.MsgTypeTestSetup, 20, []*structs.ServiceRegistration{unhealthy}))
+
+				serviceRegResp = structs.ServiceRegistrationByNameResponse{}
+				err = msgpackrpc.CallWithCodec(
+					codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+				require.NoError(t, err)
+				require.Len(t, serviceRegResp.Services, 2)
+			},
+			name: "unhealthy registrations filtered",
+		},
 	}
 
 	for _, tc := range testCases {
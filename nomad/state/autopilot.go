@@ -45,8 +45,8 @@ func (s *StateStore) AutopilotConfig() (uint64, *structs.AutopilotConfig, error)
 }
 
 // AutopilotSetConfig is used to set the current Autopilot configuration.
-func (s *StateStore) AutopilotSetConfig(index uint64, config *structs.AutopilotConfig) error {
-	tx := s.db.WriteTxn(index)
+func (s *StateStore) AutopilotSetConfig(msgType structs.MessageType, index uint64, config *structs.AutopilotConfig) error {
+	tx := s.db.WriteTxnMsgT(msgType, index)
 	defer tx.Abort()
 
 	if err := s.autopilotSetConfigTxn(index, tx, config); err != nil {
@@ -59,8 +59,8 @@ func (s *StateStore) AutopilotSetConfig(index uint64, config *structs.AutopilotC
 // AutopilotCASConfig is used to try updating the Autopilot configuration with a
 // given Raft index. If the CAS index specified is not equal to the last observed index
 // for the config, then the call is a noop,
-func (s *StateStore) AutopilotCASConfig(index, cidx uint64, config *structs.AutopilotConfig) (bool, error) {
-	tx := s.db.WriteTxn(index)
+func (s *StateStore) AutopilotCASConfig(msgType structs.MessageType, index, cidx uint64, config *structs.AutopilotConfig) (bool, error) {
+	tx := s.db.WriteTxnMsgT(msgType, index)
 	defer tx.Abort()
 
 	// Check for an existing config
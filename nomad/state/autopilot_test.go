@@ -25,7 +25,7 @@ func TestStateStore_Autopilot(t *testing.T) {
 		EnableCustomUpgrades:    true,
 	}
 
-	if err := s.AutopilotSetConfig(0, expected); err != nil {
+	if err := s.AutopilotSetConfig(structs.MsgTypeTestSetup, 0, expected); err != nil {
 		t.Fatal(err)
 	}
 
@@ -50,15 +50,15 @@ func TestStateStore_AutopilotCAS(t *testing.T) {
 		CleanupDeadServers: true,
 	}
 
-	if err := s.AutopilotSetConfig(0, expected); err != nil {
+	if err := s.AutopilotSetConfig(structs.MsgTypeTestSetup, 0, expected); err != nil {
 		t.Fatal(err)
 	}
-	if err := s.AutopilotSetConfig(1, expected); err != nil {
+	if err := s.AutopilotSetConfig(structs.MsgTypeTestSetup, 1, expected); err != nil {
 		t.Fatal(err)
 	}
 
 	// Do a CAS with an index lower than the entry
-	ok, err := s.AutopilotCASConfig(2, 0, &structs.AutopilotConfig{
+	ok, err := s.AutopilotCASConfig(structs.MsgTypeTestSetup, 2, 0, &structs.AutopilotConfig{
 		CleanupDeadServers: false,
 	})
 	if ok || err != nil {
@@ -79,7 +79,7 @@ func TestStateStore_AutopilotCAS(t *testing.T) {
 	}
 
 	// Do another CAS, this time with the correct index
-	ok, err = s.AutopilotCASConfig(2, 1, &structs.AutopilotConfig{
+	ok, err = s.AutopilotCASConfig(structs.MsgTypeTestSetup, 2, 1, &structs.AutopilotConfig{
 		CleanupDeadServers: false,
 	})
 	if !ok || err != nil {
@@ -31,6 +31,20 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         structs.TypeServiceRegistration,
 	structs.ServiceRegistrationDeleteByIDRequestType:     structs.TypeServiceDeregistration,
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: structs.TypeServiceDeregistration,
+	structs.MaintenancePlanUpsertRequestType:             structs.TypeMaintenancePlanUpserted,
+	structs.MaintenancePlanDeleteRequestType:             structs.TypeMaintenancePlanDeleted,
+	structs.CSIVolumeRegisterRequestType:                 structs.TypeCSIVolumeRegistered,
+	structs.CSIVolumeDeregisterRequestType:               structs.TypeCSIVolumeDeregistered,
+	structs.CSIVolumeClaimRequestType:                    structs.TypeCSIVolumeClaim,
+	structs.CSIVolumeClaimBatchRequestType:               structs.TypeCSIVolumeClaim,
+	structs.CSIPluginDeleteRequestType:                   structs.TypeCSIPluginDeleted,
+	structs.HostVolumeUpsertRequestType:                  structs.TypeHostVolumeRegistered,
+	structs.HostVolumeDeleteRequestType:                  structs.TypeHostVolumeDeleted,
+	structs.NamespaceUpsertRequestType:                   structs.TypeNamespaceUpserted,
+	structs.NamespaceDeleteRequestType:                   structs.TypeNamespaceDeleted,
+	structs.PeriodicLaunchSkipRequestType:                structs.TypePeriodicLaunchUpdated,
+	structs.SchedulerConfigRequestType:                   structs.TypeSchedulerConfigUpdated,
+	structs.AutopilotRequestType:                         structs.TypeAutopilotConfigUpdated,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -42,7 +56,11 @@ func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
 	var events []structs.Event
 	for _, change := range changes.Changes {
 		if event, ok := eventFromChange(change); ok {
-			event.Type = eventType
+			// Objects that change along with other objects, such as the
+			// scaling policies of a job, carry their own event type
+			if event.Type == "" {
+				event.Type = eventType
+			}
 			event.Index = changes.Index
 			events = append(events, event)
 		}
@@ -108,6 +126,99 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 					Service: before,
 				},
 			}, true
+		case TableMaintenancePlans:
+			before, ok := change.Before.(*structs.MaintenancePlan)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic: structs.TopicMaintenance,
+				Key:   before.ID,
+				Payload: &structs.MaintenancePlanEvent{
+					Plan: before,
+				},
+			}, true
+		case TableHostVolumes:
+			before, ok := change.Before.(*structs.HostVolume)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:      structs.TopicHostVolume,
+				Key:        before.ID,
+				FilterKeys: []string{before.NodeID},
+				Namespace:  before.Namespace,
+				Payload: &structs.HostVolumeEvent{
+					HostVolume: before,
+				},
+			}, true
+		case "csi_volumes":
+			before, ok := change.Before.(*structs.CSIVolume)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:      structs.TopicCSIVolume,
+				Key:        before.ID,
+				FilterKeys: []string{before.PluginID},
+				Namespace:  before.Namespace,
+				Payload:    newCSIVolumeEvent(before),
+			}, true
+		case "csi_plugins":
+			before, ok := change.Before.(*structs.CSIPlugin)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic: structs.TopicCSIPlugin,
+				Type:  structs.TypeCSIPluginDeleted,
+				Key:   before.ID,
+				Payload: &structs.CSIPluginEvent{
+					Plugin: before,
+				},
+			}, true
+		case TableNamespaces:
+			before, ok := change.Before.(*structs.Namespace)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:     structs.TopicNamespace,
+				Key:       before.Name,
+				Namespace: before.Name,
+				Payload: &structs.NamespaceEvent{
+					Namespace: before,
+				},
+			}, true
+		case "scaling_policy":
+			before, ok := change.Before.(*structs.ScalingPolicy)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:      structs.TopicScalingPolicy,
+				Type:       structs.TypeScalingPolicyDeleted,
+				Key:        before.ID,
+				FilterKeys: []string{before.Target[structs.ScalingTargetJob]},
+				Namespace:  before.Target[structs.ScalingTargetNamespace],
+				Payload: &structs.ScalingPolicyEvent{
+					ScalingPolicy: before,
+				},
+			}, true
+		case "periodic_launch":
+			before, ok := change.Before.(*structs.PeriodicLaunch)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:     structs.TopicPeriodicLaunch,
+				Type:      structs.TypePeriodicLaunchDeleted,
+				Key:       before.ID,
+				Namespace: before.Namespace,
+				Payload: &structs.PeriodicLaunchEvent{
+					PeriodicLaunch: before,
+				},
+			}, true
 		}
 		return structs.Event{}, false
 	}
@@ -235,7 +346,137 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 				Service: after,
 			},
 		}, true
+	case TableMaintenancePlans:
+		after, ok := change.After.(*structs.MaintenancePlan)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicMaintenance,
+			Key:   after.ID,
+			Payload: &structs.MaintenancePlanEvent{
+				Plan: after,
+			},
+		}, true
+	case TableHostVolumes:
+		after, ok := change.After.(*structs.HostVolume)
+		if !ok {
+			return structs.Event{}, false
+		}
+		// Volumes become ready along with the node updates that
+		// fingerprint them, so they carry their own event type
+		return structs.Event{
+			Topic:      structs.TopicHostVolume,
+			Type:       structs.TypeHostVolumeRegistered,
+			Key:        after.ID,
+			FilterKeys: []string{after.NodeID},
+			Namespace:  after.Namespace,
+			Payload: &structs.HostVolumeEvent{
+				HostVolume: after,
+			},
+		}, true
+	case "csi_volumes":
+		after, ok := change.After.(*structs.CSIVolume)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:      structs.TopicCSIVolume,
+			Key:        after.ID,
+			FilterKeys: []string{after.PluginID},
+			Namespace:  after.Namespace,
+			Payload:    newCSIVolumeEvent(after),
+		}, true
+	case "csi_plugins":
+		after, ok := change.After.(*structs.CSIPlugin)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicCSIPlugin,
+			Type:  structs.TypeCSIPluginUpdated,
+			Key:   after.ID,
+			Payload: &structs.CSIPluginEvent{
+				Plugin: after,
+			},
+		}, true
+	case TableNamespaces:
+		after, ok := change.After.(*structs.Namespace)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:     structs.TopicNamespace,
+			Key:       after.Name,
+			Namespace: after.Name,
+			Payload: &structs.NamespaceEvent{
+				Namespace: after,
+			},
+		}, true
+	case "scaling_policy":
+		after, ok := change.After.(*structs.ScalingPolicy)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:      structs.TopicScalingPolicy,
+			Type:       structs.TypeScalingPolicyUpserted,
+			Key:        after.ID,
+			FilterKeys: []string{after.Target[structs.ScalingTargetJob]},
+			Namespace:  after.Target[structs.ScalingTargetNamespace],
+			Payload: &structs.ScalingPolicyEvent{
+				ScalingPolicy: after,
+			},
+		}, true
+	case "periodic_launch":
+		after, ok := change.After.(*structs.PeriodicLaunch)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:     structs.TopicPeriodicLaunch,
+			Type:      structs.TypePeriodicLaunchUpdated,
+			Key:       after.ID,
+			Namespace: after.Namespace,
+			Payload: &structs.PeriodicLaunchEvent{
+				PeriodicLaunch: after,
+			},
+		}, true
+	case "scheduler_config":
+		after, ok := change.After.(*structs.SchedulerConfiguration)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicOperator,
+			Key:   "scheduler",
+			Payload: &structs.SchedulerConfigurationEvent{
+				SchedulerConfig: after,
+			},
+		}, true
+	case "autopilot-config":
+		after, ok := change.After.(*structs.AutopilotConfig)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicOperator,
+			Key:   "autopilot",
+			Payload: &structs.AutopilotConfigEvent{
+				AutopilotConfig: after,
+			},
+		}, true
 	}
 
 	return structs.Event{}, false
 }
+
+// newCSIVolumeEvent returns the event payload of a CSI volume. The secrets of
+// the volume are removed so they aren't leaked to subscribers.
+func newCSIVolumeEvent(vol *structs.CSIVolume) *structs.CSIVolumeEvent {
+	vol = vol.Copy()
+	vol.Secrets = nil
+	return &structs.CSIVolumeEvent{
+		Volume: vol,
+	}
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
 )
@@ -110,7 +112,7 @@ func TestEventsFromChanges_DeploymentUpdate(t *testing.T) {
 	d := mock.Deployment()
 	d.JobID = j.ID
 
-	require.NoError(t, s.upsertJobImpl(10, j, false, setupTx))
+	require.NoError(t, s.upsertJobImpl(10, nil, j, false, setupTx))
 	require.NoError(t, s.upsertDeploymentImpl(10, d, setupTx))
 
 	setupTx.Txn.Commit()
@@ -154,7 +156,7 @@ func TestEventsFromChanges_DeploymentPromotion(t *testing.T) {
 	tg2 := tg1.Copy()
 	tg2.Name = "foo"
 	j.TaskGroups = append(j.TaskGroups, tg2)
-	require.NoError(t, s.upsertJobImpl(10, j, false, setupTx))
+	require.NoError(t, s.upsertJobImpl(10, nil, j, false, setupTx))
 
 	d := mock.Deployment()
 	d.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
@@ -231,7 +233,7 @@ func TestEventsFromChanges_DeploymentAllocHealthRequestType(t *testing.T) {
 	tg2 := tg1.Copy()
 	tg2.Name = "foo"
 	j.TaskGroups = append(j.TaskGroups, tg2)
-	require.NoError(t, s.upsertJobImpl(10, j, false, setupTx))
+	require.NoError(t, s.upsertJobImpl(10, nil, j, false, setupTx))
 
 	d := mock.Deployment()
 	d.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
@@ -1002,6 +1004,142 @@ func Test_eventsFromChanges_ServiceRegistration(t *testing.T) {
 	require.Equal(t, service, eventPayload.Service)
 }
 
+func Test_eventsFromChanges_CSIVolume(t *testing.T) {
+	ci.Parallel(t)
+	testState := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer testState.StopEventBroker()
+
+	vol := mock.CSIVolume(mock.CSIPlugin())
+	vol.Secrets = structs.CSISecrets{"password": "hunter2"}
+	require.NoError(t, testState.UpsertCSIVolume(
+		structs.CSIVolumeRegisterRequestType, 10, []*structs.CSIVolume{vol}))
+
+	events := WaitForEvents(t, testState, 10, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicCSIVolume, events[0].Topic)
+	require.Equal(t, structs.TypeCSIVolumeRegistered, events[0].Type)
+	require.Equal(t, vol.ID, events[0].Key)
+	require.Equal(t, vol.Namespace, events[0].Namespace)
+	require.Equal(t, []string{vol.PluginID}, events[0].FilterKeys)
+
+	// The secrets of the volume are removed from the event only
+	eventPayload := events[0].Payload.(*structs.CSIVolumeEvent)
+	require.Equal(t, vol.ID, eventPayload.Volume.ID)
+	require.Empty(t, eventPayload.Volume.Secrets)
+	require.Equal(t, "hunter2", vol.Secrets["password"])
+
+	require.NoError(t, testState.CSIVolumeDeregister(
+		structs.CSIVolumeDeregisterRequestType, 20, vol.Namespace, []string{vol.ID}, false))
+
+	events = WaitForEvents(t, testState, 20, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicCSIVolume, events[0].Topic)
+	require.Equal(t, structs.TypeCSIVolumeDeregistered, events[0].Type)
+	require.Empty(t, events[0].Payload.(*structs.CSIVolumeEvent).Volume.Secrets)
+}
+
+func Test_eventsFromChanges_Namespace(t *testing.T) {
+	ci.Parallel(t)
+	testState := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer testState.StopEventBroker()
+
+	ns := mock.Namespace()
+
+	// Namespace events are filtered by the name of the namespace
+	broker, err := testState.EventBroker()
+	require.NoError(t, err)
+	sub, err := broker.Subscribe(&stream.SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicNamespace: {"*"}},
+		Namespace: ns.Name,
+	})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, testState.UpsertNamespaces(
+		structs.NamespaceUpsertRequestType, 10, []*structs.Namespace{ns}))
+
+	events, err := sub.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), events.Index)
+	require.Len(t, events.Events, 1)
+	require.Equal(t, structs.TypeNamespaceUpserted, events.Events[0].Type)
+	require.Equal(t, ns.Name, events.Events[0].Key)
+	require.Equal(t, ns.Name, events.Events[0].Namespace)
+	require.Equal(t, ns.Name, events.Events[0].Payload.(*structs.NamespaceEvent).Namespace.Name)
+
+	require.NoError(t, testState.DeleteNamespaces(
+		structs.NamespaceDeleteRequestType, 20, []string{ns.Name}))
+
+	events, err = sub.Next(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), events.Index)
+	require.Len(t, events.Events, 1)
+	require.Equal(t, structs.TypeNamespaceDeleted, events.Events[0].Type)
+	require.Equal(t, ns.Name, events.Events[0].Key)
+}
+
+func Test_eventsFromChanges_ScalingPolicy(t *testing.T) {
+	ci.Parallel(t)
+	testState := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer testState.StopEventBroker()
+
+	// Scaling policies are upserted along with their job, with their own
+	// event type
+	job, policy := mock.JobWithScalingPolicy()
+	require.NoError(t, testState.UpsertJob(structs.JobRegisterRequestType, 10, job))
+
+	events := WaitForEvents(t, testState, 10, 2, 1*time.Second)
+	require.Len(t, events, 2)
+
+	byTopic := make(map[structs.Topic]structs.Event)
+	for _, event := range events {
+		byTopic[event.Topic] = event
+	}
+	require.Equal(t, structs.TypeJobRegistered, byTopic[structs.TopicJob].Type)
+
+	event := byTopic[structs.TopicScalingPolicy]
+	require.Equal(t, structs.TypeScalingPolicyUpserted, event.Type)
+	require.Equal(t, job.Namespace, event.Namespace)
+	require.Equal(t, []string{job.ID}, event.FilterKeys)
+
+	eventPayload := event.Payload.(*structs.ScalingPolicyEvent)
+	require.Equal(t, policy.Target, eventPayload.ScalingPolicy.Target)
+}
+
+func Test_eventsFromChanges_OperatorConfig(t *testing.T) {
+	ci.Parallel(t)
+	testState := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer testState.StopEventBroker()
+
+	schedConfig := &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+	}
+	require.NoError(t, testState.SchedulerSetConfig(structs.SchedulerConfigRequestType, 10, schedConfig))
+
+	events := WaitForEvents(t, testState, 10, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicOperator, events[0].Topic)
+	require.Equal(t, structs.TypeSchedulerConfigUpdated, events[0].Type)
+	require.Equal(t, "scheduler", events[0].Key)
+	require.Equal(t, structs.SchedulerAlgorithmSpread,
+		events[0].Payload.(*structs.SchedulerConfigurationEvent).SchedulerConfig.SchedulerAlgorithm)
+
+	autopilotConfig := &structs.AutopilotConfig{
+		CleanupDeadServers: true,
+	}
+	require.NoError(t, testState.AutopilotSetConfig(structs.AutopilotRequestType, 20, autopilotConfig))
+
+	events = WaitForEvents(t, testState, 20, 1, 1*time.Second)
+	require.Len(t, events, 1)
+	require.Equal(t, structs.TopicOperator, events[0].Topic)
+	require.Equal(t, structs.TypeAutopilotConfigUpdated, events[0].Type)
+	require.Equal(t, "autopilot", events[0].Key)
+	require.True(t, events[0].Payload.(*structs.AutopilotConfigEvent).AutopilotConfig.CleanupDeadServers)
+}
+
 func requireNodeRegistrationEventEqual(t *testing.T, want, got structs.Event) {
 	t.Helper()
 
@@ -1,8 +1,10 @@
 package state
 
 import (
+	"encoding/binary"
 	"fmt"
 	"sync"
+	"time"
 
 	memdb "github.com/hashicorp/go-memdb"
 
@@ -14,6 +16,16 @@ const (
 
 	TableNamespaces           = "namespaces"
 	TableServiceRegistrations = "service_registrations"
+	TableVariables            = "variables"
+	TableRootKeyMeta          = "root_key_meta"
+	TableACLRoles             = "acl_roles"
+	TableACLAuthMethods       = "acl_auth_methods"
+	TableACLBindingRules      = "acl_binding_rules"
+	TableNodePools            = "node_pools"
+	TableJobSubmission        = "job_submission"
+	TableMaintenancePlans     = "maintenance_plans"
+	TableEventSinks           = "event_sinks"
+	TableHostVolumes          = "host_volumes"
 )
 
 const (
@@ -22,6 +34,12 @@ const (
 	indexNodeID      = "node_id"
 	indexAllocID     = "alloc_id"
 	indexServiceName = "service_name"
+	indexKeyID       = "key_id"
+	indexName        = "name"
+	indexAuthMethod  = "auth_method"
+
+	indexExpiresGlobal = "expires-global"
+	indexExpiresLocal  = "expires-local"
 )
 
 var (
@@ -70,6 +88,16 @@ func init() {
 		scalingEventTableSchema,
 		namespaceTableSchema,
 		serviceRegistrationsTableSchema,
+		variablesTableSchema,
+		rootKeyMetaTableSchema,
+		aclRolesTableSchema,
+		aclAuthMethodsTableSchema,
+		aclBindingRulesTableSchema,
+		nodePoolsTableSchema,
+		jobSubmissionTableSchema,
+		maintenancePlansTableSchema,
+		eventSinksTableSchema,
+		hostVolumesTableSchema,
 	}...)
 }
 
@@ -816,10 +844,66 @@ func aclTokenTableSchema() *memdb.TableSchema {
 					Field: "Global",
 				},
 			},
+			indexExpiresGlobal: {
+				Name:         indexExpiresGlobal,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &TokenExpirationIndex{
+					Global: true,
+				},
+			},
+			indexExpiresLocal: {
+				Name:         indexExpiresLocal,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &TokenExpirationIndex{
+					Global: false,
+				},
+			},
 		},
 	}
 }
 
+// TokenExpirationIndex indexes ACL tokens by their expiration time. Tokens
+// without an expiration time, or whose locality does not match Global, are
+// omitted from the index.
+type TokenExpirationIndex struct {
+	Global bool
+}
+
+// FromObject is used to extract an index value from an
+// object or to indicate that the index value is missing.
+func (t *TokenExpirationIndex) FromObject(obj interface{}) (bool, []byte, error) {
+	token, ok := obj.(*structs.ACLToken)
+	if !ok {
+		return false, nil, fmt.Errorf("object %#v is not an ACLToken", obj)
+	}
+	if t.Global != token.Global || !token.HasExpirationTime() {
+		return false, nil, nil
+	}
+	return true, encodeTokenExpiration(*token.ExpirationTime), nil
+}
+
+// FromArgs is used to build an exact index lookup based on arguments
+func (t *TokenExpirationIndex) FromArgs(args ...interface{}) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("must provide only a single argument")
+	}
+	arg, ok := args[0].(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("argument must be a time.Time: %#v", args[0])
+	}
+	return encodeTokenExpiration(arg), nil
+}
+
+// encodeTokenExpiration encodes the time as a big-endian Unix timestamp, so
+// the index is sorted by expiration time.
+func encodeTokenExpiration(t time.Time) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(t.Unix()))
+	return buf
+}
+
 // oneTimeTokenTableSchema returns the MemDB schema for the tokens table.
 // This table is used to store one-time tokens for ACL tokens
 func oneTimeTokenTableSchema() *memdb.TableSchema {
@@ -1202,3 +1286,258 @@ func serviceRegistrationsTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// variablesTableSchema returns the MemDB schema for Nomad variables.
+func variablesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariables,
+		Indexes: map[string]*memdb.IndexSchema{
+			// The path in combination with the namespace forms a unique
+			// identifier for a variable. The prefix form of this index is
+			// used to list variables beneath a path.
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+					},
+				},
+			},
+			// The keyID index allows finding the variables encrypted with a
+			// given root key.
+			indexKeyID: {
+				Name:         indexKeyID,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
+// rootKeyMetaTableSchema returns the MemDB schema for the metadata of the
+// server keyring root keys.
+func rootKeyMetaTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableRootKeyMeta,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field:     "KeyID",
+					Lowercase: true,
+				},
+			},
+		},
+	}
+}
+
+// aclRolesTableSchema returns the MemDB schema for the ACL roles table. This
+// table is used to store ACL roles which group ACL policies together so they
+// can be linked to ACL tokens.
+func aclRolesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLRoles,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+			indexName: {
+				Name:         indexName,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclAuthMethodsTableSchema returns the MemDB schema for the auth methods
+// table. This table is used to store all the auth methods, which are
+// identified by their unique name.
+func aclAuthMethodsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLAuthMethods,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclBindingRulesTableSchema returns the MemDB schema for the binding rules
+// table. This table is used to store all the binding rules, which are
+// identified by their ID and can be looked up by the auth method they
+// belong to.
+func aclBindingRulesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLBindingRules,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+			indexAuthMethod: {
+				Name:         indexAuthMethod,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AuthMethod",
+				},
+			},
+		},
+	}
+}
+
+// nodePoolsTableSchema returns the MemDB schema for the node pools table.
+// This table is used to store all node pools, which are identified by their
+// unique name.
+func nodePoolsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNodePools,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// jobSubmissionTableSchema returns the MemDB schema for the job submission
+// table, which stores the original source of each tracked job version.
+func jobSubmissionTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobSubmission,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID,
+				// Version) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field:     "JobID",
+							Lowercase: true,
+						},
+
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// maintenancePlansTableSchema returns the MemDB schema for the maintenance
+// plans table. This table is used to store all maintenance plans, which are
+// identified by their ID.
+func maintenancePlansTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableMaintenancePlans,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
+
+// hostVolumesTableSchema returns the MemDB schema for the dynamic host
+// volumes table. Volumes are identified by their namespace and ID, and can be
+// looked up by the node they are created on.
+func hostVolumesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableHostVolumes,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "ID",
+						},
+					},
+				},
+			},
+			indexNodeID: {
+				Name:         indexNodeID,
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "NodeID",
+				},
+			},
+		},
+	}
+}
+
+// eventSinksTableSchema returns the MemDB schema for the event sinks table.
+// This table is used to store all event sinks, which are identified by their
+// ID.
+func eventSinksTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableEventSinks,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
@@ -141,6 +141,11 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		return nil, fmt.Errorf("enterprise state store initialization failed: %v", err)
 	}
 
+	// Initialize the state store with the built-in node pools.
+	if err := s.nodePoolInit(); err != nil {
+		return nil, fmt.Errorf("node pool state store initialization failed: %v", err)
+	}
+
 	return s, nil
 }
 
@@ -172,7 +177,7 @@ func (s *StateStore) namespaceInit() error {
 		Description: structs.DefaultNamespaceDescription,
 	}
 
-	if err := s.UpsertNamespaces(1, []*structs.Namespace{defaultNs}); err != nil {
+	if err := s.UpsertNamespaces(structs.IgnoreUnknownTypeFlag, 1, []*structs.Namespace{defaultNs}); err != nil {
 		return fmt.Errorf("inserting default namespace failed: %v", err)
 	}
 
@@ -917,6 +922,12 @@ func upsertNodeTxn(txn *txn, index uint64, node *structs.Node) error {
 	if err := upsertCSIPluginsForNode(txn, node, index); err != nil {
 		return fmt.Errorf("csi plugin update failed: %v", err)
 	}
+	if err := upsertNodePoolForNodeTxn(txn, index, node.NodePool); err != nil {
+		return fmt.Errorf("node pool update failed: %v", err)
+	}
+	if err := updateHostVolumesForNodeTxn(txn, index, node); err != nil {
+		return fmt.Errorf("host volume update failed: %v", err)
+	}
 
 	return nil
 }
@@ -1578,7 +1589,19 @@ func (s *StateStore) Nodes(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 func (s *StateStore) UpsertJob(msgType structs.MessageType, index uint64, job *structs.Job) error {
 	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
-	if err := s.upsertJobImpl(index, job, false, txn); err != nil {
+	if err := s.upsertJobImpl(index, nil, job, false, txn); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// UpsertJobWithSubmission is used to register a job or update a job
+// definition, like UpsertJob, and stores the source the new job version was
+// submitted with. The submission may be nil.
+func (s *StateStore) UpsertJobWithSubmission(msgType structs.MessageType, index uint64, sub *structs.JobSubmission, job *structs.Job) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+	if err := s.upsertJobImpl(index, sub, job, false, txn); err != nil {
 		return err
 	}
 	return txn.Commit()
@@ -1587,11 +1610,13 @@ func (s *StateStore) UpsertJob(msgType structs.MessageType, index uint64, job *s
 // UpsertJobTxn is used to register a job or update a job definition, like UpsertJob,
 // but in a transaction.  Useful for when making multiple modifications atomically
 func (s *StateStore) UpsertJobTxn(index uint64, job *structs.Job, txn Txn) error {
-	return s.upsertJobImpl(index, job, false, txn)
+	return s.upsertJobImpl(index, nil, job, false, txn)
 }
 
-// upsertJobImpl is the implementation for registering a job or updating a job definition
-func (s *StateStore) upsertJobImpl(index uint64, job *structs.Job, keepVersion bool, txn *txn) error {
+// upsertJobImpl is the implementation for registering a job or updating a job
+// definition. The submission is optional and is only stored along with a new
+// job version.
+func (s *StateStore) upsertJobImpl(index uint64, sub *structs.JobSubmission, job *structs.Job, keepVersion bool, txn *txn) error {
 	// Assert the namespace exists
 	if exists, err := s.namespaceExists(txn, job.Namespace); err != nil {
 		return err
@@ -1613,6 +1638,10 @@ func (s *StateStore) upsertJobImpl(index uint64, job *structs.Job, keepVersion b
 
 		existingJob = existing.(*structs.Job)
 
+		// The evaluation pause is managed by the server and so it is kept
+		// across updates of the job.
+		job.EvalsPaused = existingJob.EvalsPaused
+
 		// Bump the version unless asked to keep it. This should only be done
 		// when changing an internal field such as Stable. A spec change should
 		// always come with a version bump
@@ -1656,6 +1685,12 @@ func (s *StateStore) upsertJobImpl(index uint64, job *structs.Job, keepVersion b
 		return fmt.Errorf("unable to upsert job into job_version table: %v", err)
 	}
 
+	if !keepVersion {
+		if err := s.upsertJobSubmission(index, sub, job, txn); err != nil {
+			return fmt.Errorf("unable to upsert job submission: %v", err)
+		}
+	}
+
 	if err := s.updateJobScalingPolicies(index, job, txn); err != nil {
 		return fmt.Errorf("unable to update job scaling policies: %v", err)
 	}
@@ -1762,6 +1797,11 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 		return err
 	}
 
+	// Delete the job submissions
+	if err := s.deleteJobSubmissions(index, job, txn); err != nil {
+		return err
+	}
+
 	// Cleanup plugins registered by this job, before we delete the summary
 	err = s.deleteJobFromPlugins(index, txn, job)
 	if err != nil {
@@ -1915,9 +1955,124 @@ func (s *StateStore) upsertJobVersion(index uint64, job *structs.Job, txn *txn)
 		return fmt.Errorf("failed to delete job %v (%d) from job_version", d.ID, d.Version)
 	}
 
+	// Delete the submission of the version, if any, so that submissions are
+	// only kept for tracked versions.
+	existing, err := txn.First(TableJobSubmission, indexID, d.Namespace, d.ID, d.Version)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	if existing != nil {
+		if err := txn.Delete(TableJobSubmission, existing); err != nil {
+			return fmt.Errorf("failed to delete job submission %v (%d): %v", d.ID, d.Version, err)
+		}
+		if err := txn.Insert("index", &IndexEntry{TableJobSubmission, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// upsertJobSubmission stores the submission of a new job version, replacing
+// any submission stored for an earlier job with the same version. A nil
+// submission removes the stale submission only.
+func (s *StateStore) upsertJobSubmission(index uint64, sub *structs.JobSubmission, job *structs.Job, txn *txn) error {
+	existing, err := txn.First(TableJobSubmission, indexID, job.Namespace, job.ID, job.Version)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	if existing == nil && sub == nil {
+		return nil
+	}
+	if existing != nil {
+		if err := txn.Delete(TableJobSubmission, existing); err != nil {
+			return fmt.Errorf("job submission delete failed: %v", err)
+		}
+	}
+
+	if sub != nil {
+		sub = sub.Copy()
+		sub.Namespace = job.Namespace
+		sub.JobID = job.ID
+		sub.Version = job.Version
+		sub.JobModifyIndex = job.JobModifyIndex
+		if err := txn.Insert(TableJobSubmission, sub); err != nil {
+			return fmt.Errorf("job submission insert failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
 	return nil
 }
 
+// deleteJobSubmissions deletes the submissions of all versions of the given
+// job.
+func (s *StateStore) deleteJobSubmissions(index uint64, job *structs.Job, txn *txn) error {
+	iter, err := txn.Get(TableJobSubmission, indexID+"_prefix", job.Namespace, job.ID)
+	if err != nil {
+		return err
+	}
+
+	// Put them into a slice so there are no safety concerns while actually
+	// performing the deletes
+	var subs []*structs.JobSubmission
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		// Ensure the ID is an exact match
+		sub := raw.(*structs.JobSubmission)
+		if sub.JobID != job.ID {
+			continue
+		}
+		subs = append(subs, sub)
+	}
+
+	if len(subs) == 0 {
+		return nil
+	}
+
+	for _, sub := range subs {
+		if err := txn.Delete(TableJobSubmission, sub); err != nil {
+			return fmt.Errorf("deleting job submissions failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// JobSubmission returns the source the given version of a job was submitted
+// with. It returns nil if the version was registered without its source or
+// is no longer tracked. The passed watchset may be nil.
+func (s *StateStore) JobSubmission(ws memdb.WatchSet, namespace, jobID string, version uint64) (*structs.JobSubmission, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobSubmission, indexID, namespace, jobID, version)
+	if err != nil {
+		return nil, fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobSubmission), nil
+	}
+	return nil, nil
+}
+
+// JobSubmissions returns an iterator over the submissions of all jobs.
+func (s *StateStore) JobSubmissions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobSubmission, indexID)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // JobByID is used to lookup a job by its ID. JobByID returns the current/latest job
 // version.
 func (s *StateStore) JobByID(ws memdb.WatchSet, namespace, id string) (*structs.Job, error) {
@@ -2194,8 +2349,8 @@ func (s *StateStore) JobSummaryByPrefix(ws memdb.WatchSet, namespace, id string)
 }
 
 // UpsertCSIVolume inserts a volume in the state store.
-func (s *StateStore) UpsertCSIVolume(index uint64, volumes []*structs.CSIVolume) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) UpsertCSIVolume(msgType structs.MessageType, index uint64, volumes []*structs.CSIVolume) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, v := range volumes {
@@ -2221,7 +2376,21 @@ func (s *StateStore) UpsertCSIVolume(index uint64, volumes []*structs.CSIVolume)
 			}
 			s.CSIVolumeDenormalize(nil, old.Copy())
 			if old.InUse() {
-				return fmt.Errorf("volume cannot be updated while in use")
+				// Only the capacity of a volume in use can be updated,
+				// after it has been expanded. We write the new capacity
+				// onto a copy of the existing volume so that we don't
+				// overwrite claims that changed since the update was
+				// submitted.
+				if v.Capacity == old.Capacity &&
+					v.NodeExpansionRequired == old.NodeExpansionRequired {
+					return fmt.Errorf("volume cannot be updated while in use")
+				}
+				expanded := old.Copy()
+				expanded.Capacity = v.Capacity
+				expanded.RequestedCapacityMin = v.RequestedCapacityMin
+				expanded.RequestedCapacityMax = v.RequestedCapacityMax
+				expanded.NodeExpansionRequired = v.NodeExpansionRequired
+				v = expanded
 			}
 
 			v.CreateIndex = old.CreateIndex
@@ -2419,8 +2588,8 @@ func (s *StateStore) CSIVolumesByNamespace(ws memdb.WatchSet, namespace, prefix
 }
 
 // CSIVolumeClaim updates the volume's claim count and allocation list
-func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *structs.CSIVolumeClaim) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) CSIVolumeClaim(msgType structs.MessageType, index uint64, namespace, id string, claim *structs.CSIVolumeClaim) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	row, err := txn.First("csi_volumes", "id", namespace, id)
@@ -2495,8 +2664,8 @@ func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *s
 }
 
 // CSIVolumeDeregister removes the volume from the server
-func (s *StateStore) CSIVolumeDeregister(index uint64, namespace string, ids []string, force bool) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) CSIVolumeDeregister(msgType structs.MessageType, index uint64, namespace string, ids []string, force bool) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, id := range ids {
@@ -2826,8 +2995,8 @@ func (s *StateStore) UpsertCSIPlugin(index uint64, plug *structs.CSIPlugin) erro
 }
 
 // DeleteCSIPlugin deletes the plugin if it's not in use.
-func (s *StateStore) DeleteCSIPlugin(index uint64, id string) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) DeleteCSIPlugin(msgType structs.MessageType, index uint64, id string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	plug, err := s.CSIPluginByIDTxn(txn, nil, id)
@@ -2855,8 +3024,8 @@ func (s *StateStore) DeleteCSIPlugin(index uint64, id string) error {
 }
 
 // UpsertPeriodicLaunch is used to register a launch or update it.
-func (s *StateStore) UpsertPeriodicLaunch(index uint64, launch *structs.PeriodicLaunch) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) UpsertPeriodicLaunch(msgType structs.MessageType, index uint64, launch *structs.PeriodicLaunch) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	// Check if the job already exists
@@ -2885,6 +3054,66 @@ func (s *StateStore) UpsertPeriodicLaunch(index uint64, launch *structs.Periodic
 	return txn.Commit()
 }
 
+// SkipPeriodicLaunch is used to record a periodic launch that was skipped
+// because it fell within an exclusion of the periodic job. The launch time is
+// tracked as the last launch of the job so the skipped launch isn't forced
+// when leadership changes, and the skip is counted on the job summary.
+func (s *StateStore) SkipPeriodicLaunch(msgType structs.MessageType, index uint64,
+	namespace, jobID string, launchTime time.Time) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First("periodic_launch", "id", namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("periodic launch lookup failed: %v", err)
+	}
+
+	launch := &structs.PeriodicLaunch{
+		ID:          jobID,
+		Namespace:   namespace,
+		Launch:      launchTime,
+		CreateIndex: index,
+		ModifyIndex: index,
+	}
+	if existing != nil {
+		prev := existing.(*structs.PeriodicLaunch)
+		launch.CreateIndex = prev.CreateIndex
+		if prev.Launch.After(launchTime) {
+			launch.Launch = prev.Launch
+		}
+	}
+
+	if err := txn.Insert("periodic_launch", launch); err != nil {
+		return fmt.Errorf("launch insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"periodic_launch", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	summaryRaw, err := txn.First("job_summary", "id", namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("job summary lookup failed: %v", err)
+	}
+	if summaryRaw != nil {
+		summary := summaryRaw.(*structs.JobSummary).Copy()
+		if summary.Children == nil {
+			summary.Children = new(structs.JobChildrenSummary)
+		}
+		summary.Children.Skipped++
+		summary.ModifyIndex = index
+
+		if err := txn.Insert("job_summary", summary); err != nil {
+			return fmt.Errorf("job summary insert failed: %v", err)
+		}
+		if err := txn.Insert("index", &IndexEntry{"job_summary", index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return txn.Commit()
+}
+
 // DeletePeriodicLaunch is used to delete the periodic launch
 func (s *StateStore) DeletePeriodicLaunch(index uint64, namespace, jobID string) error {
 	txn := s.db.WriteTxn(index)
@@ -4240,7 +4469,7 @@ func (s *StateStore) UpdateDeploymentStatus(msgType structs.MessageType, index u
 
 	// Upsert the job if necessary
 	if req.Job != nil {
-		if err := s.upsertJobImpl(index, req.Job, false, txn); err != nil {
+		if err := s.upsertJobImpl(index, nil, req.Job, false, txn); err != nil {
 			return err
 		}
 	}
@@ -4327,7 +4556,40 @@ func (s *StateStore) updateJobStabilityImpl(index uint64, namespace, jobID strin
 
 	copy := job.Copy()
 	copy.Stable = stable
-	return s.upsertJobImpl(index, copy, true, txn)
+	return s.upsertJobImpl(index, nil, copy, true, txn)
+}
+
+// UpdateJobEvalPause updates whether the evaluations of the given job are
+// paused. The job version is unchanged.
+func (s *StateStore) UpdateJobEvalPause(msgType structs.MessageType, index uint64, namespace, jobID string, paused bool) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First("jobs", "id", namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("job %q in namespace %q not found", jobID, namespace)
+	}
+
+	job := existing.(*structs.Job)
+	if job.EvalsPaused == paused {
+		return nil
+	}
+
+	job = job.Copy()
+	job.EvalsPaused = paused
+	job.ModifyIndex = index
+
+	if err := txn.Insert("jobs", job); err != nil {
+		return fmt.Errorf("job insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"jobs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
 }
 
 // UpdateDeploymentPromotion is used to promote canaries in a deployment and
@@ -4556,7 +4818,7 @@ func (s *StateStore) UpdateDeploymentAllocHealth(msgType structs.MessageType, in
 
 	// Upsert the job if necessary
 	if req.Job != nil {
-		if err := s.upsertJobImpl(index, req.Job, false, txn); err != nil {
+		if err := s.upsertJobImpl(index, nil, req.Job, false, txn); err != nil {
 			return err
 		}
 	}
@@ -4679,13 +4941,17 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 
 			oldSummary := rawSummary.(*structs.JobSummary)
 
-			// Create an empty summary
+			// Create an empty summary, keeping the skipped launches which
+			// can't be derived from the children
 			summary := &structs.JobSummary{
 				JobID:     job.ID,
 				Namespace: job.Namespace,
 				Summary:   make(map[string]structs.TaskGroupSummary),
 				Children:  &structs.JobChildrenSummary{},
 			}
+			if oldSummary.Children != nil {
+				summary.Children.Skipped = oldSummary.Children.Skipped
+			}
 
 			// Iterate over children of this job if any to fix summary counts
 			children := parentMap[job.ID]
@@ -5700,6 +5966,54 @@ func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool, sort S
 	return iter, nil
 }
 
+// ACLTokensByExpired returns an array of accessor IDs of expired ACL tokens.
+// Their expiration is determined against the passed time.Time value.
+//
+// The function handles global and local tokens independently as determined by
+// the global boolean argument. The number of returned IDs can be limited by
+// the max integer, which is useful to limit the number of tokens we attempt to
+// delete in a single transaction.
+func (s *StateStore) ACLTokensByExpired(global bool, now time.Time, max int) ([]string, error) {
+	txn := s.db.ReadTxn()
+
+	index := indexExpiresLocal
+	if global {
+		index = indexExpiresGlobal
+	}
+
+	// The index is sorted by expiration time, so iterate from the earliest
+	// expiring token until we find one which has not expired.
+	iter, err := txn.LowerBound("acl_token", index, time.Unix(0, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed acl token listing: %v", err)
+	}
+
+	var (
+		accessorIDs []string
+		num         int
+	)
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+
+		// The iterator is sorted by expiration time, so we can stop at the
+		// first token which has not expired yet.
+		if !token.IsExpired(now) {
+			break
+		}
+
+		// Check if we have hit our maximum number of returned accessor IDs.
+		if num >= max {
+			break
+		}
+
+		accessorIDs = append(accessorIDs, token.AccessorID)
+		num++
+	}
+
+	return accessorIDs, nil
+}
+
 // CanBootstrapACLToken checks if bootstrapping is possible and returns the reset index
 func (s *StateStore) CanBootstrapACLToken() (bool, uint64, error) {
 	txn := s.db.ReadTxn()
@@ -5906,8 +6220,8 @@ func (s *StateStore) SchedulerConfig() (uint64, *structs.SchedulerConfiguration,
 }
 
 // SchedulerSetConfig is used to set the current Scheduler configuration.
-func (s *StateStore) SchedulerSetConfig(index uint64, config *structs.SchedulerConfiguration) error {
-	tx := s.db.WriteTxn(index)
+func (s *StateStore) SchedulerSetConfig(msgType structs.MessageType, index uint64, config *structs.SchedulerConfiguration) error {
+	tx := s.db.WriteTxnMsgT(msgType, index)
 	defer tx.Abort()
 
 	s.schedulerSetConfigTxn(index, tx, config)
@@ -5961,8 +6275,8 @@ func (s *StateStore) WithWriteTransaction(msgType structs.MessageType, index uin
 // SchedulerCASConfig is used to update the scheduler configuration with a
 // given Raft index. If the CAS index specified is not equal to the last observed index
 // for the config, then the call is a noop.
-func (s *StateStore) SchedulerCASConfig(index, cidx uint64, config *structs.SchedulerConfiguration) (bool, error) {
-	tx := s.db.WriteTxn(index)
+func (s *StateStore) SchedulerCASConfig(msgType structs.MessageType, index, cidx uint64, config *structs.SchedulerConfiguration) (bool, error) {
+	tx := s.db.WriteTxnMsgT(msgType, index)
 	defer tx.Abort()
 
 	// Check for an existing config
@@ -6182,8 +6496,8 @@ func (s *StateStore) NamespaceNames() ([]string, error) {
 }
 
 // UpsertNamespaces is used to register or update a set of namespaces.
-func (s *StateStore) UpsertNamespaces(index uint64, namespaces []*structs.Namespace) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) UpsertNamespaces(msgType structs.MessageType, index uint64, namespaces []*structs.Namespace) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, ns := range namespaces {
@@ -6249,8 +6563,8 @@ func (s *StateStore) upsertNamespaceImpl(index uint64, txn *txn, namespace *stru
 }
 
 // DeleteNamespaces is used to remove a set of namespaces
-func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) DeleteNamespaces(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, name := range names {
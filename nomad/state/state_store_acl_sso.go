@@ -0,0 +1,369 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertACLAuthMethods is used to insert a number of auth methods into the
+// state store. It uses a single write transaction for efficiency, however,
+// any error means no entries will be committed.
+func (s *StateStore) UpsertACLAuthMethods(
+	msgType structs.MessageType, index uint64, authMethods []*structs.ACLAuthMethod) error {
+
+	// Grab a write transaction.
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// updated tracks whether any inserts have been made. This allows us to
+	// skip updating the index table if we do not need to.
+	var updated bool
+
+	// Iterate the array of auth methods. In the event of a single error, all
+	// inserts fail via the txn.Abort() defer.
+	for _, authMethod := range authMethods {
+
+		methodUpdated, err := s.upsertACLAuthMethodTxn(index, txn, authMethod)
+		if err != nil {
+			return err
+		}
+
+		// Ensure we track whether any inserts have been made.
+		updated = updated || methodUpdated
+	}
+
+	// If we did not perform any inserts, exit early.
+	if !updated {
+		return nil
+	}
+
+	// Perform the index table update to mark the new insert.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLAuthMethods, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// upsertACLAuthMethodTxn inserts a single auth method into the state store
+// using the provided write transaction. It is the responsibility of the
+// caller to update the index table.
+func (s *StateStore) upsertACLAuthMethodTxn(
+	index uint64, txn *txn, authMethod *structs.ACLAuthMethod) (bool, error) {
+
+	// Ensure the auth method hash is not zero to provide defense in depth.
+	// This should be done outside the state store, so we do not spend time
+	// here and thus Raft, when it can be avoided.
+	if len(authMethod.Hash) == 0 {
+		authMethod.SetHash()
+	}
+
+	existing, err := txn.First(TableACLAuthMethods, indexID, authMethod.Name)
+	if err != nil {
+		return false, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		exist := existing.(*structs.ACLAuthMethod)
+		if exist.Equal(authMethod) {
+			return false, nil
+		}
+		authMethod.CreateIndex = exist.CreateIndex
+		authMethod.ModifyIndex = index
+	} else {
+		authMethod.CreateIndex = index
+		authMethod.ModifyIndex = index
+	}
+
+	// Insert the auth method into the table.
+	if err := txn.Insert(TableACLAuthMethods, authMethod); err != nil {
+		return false, fmt.Errorf("ACL auth method insert failed: %v", err)
+	}
+	return true, nil
+}
+
+// DeleteACLAuthMethods is responsible for batch deleting auth methods based
+// on their name. The binding rules which belong to the deleted auth methods
+// are deleted within the same transaction. An error is produced if an auth
+// method is not found within state which has been passed within the array.
+func (s *StateStore) DeleteACLAuthMethods(
+	msgType structs.MessageType, index uint64, authMethodNames []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	var rulesDeleted bool
+
+	for _, name := range authMethodNames {
+		existing, err := txn.First(TableACLAuthMethods, indexID, name)
+		if err != nil {
+			return fmt.Errorf("ACL auth method lookup failed: %v", err)
+		}
+		if existing == nil {
+			return errors.New("ACL auth method not found")
+		}
+		if err := txn.Delete(TableACLAuthMethods, existing); err != nil {
+			return fmt.Errorf("ACL auth method deletion failed: %v", err)
+		}
+
+		// Remove the binding rules of the auth method, so they are not
+		// applied if an auth method with the same name is created.
+		num, err := txn.DeleteAll(TableACLBindingRules, indexAuthMethod, name)
+		if err != nil {
+			return fmt.Errorf("ACL binding rule deletion failed: %v", err)
+		}
+		rulesDeleted = rulesDeleted || num > 0
+	}
+
+	// Update the index table to indicate an update has occurred.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLAuthMethods, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if rulesDeleted {
+		if err := txn.Insert(tableIndex, &IndexEntry{TableACLBindingRules, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
+
+	return txn.Commit()
+}
+
+// GetACLAuthMethods returns an iterator that contains all auth methods
+// stored within state.
+func (s *StateStore) GetACLAuthMethods(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table to get all auth methods.
+	iter, err := txn.Get(TableACLAuthMethods, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetACLAuthMethodByName returns a single auth method specified by the input
+// name. The auth method object will be nil, if no matching entry was found;
+// it is the responsibility of the caller to check for this.
+func (s *StateStore) GetACLAuthMethodByName(ws memdb.WatchSet, authMethod string) (*structs.ACLAuthMethod, error) {
+	txn := s.db.ReadTxn()
+
+	// Perform the auth method lookup using the "id" index.
+	watchCh, existing, err := txn.FirstWatch(TableACLAuthMethods, indexID, authMethod)
+	if err != nil {
+		return nil, fmt.Errorf("ACL auth method lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLAuthMethod), nil
+	}
+	return nil, nil
+}
+
+// GetDefaultACLAuthMethod returns the auth method which is set as the
+// default. The auth method object will be nil, if no default has been set;
+// it is the responsibility of the caller to check for this.
+func (s *StateStore) GetDefaultACLAuthMethod(ws memdb.WatchSet) (*structs.ACLAuthMethod, error) {
+	iter, err := s.GetACLAuthMethods(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		authMethod := raw.(*structs.ACLAuthMethod)
+		if authMethod.Default {
+			return authMethod, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpsertACLBindingRules is used to insert a number of binding rules into the
+// state store. It uses a single write transaction for efficiency, however,
+// any error means no entries will be committed.
+func (s *StateStore) UpsertACLBindingRules(
+	msgType structs.MessageType, index uint64, bindingRules []*structs.ACLBindingRule, allowMissingAuthMethods bool) error {
+
+	// Grab a write transaction.
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// updated tracks whether any inserts have been made. This allows us to
+	// skip updating the index table if we do not need to.
+	var updated bool
+
+	// Iterate the array of binding rules. In the event of a single error,
+	// all inserts fail via the txn.Abort() defer.
+	for _, bindingRule := range bindingRules {
+
+		ruleUpdated, err := s.upsertACLBindingRuleTxn(index, txn, bindingRule, allowMissingAuthMethods)
+		if err != nil {
+			return err
+		}
+
+		// Ensure we track whether any inserts have been made.
+		updated = updated || ruleUpdated
+	}
+
+	// If we did not perform any inserts, exit early.
+	if !updated {
+		return nil
+	}
+
+	// Perform the index table update to mark the new insert.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLBindingRules, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// upsertACLBindingRuleTxn inserts a single binding rule into the state store
+// using the provided write transaction. It is the responsibility of the
+// caller to update the index table.
+func (s *StateStore) upsertACLBindingRuleTxn(
+	index uint64, txn *txn, bindingRule *structs.ACLBindingRule, allowMissingAuthMethods bool) (bool, error) {
+
+	// Ensure the binding rule hash is not zero to provide defense in depth.
+	// This should be done outside the state store, so we do not spend time
+	// here and thus Raft, when it can be avoided.
+	if len(bindingRule.Hash) == 0 {
+		bindingRule.SetHash()
+	}
+
+	// This validation also happens within the RPC handler, but Raft latency
+	// could mean that by the time the state call is invoked, another Raft
+	// update has deleted the auth method. Therefore, check again while in
+	// our write txn.
+	if !allowMissingAuthMethods {
+		method, err := txn.First(TableACLAuthMethods, indexID, bindingRule.AuthMethod)
+		if err != nil {
+			return false, fmt.Errorf("ACL auth method lookup failed: %v", err)
+		}
+		if method == nil {
+			return false, errors.New("ACL auth method not found")
+		}
+	}
+
+	existing, err := txn.First(TableACLBindingRules, indexID, bindingRule.ID)
+	if err != nil {
+		return false, fmt.Errorf("ACL binding rule lookup failed: %v", err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		exist := existing.(*structs.ACLBindingRule)
+		if exist.Equal(bindingRule) {
+			return false, nil
+		}
+		bindingRule.CreateIndex = exist.CreateIndex
+		bindingRule.ModifyIndex = index
+	} else {
+		bindingRule.CreateIndex = index
+		bindingRule.ModifyIndex = index
+	}
+
+	// Insert the binding rule into the table.
+	if err := txn.Insert(TableACLBindingRules, bindingRule); err != nil {
+		return false, fmt.Errorf("ACL binding rule insert failed: %v", err)
+	}
+	return true, nil
+}
+
+// DeleteACLBindingRules is responsible for batch deleting binding rules based
+// on their ID. It uses a single write transaction for efficiency, however,
+// any error means no entries will be committed. An error is produced if a
+// rule is not found within state which has been passed within the array.
+func (s *StateStore) DeleteACLBindingRules(
+	msgType structs.MessageType, index uint64, bindingRuleIDs []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, ruleID := range bindingRuleIDs {
+		existing, err := txn.First(TableACLBindingRules, indexID, ruleID)
+		if err != nil {
+			return fmt.Errorf("ACL binding rule lookup failed: %v", err)
+		}
+		if existing == nil {
+			return errors.New("ACL binding rule not found")
+		}
+		if err := txn.Delete(TableACLBindingRules, existing); err != nil {
+			return fmt.Errorf("ACL binding rule deletion failed: %v", err)
+		}
+	}
+
+	// Update the index table to indicate an update has occurred.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLBindingRules, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// GetACLBindingRules returns an iterator that contains all binding rules
+// stored within state.
+func (s *StateStore) GetACLBindingRules(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Walk the entire table to get all binding rules.
+	iter, err := txn.Get(TableACLBindingRules, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("ACL binding rule lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetACLBindingRule returns a single binding rule specified by the input ID.
+// The binding rule object will be nil, if no matching entry was found; it is
+// the responsibility of the caller to check for this.
+func (s *StateStore) GetACLBindingRule(ws memdb.WatchSet, ruleID string) (*structs.ACLBindingRule, error) {
+	txn := s.db.ReadTxn()
+
+	// Perform the binding rule lookup using the "id" index.
+	watchCh, existing, err := txn.FirstWatch(TableACLBindingRules, indexID, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("ACL binding rule lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLBindingRule), nil
+	}
+	return nil, nil
+}
+
+// GetACLBindingRulesByAuthMethod returns an iterator that contains all the
+// binding rules which belong to the named auth method.
+func (s *StateStore) GetACLBindingRulesByAuthMethod(
+	ws memdb.WatchSet, authMethod string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableACLBindingRules, indexAuthMethod, authMethod)
+	if err != nil {
+		return nil, fmt.Errorf("ACL binding rule lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetACLBindingRulesByIDPrefix is used to lookup binding rules using a prefix
+// to match on the ID.
+func (s *StateStore) GetACLBindingRulesByIDPrefix(ws memdb.WatchSet, idPrefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableACLBindingRules, indexID+"_prefix", idPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("ACL binding rule lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
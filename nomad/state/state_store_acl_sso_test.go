@@ -0,0 +1,207 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedAuthMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, mockedAuthMethods))
+
+	// Performing the same upsert again should be a no-op as the auth methods
+	// have not changed.
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 20, mockedAuthMethods))
+
+	ws := memdb.NewWatchSet()
+	index, err := testState.Index(TableACLAuthMethods)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+
+	// Update one of the auth methods and ensure the indexes are tracked
+	// correctly.
+	updatedAuthMethod := mockedAuthMethods[0].Copy()
+	updatedAuthMethod.Default = true
+	updatedAuthMethod.SetHash()
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 30, []*structs.ACLAuthMethod{updatedAuthMethod}))
+
+	authMethod, err := testState.GetACLAuthMethodByName(ws, updatedAuthMethod.Name)
+	require.NoError(t, err)
+	require.True(t, authMethod.Default)
+	require.Equal(t, uint64(10), authMethod.CreateIndex)
+	require.Equal(t, uint64(30), authMethod.ModifyIndex)
+
+	index, err = testState.Index(TableACLAuthMethods)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), index)
+
+	// The updated auth method should be returned as the default.
+	defaultAuthMethod, err := testState.GetDefaultACLAuthMethod(ws)
+	require.NoError(t, err)
+	require.Equal(t, updatedAuthMethod.Name, defaultAuthMethod.Name)
+}
+
+func TestStateStore_DeleteACLAuthMethods(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedAuthMethods := []*structs.ACLAuthMethod{mock.ACLAuthMethod(), mock.ACLAuthMethod()}
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 10, mockedAuthMethods))
+
+	// Create a binding rule for each auth method.
+	bindingRule1 := mock.ACLBindingRule()
+	bindingRule1.AuthMethod = mockedAuthMethods[0].Name
+	bindingRule2 := mock.ACLBindingRule()
+	bindingRule2.AuthMethod = mockedAuthMethods[1].Name
+	require.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []*structs.ACLBindingRule{bindingRule1, bindingRule2}, false))
+
+	// Deleting an auth method which does not exist should error and not
+	// delete anything.
+	err := testState.DeleteACLAuthMethods(
+		structs.MsgTypeTestSetup, 30, []string{mockedAuthMethods[0].Name, "not-an-auth-method"})
+	require.ErrorContains(t, err, "ACL auth method not found")
+
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetACLAuthMethods(ws)
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+
+	// Delete a single auth method and check the table indexes. The binding
+	// rule of the auth method should also be deleted.
+	require.NoError(t, testState.DeleteACLAuthMethods(structs.MsgTypeTestSetup, 40, []string{mockedAuthMethods[0].Name}))
+
+	authMethod, err := testState.GetACLAuthMethodByName(ws, mockedAuthMethods[0].Name)
+	require.NoError(t, err)
+	require.Nil(t, authMethod)
+
+	bindingRule, err := testState.GetACLBindingRule(ws, bindingRule1.ID)
+	require.NoError(t, err)
+	require.Nil(t, bindingRule)
+
+	bindingRule, err = testState.GetACLBindingRule(ws, bindingRule2.ID)
+	require.NoError(t, err)
+	require.NotNil(t, bindingRule)
+
+	index, err := testState.Index(TableACLAuthMethods)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), index)
+
+	index, err = testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), index)
+}
+
+func TestStateStore_UpsertACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Mock some binding rules and try to upsert them before the auth method
+	// exists. This should fail.
+	mockedBindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	err := testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedBindingRules, false)
+	require.ErrorContains(t, err, "ACL auth method not found")
+
+	// Allowing missing auth methods, as replication does, should succeed.
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedBindingRules, true))
+
+	// Upsert the auth method and perform the same upsert again, which should
+	// be a no-op as the binding rules have not changed.
+	authMethod := mock.ACLAuthMethod()
+	authMethod.Name = mockedBindingRules[0].AuthMethod
+	require.NoError(t, testState.UpsertACLAuthMethods(structs.MsgTypeTestSetup, 20, []*structs.ACLAuthMethod{authMethod}))
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 30, mockedBindingRules, false))
+
+	ws := memdb.NewWatchSet()
+	index, err := testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+
+	// Update one of the binding rules and ensure the indexes are tracked
+	// correctly.
+	updatedBindingRule := mockedBindingRules[0].Copy()
+	updatedBindingRule.Description = "updated-description"
+	updatedBindingRule.SetHash()
+	require.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLBindingRule{updatedBindingRule}, false))
+
+	bindingRule, err := testState.GetACLBindingRule(ws, updatedBindingRule.ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated-description", bindingRule.Description)
+	require.Equal(t, uint64(10), bindingRule.CreateIndex)
+	require.Equal(t, uint64(40), bindingRule.ModifyIndex)
+
+	index, err = testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), index)
+}
+
+func TestStateStore_DeleteACLBindingRules(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	mockedBindingRules := []*structs.ACLBindingRule{mock.ACLBindingRule(), mock.ACLBindingRule()}
+	require.NoError(t, testState.UpsertACLBindingRules(structs.MsgTypeTestSetup, 10, mockedBindingRules, true))
+
+	// Deleting a binding rule which does not exist should error and not
+	// delete anything.
+	err := testState.DeleteACLBindingRules(
+		structs.MsgTypeTestSetup, 20, []string{mockedBindingRules[0].ID, "not-a-binding-rule"})
+	require.ErrorContains(t, err, "ACL binding rule not found")
+
+	// Delete a single binding rule and check the table index.
+	require.NoError(t, testState.DeleteACLBindingRules(structs.MsgTypeTestSetup, 30, []string{mockedBindingRules[0].ID}))
+
+	ws := memdb.NewWatchSet()
+	bindingRule, err := testState.GetACLBindingRule(ws, mockedBindingRules[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, bindingRule)
+
+	index, err := testState.Index(TableACLBindingRules)
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), index)
+}
+
+func TestStateStore_GetACLBindingRulesByAuthMethod(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	bindingRule1 := mock.ACLBindingRule()
+	bindingRule2 := mock.ACLBindingRule()
+	bindingRule2.AuthMethod = "okta"
+	require.NoError(t, testState.UpsertACLBindingRules(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLBindingRule{bindingRule1, bindingRule2}, true))
+
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetACLBindingRulesByAuthMethod(ws, "okta")
+	require.NoError(t, err)
+
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.ACLBindingRule).ID)
+	}
+	require.Equal(t, []string{bindingRule2.ID}, ids)
+
+	// Perform a prefix lookup using the ID of the first binding rule.
+	iter, err = testState.GetACLBindingRulesByIDPrefix(ws, bindingRule1.ID[:8])
+	require.NoError(t, err)
+
+	ids = nil
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.ACLBindingRule).ID)
+	}
+	require.Equal(t, []string{bindingRule1.ID}, ids)
+}
@@ -0,0 +1,161 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// upsertACLRolePolicies creates the policies which mock.ACLRole links to.
+func upsertACLRolePolicies(t *testing.T, testState *StateStore, index uint64) {
+	policy1 := mock.ACLPolicy()
+	policy1.Name = "foo"
+	policy2 := mock.ACLPolicy()
+	policy2.Name = "bar"
+	require.NoError(t, testState.UpsertACLPolicies(
+		structs.MsgTypeTestSetup, index, []*structs.ACLPolicy{policy1, policy2}))
+}
+
+func TestStateStore_UpsertACLRoles(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Mock some ACL roles and try to upsert them before the linked policies
+	// exist. This should fail.
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	err := testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, mockedACLRoles, false)
+	require.ErrorContains(t, err, "policy not found")
+
+	// Allowing missing policies, as replication does, should succeed.
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 10, mockedACLRoles, true))
+
+	// Upsert the policies and perform the same upsert again, which should be
+	// a no-op as the roles have not changed.
+	upsertACLRolePolicies(t, testState, 20)
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 30, mockedACLRoles, false))
+
+	ws := memdb.NewWatchSet()
+	index, err := testState.Index(TableACLRoles)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+
+	// Update one of the roles and ensure the indexes are tracked correctly.
+	updatedRole := mockedACLRoles[0].Copy()
+	updatedRole.Description = "updated-description"
+	updatedRole.SetHash()
+	require.NoError(t, testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 40, []*structs.ACLRole{updatedRole}, false))
+
+	aclRole, err := testState.GetACLRoleByID(ws, updatedRole.ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated-description", aclRole.Description)
+	require.Equal(t, uint64(10), aclRole.CreateIndex)
+	require.Equal(t, uint64(40), aclRole.ModifyIndex)
+
+	index, err = testState.Index(TableACLRoles)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), index)
+
+	// Creating a new role using the name of an existing role should fail.
+	duplicateRole := mock.ACLRole()
+	duplicateRole.Name = mockedACLRoles[1].Name
+	err = testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 50, []*structs.ACLRole{duplicateRole}, false)
+	require.ErrorContains(t, err, "already exists")
+}
+
+func TestStateStore_DeleteACLRolesByID(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	upsertACLRolePolicies(t, testState, 10)
+
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 20, mockedACLRoles, false))
+
+	// Deleting a role which does not exist should error and not delete
+	// anything.
+	err := testState.DeleteACLRolesByID(
+		structs.MsgTypeTestSetup, 30, []string{mockedACLRoles[0].ID, "not-a-role"})
+	require.ErrorContains(t, err, "ACL role not found")
+
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetACLRoles(ws)
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+
+	// Delete a single role and check the table index.
+	require.NoError(t, testState.DeleteACLRolesByID(
+		structs.MsgTypeTestSetup, 40, []string{mockedACLRoles[0].ID}))
+
+	aclRole, err := testState.GetACLRoleByID(ws, mockedACLRoles[0].ID)
+	require.NoError(t, err)
+	require.Nil(t, aclRole)
+
+	index, err := testState.Index(TableACLRoles)
+	require.NoError(t, err)
+	require.Equal(t, uint64(40), index)
+}
+
+func TestStateStore_GetACLRoleByName(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	upsertACLRolePolicies(t, testState, 10)
+
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 20, mockedACLRoles, false))
+
+	ws := memdb.NewWatchSet()
+	aclRole, err := testState.GetACLRoleByName(ws, mockedACLRoles[1].Name)
+	require.NoError(t, err)
+	require.Equal(t, mockedACLRoles[1].ID, aclRole.ID)
+
+	aclRole, err = testState.GetACLRoleByName(ws, "not-a-role")
+	require.NoError(t, err)
+	require.Nil(t, aclRole)
+
+	// Renaming a role should free up the old name.
+	renamedRole := mockedACLRoles[1].Copy()
+	renamedRole.Name = "renamed-role"
+	renamedRole.SetHash()
+	require.NoError(t, testState.UpsertACLRoles(
+		structs.MsgTypeTestSetup, 30, []*structs.ACLRole{renamedRole}, false))
+
+	aclRole, err = testState.GetACLRoleByName(ws, mockedACLRoles[1].Name)
+	require.NoError(t, err)
+	require.Nil(t, aclRole)
+
+	aclRole, err = testState.GetACLRoleByName(ws, "renamed-role")
+	require.NoError(t, err)
+	require.Equal(t, mockedACLRoles[1].ID, aclRole.ID)
+}
+
+func TestStateStore_GetACLRoleByIDPrefix(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	upsertACLRolePolicies(t, testState, 10)
+
+	mockedACLRoles := []*structs.ACLRole{mock.ACLRole(), mock.ACLRole()}
+	mockedACLRoles[0].ID = "10000000-d8ce-1d27-e31c-3d4a6a9bd1d1"
+	mockedACLRoles[1].ID = "20000000-d8ce-1d27-e31c-3d4a6a9bd1d1"
+	require.NoError(t, testState.UpsertACLRoles(structs.MsgTypeTestSetup, 20, mockedACLRoles, false))
+
+	ws := memdb.NewWatchSet()
+	iter, err := testState.GetACLRoleByIDPrefix(ws, "1000")
+	require.NoError(t, err)
+
+	var aclRoles []*structs.ACLRole
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		aclRoles = append(aclRoles, raw.(*structs.ACLRole))
+	}
+	require.Len(t, aclRoles, 1)
+	require.Equal(t, mockedACLRoles[0].ID, aclRoles[0].ID)
+}
@@ -0,0 +1,139 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertEventSink is used to register or update an event sink. The delivery
+// progress of an existing sink is kept, while new sinks start with the
+// events published after their registration.
+func (s *StateStore) UpsertEventSink(msgType structs.MessageType, index uint64, sink *structs.EventSink) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableEventSinks, indexID, sink.ID)
+	if err != nil {
+		return fmt.Errorf("event sink lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		exist := existing.(*structs.EventSink)
+		sink.CreateIndex = exist.CreateIndex
+		sink.LatestIndex = exist.LatestIndex
+		sink.LatestIndexBatches = exist.LatestIndexBatches
+		sink.LostEventsIndex = exist.LostEventsIndex
+	} else {
+		sink.CreateIndex = index
+		sink.LatestIndex = index
+	}
+	sink.ModifyIndex = index
+
+	if err := txn.Insert(TableEventSinks, sink); err != nil {
+		return fmt.Errorf("event sink insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// DeleteEventSinks is responsible for batch deleting event sinks based on
+// their ID. An error is returned if a sink is not found.
+func (s *StateStore) DeleteEventSinks(msgType structs.MessageType, index uint64, ids []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		existing, err := txn.First(TableEventSinks, indexID, id)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("event sink %q not found", id)
+		}
+		if err := txn.Delete(TableEventSinks, existing); err != nil {
+			return fmt.Errorf("event sink deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// UpdateEventSinksProgress stores the delivery progress of each of the given
+// sinks. Sinks that no longer exist are skipped, and the progress of a sink
+// never moves backwards. The modify index of the sinks is left untouched as
+// their configuration did not change.
+func (s *StateStore) UpdateEventSinksProgress(msgType structs.MessageType, index uint64, progress map[string]*structs.EventSinkProgress) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for id, latest := range progress {
+		existing, err := txn.First(TableEventSinks, indexID, id)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		exist := existing.(*structs.EventSink)
+		if latest.LatestIndex < exist.LatestIndex ||
+			latest.LatestIndex == exist.LatestIndex &&
+				latest.LatestIndexBatches <= exist.LatestIndexBatches &&
+				latest.LostEventsIndex <= exist.LostEventsIndex {
+			continue
+		}
+
+		sink := exist.Copy()
+		sink.LatestIndex = latest.LatestIndex
+		sink.LatestIndexBatches = latest.LatestIndexBatches
+		sink.LostEventsIndex = helper.Max(exist.LostEventsIndex, latest.LostEventsIndex)
+		if err := txn.Insert(TableEventSinks, sink); err != nil {
+			return fmt.Errorf("event sink insert failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// EventSinks returns an iterator that contains all event sinks stored
+// within state.
+func (s *StateStore) EventSinks(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableEventSinks, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("event sink lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// EventSinkByID returns a single event sink specified by its ID. The sink
+// object will be nil, if no matching entry was found; it is the
+// responsibility of the caller to check for this.
+func (s *StateStore) EventSinkByID(ws memdb.WatchSet, id string) (*structs.EventSink, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableEventSinks, indexID, id)
+	if err != nil {
+		return nil, fmt.Errorf("event sink lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.EventSink), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertEventSink(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	sink := mock.EventSink()
+
+	// Insert the sink and ensure the indexes are set.
+	ws := memdb.NewWatchSet()
+	_, err := testState.EventSinks(ws)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.UpsertEventSink(structs.MsgTypeTestSetup, 10, sink))
+	require.True(t, watchFired(ws))
+
+	out, err := testState.EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(10), out.ModifyIndex)
+	require.Equal(t, uint64(10), out.LatestIndex)
+
+	index, err := testState.Index(TableEventSinks)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+
+	// Store the progress of the sink, unknown sinks are skipped.
+	require.NoError(t, testState.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 20,
+		map[string]*structs.EventSinkProgress{
+			sink.ID:   {LatestIndex: 15, LatestIndexBatches: 1},
+			"unknown": {LatestIndex: 15, LatestIndexBatches: 1},
+		}))
+
+	out, err = testState.EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), out.LatestIndex)
+	require.Equal(t, uint64(10), out.ModifyIndex)
+
+	// The progress never moves backwards.
+	require.NoError(t, testState.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 21,
+		map[string]*structs.EventSinkProgress{sink.ID: {LatestIndex: 12, LatestIndexBatches: 1}}))
+
+	out, err = testState.EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), out.LatestIndex)
+
+	// Further sets of events with the same index, and lost events, are
+	// recorded.
+	require.NoError(t, testState.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 22,
+		map[string]*structs.EventSinkProgress{sink.ID: {LatestIndex: 15, LatestIndexBatches: 2}}))
+	require.NoError(t, testState.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 23,
+		map[string]*structs.EventSinkProgress{sink.ID: {LatestIndex: 15, LostEventsIndex: 15}}))
+
+	out, err = testState.EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), out.LatestIndex)
+	require.Zero(t, out.LatestIndexBatches)
+	require.Equal(t, uint64(15), out.LostEventsIndex)
+
+	// Updating the sink keeps its progress.
+	updated := sink.Copy()
+	updated.Address = "http://127.0.0.1:9090/events"
+	updated.LatestIndex = 0
+	require.NoError(t, testState.UpsertEventSink(structs.MsgTypeTestSetup, 30, updated))
+
+	out, err = testState.EventSinkByID(nil, sink.ID)
+	require.NoError(t, err)
+	require.Equal(t, "http://127.0.0.1:9090/events", out.Address)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(30), out.ModifyIndex)
+	require.Equal(t, uint64(15), out.LatestIndex)
+	require.Equal(t, uint64(15), out.LostEventsIndex)
+}
+
+func TestStateStore_DeleteEventSinks(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	sink1 := mock.EventSink()
+	sink2 := mock.EventSink()
+	require.NoError(t, testState.UpsertEventSink(structs.MsgTypeTestSetup, 10, sink1))
+	require.NoError(t, testState.UpsertEventSink(structs.MsgTypeTestSetup, 11, sink2))
+
+	// Deleting an unknown sink fails and deletes nothing.
+	err := testState.DeleteEventSinks(structs.MsgTypeTestSetup, 20, []string{sink1.ID, "unknown"})
+	require.EqualError(t, err, `event sink "unknown" not found`)
+
+	iter, err := testState.EventSinks(nil)
+	require.NoError(t, err)
+	require.Len(t, collectEventSinks(iter), 2)
+
+	require.NoError(t, testState.DeleteEventSinks(structs.MsgTypeTestSetup, 20, []string{sink1.ID}))
+
+	out, err := testState.EventSinkByID(nil, sink1.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	iter, err = testState.EventSinks(nil)
+	require.NoError(t, err)
+	sinks := collectEventSinks(iter)
+	require.Len(t, sinks, 1)
+	require.Equal(t, sink2.ID, sinks[0].ID)
+
+	index, err := testState.Index(TableEventSinks)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+}
+
+func collectEventSinks(iter memdb.ResultIterator) []*structs.EventSink {
+	var sinks []*structs.EventSink
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sinks = append(sinks, raw.(*structs.EventSink))
+	}
+	return sinks
+}
@@ -0,0 +1,264 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertHostVolumes is used to insert or update a number of dynamic host
+// volumes. A volume is ready as soon as its node has fingerprinted it, and
+// pending otherwise. Any error means no entries will be committed.
+func (s *StateStore) UpsertHostVolumes(msgType structs.MessageType, index uint64, vols []*structs.HostVolume) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, vol := range vols {
+		if err := upsertHostVolumeTxn(txn, index, vol); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableHostVolumes, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// upsertHostVolumeTxn inserts a single host volume into the state store
+// using the provided write transaction. It is the responsibility of the
+// caller to update the index table.
+func upsertHostVolumeTxn(txn *txn, index uint64, vol *structs.HostVolume) error {
+	existing, err := txn.First(TableHostVolumes, indexID, vol.Namespace, vol.ID)
+	if err != nil {
+		return fmt.Errorf("host volume lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		vol.CreateIndex = existing.(*structs.HostVolume).CreateIndex
+	} else {
+		vol.CreateIndex = index
+	}
+	vol.ModifyIndex = index
+
+	// Volumes are fingerprinted by name, so the name must be unique among
+	// the dynamic and static host volumes of the node. This is only enforced
+	// here, as the checks of the RPC race with other writes.
+	raw, err := txn.First("nodes", "id", vol.NodeID)
+	if err != nil {
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	var node *structs.Node
+	if raw != nil {
+		node = raw.(*structs.Node)
+		if hv, ok := node.HostVolumes[vol.Name]; ok && hv.ID != vol.ID {
+			return fmt.Errorf("host volume %q already exists on node %q", vol.Name, vol.NodeID)
+		}
+	}
+
+	iter, err := txn.Get(TableHostVolumes, indexNodeID, vol.NodeID)
+	if err != nil {
+		return fmt.Errorf("host volume lookup failed: %v", err)
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		other := raw.(*structs.HostVolume)
+		if other.ID != vol.ID && other.Name == vol.Name {
+			return fmt.Errorf("host volume %q already exists on node %q", vol.Name, vol.NodeID)
+		}
+	}
+
+	vol.State = structs.HostVolumeStatePending
+	if node != nil && nodeHasHostVolume(node, vol) {
+		vol.State = structs.HostVolumeStateReady
+	}
+
+	// Claims are denormalized from the allocations when reading the volume
+	vol.Allocations = nil
+
+	if err := txn.Insert(TableHostVolumes, vol); err != nil {
+		return fmt.Errorf("host volume insert failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteHostVolumes is responsible for batch deleting dynamic host volumes
+// based on their ID. Volumes that are not found are ignored, and an error is
+// returned if a volume is still claimed by an allocation, in which case no
+// volume is deleted.
+func (s *StateStore) DeleteHostVolumes(msgType structs.MessageType, index uint64, namespace string, ids []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		existing, err := txn.First(TableHostVolumes, indexID, namespace, id)
+		if err != nil {
+			return fmt.Errorf("host volume lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		vol := existing.(*structs.HostVolume)
+		claims, err := hostVolumeClaimsTxn(txn, nil, vol)
+		if err != nil {
+			return err
+		}
+		if len(claims) != 0 {
+			return fmt.Errorf("host volume %q is in use by %d allocation(s)", id, len(claims))
+		}
+
+		if err := txn.Delete(TableHostVolumes, existing); err != nil {
+			return fmt.Errorf("host volume deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableHostVolumes, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// HostVolumes returns an iterator that contains all dynamic host volumes
+// stored within state.
+func (s *StateStore) HostVolumes(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableHostVolumes, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("host volume lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// HostVolumesByIDPrefix returns an iterator that contains all dynamic host
+// volumes of a namespace whose ID starts with the given prefix.
+func (s *StateStore) HostVolumesByIDPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableHostVolumes, indexID+"_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("host volume lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// HostVolumesByNodeID returns an iterator that contains all dynamic host
+// volumes created on a node.
+func (s *StateStore) HostVolumesByNodeID(ws memdb.WatchSet, nodeID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableHostVolumes, indexNodeID, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("host volume lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// HostVolumeByID returns a single dynamic host volume specified by its
+// namespace and ID, along with the allocations that claim it if withAllocs is
+// set. The volume object will be nil, if no matching entry was found; it is
+// the responsibility of the caller to check for this.
+func (s *StateStore) HostVolumeByID(ws memdb.WatchSet, namespace, id string, withAllocs bool) (*structs.HostVolume, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableHostVolumes, indexID, namespace, id)
+	if err != nil {
+		return nil, fmt.Errorf("host volume lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	vol := existing.(*structs.HostVolume)
+	if !withAllocs {
+		return vol, nil
+	}
+
+	claims, err := hostVolumeClaimsTxn(txn, ws, vol)
+	if err != nil {
+		return nil, err
+	}
+	vol = vol.Copy()
+	vol.Allocations = claims
+	return vol, nil
+}
+
+// hostVolumeClaimsTxn returns the allocations on the node of the volume that
+// claim it, that is the allocations of the namespace of the volume that are
+// not terminal on the client and whose task group requests a host volume with
+// the name of the volume.
+func hostVolumeClaimsTxn(txn ReadTxn, ws memdb.WatchSet, vol *structs.HostVolume) ([]*structs.AllocListStub, error) {
+	allocs, err := allocsByNodeTxn(txn, ws, vol.NodeID)
+	if err != nil {
+		return nil, fmt.Errorf("alloc lookup failed: %v", err)
+	}
+
+	var claims []*structs.AllocListStub
+	for _, alloc := range allocs {
+		if alloc.Namespace != vol.Namespace || alloc.ClientTerminalStatus() || alloc.Job == nil {
+			continue
+		}
+		tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+		if tg == nil {
+			continue
+		}
+		for _, req := range tg.Volumes {
+			if req.Type == structs.VolumeTypeHost && req.Source == vol.Name {
+				claims = append(claims, alloc.Stub(nil))
+				break
+			}
+		}
+	}
+	return claims, nil
+}
+
+// updateHostVolumesForNodeTxn marks the pending host volumes of a node as
+// ready once the node has fingerprinted them. It is called when the node is
+// updated and updates the index table itself.
+func updateHostVolumesForNodeTxn(txn *txn, index uint64, node *structs.Node) error {
+	iter, err := txn.Get(TableHostVolumes, indexNodeID, node.ID)
+	if err != nil {
+		return fmt.Errorf("host volume lookup failed: %v", err)
+	}
+
+	var ready []*structs.HostVolume
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		vol := raw.(*structs.HostVolume)
+		if vol.State == structs.HostVolumeStatePending && nodeHasHostVolume(node, vol) {
+			ready = append(ready, vol)
+		}
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+
+	for _, vol := range ready {
+		vol = vol.Copy()
+		vol.State = structs.HostVolumeStateReady
+		vol.ModifyIndex = index
+		if err := txn.Insert(TableHostVolumes, vol); err != nil {
+			return fmt.Errorf("host volume insert failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableHostVolumes, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// nodeHasHostVolume returns whether the node has fingerprinted the dynamic
+// host volume.
+func nodeHasHostVolume(node *structs.Node, vol *structs.HostVolume) bool {
+	hv, ok := node.HostVolumes[vol.Name]
+	return ok && hv.ID == vol.ID
+}
@@ -0,0 +1,216 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func mockHostVolume(node *structs.Node, name string) *structs.HostVolume {
+	vol := &structs.HostVolume{
+		ID:       uuid.Generate(),
+		Name:     name,
+		NodeID:   node.ID,
+		HostPath: "/var/nomad/host_volumes/" + name,
+	}
+	vol.Canonicalize()
+	return vol
+}
+
+func TestStateStore_UpsertHostVolumes(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	node := mock.Node()
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	vol1 := mockHostVolume(node, "data")
+	vol2 := mockHostVolume(node, "logs")
+
+	// Insert the volumes and ensure the indexes are set.
+	ws := memdb.NewWatchSet()
+	_, err := testState.HostVolumes(ws)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.UpsertHostVolumes(
+		structs.MsgTypeTestSetup, 20, []*structs.HostVolume{vol1, vol2}))
+	require.True(t, watchFired(ws))
+
+	out, err := testState.HostVolumeByID(nil, vol1.Namespace, vol1.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+	require.Equal(t, structs.HostVolumeStatePending, out.State)
+
+	index, err := testState.Index(TableHostVolumes)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+
+	// Names are unique on a node.
+	dup := mockHostVolume(node, "data")
+	require.EqualError(t, testState.UpsertHostVolumes(
+		structs.MsgTypeTestSetup, 30, []*structs.HostVolume{dup}),
+		`host volume "data" already exists on node "`+node.ID+`"`)
+
+	// Including the static host volumes of the node.
+	staticNode := mock.Node()
+	staticNode.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"shared": {Name: "shared", Path: "/srv/shared"},
+	}
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 25, staticNode))
+	static := mockHostVolume(staticNode, "shared")
+	require.EqualError(t, testState.UpsertHostVolumes(
+		structs.MsgTypeTestSetup, 30, []*structs.HostVolume{static}),
+		`host volume "shared" already exists on node "`+staticNode.ID+`"`)
+
+	// The volume is ready once the node has fingerprinted it.
+	ws = memdb.NewWatchSet()
+	_, err = testState.HostVolumeByID(ws, vol1.Namespace, vol1.ID, false)
+	require.NoError(t, err)
+
+	node = node.Copy()
+	node.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"data": {Name: "data", Path: vol1.HostPath, ID: vol1.ID},
+	}
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 40, node))
+	require.True(t, watchFired(ws))
+
+	out, err = testState.HostVolumeByID(nil, vol1.Namespace, vol1.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), out.CreateIndex)
+	require.Equal(t, uint64(40), out.ModifyIndex)
+	require.Equal(t, structs.HostVolumeStateReady, out.State)
+
+	out, err = testState.HostVolumeByID(nil, vol2.Namespace, vol2.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, structs.HostVolumeStatePending, out.State)
+
+	// A volume already fingerprinted is ready as soon as it's stored.
+	vol3 := mockHostVolume(node, "cache")
+	node = node.Copy()
+	node.HostVolumes["cache"] = &structs.ClientHostVolumeConfig{
+		Name: "cache",
+		Path: vol3.HostPath,
+		ID:   vol3.ID,
+	}
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 50, node))
+	require.NoError(t, testState.UpsertHostVolumes(
+		structs.MsgTypeTestSetup, 60, []*structs.HostVolume{vol3}))
+
+	out, err = testState.HostVolumeByID(nil, vol3.Namespace, vol3.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, structs.HostVolumeStateReady, out.State)
+}
+
+func TestStateStore_HostVolumesLookups(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	node1 := mock.Node()
+	node2 := mock.Node()
+	vol1 := mockHostVolume(node1, "data")
+	vol2 := mockHostVolume(node2, "data")
+	vol2.ID = vol1.ID[:8] + uuid.Generate()[8:]
+	vol3 := mockHostVolume(node2, "logs")
+	vol3.Namespace = "other"
+
+	require.NoError(t, testState.UpsertHostVolumes(
+		structs.MsgTypeTestSetup, 10, []*structs.HostVolume{vol1, vol2, vol3}))
+
+	collect := func(iter memdb.ResultIterator) []string {
+		var ids []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			ids = append(ids, raw.(*structs.HostVolume).ID)
+		}
+		return ids
+	}
+
+	iter, err := testState.HostVolumes(nil)
+	require.NoError(t, err)
+	require.Len(t, collect(iter), 3)
+
+	iter, err = testState.HostVolumesByIDPrefix(nil, structs.DefaultNamespace, vol1.ID[:8])
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{vol1.ID, vol2.ID}, collect(iter))
+
+	iter, err = testState.HostVolumesByIDPrefix(nil, "other", vol1.ID[:8])
+	require.NoError(t, err)
+	require.Empty(t, collect(iter))
+
+	iter, err = testState.HostVolumesByNodeID(nil, node2.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{vol2.ID, vol3.ID}, collect(iter))
+}
+
+func TestStateStore_DeleteHostVolumes(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	node := mock.Node()
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 10, node))
+
+	vol := mockHostVolume(node, "data")
+	require.NoError(t, testState.UpsertHostVolumes(
+		structs.MsgTypeTestSetup, 20, []*structs.HostVolume{vol}))
+
+	// An allocation whose task group requests the volume claims it.
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "data"},
+	}
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 30, alloc.Job))
+
+	// Allocations of other namespaces don't claim the volume.
+	otherNS := mock.Alloc()
+	otherNS.Namespace = "other"
+	otherNS.Job.Namespace = "other"
+	otherNS.NodeID = node.ID
+	otherNS.Job.TaskGroups[0].Volumes = alloc.Job.TaskGroups[0].Volumes
+	require.NoError(t, testState.UpsertNamespaces(structs.MsgTypeTestSetup, 35, []*structs.Namespace{{Name: "other"}}))
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 36, otherNS.Job))
+
+	require.NoError(t, testState.UpsertAllocs(
+		structs.MsgTypeTestSetup, 40, []*structs.Allocation{alloc, otherNS}))
+
+	out, err := testState.HostVolumeByID(nil, vol.Namespace, vol.ID, true)
+	require.NoError(t, err)
+	require.True(t, out.InUse())
+	require.Len(t, out.Allocations, 1)
+	require.Equal(t, alloc.ID, out.Allocations[0].ID)
+
+	err = testState.DeleteHostVolumes(
+		structs.MsgTypeTestSetup, 50, vol.Namespace, []string{vol.ID})
+	require.EqualError(t, err, `host volume "`+vol.ID+`" is in use by 1 allocation(s)`)
+
+	// Once the allocation is terminal the volume can be deleted.
+	alloc = alloc.Copy()
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, testState.UpdateAllocsFromClient(
+		structs.MsgTypeTestSetup, 60, []*structs.Allocation{alloc}))
+
+	out, err = testState.HostVolumeByID(nil, vol.Namespace, vol.ID, true)
+	require.NoError(t, err)
+	require.False(t, out.InUse())
+
+	ws := memdb.NewWatchSet()
+	_, err = testState.HostVolumeByID(ws, vol.Namespace, vol.ID, false)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.DeleteHostVolumes(
+		structs.MsgTypeTestSetup, 70, vol.Namespace, []string{vol.ID, uuid.Generate()}))
+	require.True(t, watchFired(ws))
+
+	out, err = testState.HostVolumeByID(nil, vol.Namespace, vol.ID, false)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	index, err := testState.Index(TableHostVolumes)
+	require.NoError(t, err)
+	require.Equal(t, uint64(70), index)
+}
@@ -0,0 +1,135 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertMaintenancePlans is used to insert or update a number of maintenance
+// plans. Updates of existing plans must be based on their current version,
+// as given by their modify index, so that concurrent updates by the leader
+// and operators don't overwrite each other. Any error means no entries will
+// be committed.
+func (s *StateStore) UpsertMaintenancePlans(
+	msgType structs.MessageType, index uint64, plans []*structs.MaintenancePlan) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, plan := range plans {
+		if err := upsertMaintenancePlanTxn(txn, index, plan); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableMaintenancePlans, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// upsertMaintenancePlanTxn inserts a single maintenance plan into the state
+// store using the provided write transaction. It is the responsibility of
+// the caller to update the index table.
+func upsertMaintenancePlanTxn(txn *txn, index uint64, plan *structs.MaintenancePlan) error {
+	existing, err := txn.First(TableMaintenancePlans, indexID, plan.ID)
+	if err != nil {
+		return fmt.Errorf("maintenance plan lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		exist := existing.(*structs.MaintenancePlan)
+		if exist.ModifyIndex != plan.ModifyIndex {
+			return fmt.Errorf("%w: %s", structs.ErrMaintenancePlanModified, plan.ID)
+		}
+		plan.CreateIndex = exist.CreateIndex
+		plan.ModifyIndex = index
+	} else {
+		plan.CreateIndex = index
+		plan.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableMaintenancePlans, plan); err != nil {
+		return fmt.Errorf("maintenance plan insert failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteMaintenancePlans is responsible for batch deleting maintenance plans
+// based on their ID. An error is returned if a plan is not found or still
+// running.
+func (s *StateStore) DeleteMaintenancePlans(msgType structs.MessageType, index uint64, ids []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		existing, err := txn.First(TableMaintenancePlans, indexID, id)
+		if err != nil {
+			return fmt.Errorf("maintenance plan lookup failed: %v", err)
+		}
+		if existing == nil {
+			return errors.New("maintenance plan not found")
+		}
+		if !existing.(*structs.MaintenancePlan).Terminal() {
+			return fmt.Errorf("maintenance plan %q is running and can not be deleted", id)
+		}
+
+		if err := txn.Delete(TableMaintenancePlans, existing); err != nil {
+			return fmt.Errorf("maintenance plan deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableMaintenancePlans, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// MaintenancePlans returns an iterator that contains all maintenance plans
+// stored within state.
+func (s *StateStore) MaintenancePlans(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableMaintenancePlans, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance plan lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// MaintenancePlansByIDPrefix returns an iterator that contains all
+// maintenance plans whose ID starts with the given prefix.
+func (s *StateStore) MaintenancePlansByIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableMaintenancePlans, indexID+"_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance plan lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// MaintenancePlanByID returns a single maintenance plan specified by its ID.
+// The plan object will be nil, if no matching entry was found; it is the
+// responsibility of the caller to check for this.
+func (s *StateStore) MaintenancePlanByID(ws memdb.WatchSet, id string) (*structs.MaintenancePlan, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableMaintenancePlans, indexID, id)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance plan lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.MaintenancePlan), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,136 @@
+package state
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func mockMaintenancePlan() *structs.MaintenancePlan {
+	plan := &structs.MaintenancePlan{
+		ID:     uuid.Generate(),
+		Name:   "maintenance",
+		Status: structs.MaintenancePlanStatusRunning,
+	}
+	plan.Canonicalize()
+	plan.SetNodes([]*structs.Node{mock.Node(), mock.Node()}, time.Now())
+	return plan
+}
+
+func TestStateStore_UpsertMaintenancePlans(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	plan1 := mockMaintenancePlan()
+	plan2 := mockMaintenancePlan()
+
+	// Insert the plans and ensure the indexes are set.
+	ws := memdb.NewWatchSet()
+	_, err := testState.MaintenancePlans(ws)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.UpsertMaintenancePlans(
+		structs.MsgTypeTestSetup, 10, []*structs.MaintenancePlan{plan1, plan2}))
+	require.True(t, watchFired(ws))
+
+	out, err := testState.MaintenancePlanByID(nil, plan1.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(10), out.ModifyIndex)
+
+	index, err := testState.Index(TableMaintenancePlans)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+
+	// Update a copy of the current version.
+	updated := out.Copy()
+	updated.Nodes[0].Status = structs.MaintenanceNodeStatusDraining
+	require.NoError(t, testState.UpsertMaintenancePlans(
+		structs.MsgTypeTestSetup, 20, []*structs.MaintenancePlan{updated}))
+
+	out, err = testState.MaintenancePlanByID(nil, plan1.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+	require.Equal(t, structs.MaintenanceNodeStatusDraining, out.Nodes[0].Status)
+
+	// Updates of an outdated version are rejected.
+	stale := out.Copy()
+	stale.ModifyIndex = 10
+	stale.Status = structs.MaintenancePlanStatusCancelled
+	err = testState.UpsertMaintenancePlans(
+		structs.MsgTypeTestSetup, 30, []*structs.MaintenancePlan{stale})
+	require.True(t, errors.Is(err, structs.ErrMaintenancePlanModified))
+
+	out, err = testState.MaintenancePlanByID(nil, plan1.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+	require.Equal(t, structs.MaintenancePlanStatusRunning, out.Status)
+}
+
+func TestStateStore_MaintenancePlansByIDPrefix(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	plan1 := mockMaintenancePlan()
+	plan1.ID = "aaaaaaaa-7bfb-395d-eb95-0685af2176b2"
+	plan2 := mockMaintenancePlan()
+	plan2.ID = "aaaabbbb-7bfb-395d-eb95-0685af2176b2"
+	require.NoError(t, testState.UpsertMaintenancePlans(
+		structs.MsgTypeTestSetup, 10, []*structs.MaintenancePlan{plan1, plan2}))
+
+	gatherIDs := func(prefix string) []string {
+		iter, err := testState.MaintenancePlansByIDPrefix(nil, prefix)
+		require.NoError(t, err)
+
+		var ids []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			ids = append(ids, raw.(*structs.MaintenancePlan).ID)
+		}
+		return ids
+	}
+
+	require.ElementsMatch(t, []string{plan1.ID, plan2.ID}, gatherIDs("aaaa"))
+	require.Equal(t, []string{plan2.ID}, gatherIDs("aaaabb"))
+	require.Empty(t, gatherIDs("bbbb"))
+}
+
+func TestStateStore_DeleteMaintenancePlans(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	running := mockMaintenancePlan()
+	complete := mockMaintenancePlan()
+	complete.Status = structs.MaintenancePlanStatusComplete
+	require.NoError(t, testState.UpsertMaintenancePlans(
+		structs.MsgTypeTestSetup, 10, []*structs.MaintenancePlan{running, complete}))
+
+	// Running plans can't be deleted.
+	err := testState.DeleteMaintenancePlans(structs.MsgTypeTestSetup, 20, []string{running.ID})
+	require.EqualError(t, err, `maintenance plan "`+running.ID+`" is running and can not be deleted`)
+
+	// Unknown plans can't be deleted.
+	err = testState.DeleteMaintenancePlans(structs.MsgTypeTestSetup, 20, []string{uuid.Generate()})
+	require.EqualError(t, err, "maintenance plan not found")
+
+	require.NoError(t, testState.DeleteMaintenancePlans(structs.MsgTypeTestSetup, 20, []string{complete.ID}))
+
+	out, err := testState.MaintenancePlanByID(nil, complete.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = testState.MaintenancePlanByID(nil, running.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	index, err := testState.Index(TableMaintenancePlans)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+}
@@ -0,0 +1,248 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// nodePoolInit ensures the built-in node pools exist. This is safe to do
+// every time we create the state store, for the same reasons as the default
+// namespace initialization; any restored snapshot overwrites them.
+func (s *StateStore) nodePoolInit() error {
+	allNodePool := &structs.NodePool{
+		Name:        structs.NodePoolAll,
+		Description: structs.NodePoolAllDescription,
+	}
+	defaultNodePool := &structs.NodePool{
+		Name:        structs.NodePoolDefault,
+		Description: structs.NodePoolDefaultDescription,
+	}
+
+	txn := s.db.WriteTxn(1)
+	defer txn.Abort()
+
+	for _, pool := range []*structs.NodePool{allNodePool, defaultNodePool} {
+		if _, err := s.upsertNodePoolTxn(1, txn, pool); err != nil {
+			return fmt.Errorf("inserting node pool %q failed: %v", pool.Name, err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodePools, 1}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// UpsertNodePools is used to insert a number of node pools into the state
+// store. It uses a single write transaction for efficiency, however, any
+// error means no entries will be committed.
+func (s *StateStore) UpsertNodePools(
+	msgType structs.MessageType, index uint64, pools []*structs.NodePool) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	// updated tracks whether any inserts have been made. This allows us to
+	// skip updating the index table if we do not need to.
+	var updated bool
+
+	for _, pool := range pools {
+		poolUpdated, err := s.upsertNodePoolTxn(index, txn, pool)
+		if err != nil {
+			return err
+		}
+		updated = updated || poolUpdated
+	}
+
+	// If we did not perform any inserts, exit early.
+	if !updated {
+		return nil
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// upsertNodePoolTxn inserts a single node pool into the state store using
+// the provided write transaction. It is the responsibility of the caller to
+// update the index table.
+func (s *StateStore) upsertNodePoolTxn(index uint64, txn *txn, pool *structs.NodePool) (bool, error) {
+
+	// Ensure the node pool hash is not zero to provide defense in depth.
+	// This should be done outside the state store, so we do not spend time
+	// here and thus Raft, when it can be avoided.
+	if len(pool.Hash) == 0 {
+		pool.SetHash()
+	}
+
+	existing, err := txn.First(TableNodePools, indexID, pool.Name)
+	if err != nil {
+		return false, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		exist := existing.(*structs.NodePool)
+		if string(exist.Hash) == string(pool.Hash) {
+			return false, nil
+		}
+		pool.CreateIndex = exist.CreateIndex
+		pool.ModifyIndex = index
+	} else {
+		pool.CreateIndex = index
+		pool.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableNodePools, pool); err != nil {
+		return false, fmt.Errorf("node pool insert failed: %v", err)
+	}
+	return true, nil
+}
+
+// upsertNodePoolForNodeTxn creates the node pool of a node being registered
+// if it does not exist yet, so clients may declare new pools in their
+// configuration without the pool being created beforehand.
+func upsertNodePoolForNodeTxn(txn *txn, index uint64, poolName string) error {
+	if poolName == "" {
+		return nil
+	}
+
+	existing, err := txn.First(TableNodePools, indexID, poolName)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	if existing != nil {
+		return nil
+	}
+
+	pool := &structs.NodePool{
+		Name:        poolName,
+		CreateIndex: index,
+		ModifyIndex: index,
+	}
+	pool.SetHash()
+
+	if err := txn.Insert(TableNodePools, pool); err != nil {
+		return fmt.Errorf("node pool insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteNodePools is responsible for batch deleting node pools based on
+// their name. An error is returned if a node pool is built-in, not found,
+// or still in use by nodes or non-terminal jobs.
+func (s *StateStore) DeleteNodePools(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First(TableNodePools, indexID, name)
+		if err != nil {
+			return fmt.Errorf("node pool lookup failed: %v", err)
+		}
+		if existing == nil {
+			return errors.New("node pool not found")
+		}
+
+		pool := existing.(*structs.NodePool)
+		if pool.IsBuiltIn() {
+			return fmt.Errorf("built-in node pool %q can not be deleted", name)
+		}
+
+		if err := nodePoolInUseTxn(txn, name); err != nil {
+			return err
+		}
+
+		if err := txn.Delete(TableNodePools, existing); err != nil {
+			return fmt.Errorf("node pool deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodePools, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// nodePoolInUseTxn returns an error if the node pool has nodes registered in
+// it or is used by a non-terminal job.
+func nodePoolInUseTxn(txn *txn, name string) error {
+	nodeIter, err := txn.Get("nodes", "id")
+	if err != nil {
+		return fmt.Errorf("node lookup failed: %v", err)
+	}
+	for raw := nodeIter.Next(); raw != nil; raw = nodeIter.Next() {
+		node := raw.(*structs.Node)
+		if node.NodePool == name {
+			return fmt.Errorf("node pool %q has at least one node %q. "+
+				"All nodes must be removed from the node pool before it can be deleted", name, node.ID)
+		}
+	}
+
+	jobIter, err := txn.Get("jobs", "id")
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	for raw := jobIter.Next(); raw != nil; raw = jobIter.Next() {
+		job := raw.(*structs.Job)
+		if job.NodePool == name && job.Status != structs.JobStatusDead {
+			return fmt.Errorf("node pool %q is used by at least one non-terminal job %q. "+
+				"All jobs must be terminal in node pool before it can be deleted", name, job.ID)
+		}
+	}
+	return nil
+}
+
+// NodePools returns an iterator that contains all node pools stored within
+// state.
+func (s *StateStore) NodePools(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNodePools, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// NodePoolsByNamePrefix returns an iterator that contains all node pools
+// whose name starts with the given prefix.
+func (s *StateStore) NodePoolsByNamePrefix(ws memdb.WatchSet, namePrefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNodePools, indexID+"_prefix", namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// NodePoolByName returns a single node pool specified by the input name.
+// The node pool object will be nil, if no matching entry was found; it is
+// the responsibility of the caller to check for this.
+func (s *StateStore) NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableNodePools, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.NodePool), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,191 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_NodePoolInit(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// The built-in node pools are always present.
+	for _, name := range []string{structs.NodePoolAll, structs.NodePoolDefault} {
+		pool, err := testState.NodePoolByName(nil, name)
+		require.NoError(t, err)
+		require.NotNil(t, pool)
+		require.True(t, pool.IsBuiltIn())
+	}
+
+	iter, err := testState.NodePools(memdb.NewWatchSet())
+	require.NoError(t, err)
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+}
+
+func TestStateStore_UpsertNodePools(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	pool1 := &structs.NodePool{Name: "dev", Description: "development"}
+	pool2 := &structs.NodePool{Name: "prod", Description: "production"}
+
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := testState.NodePoolByName(ws, pool1.Name)
+	require.NoError(t, err)
+
+	require.NoError(t, testState.UpsertNodePools(structs.MsgTypeTestSetup, 1000, []*structs.NodePool{pool1, pool2}))
+	require.True(t, watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := testState.NodePoolByName(ws, pool1.Name)
+	require.NoError(t, err)
+	require.Equal(t, pool1, out)
+	require.EqualValues(t, 1000, out.CreateIndex)
+	require.EqualValues(t, 1000, out.ModifyIndex)
+
+	index, err := testState.Index(TableNodePools)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+
+	// Upserting an unchanged node pool is a no-op.
+	unchanged := &structs.NodePool{Name: "dev", Description: "development"}
+	require.NoError(t, testState.UpsertNodePools(structs.MsgTypeTestSetup, 1001, []*structs.NodePool{unchanged}))
+	require.False(t, watchFired(ws))
+
+	index, err = testState.Index(TableNodePools)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+
+	// Updating a node pool keeps its create index.
+	updated := &structs.NodePool{Name: "dev", Description: "updated"}
+	require.NoError(t, testState.UpsertNodePools(structs.MsgTypeTestSetup, 1002, []*structs.NodePool{updated}))
+	require.True(t, watchFired(ws))
+
+	out, err = testState.NodePoolByName(nil, "dev")
+	require.NoError(t, err)
+	require.Equal(t, "updated", out.Description)
+	require.EqualValues(t, 1000, out.CreateIndex)
+	require.EqualValues(t, 1002, out.ModifyIndex)
+
+	// Prefix listing only returns the matching node pools.
+	iter, err := testState.NodePoolsByNamePrefix(nil, "pr")
+	require.NoError(t, err)
+
+	var names []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		names = append(names, raw.(*structs.NodePool).Name)
+	}
+	require.Equal(t, []string{"prod"}, names)
+}
+
+func TestStateStore_UpsertNode_CreatesNodePool(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	node := mock.Node()
+	node.NodePool = "dev"
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	pool, err := testState.NodePoolByName(nil, "dev")
+	require.NoError(t, err)
+	require.NotNil(t, pool)
+	require.EqualValues(t, 1000, pool.CreateIndex)
+
+	index, err := testState.Index(TableNodePools)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+
+	// Registering more nodes in an existing node pool does not modify it.
+	node2 := mock.Node()
+	node2.NodePool = "dev"
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
+
+	pool, err = testState.NodePoolByName(nil, "dev")
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, pool.ModifyIndex)
+}
+
+func TestStateStore_DeleteNodePools(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	pools := []*structs.NodePool{
+		{Name: "unused"},
+		{Name: "with-node"},
+		{Name: "with-job"},
+		{Name: "with-dead-job"},
+	}
+	require.NoError(t, testState.UpsertNodePools(structs.MsgTypeTestSetup, 1000, pools))
+
+	node := mock.Node()
+	node.NodePool = "with-node"
+	require.NoError(t, testState.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+
+	job := mock.Job()
+	job.NodePool = "with-job"
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 1002, job))
+
+	deadJob := mock.SystemJob()
+	deadJob.NodePool = "with-dead-job"
+	deadJob.Stop = true
+	require.NoError(t, testState.UpsertJob(structs.MsgTypeTestSetup, 1003, deadJob))
+
+	testCases := []struct {
+		name        string
+		pools       []string
+		expectedErr string
+	}{
+		{
+			name:        "built-in",
+			pools:       []string{structs.NodePoolDefault},
+			expectedErr: "can not be deleted",
+		},
+		{
+			name:        "not found",
+			pools:       []string{"unknown"},
+			expectedErr: "node pool not found",
+		},
+		{
+			name:        "has nodes",
+			pools:       []string{"with-node"},
+			expectedErr: "has at least one node",
+		},
+		{
+			name:        "has jobs",
+			pools:       []string{"with-job"},
+			expectedErr: "non-terminal job",
+		},
+		{
+			name:  "unused and dead jobs only",
+			pools: []string{"unused", "with-dead-job"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := testState.DeleteNodePools(structs.MsgTypeTestSetup, uint64(2000+i), tc.pools)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, name := range tc.pools {
+				pool, err := testState.NodePoolByName(nil, name)
+				require.NoError(t, err)
+				require.Nil(t, pool)
+			}
+		})
+	}
+}
@@ -197,3 +197,93 @@ func (r *StateRestore) ServiceRegistrationRestore(service *structs.ServiceRegist
 	}
 	return nil
 }
+
+// VariablesRestore is used to restore a single variable into the variables
+// table.
+func (r *StateRestore) VariablesRestore(variable *structs.VariableEncrypted) error {
+	if err := r.txn.Insert(TableVariables, variable); err != nil {
+		return fmt.Errorf("variable insert failed: %v", err)
+	}
+	return nil
+}
+
+// RootKeyMetaRestore is used to restore a single root key meta into the
+// root_key_meta table.
+func (r *StateRestore) RootKeyMetaRestore(keyMeta *structs.RootKeyMeta) error {
+	if err := r.txn.Insert(TableRootKeyMeta, keyMeta); err != nil {
+		return fmt.Errorf("root key meta insert failed: %v", err)
+	}
+	return nil
+}
+
+// ACLRoleRestore is used to restore a single ACL role into the acl_roles
+// table.
+func (r *StateRestore) ACLRoleRestore(aclRole *structs.ACLRole) error {
+	if err := r.txn.Insert(TableACLRoles, aclRole); err != nil {
+		return fmt.Errorf("ACL role insert failed: %v", err)
+	}
+	return nil
+}
+
+// ACLAuthMethodRestore is used to restore a single auth method into the
+// acl_auth_methods table.
+func (r *StateRestore) ACLAuthMethodRestore(authMethod *structs.ACLAuthMethod) error {
+	if err := r.txn.Insert(TableACLAuthMethods, authMethod); err != nil {
+		return fmt.Errorf("ACL auth method insert failed: %v", err)
+	}
+	return nil
+}
+
+// ACLBindingRuleRestore is used to restore a single binding rule into the
+// acl_binding_rules table.
+func (r *StateRestore) ACLBindingRuleRestore(bindingRule *structs.ACLBindingRule) error {
+	if err := r.txn.Insert(TableACLBindingRules, bindingRule); err != nil {
+		return fmt.Errorf("ACL binding rule insert failed: %v", err)
+	}
+	return nil
+}
+
+// NodePoolRestore is used to restore a single node pool into the node_pools
+// table.
+func (r *StateRestore) NodePoolRestore(pool *structs.NodePool) error {
+	if err := r.txn.Insert(TableNodePools, pool); err != nil {
+		return fmt.Errorf("node pool insert failed: %v", err)
+	}
+	return nil
+}
+
+// JobSubmissionRestore is used to restore a single job submission into the
+// job_submission table.
+func (r *StateRestore) JobSubmissionRestore(sub *structs.JobSubmission) error {
+	if err := r.txn.Insert(TableJobSubmission, sub); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	return nil
+}
+
+// MaintenancePlanRestore is used to restore a single maintenance plan into
+// the maintenance_plans table.
+func (r *StateRestore) MaintenancePlanRestore(plan *structs.MaintenancePlan) error {
+	if err := r.txn.Insert(TableMaintenancePlans, plan); err != nil {
+		return fmt.Errorf("maintenance plan insert failed: %v", err)
+	}
+	return nil
+}
+
+// EventSinkRestore is used to restore a single event sink into the
+// event_sinks table.
+func (r *StateRestore) EventSinkRestore(sink *structs.EventSink) error {
+	if err := r.txn.Insert(TableEventSinks, sink); err != nil {
+		return fmt.Errorf("event sink insert failed: %v", err)
+	}
+	return nil
+}
+
+// HostVolumeRestore is used to restore a single dynamic host volume into the
+// host_volumes table.
+func (r *StateRestore) HostVolumeRestore(vol *structs.HostVolume) error {
+	if err := r.txn.Insert(TableHostVolumes, vol); err != nil {
+		return fmt.Errorf("host volume insert failed: %v", err)
+	}
+	return nil
+}
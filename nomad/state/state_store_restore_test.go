@@ -573,3 +573,26 @@ func TestStateStore_ServiceRegistrationRestore(t *testing.T) {
 		require.Equal(t, serviceRegs[i], out)
 	}
 }
+
+func TestStateStore_NodePoolRestore(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	pool := &structs.NodePool{
+		Name:        "dev",
+		Description: "development nodes",
+		CreateIndex: 13,
+		ModifyIndex: 13,
+	}
+	pool.SetHash()
+
+	restore, err := testState.Restore()
+	require.NoError(t, err)
+	require.NoError(t, restore.NodePoolRestore(pool))
+	require.NoError(t, restore.Commit())
+
+	ws := memdb.NewWatchSet()
+	out, err := testState.NodePoolByName(ws, pool.Name)
+	require.NoError(t, err)
+	require.Equal(t, pool, out)
+}
@@ -705,7 +705,7 @@ func TestStateStore_Deployments_Namespace(t *testing.T) {
 	deploy3.Namespace = ns2.Name
 	deploy4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -864,7 +864,7 @@ func TestStateStore_DeploymentsByIDPrefix_Namespaces(t *testing.T) {
 	deploy1.Namespace = ns1.Name
 	deploy2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertDeployment(1000, deploy1))
 	require.NoError(t, state.UpsertDeployment(1001, deploy2))
 
@@ -912,7 +912,7 @@ func TestStateStore_UpsertNamespaces(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns1.Name)
 	require.NoError(t, err)
 
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 	require.True(t, watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -937,14 +937,14 @@ func TestStateStore_DeleteNamespaces(t *testing.T) {
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
 
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	// Create a watchset so we can test that delete fires the watch
 	ws := memdb.NewWatchSet()
 	_, err := state.NamespaceByName(ws, ns1.Name)
 	require.NoError(t, err)
 
-	require.NoError(t, state.DeleteNamespaces(1001, []string{ns1.Name, ns2.Name}))
+	require.NoError(t, state.DeleteNamespaces(structs.MsgTypeTestSetup, 1001, []string{ns1.Name, ns2.Name}))
 	require.True(t, watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -969,9 +969,9 @@ func TestStateStore_DeleteNamespaces_Default(t *testing.T) {
 
 	ns := mock.Namespace()
 	ns.Name = structs.DefaultNamespace
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
-	err := state.DeleteNamespaces(1002, []string{ns.Name})
+	err := state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "can not be deleted")
 }
@@ -982,7 +982,7 @@ func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
 	state := testStateStore(t)
 
 	ns := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	job := mock.Job()
 	job.Namespace = ns.Name
@@ -993,7 +993,7 @@ func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns.Name)
 	require.NoError(t, err)
 
-	err = state.DeleteNamespaces(1002, []string{ns.Name})
+	err = state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "one non-terminal")
 	require.False(t, watchFired(ws))
@@ -1020,7 +1020,7 @@ func TestStateStore_Namespaces(t *testing.T) {
 		namespaces = append(namespaces, ns)
 	}
 
-	require.NoError(t, state.UpsertNamespaces(1000, namespaces))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, namespaces))
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
@@ -1059,7 +1059,7 @@ func TestStateStore_NamespaceNames(t *testing.T) {
 		expectedNames = append(expectedNames, ns.Name)
 	}
 
-	err := state.UpsertNamespaces(1000, namespaces)
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, namespaces)
 	require.NoError(t, err)
 
 	found, err := state.NamespaceNames()
@@ -1078,7 +1078,7 @@ func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
 	ns := mock.Namespace()
 
 	ns.Name = "foobar"
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
@@ -1110,7 +1110,7 @@ func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
 
 	ns = mock.Namespace()
 	ns.Name = "foozip"
-	err = state.UpsertNamespaces(1001, []*structs.Namespace{ns})
+	err = state.UpsertNamespaces(structs.MsgTypeTestSetup, 1001, []*structs.Namespace{ns})
 	require.NoError(t, err)
 	require.True(t, watchFired(ws))
 
@@ -2119,6 +2119,78 @@ func TestStateStore_UpdateUpsertJob_JobVersion(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpsertJobWithSubmission(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.Job()
+
+	newSub := func(i int) *structs.JobSubmission {
+		return &structs.JobSubmission{
+			Source: fmt.Sprintf("job %q { # %d }", job.ID, i),
+			Format: structs.JobSubmissionFormatHCL2,
+		}
+	}
+
+	// Register the job with its source
+	ws := memdb.NewWatchSet()
+	_, err := state.JobSubmission(ws, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, newSub(0), job))
+	require.True(t, watchFired(ws))
+
+	out, err := state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, newSub(0).Source, out.Source)
+	require.Equal(t, job.Namespace, out.Namespace)
+	require.Equal(t, job.ID, out.JobID)
+	require.Equal(t, uint64(0), out.Version)
+	require.Equal(t, uint64(1000), out.JobModifyIndex)
+
+	index, err := state.Index(TableJobSubmission)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), index)
+
+	// A version registered without its source has no submission
+	job = job.Copy()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 1)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Submissions are only kept for tracked versions
+	for i := 2; i <= structs.JobTrackedVersions+1; i++ {
+		job = job.Copy()
+		require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, uint64(1000+i), newSub(i), job))
+	}
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, uint64(structs.JobTrackedVersions+1))
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, newSub(structs.JobTrackedVersions+1).Source, out.Source)
+
+	// Updating the stability of a version keeps its submission
+	require.NoError(t, state.UpdateJobStability(2000, job.Namespace, job.ID, job.Version, true))
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Deleting the job deletes all of its submissions
+	require.NoError(t, state.DeleteJob(2001, job.Namespace, job.ID))
+	iter, err := state.JobSubmissions(nil)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	index, err = state.Index(TableJobSubmission)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2001), index)
+}
+
 func TestStateStore_DeleteJob_Job(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2568,7 +2640,7 @@ func TestStateStore_JobsByIDPrefix_Namespaces(t *testing.T) {
 	job1.Namespace = ns1.Name
 	job2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job1))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job2))
 
@@ -2651,7 +2723,7 @@ func TestStateStore_JobsByNamespace(t *testing.T) {
 	job3.Namespace = ns2.Name
 	job4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -2944,7 +3016,7 @@ func TestStateStore_UpsertPeriodicLaunch(t *testing.T) {
 		t.Fatalf("bad: %v", err)
 	}
 
-	err := state.UpsertPeriodicLaunch(1000, launch)
+	err := state.UpsertPeriodicLaunch(structs.MsgTypeTestSetup, 1000, launch)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -2993,7 +3065,7 @@ func TestStateStore_UpdateUpsertPeriodicLaunch(t *testing.T) {
 		Launch:    time.Now(),
 	}
 
-	err := state.UpsertPeriodicLaunch(1000, launch)
+	err := state.UpsertPeriodicLaunch(structs.MsgTypeTestSetup, 1000, launch)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -3009,7 +3081,7 @@ func TestStateStore_UpdateUpsertPeriodicLaunch(t *testing.T) {
 		Namespace: job.Namespace,
 		Launch:    launch.Launch.Add(1 * time.Second),
 	}
-	err = state.UpsertPeriodicLaunch(1001, launch2)
+	err = state.UpsertPeriodicLaunch(structs.MsgTypeTestSetup, 1001, launch2)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -3047,6 +3119,48 @@ func TestStateStore_UpdateUpsertPeriodicLaunch(t *testing.T) {
 	}
 }
 
+func TestStateStore_SkipPeriodicLaunch(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	job := mock.PeriodicJob()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	launch := time.Now().Round(time.Second)
+	require.NoError(t, state.SkipPeriodicLaunch(structs.MsgTypeTestSetup, 1001, job.Namespace, job.ID, launch))
+
+	ws := memdb.NewWatchSet()
+	out, err := state.PeriodicLaunchByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.True(t, launch.Equal(out.Launch))
+	require.Equal(t, uint64(1001), out.CreateIndex)
+	require.Equal(t, uint64(1001), out.ModifyIndex)
+
+	summary, err := state.JobSummaryByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, summary.Children)
+	require.Equal(t, int64(1), summary.Children.Skipped)
+	require.Equal(t, uint64(1001), summary.ModifyIndex)
+
+	// Skipping an earlier launch counts the skip but keeps the latest launch
+	require.NoError(t, state.SkipPeriodicLaunch(structs.MsgTypeTestSetup, 1002, job.Namespace, job.ID, launch.Add(-time.Hour)))
+
+	out, err = state.PeriodicLaunchByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.True(t, launch.Equal(out.Launch))
+	require.Equal(t, uint64(1001), out.CreateIndex)
+	require.Equal(t, uint64(1002), out.ModifyIndex)
+
+	summary, err = state.JobSummaryByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), summary.Children.Skipped)
+
+	index, err := state.Index("periodic_launch")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1002), index)
+}
+
 func TestStateStore_DeletePeriodicLaunch(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3058,7 +3172,7 @@ func TestStateStore_DeletePeriodicLaunch(t *testing.T) {
 		Launch:    time.Now(),
 	}
 
-	err := state.UpsertPeriodicLaunch(1000, launch)
+	err := state.UpsertPeriodicLaunch(structs.MsgTypeTestSetup, 1000, launch)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -3116,7 +3230,7 @@ func TestStateStore_PeriodicLaunches(t *testing.T) {
 		}
 		launches = append(launches, launch)
 
-		err := state.UpsertPeriodicLaunch(1000+uint64(i), launch)
+		err := state.UpsertPeriodicLaunch(structs.MsgTypeTestSetup, 1000+uint64(i), launch)
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
@@ -3240,18 +3354,18 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	}}
 
 	index++
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v0, v1})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v0, v1})
 	require.NoError(t, err)
 
 	// volume registration is idempotent, unless identies are changed
 	index++
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v0, v1})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v0, v1})
 	require.NoError(t, err)
 
 	index++
 	v2 := v0.Copy()
 	v2.PluginID = "new-id"
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v2})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v2})
 	require.Error(t, err, fmt.Sprintf("volume exists: %s", v0.ID))
 
 	ws := memdb.NewWatchSet()
@@ -3308,10 +3422,10 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	}
 
 	index++
-	err = state.CSIVolumeClaim(index, ns, vol0, claim0)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim0)
 	require.NoError(t, err)
 	index++
-	err = state.CSIVolumeClaim(index, ns, vol0, claim1)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim1)
 	require.NoError(t, err)
 
 	ws = memdb.NewWatchSet()
@@ -3321,7 +3435,7 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	require.False(t, vs[0].HasFreeWriteClaims())
 
 	claim0.Mode = u
-	err = state.CSIVolumeClaim(2, ns, vol0, claim0)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, 2, ns, vol0, claim0)
 	require.NoError(t, err)
 	ws = memdb.NewWatchSet()
 	iter, err = state.CSIVolumesByPluginID(ws, ns, "", "minnie")
@@ -3331,36 +3445,36 @@ func TestStateStore_CSIVolume(t *testing.T) {
 
 	// registration is an error when the volume is in use
 	index++
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v0})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v0})
 	require.Error(t, err, "volume re-registered while in use")
 	// as is deregistration
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{vol0}, false)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{vol0}, false)
 	require.Error(t, err, "volume deregistered while in use")
 
 	// even if forced, because we have a non-terminal claim
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{vol0}, true)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{vol0}, true)
 	require.Error(t, err, "volume force deregistered while in use")
 
 	// we use the ID, not a prefix
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{"fo"}, true)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{"fo"}, true)
 	require.Error(t, err, "volume deregistered by prefix")
 
 	// release claims to unblock deregister
 	index++
 	claim0.State = structs.CSIVolumeClaimStateReadyToFree
-	err = state.CSIVolumeClaim(index, ns, vol0, claim0)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim0)
 	require.NoError(t, err)
 	index++
 	claim1.Mode = u
 	claim1.State = structs.CSIVolumeClaimStateReadyToFree
-	err = state.CSIVolumeClaim(index, ns, vol0, claim1)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim1)
 	require.NoError(t, err)
 
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{vol0}, false)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{vol0}, false)
 	require.NoError(t, err)
 
 	// List, now omitting the deregistered volume
@@ -3671,7 +3785,7 @@ func TestStateStore_CSIPlugin_Lifecycle(t *testing.T) {
 			Namespace: structs.DefaultNamespace,
 			PluginID:  plugID,
 		}
-		err = store.UpsertCSIVolume(nextIndex(store), []*structs.CSIVolume{vol})
+		err = store.UpsertCSIVolume(structs.MsgTypeTestSetup, nextIndex(store), []*structs.CSIVolume{vol})
 		require.NoError(t, err)
 
 		err = store.DeleteJob(nextIndex(store), structs.DefaultNamespace, controllerJobID)
@@ -3957,7 +4071,7 @@ func TestStateStore_UpsertEvals_Namespace(t *testing.T) {
 	eval3.Namespace = ns2.Name
 	eval4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -4577,7 +4691,7 @@ func TestStateStore_EvalsByIDPrefix_Namespaces(t *testing.T) {
 	eval1.Namespace = ns1.Name
 	eval2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval1, eval2}))
 
 	gatherEvals := func(iter memdb.ResultIterator) []*structs.Evaluation {
@@ -5262,7 +5376,7 @@ func TestStateStore_UpsertAlloc_AllocsByNamespace(t *testing.T) {
 	alloc4.Namespace = ns2.Name
 	alloc4.Job.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, alloc1.Job))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, alloc3.Job))
 
@@ -6346,7 +6460,7 @@ func TestStateStore_AllocsByIDPrefix_Namespaces(t *testing.T) {
 	alloc1.Namespace = ns1.Name
 	alloc2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc1, alloc2}))
 
 	gatherAllocs := func(iter memdb.ResultIterator) []*structs.Allocation {
@@ -7220,6 +7334,45 @@ func TestStateStore_UpdateJobStability(t *testing.T) {
 	require.False(t, jout.Stable)
 }
 
+func TestStateStore_UpdateJobEvalPause(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	// Pausing an unknown job fails
+	job := mock.Job()
+	err := state.UpdateJobEvalPause(structs.MsgTypeTestSetup, 1, job.Namespace, job.ID, true)
+	require.ErrorContains(t, err, "not found")
+
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 2, job))
+
+	// Pause the evaluations of the job
+	require.NoError(t, state.UpdateJobEvalPause(structs.MsgTypeTestSetup, 3, job.Namespace, job.ID, true))
+
+	ws := memdb.NewWatchSet()
+	jout, err := state.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.True(t, jout.EvalsPaused)
+	require.Equal(t, uint64(3), jout.ModifyIndex)
+	require.Equal(t, job.Version, jout.Version)
+
+	index, err := state.Index("jobs")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), index)
+
+	// Registering a new version of the job keeps the pause
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 4, job.Copy()))
+	jout, err = state.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.True(t, jout.EvalsPaused)
+
+	// Resume the evaluations of the job
+	require.NoError(t, state.UpdateJobEvalPause(structs.MsgTypeTestSetup, 5, job.Namespace, job.ID, false))
+	jout, err = state.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.False(t, jout.EvalsPaused)
+}
+
 // Test that nonexistent deployment can't be promoted
 func TestStateStore_UpsertDeploymentPromotion_Nonexistent(t *testing.T) {
 	ci.Parallel(t)
@@ -8788,6 +8941,69 @@ func TestStateStore_ACLTokensByGlobal(t *testing.T) {
 	})
 }
 
+func TestStateStore_ACLTokensByExpired(t *testing.T) {
+	ci.Parallel(t)
+
+	testState := testStateStore(t)
+	now := time.Now().UTC()
+
+	// Create a mix of local and global tokens, some of which have expired
+	// and some of which have not. Tokens without an expiration time should
+	// never be returned.
+	expiredTime := now.Add(-time.Hour)
+	futureTime := now.Add(time.Hour)
+
+	expiredLocal := mock.ACLToken()
+	expiredLocal.ExpirationTime = &expiredTime
+
+	notExpiredLocal := mock.ACLToken()
+	notExpiredLocal.ExpirationTime = &futureTime
+
+	noExpiryLocal := mock.ACLToken()
+
+	expiredGlobal := mock.ACLToken()
+	expiredGlobal.Global = true
+	expiredGlobal.ExpirationTime = &expiredTime
+
+	notExpiredGlobal := mock.ACLToken()
+	notExpiredGlobal.Global = true
+	notExpiredGlobal.ExpirationTime = &futureTime
+
+	require.NoError(t, testState.UpsertACLTokens(structs.MsgTypeTestSetup, 10, []*structs.ACLToken{
+		expiredLocal, notExpiredLocal, noExpiryLocal, expiredGlobal, notExpiredGlobal}))
+
+	localIDs, err := testState.ACLTokensByExpired(false, now, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{expiredLocal.AccessorID}, localIDs)
+
+	globalIDs, err := testState.ACLTokensByExpired(true, now, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{expiredGlobal.AccessorID}, globalIDs)
+
+	// Moving the time forward should return both local tokens with an
+	// expiration, ordered by their expiration time.
+	localIDs, err = testState.ACLTokensByExpired(false, now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{expiredLocal.AccessorID, notExpiredLocal.AccessorID}, localIDs)
+
+	// The maximum should limit the number of returned IDs.
+	localIDs, err = testState.ACLTokensByExpired(false, now.Add(2*time.Hour), 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{expiredLocal.AccessorID}, localIDs)
+
+	// Deleting the expired tokens should remove them from the index.
+	require.NoError(t, testState.DeleteACLTokens(structs.MsgTypeTestSetup, 20,
+		[]string{expiredLocal.AccessorID, expiredGlobal.AccessorID}))
+
+	localIDs, err = testState.ACLTokensByExpired(false, now, 10)
+	require.NoError(t, err)
+	require.Empty(t, localIDs)
+
+	globalIDs, err = testState.ACLTokensByExpired(true, now, 10)
+	require.NoError(t, err)
+	require.Empty(t, globalIDs)
+}
+
 func TestStateStore_OneTimeTokens(t *testing.T) {
 	ci.Parallel(t)
 	index := uint64(100)
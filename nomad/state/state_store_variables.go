@@ -0,0 +1,325 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables returns an iterator that contains all variables stored within
+// state. This is primarily useful when performing listings which use the
+// namespace wildcard operator. The caller is responsible for ensuring ACL
+// access is confirmed, or filtering is performed before responding.
+func (s *StateStore) Variables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetVariablesByNamespace returns an iterator that contains all variables
+// belonging to the provided namespace.
+func (s *StateStore) GetVariablesByNamespace(
+	ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	return s.GetVariablesByNamespaceAndPrefix(ws, namespace, "")
+}
+
+// GetVariablesByNamespaceAndPrefix returns an iterator that contains all
+// variables belonging to the provided namespace whose path begins with the
+// prefix.
+func (s *StateStore) GetVariablesByNamespaceAndPrefix(
+	ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexID+"_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetVariablesByKeyID returns an iterator that contains all variables that
+// were encrypted with the root key.
+func (s *StateStore) GetVariablesByKeyID(
+	ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexKeyID, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetVariable returns a single variable at the given namespace and path. The
+// variable will be nil, if no matching entry was found; it is the
+// responsibility of the caller to check for this.
+func (s *StateStore) GetVariable(
+	ws memdb.WatchSet, namespace, path string) (*structs.VariableEncrypted, error) {
+
+	txn := s.db.ReadTxn()
+	return s.getVariableTxn(ws, txn, namespace, path)
+}
+
+func (s *StateStore) getVariableTxn(
+	ws memdb.WatchSet, txn ReadTxn, namespace, path string) (*structs.VariableEncrypted, error) {
+
+	watchCh, existing, err := txn.FirstWatch(TableVariables, indexID, namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.VariableEncrypted), nil
+	}
+	return nil, nil
+}
+
+// VarSet is used to upsert a variable, regardless of its current state.
+func (s *StateStore) VarSet(index uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	txn := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, index)
+	defer txn.Abort()
+
+	resp := s.varSetTxn(index, txn, req)
+	if resp.IsOk() {
+		if err := txn.Commit(); err != nil {
+			return req.ErrorResponse(index, err)
+		}
+	}
+	return resp
+}
+
+// VarSetCAS is used to upsert a variable only if its current modify index
+// matches that of the request. A modify index of zero requires that the
+// variable does not yet exist.
+func (s *StateStore) VarSetCAS(index uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	txn := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, index)
+	defer txn.Abort()
+
+	existing, err := s.getVariableTxn(nil, txn, req.Var.Namespace, req.Var.Path)
+	if err != nil {
+		return req.ErrorResponse(index, err)
+	}
+	if !varCASMatches(existing, req.Var.ModifyIndex) {
+		return req.ConflictResponse(index, existing)
+	}
+
+	resp := s.varSetTxn(index, txn, req)
+	if resp.IsOk() {
+		if err := txn.Commit(); err != nil {
+			return req.ErrorResponse(index, err)
+		}
+	}
+	return resp
+}
+
+// varSetTxn inserts a single variable into the state store using the
+// provided write transaction. The index table is updated as part of the
+// call.
+func (s *StateStore) varSetTxn(
+	index uint64, txn *txn, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+
+	variable := req.Var.Copy()
+
+	existing, err := s.getVariableTxn(nil, txn, variable.Namespace, variable.Path)
+	if err != nil {
+		return req.ErrorResponse(index, err)
+	}
+
+	// Set up the indexes correctly to ensure existing indexes are maintained.
+	if existing != nil {
+		variable.CreateIndex = existing.CreateIndex
+		variable.CreateTime = existing.CreateTime
+	} else {
+		variable.CreateIndex = index
+	}
+	variable.ModifyIndex = index
+
+	if err := txn.Insert(TableVariables, variable); err != nil {
+		return req.ErrorResponse(index, fmt.Errorf("variable insert failed: %v", err))
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariables, index}); err != nil {
+		return req.ErrorResponse(index, fmt.Errorf("index update failed: %v", err))
+	}
+
+	return req.SuccessResponse(index, variable.VariableMetadata.Copy())
+}
+
+// VarDelete is used to delete a variable, regardless of its current state.
+// Deleting a variable which does not exist is not an error.
+func (s *StateStore) VarDelete(index uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	txn := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, index)
+	defer txn.Abort()
+
+	resp := s.varDeleteTxn(index, txn, req)
+	if resp.IsOk() {
+		if err := txn.Commit(); err != nil {
+			return req.ErrorResponse(index, err)
+		}
+	}
+	return resp
+}
+
+// VarDeleteCAS is used to delete a variable only if its current modify index
+// matches that of the request.
+func (s *StateStore) VarDeleteCAS(index uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	txn := s.db.WriteTxnMsgT(structs.VarApplyStateRequestType, index)
+	defer txn.Abort()
+
+	existing, err := s.getVariableTxn(nil, txn, req.Var.Namespace, req.Var.Path)
+	if err != nil {
+		return req.ErrorResponse(index, err)
+	}
+
+	// Deleting a variable which does not exist succeeds when the caller
+	// expected it not to exist.
+	if existing == nil && req.Var.ModifyIndex == 0 {
+		return req.SuccessResponse(index, nil)
+	}
+	if !varCASMatches(existing, req.Var.ModifyIndex) {
+		return req.ConflictResponse(index, existing)
+	}
+
+	resp := s.varDeleteTxn(index, txn, req)
+	if resp.IsOk() {
+		if err := txn.Commit(); err != nil {
+			return req.ErrorResponse(index, err)
+		}
+	}
+	return resp
+}
+
+func (s *StateStore) varDeleteTxn(
+	index uint64, txn *txn, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+
+	existing, err := s.getVariableTxn(nil, txn, req.Var.Namespace, req.Var.Path)
+	if err != nil {
+		return req.ErrorResponse(index, err)
+	}
+	if existing == nil {
+		return req.SuccessResponse(index, nil)
+	}
+
+	if err := txn.Delete(TableVariables, existing); err != nil {
+		return req.ErrorResponse(index, fmt.Errorf("variable deletion failed: %v", err))
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariables, index}); err != nil {
+		return req.ErrorResponse(index, fmt.Errorf("index update failed: %v", err))
+	}
+	return req.SuccessResponse(index, nil)
+}
+
+// varCASMatches returns whether the existing variable satisfies the
+// check-and-set index of a request.
+func varCASMatches(existing *structs.VariableEncrypted, casIndex uint64) bool {
+	if existing == nil {
+		return casIndex == 0
+	}
+	return existing.ModifyIndex == casIndex
+}
+
+// UpsertRootKeyMeta is used to insert or update the metadata of a root key.
+// If the key is active, all other keys are marked inactive, so there is only
+// ever a single active key.
+func (s *StateStore) UpsertRootKeyMeta(index uint64, rootKeyMeta *structs.RootKeyMeta) error {
+	txn := s.db.WriteTxnMsgT(structs.RootKeyMetaUpsertRequestType, index)
+	defer txn.Abort()
+
+	rootKeyMeta = rootKeyMeta.Copy()
+
+	existing, err := txn.First(TableRootKeyMeta, indexID, rootKeyMeta.KeyID)
+	if err != nil {
+		return fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	if existing != nil {
+		rootKeyMeta.CreateIndex = existing.(*structs.RootKeyMeta).CreateIndex
+	} else {
+		rootKeyMeta.CreateIndex = index
+	}
+	rootKeyMeta.ModifyIndex = index
+
+	if rootKeyMeta.Active() {
+		iter, err := txn.Get(TableRootKeyMeta, indexID)
+		if err != nil {
+			return fmt.Errorf("root key metadata lookup failed: %v", err)
+		}
+		var inactive []*structs.RootKeyMeta
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			keyMeta := raw.(*structs.RootKeyMeta)
+			if keyMeta.KeyID != rootKeyMeta.KeyID && keyMeta.Active() {
+				keyMeta = keyMeta.Copy()
+				keyMeta.State = structs.RootKeyStateInactive
+				keyMeta.ModifyIndex = index
+				inactive = append(inactive, keyMeta)
+			}
+		}
+		for _, keyMeta := range inactive {
+			if err := txn.Insert(TableRootKeyMeta, keyMeta); err != nil {
+				return fmt.Errorf("root key metadata insert failed: %v", err)
+			}
+		}
+	}
+
+	if err := txn.Insert(TableRootKeyMeta, rootKeyMeta); err != nil {
+		return fmt.Errorf("root key metadata insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableRootKeyMeta, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// RootKeyMetas returns an iterator over all root key metadata.
+func (s *StateStore) RootKeyMetas(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableRootKeyMeta, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// RootKeyMetaByID returns the metadata of a single root key. The metadata
+// will be nil, if no matching entry was found.
+func (s *StateStore) RootKeyMetaByID(ws memdb.WatchSet, id string) (*structs.RootKeyMeta, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableRootKeyMeta, indexID, id)
+	if err != nil {
+		return nil, fmt.Errorf("root key metadata lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if raw != nil {
+		return raw.(*structs.RootKeyMeta), nil
+	}
+	return nil, nil
+}
+
+// GetActiveRootKeyMeta returns the metadata of the active root key, or nil if
+// the keyring has not yet been initialized.
+func (s *StateStore) GetActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMeta, error) {
+	iter, err := s.RootKeyMetas(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		keyMeta := raw.(*structs.RootKeyMeta)
+		if keyMeta.Active() {
+			return keyMeta, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,203 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func mockVariableEncrypted(ns, path string) *structs.VariableEncrypted {
+	return &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: ns,
+			Path:      path,
+		},
+		VariableData: structs.VariableData{
+			Data:  []byte("ciphertext"),
+			KeyID: "key-id",
+		},
+	}
+}
+
+func TestStateStore_VarSet(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	variable := mockVariableEncrypted(structs.DefaultNamespace, "foo/bar")
+
+	// Insert the variable and ensure the indexes are set.
+	resp := testState.VarSet(10, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable})
+	require.True(t, resp.IsOk())
+	require.NoError(t, resp.Error)
+	require.Equal(t, uint64(10), resp.WrittenVarMeta.CreateIndex)
+	require.Equal(t, uint64(10), resp.WrittenVarMeta.ModifyIndex)
+
+	index, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), index)
+
+	// Updating the variable should retain the create index.
+	variable.Data = []byte("updated")
+	resp = testState.VarSet(20, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable})
+	require.True(t, resp.IsOk())
+
+	out, err := testState.GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo/bar")
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+	require.Equal(t, []byte("updated"), out.Data)
+
+	// Reading a variable which does not exist should not error.
+	out, err = testState.GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestStateStore_VarSetCAS(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	variable := mockVariableEncrypted(structs.DefaultNamespace, "foo")
+
+	// A non-zero check index should conflict when the variable does not
+	// exist, returning no conflicting variable.
+	variable.ModifyIndex = 5
+	resp := testState.VarSetCAS(10, &structs.VarApplyStateRequest{Op: structs.VarOpCAS, Var: variable})
+	require.Equal(t, structs.VarOpResultConflict, resp.Result)
+	require.Nil(t, resp.Conflict)
+
+	// A zero check index creates the variable.
+	variable.ModifyIndex = 0
+	resp = testState.VarSetCAS(10, &structs.VarApplyStateRequest{Op: structs.VarOpCAS, Var: variable})
+	require.True(t, resp.IsOk())
+
+	// A stale check index conflicts, returning the current variable.
+	resp = testState.VarSetCAS(20, &structs.VarApplyStateRequest{Op: structs.VarOpCAS, Var: variable})
+	require.Equal(t, structs.VarOpResultConflict, resp.Result)
+	require.NotNil(t, resp.Conflict)
+	require.Equal(t, uint64(10), resp.Conflict.ModifyIndex)
+
+	// The current check index updates the variable.
+	variable.ModifyIndex = 10
+	resp = testState.VarSetCAS(20, &structs.VarApplyStateRequest{Op: structs.VarOpCAS, Var: variable})
+	require.True(t, resp.IsOk())
+	require.Equal(t, uint64(20), resp.WrittenVarMeta.ModifyIndex)
+
+	index, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+}
+
+func TestStateStore_VarDelete(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	variable := mockVariableEncrypted(structs.DefaultNamespace, "foo")
+	resp := testState.VarSet(10, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable})
+	require.True(t, resp.IsOk())
+
+	// A stale check index should conflict and leave the variable in place.
+	variable.ModifyIndex = 5
+	resp = testState.VarDeleteCAS(20, &structs.VarApplyStateRequest{Op: structs.VarOpDeleteCAS, Var: variable})
+	require.Equal(t, structs.VarOpResultConflict, resp.Result)
+
+	out, err := testState.GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// The current check index deletes the variable.
+	variable.ModifyIndex = 10
+	resp = testState.VarDeleteCAS(20, &structs.VarApplyStateRequest{Op: structs.VarOpDeleteCAS, Var: variable})
+	require.True(t, resp.IsOk())
+
+	out, err = testState.GetVariable(memdb.NewWatchSet(), structs.DefaultNamespace, "foo")
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	index, err := testState.Index(TableVariables)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+
+	// Deleting a variable which does not exist is not an error.
+	resp = testState.VarDelete(30, &structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: variable})
+	require.True(t, resp.IsOk())
+}
+
+func TestStateStore_GetVariablesByNamespaceAndPrefix(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	for i, variable := range []*structs.VariableEncrypted{
+		mockVariableEncrypted(structs.DefaultNamespace, "app/web"),
+		mockVariableEncrypted(structs.DefaultNamespace, "app/db"),
+		mockVariableEncrypted(structs.DefaultNamespace, "other"),
+		mockVariableEncrypted("platform", "app/web"),
+	} {
+		resp := testState.VarSet(uint64(10+i), &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: variable})
+		require.True(t, resp.IsOk())
+	}
+
+	paths := func(iter memdb.ResultIterator) []string {
+		var out []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			variable := raw.(*structs.VariableEncrypted)
+			out = append(out, variable.Namespace+":"+variable.Path)
+		}
+		return out
+	}
+
+	iter, err := testState.GetVariablesByNamespace(memdb.NewWatchSet(), structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, []string{"default:app/db", "default:app/web", "default:other"}, paths(iter))
+
+	iter, err = testState.GetVariablesByNamespaceAndPrefix(memdb.NewWatchSet(), structs.DefaultNamespace, "app/")
+	require.NoError(t, err)
+	require.Equal(t, []string{"default:app/db", "default:app/web"}, paths(iter))
+
+	iter, err = testState.Variables(memdb.NewWatchSet())
+	require.NoError(t, err)
+	require.Len(t, paths(iter), 4)
+
+	iter, err = testState.GetVariablesByKeyID(memdb.NewWatchSet(), "key-id")
+	require.NoError(t, err)
+	require.Len(t, paths(iter), 4)
+}
+
+func TestStateStore_UpsertRootKeyMeta(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Initially there should be no active key.
+	active, err := testState.GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	require.Nil(t, active)
+
+	key1 := structs.NewRootKeyMeta()
+	require.NoError(t, testState.UpsertRootKeyMeta(10, key1))
+
+	active, err = testState.GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	require.Equal(t, key1.KeyID, active.KeyID)
+	require.Equal(t, uint64(10), active.CreateIndex)
+
+	// Inserting a new active key should mark the existing key inactive.
+	key2 := structs.NewRootKeyMeta()
+	require.NoError(t, testState.UpsertRootKeyMeta(20, key2))
+
+	active, err = testState.GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	require.Equal(t, key2.KeyID, active.KeyID)
+
+	out, err := testState.RootKeyMetaByID(nil, key1.KeyID)
+	require.NoError(t, err)
+	require.False(t, out.Active())
+	require.Equal(t, uint64(10), out.CreateIndex)
+	require.Equal(t, uint64(20), out.ModifyIndex)
+
+	index, err := testState.Index(TableRootKeyMeta)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), index)
+}
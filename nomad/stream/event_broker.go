@@ -8,6 +8,7 @@ import (
 	"sync/atomic"
 
 	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/go-memdb"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -136,6 +137,15 @@ func (e *EventBroker) SubscribeWithACLCheck(req *SubscribeRequest) (*Subscriptio
 // When a caller is finished with the subscription it must call Subscription.Unsubscribe
 // to free ACL tracking resources.
 func (e *EventBroker) Subscribe(req *SubscribeRequest) (*Subscription, error) {
+	var evaluator *bexpr.Evaluator
+	if req.Filter != "" {
+		var err error
+		evaluator, err = bexpr.CreateEvaluator(req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read filter expression: %v", err)
+		}
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -147,7 +157,7 @@ func (e *EventBroker) Subscribe(req *SubscribeRequest) (*Subscription, error) {
 		head = e.eventBuf.Head()
 	}
 	if offset > 0 && req.StartExactlyAtIndex {
-		return nil, fmt.Errorf("requested index not in buffer")
+		return nil, ErrIndexNotInBuffer
 	} else if offset > 0 {
 		metrics.SetGauge([]string{"nomad", "event_broker", "subscription", "request_offset"}, float32(offset))
 		e.logger.Debug("requested index no longer in buffer", "requsted", int(req.Index), "closest", int(head.Events.Index))
@@ -158,7 +168,7 @@ func (e *EventBroker) Subscribe(req *SubscribeRequest) (*Subscription, error) {
 	start.link.next.Store(head)
 	close(start.link.nextCh)
 
-	sub := newSubscription(req, start, e.subscriptions.unsubscribeFn(req))
+	sub := newSubscription(req, evaluator, start, e.subscriptions.unsubscribeFn(req))
 
 	e.subscriptions.add(req, sub)
 	return sub, nil
@@ -300,14 +310,39 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 			structs.TopicEvaluation,
 			structs.TopicAllocation,
 			structs.TopicJob,
-			structs.TopicService:
+			structs.TopicService,
+			structs.TopicPeriodicLaunch:
 			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadJob); !ok {
 				return false
 			}
-		case structs.TopicNode:
+		case structs.TopicCSIVolume:
+			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityCSIReadVolume); !ok {
+				return false
+			}
+		case structs.TopicHostVolume:
+			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityHostVolumeRead); !ok {
+				return false
+			}
+		case structs.TopicScalingPolicy:
+			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadScalingPolicy); !ok {
+				return false
+			}
+		case structs.TopicNamespace:
+			if ok := aclObj.AllowNamespace(subReq.Namespace); !ok {
+				return false
+			}
+		case structs.TopicNode, structs.TopicMaintenance:
 			if ok := aclObj.AllowNodeRead(); !ok {
 				return false
 			}
+		case structs.TopicCSIPlugin:
+			if ok := aclObj.AllowPluginRead(); !ok {
+				return false
+			}
+		case structs.TopicOperator:
+			if ok := aclObj.AllowOperatorRead(); !ok {
+				return false
+			}
 		default:
 			if ok := aclObj.IsManagement(); !ok {
 				return false
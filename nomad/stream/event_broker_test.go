@@ -68,6 +68,55 @@ func TestEventBroker_PublishChangesAndSubscribe(t *testing.T) {
 	require.Equal(t, expected, result.Events)
 }
 
+func TestEventBroker_SubscribeFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	publisher, err := NewEventBroker(ctx, nil, EventBrokerCfg{EventBufferSize: 100})
+	require.NoError(t, err)
+
+	// Invalid filter expressions are rejected
+	_, err = publisher.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{"*": {"*"}},
+		Filter: "Job.Name ==",
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read filter expression")
+
+	sub, err := publisher.Subscribe(&SubscribeRequest{
+		Topics:    map[structs.Topic][]string{"*": {"*"}},
+		Namespace: "*",
+		Filter:    `Job.Type == "batch"`,
+	})
+	require.NoError(t, err)
+	eventCh := consumeSubscription(ctx, sub)
+
+	service := &structs.JobEvent{Job: &structs.Job{ID: "service", Type: structs.JobTypeService}}
+	batch := &structs.JobEvent{Job: &structs.Job{ID: "batch", Type: structs.JobTypeBatch}}
+	node := &structs.NodeStreamEvent{Node: &structs.Node{ID: "node"}}
+
+	// Only the events whose payload match the filter are received, including
+	// when the payload doesn't have the fields of the filter
+	publisher.Publish(&structs.Events{Index: 1, Events: []structs.Event{
+		{Index: 1, Topic: structs.TopicJob, Key: "service", Payload: service},
+		{Index: 1, Topic: structs.TopicNode, Key: "node", Payload: node},
+		{Index: 1, Topic: structs.TopicJob, Key: "batch", Payload: batch},
+	}})
+
+	result := nextResult(t, eventCh)
+	require.NoError(t, result.Err)
+	require.Len(t, result.Events, 1)
+	require.Equal(t, "batch", result.Events[0].Key)
+
+	// Events without any matching payload are skipped
+	publisher.Publish(&structs.Events{Index: 2, Events: []structs.Event{
+		{Index: 2, Topic: structs.TopicJob, Key: "service", Payload: service},
+	}})
+	assertNoResult(t, eventCh)
+}
+
 func TestEventBroker_ShutdownClosesSubscriptions(t *testing.T) {
 	ci.Parallel(t)
 
@@ -281,6 +330,44 @@ func TestEventBroker_handleACLUpdates_policyupdated(t *testing.T) {
 				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
 			},
 		},
+		{
+			desc:              "subscribed to csi volumes and removed access",
+			policyBeforeRules: mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityCSIReadVolume}),
+			policyAfterRules:  mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}),
+			shouldUnsubscribe: true,
+			event: structs.Event{
+				Topic: structs.TopicCSIVolume,
+				Type:  structs.TypeCSIVolumeRegistered,
+				Payload: structs.CSIVolumeEvent{
+					Volume: &structs.CSIVolume{
+						ID: "some-id",
+					},
+				},
+			},
+			policyEvent: structs.Event{
+				Topic:   structs.TopicACLToken,
+				Type:    structs.TypeACLTokenUpserted,
+				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
+			},
+		},
+		{
+			desc:              "subscribed to operator config and removed access",
+			policyBeforeRules: `operator { policy = "read" }`,
+			policyAfterRules:  `operator { policy = "deny" }`,
+			shouldUnsubscribe: true,
+			event: structs.Event{
+				Topic: structs.TopicOperator,
+				Type:  structs.TypeSchedulerConfigUpdated,
+				Payload: structs.SchedulerConfigurationEvent{
+					SchedulerConfig: &structs.SchedulerConfiguration{},
+				},
+			},
+			policyEvent: structs.Event{
+				Topic:   structs.TopicACLToken,
+				Type:    structs.TypeACLTokenUpserted,
+				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
+			},
+		},
 		{
 			desc:              "subscribed to evals in all namespaces and removed access",
 			policyBeforeRules: mock.NamespacePolicy("*", "", []string{acl.NamespaceCapabilityReadJob}),
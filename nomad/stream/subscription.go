@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync/atomic"
 
+	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -24,12 +25,20 @@ const (
 var ErrSubscriptionClosed = errors.New("subscription closed by server, client should resubscribe")
 var ErrACLInvalid = errors.New("Provided ACL token is invalid for requested topics")
 
+// ErrIndexNotInBuffer is returned when subscribing exactly at an index which
+// is no longer, or not yet, in the event buffer.
+var ErrIndexNotInBuffer = errors.New("requested index not in buffer")
+
 type Subscription struct {
 	// state must be accessed atomically 0 means open, 1 means closed with reload
 	state uint32
 
 	req *SubscribeRequest
 
+	// evaluator is the compiled filter expression of the request, nil if
+	// the request has no filter.
+	evaluator *bexpr.Evaluator
+
 	// currentItem stores the current buffer item we are on. It
 	// is mutated by calls to Next.
 	currentItem *bufferItem
@@ -52,6 +61,11 @@ type SubscribeRequest struct {
 
 	Topics map[structs.Topic][]string
 
+	// Filter is an optional bexpr filter expression evaluated against the
+	// payload of each event. Events whose payload doesn't match the filter,
+	// including payloads missing the fields it references, are not sent.
+	Filter string
+
 	// StartExactlyAtIndex specifies if a subscription needs to
 	// start exactly at the requested Index. If set to false,
 	// the closest index in the buffer will be returned if there is not
@@ -59,10 +73,11 @@ type SubscribeRequest struct {
 	StartExactlyAtIndex bool
 }
 
-func newSubscription(req *SubscribeRequest, item *bufferItem, unsub func()) *Subscription {
+func newSubscription(req *SubscribeRequest, evaluator *bexpr.Evaluator, item *bufferItem, unsub func()) *Subscription {
 	return &Subscription{
 		forceClosed: make(chan struct{}),
 		req:         req,
+		evaluator:   evaluator,
 		currentItem: item,
 		unsub:       unsub,
 	}
@@ -83,7 +98,7 @@ func (s *Subscription) Next(ctx context.Context) (structs.Events, error) {
 		}
 		s.currentItem = next
 
-		events := filter(s.req, next.Events.Events)
+		events := filterPayloads(s.evaluator, filter(s.req, next.Events.Events))
 		if len(events) == 0 {
 			continue
 		}
@@ -103,7 +118,7 @@ func (s *Subscription) NextNoBlock() ([]structs.Event, error) {
 		}
 		s.currentItem = next
 
-		events := filter(s.req, next.Events.Events)
+		events := filterPayloads(s.evaluator, filter(s.req, next.Events.Events))
 		if len(events) == 0 {
 			continue
 		}
@@ -163,6 +178,26 @@ func filter(req *SubscribeRequest, events []structs.Event) []structs.Event {
 	return result
 }
 
+// filterPayloads filters events to only those whose payload matches the
+// filter expression of a subscription. The events are shared between
+// subscriptions so a new slice is returned rather than filtering in place.
+func filterPayloads(evaluator *bexpr.Evaluator, events []structs.Event) []structs.Event {
+	if evaluator == nil || len(events) == 0 {
+		return events
+	}
+
+	var result []structs.Event
+	for _, event := range events {
+		// Events of different topics have different payloads, so failing to
+		// evaluate the filter against a payload is not a match
+		if match, err := evaluator.Evaluate(event.Payload); err == nil && match {
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
+
 func eventMatchesKey(event structs.Event, key string) bool {
 	if event.Key == key {
 		return true
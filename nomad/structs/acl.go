@@ -0,0 +1,1235 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// ACLUpsertRolesRPCMethod is the RPC method for batch creating or
+	// modifying ACL roles.
+	//
+	// Args: ACLRolesUpsertRequest
+	// Reply: ACLRolesUpsertResponse
+	ACLUpsertRolesRPCMethod = "ACL.UpsertRoles"
+
+	// ACLDeleteRolesByIDRPCMethod the RPC method for batch deleting ACL
+	// roles by their ID.
+	//
+	// Args: ACLRolesDeleteByIDRequest
+	// Reply: ACLRolesDeleteByIDResponse
+	ACLDeleteRolesByIDRPCMethod = "ACL.DeleteRolesByID"
+
+	// ACLListRolesRPCMethod is the RPC method for listing ACL roles.
+	//
+	// Args: ACLRolesListRequest
+	// Reply: ACLRolesListResponse
+	ACLListRolesRPCMethod = "ACL.ListRoles"
+
+	// ACLGetRolesByIDRPCMethod is the RPC method for detailing a number of
+	// ACL roles using their ID. This is an internal only RPC endpoint and
+	// used by the ACL Role replication process.
+	//
+	// Args: ACLRolesByIDRequest
+	// Reply: ACLRolesByIDResponse
+	ACLGetRolesByIDRPCMethod = "ACL.GetRolesByID"
+
+	// ACLGetRoleByIDRPCMethod is the RPC method for detailing an individual
+	// ACL role using its ID.
+	//
+	// Args: ACLRoleByIDRequest
+	// Reply: ACLRoleByIDResponse
+	ACLGetRoleByIDRPCMethod = "ACL.GetRoleByID"
+
+	// ACLGetRoleByNameRPCMethod is the RPC method for detailing an
+	// individual ACL role using its name.
+	//
+	// Args: ACLRoleByNameRequest
+	// Reply: ACLRoleByNameResponse
+	ACLGetRoleByNameRPCMethod = "ACL.GetRoleByName"
+
+	// ACLUpsertAuthMethodsRPCMethod is the RPC method for batch creating or
+	// modifying auth methods.
+	//
+	// Args: ACLAuthMethodsUpsertRequest
+	// Reply: ACLAuthMethodsUpsertResponse
+	ACLUpsertAuthMethodsRPCMethod = "ACL.UpsertAuthMethods"
+
+	// ACLDeleteAuthMethodsRPCMethod is the RPC method for batch deleting auth
+	// methods.
+	//
+	// Args: ACLAuthMethodsDeleteRequest
+	// Reply: ACLAuthMethodsDeleteResponse
+	ACLDeleteAuthMethodsRPCMethod = "ACL.DeleteAuthMethods"
+
+	// ACLListAuthMethodsRPCMethod is the RPC method for listing auth methods.
+	//
+	// Args: ACLAuthMethodsListRequest
+	// Reply: ACLAuthMethodsListResponse
+	ACLListAuthMethodsRPCMethod = "ACL.ListAuthMethods"
+
+	// ACLGetAuthMethodRPCMethod is the RPC method for detailing an individual
+	// auth method using its name.
+	//
+	// Args: ACLAuthMethodGetRequest
+	// Reply: ACLAuthMethodGetResponse
+	ACLGetAuthMethodRPCMethod = "ACL.GetAuthMethod"
+
+	// ACLGetAuthMethodsRPCMethod is the RPC method for getting multiple auth
+	// methods using their names. This is an internal only RPC endpoint and
+	// used by the auth method replication process.
+	//
+	// Args: ACLAuthMethodsGetRequest
+	// Reply: ACLAuthMethodsGetResponse
+	ACLGetAuthMethodsRPCMethod = "ACL.GetAuthMethods"
+
+	// ACLUpsertBindingRulesRPCMethod is the RPC method for batch creating or
+	// modifying binding rules.
+	//
+	// Args: ACLBindingRulesUpsertRequest
+	// Reply: ACLBindingRulesUpsertResponse
+	ACLUpsertBindingRulesRPCMethod = "ACL.UpsertBindingRules"
+
+	// ACLDeleteBindingRulesRPCMethod is the RPC method for batch deleting
+	// binding rules.
+	//
+	// Args: ACLBindingRulesDeleteRequest
+	// Reply: ACLBindingRulesDeleteResponse
+	ACLDeleteBindingRulesRPCMethod = "ACL.DeleteBindingRules"
+
+	// ACLListBindingRulesRPCMethod is the RPC method for listing binding
+	// rules.
+	//
+	// Args: ACLBindingRulesListRequest
+	// Reply: ACLBindingRulesListResponse
+	ACLListBindingRulesRPCMethod = "ACL.ListBindingRules"
+
+	// ACLGetBindingRuleRPCMethod is the RPC method for detailing an
+	// individual binding rule using its ID.
+	//
+	// Args: ACLBindingRuleRequest
+	// Reply: ACLBindingRuleResponse
+	ACLGetBindingRuleRPCMethod = "ACL.GetBindingRule"
+
+	// ACLGetBindingRulesRPCMethod is the RPC method for getting multiple
+	// binding rules using their IDs. This is an internal only RPC endpoint
+	// and used by the binding rule replication process.
+	//
+	// Args: ACLBindingRulesRequest
+	// Reply: ACLBindingRulesResponse
+	ACLGetBindingRulesRPCMethod = "ACL.GetBindingRules"
+
+	// ACLOIDCAuthURLRPCMethod is the RPC method for starting the OIDC login
+	// workflow. It generates the OIDC provider URL which will be used for
+	// user authentication.
+	//
+	// Args: ACLOIDCAuthURLRequest
+	// Reply: ACLOIDCAuthURLResponse
+	ACLOIDCAuthURLRPCMethod = "ACL.OIDCAuthURL"
+
+	// ACLOIDCCompleteAuthRPCMethod is the RPC method for completing the OIDC
+	// login workflow. It exchanges the OIDC provider token for a Nomad ACL
+	// token with roles and policies as defined within the auth method
+	// binding rules.
+	//
+	// Args: ACLOIDCCompleteAuthRequest
+	// Reply: ACLLoginResponse
+	ACLOIDCCompleteAuthRPCMethod = "ACL.OIDCCompleteAuth"
+)
+
+const (
+	// maxACLRoleDescriptionLength limits an ACL roles description length.
+	maxACLRoleDescriptionLength = 256
+
+	// maxACLBindingRuleDescriptionLength limits an ACL binding rules
+	// description length.
+	maxACLBindingRuleDescriptionLength = 256
+)
+
+const (
+	// ACLAuthMethodTypeOIDC is the auth method type which uses the OpenID
+	// Connect protocol to authenticate users against an identity provider.
+	ACLAuthMethodTypeOIDC = "OIDC"
+
+	// ACLAuthMethodTokenLocalityLocal and ACLAuthMethodTokenLocalityGlobal
+	// detail whether the tokens generated by an auth method are local to the
+	// region or replicated to all regions.
+	ACLAuthMethodTokenLocalityLocal  = "local"
+	ACLAuthMethodTokenLocalityGlobal = "global"
+)
+
+const (
+	// ACLBindingRuleBindTypeRole is the binding rule bind type which links
+	// the generated token to the ACL role named by the bind name.
+	ACLBindingRuleBindTypeRole = "role"
+
+	// ACLBindingRuleBindTypePolicy is the binding rule bind type which links
+	// the generated token to the ACL policy named by the bind name.
+	ACLBindingRuleBindTypePolicy = "policy"
+
+	// ACLBindingRuleBindTypeManagement is the binding rule bind type which
+	// generates a management token. The bind name is not used.
+	ACLBindingRuleBindTypeManagement = "management"
+)
+
+var (
+	// validACLRoleName is used to validate an ACL role name.
+	validACLRoleName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+
+	// validACLAuthMethodName is used to validate an auth method name.
+	validACLAuthMethodName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+)
+
+// ACLTokenRoleLink is used to link an ACL token to an ACL role. The ACL token
+// can therefore inherit all the ACL policy permissions that the ACL role
+// contains.
+type ACLTokenRoleLink struct {
+
+	// ID is the ACLRole.ID UUID. This field is immutable and represents the
+	// absolute truth for the link.
+	ID string
+
+	// Name is the human friendly identifier for the ACL role and is a
+	// convenience field for operators. This field is always resolved to the
+	// ID and discarded before the token is stored in state. This is because
+	// operators can change the name of an ACL role.
+	Name string
+}
+
+// ACLRole is an abstraction for the ACL system which allows the grouping of
+// ACL policies into a single object. ACL tokens can be created and linked to
+// a role; the token then inherits all the permissions granted by the
+// policies.
+type ACLRole struct {
+
+	// ID is an internally generated UUID for this role and is controlled by
+	// Nomad.
+	ID string
+
+	// Name is unique across the entire set of federated clusters and is
+	// supplied by the operator on role creation. The name can be modified by
+	// updating the role and including the Nomad generated ID. This update
+	// will not affect tokens created and linked to this role. This is a
+	// required field.
+	Name string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the role. This is an operational
+	// field.
+	Description string
+
+	// Policies is an array of ACL policy links. Although currently policies
+	// can only be linked using their name, in the future we will want to add
+	// IDs and thus allow operators to specify either a name, an ID, or both.
+	Policies []*ACLRolePolicyLink
+
+	// Hash is the hashed value of the role and is generated using all fields
+	// above this point.
+	Hash []byte
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRolePolicyLink is used to link a policy to an ACL role. We use a struct
+// rather than a list of strings as in the future we will want to add IDs to
+// policies and then link via these.
+type ACLRolePolicyLink struct {
+
+	// Name is the ACLPolicy.Name value which will be linked to the ACL role.
+	Name string
+}
+
+// SetHash is used to compute and set the hash of the ACL role. This should be
+// called every and each time a user specified field on the role is changed
+// before updating the Nomad state store.
+func (a *ACLRole) SetHash() []byte {
+
+	// Initialize a 256bit Blake2 hash (32 bytes).
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields.
+	_, _ = hash.Write([]byte(a.Name))
+	_, _ = hash.Write([]byte(a.Description))
+
+	for _, policyLink := range a.Policies {
+		_, _ = hash.Write([]byte(policyLink.Name))
+	}
+
+	// Finalize the hash.
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash.
+	a.Hash = hashVal
+	return hashVal
+}
+
+// Validate ensure the ACL role contains valid information which meets Nomad's
+// internal requirements. This does not include any state calls, such as
+// ensuring the linked policies exist.
+func (a *ACLRole) Validate() error {
+
+	var mErr multierror.Error
+
+	if !validACLRoleName.MatchString(a.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name '%s'", a.Name))
+	}
+
+	if len(a.Description) > maxACLRoleDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxACLRoleDescriptionLength))
+	}
+
+	if len(a.Policies) < 1 {
+		mErr.Errors = append(mErr.Errors, errors.New("at least one policy should be specified"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Canonicalize performs basic canonicalization on the ACL role object. It is
+// important for callers to understand certain fields such as ID are set if it
+// is empty, so copies should be taken if needed before calling this function.
+func (a *ACLRole) Canonicalize() {
+	if a.ID == "" {
+		a.ID = uuid.Generate()
+	}
+}
+
+// Equal performs an equality check on the two ACL roles. It
+// handles nil objects.
+func (a *ACLRole) Equal(o *ACLRole) bool {
+	if a == nil || o == nil {
+		return a == o
+	}
+	if len(a.Hash) == 0 {
+		a.SetHash()
+	}
+	if len(o.Hash) == 0 {
+		o.SetHash()
+	}
+	return string(a.Hash) == string(o.Hash)
+}
+
+// Copy creates a deep copy of the ACL role. This copy can then be safely
+// modified. It handles nil objects.
+func (a *ACLRole) Copy() *ACLRole {
+	if a == nil {
+		return nil
+	}
+
+	c := new(ACLRole)
+	*c = *a
+
+	if a.Policies != nil {
+		c.Policies = make([]*ACLRolePolicyLink, len(a.Policies))
+		for i, policyLink := range a.Policies {
+			link := *policyLink
+			c.Policies[i] = &link
+		}
+	}
+	if a.Hash != nil {
+		c.Hash = make([]byte, len(a.Hash))
+		copy(c.Hash, a.Hash)
+	}
+
+	return c
+}
+
+// Stub converts the ACLRole object into a ACLRoleListStub object.
+func (a *ACLRole) Stub() *ACLRoleListStub {
+	return &ACLRoleListStub{
+		ID:          a.ID,
+		Name:        a.Name,
+		Description: a.Description,
+		Policies:    a.Policies,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// ACLRoleListStub is the stub object returned when performing a listing of
+// ACL roles. While it might not currently be different to the full response
+// object, it allows us to future-proof the RPC in the event the ACLRole
+// object grows over time.
+type ACLRoleListStub struct {
+
+	// ID is an internally generated UUID for this role and is controlled by
+	// Nomad.
+	ID string
+
+	// Name is unique across the entire set of federated clusters and is
+	// supplied by the operator on role creation.
+	Name string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the role.
+	Description string
+
+	// Policies is an array of ACL policy links.
+	Policies []*ACLRolePolicyLink
+
+	// Hash is the hashed value of the role.
+	Hash []byte
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRolesUpsertRequest is the request object used to upsert one or more ACL
+// roles.
+type ACLRolesUpsertRequest struct {
+	ACLRoles []*ACLRole
+
+	// AllowMissingPolicies skips the ACL Role policy link verification and is
+	// used by the replication process. The replication cannot ensure policies
+	// are present before ACL Roles are replicated.
+	AllowMissingPolicies bool
+
+	WriteRequest
+}
+
+// ACLRolesUpsertResponse is the response object when one or more ACL roles
+// have been successfully upserted into state.
+type ACLRolesUpsertResponse struct {
+	ACLRoles []*ACLRole
+	WriteMeta
+}
+
+// ACLRolesDeleteByIDRequest is the request object to delete one or more ACL
+// roles using the role ID.
+type ACLRolesDeleteByIDRequest struct {
+	ACLRoleIDs []string
+	WriteRequest
+}
+
+// ACLRolesDeleteByIDResponse is the response object when performing a delete
+// of ACL roles by ID.
+type ACLRolesDeleteByIDResponse struct {
+	WriteMeta
+}
+
+// ACLRolesListRequest is the request object when performing ACL role
+// listings.
+type ACLRolesListRequest struct {
+	QueryOptions
+}
+
+// ACLRolesListResponse is the response object when performing ACL role
+// listings.
+type ACLRolesListResponse struct {
+	ACLRoles []*ACLRoleListStub
+	QueryMeta
+}
+
+// ACLRolesByIDRequest is the request object when performing a lookup of
+// multiple roles by the ID.
+type ACLRolesByIDRequest struct {
+	ACLRoleIDs []string
+	QueryOptions
+}
+
+// ACLRolesByIDResponse is the response object when performing a lookup of
+// multiple roles by their IDs.
+type ACLRolesByIDResponse struct {
+	ACLRoles map[string]*ACLRole
+	QueryMeta
+}
+
+// ACLRoleByIDRequest is the request object to perform a lookup of an ACL
+// role using a specific ID.
+type ACLRoleByIDRequest struct {
+	RoleID string
+	QueryOptions
+}
+
+// ACLRoleByIDResponse is the response object when performing a lookup of an
+// ACL role matching a specific ID.
+type ACLRoleByIDResponse struct {
+	ACLRole *ACLRole
+	QueryMeta
+}
+
+// ACLRoleByNameRequest is the request object to perform a lookup of an ACL
+// role using a specific name.
+type ACLRoleByNameRequest struct {
+	RoleName string
+	QueryOptions
+}
+
+// ACLRoleByNameResponse is the response object when performing a lookup of
+// an ACL role matching a specific name.
+type ACLRoleByNameResponse struct {
+	ACLRole *ACLRole
+	QueryMeta
+}
+
+// ACLAuthMethod is used to capture the properties of an authentication
+// method used for single sign-on. Users authenticate against the identity
+// provider configured by the auth method and exchange the login for a Nomad
+// ACL token, which is linked to the roles and policies detailed by the
+// binding rules of the auth method.
+type ACLAuthMethod struct {
+
+	// Name is the identifier for the auth method and is unique across the
+	// entire set of federated clusters. This is a required field.
+	Name string
+
+	// Type is the SSO identifier this auth method is. Currently, the only
+	// supported value is "OIDC".
+	Type string
+
+	// TokenLocality defines whether the ACL tokens created by this auth
+	// method are local to the region or global. Valid values are "local"
+	// and "global".
+	TokenLocality string
+
+	// MaxTokenTTL is the maximum life of the ACL tokens created by this auth
+	// method. The generated tokens expire once this duration has passed.
+	MaxTokenTTL time.Duration
+
+	// Default identifies whether this is the default auth method used by
+	// the login command when the operator does not specify one. Only one
+	// auth method can be the default.
+	Default bool
+
+	// Config contains the detailed configuration which is specific to the
+	// auth method type.
+	Config *ACLAuthMethodConfig
+
+	// Hash is the hashed value of the auth method and is generated using all
+	// fields above this point.
+	Hash []byte
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is used to store the configuration of an OIDC auth
+// method.
+type ACLAuthMethodConfig struct {
+
+	// OIDCDiscoveryURL is the OIDC discovery URL of the identity provider,
+	// without any .well-known component (base path). It must match the
+	// issuer of the tokens signed by the identity provider.
+	OIDCDiscoveryURL string
+
+	// OIDCClientID is the OAuth client ID configured with the identity
+	// provider.
+	OIDCClientID string
+
+	// OIDCClientSecret is the OAuth client secret configured with the
+	// identity provider.
+	OIDCClientSecret string
+
+	// OIDCScopes is a list of scopes to request in addition to the "openid"
+	// scope.
+	OIDCScopes []string
+
+	// BoundAudiences is the list of audiences of which at least one must be
+	// present within the ID token. If empty, the ID token audience must
+	// contain the OIDC client ID.
+	BoundAudiences []string
+
+	// AllowedRedirectURIs is the list of URIs which the identity provider
+	// may redirect to once the user has authenticated.
+	AllowedRedirectURIs []string
+
+	// DiscoveryCaPem is a list of PEM encoded CA certificates used to
+	// communicate with the identity provider. The system CA pool is used if
+	// this is empty.
+	DiscoveryCaPem []string
+
+	// SigningAlgs is the list of algorithms which are allowed to sign the ID
+	// token. Defaults to "RS256" if empty.
+	SigningAlgs []string
+
+	// ClaimMappings maps the name of a claim holding a single value to the
+	// name of the selector variable it is exposed as, for example
+	// "value.<name>".
+	ClaimMappings map[string]string
+
+	// ListClaimMappings maps the name of a claim holding a list of values to
+	// the name of the selector variable it is exposed as, for example
+	// "list.<name>".
+	ListClaimMappings map[string]string
+}
+
+// validACLAuthMethodSigningAlgs are the ID token signing algorithms supported
+// by OIDC auth methods.
+var validACLAuthMethodSigningAlgs = []string{
+	"RS256", "RS384", "RS512",
+	"ES256", "ES384", "ES512",
+	"PS256", "PS384", "PS512",
+	"EdDSA",
+}
+
+// SetHash is used to compute and set the hash of the auth method. This
+// should be called every and each time a user specified field on the method
+// is changed before updating the Nomad state store.
+func (a *ACLAuthMethod) SetHash() []byte {
+
+	// Initialize a 256bit Blake2 hash (32 bytes).
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields.
+	_, _ = hash.Write([]byte(a.Name))
+	_, _ = hash.Write([]byte(a.Type))
+	_, _ = hash.Write([]byte(a.TokenLocality))
+	_, _ = hash.Write([]byte(a.MaxTokenTTL.String()))
+	_, _ = hash.Write([]byte(strconv.FormatBool(a.Default)))
+
+	if a.Config != nil {
+		_, _ = hash.Write([]byte(a.Config.OIDCDiscoveryURL))
+		_, _ = hash.Write([]byte(a.Config.OIDCClientID))
+		_, _ = hash.Write([]byte(a.Config.OIDCClientSecret))
+		for _, list := range [][]string{
+			a.Config.OIDCScopes,
+			a.Config.BoundAudiences,
+			a.Config.AllowedRedirectURIs,
+			a.Config.DiscoveryCaPem,
+			a.Config.SigningAlgs,
+		} {
+			for _, item := range list {
+				_, _ = hash.Write([]byte(item))
+			}
+		}
+		for _, mapping := range []map[string]string{
+			a.Config.ClaimMappings,
+			a.Config.ListClaimMappings,
+		} {
+			keys := make([]string, 0, len(mapping))
+			for k := range mapping {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				_, _ = hash.Write([]byte(k))
+				_, _ = hash.Write([]byte(mapping[k]))
+			}
+		}
+	}
+
+	// Finalize the hash.
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash.
+	a.Hash = hashVal
+	return hashVal
+}
+
+// Validate ensures the auth method contains valid information which meets
+// Nomad's internal requirements. This does not include any state calls, such
+// as ensuring no other auth method is set as the default.
+func (a *ACLAuthMethod) Validate() error {
+
+	var mErr multierror.Error
+
+	if !validACLAuthMethodName.MatchString(a.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name '%s'", a.Name))
+	}
+
+	if a.Type != ACLAuthMethodTypeOIDC {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid type '%s'", a.Type))
+	}
+
+	switch a.TokenLocality {
+	case ACLAuthMethodTokenLocalityLocal, ACLAuthMethodTokenLocalityGlobal:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid token locality '%s'", a.TokenLocality))
+	}
+
+	if a.MaxTokenTTL <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("max token TTL must be greater than zero"))
+	}
+
+	if a.Config == nil {
+		mErr.Errors = append(mErr.Errors, errors.New("missing config"))
+	} else if err := a.Config.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Canonicalize performs basic canonicalization on the auth method object.
+// The create and modify times are set, so copies should be taken if needed
+// before calling this function.
+func (a *ACLAuthMethod) Canonicalize() {
+	now := time.Now().UTC()
+	if a.CreateTime.IsZero() {
+		a.CreateTime = now
+	}
+	a.ModifyTime = now
+}
+
+// TokenLocalityIsGlobal returns whether the tokens created by the auth
+// method are global.
+func (a *ACLAuthMethod) TokenLocalityIsGlobal() bool {
+	return a.TokenLocality == ACLAuthMethodTokenLocalityGlobal
+}
+
+// Equal performs an equality check on the two auth methods. It handles nil
+// objects.
+func (a *ACLAuthMethod) Equal(o *ACLAuthMethod) bool {
+	if a == nil || o == nil {
+		return a == o
+	}
+	if len(a.Hash) == 0 {
+		a.SetHash()
+	}
+	if len(o.Hash) == 0 {
+		o.SetHash()
+	}
+	return string(a.Hash) == string(o.Hash)
+}
+
+// Copy creates a deep copy of the auth method. This copy can then be safely
+// modified. It handles nil objects.
+func (a *ACLAuthMethod) Copy() *ACLAuthMethod {
+	if a == nil {
+		return nil
+	}
+
+	c := new(ACLAuthMethod)
+	*c = *a
+
+	c.Config = a.Config.Copy()
+	if a.Hash != nil {
+		c.Hash = make([]byte, len(a.Hash))
+		copy(c.Hash, a.Hash)
+	}
+
+	return c
+}
+
+// Stub converts the ACLAuthMethod object into a ACLAuthMethodStub object.
+func (a *ACLAuthMethod) Stub() *ACLAuthMethodStub {
+	return &ACLAuthMethodStub{
+		Name:        a.Name,
+		Type:        a.Type,
+		Default:     a.Default,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// Validate ensures the OIDC configuration of an auth method is complete.
+func (c *ACLAuthMethodConfig) Validate() error {
+
+	var mErr multierror.Error
+
+	if c.OIDCDiscoveryURL == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing OIDC discovery URL"))
+	} else if u, err := url.Parse(c.OIDCDiscoveryURL); err != nil || u.Scheme == "" || u.Host == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid OIDC discovery URL '%s'", c.OIDCDiscoveryURL))
+	}
+
+	if c.OIDCClientID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing OIDC client ID"))
+	}
+
+	if len(c.AllowedRedirectURIs) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("at least one allowed redirect URI should be specified"))
+	}
+
+	for _, alg := range c.SigningAlgs {
+		if !helper.SliceStringContains(validACLAuthMethodSigningAlgs, alg) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid signing algorithm '%s'", alg))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy creates a deep copy of the auth method config. It handles nil
+// objects.
+func (c *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
+	if c == nil {
+		return nil
+	}
+
+	n := new(ACLAuthMethodConfig)
+	*n = *c
+
+	n.OIDCScopes = helper.CopySliceString(c.OIDCScopes)
+	n.BoundAudiences = helper.CopySliceString(c.BoundAudiences)
+	n.AllowedRedirectURIs = helper.CopySliceString(c.AllowedRedirectURIs)
+	n.DiscoveryCaPem = helper.CopySliceString(c.DiscoveryCaPem)
+	n.SigningAlgs = helper.CopySliceString(c.SigningAlgs)
+	n.ClaimMappings = helper.CopyMapStringString(c.ClaimMappings)
+	n.ListClaimMappings = helper.CopyMapStringString(c.ListClaimMappings)
+
+	return n
+}
+
+// ACLAuthMethodStub is the stub object returned when performing a listing of
+// auth methods. It omits the configuration, which contains the OIDC client
+// secret, so that it can be listed without an ACL token.
+type ACLAuthMethodStub struct {
+	Name    string
+	Type    string
+	Default bool
+
+	// Hash is the hashed value of the auth method.
+	Hash []byte
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodsUpsertRequest is the request object used to upsert one or
+// more auth methods.
+type ACLAuthMethodsUpsertRequest struct {
+	AuthMethods []*ACLAuthMethod
+	WriteRequest
+}
+
+// ACLAuthMethodsUpsertResponse is the response object when one or more auth
+// methods have been successfully upserted into state.
+type ACLAuthMethodsUpsertResponse struct {
+	AuthMethods []*ACLAuthMethod
+	WriteMeta
+}
+
+// ACLAuthMethodsDeleteRequest is the request object to delete one or more
+// auth methods using their name.
+type ACLAuthMethodsDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLAuthMethodsDeleteResponse is the response object when performing a
+// delete of auth methods.
+type ACLAuthMethodsDeleteResponse struct {
+	WriteMeta
+}
+
+// ACLAuthMethodsListRequest is the request object when performing auth
+// method listings.
+type ACLAuthMethodsListRequest struct {
+	QueryOptions
+}
+
+// ACLAuthMethodsListResponse is the response object when performing auth
+// method listings.
+type ACLAuthMethodsListResponse struct {
+	AuthMethods []*ACLAuthMethodStub
+	QueryMeta
+}
+
+// ACLAuthMethodGetRequest is the request object to perform a lookup of an
+// auth method using its name.
+type ACLAuthMethodGetRequest struct {
+	MethodName string
+	QueryOptions
+}
+
+// ACLAuthMethodGetResponse is the response object when performing a lookup
+// of an auth method matching a specific name.
+type ACLAuthMethodGetResponse struct {
+	AuthMethod *ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodsGetRequest is the request object when performing a lookup of
+// multiple auth methods by their names.
+type ACLAuthMethodsGetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLAuthMethodsGetResponse is the response object when performing a lookup
+// of multiple auth methods by their names.
+type ACLAuthMethodsGetResponse struct {
+	AuthMethods map[string]*ACLAuthMethod
+	QueryMeta
+}
+
+// ACLBindingRule contains a direct relation to an auth method and defines
+// how the claims of an authenticated user map to Nomad ACL roles or
+// policies.
+type ACLBindingRule struct {
+
+	// ID is an internally generated UUID for this binding rule and is
+	// controlled by Nomad.
+	ID string
+
+	// Description is a human-readable, operator set description that can
+	// provide additional context about the binding rule. This is an
+	// operational field.
+	Description string
+
+	// AuthMethod is the name of the auth method for which this rule
+	// applies. This is a required field.
+	AuthMethod string
+
+	// Selector is an expression that matches against verified identity
+	// attributes returned from the auth method during login. An empty
+	// selector matches every login.
+	Selector string
+
+	// BindType adjusts how this binding rule is applied at login time. Valid
+	// values are "role", "policy" and "management".
+	BindType string
+
+	// BindName is the target of the binding. It is the name of an ACL role
+	// or policy and may be templated using the selector variables, for
+	// example "${value.team}". It must be empty when the bind type is
+	// "management".
+	BindName string
+
+	// Hash is the hashed value of the binding rule and is generated using
+	// all fields above this point.
+	Hash []byte
+
+	CreateTime  time.Time
+	ModifyTime  time.Time
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the binding rule. This
+// should be called every and each time a user specified field on the rule is
+// changed before updating the Nomad state store.
+func (a *ACLBindingRule) SetHash() []byte {
+
+	// Initialize a 256bit Blake2 hash (32 bytes).
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields.
+	_, _ = hash.Write([]byte(a.ID))
+	_, _ = hash.Write([]byte(a.Description))
+	_, _ = hash.Write([]byte(a.AuthMethod))
+	_, _ = hash.Write([]byte(a.Selector))
+	_, _ = hash.Write([]byte(a.BindType))
+	_, _ = hash.Write([]byte(a.BindName))
+
+	// Finalize the hash.
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash.
+	a.Hash = hashVal
+	return hashVal
+}
+
+// Validate ensures the binding rule contains valid information which meets
+// Nomad's internal requirements. This does not include any state calls, such
+// as ensuring the linked auth method exists.
+func (a *ACLBindingRule) Validate() error {
+
+	var mErr multierror.Error
+
+	if a.AuthMethod == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("auth method is missing"))
+	}
+
+	if len(a.Description) > maxACLBindingRuleDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxACLBindingRuleDescriptionLength))
+	}
+
+	switch a.BindType {
+	case ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy:
+		if a.BindName == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("bind name is missing"))
+		}
+	case ACLBindingRuleBindTypeManagement:
+		if a.BindName != "" {
+			mErr.Errors = append(mErr.Errors, errors.New("bind name should be empty for management bind type"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid bind type '%s'", a.BindType))
+	}
+
+	if a.Selector != "" {
+		if _, err := bexpr.CreateEvaluator(a.Selector); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid selector: %v", err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Canonicalize performs basic canonicalization on the binding rule object.
+// It is important for callers to understand certain fields such as ID are
+// set if it is empty, so copies should be taken if needed before calling
+// this function.
+func (a *ACLBindingRule) Canonicalize() {
+	now := time.Now().UTC()
+	if a.ID == "" {
+		a.ID = uuid.Generate()
+		a.CreateTime = now
+	}
+	a.ModifyTime = now
+}
+
+// Equal performs an equality check on the two binding rules. It handles nil
+// objects.
+func (a *ACLBindingRule) Equal(o *ACLBindingRule) bool {
+	if a == nil || o == nil {
+		return a == o
+	}
+	if len(a.Hash) == 0 {
+		a.SetHash()
+	}
+	if len(o.Hash) == 0 {
+		o.SetHash()
+	}
+	return string(a.Hash) == string(o.Hash)
+}
+
+// Copy creates a deep copy of the binding rule. This copy can then be safely
+// modified. It handles nil objects.
+func (a *ACLBindingRule) Copy() *ACLBindingRule {
+	if a == nil {
+		return nil
+	}
+
+	c := new(ACLBindingRule)
+	*c = *a
+
+	if a.Hash != nil {
+		c.Hash = make([]byte, len(a.Hash))
+		copy(c.Hash, a.Hash)
+	}
+
+	return c
+}
+
+// Stub converts the ACLBindingRule object into a ACLBindingRuleListStub
+// object.
+func (a *ACLBindingRule) Stub() *ACLBindingRuleListStub {
+	return &ACLBindingRuleListStub{
+		ID:          a.ID,
+		Description: a.Description,
+		AuthMethod:  a.AuthMethod,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// ACLBindingRuleListStub is the stub object returned when performing a
+// listing of binding rules.
+type ACLBindingRuleListStub struct {
+	ID          string
+	Description string
+	AuthMethod  string
+
+	// Hash is the hashed value of the binding rule.
+	Hash []byte
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRulesUpsertRequest is the request object used to upsert one or
+// more binding rules.
+type ACLBindingRulesUpsertRequest struct {
+	ACLBindingRules []*ACLBindingRule
+
+	// AllowMissingAuthMethods skips the binding rule auth method
+	// verification and is used by the replication process. The replication
+	// cannot ensure auth methods are present before binding rules are
+	// replicated.
+	AllowMissingAuthMethods bool
+
+	WriteRequest
+}
+
+// ACLBindingRulesUpsertResponse is the response object when one or more
+// binding rules have been successfully upserted into state.
+type ACLBindingRulesUpsertResponse struct {
+	ACLBindingRules []*ACLBindingRule
+	WriteMeta
+}
+
+// ACLBindingRulesDeleteRequest is the request object to delete one or more
+// binding rules using their ID.
+type ACLBindingRulesDeleteRequest struct {
+	ACLBindingRuleIDs []string
+	WriteRequest
+}
+
+// ACLBindingRulesDeleteResponse is the response object when performing a
+// delete of binding rules.
+type ACLBindingRulesDeleteResponse struct {
+	WriteMeta
+}
+
+// ACLBindingRulesListRequest is the request object when performing binding
+// rule listings.
+type ACLBindingRulesListRequest struct {
+	QueryOptions
+}
+
+// ACLBindingRulesListResponse is the response object when performing binding
+// rule listings.
+type ACLBindingRulesListResponse struct {
+	ACLBindingRules []*ACLBindingRuleListStub
+	QueryMeta
+}
+
+// ACLBindingRulesRequest is the request object when performing a lookup of
+// multiple binding rules by their IDs.
+type ACLBindingRulesRequest struct {
+	ACLBindingRuleIDs []string
+	QueryOptions
+}
+
+// ACLBindingRulesResponse is the response object when performing a lookup of
+// multiple binding rules by their IDs.
+type ACLBindingRulesResponse struct {
+	ACLBindingRules map[string]*ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleRequest is the request object to perform a lookup of a
+// binding rule using its ID.
+type ACLBindingRuleRequest struct {
+	ACLBindingRuleID string
+	QueryOptions
+}
+
+// ACLBindingRuleResponse is the response object when performing a lookup of
+// a binding rule matching a specific ID.
+type ACLBindingRuleResponse struct {
+	ACLBindingRule *ACLBindingRule
+	QueryMeta
+}
+
+// ACLOIDCAuthURLRequest is the request object to begin the OIDC login
+// workflow.
+type ACLOIDCAuthURLRequest struct {
+
+	// AuthMethodName is the OIDC auth method to use. This is a required
+	// field.
+	AuthMethodName string
+
+	// RedirectURI is the URL that the identity provider will redirect to
+	// once the user has authenticated. It must be one of the allowed
+	// redirect URIs of the auth method. This is a required field.
+	RedirectURI string
+
+	// ClientNonce is a randomly generated string to prevent replay attacks.
+	// It is up to the client to generate this and Go integrations should use
+	// the uuid.Generate function. This is a required field.
+	ClientNonce string
+
+	WriteRequest
+}
+
+// Validate ensures the request object contains all the required fields in
+// order to start the OIDC login workflow.
+func (a *ACLOIDCAuthURLRequest) Validate() error {
+
+	var mErr multierror.Error
+
+	if a.AuthMethodName == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing auth method name"))
+	}
+	if a.ClientNonce == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing client nonce"))
+	}
+	if a.RedirectURI == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing redirect URI"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ACLOIDCAuthURLResponse is the response when starting the OIDC login
+// workflow.
+type ACLOIDCAuthURLResponse struct {
+
+	// AuthURL is the URL the user must visit in order to authenticate
+	// against the identity provider.
+	AuthURL string
+
+	WriteMeta
+}
+
+// ACLOIDCCompleteAuthRequest is the request object to complete the OIDC
+// login workflow and exchange the identity provider login for a Nomad ACL
+// token.
+type ACLOIDCCompleteAuthRequest struct {
+
+	// AuthMethodName is the OIDC auth method to use. This is a required
+	// field.
+	AuthMethodName string
+
+	// ClientNonce is the nonce which was passed when starting the OIDC login
+	// workflow. This is a required field.
+	ClientNonce string
+
+	// State and Code are the parameters passed by the identity provider to
+	// the redirect URI once the user has authenticated. These are required
+	// fields.
+	State string
+	Code  string
+
+	// RedirectURI is the URL that was passed when starting the OIDC login
+	// workflow. This is a required field.
+	RedirectURI string
+
+	WriteRequest
+}
+
+// Validate ensures the request object contains all the required fields in
+// order to complete the OIDC login workflow.
+func (a *ACLOIDCCompleteAuthRequest) Validate() error {
+
+	var mErr multierror.Error
+
+	if a.AuthMethodName == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing auth method name"))
+	}
+	if a.ClientNonce == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing client nonce"))
+	}
+	if a.State == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing state"))
+	}
+	if a.Code == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing code"))
+	}
+	if a.RedirectURI == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing redirect URI"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ACLLoginResponse is the response when a user successfully exchanges an
+// auth method login for a Nomad ACL token.
+type ACLLoginResponse struct {
+
+	// ACLToken is the token created by the login. The token expires once the
+	// max token TTL of the auth method has passed.
+	ACLToken *ACLToken
+
+	WriteMeta
+}
@@ -0,0 +1,560 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRole_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	aclRole := &ACLRole{
+		Name:        "acl-role-" + uuid.Short(),
+		Description: "mocked-test-acl-role",
+		Policies: []*ACLRolePolicyLink{
+			{Name: "mocked-test-policy-1"},
+			{Name: "mocked-test-policy-2"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	result := aclRole.SetHash()
+	require.Len(t, result, 32)
+	require.Equal(t, result, aclRole.Hash)
+
+	// Modifying a user field should change the hash; modifying an index
+	// should not.
+	aclRole.Description = "mocked-test-acl-role-updated"
+	updatedResult := aclRole.SetHash()
+	require.NotEqual(t, result, updatedResult)
+
+	aclRole.ModifyIndex = 20
+	require.Equal(t, updatedResult, aclRole.SetHash())
+}
+
+func TestACLRole_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name                  string
+		inputACLRole          *ACLRole
+		expectedErrorContains string
+	}{
+		{
+			name:                  "role name too long",
+			inputACLRole:          &ACLRole{Name: uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate()},
+			expectedErrorContains: "invalid name",
+		},
+		{
+			name:                  "role name contains invalid characters",
+			inputACLRole:          &ACLRole{Name: "--#$%$^%_%%_?>"},
+			expectedErrorContains: "invalid name",
+		},
+		{
+			name: "role description too long",
+			inputACLRole: &ACLRole{
+				Name:        "acl-role",
+				Description: uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate(),
+			},
+			expectedErrorContains: "description longer than",
+		},
+		{
+			name:                  "no policies",
+			inputACLRole:          &ACLRole{Name: "acl-role"},
+			expectedErrorContains: "at least one policy should be specified",
+		},
+		{
+			name: "valid",
+			inputACLRole: &ACLRole{
+				Name:        "acl-role",
+				Description: "mocked-test-acl-role",
+				Policies:    []*ACLRolePolicyLink{{Name: "policy-1"}},
+			},
+			expectedErrorContains: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.inputACLRole.Validate()
+			if tc.expectedErrorContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErrorContains)
+			}
+		})
+	}
+}
+
+func TestACLRole_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	// An empty ID should be generated.
+	aclRole := &ACLRole{}
+	aclRole.Canonicalize()
+	require.NotEmpty(t, aclRole.ID)
+
+	// An existing ID should be left untouched.
+	existingID := uuid.Generate()
+	aclRole = &ACLRole{ID: existingID}
+	aclRole.Canonicalize()
+	require.Equal(t, existingID, aclRole.ID)
+}
+
+func TestACLRole_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	var nilRole *ACLRole
+	require.Nil(t, nilRole.Copy())
+
+	aclRole := &ACLRole{
+		ID:          uuid.Generate(),
+		Name:        "acl-role",
+		Description: "mocked-test-acl-role",
+		Policies:    []*ACLRolePolicyLink{{Name: "policy-1"}},
+	}
+	aclRole.SetHash()
+
+	copiedRole := aclRole.Copy()
+	require.Equal(t, aclRole, copiedRole)
+	require.True(t, aclRole.Equal(copiedRole))
+
+	// Modifying the copy must not modify the original.
+	copiedRole.Policies[0].Name = "policy-2"
+	copiedRole.SetHash()
+	require.Equal(t, "policy-1", aclRole.Policies[0].Name)
+	require.False(t, aclRole.Equal(copiedRole))
+}
+
+func TestACLToken_Validate_Roles(t *testing.T) {
+	ci.Parallel(t)
+
+	// A client token linked only to roles is valid.
+	token := &ACLToken{
+		Type:  ACLClientToken,
+		Roles: []*ACLTokenRoleLink{{ID: uuid.Generate()}},
+	}
+	require.NoError(t, token.Validate(1*time.Minute, 24*time.Hour, nil))
+
+	// A client token with neither policies nor roles is invalid.
+	token.Roles = nil
+	require.ErrorContains(t, token.Validate(1*time.Minute, 24*time.Hour, nil), "missing policies or roles")
+
+	// A management token cannot be linked to roles.
+	token = &ACLToken{
+		Type:  ACLManagementToken,
+		Roles: []*ACLTokenRoleLink{{ID: uuid.Generate()}},
+	}
+	require.ErrorContains(t, token.Validate(1*time.Minute, 24*time.Hour, nil), "cannot be associated with roles")
+}
+
+func TestACLToken_IsExpired(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now().UTC()
+
+	// A nil token or a token without an expiration time never expires.
+	var nilToken *ACLToken
+	require.False(t, nilToken.IsExpired(now))
+	require.False(t, (&ACLToken{}).IsExpired(now))
+
+	expirationTime := now.Add(time.Hour)
+	token := &ACLToken{ExpirationTime: &expirationTime}
+	require.False(t, token.IsExpired(now))
+	require.True(t, token.IsExpired(now.Add(2*time.Hour)))
+}
+
+func TestACLToken_HasExpirationTime(t *testing.T) {
+	ci.Parallel(t)
+
+	var nilToken *ACLToken
+	require.False(t, nilToken.HasExpirationTime())
+	require.False(t, (&ACLToken{}).HasExpirationTime())
+	require.False(t, (&ACLToken{ExpirationTime: &time.Time{}}).HasExpirationTime())
+
+	expirationTime := time.Now().UTC().Add(time.Hour)
+	require.True(t, (&ACLToken{ExpirationTime: &expirationTime}).HasExpirationTime())
+}
+
+func TestACLToken_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	// A token without an expiration TTL does not get an expiration time.
+	token := &ACLToken{Type: ACLManagementToken}
+	token.Canonicalize()
+	require.NotEmpty(t, token.AccessorID)
+	require.NotEmpty(t, token.SecretID)
+	require.False(t, token.CreateTime.IsZero())
+	require.Nil(t, token.ExpirationTime)
+
+	// A token with an expiration TTL has its expiration time computed from
+	// the create time.
+	token = &ACLToken{Type: ACLManagementToken, ExpirationTTL: 10 * time.Minute}
+	token.Canonicalize()
+	require.NotNil(t, token.ExpirationTime)
+	require.Equal(t, token.CreateTime.Add(10*time.Minute), *token.ExpirationTime)
+}
+
+func TestACLToken_Validate_Expiration(t *testing.T) {
+	ci.Parallel(t)
+
+	minTTL, maxTTL := time.Minute, 24*time.Hour
+
+	// A TTL within the bounds is valid.
+	token := &ACLToken{Type: ACLManagementToken, ExpirationTTL: time.Hour}
+	token.Canonicalize()
+	require.NoError(t, token.Validate(minTTL, maxTTL, nil))
+
+	// A TTL outside the bounds is invalid.
+	token = &ACLToken{Type: ACLManagementToken, ExpirationTTL: time.Second}
+	token.Canonicalize()
+	require.ErrorContains(t, token.Validate(minTTL, maxTTL, nil), "expiration time cannot be less than")
+
+	token = &ACLToken{Type: ACLManagementToken, ExpirationTTL: 48 * time.Hour}
+	token.Canonicalize()
+	require.ErrorContains(t, token.Validate(minTTL, maxTTL, nil), "expiration time cannot be less than")
+
+	// A negative TTL is invalid.
+	token = &ACLToken{Type: ACLManagementToken, ExpirationTTL: -time.Hour}
+	token.Canonicalize()
+	require.ErrorContains(t, token.Validate(minTTL, maxTTL, nil), "should not be negative")
+
+	// The expiration of an existing token cannot be modified.
+	existing := &ACLToken{Type: ACLManagementToken, ExpirationTTL: time.Hour}
+	existing.Canonicalize()
+
+	update := existing.Copy()
+	require.NoError(t, update.Validate(minTTL, maxTTL, existing))
+
+	update.ExpirationTTL = 2 * time.Hour
+	require.ErrorContains(t, update.Validate(minTTL, maxTTL, existing), "cannot update expiration TTL")
+
+	update = existing.Copy()
+	newExpirationTime := existing.ExpirationTime.Add(time.Hour)
+	update.ExpirationTime = &newExpirationTime
+	require.ErrorContains(t, update.Validate(minTTL, maxTTL, existing), "cannot update expiration time")
+
+	// An update which does not specify the expiration is valid, as the
+	// existing values are retained.
+	update = existing.Copy()
+	update.ExpirationTTL = 0
+	update.ExpirationTime = nil
+	require.NoError(t, update.Validate(minTTL, maxTTL, existing))
+}
+
+func TestACLToken_Copy_ExpirationTime(t *testing.T) {
+	ci.Parallel(t)
+
+	expirationTime := time.Now().UTC().Add(time.Hour)
+	token := &ACLToken{
+		AccessorID:     uuid.Generate(),
+		Type:           ACLClientToken,
+		Policies:       []string{"foo"},
+		ExpirationTime: &expirationTime,
+	}
+	token.SetHash()
+
+	// Modifying the copy must not modify the original.
+	copiedToken := token.Copy()
+	require.Equal(t, token, copiedToken)
+	*copiedToken.ExpirationTime = expirationTime.Add(time.Hour)
+	require.Equal(t, expirationTime, *token.ExpirationTime)
+}
+
+// newTestACLAuthMethod returns a valid OIDC auth method.
+func newTestACLAuthMethod() *ACLAuthMethod {
+	return &ACLAuthMethod{
+		Name:          "acl-auth-method-" + uuid.Short(),
+		Type:          ACLAuthMethodTypeOIDC,
+		TokenLocality: ACLAuthMethodTokenLocalityLocal,
+		MaxTokenTTL:   time.Hour,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "http://example.com",
+			OIDCClientID:        "mock",
+			OIDCClientSecret:    "very secret secret",
+			BoundAudiences:      []string{"audience1", "audience2"},
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+			SigningAlgs:         []string{"RS256"},
+			ClaimMappings:       map[string]string{"first_name": "name"},
+			ListClaimMappings:   map[string]string{"groups": "roles"},
+		},
+	}
+}
+
+func TestACLAuthMethod_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	authMethod := newTestACLAuthMethod()
+	result := authMethod.SetHash()
+	require.Len(t, result, 32)
+	require.Equal(t, result, authMethod.Hash)
+
+	// Modifying a user field, including the config, should change the hash;
+	// modifying an index should not.
+	authMethod.Config.OIDCClientID = "mock-updated"
+	updatedResult := authMethod.SetHash()
+	require.NotEqual(t, result, updatedResult)
+
+	authMethod.ModifyIndex = 20
+	require.Equal(t, updatedResult, authMethod.SetHash())
+}
+
+func TestACLAuthMethod_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name                  string
+		modifyFn              func(a *ACLAuthMethod)
+		expectedErrorContains string
+	}{
+		{
+			name:                  "invalid name",
+			modifyFn:              func(a *ACLAuthMethod) { a.Name = "--#$%$^%_%%_?>" },
+			expectedErrorContains: "invalid name",
+		},
+		{
+			name:                  "invalid type",
+			modifyFn:              func(a *ACLAuthMethod) { a.Type = "LDAP" },
+			expectedErrorContains: "invalid type",
+		},
+		{
+			name:                  "invalid token locality",
+			modifyFn:              func(a *ACLAuthMethod) { a.TokenLocality = "regional" },
+			expectedErrorContains: "invalid token locality",
+		},
+		{
+			name:                  "zero max token TTL",
+			modifyFn:              func(a *ACLAuthMethod) { a.MaxTokenTTL = 0 },
+			expectedErrorContains: "max token TTL must be greater than zero",
+		},
+		{
+			name:                  "missing config",
+			modifyFn:              func(a *ACLAuthMethod) { a.Config = nil },
+			expectedErrorContains: "missing config",
+		},
+		{
+			name:                  "missing discovery URL",
+			modifyFn:              func(a *ACLAuthMethod) { a.Config.OIDCDiscoveryURL = "" },
+			expectedErrorContains: "missing OIDC discovery URL",
+		},
+		{
+			name:                  "missing client ID",
+			modifyFn:              func(a *ACLAuthMethod) { a.Config.OIDCClientID = "" },
+			expectedErrorContains: "missing OIDC client ID",
+		},
+		{
+			name:                  "missing redirect URIs",
+			modifyFn:              func(a *ACLAuthMethod) { a.Config.AllowedRedirectURIs = nil },
+			expectedErrorContains: "at least one allowed redirect URI should be specified",
+		},
+		{
+			name:                  "invalid signing algorithm",
+			modifyFn:              func(a *ACLAuthMethod) { a.Config.SigningAlgs = []string{"HS256"} },
+			expectedErrorContains: "invalid signing algorithm",
+		},
+		{
+			name:                  "valid",
+			modifyFn:              func(a *ACLAuthMethod) {},
+			expectedErrorContains: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authMethod := newTestACLAuthMethod()
+			tc.modifyFn(authMethod)
+			err := authMethod.Validate()
+			if tc.expectedErrorContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErrorContains)
+			}
+		})
+	}
+}
+
+func TestACLAuthMethod_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	// An empty create time should be set.
+	authMethod := newTestACLAuthMethod()
+	authMethod.Canonicalize()
+	require.False(t, authMethod.CreateTime.IsZero())
+	require.False(t, authMethod.ModifyTime.IsZero())
+
+	// An existing create time should be left untouched.
+	createTime := time.Now().UTC().Add(-time.Hour)
+	authMethod = newTestACLAuthMethod()
+	authMethod.CreateTime = createTime
+	authMethod.Canonicalize()
+	require.Equal(t, createTime, authMethod.CreateTime)
+	require.True(t, authMethod.ModifyTime.After(createTime))
+}
+
+func TestACLAuthMethod_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	var nilAuthMethod *ACLAuthMethod
+	require.Nil(t, nilAuthMethod.Copy())
+
+	authMethod := newTestACLAuthMethod()
+	authMethod.SetHash()
+
+	copiedAuthMethod := authMethod.Copy()
+	require.Equal(t, authMethod, copiedAuthMethod)
+	require.True(t, authMethod.Equal(copiedAuthMethod))
+
+	// Modifying the copy must not modify the original.
+	copiedAuthMethod.Config.BoundAudiences[0] = "audience3"
+	copiedAuthMethod.Config.ClaimMappings["last_name"] = "surname"
+	copiedAuthMethod.SetHash()
+	require.Equal(t, "audience1", authMethod.Config.BoundAudiences[0])
+	require.NotContains(t, authMethod.Config.ClaimMappings, "last_name")
+	require.False(t, authMethod.Equal(copiedAuthMethod))
+}
+
+func TestACLBindingRule_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	bindingRule := &ACLBindingRule{
+		ID:          uuid.Generate(),
+		AuthMethod:  "auth0",
+		Selector:    `"engineering" in list.roles`,
+		BindType:    ACLBindingRuleBindTypeRole,
+		BindName:    "engineering",
+		CreateIndex: 10,
+		ModifyIndex: 10,
+	}
+	result := bindingRule.SetHash()
+	require.Len(t, result, 32)
+	require.Equal(t, result, bindingRule.Hash)
+
+	// Modifying a user field should change the hash; modifying an index
+	// should not.
+	bindingRule.BindName = "engineering-updated"
+	updatedResult := bindingRule.SetHash()
+	require.NotEqual(t, result, updatedResult)
+
+	bindingRule.ModifyIndex = 20
+	require.Equal(t, updatedResult, bindingRule.SetHash())
+}
+
+func TestACLBindingRule_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name                  string
+		inputBindingRule      *ACLBindingRule
+		expectedErrorContains string
+	}{
+		{
+			name:                  "missing auth method",
+			inputBindingRule:      &ACLBindingRule{BindType: ACLBindingRuleBindTypeManagement},
+			expectedErrorContains: "auth method is missing",
+		},
+		{
+			name: "description too long",
+			inputBindingRule: &ACLBindingRule{
+				AuthMethod:  "auth0",
+				Description: uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate() + uuid.Generate(),
+				BindType:    ACLBindingRuleBindTypeManagement,
+			},
+			expectedErrorContains: "description longer than",
+		},
+		{
+			name:                  "missing bind name",
+			inputBindingRule:      &ACLBindingRule{AuthMethod: "auth0", BindType: ACLBindingRuleBindTypePolicy},
+			expectedErrorContains: "bind name is missing",
+		},
+		{
+			name: "management bind name",
+			inputBindingRule: &ACLBindingRule{
+				AuthMethod: "auth0",
+				BindType:   ACLBindingRuleBindTypeManagement,
+				BindName:   "admin",
+			},
+			expectedErrorContains: "bind name should be empty for management bind type",
+		},
+		{
+			name:                  "invalid bind type",
+			inputBindingRule:      &ACLBindingRule{AuthMethod: "auth0", BindType: "group", BindName: "admin"},
+			expectedErrorContains: "invalid bind type",
+		},
+		{
+			name: "invalid selector",
+			inputBindingRule: &ACLBindingRule{
+				AuthMethod: "auth0",
+				Selector:   "value.team ==",
+				BindType:   ACLBindingRuleBindTypeRole,
+				BindName:   "engineering",
+			},
+			expectedErrorContains: "invalid selector",
+		},
+		{
+			name: "valid",
+			inputBindingRule: &ACLBindingRule{
+				AuthMethod: "auth0",
+				Selector:   `"engineering" in list.roles`,
+				BindType:   ACLBindingRuleBindTypeRole,
+				BindName:   "engineering",
+			},
+			expectedErrorContains: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.inputBindingRule.Validate()
+			if tc.expectedErrorContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErrorContains)
+			}
+		})
+	}
+}
+
+func TestACLBindingRule_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	// An empty ID should be generated along with the create time.
+	bindingRule := &ACLBindingRule{}
+	bindingRule.Canonicalize()
+	require.NotEmpty(t, bindingRule.ID)
+	require.False(t, bindingRule.CreateTime.IsZero())
+	require.False(t, bindingRule.ModifyTime.IsZero())
+
+	// An existing ID should be left untouched.
+	existingID := uuid.Generate()
+	bindingRule = &ACLBindingRule{ID: existingID}
+	bindingRule.Canonicalize()
+	require.Equal(t, existingID, bindingRule.ID)
+}
+
+func TestACLBindingRule_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	var nilBindingRule *ACLBindingRule
+	require.Nil(t, nilBindingRule.Copy())
+
+	bindingRule := &ACLBindingRule{
+		ID:         uuid.Generate(),
+		AuthMethod: "auth0",
+		BindType:   ACLBindingRuleBindTypeRole,
+		BindName:   "engineering",
+	}
+	bindingRule.SetHash()
+
+	copiedBindingRule := bindingRule.Copy()
+	require.Equal(t, bindingRule, copiedBindingRule)
+	require.True(t, bindingRule.Equal(copiedBindingRule))
+
+	copiedBindingRule.BindName = "engineering-updated"
+	copiedBindingRule.SetHash()
+	require.False(t, bindingRule.Equal(copiedBindingRule))
+}
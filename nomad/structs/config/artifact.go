@@ -34,6 +34,37 @@ type ArtifactConfig struct {
 	// S3Timeout is the duration in which an S3 operation must complete or
 	// it will be canceled. Defaults to 30m.
 	S3Timeout *string `hcl:"s3_timeout"`
+
+	// CacheMaxSize is the maximum size of the artifacts pinned by a checksum
+	// that are cached by the client. Setting it to 0 disables the cache.
+	// Defaults to 10GB.
+	CacheMaxSize *string `hcl:"cache_max_size"`
+
+	// DisableSandbox downloads artifacts in the client process instead of an
+	// isolated subprocess. Defaults to false.
+	DisableSandbox *bool `hcl:"disable_sandbox"`
+
+	// DisableFilesystemIsolation disables the Landlock restrictions that
+	// only allow the sandbox subprocess to write to its download directory.
+	// Hosts without Landlock fail to download artifacts unless it is set.
+	// Defaults to false.
+	DisableFilesystemIsolation *bool `hcl:"disable_filesystem_isolation"`
+
+	// SandboxUser is the unprivileged user the sandbox subprocess runs as
+	// when the client runs as root. Defaults to the user of the client.
+	SandboxUser *string `hcl:"sandbox_user"`
+
+	// SandboxMemoryMax is the maximum memory the sandbox subprocess may use.
+	// Setting it to 0 disables the limit. Defaults to 2GB.
+	SandboxMemoryMax *string `hcl:"sandbox_memory_max"`
+
+	// SandboxCPUMax is the maximum number of CPU cores the sandbox
+	// subprocess may use. Setting it to 0 disables the limit. Defaults to 1.
+	SandboxCPUMax *float64 `hcl:"sandbox_cpu_max"`
+
+	// SandboxTimeout is the duration in which the sandbox subprocess must
+	// download an artifact or it will be killed. Defaults to 1h.
+	SandboxTimeout *string `hcl:"sandbox_timeout"`
 }
 
 func (a *ArtifactConfig) Copy() *ArtifactConfig {
@@ -60,6 +91,27 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 	if a.S3Timeout != nil {
 		newCopy.S3Timeout = helper.StringToPtr(*a.S3Timeout)
 	}
+	if a.CacheMaxSize != nil {
+		newCopy.CacheMaxSize = helper.StringToPtr(*a.CacheMaxSize)
+	}
+	if a.DisableSandbox != nil {
+		newCopy.DisableSandbox = helper.BoolToPtr(*a.DisableSandbox)
+	}
+	if a.DisableFilesystemIsolation != nil {
+		newCopy.DisableFilesystemIsolation = helper.BoolToPtr(*a.DisableFilesystemIsolation)
+	}
+	if a.SandboxUser != nil {
+		newCopy.SandboxUser = helper.StringToPtr(*a.SandboxUser)
+	}
+	if a.SandboxMemoryMax != nil {
+		newCopy.SandboxMemoryMax = helper.StringToPtr(*a.SandboxMemoryMax)
+	}
+	if a.SandboxCPUMax != nil {
+		newCopy.SandboxCPUMax = helper.Float64ToPtr(*a.SandboxCPUMax)
+	}
+	if a.SandboxTimeout != nil {
+		newCopy.SandboxTimeout = helper.StringToPtr(*a.SandboxTimeout)
+	}
 
 	return newCopy
 }
@@ -91,6 +143,27 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 	if o.S3Timeout != nil {
 		newCopy.S3Timeout = helper.StringToPtr(*o.S3Timeout)
 	}
+	if o.CacheMaxSize != nil {
+		newCopy.CacheMaxSize = helper.StringToPtr(*o.CacheMaxSize)
+	}
+	if o.DisableSandbox != nil {
+		newCopy.DisableSandbox = helper.BoolToPtr(*o.DisableSandbox)
+	}
+	if o.DisableFilesystemIsolation != nil {
+		newCopy.DisableFilesystemIsolation = helper.BoolToPtr(*o.DisableFilesystemIsolation)
+	}
+	if o.SandboxUser != nil {
+		newCopy.SandboxUser = helper.StringToPtr(*o.SandboxUser)
+	}
+	if o.SandboxMemoryMax != nil {
+		newCopy.SandboxMemoryMax = helper.StringToPtr(*o.SandboxMemoryMax)
+	}
+	if o.SandboxCPUMax != nil {
+		newCopy.SandboxCPUMax = helper.Float64ToPtr(*o.SandboxCPUMax)
+	}
+	if o.SandboxTimeout != nil {
+		newCopy.SandboxTimeout = helper.StringToPtr(*o.SandboxTimeout)
+	}
 
 	return newCopy
 }
@@ -154,6 +227,34 @@ func (a *ArtifactConfig) Validate() error {
 		return fmt.Errorf("s3_timeout must be > 0")
 	}
 
+	if a.CacheMaxSize != nil {
+		if v, err := humanize.ParseBytes(*a.CacheMaxSize); err != nil {
+			return fmt.Errorf("cache_max_size not a valid size: %w", err)
+		} else if v > math.MaxInt64 {
+			return fmt.Errorf("cache_max_size must be < %d but found %d", int64(math.MaxInt64), v)
+		}
+	}
+
+	if a.SandboxMemoryMax != nil {
+		if v, err := humanize.ParseBytes(*a.SandboxMemoryMax); err != nil {
+			return fmt.Errorf("sandbox_memory_max not a valid size: %w", err)
+		} else if v > math.MaxInt64 {
+			return fmt.Errorf("sandbox_memory_max must be < %d but found %d", int64(math.MaxInt64), v)
+		}
+	}
+
+	if a.SandboxCPUMax != nil && *a.SandboxCPUMax < 0 {
+		return fmt.Errorf("sandbox_cpu_max must be >= 0")
+	}
+
+	if a.SandboxTimeout != nil {
+		if v, err := time.ParseDuration(*a.SandboxTimeout); err != nil {
+			return fmt.Errorf("sandbox_timeout not a valid duration: %w", err)
+		} else if v < 0 {
+			return fmt.Errorf("sandbox_timeout must be > 0")
+		}
+	}
+
 	return nil
 }
 
@@ -182,5 +283,18 @@ func DefaultArtifactConfig() *ArtifactConfig {
 		// Timeout for S3 operations. Must be long enough to
 		// accommodate large/slow downloads.
 		S3Timeout: helper.StringToPtr("30m"),
+
+		// Maximum size of the cached artifacts.
+		CacheMaxSize: helper.StringToPtr("10GB"),
+
+		// Download artifacts in an isolated subprocess.
+		DisableSandbox:             helper.BoolToPtr(false),
+		DisableFilesystemIsolation: helper.BoolToPtr(false),
+
+		// Resource limits of the sandbox subprocess. Must be large enough
+		// to accommodate large downloads and archives.
+		SandboxMemoryMax: helper.StringToPtr("2GB"),
+		SandboxCPUMax:    helper.Float64ToPtr(1),
+		SandboxTimeout:   helper.StringToPtr("1h"),
 	}
 }
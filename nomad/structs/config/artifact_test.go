@@ -40,6 +40,7 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:      helper.StringToPtr("30m"),
 				HgTimeout:       helper.StringToPtr("30m"),
 				S3Timeout:       helper.StringToPtr("30m"),
+				CacheMaxSize:    helper.StringToPtr("10GB"),
 			},
 			other: &ArtifactConfig{
 				HTTPReadTimeout: helper.StringToPtr("5m"),
@@ -48,6 +49,9 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:      helper.StringToPtr("2m"),
 				HgTimeout:       helper.StringToPtr("3m"),
 				S3Timeout:       helper.StringToPtr("4m"),
+				CacheMaxSize:    helper.StringToPtr("1GB"),
+				DisableSandbox:  helper.BoolToPtr(true),
+				SandboxCPUMax:   helper.Float64ToPtr(0.5),
 			},
 			expected: &ArtifactConfig{
 				HTTPReadTimeout: helper.StringToPtr("5m"),
@@ -56,6 +60,9 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:      helper.StringToPtr("2m"),
 				HgTimeout:       helper.StringToPtr("3m"),
 				S3Timeout:       helper.StringToPtr("4m"),
+				CacheMaxSize:    helper.StringToPtr("1GB"),
+				DisableSandbox:  helper.BoolToPtr(true),
+				SandboxCPUMax:   helper.Float64ToPtr(0.5),
 			},
 		},
 		{
@@ -331,6 +338,55 @@ func TestArtifactConfig_Validate(t *testing.T) {
 			},
 			expectedError: "s3_timeout not a valid duration",
 		},
+		{
+			name: "cache max size is missing",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = nil
+			},
+			expectedError: "",
+		},
+		{
+			name: "cache max size is invalid",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = helper.StringToPtr("invalid")
+			},
+			expectedError: "cache_max_size not a valid size",
+		},
+		{
+			name: "cache max size is zero",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = helper.StringToPtr("0")
+			},
+			expectedError: "",
+		},
+		{
+			name: "sandbox memory max is invalid",
+			config: func(a *ArtifactConfig) {
+				a.SandboxMemoryMax = helper.StringToPtr("invalid")
+			},
+			expectedError: "sandbox_memory_max not a valid size",
+		},
+		{
+			name: "sandbox cpu max is negative",
+			config: func(a *ArtifactConfig) {
+				a.SandboxCPUMax = helper.Float64ToPtr(-1)
+			},
+			expectedError: "sandbox_cpu_max must be >= 0",
+		},
+		{
+			name: "sandbox timeout is invalid",
+			config: func(a *ArtifactConfig) {
+				a.SandboxTimeout = helper.StringToPtr("invalid")
+			},
+			expectedError: "sandbox_timeout not a valid duration",
+		},
+		{
+			name: "sandbox timeout is zero",
+			config: func(a *ArtifactConfig) {
+				a.SandboxTimeout = helper.StringToPtr("0")
+			},
+			expectedError: "",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -289,6 +289,11 @@ type CSIVolume struct {
 	Context    map[string]string
 	Capacity   int64 // bytes
 
+	// NodeExpansionRequired is set when the controller plugin has
+	// expanded the volume but the nodes where it is staged have not
+	// yet been told to expand it.
+	NodeExpansionRequired bool
+
 	// These values are used only on volume creation but we record them
 	// so that we can diff the volume later
 	RequestedCapacityMin  int64 // bytes
@@ -514,6 +519,12 @@ func (v *CSIVolume) InUse() bool {
 		len(v.WriteAllocs) != 0
 }
 
+// RequiresExpansion tests whether the requested minimum capacity of the
+// volume has grown past its current capacity
+func (v *CSIVolume) RequiresExpansion() bool {
+	return v.Capacity != 0 && v.RequestedCapacityMin > v.Capacity
+}
+
 // Copy returns a copy of the volume, which shares only the Topologies slice
 func (v *CSIVolume) Copy() *CSIVolume {
 	out := new(CSIVolume)
@@ -787,12 +798,12 @@ func (v *CSIVolume) Merge(other *CSIVolume) error {
 			"volume snapshot ID cannot be updated"))
 	}
 
-	// must be compatible with capacity range
-	// TODO: when ExpandVolume is implemented we'll need to update
-	// this logic https://github.com/hashicorp/nomad/issues/10324
+	// must be compatible with capacity range. The requested capacity
+	// can grow past the existing capacity, in which case the caller is
+	// responsible for expanding the volume, but volumes can't shrink.
 	if v.Capacity != 0 {
-		if other.RequestedCapacityMax < v.Capacity ||
-			other.RequestedCapacityMin > v.Capacity {
+		if other.RequestedCapacityMax != 0 &&
+			other.RequestedCapacityMax < v.Capacity {
 			errs = multierror.Append(errs, errors.New(
 				"volume requested capacity update was not compatible with existing capacity"))
 		} else {
@@ -593,13 +593,24 @@ func TestCSIVolume_Merge(t *testing.T) {
 	}{
 		{
 			name: "invalid capacity update",
+			v:    &CSIVolume{Capacity: 300},
+			update: &CSIVolume{
+				RequestedCapacityMax: 200, RequestedCapacityMin: 100},
+			expected: "volume requested capacity update was not compatible with existing capacity",
+			expectFn: func(t *testing.T, v *CSIVolume) {
+				require.NotEqual(t, int64(200), v.RequestedCapacityMax)
+				require.NotEqual(t, int64(100), v.RequestedCapacityMin)
+			},
+		},
+		{
+			name: "valid capacity expansion",
 			v:    &CSIVolume{Capacity: 100},
 			update: &CSIVolume{
 				RequestedCapacityMax: 300, RequestedCapacityMin: 200},
-			expected: "volume requested capacity update was not compatible with existing capacity",
 			expectFn: func(t *testing.T, v *CSIVolume) {
-				require.NotEqual(t, 300, v.RequestedCapacityMax)
-				require.NotEqual(t, 200, v.RequestedCapacityMin)
+				require.Equal(t, int64(300), v.RequestedCapacityMax)
+				require.Equal(t, int64(200), v.RequestedCapacityMin)
+				require.Equal(t, int64(100), v.Capacity)
 			},
 		},
 		{
@@ -741,12 +752,12 @@ func TestCSIVolume_Merge(t *testing.T) {
 			if tc.expected == "" {
 				require.NoError(t, err)
 			} else {
-				if tc.expectFn != nil {
-					tc.expectFn(t, tc.v)
-				}
 				require.Error(t, err, tc.expected)
 				require.Contains(t, err.Error(), tc.expected)
 			}
+			if tc.expectFn != nil {
+				tc.expectFn(t, tc.v)
+			}
 		})
 	}
 }
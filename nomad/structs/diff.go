@@ -68,7 +68,7 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	// See agent.ApiJobToStructJob Update is a default for TaskGroups
 	diff := &JobDiff{Type: DiffTypeNone}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
-	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "CreateIndex",
+	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "EvalsPaused", "CreateIndex",
 		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID"}
 
 	if j == nil && other == nil {
@@ -131,7 +131,7 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	diff.TaskGroups = tgs
 
 	// Periodic diff
-	if pDiff := primitiveObjectDiff(j.Periodic, other.Periodic, nil, "Periodic", contextual); pDiff != nil {
+	if pDiff := periodicDiff(j.Periodic, other.Periodic, contextual); pDiff != nil {
 		diff.Objects = append(diff.Objects, pDiff)
 	}
 
@@ -505,7 +505,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -544,6 +544,11 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, tmplDiffs...)
 	}
 
+	// Actions diff
+	if aDiffs := actionDiffs(t.Actions, other.Actions, contextual); aDiffs != nil {
+		diff.Objects = append(diff.Objects, aDiffs...)
+	}
+
 	return diff, nil
 }
 
@@ -1442,7 +1447,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1590,6 +1595,108 @@ func serviceCheckDiffs(old, new []*ServiceCheck, contextual bool) []*ObjectDiff
 	return diffs
 }
 
+// logConfigDiff returns the diff of two log config objects, including the
+// diff of their sinks. If contextual diff is enabled, all fields will be
+// returned, even if no diff occurred.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "LogConfig"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if old.Equals(new) {
+		return nil
+	} else if old == nil {
+		old = &LogConfig{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &LogConfig{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Sinks diffs
+	sinkDiffs := primitiveObjectSetDiff(
+		interfaceSlice(old.Sinks),
+		interfaceSlice(new.Sinks),
+		nil, "Sink", contextual)
+	if sinkDiffs != nil {
+		diff.Objects = append(diff.Objects, sinkDiffs...)
+	}
+
+	return diff
+}
+
+// actionDiffs diffs a set of task actions, which are matched by name. If
+// contextual diff is enabled, unchanged fields within the actions will be
+// returned.
+func actionDiffs(old, new []*Action, contextual bool) []*ObjectDiff {
+	oldMap := make(map[string]*Action, len(old))
+	newMap := make(map[string]*Action, len(new))
+	for _, a := range old {
+		oldMap[a.Name] = a
+	}
+	for _, a := range new {
+		newMap[a.Name] = a
+	}
+
+	var diffs []*ObjectDiff
+	for name, oldAction := range oldMap {
+		// Diff the same, deleted and edited
+		if diff := actionDiff(oldAction, newMap[name], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	for name, newAction := range newMap {
+		// Diff the added
+		if _, ok := oldMap[name]; !ok {
+			diffs = append(diffs, actionDiff(nil, newAction, contextual))
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+// actionDiff returns the diff of two task actions, including their
+// arguments.
+func actionDiff(old, new *Action, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Action"}
+	var oldFlat, newFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		diff.Type = DiffTypeAdded
+		newFlat = actionFlatten(new)
+	} else if new == nil {
+		diff.Type = DiffTypeDeleted
+		oldFlat = actionFlatten(old)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldFlat = actionFlatten(old)
+		newFlat = actionFlatten(new)
+	}
+
+	diff.Fields = fieldDiffs(oldFlat, newFlat, contextual)
+	return diff
+}
+
+// actionFlatten flattens a task action, keeping the order of its arguments.
+func actionFlatten(a *Action) map[string]string {
+	flat := flatmap.Flatten(a, nil, true)
+	for i, arg := range a.Args {
+		flat[fmt.Sprintf("Args[%d]", i)] = arg
+	}
+	return flat
+}
+
 // vaultDiff returns the diff of two vault objects. If contextual diff is
 // enabled, all fields will be returned, even if no diff occurred.
 func vaultDiff(old, new *Vault, contextual bool) *ObjectDiff {
@@ -1765,6 +1872,48 @@ func findTemplateMatch(template *Template, newTemplates []*Template, newTemplate
 // parameterizedJobDiff returns the diff of two parameterized job objects. If
 // contextual diff is enabled, all fields will be returned, even if no diff
 // occurred.
+// periodicDiff returns the diff of two periodic configs. The primitive fields
+// are diffed as a single object and the list fields as sets.
+func periodicDiff(old, new *PeriodicConfig, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "Periodic", contextual)
+
+	oldConfig, newConfig := old, new
+	if oldConfig == nil {
+		oldConfig = &PeriodicConfig{}
+	}
+	if newConfig == nil {
+		newConfig = &PeriodicConfig{}
+	}
+
+	var setDiffs []*ObjectDiff
+	for _, sd := range []*ObjectDiff{
+		stringSetDiff(oldConfig.Specs, newConfig.Specs, "Specs", contextual),
+		stringSetDiff(oldConfig.ExcludeDates, newConfig.ExcludeDates, "ExcludeDates", contextual),
+		stringSetDiff(oldConfig.ExcludeCrons, newConfig.ExcludeCrons, "ExcludeCrons", contextual),
+	} {
+		if sd != nil && sd.Type != DiffTypeNone {
+			setDiffs = append(setDiffs, sd)
+		}
+	}
+	if len(setDiffs) == 0 {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Name: "Periodic"}
+		switch {
+		case old == nil:
+			diff.Type = DiffTypeAdded
+		case new == nil:
+			diff.Type = DiffTypeDeleted
+		default:
+			diff.Type = DiffTypeEdited
+		}
+	}
+	diff.Objects = append(diff.Objects, setDiffs...)
+	return diff
+}
+
 func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *ObjectDiff {
 	diff := &ObjectDiff{Type: DiffTypeNone, Name: "ParameterizedJob"}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
@@ -671,6 +671,48 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Periodic crons and exclusions edited
+			Old: &Job{
+				Periodic: &PeriodicConfig{
+					Enabled:      true,
+					Specs:        []string{"0 9 * * MON-FRI"},
+					SpecType:     "cron",
+					ExcludeDates: []string{"2022-12-25"},
+				},
+			},
+			New: &Job{
+				Periodic: &PeriodicConfig{
+					Enabled:      true,
+					Specs:        []string{"0 9 * * MON-FRI", "0 12 * * SAT,SUN"},
+					SpecType:     "cron",
+					ExcludeDates: []string{"2022-12-25"},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Periodic",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Specs",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Specs",
+										Old:  "",
+										New:  "0 12 * * SAT,SUN",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Constraints edited
 			Old: &Job{
@@ -902,6 +944,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "StickyHost",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 					{
@@ -930,6 +978,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "StickyHost",
+								Old:  "false",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -4539,6 +4593,75 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "LogConfig sink added",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{
+							Type:      LogSinkTypeSyslog,
+							Address:   "udp://127.0.0.1:514",
+							BatchSize: 100,
+						},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Address",
+										Old:  "",
+										New:  "udp://127.0.0.1:514",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BatchSize",
+										Old:  "",
+										New:  "100",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BatchWait",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BufferSize",
+										Old:  "",
+										New:  "0",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "syslog",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Artifacts edited",
 			Old: &Task{
@@ -4682,6 +4805,88 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Actions edited",
+			Old: &Task{
+				Actions: []*Action{
+					{
+						Name:    "flush-cache",
+						Command: "redis-cli",
+						Args:    []string{"FLUSHALL"},
+					},
+					{
+						Name:    "rotate-logs",
+						Command: "logrotate",
+					},
+				},
+			},
+			New: &Task{
+				Actions: []*Action{
+					{
+						Name:    "flush-cache",
+						Command: "redis-cli",
+						Args:    []string{"FLUSHALL", "ASYNC"},
+					},
+					{
+						Name:    "reload",
+						Command: "kill",
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Action",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Args[1]",
+								Old:  "",
+								New:  "ASYNC",
+							},
+						},
+					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Action",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Command",
+								Old:  "",
+								New:  "kill",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Name",
+								Old:  "",
+								New:  "reload",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Action",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Command",
+								Old:  "logrotate",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Name",
+								Old:  "rotate-logs",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Resources edited (no networks)",
 			Old: &Task{
@@ -1,5 +1,16 @@
 package structs
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
 // EventStreamRequest is used to stream events from a servers EventBroker
 type EventStreamRequest struct {
 	Topics map[Topic][]string
@@ -16,15 +27,23 @@ type EventStreamWrapper struct {
 type Topic string
 
 const (
-	TopicDeployment Topic = "Deployment"
-	TopicEvaluation Topic = "Evaluation"
-	TopicAllocation Topic = "Allocation"
-	TopicJob        Topic = "Job"
-	TopicNode       Topic = "Node"
-	TopicACLPolicy  Topic = "ACLPolicy"
-	TopicACLToken   Topic = "ACLToken"
-	TopicService    Topic = "Service"
-	TopicAll        Topic = "*"
+	TopicDeployment     Topic = "Deployment"
+	TopicEvaluation     Topic = "Evaluation"
+	TopicAllocation     Topic = "Allocation"
+	TopicJob            Topic = "Job"
+	TopicNode           Topic = "Node"
+	TopicACLPolicy      Topic = "ACLPolicy"
+	TopicACLToken       Topic = "ACLToken"
+	TopicService        Topic = "Service"
+	TopicMaintenance    Topic = "Maintenance"
+	TopicCSIVolume      Topic = "CSIVolume"
+	TopicCSIPlugin      Topic = "CSIPlugin"
+	TopicHostVolume     Topic = "HostVolume"
+	TopicNamespace      Topic = "Namespace"
+	TopicScalingPolicy  Topic = "ScalingPolicy"
+	TopicPeriodicLaunch Topic = "PeriodicLaunch"
+	TopicOperator       Topic = "Operator"
+	TopicAll            Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
 	TypeNodeDeregistration            = "NodeDeregistration"
@@ -48,6 +67,23 @@ const (
 	TypeACLPolicyUpserted             = "ACLPolicyUpserted"
 	TypeServiceRegistration           = "ServiceRegistration"
 	TypeServiceDeregistration         = "ServiceDeregistration"
+	TypeMaintenancePlanUpserted       = "MaintenancePlanUpserted"
+	TypeMaintenancePlanDeleted        = "MaintenancePlanDeleted"
+	TypeCSIVolumeRegistered           = "CSIVolumeRegistered"
+	TypeCSIVolumeDeregistered         = "CSIVolumeDeregistered"
+	TypeCSIVolumeClaim                = "CSIVolumeClaim"
+	TypeCSIPluginUpdated              = "CSIPluginUpdated"
+	TypeCSIPluginDeleted              = "CSIPluginDeleted"
+	TypeHostVolumeRegistered          = "HostVolumeRegistered"
+	TypeHostVolumeDeleted             = "HostVolumeDeleted"
+	TypeNamespaceUpserted             = "NamespaceUpserted"
+	TypeNamespaceDeleted              = "NamespaceDeleted"
+	TypeScalingPolicyUpserted         = "ScalingPolicyUpserted"
+	TypeScalingPolicyDeleted          = "ScalingPolicyDeleted"
+	TypePeriodicLaunchUpdated         = "PeriodicLaunchUpdated"
+	TypePeriodicLaunchDeleted         = "PeriodicLaunchDeleted"
+	TypeSchedulerConfigUpdated        = "SchedulerConfigUpdated"
+	TypeAutopilotConfigUpdated        = "AutopilotConfigUpdated"
 )
 
 // Event represents a change in Nomads state.
@@ -121,6 +157,54 @@ type NodeStreamEvent struct {
 	Node *Node
 }
 
+// MaintenancePlanEvent holds a newly updated or deleted maintenance plan.
+type MaintenancePlanEvent struct {
+	Plan *MaintenancePlan
+}
+
+// CSIVolumeEvent holds a newly updated or deleted CSI volume. The secrets of
+// the volume are removed.
+type CSIVolumeEvent struct {
+	Volume *CSIVolume
+}
+
+// HostVolumeEvent holds a newly updated or deleted dynamic host volume.
+type HostVolumeEvent struct {
+	HostVolume *HostVolume
+}
+
+// CSIPluginEvent holds a newly updated or deleted CSI plugin.
+type CSIPluginEvent struct {
+	Plugin *CSIPlugin
+}
+
+// NamespaceEvent holds a newly updated or deleted namespace.
+type NamespaceEvent struct {
+	Namespace *Namespace
+}
+
+// ScalingPolicyEvent holds a newly updated or deleted scaling policy.
+type ScalingPolicyEvent struct {
+	ScalingPolicy *ScalingPolicy
+}
+
+// PeriodicLaunchEvent holds the newly updated or deleted last launch of a
+// periodic job.
+type PeriodicLaunchEvent struct {
+	PeriodicLaunch *PeriodicLaunch
+}
+
+// SchedulerConfigurationEvent holds the newly updated scheduler
+// configuration.
+type SchedulerConfigurationEvent struct {
+	SchedulerConfig *SchedulerConfiguration
+}
+
+// AutopilotConfigEvent holds the newly updated autopilot configuration.
+type AutopilotConfigEvent struct {
+	AutopilotConfig *AutopilotConfig
+}
+
 type ACLTokenEvent struct {
 	ACLToken *ACLToken
 	secretID string
@@ -151,3 +235,201 @@ func (a *ACLTokenEvent) SecretID() string {
 type ACLPolicyEvent struct {
 	ACLPolicy *ACLPolicy
 }
+
+// SinkType is the type of destination an EventSink delivers events to.
+type SinkType string
+
+const (
+	// SinkWebhook delivers events with HTTP POST requests to an address.
+	SinkWebhook SinkType = "webhook"
+
+	// SinkFile appends events as newline delimited JSON to a file local to
+	// the leader.
+	SinkFile SinkType = "file"
+)
+
+var (
+	// validEventSinkID is used to validate an event sink ID
+	validEventSinkID = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+)
+
+// EventSink is a server managed subscription to the event stream. The leader
+// delivers the events matching the topics and namespace of the sink to its
+// destination, and tracks the index of the last delivered events so that
+// delivery resumes where it stopped after an outage or a leader election.
+// Events are delivered at least once.
+type EventSink struct {
+	// ID is the unique identifier of the sink, chosen by the operator.
+	ID string
+
+	// Type is the type of destination of the sink.
+	Type SinkType
+
+	// Topics are the topics and keys the sink is subscribed to, with the
+	// same semantics as the event stream topic filter.
+	Topics map[Topic][]string
+
+	// Namespace is the namespace of the events the sink is subscribed to.
+	// The wildcard "*" subscribes to all namespaces.
+	Namespace string
+
+	// Address is the URL that webhook sinks send events to.
+	Address string
+
+	// Headers are additional HTTP headers set on the requests of webhook
+	// sinks.
+	Headers map[string]string
+
+	// Path is the absolute path of the file that file sinks append events
+	// to.
+	Path string
+
+	// LatestIndex is the Raft index of the last events delivered to the
+	// sink.
+	LatestIndex uint64
+
+	// LatestIndexBatches is the number of sets of events with the latest
+	// index delivered to the sink. A single Raft operation may publish
+	// several sets of events with the same index.
+	LatestIndexBatches int
+
+	// LostEventsIndex is the index of the last events delivered to the sink
+	// before events that had not been delivered left the buffer of the event
+	// broker, and were lost. It is zero if the sink never lost events.
+	LostEventsIndex uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Canonicalize sets the default topics and namespace of the sink.
+func (e *EventSink) Canonicalize() {
+	if len(e.Topics) == 0 {
+		e.Topics = map[Topic][]string{
+			TopicAll: {string(TopicAll)},
+		}
+	}
+	if e.Namespace == "" {
+		e.Namespace = DefaultNamespace
+	}
+}
+
+// Validate returns an error if the sink is invalid.
+func (e *EventSink) Validate() error {
+	var mErr multierror.Error
+
+	if !validEventSinkID.MatchString(e.ID) {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("invalid ID %q, must match regex %s", e.ID, validEventSinkID))
+	}
+
+	for topic, keys := range e.Topics {
+		if topic == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("empty topic"))
+		}
+		if len(keys) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("topic %q has no keys", topic))
+		}
+	}
+
+	switch e.Type {
+	case SinkWebhook:
+		u, err := url.Parse(e.Address)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address: %v", err))
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			mErr.Errors = append(mErr.Errors,
+				fmt.Errorf("invalid address %q, must be an http or https URL", e.Address))
+		}
+		if e.Path != "" {
+			mErr.Errors = append(mErr.Errors, errors.New("path is only valid for file sinks"))
+		}
+	case SinkFile:
+		if !filepath.IsAbs(e.Path) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid path %q, must be absolute", e.Path))
+		}
+		if e.Address != "" || len(e.Headers) != 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("address and headers are only valid for webhook sinks"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("invalid type %q, must be one of %q or %q", e.Type, SinkWebhook, SinkFile))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the sink.
+func (e *EventSink) Copy() *EventSink {
+	if e == nil {
+		return nil
+	}
+	c := new(EventSink)
+	*c = *e
+
+	if e.Topics != nil {
+		c.Topics = make(map[Topic][]string, len(e.Topics))
+		for topic, keys := range e.Topics {
+			c.Topics[topic] = helper.CopySliceString(keys)
+		}
+	}
+	c.Headers = helper.CopyMapStringString(e.Headers)
+	return c
+}
+
+// EventSinkUpsertRequest is used to register or update an event sink.
+type EventSinkUpsertRequest struct {
+	Sink *EventSink
+	WriteRequest
+}
+
+// EventSinkSpecificRequest is used to query a specific event sink.
+type EventSinkSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// EventSinkResponse is used to return a single event sink.
+type EventSinkResponse struct {
+	Sink *EventSink
+	QueryMeta
+}
+
+// EventSinkListRequest is used to list the event sinks.
+type EventSinkListRequest struct {
+	QueryOptions
+}
+
+// EventSinkListResponse is used to return a list of event sinks.
+type EventSinkListResponse struct {
+	Sinks []*EventSink
+	QueryMeta
+}
+
+// EventSinkDeleteRequest is used to delete event sinks.
+type EventSinkDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
+
+// EventSinkProgress is the delivery progress of an event sink.
+type EventSinkProgress struct {
+	// LatestIndex is the index of the last events delivered to the sink.
+	LatestIndex uint64
+
+	// LatestIndexBatches is the number of sets of events with the latest
+	// index delivered to the sink.
+	LatestIndexBatches int
+
+	// LostEventsIndex is the index of the last events delivered to the sink
+	// before it lost events, or zero.
+	LostEventsIndex uint64
+}
+
+// EventSinkProgressRequest is used by the leader to store the delivery
+// progress of each sink.
+type EventSinkProgressRequest struct {
+	// Progress maps the ID of the sinks to their delivery progress.
+	Progress map[string]*EventSinkProgress
+	WriteRequest
+}
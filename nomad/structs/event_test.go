@@ -0,0 +1,113 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSink_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		sink   *EventSink
+		expErr []string
+	}{
+		{
+			name: "valid webhook",
+			sink: &EventSink{
+				ID:      "webhook",
+				Type:    SinkWebhook,
+				Address: "https://example.com/events",
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			},
+		},
+		{
+			name: "valid file",
+			sink: &EventSink{
+				ID:   "file",
+				Type: SinkFile,
+				Path: "/var/log/nomad/events.json",
+			},
+		},
+		{
+			name: "invalid webhook",
+			sink: &EventSink{
+				ID:      "bad id",
+				Type:    SinkWebhook,
+				Address: "example.com/events",
+				Path:    "/tmp/events.json",
+			},
+			expErr: []string{
+				`invalid ID "bad id"`,
+				"must be an http or https URL",
+				"path is only valid for file sinks",
+			},
+		},
+		{
+			name: "invalid file",
+			sink: &EventSink{
+				ID:      "file",
+				Type:    SinkFile,
+				Path:    "events.json",
+				Address: "https://example.com/events",
+			},
+			expErr: []string{
+				`invalid path "events.json", must be absolute`,
+				"address and headers are only valid for webhook sinks",
+			},
+		},
+		{
+			name: "invalid type and topics",
+			sink: &EventSink{
+				ID:     "sink",
+				Type:   "kafka",
+				Topics: map[Topic][]string{TopicJob: {}},
+			},
+			expErr: []string{
+				`invalid type "kafka"`,
+				`topic "Job" has no keys`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.sink.Canonicalize()
+			err := tc.sink.Validate()
+			if len(tc.expErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, exp := range tc.expErr {
+				require.Contains(t, err.Error(), exp)
+			}
+		})
+	}
+}
+
+func TestEventSink_Canonicalize_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	sink := &EventSink{
+		ID:      "webhook",
+		Type:    SinkWebhook,
+		Address: "https://example.com/events",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	sink.Canonicalize()
+	require.Equal(t, map[Topic][]string{TopicAll: {"*"}}, sink.Topics)
+	require.Equal(t, DefaultNamespace, sink.Namespace)
+
+	c := sink.Copy()
+	require.Equal(t, sink, c)
+
+	c.Topics[TopicAll][0] = "other"
+	c.Headers["Authorization"] = "other"
+	require.Equal(t, "*", sink.Topics[TopicAll][0])
+	require.Equal(t, "Bearer token", sink.Headers["Authorization"])
+}
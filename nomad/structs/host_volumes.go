@@ -0,0 +1,268 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// HostVolumeCreateRPCMethod is the RPC method for creating a dynamic host
+	// volume on a node. The node is selected by the servers unless it is set
+	// in the request.
+	//
+	// Args: HostVolumeCreateRequest
+	// Reply: HostVolumeCreateResponse
+	HostVolumeCreateRPCMethod = "HostVolume.Create"
+
+	// HostVolumeDeleteRPCMethod is the RPC method for deleting dynamic host
+	// volumes that are no longer used by any allocation.
+	//
+	// Args: HostVolumeDeleteRequest
+	// Reply: HostVolumeDeleteResponse
+	HostVolumeDeleteRPCMethod = "HostVolume.Delete"
+
+	// HostVolumeGetRPCMethod is the RPC method for detailing a single dynamic
+	// host volume according to its ID.
+	//
+	// Args: HostVolumeGetRequest
+	// Reply: HostVolumeGetResponse
+	HostVolumeGetRPCMethod = "HostVolume.Get"
+
+	// HostVolumeListRPCMethod is the RPC method for listing dynamic host
+	// volumes.
+	//
+	// Args: HostVolumeListRequest
+	// Reply: HostVolumeListResponse
+	HostVolumeListRPCMethod = "HostVolume.List"
+)
+
+const (
+	// HostVolumeState* are the states of a dynamic host volume. A volume is
+	// pending once it has been created on its node, until the node has
+	// fingerprinted it and it can be used by allocations.
+	HostVolumeStatePending = "pending"
+	HostVolumeStateReady   = "ready"
+
+	// HostVolumePluginMkdir is the built-in host volume plugin, which creates
+	// a directory on the node.
+	HostVolumePluginMkdir = "mkdir"
+
+	// HostVolumePluginAttrPrefix is the prefix of the node attributes
+	// fingerprinted for the host volume plugins of a node, e.g.
+	// "plugins.host_volume.mkdir.version"
+	HostVolumePluginAttrPrefix = "plugins.host_volume."
+)
+
+// validHostVolumeName is the set of characters allowed in the name of a
+// dynamic host volume, which is also used by the plugins for directory names.
+var validHostVolumeName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+
+// HostVolume is a host volume created on a node through the API, rather than
+// configured statically in the client configuration. Once the node has
+// fingerprinted it, the volume is part of the node's HostVolumes and can be
+// requested by task groups like any other host volume.
+type HostVolume struct {
+	// ID is the unique identifier of the volume, generated by the servers.
+	ID string
+
+	// Name is the name the volume is fingerprinted with on its node and
+	// which task groups use as the source of their host volumes. It must be
+	// unique on the node.
+	Name string
+
+	Namespace string
+
+	// PluginID is the host volume plugin used to create and delete the
+	// volume on the node.
+	PluginID string
+
+	// NodePool and Constraints are used to select the node of the volume
+	// when no NodeID is given. A given node must be in NodePool if it is
+	// set, and the volume then defaults to the pool of the node.
+	NodePool    string
+	Constraints []*Constraint
+
+	// NodeID is the node the volume is created on.
+	NodeID string
+
+	// RequestedCapacityMinBytes and RequestedCapacityMaxBytes are passed to
+	// the plugin, which reports the capacity actually provisioned.
+	RequestedCapacityMinBytes int64
+	RequestedCapacityMaxBytes int64
+	CapacityBytes             int64
+
+	// Parameters are passed to the plugin as is, and are typically used to
+	// set the ownership and mode of the volume.
+	Parameters map[string]string
+
+	// HostPath is the path of the volume on the node, as reported by the
+	// plugin.
+	HostPath string
+
+	State string
+
+	// Allocations are the non-terminal allocations on the node of the volume
+	// that claim it. They are denormalized when the volume is read and are
+	// not stored.
+	Allocations []*AllocListStub
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy returns a deep copy of the host volume.
+func (v *HostVolume) Copy() *HostVolume {
+	if v == nil {
+		return nil
+	}
+
+	nv := new(HostVolume)
+	*nv = *v
+	nv.Constraints = CopySliceConstraints(v.Constraints)
+	nv.Parameters = helper.CopyMap(v.Parameters)
+	if v.Allocations != nil {
+		nv.Allocations = make([]*AllocListStub, len(v.Allocations))
+		copy(nv.Allocations, v.Allocations)
+	}
+	return nv
+}
+
+// Canonicalize sets the defaults of the user-defined fields of the volume.
+func (v *HostVolume) Canonicalize() {
+	if v.Namespace == "" {
+		v.Namespace = DefaultNamespace
+	}
+	if v.NodePool == "" && v.NodeID == "" {
+		v.NodePool = NodePoolDefault
+	}
+	if v.PluginID == "" {
+		v.PluginID = HostVolumePluginMkdir
+	}
+}
+
+// Validate validates the user-defined fields of the volume.
+func (v *HostVolume) Validate() error {
+	var mErr *multierror.Error
+
+	if !validHostVolumeName.MatchString(v.Name) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid name %q", v.Name))
+	}
+	if v.PluginID == "" {
+		mErr = multierror.Append(mErr, errors.New("missing plugin ID"))
+	}
+	if v.RequestedCapacityMinBytes < 0 || v.RequestedCapacityMaxBytes < 0 {
+		mErr = multierror.Append(mErr, errors.New("capacity can not be negative"))
+	}
+	if v.RequestedCapacityMaxBytes != 0 && v.RequestedCapacityMaxBytes < v.RequestedCapacityMinBytes {
+		mErr = multierror.Append(mErr, errors.New("capacity_max must be greater than or equal to capacity_min"))
+	}
+	if v.NodeID != "" && len(v.Constraints) != 0 {
+		mErr = multierror.Append(mErr, errors.New("constraints can not be set along with a node ID"))
+	}
+	for idx, c := range v.Constraints {
+		if err := c.Validate(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("constraint %d validation failed: %v", idx+1, err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// InUse returns whether any allocation claims the volume. The allocations
+// of the volume must have been denormalized.
+func (v *HostVolume) InUse() bool {
+	return len(v.Allocations) != 0
+}
+
+// Stub returns a summarized version of the volume that is used when listing.
+func (v *HostVolume) Stub() *HostVolumeStub {
+	return &HostVolumeStub{
+		ID:            v.ID,
+		Name:          v.Name,
+		Namespace:     v.Namespace,
+		PluginID:      v.PluginID,
+		NodePool:      v.NodePool,
+		NodeID:        v.NodeID,
+		CapacityBytes: v.CapacityBytes,
+		State:         v.State,
+		CreateIndex:   v.CreateIndex,
+		ModifyIndex:   v.ModifyIndex,
+	}
+}
+
+// HostVolumeStub is the summarized version of a host volume.
+type HostVolumeStub struct {
+	ID            string
+	Name          string
+	Namespace     string
+	PluginID      string
+	NodePool      string
+	NodeID        string
+	CapacityBytes int64
+	State         string
+	CreateIndex   uint64
+	ModifyIndex   uint64
+}
+
+// HostVolumeCreateRequest is used to create a dynamic host volume.
+type HostVolumeCreateRequest struct {
+	Volume *HostVolume
+	WriteRequest
+}
+
+// HostVolumeCreateResponse is the response object for a host volume create
+// request.
+type HostVolumeCreateResponse struct {
+	Volume *HostVolume
+	WriteMeta
+}
+
+// HostVolumeDeleteRequest is used to delete dynamic host volumes.
+type HostVolumeDeleteRequest struct {
+	VolumeIDs []string
+	WriteRequest
+}
+
+// HostVolumeDeleteResponse is the response object for a host volume delete
+// request.
+type HostVolumeDeleteResponse struct {
+	WriteMeta
+}
+
+// HostVolumeUpsertRequest is the Raft request used to store dynamic host
+// volumes once they have been created on their node.
+type HostVolumeUpsertRequest struct {
+	Volumes []*HostVolume
+	WriteRequest
+}
+
+// HostVolumeGetRequest is used to query a specific host volume.
+type HostVolumeGetRequest struct {
+	ID string
+	QueryOptions
+}
+
+// HostVolumeGetResponse is the response object for a host volume get
+// request.
+type HostVolumeGetResponse struct {
+	Volume *HostVolume
+	QueryMeta
+}
+
+// HostVolumeListRequest is used to list host volumes, optionally only those
+// of a node.
+type HostVolumeListRequest struct {
+	NodeID string
+	QueryOptions
+}
+
+// HostVolumeListResponse is the response object for a host volume list
+// request.
+type HostVolumeListResponse struct {
+	Volumes []*HostVolumeStub
+	QueryMeta
+}
@@ -0,0 +1,53 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostVolume_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	vol := &HostVolume{Name: "data"}
+	vol.Canonicalize()
+	require.Equal(t, DefaultNamespace, vol.Namespace)
+	require.Equal(t, NodePoolDefault, vol.NodePool)
+	require.Equal(t, HostVolumePluginMkdir, vol.PluginID)
+	require.NoError(t, vol.Validate())
+
+	invalid := &HostVolume{
+		Name:                      "../data",
+		RequestedCapacityMinBytes: 200,
+		RequestedCapacityMaxBytes: 100,
+		NodeID:                    "node",
+		Constraints: []*Constraint{{
+			LTarget: "${attr.kernel.name}",
+			Operand: "=",
+		}},
+	}
+	err := invalid.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid name "../data"`)
+	require.Contains(t, err.Error(), "missing plugin ID")
+	require.Contains(t, err.Error(), "capacity_max must be greater than or equal to capacity_min")
+	require.Contains(t, err.Error(), "constraints can not be set along with a node ID")
+	require.Contains(t, err.Error(), "constraint 1 validation failed")
+}
+
+func TestHostVolume_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	vol := &HostVolume{
+		Name:        "data",
+		Parameters:  map[string]string{"mode": "0700"},
+		Constraints: []*Constraint{{LTarget: "${attr.kernel.name}", RTarget: "linux", Operand: "="}},
+	}
+	out := vol.Copy()
+	out.Parameters["mode"] = "0755"
+	out.Constraints[0].RTarget = "darwin"
+
+	require.Equal(t, "0700", vol.Parameters["mode"])
+	require.Equal(t, "linux", vol.Constraints[0].RTarget)
+}
@@ -0,0 +1,192 @@
+package structs
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+)
+
+const (
+	// KeyringGetRootKeyRPCMethod is the RPC method used by servers to fetch
+	// the root key material they are missing from their peers.
+	//
+	// Args: KeyringGetRootKeyRequest
+	// Reply: KeyringGetRootKeyResponse
+	KeyringGetRootKeyRPCMethod = "Keyring.Get"
+
+	// KeyringListPublicRPCMethod is the RPC method used to list the public
+	// keys which verify workload identities. It requires no authentication.
+	//
+	// Args: GenericRequest
+	// Reply: KeyringListPublicResponse
+	KeyringListPublicRPCMethod = "Keyring.ListPublic"
+)
+
+// RootKey is used to encrypt and decrypt variables. It is never stored in
+// Raft; only its metadata is. The key material is held by each server in its
+// local keystore.
+type RootKey struct {
+	Meta *RootKeyMeta
+	Key  []byte
+}
+
+// NewRootKey returns a new root key and its metadata, using the passed
+// algorithm.
+func NewRootKey(algorithm EncryptionAlgorithm) (*RootKey, error) {
+	meta := NewRootKeyMeta()
+	meta.Algorithm = algorithm
+
+	rootKey := &RootKey{
+		Meta: meta,
+	}
+
+	switch algorithm {
+	case EncryptionAlgorithmAES256GCM:
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %v", err)
+		}
+		rootKey.Key = key
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm %q", algorithm)
+	}
+
+	return rootKey, nil
+}
+
+// Copy returns a deep copy of the root key.
+func (k *RootKey) Copy() *RootKey {
+	if k == nil {
+		return nil
+	}
+	key := make([]byte, len(k.Key))
+	copy(key, k.Key)
+	return &RootKey{
+		Meta: k.Meta.Copy(),
+		Key:  key,
+	}
+}
+
+// RootKeyMeta is the metadata used to refer to a RootKey. It is stored in
+// Raft.
+type RootKeyMeta struct {
+	KeyID       string
+	Algorithm   EncryptionAlgorithm
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+	State       RootKeyState
+}
+
+// RootKeyState enumerates the states of a root key.
+type RootKeyState string
+
+const (
+	// RootKeyStateActive is the state of the single key used to encrypt new
+	// variables.
+	RootKeyStateActive RootKeyState = "active"
+
+	// RootKeyStateInactive is the state of keys which are only used to
+	// decrypt existing variables.
+	RootKeyStateInactive RootKeyState = "inactive"
+)
+
+// NewRootKeyMeta returns new RootKeyMeta with default values.
+func NewRootKeyMeta() *RootKeyMeta {
+	return &RootKeyMeta{
+		KeyID:      uuid.Generate(),
+		Algorithm:  EncryptionAlgorithmAES256GCM,
+		State:      RootKeyStateActive,
+		CreateTime: time.Now().UTC().UnixNano(),
+	}
+}
+
+// Active returns whether the key is used to encrypt new variables.
+func (rkm *RootKeyMeta) Active() bool {
+	return rkm.State == RootKeyStateActive
+}
+
+// Copy returns a copy of the metadata.
+func (rkm *RootKeyMeta) Copy() *RootKeyMeta {
+	if rkm == nil {
+		return nil
+	}
+	out := *rkm
+	return &out
+}
+
+// Validate checks the metadata is usable.
+func (rkm *RootKeyMeta) Validate() error {
+	if rkm == nil {
+		return fmt.Errorf("root key metadata is required")
+	}
+	if rkm.KeyID == "" {
+		return fmt.Errorf("root key ID is required")
+	}
+	if rkm.Algorithm != EncryptionAlgorithmAES256GCM {
+		return fmt.Errorf("unsupported encryption algorithm %q", rkm.Algorithm)
+	}
+	switch rkm.State {
+	case RootKeyStateActive, RootKeyStateInactive:
+	default:
+		return fmt.Errorf("root key state %q is invalid", rkm.State)
+	}
+	return nil
+}
+
+// EncryptionAlgorithm chooses which algorithm is used for encrypting and
+// decrypting entries with this key.
+type EncryptionAlgorithm string
+
+const (
+	EncryptionAlgorithmAES256GCM EncryptionAlgorithm = "aes256-gcm"
+)
+
+// KeyringUpdateRootKeyMetaRequest is used to write root key metadata to
+// Raft. When the key is active, all other keys are marked inactive.
+type KeyringUpdateRootKeyMetaRequest struct {
+	RootKeyMeta *RootKeyMeta
+	WriteRequest
+}
+
+// KeyringGetRootKeyRequest is used by servers to fetch root key material
+// from their peers.
+type KeyringGetRootKeyRequest struct {
+	KeyID string
+	QueryOptions
+}
+
+// KeyringGetRootKeyResponse is the response to a KeyringGetRootKeyRequest.
+type KeyringGetRootKeyResponse struct {
+	Key *RootKey
+	QueryMeta
+}
+
+const (
+	// PubKeyAlgEdDSA is the JWT algorithm of the public keys which verify
+	// workload identities.
+	PubKeyAlgEdDSA = "EdDSA"
+
+	// PubKeyUseSig is the intended use of the public keys which verify
+	// workload identities.
+	PubKeyUseSig = "sig"
+)
+
+// KeyringPublicKey is the public key derived from a root key, which is used
+// by third parties to verify the workload identities signed by the key.
+type KeyringPublicKey struct {
+	KeyID      string
+	PublicKey  []byte
+	Algorithm  string
+	Use        string
+	CreateTime int64
+}
+
+// KeyringListPublicResponse is the response to a KeyringListPublicRPCMethod
+// request.
+type KeyringListPublicResponse struct {
+	PublicKeys []*KeyringPublicKey
+	QueryMeta
+}
@@ -0,0 +1,476 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// MaintenancePlanListRPCMethod is the RPC method for listing maintenance
+	// plans.
+	//
+	// Args: MaintenancePlanListRequest
+	// Reply: MaintenancePlanListResponse
+	MaintenancePlanListRPCMethod = "Maintenance.List"
+
+	// MaintenancePlanGetPlanRPCMethod is the RPC method for detailing a
+	// single maintenance plan according to its ID.
+	//
+	// Args: MaintenancePlanSpecificRequest
+	// Reply: SingleMaintenancePlanResponse
+	MaintenancePlanGetPlanRPCMethod = "Maintenance.GetPlan"
+
+	// MaintenancePlanRegisterRPCMethod is the RPC method for creating a
+	// maintenance plan. The nodes of the plan are resolved from its selector
+	// when it is registered.
+	//
+	// Args: MaintenancePlanRegisterRequest
+	// Reply: MaintenancePlanRegisterResponse
+	MaintenancePlanRegisterRPCMethod = "Maintenance.Register"
+
+	// MaintenancePlanCancelRPCMethod is the RPC method for cancelling a
+	// running maintenance plan.
+	//
+	// Args: MaintenancePlanCancelRequest
+	// Reply: GenericResponse
+	MaintenancePlanCancelRPCMethod = "Maintenance.Cancel"
+
+	// MaintenancePlanNodeDoneRPCMethod is the RPC method for signalling that
+	// the maintenance of some nodes of a plan is done.
+	//
+	// Args: MaintenancePlanNodeDoneRequest
+	// Reply: GenericResponse
+	MaintenancePlanNodeDoneRPCMethod = "Maintenance.NodeDone"
+
+	// MaintenancePlanDeleteRPCMethod is the RPC method for deleting
+	// maintenance plans that are no longer running.
+	//
+	// Args: MaintenancePlanDeleteRequest
+	// Reply: GenericResponse
+	MaintenancePlanDeleteRPCMethod = "Maintenance.Delete"
+)
+
+const (
+	// MaintenancePlanStatus* are the statuses of a maintenance plan.
+	MaintenancePlanStatusRunning   = "running"
+	MaintenancePlanStatusComplete  = "complete"
+	MaintenancePlanStatusFailed    = "failed"
+	MaintenancePlanStatusCancelled = "cancelled"
+
+	// MaintenanceNodeStatus* are the statuses of a node within a
+	// maintenance plan. Nodes move from pending to draining once their batch
+	// starts, to waiting once the drain completed and to complete once the
+	// maintenance is done and the node is eligible again.
+	MaintenanceNodeStatusPending  = "pending"
+	MaintenanceNodeStatusDraining = "draining"
+	MaintenanceNodeStatusWaiting  = "waiting"
+	MaintenanceNodeStatusComplete = "complete"
+	MaintenanceNodeStatusFailed   = "failed"
+
+	// MaintenanceCompleteOnSignal waits for an operator to signal that the
+	// maintenance of a drained node is done.
+	MaintenanceCompleteOnSignal = "signal"
+
+	// MaintenanceCompleteOnReregister considers the maintenance of a drained
+	// node done once it registers again, for example after a reboot. An
+	// operator may still signal it done earlier.
+	MaintenanceCompleteOnReregister = "reregister"
+
+	// DefaultMaintenanceDrainDeadline is the drain deadline applied to the
+	// nodes of a maintenance plan without a drain spec.
+	DefaultMaintenanceDrainDeadline = time.Hour
+
+	// maxMaintenancePlanNameLength limits a maintenance plan name length.
+	maxMaintenancePlanNameLength = 128
+)
+
+var (
+	// ErrMaintenancePlanModified is returned when a maintenance plan update
+	// is based on an outdated version of the plan.
+	ErrMaintenancePlanModified = errors.New("maintenance plan was modified concurrently")
+)
+
+// MaintenancePlan is a rolling maintenance of a set of nodes. The leader
+// drains the nodes in batches, waits for each node to be done with its
+// maintenance and then makes it eligible for scheduling again before moving
+// to the next batch.
+type MaintenancePlan struct {
+	// ID is a unique identifier for the plan.
+	ID string
+
+	// Name is an optional human-friendly name for the plan.
+	Name string
+
+	// Selector selects the nodes of the plan when it is registered.
+	Selector *MaintenanceSelector
+
+	// Concurrency is the number of nodes in each batch.
+	Concurrency int
+
+	// DrainSpec is the drain applied to each node of the plan.
+	DrainSpec *DrainSpec
+
+	// CompleteOn determines when the maintenance of a drained node is
+	// considered done.
+	CompleteOn string
+
+	// Gate is checked between batches.
+	Gate *MaintenanceGate
+
+	// Nodes are the nodes of the plan and their progress.
+	Nodes []*MaintenanceNode
+
+	// Status is the status of the plan.
+	Status string
+
+	// StatusDescription gives more detail about the status of the plan.
+	StatusDescription string
+
+	CreateTime  int64
+	ModifyTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// MaintenanceSelector selects the nodes of a maintenance plan. All the set
+// fields must match for a node to be selected.
+type MaintenanceSelector struct {
+	// NodeClass selects nodes with the given node class.
+	NodeClass string
+
+	// Datacenter selects nodes in the given datacenter.
+	Datacenter string
+
+	// Meta selects nodes which have all the given metadata values.
+	Meta map[string]string
+
+	// Filter is a bexpr filter expression evaluated against each node.
+	Filter string
+}
+
+// MaintenanceGate is checked before starting the next batch of a plan.
+type MaintenanceGate struct {
+	// Delay is the time to wait after a batch completes before the next batch
+	// starts.
+	Delay time.Duration
+
+	// HealthyAllocs waits for the allocations migrated off the nodes of the
+	// previous batches to have healthy replacements.
+	HealthyAllocs bool
+}
+
+// MaintenanceNode tracks the maintenance of a single node of a plan.
+type MaintenanceNode struct {
+	NodeID string
+
+	// Batch is the batch of the plan the node belongs to.
+	Batch int
+
+	// Status is the maintenance status of the node.
+	Status string
+
+	// StatusDescription gives more detail about the status of the node.
+	StatusDescription string
+
+	// Done is set once an operator signalled the maintenance of the node is
+	// done.
+	Done bool
+
+	// DrainedAgentStartID is the agent start ID of the node observed once
+	// the drain completed. It is used to detect the node registering again
+	// after its agent restarted.
+	DrainedAgentStartID string
+
+	// UpdateTime is the time of the last status change.
+	UpdateTime int64
+}
+
+// Copy returns a deep copy of the maintenance selector.
+func (m *MaintenanceSelector) Copy() *MaintenanceSelector {
+	if m == nil {
+		return nil
+	}
+
+	nm := new(MaintenanceSelector)
+	*nm = *m
+	nm.Meta = helper.CopyMapStringString(m.Meta)
+	return nm
+}
+
+// Matches returns true if the node is selected.
+func (m *MaintenanceSelector) Matches(node *Node) (bool, error) {
+	if m == nil {
+		return true, nil
+	}
+	if m.NodeClass != "" && node.NodeClass != m.NodeClass {
+		return false, nil
+	}
+	if m.Datacenter != "" && node.Datacenter != m.Datacenter {
+		return false, nil
+	}
+	for k, v := range m.Meta {
+		if value, ok := node.Meta[k]; !ok || value != v {
+			return false, nil
+		}
+	}
+	if m.Filter != "" {
+		evaluator, err := bexpr.CreateEvaluator(m.Filter)
+		if err != nil {
+			return false, err
+		}
+		return evaluator.Evaluate(node)
+	}
+	return true, nil
+}
+
+// Copy returns a copy of the maintenance gate.
+func (m *MaintenanceGate) Copy() *MaintenanceGate {
+	if m == nil {
+		return nil
+	}
+
+	nm := new(MaintenanceGate)
+	*nm = *m
+	return nm
+}
+
+// Copy returns a copy of the maintenance node.
+func (m *MaintenanceNode) Copy() *MaintenanceNode {
+	if m == nil {
+		return nil
+	}
+
+	nm := new(MaintenanceNode)
+	*nm = *m
+	return nm
+}
+
+// Terminal returns true if the maintenance of the node is over.
+func (m *MaintenanceNode) Terminal() bool {
+	switch m.Status {
+	case MaintenanceNodeStatusComplete, MaintenanceNodeStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetStatus updates the status of the node.
+func (m *MaintenanceNode) SetStatus(status, desc string, now time.Time) {
+	m.Status = status
+	m.StatusDescription = desc
+	m.UpdateTime = now.UnixNano()
+}
+
+// Copy returns a deep copy of the maintenance plan.
+func (m *MaintenancePlan) Copy() *MaintenancePlan {
+	if m == nil {
+		return nil
+	}
+
+	nm := new(MaintenancePlan)
+	*nm = *m
+	nm.Selector = m.Selector.Copy()
+	nm.DrainSpec = m.DrainSpec.Copy()
+	nm.Gate = m.Gate.Copy()
+	if m.Nodes != nil {
+		nm.Nodes = make([]*MaintenanceNode, len(m.Nodes))
+		for i, node := range m.Nodes {
+			nm.Nodes[i] = node.Copy()
+		}
+	}
+	return nm
+}
+
+// Canonicalize sets the defaults of the user provided fields of the plan.
+func (m *MaintenancePlan) Canonicalize() {
+	if m.Concurrency == 0 {
+		m.Concurrency = 1
+	}
+	if m.CompleteOn == "" {
+		m.CompleteOn = MaintenanceCompleteOnSignal
+	}
+	if m.DrainSpec == nil {
+		m.DrainSpec = &DrainSpec{Deadline: DefaultMaintenanceDrainDeadline}
+	}
+}
+
+// Validate returns an error if the user provided fields of the plan are
+// invalid.
+func (m *MaintenancePlan) Validate() error {
+	var mErr multierror.Error
+
+	if len(m.Name) > maxMaintenancePlanNameLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("name longer than %d", maxMaintenancePlanNameLength))
+	}
+	if m.Concurrency < 1 {
+		mErr.Errors = append(mErr.Errors, errors.New("concurrency must be at least 1"))
+	}
+	switch m.CompleteOn {
+	case MaintenanceCompleteOnSignal, MaintenanceCompleteOnReregister:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid complete on %q", m.CompleteOn))
+	}
+	if m.DrainSpec == nil {
+		mErr.Errors = append(mErr.Errors, errors.New("missing drain spec"))
+	} else if err := m.DrainSpec.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid drain spec: %v", err))
+	}
+	if m.Selector != nil && m.Selector.Filter != "" {
+		if _, err := bexpr.CreateEvaluator(m.Selector.Filter); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid node filter: %v", err))
+		}
+	}
+	if m.Gate != nil && m.Gate.Delay < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("gate delay must not be negative"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Terminal returns true if the plan is no longer running.
+func (m *MaintenancePlan) Terminal() bool {
+	return m.Status != MaintenancePlanStatusRunning
+}
+
+// SetNodes sets the nodes of the plan, ordered by name, and assigns them to
+// batches according to the plan concurrency.
+func (m *MaintenancePlan) SetNodes(nodes []*Node, now time.Time) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	concurrency := helper.Max(1, m.Concurrency)
+	m.Nodes = make([]*MaintenanceNode, len(nodes))
+	for i, node := range nodes {
+		m.Nodes[i] = &MaintenanceNode{
+			NodeID:     node.ID,
+			Batch:      i / concurrency,
+			Status:     MaintenanceNodeStatusPending,
+			UpdateTime: now.UnixNano(),
+		}
+	}
+}
+
+// LookupNode returns the maintenance of the given node or nil if the node is
+// not part of the plan.
+func (m *MaintenancePlan) LookupNode(nodeID string) *MaintenanceNode {
+	for _, node := range m.Nodes {
+		if node.NodeID == nodeID {
+			return node
+		}
+	}
+	return nil
+}
+
+// Stub returns a summarized version of the plan.
+func (m *MaintenancePlan) Stub() *MaintenancePlanListStub {
+	stub := &MaintenancePlanListStub{
+		ID:                m.ID,
+		Name:              m.Name,
+		Concurrency:       m.Concurrency,
+		Status:            m.Status,
+		StatusDescription: m.StatusDescription,
+		NodesTotal:        len(m.Nodes),
+		CreateTime:        m.CreateTime,
+		ModifyTime:        m.ModifyTime,
+		CreateIndex:       m.CreateIndex,
+		ModifyIndex:       m.ModifyIndex,
+	}
+	for _, node := range m.Nodes {
+		if node.Status == MaintenanceNodeStatusComplete {
+			stub.NodesComplete++
+		}
+	}
+	return stub
+}
+
+// MaintenancePlanListStub is used to return a subset of a maintenance plan.
+type MaintenancePlanListStub struct {
+	ID                string
+	Name              string
+	Concurrency       int
+	Status            string
+	StatusDescription string
+	NodesTotal        int
+	NodesComplete     int
+	CreateTime        int64
+	ModifyTime        int64
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// MaintenancePlanListRequest is used to request a list of maintenance plans.
+type MaintenancePlanListRequest struct {
+	QueryOptions
+}
+
+// MaintenancePlanListResponse is the response object for a maintenance plan
+// list request.
+type MaintenancePlanListResponse struct {
+	Plans []*MaintenancePlanListStub
+	QueryMeta
+}
+
+// MaintenancePlanSpecificRequest is used to query a specific maintenance
+// plan.
+type MaintenancePlanSpecificRequest struct {
+	PlanID string
+	QueryOptions
+}
+
+// SingleMaintenancePlanResponse is the response object for a single
+// maintenance plan request.
+type SingleMaintenancePlanResponse struct {
+	Plan *MaintenancePlan
+	QueryMeta
+}
+
+// MaintenancePlanRegisterRequest is used to create a maintenance plan.
+type MaintenancePlanRegisterRequest struct {
+	Plan *MaintenancePlan
+	WriteRequest
+}
+
+// MaintenancePlanRegisterResponse is the response object for a maintenance
+// plan register request.
+type MaintenancePlanRegisterResponse struct {
+	PlanID string
+	WriteMeta
+}
+
+// MaintenancePlanCancelRequest is used to cancel a running maintenance plan.
+type MaintenancePlanCancelRequest struct {
+	PlanID string
+	WriteRequest
+}
+
+// MaintenancePlanNodeDoneRequest is used to signal that the maintenance of
+// some nodes of a plan is done.
+type MaintenancePlanNodeDoneRequest struct {
+	PlanID  string
+	NodeIDs []string
+	WriteRequest
+}
+
+// MaintenancePlanDeleteRequest is used to delete a set of maintenance plans.
+type MaintenancePlanDeleteRequest struct {
+	PlanIDs []string
+	WriteRequest
+}
+
+// MaintenancePlanUpsertRequest is used by the leader to store the progress
+// of maintenance plans.
+type MaintenancePlanUpsertRequest struct {
+	Plans []*MaintenancePlan
+	WriteRequest
+}
@@ -0,0 +1,172 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenancePlan_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		plan   *MaintenancePlan
+		expErr []string
+	}{
+		{
+			name: "valid",
+			plan: &MaintenancePlan{
+				Name:     "kernel-upgrade",
+				Selector: &MaintenanceSelector{Filter: `Attributes["kernel.name"] == "linux"`},
+			},
+		},
+		{
+			name: "invalid fields",
+			plan: &MaintenancePlan{
+				Name:        strings.Repeat("a", maxMaintenancePlanNameLength+1),
+				Concurrency: -1,
+				CompleteOn:  "never",
+				DrainSpec:   &DrainSpec{MaxParallelMigrations: -1},
+				Selector:    &MaintenanceSelector{Filter: `Attributes[`},
+				Gate:        &MaintenanceGate{Delay: -time.Second},
+			},
+			expErr: []string{
+				"name longer than",
+				"concurrency must be at least 1",
+				`invalid complete on "never"`,
+				"invalid drain spec",
+				"invalid node filter",
+				"gate delay must not be negative",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.plan.Canonicalize()
+			err := tc.plan.Validate()
+			if len(tc.expErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, exp := range tc.expErr {
+				require.Contains(t, err.Error(), exp)
+			}
+		})
+	}
+}
+
+func TestMaintenancePlan_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	plan := &MaintenancePlan{}
+	plan.Canonicalize()
+	require.Equal(t, 1, plan.Concurrency)
+	require.Equal(t, MaintenanceCompleteOnSignal, plan.CompleteOn)
+	require.Equal(t, DefaultMaintenanceDrainDeadline, plan.DrainSpec.Deadline)
+}
+
+func TestMaintenancePlan_SetNodes(t *testing.T) {
+	ci.Parallel(t)
+
+	var nodes []*Node
+	for _, name := range []string{"c", "a", "e", "b", "d"} {
+		nodes = append(nodes, &Node{ID: uuid.Generate(), Name: name})
+	}
+
+	plan := &MaintenancePlan{Concurrency: 2}
+	now := time.Now()
+	plan.SetNodes(nodes, now)
+
+	require.Len(t, plan.Nodes, 5)
+	expBatches := map[string]int{"a": 0, "b": 0, "c": 1, "d": 1, "e": 2}
+	for _, node := range nodes {
+		mn := plan.LookupNode(node.ID)
+		require.NotNil(t, mn)
+		require.Equal(t, expBatches[node.Name], mn.Batch, "node %s", node.Name)
+		require.Equal(t, MaintenanceNodeStatusPending, mn.Status)
+	}
+	require.Nil(t, plan.LookupNode(uuid.Generate()))
+
+	stub := plan.Stub()
+	require.Equal(t, 5, stub.NodesTotal)
+	require.Zero(t, stub.NodesComplete)
+}
+
+func TestMaintenanceSelector_Matches(t *testing.T) {
+	ci.Parallel(t)
+
+	node := &Node{
+		Datacenter: "dc1",
+		NodeClass:  "web",
+		Meta:       map[string]string{"rack": "r1"},
+		Attributes: map[string]string{"kernel.name": "linux"},
+	}
+
+	testCases := []struct {
+		name     string
+		selector *MaintenanceSelector
+		expMatch bool
+	}{
+		{name: "nil selector", expMatch: true},
+		{name: "empty selector", selector: &MaintenanceSelector{}, expMatch: true},
+		{
+			name: "all match",
+			selector: &MaintenanceSelector{
+				NodeClass:  "web",
+				Datacenter: "dc1",
+				Meta:       map[string]string{"rack": "r1"},
+				Filter:     `Attributes["kernel.name"] == "linux"`,
+			},
+			expMatch: true,
+		},
+		{name: "node class", selector: &MaintenanceSelector{NodeClass: "db"}},
+		{name: "datacenter", selector: &MaintenanceSelector{Datacenter: "dc2"}},
+		{name: "meta", selector: &MaintenanceSelector{Meta: map[string]string{"rack": "r2"}}},
+		{name: "missing meta", selector: &MaintenanceSelector{Meta: map[string]string{"zone": "z1"}}},
+		{name: "filter", selector: &MaintenanceSelector{Filter: `Attributes["kernel.name"] == "darwin"`}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, err := tc.selector.Matches(node)
+			require.NoError(t, err)
+			require.Equal(t, tc.expMatch, match)
+		})
+	}
+}
+
+func TestMaintenancePlan_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	plan := &MaintenancePlan{
+		ID:       uuid.Generate(),
+		Selector: &MaintenanceSelector{Meta: map[string]string{"rack": "r1"}},
+		DrainSpec: &DrainSpec{
+			Deadline: time.Hour,
+			JobOrder: []string{"web"},
+		},
+		Gate:  &MaintenanceGate{Delay: time.Minute},
+		Nodes: []*MaintenanceNode{{NodeID: uuid.Generate(), Status: MaintenanceNodeStatusPending}},
+	}
+
+	copied := plan.Copy()
+	require.Equal(t, plan, copied)
+
+	copied.Selector.Meta["rack"] = "r2"
+	copied.DrainSpec.JobOrder[0] = "api"
+	copied.Gate.Delay = time.Hour
+	copied.Nodes[0].Status = MaintenanceNodeStatusDraining
+
+	require.Equal(t, "r1", plan.Selector.Meta["rack"])
+	require.Equal(t, "web", plan.DrainSpec.JobOrder[0])
+	require.Equal(t, time.Minute, plan.Gate.Delay)
+	require.Equal(t, MaintenanceNodeStatusPending, plan.Nodes[0].Status)
+}
@@ -0,0 +1,246 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// NodePoolAll is a built-in node pool that always includes all nodes in
+	// the cluster. Jobs that target it may be placed on any node.
+	NodePoolAll            = "all"
+	NodePoolAllDescription = "Node pool with all nodes in the cluster."
+
+	// NodePoolDefault is a built-in node pool for nodes that don't specify
+	// a node pool in their configuration and for jobs that don't target one.
+	NodePoolDefault            = "default"
+	NodePoolDefaultDescription = "Default node pool."
+
+	// maxNodePoolDescriptionLength limits a node pool description length.
+	maxNodePoolDescriptionLength = 256
+)
+
+const (
+	// NodePoolListRPCMethod is the RPC method for listing node pools.
+	//
+	// Args: NodePoolListRequest
+	// Reply: NodePoolListResponse
+	NodePoolListRPCMethod = "NodePool.List"
+
+	// NodePoolGetNodePoolRPCMethod is the RPC method for detailing a single
+	// node pool according to its name.
+	//
+	// Args: NodePoolSpecificRequest
+	// Reply: SingleNodePoolResponse
+	NodePoolGetNodePoolRPCMethod = "NodePool.GetNodePool"
+
+	// NodePoolUpsertNodePoolsRPCMethod is the RPC method for creating or
+	// updating node pools.
+	//
+	// Args: NodePoolUpsertRequest
+	// Reply: GenericResponse
+	NodePoolUpsertNodePoolsRPCMethod = "NodePool.UpsertNodePools"
+
+	// NodePoolDeleteNodePoolsRPCMethod is the RPC method for deleting node
+	// pools according to their name.
+	//
+	// Args: NodePoolDeleteRequest
+	// Reply: GenericResponse
+	NodePoolDeleteNodePoolsRPCMethod = "NodePool.DeleteNodePools"
+)
+
+var (
+	// validNodePoolName is the rule used to validate a node pool name.
+	validNodePoolName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+)
+
+// ValidNodePoolName returns true if the name is a valid node pool name.
+func ValidNodePoolName(name string) bool {
+	return validNodePoolName.MatchString(name)
+}
+
+// NodePool allows partitioning infrastructure. Clients declare the node pool
+// they belong to and jobs may only be placed on the nodes of the node pool
+// they target.
+type NodePool struct {
+	// Name is the node pool name. It must be unique.
+	Name string
+
+	// Description is the human-friendly description of the node pool.
+	Description string
+
+	// Meta is a set of user-provided metadata for the node pool.
+	Meta map[string]string
+
+	// SchedulerConfiguration is the scheduler configuration specific to the
+	// node pool. Any value set overrides the cluster-wide scheduler
+	// configuration for placements within the node pool.
+	SchedulerConfiguration *NodePoolSchedulerConfiguration
+
+	// Hash is the hash of the node pool which is used to efficiently detect
+	// changes.
+	Hash []byte
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NodePoolSchedulerConfiguration is the scheduler configuration applied to a
+// node pool. Unset values fall back to the cluster-wide configuration.
+type NodePoolSchedulerConfiguration struct {
+	// SchedulerAlgorithm is the scheduling algorithm to use for the pool.
+	SchedulerAlgorithm SchedulerAlgorithm `hcl:"scheduler_algorithm"`
+
+	// MemoryOversubscriptionEnabled specifies whether memory oversubscription
+	// is enabled for the pool.
+	MemoryOversubscriptionEnabled *bool `hcl:"memory_oversubscription_enabled"`
+}
+
+// Copy returns a deep copy of the node pool scheduler configuration.
+func (n *NodePoolSchedulerConfiguration) Copy() *NodePoolSchedulerConfiguration {
+	if n == nil {
+		return nil
+	}
+
+	nc := new(NodePoolSchedulerConfiguration)
+	*nc = *n
+	if n.MemoryOversubscriptionEnabled != nil {
+		nc.MemoryOversubscriptionEnabled = helper.BoolToPtr(*n.MemoryOversubscriptionEnabled)
+	}
+	return nc
+}
+
+// Validate returns an error if the node pool scheduler configuration is
+// invalid.
+func (n *NodePoolSchedulerConfiguration) Validate() error {
+	if n == nil {
+		return nil
+	}
+
+	return n.SchedulerAlgorithm.Validate()
+}
+
+// Validate returns an error if the node pool is invalid.
+func (n *NodePool) Validate() error {
+	var mErr multierror.Error
+
+	if !validNodePoolName.MatchString(n.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name %q, must match regex %s", n.Name, validNodePoolName))
+	}
+	if len(n.Description) > maxNodePoolDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxNodePoolDescriptionLength))
+	}
+	if err := n.SchedulerConfiguration.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid scheduler configuration: %v", err))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// IsBuiltIn returns true if the node pool is one of the built-in pools which
+// are always present and can't be modified or deleted.
+func (n *NodePool) IsBuiltIn() bool {
+	switch n.Name {
+	case NodePoolAll, NodePoolDefault:
+		return true
+	default:
+		return false
+	}
+}
+
+// Copy returns a deep copy of the node pool.
+func (n *NodePool) Copy() *NodePool {
+	if n == nil {
+		return nil
+	}
+
+	nc := new(NodePool)
+	*nc = *n
+	nc.Meta = helper.CopyMapStringString(n.Meta)
+	nc.SchedulerConfiguration = n.SchedulerConfiguration.Copy()
+	nc.Hash = make([]byte, len(n.Hash))
+	copy(nc.Hash, n.Hash)
+	return nc
+}
+
+// SetHash is used to compute and set the hash of the node pool.
+func (n *NodePool) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	_, _ = hash.Write([]byte(n.Name))
+	_, _ = hash.Write([]byte(n.Description))
+	if n.SchedulerConfiguration != nil {
+		_, _ = hash.Write([]byte(n.SchedulerConfiguration.SchedulerAlgorithm))
+
+		memSub := n.SchedulerConfiguration.MemoryOversubscriptionEnabled
+		if memSub != nil {
+			_, _ = hash.Write([]byte(fmt.Sprintf("%v", *memSub)))
+		}
+	}
+
+	// sort keys to ensure hash stability when meta is stored later
+	var keys []string
+	for k := range n.Meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		_, _ = hash.Write([]byte(k))
+		_, _ = hash.Write([]byte(n.Meta[k]))
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	n.Hash = hashVal
+	return hashVal
+}
+
+// NodePoolListRequest is used to request a list of node pools.
+type NodePoolListRequest struct {
+	QueryOptions
+}
+
+// NodePoolListResponse is the response object for a node pool list request.
+type NodePoolListResponse struct {
+	NodePools []*NodePool
+	QueryMeta
+}
+
+// NodePoolSpecificRequest is used to query a specific node pool.
+type NodePoolSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleNodePoolResponse is the response object for a single node pool
+// request.
+type SingleNodePoolResponse struct {
+	NodePool *NodePool
+	QueryMeta
+}
+
+// NodePoolUpsertRequest is used to create or update a set of node pools.
+type NodePoolUpsertRequest struct {
+	NodePools []*NodePool
+	WriteRequest
+}
+
+// NodePoolDeleteRequest is used to delete a set of node pools.
+type NodePoolDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
@@ -0,0 +1,283 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePool_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name        string
+		pool        *NodePool
+		expectedErr string
+	}{
+		{
+			name: "valid pool",
+			pool: &NodePool{
+				Name:        "valid",
+				Description: "a valid node pool",
+				SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm: SchedulerAlgorithmSpread,
+				},
+			},
+		},
+		{
+			name:        "invalid name",
+			pool:        &NodePool{Name: "not@valid"},
+			expectedErr: "invalid name",
+		},
+		{
+			name:        "empty name",
+			pool:        &NodePool{},
+			expectedErr: "invalid name",
+		},
+		{
+			name: "description too long",
+			pool: &NodePool{
+				Name:        "valid",
+				Description: strings.Repeat("a", maxNodePoolDescriptionLength+1),
+			},
+			expectedErr: "description longer than",
+		},
+		{
+			name: "invalid scheduler algorithm",
+			pool: &NodePool{
+				Name: "valid",
+				SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+					SchedulerAlgorithm: "invalid",
+				},
+			},
+			expectedErr: "invalid scheduler algorithm",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.pool.Validate()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNodePool_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	pool := &NodePool{
+		Name:        "original",
+		Description: "original node pool",
+		Meta:        map[string]string{"original": "true"},
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(false),
+		},
+	}
+	pool.SetHash()
+
+	poolCopy := pool.Copy()
+	require.Equal(t, pool, poolCopy)
+
+	poolCopy.Name = "copy"
+	poolCopy.Meta["original"] = "false"
+	poolCopy.SchedulerConfiguration.SchedulerAlgorithm = SchedulerAlgorithmBinpack
+	*poolCopy.SchedulerConfiguration.MemoryOversubscriptionEnabled = true
+	poolCopy.SetHash()
+
+	require.Equal(t, "original", pool.Name)
+	require.Equal(t, "true", pool.Meta["original"])
+	require.Equal(t, SchedulerAlgorithmSpread, pool.SchedulerConfiguration.SchedulerAlgorithm)
+	require.False(t, *pool.SchedulerConfiguration.MemoryOversubscriptionEnabled)
+	require.NotEqual(t, pool.Hash, poolCopy.Hash)
+}
+
+func TestNodePool_IsBuiltIn(t *testing.T) {
+	ci.Parallel(t)
+
+	require.True(t, (&NodePool{Name: NodePoolAll}).IsBuiltIn())
+	require.True(t, (&NodePool{Name: NodePoolDefault}).IsBuiltIn())
+	require.False(t, (&NodePool{Name: "dev"}).IsBuiltIn())
+}
+
+func TestSchedulerConfiguration_WithNodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	config := &SchedulerConfiguration{
+		SchedulerAlgorithm:            SchedulerAlgorithmBinpack,
+		MemoryOversubscriptionEnabled: false,
+	}
+
+	// No node pool or no overrides return the same configuration
+	require.Equal(t, config, config.WithNodePool(nil))
+	require.Equal(t, config, config.WithNodePool(&NodePool{Name: "dev"}))
+
+	// Partial overrides
+	out := config.WithNodePool(&NodePool{
+		Name: "dev",
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	})
+	require.Equal(t, SchedulerAlgorithmBinpack, out.SchedulerAlgorithm)
+	require.True(t, out.MemoryOversubscriptionEnabled)
+
+	// Full overrides
+	out = config.WithNodePool(&NodePool{
+		Name: "dev",
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	})
+	require.Equal(t, SchedulerAlgorithmSpread, out.SchedulerAlgorithm)
+	require.True(t, out.MemoryOversubscriptionEnabled)
+
+	// The original configuration is not modified
+	require.Equal(t, SchedulerAlgorithmBinpack, config.SchedulerAlgorithm)
+	require.False(t, config.MemoryOversubscriptionEnabled)
+
+	// Overrides apply to clusters without a stored configuration
+	var noConfig *SchedulerConfiguration
+	require.Nil(t, noConfig.WithNodePool(&NodePool{Name: "dev"}))
+	out = noConfig.WithNodePool(&NodePool{
+		Name: "dev",
+		SchedulerConfiguration: &NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	})
+	require.Equal(t, SchedulerAlgorithmSpread, out.SchedulerAlgorithm)
+	require.True(t, out.MemoryOversubscriptionEnabled)
+}
+
+func TestNamespaceNodePoolConfiguration_IsAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		config   *NamespaceNodePoolConfiguration
+		pool     string
+		expected bool
+	}{
+		{
+			name:     "nil config allows all",
+			config:   nil,
+			pool:     "dev",
+			expected: true,
+		},
+		{
+			name:     "default is always allowed",
+			config:   &NamespaceNodePoolConfiguration{Default: "dev", Allowed: []string{}},
+			pool:     "dev",
+			expected: true,
+		},
+		{
+			name:     "allowed glob",
+			config:   &NamespaceNodePoolConfiguration{Allowed: []string{"prod-*"}},
+			pool:     "prod-east",
+			expected: true,
+		},
+		{
+			name:     "not allowed",
+			config:   &NamespaceNodePoolConfiguration{Allowed: []string{"prod-*"}},
+			pool:     "dev",
+			expected: false,
+		},
+		{
+			name:     "denied glob",
+			config:   &NamespaceNodePoolConfiguration{Denied: []string{"gpu-*"}},
+			pool:     "gpu-large",
+			expected: false,
+		},
+		{
+			name:     "not denied",
+			config:   &NamespaceNodePoolConfiguration{Denied: []string{"gpu-*"}},
+			pool:     "dev",
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.config.IsAllowed(tc.pool))
+		})
+	}
+}
+
+func TestNamespaceNodePoolConfiguration_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, (&NamespaceNodePoolConfiguration{Default: "dev"}).Validate())
+	require.Error(t, (&NamespaceNodePoolConfiguration{Default: "not@valid"}).Validate())
+	require.Error(t, (&NamespaceNodePoolConfiguration{
+		Allowed: []string{"a"},
+		Denied:  []string{"b"},
+	}).Validate())
+}
+
+func TestSchedulerConfiguration_WithNamespace(t *testing.T) {
+	ci.Parallel(t)
+
+	config := &SchedulerConfiguration{
+		SchedulerAlgorithm:            SchedulerAlgorithmBinpack,
+		MemoryOversubscriptionEnabled: true,
+	}
+
+	// No namespace or no overrides return the same configuration
+	require.Equal(t, config, config.WithNamespace(nil))
+	require.Equal(t, config, config.WithNamespace(&Namespace{Name: "batch"}))
+	require.Equal(t, config, config.WithNamespace(&Namespace{
+		Name:                   "batch",
+		SchedulerConfiguration: &NamespaceSchedulerConfiguration{},
+	}))
+
+	out := config.WithNamespace(&Namespace{
+		Name: "web",
+		SchedulerConfiguration: &NamespaceSchedulerConfiguration{
+			SchedulerAlgorithm: SchedulerAlgorithmSpread,
+		},
+	})
+	require.Equal(t, SchedulerAlgorithmSpread, out.SchedulerAlgorithm)
+	require.True(t, out.MemoryOversubscriptionEnabled)
+
+	// The original configuration is not modified
+	require.Equal(t, SchedulerAlgorithmBinpack, config.SchedulerAlgorithm)
+}
+
+func TestSchedulerConfiguration_WithSchedulerAlgorithm(t *testing.T) {
+	ci.Parallel(t)
+
+	config := &SchedulerConfiguration{SchedulerAlgorithm: SchedulerAlgorithmBinpack}
+	require.Equal(t, config, config.WithSchedulerAlgorithm(""))
+	require.Equal(t, SchedulerAlgorithmSpread, config.WithSchedulerAlgorithm(SchedulerAlgorithmSpread).SchedulerAlgorithm)
+	require.Equal(t, SchedulerAlgorithmBinpack, config.SchedulerAlgorithm)
+
+	// Overrides apply without a cluster configuration
+	var nilConfig *SchedulerConfiguration
+	require.Nil(t, nilConfig.WithSchedulerAlgorithm(""))
+	require.Equal(t, SchedulerAlgorithmSpread, nilConfig.WithSchedulerAlgorithm(SchedulerAlgorithmSpread).EffectiveSchedulerAlgorithm())
+}
+
+func TestNamespaceSchedulerConfiguration_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, (*NamespaceSchedulerConfiguration)(nil).Validate())
+	require.NoError(t, (&NamespaceSchedulerConfiguration{}).Validate())
+	require.NoError(t, (&NamespaceSchedulerConfiguration{SchedulerAlgorithm: SchedulerAlgorithmSpread}).Validate())
+	require.EqualError(t, (&NamespaceSchedulerConfiguration{SchedulerAlgorithm: "random"}).Validate(),
+		`invalid scheduler algorithm "random"`)
+
+	ns := &Namespace{
+		Name:                   "batch",
+		SchedulerConfiguration: &NamespaceSchedulerConfiguration{SchedulerAlgorithm: "random"},
+	}
+	require.ErrorContains(t, ns.Validate(), "invalid scheduler configuration")
+}
@@ -140,6 +140,17 @@ const (
 	SchedulerAlgorithmSpread SchedulerAlgorithm = "spread"
 )
 
+// Validate returns an error if the scheduler algorithm is not empty and not
+// one of the known algorithms.
+func (a SchedulerAlgorithm) Validate() error {
+	switch a {
+	case "", SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread:
+		return nil
+	default:
+		return fmt.Errorf("invalid scheduler algorithm %q", a)
+	}
+}
+
 // SchedulerConfiguration is the config for controlling scheduler behavior
 type SchedulerConfiguration struct {
 	// SchedulerAlgorithm lets you select between available scheduling algorithms.
@@ -156,6 +167,11 @@ type SchedulerConfiguration struct {
 	// management ACL token
 	RejectJobRegistration bool `hcl:"reject_job_registration"`
 
+	// PauseEvalBroker stops the leader from handing evaluations to the
+	// schedulers. Evaluations created while paused are kept in the state
+	// store and enqueued once the broker is resumed.
+	PauseEvalBroker bool `hcl:"pause_eval_broker"`
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -169,6 +185,50 @@ func (s *SchedulerConfiguration) EffectiveSchedulerAlgorithm() SchedulerAlgorith
 	return s.SchedulerAlgorithm
 }
 
+// WithNodePool returns a copy of the scheduler configuration with the
+// overrides set in the node pool scheduler configuration applied.
+func (s *SchedulerConfiguration) WithNodePool(pool *NodePool) *SchedulerConfiguration {
+	if pool == nil || pool.SchedulerConfiguration == nil {
+		return s
+	}
+
+	var sc SchedulerConfiguration
+	if s != nil {
+		sc = *s
+	}
+	if alg := pool.SchedulerConfiguration.SchedulerAlgorithm; alg != "" {
+		sc.SchedulerAlgorithm = alg
+	}
+	if memSub := pool.SchedulerConfiguration.MemoryOversubscriptionEnabled; memSub != nil {
+		sc.MemoryOversubscriptionEnabled = *memSub
+	}
+	return &sc
+}
+
+// WithNamespace returns a copy of the scheduler configuration with the
+// overrides set in the namespace scheduler configuration applied.
+func (s *SchedulerConfiguration) WithNamespace(ns *Namespace) *SchedulerConfiguration {
+	if ns == nil || ns.SchedulerConfiguration == nil {
+		return s
+	}
+	return s.WithSchedulerAlgorithm(ns.SchedulerConfiguration.SchedulerAlgorithm)
+}
+
+// WithSchedulerAlgorithm returns a copy of the scheduler configuration using
+// the given scheduler algorithm, unless it is empty.
+func (s *SchedulerConfiguration) WithSchedulerAlgorithm(alg SchedulerAlgorithm) *SchedulerConfiguration {
+	if alg == "" {
+		return s
+	}
+
+	var sc SchedulerConfiguration
+	if s != nil {
+		sc = *s
+	}
+	sc.SchedulerAlgorithm = alg
+	return &sc
+}
+
 func (s *SchedulerConfiguration) Canonicalize() {
 	if s != nil && s.SchedulerAlgorithm == "" {
 		s.SchedulerAlgorithm = SchedulerAlgorithmBinpack
@@ -85,10 +85,66 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the latest result of each health check defined within
+	// the service block. The checks are executed by the client running the
+	// allocation, which updates the registration whenever a check changes
+	// status.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// ServiceRegistrationCheck is the status of a single health check which is
+// executed by the Nomad client on behalf of a service registration.
+type ServiceRegistrationCheck struct {
+
+	// ID is the unique identifier of the check within the service
+	// registration. It is derived from the check definition, so a modified
+	// check block results in a new ID.
+	ID string
+
+	// Name is the name of the check as defined within the jobspec.
+	Name string
+
+	// Type is the check type, which is either ServiceCheckHTTP or
+	// ServiceCheckTCP.
+	Type string
+
+	// Status is the current status of the check and is one of
+	// ServiceCheckPassing, ServiceCheckWarning, or ServiceCheckCritical.
+	Status string
+
+	// Output is a human-readable description of the last check result, such
+	// as the HTTP status code received or the connection error.
+	Output string
+
+	// Timestamp is the UnixNano time at which the status of the check last
+	// changed.
+	Timestamp int64
+}
+
+// Copy creates a copy of the check status. It handles nil objects.
+func (c *ServiceRegistrationCheck) Copy() *ServiceRegistrationCheck {
+	if c == nil {
+		return nil
+	}
+	nc := new(ServiceRegistrationCheck)
+	*nc = *c
+	return nc
+}
+
+// Equals performs an equality check on the two check statuses. The output and
+// timestamp are not compared, as they are informational and comparing them
+// would cause state churn without any change in health. It handles nil
+// objects.
+func (c *ServiceRegistrationCheck) Equals(o *ServiceRegistrationCheck) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return c.ID == o.ID && c.Name == o.Name && c.Type == o.Type && c.Status == o.Status
+}
+
 // Copy creates a deep copy of the service registration. This copy can then be
 // safely modified. It handles nil objects.
 func (s *ServiceRegistration) Copy() *ServiceRegistration {
@@ -100,6 +156,13 @@ func (s *ServiceRegistration) Copy() *ServiceRegistration {
 	*ns = *s
 	ns.Tags = helper.CopySliceString(ns.Tags)
 
+	if s.Checks != nil {
+		ns.Checks = make([]*ServiceRegistrationCheck, len(s.Checks))
+		for i, check := range s.Checks {
+			ns.Checks[i] = check.Copy()
+		}
+	}
+
 	return ns
 }
 
@@ -139,6 +202,29 @@ func (s *ServiceRegistration) Equals(o *ServiceRegistration) bool {
 	if !helper.CompareSliceSetString(s.Tags, o.Tags) {
 		return false
 	}
+	if len(s.Checks) != len(o.Checks) {
+		return false
+	}
+	for i := range s.Checks {
+		if !s.Checks[i].Equals(o.Checks[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Healthy returns whether the service registration should be considered able
+// to serve traffic. A registration without checks is always healthy, otherwise
+// none of its checks may be critical.
+func (s *ServiceRegistration) Healthy() bool {
+	if s == nil {
+		return false
+	}
+	for _, check := range s.Checks {
+		if check.Status == ServiceCheckCritical {
+			return false
+		}
+	}
 	return true
 }
 
@@ -382,6 +382,39 @@ func TestServiceRegistration_Equal(t *testing.T) {
 	}
 }
 
+func TestServiceRegistration_Checks(t *testing.T) {
+	sr := &ServiceRegistration{
+		ID:          "_nomad-task-2873cf75-42e5-7c45-ca1c-415f3e18be3d-group-cache-example-cache-db",
+		ServiceName: "example-cache",
+		Namespace:   "default",
+		Address:     "192.168.13.13",
+		Port:        23813,
+	}
+
+	// A registration without checks is always healthy.
+	require.True(t, sr.Healthy())
+
+	sr.Checks = []*ServiceRegistrationCheck{
+		{ID: "check-1", Name: "alive", Type: ServiceCheckTCP, Status: ServiceCheckPassing, Output: "ok"},
+		{ID: "check-2", Name: "ready", Type: ServiceCheckHTTP, Status: ServiceCheckWarning},
+	}
+	require.True(t, sr.Healthy())
+
+	// The copy must be deep, so modifying a check status is detected.
+	newSR := sr.Copy()
+	require.True(t, sr.Equals(newSR))
+
+	newSR.Checks[1].Status = ServiceCheckCritical
+	require.False(t, sr.Equals(newSR))
+	require.True(t, sr.Healthy())
+	require.False(t, newSR.Healthy())
+
+	// Output changes alone are not considered a modification.
+	newSR = sr.Copy()
+	newSR.Checks[0].Output = "still ok"
+	require.True(t, sr.Equals(newSR))
+}
+
 func TestServiceRegistration_GetID(t *testing.T) {
 	testCases := []struct {
 		inputServiceRegistration *ServiceRegistration
@@ -29,6 +29,14 @@ const (
 	ServiceCheckScript = "script"
 	ServiceCheckGRPC   = "grpc"
 
+	// ServiceCheckPassing, ServiceCheckWarning, and ServiceCheckCritical are
+	// the statuses a check can have. They mirror the Consul health statuses,
+	// so check definitions and their results behave in the same manner
+	// regardless of the service provider.
+	ServiceCheckPassing  = api.HealthPassing
+	ServiceCheckWarning  = api.HealthWarning
+	ServiceCheckCritical = api.HealthCritical
+
 	// minCheckInterval is the minimum check interval permitted.  Consul
 	// currently has its MinInterval set to 1s.  Mirror that here for
 	// consistency.
@@ -663,11 +671,35 @@ func (s *Service) validateConsulService(mErr *multierror.Error) {
 // nomad provider.
 func (s *Service) validateNomadService(mErr *multierror.Error) {
 
-	// Service blocks for the Nomad provider do not support checks. We perform
-	// a nil check, as an empty check list is nilled within the service
-	// canonicalize function.
-	if s.Checks != nil {
-		mErr.Errors = append(mErr.Errors, errors.New("Service with provider nomad cannot include Check blocks"))
+	// Checks for services using the Nomad provider are executed by the Nomad
+	// client, which only supports a subset of check types and features.
+	for _, c := range s.Checks {
+		switch c.Type {
+		case ServiceCheckHTTP, ServiceCheckTCP:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: service with provider nomad only supports %q and %q checks, not %q",
+				c.Name, ServiceCheckHTTP, ServiceCheckTCP, c.Type))
+			continue
+		}
+
+		if s.PortLabel == "" && c.PortLabel == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: check requires a port but neither check nor service %+q have a port", c.Name, s.Name))
+			continue
+		}
+
+		if c.Expose {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: service with provider nomad cannot expose checks", c.Name))
+			continue
+		}
+
+		if c.CheckRestart != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: service with provider nomad does not support check_restart", c.Name))
+			continue
+		}
+
+		if err := c.validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: %v", c.Name, err))
+		}
 	}
 
 	// Services using the Nomad provider do not support Consul connect.
@@ -1221,6 +1253,9 @@ func (t *SidecarTask) MergeIntoTask(task *Task) {
 			if t.LogConfig.MaxFileSizeMB > 0 {
 				task.LogConfig.MaxFileSizeMB = t.LogConfig.MaxFileSizeMB
 			}
+			if len(t.LogConfig.Sinks) > 0 {
+				task.LogConfig.Sinks = t.LogConfig.Sinks
+			}
 		}
 	}
 
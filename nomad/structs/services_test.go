@@ -1562,8 +1562,26 @@ func TestService_Validate(t *testing.T) {
 				},
 			},
 			expErr:    true,
-			expErrStr: "Service with provider nomad cannot include Check blocks",
-			name:      "provider nomad with checks",
+			expErrStr: `service with provider nomad only supports "http" and "tcp" checks`,
+			name:      "provider nomad with unsupported check type",
+		},
+		{
+			input: &Service{
+				Name:      "testservice",
+				Provider:  "nomad",
+				PortLabel: "http",
+				Checks: []*ServiceCheck{
+					{
+						Name:     "servicecheck",
+						Type:     ServiceCheckHTTP,
+						Path:     "/health",
+						Interval: 10 * time.Second,
+						Timeout:  2 * time.Second,
+					},
+				},
+			},
+			expErr: false,
+			name:   "provider nomad with http check",
 		},
 		{
 			input: &Service{
@@ -1715,9 +1733,54 @@ func TestService_validateNomadService(t *testing.T) {
 					{Name: "some-check"},
 				},
 			},
+			inputErr: &multierror.Error{},
+			expectedOutputErrors: []error{
+				errors.New(`Check some-check invalid: service with provider nomad only supports "http" and "tcp" checks, not ""`),
+			},
+			name: "invalid service due to check type",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{
+					{
+						Name:     "some-check",
+						Type:     ServiceCheckTCP,
+						Interval: 10 * time.Second,
+						Timeout:  2 * time.Second,
+						CheckRestart: &CheckRestart{
+							Limit: 3,
+						},
+					},
+				},
+			},
+			inputErr: &multierror.Error{},
+			expectedOutputErrors: []error{
+				errors.New("Check some-check invalid: service with provider nomad does not support check_restart"),
+			},
+			name: "invalid service due to check restart",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{
+					{
+						Name:     "some-check",
+						Type:     ServiceCheckTCP,
+						Interval: 10 * time.Second,
+						Timeout:  2 * time.Second,
+					},
+				},
+			},
 			inputErr:             &multierror.Error{},
-			expectedOutputErrors: []error{errors.New("Service with provider nomad cannot include Check blocks")},
-			name:                 "invalid service due to checks",
+			expectedOutputErrors: []error{},
+			name:                 "valid service with tcp check",
 		},
 		{
 			inputService: &Service{
@@ -1748,7 +1811,7 @@ func TestService_validateNomadService(t *testing.T) {
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New("Service with provider nomad cannot include Check blocks"),
+				errors.New(`Check some-check invalid: service with provider nomad only supports "http" and "tcp" checks, not ""`),
 				errors.New("Service with provider nomad cannot include Connect blocks"),
 			},
 			name: "invalid service due to checks and connect",
@@ -16,6 +16,7 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -43,6 +44,7 @@ import (
 	psstructs "github.com/hashicorp/nomad/plugins/shared/structs"
 	"github.com/miekg/dns"
 	"github.com/mitchellh/copystructure"
+	glob "github.com/ryanuber/go-glob"
 )
 
 var (
@@ -108,10 +110,27 @@ const (
 	ServiceRegistrationUpsertRequestType         MessageType = 47
 	ServiceRegistrationDeleteByIDRequestType     MessageType = 48
 	ServiceRegistrationDeleteByNodeIDRequestType MessageType = 49
+	VarApplyStateRequestType                     MessageType = 50
+	RootKeyMetaUpsertRequestType                 MessageType = 51
+	ACLRolesUpsertRequestType                    MessageType = 52
+	ACLRolesDeleteByIDRequestType                MessageType = 53
+	ACLAuthMethodsUpsertRequestType              MessageType = 54
+	ACLAuthMethodsDeleteRequestType              MessageType = 55
+	ACLBindingRulesUpsertRequestType             MessageType = 56
+	ACLBindingRulesDeleteRequestType             MessageType = 57
+	NodePoolUpsertRequestType                    MessageType = 58
+	NodePoolDeleteRequestType                    MessageType = 59
+	PeriodicLaunchSkipRequestType                MessageType = 60
+	JobEvalPauseRequestType                      MessageType = 61
+	MaintenancePlanUpsertRequestType             MessageType = 62
+	MaintenancePlanDeleteRequestType             MessageType = 63
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
 	NamespaceDeleteRequestType MessageType = 65
+
+	HostVolumeUpsertRequestType MessageType = 66
+	HostVolumeDeleteRequestType MessageType = 67
 )
 
 const (
@@ -600,6 +619,10 @@ type JobRegisterRequest struct {
 	// Eval is the evaluation that is associated with the job registration
 	Eval *Evaluation
 
+	// Submission is the original source of the job, as given to the CLI. It
+	// is optional and is stored alongside the registered job version.
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
@@ -808,6 +831,23 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobEvalPauseRequest is used to pause or resume the evaluations of a job.
+type JobEvalPauseRequest struct {
+	// JobID is the job to pause or resume the evaluations of
+	JobID string
+
+	// Paused marks whether the evaluations of the job are paused
+	Paused bool
+
+	WriteRequest
+}
+
+// JobEvalPauseResponse is the response when pausing or resuming the
+// evaluations of a job.
+type JobEvalPauseResponse struct {
+	WriteMeta
+}
+
 // NodeListRequest is used to parameterize a list request
 type NodeListRequest struct {
 	QueryOptions
@@ -829,6 +869,22 @@ type EvalDeleteRequest struct {
 	WriteRequest
 }
 
+// EvalBatchDeleteRequest is used by operators to delete pending and blocked
+// evaluations, either by ID or by matching a filter expression. Exactly one
+// of EvalIDs or Filter must be set.
+type EvalBatchDeleteRequest struct {
+	EvalIDs []string
+	Filter  string
+	WriteRequest
+}
+
+// EvalBatchDeleteResponse is the response to an EvalBatchDeleteRequest.
+type EvalBatchDeleteResponse struct {
+	// Count is the number of evaluations that were deleted.
+	Count int
+	WriteMeta
+}
+
 // EvalSpecificRequest is used when we just need to specify a target evaluation
 type EvalSpecificRequest struct {
 	EvalID         string
@@ -960,6 +1016,10 @@ type AllocStopRequest struct {
 	AllocID         string
 	NoShutdownDelay bool
 
+	// Unpin allows the replacement of the allocation to be placed on any
+	// node, even if its task group uses sticky_host.
+	Unpin bool
+
 	WriteRequest
 }
 
@@ -1444,6 +1504,20 @@ type JobVersionsResponse struct {
 	QueryMeta
 }
 
+// JobSubmissionRequest is used to get the source a job version was
+// submitted with
+type JobSubmissionRequest struct {
+	JobID   string
+	Version uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse is used for a job get submission request
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
+
 // JobPlanResponse is used to respond to a job plan request
 type JobPlanResponse struct {
 	// Annotations stores annotations explaining decisions the scheduler made.
@@ -1740,6 +1814,165 @@ type DrainSpec struct {
 	// IgnoreSystemJobs allows systems jobs to remain on the node even though it
 	// has been marked for draining.
 	IgnoreSystemJobs bool
+
+	// JobOrder is the list of job IDs whose allocations are migrated off the
+	// node first, in order. The allocations of a job are only migrated once
+	// those of the jobs before it have left the node.
+	JobOrder []string
+
+	// OrderByPriority migrates the allocations of higher priority jobs before
+	// those of lower priority jobs. It orders the jobs not listed in JobOrder.
+	OrderByPriority bool
+
+	// MaxParallelMigrations caps the number of allocations that may be
+	// migrating across the whole cluster at once while the node drains. An
+	// allocation is migrating from the time it is marked for migration until
+	// its replacement is healthy. Zero means no limit.
+	MaxParallelMigrations int
+
+	// Window restricts the start of the drain to a maintenance window.
+	Window *DrainWindow
+}
+
+// Validate returns an error if the drain specification is invalid.
+func (d *DrainSpec) Validate() error {
+	var mErr multierror.Error
+	if d.MaxParallelMigrations < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("max parallel migrations must not be negative"))
+	}
+	for _, jobID := range d.JobOrder {
+		if jobID == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("job order must not contain an empty job ID"))
+			break
+		}
+	}
+	if d.Window != nil {
+		if err := d.Window.Validate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("invalid drain window: %v", err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (d *DrainSpec) Copy() *DrainSpec {
+	if d == nil {
+		return nil
+	}
+
+	nd := new(DrainSpec)
+	*nd = *d
+	nd.JobOrder = helper.CopySliceString(d.JobOrder)
+	nd.Window = d.Window.Copy()
+	return nd
+}
+
+func (d *DrainSpec) jobOrderEqual(order []string) bool {
+	if len(d.JobOrder) != len(order) {
+		return false
+	}
+	for i, jobID := range d.JobOrder {
+		if order[i] != jobID {
+			return false
+		}
+	}
+	return true
+}
+
+// Ordered returns whether the drain migrates the allocations of jobs in a
+// specific order.
+func (d *DrainSpec) Ordered() bool {
+	return len(d.JobOrder) > 0 || d.OrderByPriority
+}
+
+// JobRank returns the position of the job in the drain order. Allocations of
+// jobs with a lower rank are migrated first.
+func (d *DrainSpec) JobRank(job *Job) int {
+	rank := len(d.JobOrder)
+	for i, jobID := range d.JobOrder {
+		if jobID == job.ID {
+			rank = i
+			break
+		}
+	}
+
+	// Job priorities are bounded by JobMaxPriority, so order the jobs that
+	// are not listed within the same rank by their priority.
+	rank *= JobMaxPriority + 1
+	if d.OrderByPriority {
+		rank += JobMaxPriority - job.Priority
+	}
+	return rank
+}
+
+// DrainWindow is a recurring maintenance window in which a drain may start.
+type DrainWindow struct {
+	// Cron is the cron expression for the start of each window.
+	Cron string
+
+	// Duration is how long each window lasts.
+	Duration time.Duration
+
+	// TimeZone is the time zone the cron expression is evaluated in. It
+	// defaults to UTC.
+	TimeZone string
+}
+
+func (w *DrainWindow) Copy() *DrainWindow {
+	if w == nil {
+		return nil
+	}
+	nw := new(DrainWindow)
+	*nw = *w
+	return nw
+}
+
+func (w *DrainWindow) Equal(o *DrainWindow) bool {
+	if w == nil || o == nil {
+		return w == o
+	}
+	return *w == *o
+}
+
+// Validate returns an error if the drain window is invalid.
+func (w *DrainWindow) Validate() error {
+	var mErr multierror.Error
+	if _, err := cronexpr.Parse(w.Cron); err != nil {
+		_ = multierror.Append(&mErr, fmt.Errorf("invalid cron spec %q: %v", w.Cron, err))
+	}
+	if w.Duration <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("duration must be positive"))
+	}
+	if _, err := time.LoadLocation(w.TimeZone); err != nil {
+		_ = multierror.Append(&mErr, fmt.Errorf("invalid time zone %q: %v", w.TimeZone, err))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Next returns the earliest time at or after the given time that falls within
+// the window.
+func (w *DrainWindow) Next(from time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	e, err := cronexpr.Parse(w.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// The window open at the given time, if any, started within the last
+	// duration.
+	start, err := CronParseNext(e, from.In(loc).Add(-w.Duration), w.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if start.IsZero() {
+		return time.Time{}, fmt.Errorf("cron spec %q has no upcoming window", w.Cron)
+	}
+	if !start.After(from) {
+		return from, nil
+	}
+	return start.UTC(), nil
 }
 
 // DrainStrategy describes a Node's drain behavior.
@@ -1753,6 +1986,11 @@ type DrainStrategy struct {
 
 	// StartedAt is the time the drain process started
 	StartedAt time.Time
+
+	// WindowStart is the start of the drain window the drain is waiting for.
+	// Allocations are not migrated before it. It is only set when the drain
+	// specifies a window.
+	WindowStart time.Time
 }
 
 func (d *DrainStrategy) Copy() *DrainStrategy {
@@ -1762,9 +2000,17 @@ func (d *DrainStrategy) Copy() *DrainStrategy {
 
 	nd := new(DrainStrategy)
 	*nd = *d
+	nd.JobOrder = helper.CopySliceString(d.JobOrder)
+	nd.Window = d.Window.Copy()
 	return nd
 }
 
+// WindowOpen returns whether the drain may migrate allocations at the given
+// time, which is only false while the drain waits for its window to start.
+func (d *DrainStrategy) WindowOpen(now time.Time) bool {
+	return d == nil || !now.Before(d.WindowStart)
+}
+
 // DeadlineTime returns a boolean whether the drain strategy allows an infinite
 // duration or otherwise the deadline time. The force drain is captured by the
 // deadline time being in the past.
@@ -1803,6 +2049,16 @@ func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
 		return false
 	} else if d.IgnoreSystemJobs != o.IgnoreSystemJobs {
 		return false
+	} else if !d.jobOrderEqual(o.JobOrder) {
+		return false
+	} else if d.OrderByPriority != o.OrderByPriority {
+		return false
+	} else if d.MaxParallelMigrations != o.MaxParallelMigrations {
+		return false
+	} else if !d.Window.Equal(o.Window) {
+		return false
+	} else if d.WindowStart != o.WindowStart {
+		return false
 	}
 
 	return true
@@ -1914,6 +2170,10 @@ type Node struct {
 	// together for the purpose of determining scheduling pressure.
 	NodeClass string
 
+	// NodePool is the node pool the node belongs to. Jobs are only placed on
+	// nodes within the node pool they target.
+	NodePool string
+
 	// ComputedClass is a unique id that identifies nodes with a common set of
 	// attributes and capabilities.
 	ComputedClass string
@@ -1936,6 +2196,11 @@ type Node struct {
 	// updated
 	StatusUpdatedAt int64
 
+	// AgentStartID identifies the current run of the client agent. It is
+	// regenerated each time the agent starts, unlike StatusUpdatedAt which
+	// changes whenever the node registers an update.
+	AgentStartID string
+
 	// Events is the most recent set of events generated for the node,
 	// retaining only MaxRetainedNodeEvents number at a time
 	Events []*NodeEvent
@@ -2003,6 +2268,11 @@ func (n *Node) Canonicalize() {
 		n.SchedulingEligibility = NodeSchedulingEligible
 	}
 
+	// Nodes that do not declare a node pool belong to the default one.
+	if n.NodePool == "" {
+		n.NodePool = NodePoolDefault
+	}
+
 	// COMPAT remove in 1.0
 	// In v0.12.0 we introduced a separate node specific network resource struct
 	// so we need to covert any pre 0.12 clients to the correct struct
@@ -2207,6 +2477,7 @@ func (n *Node) Stub(fields *NodeStubFields) *NodeListStub {
 		Datacenter:            n.Datacenter,
 		Name:                  n.Name,
 		NodeClass:             n.NodeClass,
+		NodePool:              n.NodePool,
 		Version:               n.Attributes["nomad.version"],
 		Drain:                 n.DrainStrategy != nil,
 		SchedulingEligibility: n.SchedulingEligibility,
@@ -2245,6 +2516,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
@@ -4110,6 +4382,16 @@ type Job struct {
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
+	// NodePool is the node pool the job is allowed to place allocations on.
+	// An empty value is resolved to the namespace default, or the default
+	// node pool, when the job is registered.
+	NodePool string
+
+	// SchedulerAlgorithm overrides the scheduling algorithm used to place the
+	// allocations of the job. An empty value falls back to the namespace,
+	// node pool, or cluster configuration.
+	SchedulerAlgorithm SchedulerAlgorithm
+
 	// Constraints can be specified at a job level and apply to
 	// all the task groups and tasks.
 	Constraints []*Constraint
@@ -4189,6 +4471,11 @@ type Job struct {
 	// update stanza.
 	Stable bool
 
+	// EvalsPaused marks that the evaluations of the job are held by the eval
+	// broker rather than being handed to the schedulers. It is set via the
+	// evaluation pause APIs and persists across job registrations.
+	EvalsPaused bool
+
 	// Version is a monotonically increasing version number that is incremented
 	// on each job register.
 	Version uint64
@@ -4341,6 +4628,12 @@ func (j *Job) Validate() error {
 			}
 		}
 	}
+	if j.NodePool != "" && !validNodePoolName.MatchString(j.NodePool) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid node pool %q", j.NodePool))
+	}
+	if err := j.SchedulerAlgorithm.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
 	if len(j.TaskGroups) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job task groups"))
 	}
@@ -4490,6 +4783,25 @@ func (j *Job) LookupTaskGroup(name string) *TaskGroup {
 	return nil
 }
 
+// Actions returns the actions defined by the tasks of the job.
+func (j *Job) Actions() []*JobAction {
+	var actions []*JobAction
+	for _, tg := range j.TaskGroups {
+		for _, task := range tg.Tasks {
+			for _, a := range task.Actions {
+				actions = append(actions, &JobAction{
+					TaskGroup: tg.Name,
+					Task:      task.Name,
+					Name:      a.Name,
+					Command:   a.Command,
+					Args:      helper.CopySliceString(a.Args),
+				})
+			}
+		}
+	}
+	return actions
+}
+
 // CombinedTaskMeta takes a TaskGroup and Task name and returns the combined
 // meta data for the task. When joining Job, Group and Task Meta, the precedence
 // is by deepest scope (Task > Group > Job).
@@ -4551,6 +4863,7 @@ func (j *Job) Stub(summary *JobSummary) *JobListStub {
 		ParentID:          j.ParentID,
 		Name:              j.Name,
 		Datacenters:       j.Datacenters,
+		NodePool:          j.NodePool,
 		Multiregion:       j.Multiregion,
 		Type:              j.Type,
 		Priority:          j.Priority,
@@ -4735,6 +5048,7 @@ type JobListStub struct {
 	Name              string
 	Namespace         string `json:",omitempty"`
 	Datacenters       []string
+	NodePool          string
 	Multiregion       *Multiregion
 	Type              string
 	Priority          int
@@ -4750,6 +5064,74 @@ type JobListStub struct {
 	SubmitTime        int64
 }
 
+// JobSubmission is the original source a job version was submitted with,
+// along with the variables used to render it. It is stored for each tracked
+// version of a job so that the templates that produced a job can be audited.
+type JobSubmission struct {
+	// Source is the original job definition, as read by the CLI.
+	Source string
+
+	// Format is the format of Source, one of JobSubmissionFormatHCL1,
+	// JobSubmissionFormatHCL2 or JobSubmissionFormatJSON.
+	Format string
+
+	// VariableFlags are the HCL2 variables given as -var flags.
+	VariableFlags map[string]string
+
+	// Variables is the content of the HCL2 variable files given as -var-file
+	// flags.
+	Variables string
+
+	// VariableEnvs are the HCL2 variables set by NOMAD_VAR_ environment
+	// variables.
+	VariableEnvs map[string]string
+
+	// Files are the contents of the files read by the HCL2 file function,
+	// keyed by the path they were read with.
+	Files map[string]string
+
+	// Namespace, JobID, Version and JobModifyIndex identify the job version
+	// the submission belongs to. They are set by the server.
+	Namespace      string
+	JobID          string
+	Version        uint64
+	JobModifyIndex uint64
+}
+
+const (
+	JobSubmissionFormatHCL1 = "hcl1"
+	JobSubmissionFormatHCL2 = "hcl2"
+	JobSubmissionFormatJSON = "json"
+)
+
+// Size returns the number of bytes used by the submitted source, variables
+// and files, which is checked against the server's job_max_source_size.
+func (js *JobSubmission) Size() int {
+	if js == nil {
+		return 0
+	}
+	size := len(js.Source) + len(js.Variables)
+	for _, m := range []map[string]string{js.VariableFlags, js.VariableEnvs, js.Files} {
+		for k, v := range m {
+			size += len(k) + len(v)
+		}
+	}
+	return size
+}
+
+// Copy returns a deep copy of the submission.
+func (js *JobSubmission) Copy() *JobSubmission {
+	if js == nil {
+		return nil
+	}
+	c := new(JobSubmission)
+	*c = *js
+	c.VariableFlags = helper.CopyMapStringString(js.VariableFlags)
+	c.VariableEnvs = helper.CopyMapStringString(js.VariableEnvs)
+	c.Files = helper.CopyMapStringString(js.Files)
+	return c
+}
+
 // JobSummary summarizes the state of the allocations of a job
 type JobSummary struct {
 	// JobID is the ID of the job the summary is for
@@ -4787,6 +5169,10 @@ type JobChildrenSummary struct {
 	Pending int64
 	Running int64
 	Dead    int64
+
+	// Skipped is the number of periodic launches that were skipped because
+	// they fell within one of the exclusions of the periodic config.
+	Skipped int64
 }
 
 // Copy returns a new copy of a JobChildrenSummary
@@ -5026,6 +5412,14 @@ type Namespace struct {
 	// Capabilities is the set of capabilities allowed for this namespace
 	Capabilities *NamespaceCapabilities
 
+	// NodePoolConfiguration is the namespace configuration for handling node
+	// pools.
+	NodePoolConfiguration *NamespaceNodePoolConfiguration
+
+	// SchedulerConfiguration is the scheduler configuration applied to the
+	// jobs of the namespace.
+	SchedulerConfiguration *NamespaceSchedulerConfiguration
+
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
@@ -5045,6 +5439,119 @@ type NamespaceCapabilities struct {
 	DisabledTaskDrivers []string
 }
 
+// NamespaceNodePoolConfiguration stores configuration about node pools for a
+// namespace.
+type NamespaceNodePoolConfiguration struct {
+	// Default is the node pool used by jobs in this namespace that don't
+	// specify a node pool of their own.
+	Default string
+
+	// Allowed specifies the node pools that are allowed to be used by jobs in
+	// this namespace. By default, all node pools are allowed. If an empty
+	// list is provided only the namespace's default node pool is allowed.
+	// This field supports wildcard globbing through the use of `*` for
+	// multi-character matching. This field cannot be used with Denied.
+	Allowed []string
+
+	// Denied specifies the node pools that are not allowed to be used by
+	// jobs in this namespace. This field supports wildcard globbing through
+	// the use of `*` for multi-character matching. If specified, any node
+	// pool is allowed to be used, except for those that match any of these
+	// patterns. This field cannot be used with Allowed.
+	Denied []string
+}
+
+// Validate returns an error if the namespace node pool configuration is
+// invalid.
+func (n *NamespaceNodePoolConfiguration) Validate() error {
+	if n == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if n.Default != "" && !validNodePoolName.MatchString(n.Default) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid default node pool %q", n.Default))
+	}
+	if n.Allowed != nil && len(n.Denied) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("only one of allowed or denied node pools may be set"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// IsAllowed returns true if jobs in the namespace may use the given node
+// pool. The namespace default node pool is always allowed.
+func (n *NamespaceNodePoolConfiguration) IsAllowed(pool string) bool {
+	if n == nil {
+		return true
+	}
+
+	defaultPool := n.Default
+	if defaultPool == "" {
+		defaultPool = NodePoolDefault
+	}
+	if pool == defaultPool {
+		return true
+	}
+
+	if n.Allowed != nil {
+		for _, allowed := range n.Allowed {
+			if glob.Glob(allowed, pool) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, denied := range n.Denied {
+		if glob.Glob(denied, pool) {
+			return false
+		}
+	}
+	return true
+}
+
+// Copy returns a deep copy of the namespace node pool configuration.
+func (n *NamespaceNodePoolConfiguration) Copy() *NamespaceNodePoolConfiguration {
+	if n == nil {
+		return nil
+	}
+
+	nc := new(NamespaceNodePoolConfiguration)
+	*nc = *n
+	nc.Allowed = helper.CopySliceString(n.Allowed)
+	nc.Denied = helper.CopySliceString(n.Denied)
+	return nc
+}
+
+// NamespaceSchedulerConfiguration is the scheduler configuration applied to
+// the jobs of a namespace. Unset values fall back to the node pool or
+// cluster-wide configuration.
+type NamespaceSchedulerConfiguration struct {
+	// SchedulerAlgorithm is the scheduling algorithm to use for the jobs of
+	// the namespace that don't set their own.
+	SchedulerAlgorithm SchedulerAlgorithm
+}
+
+// Validate returns an error if the namespace scheduler configuration is
+// invalid.
+func (n *NamespaceSchedulerConfiguration) Validate() error {
+	if n == nil {
+		return nil
+	}
+	return n.SchedulerAlgorithm.Validate()
+}
+
+// Copy returns a copy of the namespace scheduler configuration.
+func (n *NamespaceSchedulerConfiguration) Copy() *NamespaceSchedulerConfiguration {
+	if n == nil {
+		return nil
+	}
+
+	nc := new(NamespaceSchedulerConfiguration)
+	*nc = *n
+	return nc
+}
+
 func (n *Namespace) Validate() error {
 	var mErr multierror.Error
 
@@ -5057,6 +5564,12 @@ func (n *Namespace) Validate() error {
 		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
 		mErr.Errors = append(mErr.Errors, err)
 	}
+	if err := n.NodePoolConfiguration.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid node pool configuration: %v", err))
+	}
+	if err := n.SchedulerConfiguration.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid scheduler configuration: %v", err))
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -5081,6 +5594,18 @@ func (n *Namespace) SetHash() []byte {
 			_, _ = hash.Write([]byte(driver))
 		}
 	}
+	if n.NodePoolConfiguration != nil {
+		_, _ = hash.Write([]byte(n.NodePoolConfiguration.Default))
+		for _, pool := range n.NodePoolConfiguration.Allowed {
+			_, _ = hash.Write([]byte(pool))
+		}
+		for _, pool := range n.NodePoolConfiguration.Denied {
+			_, _ = hash.Write([]byte(pool))
+		}
+	}
+	if n.SchedulerConfiguration != nil {
+		_, _ = hash.Write([]byte(n.SchedulerConfiguration.SchedulerAlgorithm))
+	}
 
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
@@ -5113,6 +5638,8 @@ func (n *Namespace) Copy() *Namespace {
 		c.DisabledTaskDrivers = helper.CopySliceString(n.Capabilities.DisabledTaskDrivers)
 		nc.Capabilities = c
 	}
+	nc.NodePoolConfiguration = n.NodePoolConfiguration.Copy()
+	nc.SchedulerConfiguration = n.SchedulerConfiguration.Copy()
 	if n.Meta != nil {
 		nc.Meta = make(map[string]string, len(n.Meta))
 		for k, v := range n.Meta {
@@ -5177,6 +5704,10 @@ const (
 	// PeriodicSpecTest is only used by unit tests. It is a sorted, comma
 	// separated list of unix timestamps at which to launch.
 	PeriodicSpecTest = "_internal_test"
+
+	// PeriodicExcludeDateFormat is the format of the dates excluded from
+	// periodic launches.
+	PeriodicExcludeDateFormat = "2006-01-02"
 )
 
 // Periodic defines the interval a job should be run at.
@@ -5188,9 +5719,23 @@ type PeriodicConfig struct {
 	// on the SpecType.
 	Spec string
 
+	// Specs specifies multiple intervals the job should be run as. The job is
+	// launched at the earliest time matched by any of them. It is parsed based
+	// on the SpecType and is mutually exclusive with Spec.
+	Specs []string
+
 	// SpecType defines the format of the spec.
 	SpecType string
 
+	// ExcludeDates is a list of calendar dates, formatted as YYYY-MM-DD and
+	// evaluated in the periodic time zone, during which launches are skipped.
+	ExcludeDates []string
+
+	// ExcludeCrons is a list of cron expressions describing windows during
+	// which launches are skipped. A launch is skipped if the minute it falls
+	// in matches any of the expressions.
+	ExcludeCrons []string
+
 	// ProhibitOverlap enforces that spawned jobs do not run in parallel.
 	ProhibitOverlap bool
 
@@ -5211,6 +5756,9 @@ func (p *PeriodicConfig) Copy() *PeriodicConfig {
 	}
 	np := new(PeriodicConfig)
 	*np = *p
+	np.Specs = helper.CopySliceString(p.Specs)
+	np.ExcludeDates = helper.CopySliceString(p.ExcludeDates)
+	np.ExcludeCrons = helper.CopySliceString(p.ExcludeCrons)
 	return np
 }
 
@@ -5220,9 +5768,12 @@ func (p *PeriodicConfig) Validate() error {
 	}
 
 	var mErr multierror.Error
-	if p.Spec == "" {
+	if p.Spec == "" && len(p.Specs) == 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Must specify a spec"))
 	}
+	if p.Spec != "" && len(p.Specs) > 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Only one of cron or crons may be specified"))
+	}
 
 	// Check if we got a valid time zone
 	if p.TimeZone != "" {
@@ -5233,9 +5784,11 @@ func (p *PeriodicConfig) Validate() error {
 
 	switch p.SpecType {
 	case PeriodicSpecCron:
-		// Validate the cron spec
-		if _, err := cronexpr.Parse(p.Spec); err != nil {
-			_ = multierror.Append(&mErr, fmt.Errorf("Invalid cron spec %q: %v", p.Spec, err))
+		// Validate the cron specs
+		for _, spec := range p.specs() {
+			if _, err := cronexpr.Parse(spec); err != nil {
+				_ = multierror.Append(&mErr, fmt.Errorf("Invalid cron spec %q: %v", spec, err))
+			}
 		}
 	case PeriodicSpecTest:
 		// No-op
@@ -5243,9 +5796,29 @@ func (p *PeriodicConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Unknown periodic specification type %q", p.SpecType))
 	}
 
+	// Validate the exclusions
+	for _, date := range p.ExcludeDates {
+		if _, err := time.Parse(PeriodicExcludeDateFormat, date); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Invalid exclude date %q: must be formatted as YYYY-MM-DD", date))
+		}
+	}
+	for _, spec := range p.ExcludeCrons {
+		if _, err := cronexpr.Parse(spec); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Invalid exclude cron %q: %v", spec, err))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
+// specs returns all the specs of the periodic config.
+func (p *PeriodicConfig) specs() []string {
+	if p.Spec != "" {
+		return append([]string{p.Spec}, p.Specs...)
+	}
+	return p.Specs
+}
+
 func (p *PeriodicConfig) Canonicalize() {
 	// Load the location
 	l, err := time.LoadLocation(p.TimeZone)
@@ -5273,16 +5846,34 @@ func CronParseNext(e *cronexpr.Expression, fromTime time.Time, spec string) (t t
 // passed time. If no matching instance exists, the zero value of time.Time is
 // returned. The `time.Location` of the returned value matches that of the
 // passed time.
+//
+// When multiple specs are set, the earliest of their next times is returned.
 func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
+	var next time.Time
+	for _, spec := range p.specs() {
+		t, err := p.nextForSpec(spec, fromTime)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next, nil
+}
+
+// nextForSpec returns the closest time instant matching the given spec that
+// is after the passed time.
+func (p *PeriodicConfig) nextForSpec(spec string, fromTime time.Time) (time.Time, error) {
 	switch p.SpecType {
 	case PeriodicSpecCron:
-		e, err := cronexpr.Parse(p.Spec)
+		e, err := cronexpr.Parse(spec)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("failed parsing cron expression: %q: %v", p.Spec, err)
+			return time.Time{}, fmt.Errorf("failed parsing cron expression: %q: %v", spec, err)
 		}
-		return CronParseNext(e, fromTime, p.Spec)
+		return CronParseNext(e, fromTime, spec)
 	case PeriodicSpecTest:
-		split := strings.Split(p.Spec, ",")
+		split := strings.Split(spec, ",")
 		if len(split) == 1 && split[0] == "" {
 			return time.Time{}, nil
 		}
@@ -5309,6 +5900,42 @@ func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	return time.Time{}, nil
 }
 
+// IsExcluded returns whether the given launch time falls within one of the
+// exclusions of the periodic config, in which case the launch is skipped.
+func (p *PeriodicConfig) IsExcluded(launchTime time.Time) bool {
+	if len(p.ExcludeDates) == 0 && len(p.ExcludeCrons) == 0 {
+		return false
+	}
+
+	launchTime = launchTime.In(p.GetLocation())
+
+	date := launchTime.Format(PeriodicExcludeDateFormat)
+	for _, excluded := range p.ExcludeDates {
+		if date == excluded {
+			return true
+		}
+	}
+
+	// A cron window matches if the expression has an instant within the
+	// minute of the launch.
+	minute := launchTime.Truncate(time.Minute)
+	for _, spec := range p.ExcludeCrons {
+		e, err := cronexpr.Parse(spec)
+		if err != nil {
+			continue
+		}
+		next, err := CronParseNext(e, minute.Add(-time.Second), spec)
+		if err != nil || next.IsZero() {
+			continue
+		}
+		if next.Before(minute.Add(time.Minute)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetLocation returns the location to use for determining the time zone to run
 // the periodic job against.
 func (p *PeriodicConfig) GetLocation() *time.Location {
@@ -5337,6 +5964,14 @@ type PeriodicLaunch struct {
 	ModifyIndex uint64
 }
 
+// PeriodicLaunchSkipRequest is used to record a periodic launch that was
+// skipped because it fell within one of the exclusions of the periodic job.
+type PeriodicLaunchSkipRequest struct {
+	JobID  string
+	Launch time.Time
+	WriteRequest
+}
+
 const (
 	DispatchPayloadForbidden = "forbidden"
 	DispatchPayloadOptional  = "optional"
@@ -6189,6 +6824,16 @@ type TaskGroup struct {
 	// MaxClientDisconnect, if set, configures the client to allow placed
 	// allocations for tasks in this group to attempt to resume running without a restart.
 	MaxClientDisconnect *time.Duration
+
+	// StickyHost pins the replacement of an allocation to the node of the
+	// allocation it replaces. If that node is not feasible the replacement is
+	// not placed elsewhere, unless the previous allocation was unpinned.
+	StickyHost bool
+
+	// SchedulerAlgorithm overrides the scheduling algorithm used to place the
+	// allocations of the group. An empty value falls back to the job
+	// configuration.
+	SchedulerAlgorithm SchedulerAlgorithm
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6320,6 +6965,21 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect cannot be negative"))
 	}
 
+	if tg.StickyHost {
+		switch j.Type {
+		case JobTypeService, JobTypeBatch:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Job type %q does not allow sticky_host", j.Type))
+		}
+		if tg.Update != nil && tg.Update.Canary > 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("sticky_host cannot be used with canary deployments"))
+		}
+	}
+
+	if err := tg.SchedulerAlgorithm.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
 	for idx, constr := range tg.Constraints {
 		if err := constr.Validate(); err != nil {
 			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
@@ -6851,6 +7511,10 @@ const (
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// Sinks are the external destinations the task logs are shipped to, in
+	// addition to the local rotated files.
+	Sinks []*LogSink
 }
 
 func (l *LogConfig) Equals(o *LogConfig) bool {
@@ -6866,6 +7530,15 @@ func (l *LogConfig) Equals(o *LogConfig) bool {
 		return false
 	}
 
+	if len(l.Sinks) != len(o.Sinks) {
+		return false
+	}
+	for i, sink := range l.Sinks {
+		if !sink.Equals(o.Sinks[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -6873,10 +7546,17 @@ func (l *LogConfig) Copy() *LogConfig {
 	if l == nil {
 		return nil
 	}
-	return &LogConfig{
+	nl := &LogConfig{
 		MaxFiles:      l.MaxFiles,
 		MaxFileSizeMB: l.MaxFileSizeMB,
 	}
+	if l.Sinks != nil {
+		nl.Sinks = make([]*LogSink, len(l.Sinks))
+		for i, sink := range l.Sinks {
+			nl.Sinks[i] = sink.Copy()
+		}
+	}
+	return nl
 }
 
 // DefaultLogConfig returns the default LogConfig values.
@@ -6897,6 +7577,139 @@ func (l *LogConfig) Validate() error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	for i, sink := range l.Sinks {
+		if err := sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("Sink %d:", i+1)))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// LogSinkTypeSyslog ships the task logs as RFC5424 syslog messages over
+	// TCP, UDP or a unix socket.
+	LogSinkTypeSyslog = "syslog"
+
+	// LogSinkTypeFluent ships the task logs to a fluent forward endpoint,
+	// such as a local Fluentd or Fluent Bit agent.
+	LogSinkTypeFluent = "fluent"
+
+	// LogSinkTypeHTTP ships batches of task logs as JSON to an HTTP endpoint.
+	LogSinkTypeHTTP = "http"
+)
+
+const (
+	// DefaultLogSinkBatchSize is the default maximum number of log lines
+	// shipped in a single batch.
+	DefaultLogSinkBatchSize = 100
+
+	// DefaultLogSinkBatchWait is the default maximum time a log line waits
+	// for its batch to fill before it is shipped.
+	DefaultLogSinkBatchWait = 1 * time.Second
+
+	// DefaultLogSinkBufferSize is the default number of log lines buffered
+	// while a sink is unavailable, beyond which new lines are dropped.
+	DefaultLogSinkBufferSize = 10000
+)
+
+// LogSink is an external destination that the lines of the task logs are
+// shipped to, tagged with the allocation, task and stream they come from.
+type LogSink struct {
+	// Type is the type of the sink: syslog, fluent or http.
+	Type string
+
+	// Address is the address of the sink. Syslog sinks use the tcp://, udp://
+	// or unix:// schemes, fluent sinks use a host:port or unix:// address
+	// and HTTP sinks use an http:// or https:// URL.
+	Address string
+
+	// Tag is the syslog app name or the fluent tag of the shipped lines.
+	Tag string
+
+	// Headers are the headers of the requests sent to HTTP sinks.
+	Headers map[string]string
+
+	// BatchSize is the maximum number of lines shipped at once to fluent and
+	// HTTP sinks.
+	BatchSize int
+
+	// BatchWait is the maximum time a line waits for its batch to fill.
+	BatchWait time.Duration
+
+	// BufferSize is the number of lines buffered while the sink is slow or
+	// unavailable. Lines are dropped once the buffer is full, so a sink never
+	// blocks the task or the local log files.
+	BufferSize int
+}
+
+func (s *LogSink) Equals(o *LogSink) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.Type == o.Type &&
+		s.Address == o.Address &&
+		s.Tag == o.Tag &&
+		helper.CompareMapStringString(s.Headers, o.Headers) &&
+		s.BatchSize == o.BatchSize &&
+		s.BatchWait == o.BatchWait &&
+		s.BufferSize == o.BufferSize
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := new(LogSink)
+	*ns = *s
+	ns.Headers = helper.CopyMapStringString(s.Headers)
+	return ns
+}
+
+// Validate returns an error if the log sink is invalid.
+func (s *LogSink) Validate() error {
+	var mErr multierror.Error
+
+	if s.Address == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing address"))
+	}
+
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		if s.Address != "" {
+			u, err := url.Parse(s.Address)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address %q: %v", s.Address, err))
+			} else if u.Scheme != "tcp" && u.Scheme != "udp" && u.Scheme != "unix" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("syslog address scheme must be one of tcp, udp or unix; got %q", u.Scheme))
+			}
+		}
+	case LogSinkTypeFluent:
+	case LogSinkTypeHTTP:
+		if s.Address != "" {
+			u, err := url.Parse(s.Address)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address %q: %v", s.Address, err))
+			} else if u.Scheme != "http" && u.Scheme != "https" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("http address scheme must be one of http or https; got %q", u.Scheme))
+			}
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("type must be one of %q, %q or %q; got %q",
+			LogSinkTypeSyslog, LogSinkTypeFluent, LogSinkTypeHTTP, s.Type))
+	}
+
+	if len(s.Headers) > 0 && s.Type != LogSinkTypeHTTP {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("headers are only supported by %q sinks", LogSinkTypeHTTP))
+	}
+	if s.BatchSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("batch size must not be negative; got %d", s.BatchSize))
+	}
+	if s.BatchWait < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("batch wait must not be negative; got %v", s.BatchWait))
+	}
+	if s.BufferSize < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("buffer size must not be negative; got %d", s.BufferSize))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -6987,6 +7800,9 @@ type Task struct {
 
 	// CSIPluginConfig is used to configure the plugin supervisor for the task.
 	CSIPluginConfig *TaskCSIPluginConfig
+
+	// Actions are the named commands operators can run in the task.
+	Actions []*Action
 }
 
 // UsesConnect is for conveniently detecting if the Task is able to make use
@@ -7051,9 +7867,28 @@ func (t *Task) Copy() *Task {
 		nt.Templates = templates
 	}
 
+	if t.Actions != nil {
+		actions := make([]*Action, len(t.Actions))
+		for i, a := range nt.Actions {
+			actions[i] = a.Copy()
+		}
+		nt.Actions = actions
+	}
+
 	return nt
 }
 
+// LookupAction returns the action with the given name, or nil if the task
+// doesn't define it.
+func (t *Task) LookupAction(name string) *Action {
+	for _, a := range t.Actions {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
 // Canonicalize canonicalizes fields in the task.
 func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 	// Ensure that an empty and nil map are treated the same to avoid scheduling
@@ -7206,6 +8041,21 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 		}
 	}
 
+	actions := make(map[string]int, len(t.Actions))
+	for idx, action := range t.Actions {
+		if err := action.Validate(); err != nil {
+			outer := fmt.Errorf("Action %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+
+		if other, ok := actions[action.Name]; ok {
+			outer := fmt.Errorf("Action %d has same name as %d", idx+1, other)
+			mErr.Errors = append(mErr.Errors, outer)
+		} else {
+			actions[action.Name] = idx + 1
+		}
+	}
+
 	// Validate the dispatch payload block if there
 	if t.DispatchPayload != nil {
 		if err := t.DispatchPayload.Validate(); err != nil {
@@ -8564,6 +9414,46 @@ func (ta *TaskArtifact) validateChecksum() error {
 	return nil
 }
 
+// Action is a named command defined on a task, which operators can run in
+// the task's running allocations without knowing the command line.
+type Action struct {
+	Name    string
+	Command string
+	Args    []string
+}
+
+func (a *Action) Copy() *Action {
+	if a == nil {
+		return nil
+	}
+	na := new(Action)
+	*na = *a
+	na.Args = helper.CopySliceString(a.Args)
+	return na
+}
+
+func (a *Action) Validate() error {
+	var mErr multierror.Error
+	if a.Name == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing action name"))
+	} else if strings.ContainsAny(a.Name, "/\\ \000") {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Action name %q cannot include slashes, spaces or null characters", a.Name))
+	}
+	if a.Command == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing action command"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// JobAction is an action of a task of a job.
+type JobAction struct {
+	TaskGroup string
+	Task      string
+	Name      string
+	Command   string
+	Args      []string
+}
+
 const (
 	ConstraintDistinctProperty  = "distinct_property"
 	ConstraintDistinctHosts     = "distinct_hosts"
@@ -8788,10 +9678,25 @@ type Spread struct {
 	// Attribute is the node attribute used as the spread criteria
 	Attribute string
 
+	// Attributes are the node attributes of a compound spread, evaluated
+	// hierarchically: allocations are spread across the values of the first
+	// attribute, then across the values of each following attribute within
+	// the values of the previous ones. The targets of a compound spread are
+	// paths of values separated by SpreadTargetSeparator, like "dc1/rack1",
+	// whose percentage is relative to their parent. Attributes and Attribute
+	// are mutually exclusive.
+	Attributes []string
+
 	// Weight is the relative weight of this spread, useful when there are multiple
 	// spread and affinities
 	Weight int8
 
+	// MaxSkew makes a placement infeasible once the number of allocations
+	// with the attribute value of the node would exceed the number of
+	// allocations of the least used value by more than MaxSkew. It can't be
+	// used with SpreadTarget.
+	MaxSkew int
+
 	// SpreadTarget is used to describe desired percentages for each attribute value
 	SpreadTarget []*SpreadTarget
 
@@ -8831,6 +9736,7 @@ func (s *Spread) Copy() *Spread {
 	ns := new(Spread)
 	*ns = *s
 
+	ns.Attributes = helper.CopySliceString(s.Attributes)
 	ns.SpreadTarget = CopySliceSpreadTarget(s.SpreadTarget)
 	return ns
 }
@@ -8839,36 +9745,161 @@ func (s *Spread) String() string {
 	if s.str != "" {
 		return s.str
 	}
-	s.str = fmt.Sprintf("%s %s %v", s.Attribute, s.SpreadTarget, s.Weight)
+	s.str = fmt.Sprintf("%s %s %v", s.Key(), s.SpreadTarget, s.Weight)
+	if s.MaxSkew > 0 {
+		s.str += fmt.Sprintf(" max_skew=%d", s.MaxSkew)
+	}
 	return s.str
 }
 
+// SpreadTargetSeparator separates the values of the targets of compound
+// spreads, like "dc1/rack1". Values containing the separator or a backslash
+// escape them with a backslash, like "us\/east/rack1".
+const SpreadTargetSeparator = "/"
+
+// spreadValueEscaper escapes the separator and backslashes within the values
+// of the targets of compound spreads.
+var spreadValueEscaper = strings.NewReplacer(`\`, `\\`, SpreadTargetSeparator, `\`+SpreadTargetSeparator)
+
+// JoinSpreadValues returns the path of the values of a target of a compound
+// spread, escaping the values.
+func JoinSpreadValues(values []string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = spreadValueEscaper.Replace(value)
+	}
+	return strings.Join(escaped, SpreadTargetSeparator)
+}
+
+// SplitSpreadValues returns the unescaped values of the path of a target of a
+// compound spread.
+func SplitSpreadValues(path string) []string {
+	var values []string
+	var value strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path):
+			i++
+			value.WriteByte(path[i])
+		case strings.HasPrefix(path[i:], SpreadTargetSeparator):
+			values = append(values, value.String())
+			value.Reset()
+		default:
+			value.WriteByte(path[i])
+		}
+	}
+	return append(values, value.String())
+}
+
+// IsCompound returns whether the spread is over several attributes.
+func (s *Spread) IsCompound() bool {
+	return len(s.Attributes) > 0
+}
+
+// SpreadAttributes returns the node attributes of the spread, which is the
+// single Attribute unless the spread is compound.
+func (s *Spread) SpreadAttributes() []string {
+	if s.IsCompound() {
+		return s.Attributes
+	}
+	return []string{s.Attribute}
+}
+
+// TargetValues returns the values of the path of a target of the spread. Only
+// the targets of compound spreads are paths of several values.
+func (s *Spread) TargetValues(target string) []string {
+	if !s.IsCompound() {
+		return []string{target}
+	}
+	return SplitSpreadValues(target)
+}
+
+// Key returns a string identifying the attributes of the spread.
+func (s *Spread) Key() string {
+	return strings.Join(s.SpreadAttributes(), SpreadTargetSeparator)
+}
+
 func (s *Spread) Validate() error {
 	var mErr multierror.Error
-	if s.Attribute == "" {
+	if s.Attribute == "" && !s.IsCompound() {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing spread attribute"))
 	}
+	if s.Attribute != "" && s.IsCompound() {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza can't set both attribute and attributes"))
+	}
+	seenAttrs := make(map[string]struct{})
+	for _, attr := range s.Attributes {
+		if attr == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("Spread attributes can't be empty"))
+			continue
+		}
+		if _, ok := seenAttrs[attr]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread attribute %q already defined", attr))
+		}
+		seenAttrs[attr] = struct{}{}
+	}
 	if s.Weight <= 0 || s.Weight > 100 {
 		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza must have a positive weight from 0 to 100"))
 	}
+	if s.MaxSkew < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread max_skew can't be negative"))
+	}
+	if s.MaxSkew > 0 && len(s.SpreadTarget) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread max_skew can't be used with targets"))
+	}
 	seen := make(map[string]struct{})
-	sumPercent := uint32(0)
+
+	// The percentages of the targets of compound spreads are relative to
+	// their parent, so they are summed by parent
+	sumPercent := make(map[string]uint32)
 
 	for _, target := range s.SpreadTarget {
+		values := s.TargetValues(target.Value)
+		path := JoinSpreadValues(values)
+
 		// Make sure there are no duplicates
-		_, ok := seen[target.Value]
+		_, ok := seen[path]
 		if !ok {
-			seen[target.Value] = struct{}{}
+			seen[path] = struct{}{}
 		} else {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread target value %q already defined", target.Value))
 		}
 		if target.Percent > 100 {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread target percentage for value %q must be between 0 and 100", target.Value))
 		}
-		sumPercent += uint32(target.Percent)
+
+		parent := ""
+		if s.IsCompound() {
+			if len(values) > len(s.Attributes) {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread target value %q has more values than attributes", target.Value))
+			}
+			for _, value := range values {
+				if value == "" {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread target value %q has an empty value", target.Value))
+					break
+				}
+			}
+			parent = JoinSpreadValues(values[:len(values)-1])
+		}
+		sumPercent[parent] += uint32(target.Percent)
+	}
+	for parent, sum := range sumPercent {
+		if sum <= 100 {
+			continue
+		}
+		if parent == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Sum of spread target percentages must not be greater than 100%%; got %d%%", sum))
+		} else {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Sum of spread target percentages under %q must not be greater than 100%%; got %d%%", parent, sum))
+		}
 	}
-	if sumPercent > 100 {
-		mErr.Errors = append(mErr.Errors, fmt.Errorf("Sum of spread target percentages must not be greater than 100%%; got %d%%", sumPercent))
+
+	// Targets are evaluated hierarchically, so a nested target is only
+	// meaningful if its parent is a target too
+	for parent := range sumPercent {
+		if _, ok := seen[parent]; parent != "" && !ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Spread target %q must be defined for its nested targets", parent))
+		}
 	}
 	return mErr.ErrorOrNil()
 }
@@ -9398,6 +10429,11 @@ type DesiredTransition struct {
 	// task shutdown_delay configuration and ignore the delay for any
 	// allocations stopped as a result of this Deregister call.
 	NoShutdownDelay *bool
+
+	// Unpin is used to indicate that the replacement of this allocation may
+	// be placed on any node, even if its task group pins allocations to the
+	// node of the allocation they replace.
+	Unpin *bool
 }
 
 // Merge merges the two desired transitions, preferring the values from the
@@ -9418,6 +10454,10 @@ func (d *DesiredTransition) Merge(o *DesiredTransition) {
 	if o.NoShutdownDelay != nil {
 		d.NoShutdownDelay = o.NoShutdownDelay
 	}
+
+	if o.Unpin != nil {
+		d.Unpin = o.Unpin
+	}
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -9449,6 +10489,15 @@ func (d *DesiredTransition) ShouldIgnoreShutdownDelay() bool {
 	return d.NoShutdownDelay != nil && *d.NoShutdownDelay
 }
 
+// ShouldUnpin returns whether the transition object dictates that the
+// replacement of the allocation is not pinned to its node.
+func (d *DesiredTransition) ShouldUnpin() bool {
+	if d == nil {
+		return false
+	}
+	return d.Unpin != nil && *d.Unpin
+}
+
 const (
 	AllocDesiredStatusRun   = "run"   // Allocation should run
 	AllocDesiredStatusStop  = "stop"  // Allocation should stop
@@ -9575,6 +10624,14 @@ type Allocation struct {
 	// to stop running because it got preempted
 	PreemptedByAllocation string
 
+	// SignedIdentities maps the name of each task to the workload identity
+	// signed for it by the leader. It is omitted from the HTTP API as the
+	// identities are secrets.
+	SignedIdentities map[string]string `json:"-"`
+
+	// SigningKeyID is the ID of the root key used to sign the identities.
+	SigningKeyID string
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -9718,6 +10775,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 
 	na.RescheduleTracker = a.RescheduleTracker.Copy()
 	na.PreemptedAllocations = helper.CopySliceString(a.PreemptedAllocations)
+	na.SignedIdentities = helper.CopyMapStringString(a.SignedIdentities)
 	return na
 }
 
@@ -10727,6 +11785,16 @@ const (
 	// tokens. We periodically scan for expired tokens and delete them.
 	CoreJobOneTimeTokenGC = "one-time-token-gc"
 
+	// CoreJobLocalTokenExpiredGC is used for the garbage collection of
+	// expired local ACL tokens. We periodically scan for expired tokens and
+	// delete them.
+	CoreJobLocalTokenExpiredGC = "local-token-expired-gc"
+
+	// CoreJobGlobalTokenExpiredGC is used for the garbage collection of
+	// expired global ACL tokens. We periodically scan for expired tokens and
+	// delete them. This only runs within the authoritative region.
+	CoreJobGlobalTokenExpiredGC = "global-token-expired-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -11429,6 +12497,10 @@ type PlanAnnotations struct {
 
 	// PreemptedAllocs is the set of allocations to be preempted to make the placement successful.
 	PreemptedAllocs []*AllocListStub
+
+	// SchedulerAlgorithms is the scheduling algorithm used to place the
+	// allocations of each task group.
+	SchedulerAlgorithms map[string]SchedulerAlgorithm
 }
 
 // DesiredUpdates is the set of changes the scheduler would like to make given
@@ -11707,14 +12779,30 @@ type ACLPolicyUpsertRequest struct {
 
 // ACLToken represents a client token which is used to Authenticate
 type ACLToken struct {
-	AccessorID  string   // Public Accessor ID (UUID)
-	SecretID    string   // Secret ID, private (UUID)
-	Name        string   // Human friendly name
-	Type        string   // Client or Management
-	Policies    []string // Policies this token ties to
-	Global      bool     // Global or Region local
-	Hash        []byte
-	CreateTime  time.Time // Time of creation
+	AccessorID string   // Public Accessor ID (UUID)
+	SecretID   string   // Secret ID, private (UUID)
+	Name       string   // Human friendly name
+	Type       string   // Client or Management
+	Policies   []string // Policies this token ties to
+
+	// Roles represents the ACL roles that this token is tied to. The token
+	// will inherit the permissions of all policies detailed within the role.
+	Roles []*ACLTokenRoleLink
+
+	Global     bool // Global or Region local
+	Hash       []byte
+	CreateTime time.Time // Time of creation
+
+	// ExpirationTime represents the point after which a token should be
+	// considered revoked and is eligible for destruction. A nil value
+	// indicates the token does not expire.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is a convenience field for helping set ExpirationTime to
+	// a value of CreateTime+ExpirationTTL. This can only be set during token
+	// creation. This is a string version of a time.Duration like "2m".
+	ExpirationTTL time.Duration
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -11742,12 +12830,60 @@ func (a *ACLToken) Copy() *ACLToken {
 
 	c.Policies = make([]string, len(a.Policies))
 	copy(c.Policies, a.Policies)
+
+	if a.Roles != nil {
+		c.Roles = make([]*ACLTokenRoleLink, len(a.Roles))
+		for i, roleLink := range a.Roles {
+			link := *roleLink
+			c.Roles[i] = &link
+		}
+	}
+
 	c.Hash = make([]byte, len(a.Hash))
 	copy(c.Hash, a.Hash)
 
+	if a.ExpirationTime != nil {
+		expirationTime := *a.ExpirationTime
+		c.ExpirationTime = &expirationTime
+	}
+
 	return c
 }
 
+// IsExpired compares the token's expiration time to the passed time and
+// returns whether the token has expired. A token without an expiration time
+// never expires.
+func (a *ACLToken) IsExpired(t time.Time) bool {
+	if a == nil || a.ExpirationTime == nil {
+		return false
+	}
+	return a.ExpirationTime.Before(t)
+}
+
+// HasExpirationTime checks whether the ACL token has an expiration time
+// value set.
+func (a *ACLToken) HasExpirationTime() bool {
+	if a == nil || a.ExpirationTime == nil {
+		return false
+	}
+	return !a.ExpirationTime.IsZero()
+}
+
+// Canonicalize performs basic canonicalization on a new ACL token. The
+// accessor and secret IDs are generated, the create time is set, and the
+// expiration time is computed from the expiration TTL if one was supplied.
+// It must only be called on new tokens.
+func (a *ACLToken) Canonicalize() {
+	a.AccessorID = uuid.Generate()
+	a.SecretID = uuid.Generate()
+	a.CreateTime = time.Now().UTC()
+
+	if a.ExpirationTTL != 0 {
+		expirationTime := a.CreateTime.Add(a.ExpirationTTL)
+		a.ExpirationTime = &expirationTime
+	}
+}
+
 var (
 	// AnonymousACLToken is used no SecretID is provided, and the
 	// request is made anonymously.
@@ -11761,15 +12897,17 @@ var (
 )
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	Hash        []byte
-	CreateTime  time.Time
-	CreateIndex uint64
-	ModifyIndex uint64
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []*ACLTokenRoleLink
+	Global         bool
+	Hash           []byte
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
 }
 
 // SetHash is used to compute and set the hash of the ACL token
@@ -11786,6 +12924,9 @@ func (a *ACLToken) SetHash() []byte {
 	for _, policyName := range a.Policies {
 		_, _ = hash.Write([]byte(policyName))
 	}
+	for _, roleLink := range a.Roles {
+		_, _ = hash.Write([]byte(roleLink.ID))
+	}
 	if a.Global {
 		_, _ = hash.Write([]byte("global"))
 	} else {
@@ -11802,36 +12943,75 @@ func (a *ACLToken) SetHash() []byte {
 
 func (a *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:  a.AccessorID,
-		Name:        a.Name,
-		Type:        a.Type,
-		Policies:    a.Policies,
-		Global:      a.Global,
-		Hash:        a.Hash,
-		CreateTime:  a.CreateTime,
-		CreateIndex: a.CreateIndex,
-		ModifyIndex: a.ModifyIndex,
-	}
-}
-
-// Validate is used to check a token for reasonableness
-func (a *ACLToken) Validate() error {
+		AccessorID:     a.AccessorID,
+		Name:           a.Name,
+		Type:           a.Type,
+		Policies:       a.Policies,
+		Roles:          a.Roles,
+		Global:         a.Global,
+		Hash:           a.Hash,
+		CreateTime:     a.CreateTime,
+		ExpirationTime: a.ExpirationTime,
+		CreateIndex:    a.CreateIndex,
+		ModifyIndex:    a.ModifyIndex,
+	}
+}
+
+// Validate is used to check a token for reasonableness. The minimum and
+// maximum TTL values bound the expiration of new tokens. When updating a
+// token, existing should be the token as currently held in state; the
+// expiration of a token cannot be modified once it has been created.
+func (a *ACLToken) Validate(minTTL, maxTTL time.Duration, existing *ACLToken) error {
 	var mErr multierror.Error
 	if len(a.Name) > maxTokenNameLength {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token name too long"))
 	}
 	switch a.Type {
 	case ACLClientToken:
-		if len(a.Policies) == 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies"))
+		if len(a.Policies) == 0 && len(a.Roles) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies or roles"))
 		}
 	case ACLManagementToken:
 		if len(a.Policies) != 0 {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies"))
 		}
+		if len(a.Roles) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with roles"))
+		}
 	default:
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token type must be client or management"))
 	}
+
+	// The expiration of a token is immutable, so only validate the bounds
+	// when creating a token.
+	if existing == nil {
+		if a.ExpirationTTL < 0 {
+			mErr.Errors = append(mErr.Errors,
+				fmt.Errorf("token expiration TTL '%s' should not be negative", a.ExpirationTTL))
+		}
+
+		if a.ExpirationTime != nil && !a.ExpirationTime.IsZero() {
+			if a.CreateTime.After(*a.ExpirationTime) {
+				mErr.Errors = append(mErr.Errors, errors.New("expiration time cannot be before create time"))
+			}
+
+			// Check the expiration time is within the allowed bounds.
+			expiresIn := a.ExpirationTime.Sub(a.CreateTime)
+			if expiresIn < minTTL || expiresIn > maxTTL {
+				mErr.Errors = append(mErr.Errors,
+					fmt.Errorf("expiration time cannot be less than %s or more than %s in the future", minTTL, maxTTL))
+			}
+		}
+	} else {
+		if a.ExpirationTTL != 0 && a.ExpirationTTL != existing.ExpirationTTL {
+			mErr.Errors = append(mErr.Errors, errors.New("cannot update expiration TTL"))
+		}
+		if a.ExpirationTime != nil && !a.ExpirationTime.IsZero() &&
+			(existing.ExpirationTime == nil || !a.ExpirationTime.Equal(*existing.ExpirationTime)) {
+			mErr.Errors = append(mErr.Errors, errors.New("cannot update expiration time"))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
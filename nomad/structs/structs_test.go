@@ -98,6 +98,20 @@ func TestJob_Validate(t *testing.T) {
 	}
 	err = j.Validate()
 	require.Error(t, err, "datacenter must be non-empty string")
+
+	// test for invalid scheduler algorithms
+	j = testJob()
+	j.SchedulerAlgorithm = "random"
+	j.TaskGroups[0].SchedulerAlgorithm = "other"
+	err = j.Validate()
+	requireErrors(t, err,
+		`invalid scheduler algorithm "random"`,
+		`invalid scheduler algorithm "other"`,
+	)
+
+	j.SchedulerAlgorithm = SchedulerAlgorithmSpread
+	j.TaskGroups[0].SchedulerAlgorithm = SchedulerAlgorithmBinpack
+	require.NoError(t, j.Validate())
 }
 
 func TestJob_ValidateScaling(t *testing.T) {
@@ -1605,6 +1619,65 @@ func TestTask_Validate(t *testing.T) {
 	)
 }
 
+func TestTask_Validate_Actions(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &Task{
+		Name:   "web",
+		Driver: "docker",
+		Resources: &Resources{
+			CPU:      100,
+			MemoryMB: 100,
+		},
+		LogConfig: DefaultLogConfig(),
+		Actions: []*Action{
+			{Name: "flush-cache", Command: "redis-cli", Args: []string{"FLUSHALL"}},
+			{Name: "rotate-logs", Command: "logrotate"},
+		},
+	}
+	ephemeralDisk := DefaultEphemeralDisk()
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil, nil))
+
+	task.Actions = append(task.Actions,
+		&Action{Command: "true"},
+		&Action{Name: "flush cache", Command: "true"},
+		&Action{Name: "reload"},
+		&Action{Name: "flush-cache", Command: "memcflush"},
+	)
+	err := task.Validate(ephemeralDisk, JobTypeService, nil, nil)
+	requireErrors(t, err,
+		"Missing action name",
+		"cannot include slashes, spaces or null characters",
+		"Missing action command",
+		"Action 6 has same name as 1",
+	)
+}
+
+func TestJob_Actions(t *testing.T) {
+	ci.Parallel(t)
+
+	job := MockJob()
+	job.TaskGroups[0].Tasks[0].Actions = []*Action{
+		{Name: "flush-cache", Command: "redis-cli", Args: []string{"FLUSHALL"}},
+	}
+	tg := job.TaskGroups[0].Copy()
+	tg.Name = "cache"
+	tg.Tasks[0].Actions = append(tg.Tasks[0].Actions, &Action{Name: "rotate-logs", Command: "logrotate"})
+	job.TaskGroups = append(job.TaskGroups, tg)
+
+	require.Equal(t, []*JobAction{
+		{TaskGroup: "web", Task: "web", Name: "flush-cache", Command: "redis-cli", Args: []string{"FLUSHALL"}},
+		{TaskGroup: "cache", Task: "web", Name: "flush-cache", Command: "redis-cli", Args: []string{"FLUSHALL"}},
+		{TaskGroup: "cache", Task: "web", Name: "rotate-logs", Command: "logrotate"},
+	}, job.Actions())
+
+	// Actions are copied with their task
+	require.Equal(t, job.TaskGroups[1].Tasks[0].Actions, job.TaskGroups[1].Tasks[0].Copy().Actions)
+	require.Equal(t, tg.Tasks[0], tg.Tasks[0].Copy())
+	require.Equal(t, "rotate-logs", tg.Tasks[0].LookupAction("rotate-logs").Name)
+	require.Nil(t, tg.Tasks[0].LookupAction("missing"))
+}
+
 func TestTask_Validate_Resources(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2479,6 +2552,13 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equals(b))
 	})
 
+	t.Run("sinks", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Type: LogSinkTypeSyslog, Address: "udp://127.0.0.1:514"}}}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Type: LogSinkTypeSyslog, Address: "udp://127.0.0.1:515"}}}
+		require.False(t, a.Equals(b))
+		require.True(t, a.Equals(a.Copy()))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -2486,6 +2566,73 @@ func TestLogConfig_Equals(t *testing.T) {
 	})
 }
 
+func TestLogConfig_Validate_Sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		sink *LogSink
+		err  string
+	}{
+		{
+			name: "syslog",
+			sink: &LogSink{Type: LogSinkTypeSyslog, Address: "tcp://127.0.0.1:514"},
+		},
+		{
+			name: "syslog bad scheme",
+			sink: &LogSink{Type: LogSinkTypeSyslog, Address: "http://127.0.0.1:514"},
+			err:  `syslog address scheme must be one of tcp, udp or unix; got "http"`,
+		},
+		{
+			name: "fluent",
+			sink: &LogSink{Type: LogSinkTypeFluent, Address: "127.0.0.1:24224"},
+		},
+		{
+			name: "http",
+			sink: &LogSink{Type: LogSinkTypeHTTP, Address: "https://logs.example.com", Headers: map[string]string{"X-Token": "secret"}},
+		},
+		{
+			name: "http bad scheme",
+			sink: &LogSink{Type: LogSinkTypeHTTP, Address: "tcp://logs.example.com"},
+			err:  `http address scheme must be one of http or https; got "tcp"`,
+		},
+		{
+			name: "headers",
+			sink: &LogSink{Type: LogSinkTypeFluent, Address: "127.0.0.1:24224", Headers: map[string]string{"X-Token": "secret"}},
+			err:  `headers are only supported by "http" sinks`,
+		},
+		{
+			name: "missing address",
+			sink: &LogSink{Type: LogSinkTypeFluent},
+			err:  "missing address",
+		},
+		{
+			name: "bad type",
+			sink: &LogSink{Type: "kafka", Address: "127.0.0.1:9092"},
+			err:  `type must be one of "syslog", "fluent" or "http"; got "kafka"`,
+		},
+		{
+			name: "negative buffer size",
+			sink: &LogSink{Type: LogSinkTypeFluent, Address: "127.0.0.1:24224", BufferSize: -1},
+			err:  "buffer size must not be negative",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultLogConfig()
+			config.Sinks = []*LogSink{tc.sink}
+			err := config.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
 func TestTask_Validate_CSIPluginConfig(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3739,6 +3886,109 @@ func TestPeriodicConfig_DST(t *testing.T) {
 	require.Equal(e2, n2.UTC())
 }
 
+func TestPeriodicConfig_MultipleSpecs(t *testing.T) {
+	ci.Parallel(t)
+
+	p := &PeriodicConfig{
+		Enabled:  true,
+		SpecType: PeriodicSpecCron,
+		Specs:    []string{"0 9 * * MON-FRI", "0 12 * * SAT,SUN"},
+	}
+	p.Canonicalize()
+	require.NoError(t, p.Validate())
+
+	// Friday evening launches on Saturday at noon
+	from := time.Date(2022, time.June, 17, 18, 0, 0, 0, time.UTC)
+	n, err := p.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, time.June, 18, 12, 0, 0, 0, time.UTC), n)
+
+	// Sunday evening launches on Monday morning
+	from = time.Date(2022, time.June, 19, 18, 0, 0, 0, time.UTC)
+	n, err = p.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, time.June, 20, 9, 0, 0, 0, time.UTC), n)
+
+	// Specifying both cron and crons is invalid
+	p.Spec = "@hourly"
+	require.ErrorContains(t, p.Validate(), "Only one of cron or crons")
+
+	// Every expression must be valid
+	p = &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Specs: []string{"@hourly", "foo"}}
+	p.Canonicalize()
+	require.ErrorContains(t, p.Validate(), "Invalid cron spec")
+}
+
+func TestPeriodicConfig_Exclusions_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	p := &PeriodicConfig{
+		Enabled:      true,
+		SpecType:     PeriodicSpecCron,
+		Spec:         "@daily",
+		ExcludeDates: []string{"2022-12-25"},
+		ExcludeCrons: []string{"* 0-6 * * *"},
+	}
+	p.Canonicalize()
+	require.NoError(t, p.Validate())
+
+	p.ExcludeDates = []string{"12/25/2022"}
+	require.ErrorContains(t, p.Validate(), "Invalid exclude date")
+
+	p.ExcludeDates = nil
+	p.ExcludeCrons = []string{"* *"}
+	require.ErrorContains(t, p.Validate(), "Invalid exclude cron")
+}
+
+func TestPeriodicConfig_IsExcluded(t *testing.T) {
+	ci.Parallel(t)
+
+	p := &PeriodicConfig{
+		Enabled:      true,
+		SpecType:     PeriodicSpecCron,
+		Spec:         "@hourly",
+		TimeZone:     "America/New_York",
+		ExcludeDates: []string{"2022-12-25"},
+		ExcludeCrons: []string{"* 0-6 * * *"},
+	}
+	p.Canonicalize()
+	require.NoError(t, p.Validate())
+
+	cases := []struct {
+		name     string
+		launch   time.Time
+		excluded bool
+	}{
+		{
+			name:     "excluded date",
+			launch:   time.Date(2022, time.December, 25, 12, 0, 0, 0, p.location),
+			excluded: true,
+		},
+		{
+			// Still the 24th in the periodic time zone
+			name:     "excluded date in other time zone",
+			launch:   time.Date(2022, time.December, 25, 1, 0, 0, 0, time.UTC),
+			excluded: false,
+		},
+		{
+			name:     "excluded cron window",
+			launch:   time.Date(2022, time.December, 20, 3, 0, 0, 0, p.location),
+			excluded: true,
+		},
+		{
+			name:     "outside cron window",
+			launch:   time.Date(2022, time.December, 20, 7, 0, 0, 0, p.location),
+			excluded: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.excluded, p.IsExcluded(c.launch))
+		})
+	}
+}
+
 func TestTaskLifecycleConfig_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5771,6 +6021,45 @@ func TestJobConfig_Validate_MaxClientDisconnect(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestJobConfig_Validate_StickyHost(t *testing.T) {
+	ci.Parallel(t)
+
+	// Sticky host cannot be used with canaries
+	job := testJob()
+	job.TaskGroups[0].StickyHost = true
+	job.TaskGroups[0].Update = DefaultUpdateStrategy.Copy()
+	job.TaskGroups[0].Update.Canary = 1
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "sticky_host cannot be used with canary deployments")
+
+	// Sticky host is valid for service jobs without canaries
+	job.TaskGroups[0].Update.Canary = 0
+	require.NoError(t, job.Validate())
+
+	// Sticky host cannot be used by system jobs
+	tg := job.TaskGroups[0].Copy()
+	tg.Update = nil
+	tg.ReschedulePolicy = nil
+	err = tg.Validate(&Job{Type: JobTypeSystem})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Job type "system" does not allow sticky_host`)
+}
+
+func TestDesiredTransition_ShouldUnpin(t *testing.T) {
+	ci.Parallel(t)
+
+	var d *DesiredTransition
+	require.False(t, d.ShouldUnpin())
+
+	d = &DesiredTransition{Migrate: helper.BoolToPtr(true)}
+	require.False(t, d.ShouldUnpin())
+
+	d.Merge(&DesiredTransition{Unpin: helper.BoolToPtr(true)})
+	require.True(t, d.ShouldUnpin())
+	require.True(t, d.ShouldMigrate())
+}
+
 func TestParameterizedJobConfig_Canonicalize(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6000,7 +6289,7 @@ func TestACLTokenValidate(t *testing.T) {
 	tk := &ACLToken{}
 
 	// Missing a type
-	err := tk.Validate()
+	err := tk.Validate(1*time.Minute, 24*time.Hour, nil)
 	assert.NotNil(t, err)
 	if !strings.Contains(err.Error(), "client or management") {
 		t.Fatalf("bad: %v", err)
@@ -6008,7 +6297,7 @@ func TestACLTokenValidate(t *testing.T) {
 
 	// Missing policies
 	tk.Type = ACLClientToken
-	err = tk.Validate()
+	err = tk.Validate(1*time.Minute, 24*time.Hour, nil)
 	assert.NotNil(t, err)
 	if !strings.Contains(err.Error(), "missing policies") {
 		t.Fatalf("bad: %v", err)
@@ -6017,7 +6306,7 @@ func TestACLTokenValidate(t *testing.T) {
 	// Invalid policies
 	tk.Type = ACLManagementToken
 	tk.Policies = []string{"foo"}
-	err = tk.Validate()
+	err = tk.Validate(1*time.Minute, 24*time.Hour, nil)
 	assert.NotNil(t, err)
 	if !strings.Contains(err.Error(), "associated with policies") {
 		t.Fatalf("bad: %v", err)
@@ -6029,7 +6318,7 @@ func TestACLTokenValidate(t *testing.T) {
 		tk.Name += uuid.Generate()
 	}
 	tk.Policies = nil
-	err = tk.Validate()
+	err = tk.Validate(1*time.Minute, 24*time.Hour, nil)
 	assert.NotNil(t, err)
 	if !strings.Contains(err.Error(), "too long") {
 		t.Fatalf("bad: %v", err)
@@ -6037,7 +6326,7 @@ func TestACLTokenValidate(t *testing.T) {
 
 	// Make it valid
 	tk.Name = "foo"
-	err = tk.Validate()
+	err = tk.Validate(1*time.Minute, 24*time.Hour, nil)
 	assert.Nil(t, err)
 }
 
@@ -6347,6 +6636,99 @@ func TestNode_Canonicalize(t *testing.T) {
 	require.Equal(NodeSchedulingIneligible, node.SchedulingEligibility)
 }
 
+func TestDrainWindow_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	w := &DrainWindow{Cron: "0 2 * * *", Duration: time.Hour, TimeZone: "Europe/Berlin"}
+	require.NoError(t, w.Validate())
+
+	w = &DrainWindow{Cron: "not a cron", TimeZone: "Nowhere/Nothing"}
+	err := w.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid cron spec")
+	require.Contains(t, err.Error(), "duration must be positive")
+	require.Contains(t, err.Error(), "invalid time zone")
+
+	spec := &DrainSpec{
+		JobOrder:              []string{"web", ""},
+		MaxParallelMigrations: -1,
+		Window:                w,
+	}
+	err = spec.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "max parallel migrations")
+	require.Contains(t, err.Error(), "empty job ID")
+	require.Contains(t, err.Error(), "invalid drain window")
+}
+
+func TestDrainWindow_Next(t *testing.T) {
+	ci.Parallel(t)
+
+	w := &DrainWindow{Cron: "0 2 * * *", Duration: 2 * time.Hour}
+
+	// Before the window opens
+	from := time.Date(2022, 6, 1, 1, 0, 0, 0, time.UTC)
+	next, err := w.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, 6, 1, 2, 0, 0, 0, time.UTC), next)
+
+	// Within the window
+	from = time.Date(2022, 6, 1, 3, 30, 0, 0, time.UTC)
+	next, err = w.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, from, next)
+
+	// After the window closed
+	from = time.Date(2022, 6, 1, 4, 0, 0, 0, time.UTC)
+	next, err = w.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, 6, 2, 2, 0, 0, 0, time.UTC), next)
+
+	// In another time zone
+	w.TimeZone = "America/New_York"
+	next, err = w.Next(from)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2022, 6, 1, 6, 0, 0, 0, time.UTC), next)
+}
+
+func TestDrainSpec_JobRank(t *testing.T) {
+	ci.Parallel(t)
+
+	first := &Job{ID: "first", Priority: 10}
+	high := &Job{ID: "high", Priority: 90}
+	low := &Job{ID: "low", Priority: 20}
+
+	spec := &DrainSpec{JobOrder: []string{"first"}}
+	require.Less(t, spec.JobRank(first), spec.JobRank(high))
+	require.Equal(t, spec.JobRank(high), spec.JobRank(low))
+
+	spec.OrderByPriority = true
+	require.Less(t, spec.JobRank(first), spec.JobRank(high))
+	require.Less(t, spec.JobRank(high), spec.JobRank(low))
+}
+
+func TestDrainStrategy_Equal(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &DrainStrategy{
+		DrainSpec: DrainSpec{
+			Deadline: time.Hour,
+			JobOrder: []string{"a", "b"},
+			Window:   &DrainWindow{Cron: "@daily", Duration: time.Hour},
+		},
+	}
+	o := d.Copy()
+	require.True(t, d.Equal(o))
+
+	o.JobOrder = []string{"b", "a"}
+	require.False(t, d.Equal(o))
+
+	o = d.Copy()
+	o.Window.Duration = 2 * time.Hour
+	require.False(t, d.Equal(o))
+	require.Equal(t, time.Hour, d.Window.Duration)
+}
+
 func TestNode_Copy(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -6612,6 +6994,150 @@ func TestSpread_Validate(t *testing.T) {
 			err:  nil,
 			name: "Valid spread",
 		},
+		{
+			spread: &Spread{
+				Attribute:  "${node.datacenter}",
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     50,
+			},
+			err:  fmt.Errorf("Spread stanza can't set both attribute and attributes"),
+			name: "Attribute and attributes",
+		},
+		{
+			spread: &Spread{
+				Attributes: []string{"${node.datacenter}", "${node.datacenter}"},
+				Weight:     50,
+			},
+			err:  fmt.Errorf("Spread attribute \"${node.datacenter}\" already defined"),
+			name: "Duplicate attributes",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${meta.rack}",
+				Weight:    50,
+				MaxSkew:   -1,
+			},
+			err:  fmt.Errorf("Spread max_skew can't be negative"),
+			name: "Negative max skew",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${meta.rack}",
+				Weight:    50,
+				MaxSkew:   1,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "r1",
+						Percent: 50,
+					},
+				},
+			},
+			err:  fmt.Errorf("Spread max_skew can't be used with targets"),
+			name: "Max skew with targets",
+		},
+		{
+			spread: &Spread{
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     50,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "dc1",
+						Percent: 50,
+					},
+					{
+						Value:   "dc1/r1",
+						Percent: 60,
+					},
+					{
+						Value:   "dc1/r2",
+						Percent: 60,
+					},
+				},
+			},
+			err:  fmt.Errorf("Sum of spread target percentages under \"dc1\" must not be greater than 100%%; got %d%%", 120),
+			name: "Invalid nested percentages",
+		},
+		{
+			spread: &Spread{
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     50,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "dc1/r1",
+						Percent: 50,
+					},
+				},
+			},
+			err:  fmt.Errorf("Spread target \"dc1\" must be defined for its nested targets"),
+			name: "Missing parent target",
+		},
+		{
+			spread: &Spread{
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     50,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "dc1/r1/a",
+						Percent: 50,
+					},
+				},
+			},
+			err:  fmt.Errorf("Spread target value \"dc1/r1/a\" has more values than attributes"),
+			name: "Target deeper than attributes",
+		},
+		{
+			spread: &Spread{
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     50,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "dc1",
+						Percent: 70,
+					},
+					{
+						Value:   "dc2",
+						Percent: 30,
+					},
+					{
+						Value:   "dc1/r1",
+						Percent: 60,
+					},
+					{
+						Value:   "dc1/r2",
+						Percent: 40,
+					},
+				},
+			},
+			err:  nil,
+			name: "Valid compound spread",
+		},
+		{
+			spread: &Spread{
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				Weight:     50,
+				MaxSkew:    1,
+			},
+			err:  nil,
+			name: "Valid compound spread with max skew",
+		},
+		{
+			spread: &Spread{
+				Attributes: []string{"${meta.zone}", "${meta.rack}"},
+				Weight:     50,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   `us\/east`,
+						Percent: 50,
+					},
+					{
+						Value:   `us\/east/r\/1`,
+						Percent: 50,
+					},
+				},
+			},
+			err:  nil,
+			name: "Valid compound spread with escaped separators",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -6627,6 +7153,30 @@ func TestSpread_Validate(t *testing.T) {
 	}
 }
 
+func TestSpreadValues(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		values []string
+		path   string
+	}{
+		{[]string{"dc1"}, "dc1"},
+		{[]string{"dc1", "r1"}, "dc1/r1"},
+		{[]string{"us/east", "r1"}, `us\/east/r1`},
+		{[]string{`a\`, "b"}, `a\\/b`},
+		{[]string{"", "r1"}, "/r1"},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.path, JoinSpreadValues(c.values))
+		require.Equal(t, c.values, SplitSpreadValues(c.path))
+	}
+
+	spread := &Spread{Attribute: "${meta.zone}"}
+	require.Equal(t, []string{"us/east"}, spread.TargetValues("us/east"))
+	spread = &Spread{Attributes: []string{"${meta.zone}", "${meta.rack}"}}
+	require.Equal(t, []string{"us/east", "r1"}, spread.TargetValues(`us\/east/r1`))
+}
+
 func TestNodeReservedNetworkResources_ParseReserved(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7111,3 +7661,20 @@ func requireErrors(t *testing.T, err error, expected ...string) {
 
 	require.Equal(t, expected, found)
 }
+
+func TestJobSubmission_Size(t *testing.T) {
+	ci.Parallel(t)
+
+	var sub *JobSubmission
+	require.Zero(t, sub.Size())
+
+	// The files and environment variables count against the size limit
+	sub = &JobSubmission{
+		Source:        "12345",
+		Variables:     "123",
+		VariableFlags: map[string]string{"a": "1"},
+		VariableEnvs:  map[string]string{"b": "22"},
+		Files:         map[string]string{"c.txt": "4444"},
+	}
+	require.Equal(t, 5+3+2+3+9, sub.Size())
+}
@@ -0,0 +1,380 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// VariablesApplyRPCMethod is the RPC method for upserting or deleting a
+	// variable.
+	//
+	// Args: VariablesApplyRequest
+	// Reply: VariablesApplyResponse
+	VariablesApplyRPCMethod = "Variables.Apply"
+
+	// VariablesListRPCMethod is the RPC method for listing the metadata of
+	// variables within Nomad.
+	//
+	// Args: VariablesListRequest
+	// Reply: VariablesListResponse
+	VariablesListRPCMethod = "Variables.List"
+
+	// VariablesReadRPCMethod is the RPC method for reading a single variable,
+	// including its decrypted items.
+	//
+	// Args: VariablesReadRequest
+	// Reply: VariablesReadResponse
+	VariablesReadRPCMethod = "Variables.Read"
+
+	// maxVariableSize is the maximum size, in bytes, of the unencrypted items
+	// of a single variable.
+	maxVariableSize = 65536
+
+	// VariablesJobsPathPrefix is the path prefix under which variables are
+	// implicitly accessible to the workloads of the matching job.
+	VariablesJobsPathPrefix = "nomad/jobs"
+
+	// variablesReservedPathPrefix is the path prefix reserved for use by
+	// Nomad. Only paths under VariablesJobsPathPrefix may be written within
+	// it.
+	variablesReservedPathPrefix = "nomad/"
+)
+
+var (
+	// validVariablePath is used to validate a variable path. Periods are not
+	// allowed, so that a path and item key can be unambiguously joined.
+	validVariablePath = regexp.MustCompile("^[a-zA-Z0-9-_~/]{1,128}$")
+
+	// ErrVariableNotFound is returned when a variable cannot be found at the
+	// requested path.
+	ErrVariableNotFound = errors.New("variable not found")
+)
+
+// VariableMetadata is the metadata envelope for a variable. It is shared by
+// both the encrypted and decrypted representations and is the object
+// returned when listing variables.
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateIndex uint64
+	CreateTime  int64
+	ModifyIndex uint64
+	ModifyTime  int64
+}
+
+// VariableEncrypted is the representation of a variable stored within state.
+// The items are encrypted using the server keyring and are only decrypted by
+// the servers when they are read.
+type VariableEncrypted struct {
+	VariableMetadata
+	VariableData
+}
+
+// VariableData is the encrypted payload of a variable.
+type VariableData struct {
+
+	// Data is the ciphertext of the msgpack encoded VariableItems, including
+	// the nonce.
+	Data []byte
+
+	// KeyID is the ID of the root key used to encrypt Data.
+	KeyID string
+}
+
+// VariableDecrypted is the representation of a variable as read and written
+// by API consumers.
+type VariableDecrypted struct {
+	VariableMetadata
+	Items VariableItems
+}
+
+// VariableItems are the key/value pairs stored within a variable.
+type VariableItems map[string]string
+
+// Size returns the combined length of the keys and values of the items.
+func (vi VariableItems) Size() uint64 {
+	var out uint64
+	for k, v := range vi {
+		out += uint64(len(k))
+		out += uint64(len(v))
+	}
+	return out
+}
+
+// Copy returns a deep copy of the metadata.
+func (vm *VariableMetadata) Copy() *VariableMetadata {
+	if vm == nil {
+		return nil
+	}
+	nvm := *vm
+	return &nvm
+}
+
+// GetNamespace returns the namespace of the variable, satisfying the
+// paginator.NamespaceGetter interface.
+func (vm *VariableMetadata) GetNamespace() string {
+	return vm.Namespace
+}
+
+// GetID returns the path of the variable, satisfying the paginator.IDGetter
+// interface.
+func (vm *VariableMetadata) GetID() string {
+	return vm.Path
+}
+
+// GetCreateIndex returns the create index of the variable, satisfying the
+// paginator.CreateIndexGetter interface.
+func (vm *VariableMetadata) GetCreateIndex() uint64 {
+	return vm.CreateIndex
+}
+
+// Copy returns a deep copy of the encrypted variable.
+func (ve *VariableEncrypted) Copy() *VariableEncrypted {
+	if ve == nil {
+		return nil
+	}
+	nve := *ve
+	if ve.Data != nil {
+		nve.Data = make([]byte, len(ve.Data))
+		copy(nve.Data, ve.Data)
+	}
+	return &nve
+}
+
+// Copy returns a deep copy of the decrypted variable.
+func (vd *VariableDecrypted) Copy() *VariableDecrypted {
+	if vd == nil {
+		return nil
+	}
+	nvd := *vd
+	if vd.Items != nil {
+		nvd.Items = make(VariableItems, len(vd.Items))
+		for k, v := range vd.Items {
+			nvd.Items[k] = v
+		}
+	}
+	return &nvd
+}
+
+// Canonicalize ensures the variable has a namespace set.
+func (vd *VariableDecrypted) Canonicalize() {
+	if vd.Namespace == "" {
+		vd.Namespace = DefaultNamespace
+	}
+}
+
+// Validate performs validation of the decrypted variable, ensuring the path
+// and items are usable.
+func (vd *VariableDecrypted) Validate() error {
+	var mErr multierror.Error
+
+	if err := ValidateVariablePath(vd.Path); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if len(vd.Items) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("variable missing items"))
+	}
+	for k := range vd.Items {
+		if k == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("variable item keys must not be empty"))
+			break
+		}
+	}
+	if size := vd.Items.Size(); size > maxVariableSize {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("variable items exceed maximum size of %d bytes", maxVariableSize))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ValidateVariablePath checks that the path is valid for a variable.
+func ValidateVariablePath(path string) error {
+	if !validVariablePath.MatchString(path) {
+		return fmt.Errorf("invalid path %q: must be between 1 and 128 characters"+
+			" and only contain alphanumerics and the characters -_~/", path)
+	}
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") || strings.Contains(path, "//") {
+		return fmt.Errorf("invalid path %q: must not contain empty segments", path)
+	}
+	if strings.HasPrefix(path+"/", variablesReservedPathPrefix) &&
+		!PathWithinVariablePrefix(path, VariablesJobsPathPrefix) {
+		return fmt.Errorf("invalid path %q: only paths under %q may be used within the reserved %q prefix",
+			path, VariablesJobsPathPrefix, variablesReservedPathPrefix)
+	}
+	return nil
+}
+
+// PathWithinVariablePrefix returns whether the path is equal to the prefix,
+// or nested beneath it.
+func PathWithinVariablePrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// VariablesJobPath returns the path under which the variables of the job are
+// implicitly accessible to its workloads.
+func VariablesJobPath(jobID string) string {
+	return VariablesJobsPathPrefix + "/" + jobID
+}
+
+// VariablesTaskPaths returns the paths of the variables which are implicitly
+// accessible to a task, in increasing order of specificity.
+func VariablesTaskPaths(jobID, group, task string) []string {
+	jobPath := VariablesJobPath(jobID)
+	return []string{
+		jobPath,
+		jobPath + "/" + group,
+		jobPath + "/" + group + "/" + task,
+	}
+}
+
+// VarOp is the operation being performed by a variables apply request.
+type VarOp string
+
+const (
+	VarOpSet       VarOp = "set"
+	VarOpDelete    VarOp = "delete"
+	VarOpDeleteCAS VarOp = "delete-cas"
+	VarOpCAS       VarOp = "cas"
+)
+
+// IsValid returns whether the operation is known.
+func (op VarOp) IsValid() bool {
+	switch op {
+	case VarOpSet, VarOpDelete, VarOpDeleteCAS, VarOpCAS:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsDelete returns whether the operation removes the variable.
+func (op VarOp) IsDelete() bool {
+	return op == VarOpDelete || op == VarOpDeleteCAS
+}
+
+// VarOpResult is the outcome of a variables apply request.
+type VarOpResult string
+
+const (
+	VarOpResultOk       VarOpResult = "ok"
+	VarOpResultConflict VarOpResult = "conflict"
+	VarOpResultRedacted VarOpResult = "conflict-redacted"
+)
+
+// VariablesApplyRequest is used to upsert or delete a variable.
+type VariablesApplyRequest struct {
+	Op  VarOp
+	Var *VariableDecrypted
+	WriteRequest
+}
+
+// VariablesApplyResponse is the response object when upserting or deleting
+// a variable. When a check-and-set operation fails, Conflict contains the
+// current variable, with its items redacted if the caller cannot read them.
+type VariablesApplyResponse struct {
+	Op       VarOp
+	Input    *VariableDecrypted
+	Result   VarOpResult
+	Conflict *VariableDecrypted
+	Output   *VariableDecrypted
+	WriteMeta
+}
+
+// IsConflict returns whether the apply operation failed due to a
+// check-and-set conflict.
+func (r *VariablesApplyResponse) IsConflict() bool {
+	return r.Result == VarOpResultConflict || r.Result == VarOpResultRedacted
+}
+
+// VarApplyStateRequest is the request applied via Raft to write an encrypted
+// variable to state.
+type VarApplyStateRequest struct {
+	Op  VarOp
+	Var *VariableEncrypted
+	WriteRequest
+}
+
+// VarApplyStateResponse is the FSM response of a VarApplyStateRequest.
+type VarApplyStateResponse struct {
+	Op       VarOp
+	Result   VarOpResult
+	Conflict *VariableEncrypted
+
+	// WrittenVarMeta is the metadata of the variable written to state and is
+	// nil when deleting.
+	WrittenVarMeta *VariableMetadata
+	Error          error
+	WriteMeta
+}
+
+// IsOk returns whether the operation was successfully applied.
+func (r *VarApplyStateResponse) IsOk() bool {
+	return r.Result == VarOpResultOk
+}
+
+// VariablesListRequest is used to list the metadata of variables. The path
+// prefix is passed via QueryOptions.Prefix.
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse is the response object when listing variables.
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesReadRequest is used to read a single variable.
+type VariablesReadRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesReadResponse is the response object when reading a variable. Data
+// is nil if the variable does not exist.
+type VariablesReadResponse struct {
+	Data *VariableDecrypted
+	QueryMeta
+}
+
+// ErrorResponse returns a response for the request which failed with the
+// passed error.
+func (r *VarApplyStateRequest) ErrorResponse(index uint64, err error) *VarApplyStateResponse {
+	return &VarApplyStateResponse{
+		Op:        r.Op,
+		Error:     err,
+		WriteMeta: WriteMeta{Index: index},
+	}
+}
+
+// ConflictResponse returns a response for the request which failed its
+// check-and-set index. The conflict is nil if the variable does not exist.
+func (r *VarApplyStateRequest) ConflictResponse(index uint64, conflict *VariableEncrypted) *VarApplyStateResponse {
+	resp := &VarApplyStateResponse{
+		Op:        r.Op,
+		Result:    VarOpResultConflict,
+		WriteMeta: WriteMeta{Index: index},
+	}
+	if conflict != nil {
+		resp.Conflict = conflict.Copy()
+	}
+	return resp
+}
+
+// SuccessResponse returns a response for the request which was successfully
+// applied.
+func (r *VarApplyStateRequest) SuccessResponse(index uint64, meta *VariableMetadata) *VarApplyStateResponse {
+	return &VarApplyStateResponse{
+		Op:             r.Op,
+		Result:         VarOpResultOk,
+		WrittenVarMeta: meta,
+		WriteMeta:      WriteMeta{Index: index},
+	}
+}
@@ -0,0 +1,88 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVariablePath(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		path   string
+		expErr string
+	}{
+		{path: "foo"},
+		{path: "foo/bar-baz_qux~1"},
+		{path: "nomad/jobs"},
+		{path: "nomad/jobs/example/web"},
+		{path: "nomads/foo"},
+		{path: "", expErr: "must be between 1 and 128 characters"},
+		{path: strings.Repeat("a", 129), expErr: "must be between 1 and 128 characters"},
+		{path: "foo.bar", expErr: "only contain alphanumerics"},
+		{path: "/foo", expErr: "must not contain empty segments"},
+		{path: "foo/", expErr: "must not contain empty segments"},
+		{path: "foo//bar", expErr: "must not contain empty segments"},
+		{path: "nomad", expErr: "reserved"},
+		{path: "nomad/foo", expErr: "reserved"},
+		{path: "nomad/jobsfoo", expErr: "reserved"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			err := ValidateVariablePath(tc.path)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expErr)
+			}
+		})
+	}
+}
+
+func TestVariableDecrypted_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	v := &VariableDecrypted{
+		VariableMetadata: VariableMetadata{Path: "foo"},
+		Items:            VariableItems{"key": "value"},
+	}
+	require.NoError(t, v.Validate())
+
+	v.Items = nil
+	require.ErrorContains(t, v.Validate(), "variable missing items")
+
+	v.Items = VariableItems{"": "value"}
+	require.ErrorContains(t, v.Validate(), "keys must not be empty")
+
+	v.Items = VariableItems{"key": strings.Repeat("a", maxVariableSize)}
+	require.ErrorContains(t, v.Validate(), "exceed maximum size")
+}
+
+func TestVariableDecrypted_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	v := &VariableDecrypted{
+		VariableMetadata: VariableMetadata{Namespace: "default", Path: "foo"},
+		Items:            VariableItems{"key": "value"},
+	}
+	c := v.Copy()
+	require.Equal(t, v, c)
+
+	c.Items["key"] = "changed"
+	require.Equal(t, "value", v.Items["key"])
+}
+
+func TestVariablesTaskPaths(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t, []string{
+		"nomad/jobs/example",
+		"nomad/jobs/example/web",
+		"nomad/jobs/example/web/server",
+	}, VariablesTaskPaths("example", "web", "server"))
+}
@@ -30,6 +30,10 @@ type ClientHostVolumeConfig struct {
 	Name     string `hcl:",key"`
 	Path     string `hcl:"path"`
 	ReadOnly bool   `hcl:"read_only"`
+
+	// ID is set only for dynamic host volumes created through the API, and
+	// is the ID of the HostVolume tracked by the servers
+	ID string `hcl:"-"`
 }
 
 func (p *ClientHostVolumeConfig) Copy() *ClientHostVolumeConfig {
@@ -0,0 +1,49 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// WorkloadIdentityAudience is the audience of the workload identities
+	// signed by the servers.
+	WorkloadIdentityAudience = "nomadproject.io"
+)
+
+// IdentityClaims are the claims of the workload identity signed by the
+// servers for each task of an allocation. The identity is valid for as long
+// as the allocation it was signed for is not terminal.
+type IdentityClaims struct {
+	Namespace    string `json:"nomad_namespace"`
+	JobID        string `json:"nomad_job_id"`
+	AllocationID string `json:"nomad_allocation_id"`
+	TaskGroup    string `json:"nomad_task_group"`
+	TaskName     string `json:"nomad_task"`
+
+	jwt.RegisteredClaims
+}
+
+// NewIdentityClaims returns the identity claims of the task within the
+// allocation.
+func NewIdentityClaims(job *Job, alloc *Allocation, taskName string, now time.Time) *IdentityClaims {
+	return &IdentityClaims{
+		Namespace:    alloc.Namespace,
+		JobID:        job.ID,
+		AllocationID: alloc.ID,
+		TaskGroup:    alloc.TaskGroup,
+		TaskName:     taskName,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       alloc.ID + ":" + taskName,
+			Subject:  WorkloadIdentitySubject(alloc.Namespace, job.ID, alloc.TaskGroup, taskName),
+			Audience: jwt.ClaimStrings{WorkloadIdentityAudience},
+			IssuedAt: jwt.NewNumericDate(now),
+		},
+	}
+}
+
+// WorkloadIdentitySubject returns the subject of the identity of a task.
+func WorkloadIdentitySubject(namespace, jobID, group, task string) string {
+	return namespace + ":" + jobID + ":" + group + ":" + task
+}
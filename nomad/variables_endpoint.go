@@ -0,0 +1,353 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/state/paginator"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables encapsulates the variables RPC endpoint which is callable via the
+// Variables RPCs and externally via the "/v1/var{s}" HTTP API.
+type Variables struct {
+	srv *Server
+}
+
+// Apply is used to upsert or delete a variable. The items of the variable are
+// encrypted by the server before being written to Raft.
+func (v *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.VariablesApplyResponse) error {
+	if done, err := v.srv.forward(structs.VariablesApplyRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "apply"}, time.Now())
+
+	if args.Var == nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing variable")
+	}
+	if !args.Op.IsValid() {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid variable operation %q", args.Op)
+	}
+
+	// The namespace of the request is authoritative, as this is the one the
+	// ACL check is performed against.
+	args.Var.Namespace = args.RequestNamespace()
+	args.Var.Canonicalize()
+
+	if args.Op.IsDelete() {
+		if err := structs.ValidateVariablePath(args.Var.Path); err != nil {
+			return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+		}
+	} else if err := args.Var.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil {
+		capability := acl.VariablesCapabilityWrite
+		if args.Op.IsDelete() {
+			capability = acl.VariablesCapabilityDestroy
+		}
+		if !aclObj.AllowVariableOperation(args.Var.Namespace, args.Var.Path, capability) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	encrypted, err := v.encrypt(args.Var)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixNano()
+	encrypted.CreateTime = now
+	encrypted.ModifyTime = now
+
+	req := &structs.VarApplyStateRequest{
+		Op:           args.Op,
+		Var:          encrypted,
+		WriteRequest: args.WriteRequest,
+	}
+
+	out, index, err := v.srv.raftApply(structs.VarApplyStateRequestType, req)
+	if err != nil {
+		return err
+	}
+
+	// Check if the FSM response, which is an interface, contains an error.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+	resp, ok := out.(*structs.VarApplyStateResponse)
+	if !ok {
+		return fmt.Errorf("unexpected variable apply response type %T", out)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	reply.Op = args.Op
+	reply.Input = args.Var.Copy()
+	reply.Result = resp.Result
+	reply.Index = index
+
+	switch {
+	case resp.Result == structs.VarOpResultConflict:
+		if err := v.setConflict(aclObj, resp.Conflict, reply); err != nil {
+			return err
+		}
+	case resp.WrittenVarMeta != nil:
+		reply.Output = &structs.VariableDecrypted{
+			VariableMetadata: *resp.WrittenVarMeta,
+			Items:            args.Var.Copy().Items,
+		}
+	}
+	return nil
+}
+
+// setConflict populates the conflict of the reply. The items are redacted if
+// the caller is unable to read the conflicting variable.
+func (v *Variables) setConflict(
+	aclObj *acl.ACL, conflict *structs.VariableEncrypted, reply *structs.VariablesApplyResponse) error {
+
+	// A nil conflict indicates the variable does not exist, despite the
+	// caller expecting it to.
+	if conflict == nil {
+		return nil
+	}
+
+	if aclObj != nil &&
+		!aclObj.AllowVariableOperation(conflict.Namespace, conflict.Path, acl.VariablesCapabilityRead) {
+		reply.Result = structs.VarOpResultRedacted
+		reply.Conflict = &structs.VariableDecrypted{VariableMetadata: conflict.VariableMetadata}
+		return nil
+	}
+
+	decrypted, err := v.decrypt(conflict)
+	if err != nil {
+		return err
+	}
+	reply.Conflict = decrypted
+	return nil
+}
+
+// Read is used to read a single variable, including its decrypted items. It
+// is callable by ACL tokens with the read capability on the path, and by
+// nodes reading the implicit variables of the jobs they are running.
+func (v *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := v.srv.forward(structs.VariablesReadRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	if err := v.handleMixedAuthEndpoint(args.QueryOptions, args.Path); err != nil {
+		return err
+	}
+
+	return v.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+			out, err := stateStore.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = nil
+			if out != nil {
+				decrypted, err := v.decrypt(out)
+				if err != nil {
+					return err
+				}
+				reply.Data = decrypted
+			}
+
+			// Use the index table to populate the query meta as we have no way
+			// of tracking the max index on deletes.
+			return v.srv.setReplyQueryMeta(stateStore, state.TableVariables, &reply.QueryMeta)
+		},
+	})
+}
+
+// List is used to list the metadata of variables held within state. It
+// supports single and wildcard namespace listings, and filtering by path
+// prefix.
+func (v *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := v.srv.forward(structs.VariablesListRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Only variables which the caller has the list capability for are
+	// returned, so callers with partial access see a partial listing.
+	filters := []paginator.Filter{
+		paginator.GenericFilter{
+			Allow: func(raw interface{}) (bool, error) {
+				variable := raw.(*structs.VariableEncrypted)
+				if !strings.HasPrefix(variable.Path, args.Prefix) {
+					return false, nil
+				}
+				if aclObj == nil {
+					return true, nil
+				}
+				return aclObj.AllowVariableOperation(
+					variable.Namespace, variable.Path, acl.VariablesCapabilityList), nil
+			},
+		},
+	}
+
+	return v.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, stateStore *state.StateStore) error {
+
+			var iter memdb.ResultIterator
+			var err error
+			if args.RequestNamespace() == structs.AllNamespacesSentinel {
+				iter, err = stateStore.Variables(ws)
+			} else {
+				iter, err = stateStore.GetVariablesByNamespaceAndPrefix(ws, args.RequestNamespace(), args.Prefix)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Generate the tokenizer to use for pagination using namespace and
+			// path to ensure complete uniqueness.
+			tokenizer := paginator.NewStructsTokenizer(iter,
+				paginator.StructsTokenizerOptions{
+					WithNamespace: true,
+					WithID:        true,
+				},
+			)
+
+			variables := []*structs.VariableMetadata{}
+
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
+				func(raw interface{}) error {
+					variable := raw.(*structs.VariableEncrypted)
+					variables = append(variables, variable.VariableMetadata.Copy())
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := paginatorImpl.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			reply.Data = variables
+			reply.NextToken = nextToken
+
+			// Use the index table to populate the query meta as we have no way
+			// of tracking the max index on deletes.
+			return v.srv.setReplyQueryMeta(stateStore, state.TableVariables, &reply.QueryMeta)
+		},
+	})
+}
+
+// handleMixedAuthEndpoint is a helper to handle auth on the read endpoint,
+// which can either be called by Nomad nodes on behalf of their workloads, or
+// by external clients.
+func (v *Variables) handleMixedAuthEndpoint(args structs.QueryOptions, path string) error {
+
+	// Perform the initial token resolution.
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+
+	switch err {
+	case nil:
+		// Perform our ACL validation. If the object is nil, this means ACLs
+		// are not enabled.
+		if aclObj != nil &&
+			!aclObj.AllowVariableOperation(args.RequestNamespace(), path, acl.VariablesCapabilityRead) {
+			return structs.ErrPermissionDenied
+		}
+		return nil
+	case structs.ErrTokenNotFound:
+		// Fallthrough to the node lookup.
+	default:
+		return err
+	}
+
+	// Attempt to lookup AuthToken as a Node.SecretID and return any error
+	// wrapped along with the original.
+	stateStore := v.srv.fsm.State()
+	node, stateErr := stateStore.NodeBySecretID(nil, args.AuthToken)
+	if stateErr != nil {
+		var mErr multierror.Error
+		mErr.Errors = append(mErr.Errors, err, stateErr)
+		return mErr.ErrorOrNil()
+	}
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	// Nodes are only permitted to read the implicit variables of the jobs
+	// which have running allocations placed on them.
+	allocs, err := stateStore.AllocsByNode(nil, node.ID)
+	if err != nil {
+		return err
+	}
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() || alloc.Namespace != args.RequestNamespace() {
+			continue
+		}
+		if structs.PathWithinVariablePrefix(path, structs.VariablesJobPath(alloc.JobID)) {
+			return nil
+		}
+	}
+	return structs.ErrPermissionDenied
+}
+
+// encrypt encrypts the items of the variable using the active root key.
+func (v *Variables) encrypt(variable *structs.VariableDecrypted) (*structs.VariableEncrypted, error) {
+	buf, err := json.Marshal(variable.Items)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, keyID, err := v.srv.encrypter.Encrypt(buf)
+	if err != nil {
+		return nil, err
+	}
+	return &structs.VariableEncrypted{
+		VariableMetadata: variable.VariableMetadata,
+		VariableData: structs.VariableData{
+			Data:  ciphertext,
+			KeyID: keyID,
+		},
+	}, nil
+}
+
+// decrypt decrypts the items of the variable using the root key it was
+// encrypted with.
+func (v *Variables) decrypt(variable *structs.VariableEncrypted) (*structs.VariableDecrypted, error) {
+	buf, err := v.srv.encrypter.Decrypt(variable.Data, variable.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := &structs.VariableDecrypted{
+		VariableMetadata: variable.VariableMetadata,
+	}
+	if err := json.Unmarshal(buf, &decrypted.Items); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
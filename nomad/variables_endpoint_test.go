@@ -0,0 +1,263 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForKeyring waits for the leader to initialize the keyring, so that
+// variables can be encrypted.
+func waitForKeyring(t *testing.T, s *Server) {
+	testutil.WaitForResult(func() (bool, error) {
+		keyMeta, err := s.State().GetActiveRootKeyMeta(nil)
+		return keyMeta != nil, err
+	}, func(err error) {
+		t.Fatalf("keyring was not initialized: %v", err)
+	})
+}
+
+func TestVariablesEndpoint_Apply(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanup := TestServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	waitForKeyring(t, s)
+
+	variable := &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "app/creds"},
+		Items:            structs.VariableItems{"username": "admin", "password": "hunter2"},
+	}
+
+	// Create the variable and ensure the items are encrypted within state.
+	applyReq := &structs.VariablesApplyRequest{
+		Op:           structs.VarOpSet,
+		Var:          variable,
+		WriteRequest: structs.WriteRequest{Region: DefaultRegion, Namespace: structs.DefaultNamespace},
+	}
+	var applyResp structs.VariablesApplyResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+	require.Equal(t, structs.VarOpResultOk, applyResp.Result)
+	require.NotNil(t, applyResp.Output)
+	require.Equal(t, applyResp.Index, applyResp.Output.ModifyIndex)
+
+	stored, err := s.State().GetVariable(nil, structs.DefaultNamespace, "app/creds")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.NotContains(t, string(stored.Data), "hunter2")
+
+	// Read the variable back and ensure the items are decrypted.
+	readReq := &structs.VariablesReadRequest{
+		Path:         "app/creds",
+		QueryOptions: structs.QueryOptions{Region: DefaultRegion, Namespace: structs.DefaultNamespace},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.NotNil(t, readResp.Data)
+	require.Equal(t, variable.Items, readResp.Data.Items)
+
+	// A stale check-and-set should conflict and return the current variable.
+	applyReq.Op = structs.VarOpCAS
+	applyReq.Var.ModifyIndex = 1
+	applyResp = structs.VariablesApplyResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+	require.True(t, applyResp.IsConflict())
+	require.Equal(t, structs.VarOpResultConflict, applyResp.Result)
+	require.Equal(t, variable.Items, applyResp.Conflict.Items)
+
+	// Invalid variables should be rejected.
+	applyReq.Op = structs.VarOpSet
+	applyReq.Var = &structs.VariableDecrypted{VariableMetadata: structs.VariableMetadata{Path: "nomad/foo"}}
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "reserved")
+
+	// Delete the variable and ensure reading it returns nothing.
+	applyReq.Op = structs.VarOpDelete
+	applyReq.Var = &structs.VariableDecrypted{VariableMetadata: structs.VariableMetadata{Path: "app/creds"}}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+	require.Equal(t, structs.VarOpResultOk, applyResp.Result)
+
+	readResp = structs.VariablesReadResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.Nil(t, readResp.Data)
+}
+
+func TestVariablesEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanup := TestACLServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	waitForKeyring(t, s)
+
+	// Write variables using the management token.
+	for _, path := range []string{"app/web", "app/db", "other"} {
+		req := &structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: path},
+				Items:            structs.VariableItems{"key": "value"},
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    DefaultRegion,
+				Namespace: structs.DefaultNamespace,
+				AuthToken: root.SecretID,
+			},
+		}
+		var resp structs.VariablesApplyResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, req, &resp))
+	}
+
+	// Create a token which can only list the paths under "app/" and read
+	// "app/web". The most specific path matched takes precedence, so it must
+	// also grant list.
+	policy := `
+namespace "default" {
+  variables {
+    path "app/*" { capabilities = ["list"] }
+    path "app/web" { capabilities = ["list", "read"] }
+  }
+}`
+	token := mock.CreatePolicyAndToken(t, s.State(), 1000, "variables", policy)
+
+	// The listing should be filtered to the paths the token can list.
+	listReq := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Data, 2)
+	require.Equal(t, "app/db", listResp.Data[0].Path)
+	require.Equal(t, "app/web", listResp.Data[1].Path)
+
+	// The wildcard namespace listing should be filtered in the same way.
+	listReq.Namespace = structs.AllNamespacesSentinel
+	listResp = structs.VariablesListResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, listReq, &listResp))
+	require.Len(t, listResp.Data, 2)
+
+	// The token can read "app/web" but not "app/db".
+	readReq := &structs.VariablesReadRequest{
+		Path: "app/web",
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.NotNil(t, readResp.Data)
+
+	readReq.Path = "app/db"
+	err := msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The token cannot write.
+	applyReq := &structs.VariablesApplyRequest{
+		Op: structs.VarOpSet,
+		Var: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "app/web"},
+			Items:            structs.VariableItems{"key": "value"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    DefaultRegion,
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var applyResp structs.VariablesApplyResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// A conflict on a path the caller cannot read should be redacted.
+	writePolicy := `
+namespace "default" {
+  variables {
+    path "app/db" { capabilities = ["write"] }
+  }
+}`
+	writeToken := mock.CreatePolicyAndToken(t, s.State(), 1010, "variables-write", writePolicy)
+	applyReq.Op = structs.VarOpCAS
+	applyReq.Var.Path = "app/db"
+	applyReq.AuthToken = writeToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, applyReq, &applyResp))
+	require.Equal(t, structs.VarOpResultRedacted, applyResp.Result)
+	require.Nil(t, applyResp.Conflict.Items)
+	require.Equal(t, "app/db", applyResp.Conflict.Path)
+}
+
+func TestVariablesEndpoint_Read_NodeSecret(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanup := TestACLServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	waitForKeyring(t, s)
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(t, s.State().UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	require.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	jobPath := structs.VariablesJobPath(alloc.JobID)
+	for _, path := range []string{jobPath, "other"} {
+		req := &structs.VariablesApplyRequest{
+			Op: structs.VarOpSet,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: path},
+				Items:            structs.VariableItems{"key": "value"},
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    DefaultRegion,
+				Namespace: alloc.Namespace,
+				AuthToken: root.SecretID,
+			},
+		}
+		var resp structs.VariablesApplyResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, req, &resp))
+	}
+
+	// The node can read the variables of the job it is running.
+	readReq := &structs.VariablesReadRequest{
+		Path: jobPath,
+		QueryOptions: structs.QueryOptions{
+			Region:    DefaultRegion,
+			Namespace: alloc.Namespace,
+			AuthToken: node.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	require.Equal(t, structs.VariableItems{"key": "value"}, readResp.Data.Items)
+
+	// The node cannot read other variables.
+	readReq.Path = "other"
+	err := msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Once the allocation is terminal, the node can no longer read the job
+	// variables.
+	stopped := alloc.Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	require.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 1010, []*structs.Allocation{stopped}))
+
+	readReq.Path = jobPath
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
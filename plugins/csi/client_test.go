@@ -1361,3 +1361,167 @@ func TestClient_RPC_NodeUnpublishVolume(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_RPC_ControllerExpandVolume(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name        string
+		Request     *ControllerExpandVolumeRequest
+		Response    *csipbv1.ControllerExpandVolumeResponse
+		ResponseErr error
+		ExpectedErr error
+	}{
+		{
+			Name: "success",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange: &CapacityRange{
+					RequiredBytes: 1,
+					LimitBytes:    2,
+				},
+				Secrets: map[string]string{"super": "secret"},
+			},
+			Response: &csipbv1.ControllerExpandVolumeResponse{
+				CapacityBytes:         2,
+				NodeExpansionRequired: true,
+			},
+		},
+		{
+			Name: "validate missing volume ID",
+			Request: &ControllerExpandVolumeRequest{
+				CapacityRange: &CapacityRange{RequiredBytes: 1},
+			},
+			ExpectedErr: errors.New("missing ExternalVolumeID"),
+		},
+		{
+			Name: "validate missing capacity range",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+			},
+			ExpectedErr: errors.New("missing CapacityRange"),
+		},
+		{
+			Name: "validate inconsistent capacity range",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange: &CapacityRange{
+					RequiredBytes: 2,
+					LimitBytes:    1,
+				},
+			},
+			ExpectedErr: errors.New("LimitBytes cannot be less than RequiredBytes"),
+		},
+		{
+			Name: "grpc error OutOfRange",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange:    &CapacityRange{RequiredBytes: 1},
+			},
+			ResponseErr: status.Errorf(codes.OutOfRange, "too big"),
+			ExpectedErr: errors.New(`unsupported capacity_range for volume "vol-1": rpc error: code = OutOfRange desc = too big`),
+		},
+		{
+			Name: "grpc error FailedPrecondition",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange:    &CapacityRange{RequiredBytes: 1},
+			},
+			ResponseErr: status.Errorf(codes.FailedPrecondition, "in use"),
+			ExpectedErr: errors.New(`volume "vol-1" cannot be expanded while in use: rpc error: code = FailedPrecondition desc = in use`),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, cc, _, client := newTestClient(t)
+			defer client.Close()
+
+			cc.NextErr = tc.ResponseErr
+			cc.NextExpandVolumeResponse = tc.Response
+
+			resp, err := client.ControllerExpandVolume(context.TODO(), tc.Request)
+			if tc.ExpectedErr != nil {
+				require.EqualError(t, err, tc.ExpectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Response.CapacityBytes, resp.CapacityBytes)
+			require.Equal(t, tc.Response.NodeExpansionRequired, resp.NodeExpansionRequired)
+		})
+	}
+}
+
+func TestClient_RPC_NodeExpandVolume(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name        string
+		Request     *NodeExpandVolumeRequest
+		Response    *csipbv1.NodeExpandVolumeResponse
+		ResponseErr error
+		ExpectedErr error
+	}{
+		{
+			Name: "success",
+			Request: &NodeExpandVolumeRequest{
+				ExternalID:    "vol-1",
+				CapacityRange: &CapacityRange{RequiredBytes: 10},
+				TargetPath:    "/dev/null",
+			},
+			Response: &csipbv1.NodeExpandVolumeResponse{CapacityBytes: 10},
+		},
+		{
+			Name: "validate missing volume ID",
+			Request: &NodeExpandVolumeRequest{
+				CapacityRange: &CapacityRange{RequiredBytes: 10},
+				TargetPath:    "/dev/null",
+			},
+			ExpectedErr: errors.New("validation error: missing volume ID"),
+		},
+		{
+			Name: "validate missing target path",
+			Request: &NodeExpandVolumeRequest{
+				ExternalID:    "vol-1",
+				CapacityRange: &CapacityRange{RequiredBytes: 10},
+			},
+			ExpectedErr: errors.New("validation error: missing TargetPath"),
+		},
+		{
+			Name: "grpc error NotFound",
+			Request: &NodeExpandVolumeRequest{
+				ExternalID:    "vol-1",
+				CapacityRange: &CapacityRange{RequiredBytes: 10},
+				TargetPath:    "/dev/null",
+			},
+			ResponseErr: status.Errorf(codes.NotFound, "does not exist"),
+			ExpectedErr: errors.New(`volume "vol-1" could not be found: rpc error: code = NotFound desc = does not exist`),
+		},
+		{
+			Name: "grpc error Internal",
+			Request: &NodeExpandVolumeRequest{
+				ExternalID:    "vol-1",
+				CapacityRange: &CapacityRange{RequiredBytes: 10},
+				TargetPath:    "/dev/null",
+			},
+			ResponseErr: status.Errorf(codes.Internal, "some grpc error"),
+			ExpectedErr: errors.New("node plugin returned an internal error, check the plugin allocation logs for more information: rpc error: code = Internal desc = some grpc error"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, _, nc, client := newTestClient(t)
+			defer client.Close()
+
+			nc.NextErr = tc.ResponseErr
+			nc.NextExpandVolumeResponse = tc.Response
+
+			resp, err := client.NodeExpandVolume(context.TODO(), tc.Request)
+			if tc.ExpectedErr != nil {
+				require.EqualError(t, err, tc.ExpectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Response.CapacityBytes, resp.CapacityBytes)
+		})
+	}
+}
@@ -57,6 +57,10 @@ type Client struct {
 	NextControllerDeleteVolumeErr   error
 	ControllerDeleteVolumeCallCount int64
 
+	NextControllerExpandVolumeResponse *csi.ControllerExpandVolumeResponse
+	NextControllerExpandVolumeErr      error
+	ControllerExpandVolumeCallCount    int64
+
 	NextControllerListVolumesResponse *csi.ControllerListVolumesResponse
 	NextControllerListVolumesErr      error
 	ControllerListVolumesCallCount    int64
@@ -95,6 +99,11 @@ type Client struct {
 
 	NextNodeUnpublishVolumeErr   error
 	NodeUnpublishVolumeCallCount int64
+
+	PrevNodeExpandVolumeRequest  *csi.NodeExpandVolumeRequest
+	NextNodeExpandVolumeResponse *csi.NodeExpandVolumeResponse
+	NextNodeExpandVolumeErr      error
+	NodeExpandVolumeCallCount    int64
 }
 
 // PluginInfo describes the type and version of a plugin.
@@ -204,6 +213,13 @@ func (c *Client) ControllerDeleteVolume(ctx context.Context, req *csi.Controller
 	return c.NextControllerDeleteVolumeErr
 }
 
+func (c *Client) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest, opts ...grpc.CallOption) (*csi.ControllerExpandVolumeResponse, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+	c.ControllerExpandVolumeCallCount++
+	return c.NextControllerExpandVolumeResponse, c.NextControllerExpandVolumeErr
+}
+
 func (c *Client) ControllerListVolumes(ctx context.Context, req *csi.ControllerListVolumesRequest, opts ...grpc.CallOption) (*csi.ControllerListVolumesResponse, error) {
 	c.Mu.Lock()
 	defer c.Mu.Unlock()
@@ -297,6 +313,16 @@ func (c *Client) NodeUnpublishVolume(ctx context.Context, volumeID, targetPath s
 	return c.NextNodeUnpublishVolumeErr
 }
 
+func (c *Client) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest, opts ...grpc.CallOption) (*csi.NodeExpandVolumeResponse, error) {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
+
+	c.PrevNodeExpandVolumeRequest = req
+	c.NodeExpandVolumeCallCount++
+
+	return c.NextNodeExpandVolumeResponse, c.NextNodeExpandVolumeErr
+}
+
 // Close the client and ensure any connections are cleaned up.
 func (c *Client) Close() error {
 
@@ -53,6 +53,10 @@ type CSIPlugin interface {
 	// external storage provider
 	ControllerDeleteVolume(ctx context.Context, req *ControllerDeleteVolumeRequest, opts ...grpc.CallOption) error
 
+	// ControllerExpandVolume is used to expand a remote volume in the
+	// external storage provider
+	ControllerExpandVolume(ctx context.Context, req *ControllerExpandVolumeRequest, opts ...grpc.CallOption) (*ControllerExpandVolumeResponse, error)
+
 	// ControllerListVolumes is used to list all volumes available in the
 	// external storage provider
 	ControllerListVolumes(ctx context.Context, req *ControllerListVolumesRequest, opts ...grpc.CallOption) (*ControllerListVolumesResponse, error)
@@ -98,6 +102,11 @@ type CSIPlugin interface {
 	// for the given volume.
 	NodeUnpublishVolume(ctx context.Context, volumeID, targetPath string, opts ...grpc.CallOption) error
 
+	// NodeExpandVolume is used when a plugin has the EXPAND_VOLUME node
+	// capability to expand a volume on the node after it has been expanded
+	// by ControllerExpandVolume, e.g. to resize its filesystem.
+	NodeExpandVolume(ctx context.Context, req *NodeExpandVolumeRequest, opts ...grpc.CallOption) (*NodeExpandVolumeResponse, error)
+
 	// Shutdown the client and ensure any connections are cleaned up.
 	Close() error
 }
@@ -241,6 +250,56 @@ func (r *NodeStageVolumeRequest) Validate() error {
 	return nil
 }
 
+type NodeExpandVolumeRequest struct {
+	// The external ID of the volume to expand.
+	ExternalID string
+
+	// The capacity the volume was expanded to by the controller.
+	CapacityRange *CapacityRange
+
+	// The path to which the volume is published by `NodePublishVolume`.
+	// This is a REQUIRED field.
+	TargetPath string
+
+	// The path to which the volume was staged by `NodeStageVolume`. It MUST
+	// be set if the Node Plugin implements the `STAGE_UNSTAGE_VOLUME` node
+	// capability.
+	StagingTargetPath string
+
+	// Volume capability describing how the CO uses this volume.
+	VolumeCapability *VolumeCapability
+}
+
+func (r *NodeExpandVolumeRequest) ToCSIRepresentation() *csipbv1.NodeExpandVolumeRequest {
+	if r == nil {
+		return nil
+	}
+
+	return &csipbv1.NodeExpandVolumeRequest{
+		VolumeId:          r.ExternalID,
+		VolumePath:        r.TargetPath,
+		CapacityRange:     r.CapacityRange.ToCSIRepresentation(),
+		StagingTargetPath: r.StagingTargetPath,
+		VolumeCapability:  r.VolumeCapability.ToCSIRepresentation(),
+	}
+}
+
+func (r *NodeExpandVolumeRequest) Validate() error {
+	if r.ExternalID == "" {
+		return errors.New("missing volume ID")
+	}
+
+	if r.TargetPath == "" {
+		return errors.New("missing TargetPath")
+	}
+
+	return r.CapacityRange.Validate()
+}
+
+type NodeExpandVolumeResponse struct {
+	CapacityBytes int64
+}
+
 type PluginCapabilitySet struct {
 	hasControllerService bool
 	hasTopologies        bool
@@ -622,6 +681,40 @@ func (r *ControllerDeleteVolumeRequest) Validate() error {
 	return nil
 }
 
+type ControllerExpandVolumeRequest struct {
+	ExternalVolumeID string
+	CapacityRange    *CapacityRange
+	Secrets          structs.CSISecrets
+
+	// VolumeCapability is the capability the volume is currently used
+	// with, if any. This field is OPTIONAL.
+	VolumeCapability *VolumeCapability
+}
+
+func (r *ControllerExpandVolumeRequest) ToCSIRepresentation() *csipbv1.ControllerExpandVolumeRequest {
+	if r == nil {
+		return nil
+	}
+	return &csipbv1.ControllerExpandVolumeRequest{
+		VolumeId:         r.ExternalVolumeID,
+		CapacityRange:    r.CapacityRange.ToCSIRepresentation(),
+		Secrets:          r.Secrets,
+		VolumeCapability: r.VolumeCapability.ToCSIRepresentation(),
+	}
+}
+
+func (r *ControllerExpandVolumeRequest) Validate() error {
+	if r.ExternalVolumeID == "" {
+		return errors.New("missing ExternalVolumeID")
+	}
+	return r.CapacityRange.Validate()
+}
+
+type ControllerExpandVolumeResponse struct {
+	CapacityBytes         int64
+	NodeExpansionRequired bool
+}
+
 type ControllerListVolumesRequest struct {
 	MaxEntries    int32
 	StartingToken string
@@ -964,6 +1057,21 @@ type CapacityRange struct {
 	LimitBytes    int64
 }
 
+// Validate checks that a capacity range requested to expand a volume is set
+// and consistent.
+func (c *CapacityRange) Validate() error {
+	if c == nil {
+		return errors.New("missing CapacityRange")
+	}
+	if c.LimitBytes == 0 && c.RequiredBytes == 0 {
+		return errors.New("one of LimitBytes or RequiredBytes must be set")
+	}
+	if c.LimitBytes != 0 && c.LimitBytes < c.RequiredBytes {
+		return errors.New("LimitBytes cannot be less than RequiredBytes")
+	}
+	return nil
+}
+
 func (c *CapacityRange) ToCSIRepresentation() *csipbv1.CapacityRange {
 	if c == nil {
 		return nil
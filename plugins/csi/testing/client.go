@@ -56,6 +56,7 @@ type ControllerClient struct {
 	NextCreateSnapshotResponse             *csipbv1.CreateSnapshotResponse
 	NextDeleteSnapshotResponse             *csipbv1.DeleteSnapshotResponse
 	NextListSnapshotsResponse              *csipbv1.ListSnapshotsResponse
+	NextExpandVolumeResponse               *csipbv1.ControllerExpandVolumeResponse
 }
 
 // NewControllerClient returns a new ControllerClient
@@ -75,6 +76,7 @@ func (c *ControllerClient) Reset() {
 	c.NextCreateSnapshotResponse = nil
 	c.NextDeleteSnapshotResponse = nil
 	c.NextListSnapshotsResponse = nil
+	c.NextExpandVolumeResponse = nil
 }
 
 func (c *ControllerClient) ControllerGetCapabilities(ctx context.Context, in *csipbv1.ControllerGetCapabilitiesRequest, opts ...grpc.CallOption) (*csipbv1.ControllerGetCapabilitiesResponse, error) {
@@ -116,6 +118,10 @@ func (c *ControllerClient) ListVolumes(ctx context.Context, in *csipbv1.ListVolu
 	return c.NextListVolumesResponse, c.NextErr
 }
 
+func (c *ControllerClient) ControllerExpandVolume(ctx context.Context, in *csipbv1.ControllerExpandVolumeRequest, opts ...grpc.CallOption) (*csipbv1.ControllerExpandVolumeResponse, error) {
+	return c.NextExpandVolumeResponse, c.NextErr
+}
+
 func (c *ControllerClient) CreateSnapshot(ctx context.Context, in *csipbv1.CreateSnapshotRequest, opts ...grpc.CallOption) (*csipbv1.CreateSnapshotResponse, error) {
 	return c.NextCreateSnapshotResponse, c.NextErr
 }
@@ -137,6 +143,7 @@ type NodeClient struct {
 	NextUnstageVolumeResponse   *csipbv1.NodeUnstageVolumeResponse
 	NextPublishVolumeResponse   *csipbv1.NodePublishVolumeResponse
 	NextUnpublishVolumeResponse *csipbv1.NodeUnpublishVolumeResponse
+	NextExpandVolumeResponse    *csipbv1.NodeExpandVolumeResponse
 }
 
 // NewNodeClient returns a new stub NodeClient
@@ -152,6 +159,7 @@ func (c *NodeClient) Reset() {
 	c.NextUnstageVolumeResponse = nil
 	c.NextPublishVolumeResponse = nil
 	c.NextUnpublishVolumeResponse = nil
+	c.NextExpandVolumeResponse = nil
 }
 
 func (c *NodeClient) NodeGetCapabilities(ctx context.Context, in *csipbv1.NodeGetCapabilitiesRequest, opts ...grpc.CallOption) (*csipbv1.NodeGetCapabilitiesResponse, error) {
@@ -177,3 +185,7 @@ func (c *NodeClient) NodePublishVolume(ctx context.Context, in *csipbv1.NodePubl
 func (c *NodeClient) NodeUnpublishVolume(ctx context.Context, in *csipbv1.NodeUnpublishVolumeRequest, opts ...grpc.CallOption) (*csipbv1.NodeUnpublishVolumeResponse, error) {
 	return c.NextUnpublishVolumeResponse, c.NextErr
 }
+
+func (c *NodeClient) NodeExpandVolume(ctx context.Context, in *csipbv1.NodeExpandVolumeRequest, opts ...grpc.CallOption) (*csipbv1.NodeExpandVolumeResponse, error) {
+	return c.NextExpandVolumeResponse, c.NextErr
+}
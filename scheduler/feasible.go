@@ -28,6 +28,7 @@ const (
 	FilterConstraintDrivers                        = "missing drivers"
 	FilterConstraintDevices                        = "missing devices"
 	FilterConstraintsCSIPluginTopology             = "did not meet topology requirement"
+	FilterConstraintNodePool                       = "node pool"
 )
 
 var (
@@ -130,11 +131,61 @@ func NewRandomIterator(ctx Context, nodes []*structs.Node) *StaticIterator {
 	return NewStaticIterator(ctx, nodes)
 }
 
+// NodePoolChecker is a FeasibilityChecker which returns whether a node is in
+// the node pool targeted by the job.
+type NodePoolChecker struct {
+	ctx  Context
+	pool string
+}
+
+// NewNodePoolChecker creates a NodePoolChecker
+func NewNodePoolChecker(ctx Context) *NodePoolChecker {
+	return &NodePoolChecker{
+		ctx: ctx,
+	}
+}
+
+// SetPool sets the node pool targeted by the job.
+func (c *NodePoolChecker) SetPool(pool string) {
+	c.pool = pool
+}
+
+func (c *NodePoolChecker) Feasible(n *structs.Node) bool {
+	if nodeInPool(n, c.pool) {
+		return true
+	}
+
+	c.ctx.Metrics().FilterNode(n, FilterConstraintNodePool)
+	return false
+}
+
+// nodeInPool returns whether the node belongs to the given node pool. Nodes
+// and jobs that predate node pools have no pool set and are treated as part
+// of the default node pool.
+func nodeInPool(n *structs.Node, pool string) bool {
+	if pool == structs.NodePoolAll {
+		return true
+	}
+	if pool == "" {
+		pool = structs.NodePoolDefault
+	}
+
+	nodePool := n.NodePool
+	if nodePool == "" {
+		nodePool = structs.NodePoolDefault
+	}
+	return nodePool == pool
+}
+
 // HostVolumeChecker is a FeasibilityChecker which returns whether a node has
 // the host volumes necessary to schedule a task group.
 type HostVolumeChecker struct {
 	ctx Context
 
+	// namespace is the namespace of the job, which dynamic host volumes must
+	// belong to.
+	namespace string
+
 	// volumes is a map[HostVolumeName][]RequestedVolume. The requested volumes are
 	// a slice because a single task group may request the same volume multiple times.
 	volumes map[string][]*structs.VolumeRequest
@@ -147,6 +198,11 @@ func NewHostVolumeChecker(ctx Context) *HostVolumeChecker {
 	}
 }
 
+// SetNamespace sets the namespace of the job being placed.
+func (h *HostVolumeChecker) SetNamespace(namespace string) {
+	h.namespace = namespace
+}
+
 // SetVolumes takes the volumes required by a task group and updates the checker.
 func (h *HostVolumeChecker) SetVolumes(volumes map[string]*structs.VolumeRequest) {
 	lookupMap := make(map[string][]*structs.VolumeRequest)
@@ -191,6 +247,14 @@ func (h *HostVolumeChecker) hasVolumes(n *structs.Node) bool {
 			return false
 		}
 
+		// Dynamic host volumes can only be used by jobs of their namespace
+		if nodeVolume.ID != "" {
+			vol, err := h.ctx.State().HostVolumeByID(nil, h.namespace, nodeVolume.ID, false)
+			if err != nil || vol == nil {
+				return false
+			}
+		}
+
 		// If the volume supports being mounted as ReadWrite, we do not need to
 		// do further validation for readonly placement.
 		if !nodeVolume.ReadOnly {
@@ -89,6 +89,49 @@ func TestRandomIterator(t *testing.T) {
 	}
 }
 
+func TestNodePoolChecker(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	nodes[1].NodePool = "dev"
+	nodes[2].NodePool = ""
+
+	cases := []struct {
+		Pool   string
+		Result []bool
+	}{
+		{
+			Pool:   structs.NodePoolDefault,
+			Result: []bool{true, false, true},
+		},
+		{
+			Pool:   "",
+			Result: []bool{true, false, true},
+		},
+		{
+			Pool:   "dev",
+			Result: []bool{false, true, false},
+		},
+		{
+			Pool:   structs.NodePoolAll,
+			Result: []bool{true, true, true},
+		},
+	}
+
+	checker := NewNodePoolChecker(ctx)
+	for _, c := range cases {
+		checker.SetPool(c.Pool)
+		for i, node := range nodes {
+			require.Equal(t, c.Result[i], checker.Feasible(node), "pool %q node %d", c.Pool, i)
+		}
+	}
+}
+
 func TestHostVolumeChecker(t *testing.T) {
 	ci.Parallel(t)
 
@@ -243,6 +286,35 @@ func TestHostVolumeChecker_ReadOnly(t *testing.T) {
 	}
 }
 
+func TestHostVolumeChecker_Namespace(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	node := mock.Node()
+	vol := &structs.HostVolume{
+		ID:        uuid.Generate(),
+		Namespace: structs.DefaultNamespace,
+		Name:      "data",
+		NodeID:    node.ID,
+	}
+	require.NoError(t, state.UpsertHostVolumes(structs.MsgTypeTestSetup, 1000, []*structs.HostVolume{vol}))
+	node.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"data": {Name: "data", Path: "/data", ID: vol.ID},
+	}
+
+	checker := NewHostVolumeChecker(ctx)
+	checker.SetVolumes(map[string]*structs.VolumeRequest{
+		"data": {Type: structs.VolumeTypeHost, Source: "data"},
+	})
+
+	// Dynamic host volumes are only available to jobs of their namespace
+	checker.SetNamespace(structs.DefaultNamespace)
+	require.True(t, checker.Feasible(node))
+
+	checker.SetNamespace("other")
+	require.False(t, checker.Feasible(node))
+}
+
 func TestCSIVolumeChecker(t *testing.T) {
 	ci.Parallel(t)
 	state, ctx := testContext(t)
@@ -353,7 +425,7 @@ func TestCSIVolumeChecker(t *testing.T) {
 		{Segments: map[string]string{"rack": "R1"}},
 		{Segments: map[string]string{"rack": "R2"}},
 	}
-	err := state.UpsertCSIVolume(index, []*structs.CSIVolume{vol})
+	err := state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol})
 	require.NoError(t, err)
 	index++
 
@@ -364,14 +436,14 @@ func TestCSIVolumeChecker(t *testing.T) {
 	vol2.Namespace = structs.DefaultNamespace
 	vol2.AccessMode = structs.CSIVolumeAccessModeMultiNodeSingleWriter
 	vol2.AttachmentMode = structs.CSIVolumeAttachmentModeFilesystem
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{vol2})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol2})
 	require.NoError(t, err)
 	index++
 
 	vid3 := "volume-id[0]"
 	vol3 := vol.Copy()
 	vol3.ID = vid3
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{vol3})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol3})
 	require.NoError(t, err)
 	index++
 
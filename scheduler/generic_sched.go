@@ -71,6 +71,10 @@ const (
 	// maxPastRescheduleEvents is the maximum number of past reschedule event
 	// that we track when unlimited rescheduling is enabled
 	maxPastRescheduleEvents = 5
+
+	// stickyHostNotReady is the constraint recorded when an allocation is not
+	// placed because the node it is pinned to is not ready
+	stickyHostNotReady = "sticky host node not ready"
 )
 
 // minVersionMaxClientDisconnect is the minimum version that supports max_client_disconnect.
@@ -276,7 +280,9 @@ func (s *GenericScheduler) process() (bool, error) {
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
 	if !s.job.Stopped() {
-		s.stack.SetJob(s.job)
+		if err := s.stack.SetJob(s.job); err != nil {
+			return false, err
+		}
 	}
 
 	// Compute the target job allocations
@@ -384,8 +390,13 @@ func (s *GenericScheduler) computeJobAllocs() error {
 	s.logger.Debug("reconciled current state with desired state", "results", log.Fmt("%#v", results))
 
 	if s.eval.AnnotatePlan {
+		algorithms, err := taskGroupSchedulerAlgorithms(s.state, s.job)
+		if err != nil {
+			return err
+		}
 		s.plan.Annotations = &structs.PlanAnnotations{
-			DesiredTGUpdates: results.desiredTGUpdates,
+			DesiredTGUpdates:    results.desiredTGUpdates,
+			SchedulerAlgorithms: algorithms,
 		}
 	}
 
@@ -498,7 +509,7 @@ func (s *GenericScheduler) downgradedJobForPlacement(p placementResult) (string,
 // destructive updates to place and the set of new placements to place.
 func (s *GenericScheduler) computePlacements(destructive, place []placementResult) error {
 	// Get the base nodes
-	nodes, _, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters)
+	nodes, _, byDC, err := readyNodesInDCsAndPool(s.state, s.job.Datacenters, s.job.NodePool)
 	if err != nil {
 		return err
 	}
@@ -554,7 +565,9 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 			// Use downgraded job in scheduling stack to honor
 			// old job resources and constraints
 			if downgradedJob != nil {
-				s.stack.SetJob(downgradedJob)
+				if err := s.stack.SetJob(downgradedJob); err != nil {
+					return err
+				}
 			}
 
 			// Find the preferred node
@@ -563,6 +576,12 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				return err
 			}
 
+			// Find the node the allocation is pinned to, if any
+			pinnedNode, pinned, err := s.findPinnedNode(missing)
+			if err != nil {
+				return err
+			}
+
 			// Check if we should stop the previous allocation upon successful
 			// placement of its replacement. This allow atomic placements/stops. We
 			// stop the allocation before trying to find a replacement because this
@@ -576,6 +595,16 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 			// Compute penalty nodes for rescheduled allocs
 			selectOptions := getSelectOptions(prevAllocation, preferredNode)
 			selectOptions.AllocName = missing.Name()
+
+			// Pinned allocations are only placed on the node of their
+			// predecessor, and only while that node is ready
+			if pinned {
+				selectOptions.PreferredNodes = nil
+				if pinnedNode != nil && pinnedNode.Ready() {
+					selectOptions.PreferredNodes = []*structs.Node{pinnedNode}
+				}
+				selectOptions.PreferredNodesOnly = true
+			}
 			option := s.selectNextOption(tg, selectOptions)
 
 			// Store the available nodes by datacenter
@@ -586,7 +615,9 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 
 			// Restore stack job now that placement is done, to use plan job version
 			if downgradedJob != nil {
-				s.stack.SetJob(s.job)
+				if err := s.stack.SetJob(s.job); err != nil {
+					return err
+				}
 			}
 
 			// Set fields based on if we found an allocation option
@@ -659,6 +690,12 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 					s.failedTGAllocs = make(map[string]*structs.AllocMetric)
 				}
 
+				// Record why an allocation pinned to a node that is not ready
+				// could not be placed
+				if pinned && (pinnedNode == nil || !pinnedNode.Ready()) {
+					s.ctx.Metrics().FilterNode(pinnedNode, stickyHostNotReady)
+				}
+
 				// Update metrics with the resources requested by the task group.
 				s.ctx.Metrics().ExhaustResources(tg)
 
@@ -796,6 +833,24 @@ func (s *GenericScheduler) findPreferredNode(place placementResult) (*structs.No
 	return nil, nil
 }
 
+// findPinnedNode finds the node an allocation is pinned to. The returned
+// boolean is true if the task group uses sticky_host and the allocation
+// replaces one that was not unpinned, in which case the allocation must only
+// be placed on the returned node. The node is nil if it no longer exists.
+func (s *GenericScheduler) findPinnedNode(place placementResult) (*structs.Node, bool, error) {
+	prev := place.PreviousAllocation()
+	if prev == nil || !place.TaskGroup().StickyHost || prev.DesiredTransition.ShouldUnpin() {
+		return nil, false, nil
+	}
+
+	ws := memdb.NewWatchSet()
+	node, err := s.state.NodeByID(ws, prev.NodeID)
+	if err != nil {
+		return nil, false, err
+	}
+	return node, true, nil
+}
+
 // selectNextOption calls the stack to get a node for placement
 func (s *GenericScheduler) selectNextOption(tg *structs.TaskGroup, selectOptions *SelectOptions) *RankedNode {
 	option := s.stack.Select(tg, selectOptions)
@@ -166,7 +166,7 @@ func TestServiceSched_JobRegister_MemoryMaxHonored(t *testing.T) {
 			res.MemoryMaxMB = c.memoryMax
 
 			h := NewHarness(t)
-			h.State.SchedulerSetConfig(h.NextIndex(), &structs.SchedulerConfiguration{
+			h.State.SchedulerSetConfig(structs.MsgTypeTestSetup, h.NextIndex(), &structs.SchedulerConfiguration{
 				MemoryOversubscriptionEnabled: c.memoryOversubscriptionEnabled,
 			})
 
@@ -315,6 +315,172 @@ func TestServiceSched_JobRegister_StickyAllocs(t *testing.T) {
 	}
 }
 
+func TestServiceSched_StickyHost(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+
+		// pinnedNodeReady sets whether the node of the allocation is ready
+		pinnedNodeReady bool
+
+		// unpin sets whether the allocation was unpinned
+		unpin bool
+
+		// expectSameNode is whether the replacement is placed on the node
+		// of the allocation, or on the other node if false
+		expectSameNode bool
+
+		// expectBlocked is whether the replacement is not placed
+		expectBlocked bool
+	}{
+		{
+			name:            "pinned node ready",
+			pinnedNodeReady: true,
+			expectSameNode:  true,
+		},
+		{
+			name:            "pinned node not ready",
+			pinnedNodeReady: false,
+			expectBlocked:   true,
+		},
+		{
+			name:            "unpinned",
+			pinnedNodeReady: false,
+			unpin:           true,
+			expectSameNode:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			// Create the node of the allocation and another node
+			pinnedNode := mock.Node()
+			if !tc.pinnedNodeReady {
+				pinnedNode.SchedulingEligibility = structs.NodeSchedulingIneligible
+			}
+			require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), pinnedNode))
+			otherNode := mock.Node()
+			require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), otherNode))
+
+			// Create a job using sticky_host and an allocation to migrate.
+			// The job has an affinity for the other node, so only the pinning
+			// keeps the replacement on the node of the allocation.
+			job := mock.Job()
+			job.TaskGroups[0].Count = 1
+			job.TaskGroups[0].StickyHost = true
+			job.TaskGroups[0].Affinities = []*structs.Affinity{{
+				LTarget: "${node.unique.id}",
+				RTarget: otherNode.ID,
+				Operand: "=",
+				Weight:  100,
+			}}
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			alloc := mock.Alloc()
+			alloc.Job = job
+			alloc.JobID = job.ID
+			alloc.NodeID = pinnedNode.ID
+			alloc.Name = "my-job.web[0]"
+			alloc.ClientStatus = structs.AllocClientStatusRunning
+			alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+			if tc.unpin {
+				alloc.DesiredTransition.Unpin = helper.BoolToPtr(true)
+			}
+			require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerAllocStop,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			// Process the evaluation
+			require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+			if tc.expectBlocked {
+				// Ensure nothing was placed and the eval was blocked
+				for _, plan := range h.Plans {
+					require.Empty(t, plan.NodeAllocation)
+				}
+				require.Len(t, h.Evals, 1)
+				metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+				require.NotNil(t, metrics)
+				require.Equal(t, 1, metrics.ConstraintFiltered[stickyHostNotReady])
+				require.Len(t, h.CreateEvals, 1)
+				require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+				return
+			}
+
+			require.Len(t, h.Plans, 1)
+			var planned []*structs.Allocation
+			for _, allocList := range h.Plans[0].NodeAllocation {
+				planned = append(planned, allocList...)
+			}
+			require.Len(t, planned, 1)
+			require.Equal(t, alloc.ID, planned[0].PreviousAllocation)
+			if tc.expectSameNode {
+				require.Equal(t, pinnedNode.ID, planned[0].NodeID)
+			} else {
+				require.Equal(t, otherNode.ID, planned[0].NodeID)
+			}
+		})
+	}
+}
+
+func TestServiceSched_JobRegister_NodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes in the default node pool and in a custom node pool
+	var devNodes []string
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		if i%2 == 0 {
+			node.NodePool = "dev"
+			devNodes = append(devNodes, node.ID)
+		}
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job in the custom node pool
+	job := mock.Job()
+	job.NodePool = "dev"
+	job.TaskGroups[0].Count = 4
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+	require.Len(t, h.Plans, 1)
+
+	// Ensure all allocations were placed on nodes of the node pool
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, out, 4)
+	for _, alloc := range out {
+		require.Contains(t, devNodes, alloc.NodeID)
+	}
+}
+
 func TestServiceSched_JobRegister_DiskConstraints(t *testing.T) {
 	ci.Parallel(t)
 
@@ -989,6 +1155,9 @@ func TestServiceSched_JobRegister_Annotate(t *testing.T) {
 	if !reflect.DeepEqual(desiredChanges, expected) {
 		t.Fatalf("Unexpected desired updates; got %#v; want %#v", desiredChanges, expected)
 	}
+
+	expectedAlgorithms := map[string]structs.SchedulerAlgorithm{"web": structs.SchedulerAlgorithmBinpack}
+	require.Equal(t, expectedAlgorithms, plan.Annotations.SchedulerAlgorithms)
 }
 
 func TestServiceSched_JobRegister_CountZero(t *testing.T) {
@@ -6268,7 +6437,7 @@ func TestServiceSched_CSIVolumesPerAlloc(t *testing.T) {
 	// once its been fixed
 	shared.AccessMode = structs.CSIVolumeAccessModeMultiNodeReader
 
-	require.NoError(h.State.UpsertCSIVolume(
+	require.NoError(h.State.UpsertCSIVolume(structs.MsgTypeTestSetup, 
 		h.NextIndex(), []*structs.CSIVolume{shared, vol0, vol1, vol2}))
 
 	// Create a job that uses both
@@ -6361,7 +6530,7 @@ func TestServiceSched_CSIVolumesPerAlloc(t *testing.T) {
 	vol4.ID = "volume-unique[3]"
 	vol5 := vol0.Copy()
 	vol5.ID = "volume-unique[4]"
-	require.NoError(h.State.UpsertCSIVolume(
+	require.NoError(h.State.UpsertCSIVolume(structs.MsgTypeTestSetup, 
 		h.NextIndex(), []*structs.CSIVolume{vol4, vol5}))
 
 	// Process again with failure fixed. It should create a new plan
@@ -6440,7 +6609,7 @@ func TestServiceSched_CSITopology(t *testing.T) {
 	vol1.PluginID = "test-plugin-zone-1"
 	vol1.RequestedTopologies.Required[0].Segments["zone"] = "zone-1"
 
-	require.NoError(t, h.State.UpsertCSIVolume(
+	require.NoError(t, h.State.UpsertCSIVolume(structs.MsgTypeTestSetup, 
 		h.NextIndex(), []*structs.CSIVolume{vol0, vol1}))
 
 	// Create a job that uses those volumes
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
@@ -30,6 +31,11 @@ type propertySet struct {
 	// targetAttribute is the attribute this property set is checking
 	targetAttribute string
 
+	// targetAttributes are the attributes of a compound property, whose value
+	// is the path of the values of each attribute. It is only set when
+	// evaluating compound spread stanzas.
+	targetAttributes []string
+
 	// allowedCount is the allowed number of allocations that can have the
 	// distinct property
 	allowedCount uint64
@@ -103,6 +109,14 @@ func (p *propertySet) SetTargetAttribute(targetAttribute string, taskGroup strin
 	p.setTargetAttributeWithCount(targetAttribute, 0, taskGroup)
 }
 
+// SetTargetAttributes is used to populate this property set with a compound
+// property made of several attributes. This is used when evaluating compound
+// spread stanzas
+func (p *propertySet) SetTargetAttributes(targetAttributes []string, taskGroup string) {
+	p.targetAttributes = targetAttributes
+	p.setTargetAttributeWithCount(strings.Join(targetAttributes, structs.SpreadTargetSeparator), 0, taskGroup)
+}
+
 // setTargetAttributeWithCount is a shared helper for setting a job or task group attribute and allowedCount
 // allowedCount can be zero when this is used in evaluating spread stanzas
 func (p *propertySet) setTargetAttributeWithCount(targetAttribute string, allowedCount uint64, taskGroup string) {
@@ -235,7 +249,7 @@ func (p *propertySet) UsedCount(option *structs.Node, tg string) (string, string
 	}
 
 	// Get the nodes property value
-	nValue, ok := getProperty(option, p.targetAttribute)
+	nValue, ok := p.nodeProperty(option)
 	if !ok {
 		return nValue, fmt.Sprintf("missing property %q", p.targetAttribute), 0
 	}
@@ -327,7 +341,7 @@ func (p *propertySet) populateProperties(allocs []*structs.Allocation, nodes map
 	properties map[string]uint64) {
 
 	for _, alloc := range allocs {
-		nProperty, ok := getProperty(nodes[alloc.NodeID], p.targetAttribute)
+		nProperty, ok := p.nodeProperty(nodes[alloc.NodeID])
 		if !ok {
 			continue
 		}
@@ -336,6 +350,29 @@ func (p *propertySet) populateProperties(allocs []*structs.Allocation, nodes map
 	}
 }
 
+// nodeProperty is used to lookup the value of the property of the set on the
+// node
+func (p *propertySet) nodeProperty(n *structs.Node) (string, bool) {
+	if len(p.targetAttributes) == 0 {
+		return getProperty(n, p.targetAttribute)
+	}
+	return getCompoundProperty(n, p.targetAttributes)
+}
+
+// getCompoundProperty is used to lookup the value of a compound property on
+// the node, which is the path of the escaped values of its attributes
+func getCompoundProperty(n *structs.Node, properties []string) (string, bool) {
+	values := make([]string, 0, len(properties))
+	for _, property := range properties {
+		value, ok := getProperty(n, property)
+		if !ok {
+			return "", false
+		}
+		values = append(values, value)
+	}
+	return structs.JoinSpreadValues(values), true
+}
+
 // getProperty is used to lookup the property value on the node
 func getProperty(n *structs.Node, property string) (string, bool) {
 	if n == nil || property == "" {
@@ -162,25 +162,28 @@ type BinPackIterator struct {
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
 // potentially evicting other tasks based on a given priority.
 func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority int, schedConfig *structs.SchedulerConfiguration) *BinPackIterator {
-
-	algorithm := schedConfig.EffectiveSchedulerAlgorithm()
-	scoreFn := structs.ScoreFitBinPack
-	if algorithm == structs.SchedulerAlgorithmSpread {
-		scoreFn = structs.ScoreFitSpread
-	}
-
 	iter := &BinPackIterator{
-		ctx:                    ctx,
-		source:                 source,
-		evict:                  evict,
-		priority:               priority,
-		memoryOversubscription: schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled,
-		scoreFit:               scoreFn,
+		ctx:      ctx,
+		source:   source,
+		evict:    evict,
+		priority: priority,
 	}
-	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", algorithm)
+	iter.SetSchedulerConfiguration(schedConfig)
+	iter.ctx.Logger().Named("binpack").Trace("NewBinPackIterator created", "algorithm", schedConfig.EffectiveSchedulerAlgorithm())
 	return iter
 }
 
+// SetSchedulerConfiguration updates the scoring algorithm and memory
+// oversubscription behavior of the iterator, such as when the job being
+// placed targets a node pool with its own scheduler configuration.
+func (iter *BinPackIterator) SetSchedulerConfiguration(schedConfig *structs.SchedulerConfiguration) {
+	iter.scoreFit = structs.ScoreFitBinPack
+	if schedConfig.EffectiveSchedulerAlgorithm() == structs.SchedulerAlgorithmSpread {
+		iter.scoreFit = structs.ScoreFitSpread
+	}
+	iter.memoryOversubscription = schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled
+}
+
 func (iter *BinPackIterator) SetJob(job *structs.Job) {
 	iter.priority = job.Priority
 	iter.jobId = job.NamespacedID()
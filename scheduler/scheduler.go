@@ -103,6 +103,16 @@ type State interface {
 	// SchedulerConfig returns config options for the scheduler
 	SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error)
 
+	// NodePoolByName returns the node pool with the given name
+	NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error)
+
+	// HostVolumeByID returns the dynamic host volume with the given ID in
+	// the namespace
+	HostVolumeByID(ws memdb.WatchSet, namespace, id string, withAllocs bool) (*structs.HostVolume, error)
+
+	// NamespaceByName returns the namespace with the given name
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
 	// CSIVolumeByID fetch CSI volumes, containing controller jobs
 	CSIVolumeByID(memdb.WatchSet, string, string) (*structs.CSIVolume, error)
 
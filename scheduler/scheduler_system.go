@@ -132,7 +132,7 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Get the ready nodes in the required datacenters
 	if !s.job.Stopped() {
-		s.nodes, s.notReadyNodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters)
+		s.nodes, s.notReadyNodes, s.nodesByDC, err = readyNodesInDCsAndPool(s.state, s.job.Datacenters, s.job.NodePool)
 		if err != nil {
 			return false, fmt.Errorf("failed to get ready nodes: %v", err)
 		}
@@ -150,7 +150,9 @@ func (s *SystemScheduler) process() (bool, error) {
 	// Construct the placement stack
 	s.stack = NewSystemStack(s.sysbatch, s.ctx)
 	if !s.job.Stopped() {
-		s.stack.SetJob(s.job)
+		if err := s.stack.SetJob(s.job); err != nil {
+			return false, err
+		}
 	}
 
 	// Compute the target job allocations
@@ -262,8 +264,13 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	diff.update = destructiveUpdates
 
 	if s.eval.AnnotatePlan {
+		algorithms, err := taskGroupSchedulerAlgorithms(s.state, s.job)
+		if err != nil {
+			return err
+		}
 		s.plan.Annotations = &structs.PlanAnnotations{
-			DesiredTGUpdates: desiredUpdates(diff, inplaceUpdates, destructiveUpdates),
+			DesiredTGUpdates:    desiredUpdates(diff, inplaceUpdates, destructiveUpdates),
+			SchedulerAlgorithms: algorithms,
 		}
 	}
 
@@ -77,6 +77,53 @@ func TestSystemSched_JobRegister(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestSystemSched_JobRegister_NodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes and move half of them to a custom node pool
+	nodes := createNodes(t, h, 10)
+	for _, node := range nodes[:5] {
+		node := node.Copy()
+		node.NodePool = "dev"
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job in the custom node pool
+	job := mock.SystemJob()
+	job.NodePool = "dev"
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewSystemScheduler, eval))
+	require.Len(t, h.Plans, 1)
+
+	// Ensure allocations were only placed on the nodes of the node pool
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+	require.NoError(t, err)
+	require.Len(t, out, 5)
+	for _, alloc := range out {
+		node, err := h.State.NodeByID(ws, alloc.NodeID)
+		require.NoError(t, err)
+		require.Equal(t, "dev", node.NodePool)
+	}
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestSystemSched_JobRegister_StickyAllocs(t *testing.T) {
 	ci.Parallel(t)
 
@@ -233,7 +280,7 @@ func TestSystemSched_ExhaustResources(t *testing.T) {
 	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
 
 	// Enable Preemption
-	h.State.SchedulerSetConfig(h.NextIndex(), &structs.SchedulerConfiguration{
+	h.State.SchedulerSetConfig(structs.MsgTypeTestSetup, h.NextIndex(), &structs.SchedulerConfiguration{
 		PreemptionConfig: structs.PreemptionConfig{
 			SystemSchedulerEnabled: true,
 		},
@@ -1877,7 +1924,7 @@ func TestSystemSched_Preemption(t *testing.T) {
 	}
 
 	// Enable Preemption
-	err := h.State.SchedulerSetConfig(h.NextIndex(), &structs.SchedulerConfiguration{
+	err := h.State.SchedulerSetConfig(structs.MsgTypeTestSetup, h.NextIndex(), &structs.SchedulerConfiguration{
 		PreemptionConfig: structs.PreemptionConfig{
 			SystemSchedulerEnabled: true,
 		},
@@ -0,0 +1,341 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Simulation describes hypothetical changes to the cluster that are applied
+// to a copy of its state before running the schedulers offline, to answer
+// capacity planning questions without submitting anything to the cluster.
+type Simulation struct {
+	// RemoveNodes are the IDs of the nodes to remove from the cluster. The
+	// nodes are marked as down so that their allocations are replaced.
+	RemoveNodes []string
+
+	// RemoveDatacenters are the datacenters whose nodes are all removed.
+	RemoveDatacenters []string
+
+	// Jobs are the jobs to register.
+	Jobs []*structs.Job
+
+	// SchedulerConfig replaces the scheduler configuration when set.
+	SchedulerConfig *structs.SchedulerConfiguration
+}
+
+// SimulationResult is the outcome of a simulation.
+type SimulationResult struct {
+	// RemovedNodes are the IDs of the nodes removed by the simulation.
+	RemovedNodes []string
+
+	// Evaluations are the results of the evaluations processed by the
+	// simulation, in the order they were processed.
+	Evaluations []*SimulationEvalResult
+
+	// Utilization is the utilization of the ready nodes of each datacenter
+	// once the simulation is complete, sorted by datacenter.
+	Utilization []*SimulationUtilization
+}
+
+// SimulationEvalResult is the result of an evaluation processed by a
+// simulation.
+type SimulationEvalResult struct {
+	Namespace   string
+	JobID       string
+	JobType     string
+	TriggeredBy string
+
+	// Placed is the number of allocations placed by task group.
+	Placed map[string]int
+
+	// Stopped is the number of allocations stopped by task group, including
+	// the allocations lost with the removed nodes.
+	Stopped map[string]int
+
+	// FailedTGAllocs are the metrics of the task groups that couldn't be
+	// placed entirely.
+	FailedTGAllocs map[string]*structs.AllocMetric
+
+	// Error is set when the scheduler failed to process the evaluation.
+	Error string
+}
+
+// Failed returns the number of allocations that couldn't be placed.
+func (r *SimulationEvalResult) Failed() int {
+	failed := 0
+	for _, metric := range r.FailedTGAllocs {
+		failed += metric.CoalescedFailures + 1
+	}
+	return failed
+}
+
+// SimulationUtilization is the utilization of the ready nodes of a
+// datacenter.
+type SimulationUtilization struct {
+	Datacenter string
+	Nodes      int
+
+	CPU         int64
+	CPUCapacity int64
+
+	MemoryMB         int64
+	MemoryCapacityMB int64
+}
+
+// Run applies the changes of the simulation to the state and processes the
+// evaluations they trigger with the builtin schedulers. The state is modified
+// by the simulation, so it must not be the state of a running server.
+func (s *Simulation) Run(logger log.Logger, store *state.StateStore) (*SimulationResult, error) {
+	h, err := NewSimulationHarness(store, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create harness: %v", err)
+	}
+
+	if s.SchedulerConfig != nil {
+		if err := store.SchedulerSetConfig(structs.MsgTypeTestSetup, h.NextIndex(), s.SchedulerConfig); err != nil {
+			return nil, fmt.Errorf("failed to set scheduler configuration: %v", err)
+		}
+	}
+
+	result := &SimulationResult{}
+	removedEvals, err := s.removeNodes(h, result)
+	if err != nil {
+		return nil, err
+	}
+	registerEvals, err := s.registerJobs(h)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, eval := range append(removedEvals, registerEvals...) {
+		evalResult, err := s.process(h, eval)
+		if err != nil {
+			return nil, err
+		}
+		result.Evaluations = append(result.Evaluations, evalResult)
+	}
+
+	result.Utilization, err = simulationUtilization(store)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// removeNodes marks the removed nodes as down, and returns the evaluations of
+// the jobs that have allocations on them.
+func (s *Simulation) removeNodes(h *Harness, result *SimulationResult) ([]*structs.Evaluation, error) {
+	remove := make(map[string]struct{}, len(s.RemoveNodes))
+	for _, id := range s.RemoveNodes {
+		remove[id] = struct{}{}
+	}
+	removeDCs := make(map[string]struct{}, len(s.RemoveDatacenters))
+	for _, dc := range s.RemoveDatacenters {
+		removeDCs[dc] = struct{}{}
+	}
+
+	iter, err := h.State.Nodes(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	var nodes []*structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		_, removeNode := remove[node.ID]
+		_, removeDC := removeDCs[node.Datacenter]
+		if removeNode || removeDC {
+			nodes = append(nodes, node)
+			delete(remove, node.ID)
+		}
+	}
+	if len(remove) > 0 {
+		missing := make([]string, 0, len(remove))
+		for id := range remove {
+			missing = append(missing, id)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("nodes not found: %s", strings.Join(missing, ", "))
+	}
+
+	var evals []*structs.Evaluation
+	seen := make(map[structs.NamespacedID]struct{})
+	for _, node := range nodes {
+		event := structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemCluster).
+			SetMessage("Node removed by simulation")
+		if err := h.State.UpdateNodeStatus(structs.MsgTypeTestSetup, h.NextIndex(),
+			node.ID, structs.NodeStatusDown, time.Now().UnixNano(), event); err != nil {
+			return nil, fmt.Errorf("failed to update node %q: %v", node.ID, err)
+		}
+		result.RemovedNodes = append(result.RemovedNodes, node.ID)
+
+		allocs, err := h.State.AllocsByNode(nil, node.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list allocations of node %q: %v", node.ID, err)
+		}
+		for _, alloc := range allocs {
+			jobID := structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}
+			if _, ok := seen[jobID]; ok || alloc.TerminalStatus() {
+				continue
+			}
+			seen[jobID] = struct{}{}
+
+			job, err := h.State.JobByID(nil, alloc.Namespace, alloc.JobID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to lookup job %q: %v", alloc.JobID, err)
+			}
+			if job == nil {
+				continue
+			}
+			evals = append(evals, &structs.Evaluation{
+				ID:          uuid.Generate(),
+				Namespace:   job.Namespace,
+				Priority:    job.Priority,
+				Type:        job.Type,
+				TriggeredBy: structs.EvalTriggerNodeUpdate,
+				JobID:       job.ID,
+				NodeID:      node.ID,
+				Status:      structs.EvalStatusPending,
+			})
+		}
+	}
+	return evals, nil
+}
+
+// registerJobs stores the jobs of the simulation, and returns their
+// evaluations.
+func (s *Simulation) registerJobs(h *Harness) ([]*structs.Evaluation, error) {
+	evals := make([]*structs.Evaluation, 0, len(s.Jobs))
+	for _, job := range s.Jobs {
+		if err := h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job); err != nil {
+			return nil, fmt.Errorf("failed to register job %q: %v", job.ID, err)
+		}
+		evals = append(evals, &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   job.Namespace,
+			Priority:    job.Priority,
+			Type:        job.Type,
+			TriggeredBy: structs.EvalTriggerJobRegister,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		})
+	}
+	return evals, nil
+}
+
+// process processes an evaluation with its builtin scheduler, and summarizes
+// the plans it submitted.
+func (s *Simulation) process(h *Harness, eval *structs.Evaluation) (*SimulationEvalResult, error) {
+	result := &SimulationEvalResult{
+		Namespace:   eval.Namespace,
+		JobID:       eval.JobID,
+		JobType:     eval.Type,
+		TriggeredBy: eval.TriggeredBy,
+		Placed:      make(map[string]int),
+		Stopped:     make(map[string]int),
+	}
+
+	// Track the existing allocations to tell placements from updates
+	allocs, err := h.State.AllocsByJob(nil, eval.Namespace, eval.JobID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocations of job %q: %v", eval.JobID, err)
+	}
+	existing := make(map[string]struct{}, len(allocs))
+	for _, alloc := range allocs {
+		existing[alloc.ID] = struct{}{}
+	}
+
+	factory, ok := BuiltinSchedulers[eval.Type]
+	if !ok {
+		result.Error = fmt.Sprintf("unknown scheduler %q", eval.Type)
+		return result, nil
+	}
+
+	// Plans are applied along with their evaluation, which must exist
+	if err := h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}); err != nil {
+		return nil, fmt.Errorf("failed to store evaluation: %v", err)
+	}
+
+	plans, evals := len(h.Plans), len(h.Evals)
+	if err := h.Process(factory, eval); err != nil {
+		result.Error = err.Error()
+	}
+
+	for _, plan := range h.Plans[plans:] {
+		for _, nodeAllocs := range plan.NodeAllocation {
+			for _, alloc := range nodeAllocs {
+				if _, ok := existing[alloc.ID]; !ok {
+					result.Placed[alloc.TaskGroup]++
+				}
+			}
+		}
+		for _, nodeAllocs := range plan.NodeUpdate {
+			for _, alloc := range nodeAllocs {
+				result.Stopped[alloc.TaskGroup]++
+			}
+		}
+	}
+
+	// The last update of the evaluation has the task groups that failed
+	if updates := h.Evals[evals:]; len(updates) > 0 {
+		result.FailedTGAllocs = updates[len(updates)-1].FailedTGAllocs
+	}
+	return result, nil
+}
+
+// simulationUtilization computes the utilization of the ready nodes of each
+// datacenter.
+func simulationUtilization(store *state.StateStore) ([]*SimulationUtilization, error) {
+	iter, err := store.Nodes(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	byDC := make(map[string]*SimulationUtilization)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if !node.Ready() {
+			continue
+		}
+
+		allocs, err := store.AllocsByNode(nil, node.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list allocations of node %q: %v", node.ID, err)
+		}
+		allocs, _ = structs.FilterTerminalAllocs(allocs)
+		_, _, used, err := structs.AllocsFit(node, allocs, nil, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute the resources of node %q: %v", node.ID, err)
+		}
+
+		capacity := node.ComparableResources()
+		capacity.Subtract(node.ComparableReservedResources())
+
+		u, ok := byDC[node.Datacenter]
+		if !ok {
+			u = &SimulationUtilization{Datacenter: node.Datacenter}
+			byDC[node.Datacenter] = u
+		}
+		u.Nodes++
+		u.CPU += used.Flattened.Cpu.CpuShares
+		u.CPUCapacity += capacity.Flattened.Cpu.CpuShares
+		u.MemoryMB += used.Flattened.Memory.MemoryMB
+		u.MemoryCapacityMB += capacity.Flattened.Memory.MemoryMB
+	}
+
+	utilization := make([]*SimulationUtilization, 0, len(byDC))
+	for _, u := range byDC {
+		utilization = append(utilization, u)
+	}
+	sort.Slice(utilization, func(i, j int) bool {
+		return utilization[i].Datacenter < utilization[j].Datacenter
+	})
+	return utilization, nil
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulation_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	logger := testlog.HCLogger(t)
+
+	var dc2Nodes []string
+	for i, dc := range []string{"dc1", "dc1", "dc2", "dc2"} {
+		node := mock.Node()
+		node.Datacenter = dc
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		if dc == "dc2" {
+			dc2Nodes = append(dc2Nodes, node.ID)
+		}
+	}
+
+	// Registering a job places its allocations, spread across the nodes
+	job := mock.Job()
+	job.Datacenters = []string{"dc1", "dc2"}
+	job.Spreads = []*structs.Spread{{Attribute: "${node.datacenter}", Weight: 100}}
+	sim := &Simulation{
+		Jobs: []*structs.Job{job},
+		SchedulerConfig: &structs.SchedulerConfiguration{
+			SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+		},
+	}
+	result, err := sim.Run(logger, store)
+	require.NoError(t, err)
+
+	_, config, err := store.SchedulerConfig()
+	require.NoError(t, err)
+	require.Equal(t, structs.SchedulerAlgorithmSpread, config.SchedulerAlgorithm)
+
+	require.Len(t, result.Evaluations, 1)
+	require.Equal(t, structs.EvalTriggerJobRegister, result.Evaluations[0].TriggeredBy)
+	require.Equal(t, map[string]int{"web": 10}, result.Evaluations[0].Placed)
+	require.Zero(t, result.Evaluations[0].Failed())
+
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, true)
+	require.NoError(t, err)
+	lost := 0
+	for _, alloc := range allocs {
+		for _, id := range dc2Nodes {
+			if alloc.NodeID == id {
+				lost++
+			}
+		}
+	}
+	require.NotZero(t, lost)
+
+	// Removing a datacenter replaces the allocations of its nodes
+	sim = &Simulation{RemoveDatacenters: []string{"dc2"}}
+	result, err = sim.Run(logger, store)
+	require.NoError(t, err)
+	require.ElementsMatch(t, dc2Nodes, result.RemovedNodes)
+	require.Len(t, result.Evaluations, 1)
+	require.Equal(t, structs.EvalTriggerNodeUpdate, result.Evaluations[0].TriggeredBy)
+	require.Equal(t, map[string]int{"web": lost}, result.Evaluations[0].Placed)
+	require.Equal(t, map[string]int{"web": lost}, result.Evaluations[0].Stopped)
+
+	// Only the ready nodes are accounted for in the utilization
+	require.Len(t, result.Utilization, 1)
+	require.Equal(t, &SimulationUtilization{
+		Datacenter:       "dc1",
+		Nodes:            2,
+		CPU:              10 * 500,
+		CPUCapacity:      2 * (4000 - 100),
+		MemoryMB:         10 * 256,
+		MemoryCapacityMB: 2 * (8192 - 256),
+	}, result.Utilization[0])
+
+	// Jobs that don't fit report their failed placements
+	large := mock.Job()
+	large.TaskGroups[0].Count = 5
+	large.TaskGroups[0].Tasks[0].Resources.CPU = 1000
+	sim = &Simulation{Jobs: []*structs.Job{large}}
+	result, err = sim.Run(logger, store)
+	require.NoError(t, err)
+	require.Len(t, result.Evaluations, 1)
+	require.NotZero(t, result.Evaluations[0].Failed())
+	require.Equal(t, 5, result.Evaluations[0].Placed["web"]+result.Evaluations[0].Failed())
+	require.Contains(t, result.Evaluations[0].FailedTGAllocs, "web")
+
+	// Removing unknown nodes is an error
+	sim = &Simulation{RemoveNodes: []string{"unknown"}}
+	_, err = sim.Run(logger, store)
+	require.EqualError(t, err, "nodes not found: unknown")
+}
@@ -1,6 +1,9 @@
 package scheduler
 
 import (
+	"fmt"
+	"sort"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -38,6 +41,15 @@ type SpreadIterator struct {
 	// existing allocs are computed once, and allocs from the plan are updated
 	// when Reset is called
 	groupPropertySets map[string][]*propertySet
+
+	// groupTopologySpreads is a memoized map from task group to the spreads
+	// that are evaluated hierarchically, which are the compound spreads and
+	// the spreads with a max skew
+	groupTopologySpreads map[string][]*topologySpread
+
+	// nodes are the base nodes of the stack, whose attribute values are the
+	// ones a max skew is enforced across
+	nodes []*structs.Node
 }
 
 type spreadAttributeMap map[string]*spreadInfo
@@ -53,6 +65,8 @@ func NewSpreadIterator(ctx Context, source RankIterator) *SpreadIterator {
 		source:            source,
 		groupPropertySets: make(map[string][]*propertySet),
 		tgSpreadInfo:      make(map[string]spreadAttributeMap),
+
+		groupTopologySpreads: make(map[string][]*topologySpread),
 	}
 	return iter
 }
@@ -64,6 +78,26 @@ func (iter *SpreadIterator) Reset() {
 			ps.PopulateProposed()
 		}
 	}
+	for _, spreads := range iter.groupTopologySpreads {
+		for _, ts := range spreads {
+			for _, ps := range ts.levels {
+				ps.PopulateProposed()
+			}
+			ts.capacity = nil
+		}
+	}
+}
+
+// SetNodes is used to set the base nodes of the stack, which max skews are
+// enforced across.
+func (iter *SpreadIterator) SetNodes(nodes []*structs.Node) {
+	iter.nodes = nodes
+	for _, spreads := range iter.groupTopologySpreads {
+		for _, ts := range spreads {
+			ts.domains = nil
+			ts.capacity = nil
+		}
+	}
 }
 
 func (iter *SpreadIterator) SetJob(job *structs.Job) {
@@ -77,6 +111,7 @@ func (iter *SpreadIterator) SetJob(job *structs.Job) {
 	// versions of spread/properties to the new job version
 	iter.tgSpreadInfo = make(map[string]spreadAttributeMap)
 	iter.groupPropertySets = make(map[string][]*propertySet)
+	iter.groupTopologySpreads = make(map[string][]*topologySpread)
 }
 
 func (iter *SpreadIterator) SetTaskGroup(tg *structs.TaskGroup) {
@@ -84,23 +119,28 @@ func (iter *SpreadIterator) SetTaskGroup(tg *structs.TaskGroup) {
 
 	// Build the property set at the taskgroup level
 	if _, ok := iter.groupPropertySets[tg.Name]; !ok {
-		// First add property sets that are at the job level for this task group
-		for _, spread := range iter.jobSpreads {
-			pset := NewPropertySet(iter.ctx, iter.job)
-			pset.SetTargetAttribute(spread.Attribute, tg.Name)
-			iter.groupPropertySets[tg.Name] = append(iter.groupPropertySets[tg.Name], pset)
-		}
+		iter.groupPropertySets[tg.Name] = nil
+
+		// First add property sets that are at the job level for this task
+		// group, then include property sets at the task group level
+		for _, spreads := range [][]*structs.Spread{iter.jobSpreads, tg.Spreads} {
+			for _, spread := range spreads {
+				if isTopologySpread(spread) {
+					ts := newTopologySpread(iter.ctx, iter.job, tg, spread)
+					iter.groupTopologySpreads[tg.Name] = append(iter.groupTopologySpreads[tg.Name], ts)
+					continue
+				}
 
-		// Include property sets at the task group level
-		for _, spread := range tg.Spreads {
-			pset := NewPropertySet(iter.ctx, iter.job)
-			pset.SetTargetAttribute(spread.Attribute, tg.Name)
-			iter.groupPropertySets[tg.Name] = append(iter.groupPropertySets[tg.Name], pset)
+				pset := NewPropertySet(iter.ctx, iter.job)
+				pset.SetTargetAttribute(spread.Attribute, tg.Name)
+				iter.groupPropertySets[tg.Name] = append(iter.groupPropertySets[tg.Name], pset)
+			}
 		}
 	}
 
 	// Check if there are any spreads configured
-	iter.hasSpread = len(iter.groupPropertySets[tg.Name]) != 0
+	iter.hasSpread = len(iter.groupPropertySets[tg.Name]) != 0 ||
+		len(iter.groupTopologySpreads[tg.Name]) != 0
 
 	// Build tgSpreadInfo at the task group level
 	if _, ok := iter.tgSpreadInfo[tg.Name]; !ok {
@@ -180,6 +220,22 @@ func (iter *SpreadIterator) Next() *RankedNode {
 			}
 		}
 
+		// Evaluate the compound spreads and the spreads with a max skew, which
+		// can make the option infeasible
+		feasible := true
+		for _, ts := range iter.groupTopologySpreads[tgName] {
+			scoreBoost, reason := ts.score(iter, option.Node)
+			if reason != "" {
+				iter.ctx.Metrics().FilterNode(option.Node, reason)
+				feasible = false
+				break
+			}
+			totalSpreadScore += scoreBoost
+		}
+		if !feasible {
+			continue
+		}
+
 		if totalSpreadScore != 0.0 {
 			option.Scores = append(option.Scores, totalSpreadScore)
 			iter.ctx.Metrics().ScoreNode(option.Node, "allocation-spread", totalSpreadScore)
@@ -197,12 +253,18 @@ func evenSpreadScoreBoost(pset *propertySet, option *structs.Node) float64 {
 		return 0.0
 	}
 	// Get the nodes property value
-	nValue, ok := getProperty(option, pset.targetAttribute)
+	nValue, ok := pset.nodeProperty(option)
 
 	// Maximum possible penalty when the attribute isn't set on the node
 	if !ok {
 		return -1.0
 	}
+	return evenSpreadBoost(combinedUseMap, nValue)
+}
+
+// evenSpreadBoost calculates the score of the value given how many times
+// each value is used, when all values get equal preference
+func evenSpreadBoost(combinedUseMap map[string]uint64, nValue string) float64 {
 	currentAttributeCount := combinedUseMap[nValue]
 	minCount := uint64(0)
 	maxCount := uint64(0)
@@ -253,6 +315,13 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 	combinedSpreads = append(combinedSpreads, tg.Spreads...)
 	combinedSpreads = append(combinedSpreads, iter.jobSpreads...)
 	for _, spread := range combinedSpreads {
+		iter.sumSpreadWeights += int32(spread.Weight)
+		if isTopologySpread(spread) {
+			// The desired counts of topology spreads are computed
+			// hierarchically by newTopologySpread
+			continue
+		}
+
 		si := &spreadInfo{weight: spread.Weight, desiredCounts: make(map[string]float64)}
 		sumDesiredCounts := 0.0
 		for _, st := range spread.SpreadTarget {
@@ -266,7 +335,272 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 			si.desiredCounts[implicitTarget] = remainingCount
 		}
 		spreadInfos[spread.Attribute] = si
-		iter.sumSpreadWeights += int32(spread.Weight)
 	}
 	iter.tgSpreadInfo[tg.Name] = spreadInfos
 }
+
+// isTopologySpread returns whether the spread is evaluated hierarchically by
+// a topologySpread rather than by the property sets of the iterator.
+func isTopologySpread(spread *structs.Spread) bool {
+	return spread.IsCompound() || spread.MaxSkew > 0
+}
+
+// topologySpread evaluates a compound spread, or a spread with a max skew,
+// level by level: the first level spreads allocations across the values of
+// the first attribute, and each following level across the values of its
+// attribute within the values of the previous levels. The value of a node at
+// a level is the path of its values of the attributes up to the level, like
+// "dc1/rack1".
+type topologySpread struct {
+	spread *structs.Spread
+	tg     *structs.TaskGroup
+
+	// levels are the property sets tracking the allocations by the value
+	// paths of each level
+	levels []*propertySet
+
+	// desiredCounts maps the target values to their desired count, computed
+	// from the percentages of the targets relative to their parent
+	desiredCounts map[string]float64
+
+	// targetParents are the value paths that have targets under them
+	targetParents map[string]struct{}
+
+	// domains maps the value paths of each level found on the base nodes
+	// meeting the constraints of the task group to these nodes. The max skew
+	// is enforced across the domains. They are computed lazily.
+	domains []map[string][]*structs.Node
+
+	// capacity tracks whether the domains of each level have a node with
+	// capacity for the task group given the current plan. It is computed
+	// lazily and cleared when the iterator is reset.
+	capacity []map[string]bool
+
+	// placeholder is an allocation of the resources of the task group used
+	// to check the capacity of the domains
+	placeholder *structs.Allocation
+}
+
+func newTopologySpread(ctx Context, job *structs.Job, tg *structs.TaskGroup, spread *structs.Spread) *topologySpread {
+	attributes := spread.SpreadAttributes()
+	ts := &topologySpread{
+		spread:        spread,
+		tg:            tg,
+		levels:        make([]*propertySet, len(attributes)),
+		desiredCounts: make(map[string]float64),
+		targetParents: make(map[string]struct{}),
+		placeholder:   taskGroupPlaceholder(tg),
+	}
+	for i := range attributes {
+		pset := NewPropertySet(ctx, job)
+		pset.SetTargetAttributes(attributes[:i+1], tg.Name)
+		ts.levels[i] = pset
+	}
+
+	// Compute the desired counts of parents before those of their children.
+	// Targets are keyed by their escaped value path, like the node values
+	targets := make([][]string, len(spread.SpreadTarget))
+	percents := make(map[string]uint8, len(spread.SpreadTarget))
+	for i, st := range spread.SpreadTarget {
+		targets[i] = spread.TargetValues(st.Value)
+		percents[structs.JoinSpreadValues(targets[i])] = st.Percent
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return len(targets[i]) < len(targets[j])
+	})
+
+	parentCounts := map[string]float64{"": float64(tg.Count)}
+	sumDesiredCounts := make(map[string]float64)
+	for _, values := range targets {
+		value := structs.JoinSpreadValues(values)
+		parent := structs.JoinSpreadValues(values[:len(values)-1])
+		parentCount, ok := parentCounts[parent]
+		if !ok {
+			// Targets without a parent target are rejected by validation
+			continue
+		}
+		desiredCount := (float64(percents[value]) / float64(100)) * parentCount
+		ts.desiredCounts[value] = desiredCount
+		parentCounts[value] = desiredCount
+		sumDesiredCounts[parent] += desiredCount
+		ts.targetParents[parent] = struct{}{}
+	}
+
+	// Account for the remaining count of each parent that has targets
+	for parent, sum := range sumDesiredCounts {
+		if sum > 0 && sum < parentCounts[parent] {
+			ts.desiredCounts[spreadPath(parent, implicitTarget)] = parentCounts[parent] - sum
+		}
+	}
+	return ts
+}
+
+// score returns the score of the node averaged across the levels of the
+// spread, or the reason why the node is infeasible when placing on it would
+// exceed the max skew.
+func (ts *topologySpread) score(iter *SpreadIterator, option *structs.Node) (float64, string) {
+	spreadWeight := float64(ts.spread.Weight) / float64(iter.sumSpreadWeights)
+
+	total := 0.0
+	parent := ""
+	for i, pset := range ts.levels {
+		nValue, errorMsg, usedCount := pset.UsedCount(option, ts.tg.Name)
+		if errorMsg != "" {
+			if ts.spread.MaxSkew > 0 {
+				return 0, fmt.Sprintf("spread max_skew: %s", errorMsg)
+			}
+			iter.ctx.Logger().Named("spread").Debug("error building spread attributes for task group", "task_group", ts.tg.Name, "error", errorMsg)
+			return -1.0, ""
+		}
+
+		// Only the values sharing the parent of the node compete with it
+		siblings := make(map[string]uint64)
+		for value, count := range pset.GetCombinedUseMap() {
+			if spreadParent(value) == parent {
+				siblings[value] = count
+			}
+		}
+
+		if ts.spread.MaxSkew > 0 {
+			// Full domains can't receive allocations so they don't hold
+			// back the others
+			minCount := usedCount
+			for value := range ts.levelDomains(iter)[i] {
+				if spreadParent(value) != parent {
+					continue
+				}
+				if count := siblings[value]; count < minCount && ts.hasCapacity(iter, i, value) {
+					minCount = count
+				}
+			}
+			if skew := usedCount + 1 - minCount; skew > uint64(ts.spread.MaxSkew) {
+				return 0, fmt.Sprintf("spread max_skew: %s=%s skew %d exceeds %d",
+					pset.targetAttribute, nValue, skew, ts.spread.MaxSkew)
+			}
+		}
+
+		if _, ok := ts.targetParents[parent]; ok {
+			desiredCount, ok := ts.desiredCounts[nValue]
+			if !ok {
+				desiredCount, ok = ts.desiredCounts[spreadPath(parent, implicitTarget)]
+			}
+			if !ok || desiredCount == 0 {
+				// The desired count for this value is zero so use the
+				// maximum possible penalty for this node
+				total -= 1.0
+			} else {
+				// Add one to include placement on this node
+				total += ((desiredCount - float64(usedCount+1)) / desiredCount) * spreadWeight
+			}
+		} else if len(siblings) != 0 {
+			total += evenSpreadBoost(siblings, nValue)
+		}
+
+		parent = nValue
+	}
+	return total / float64(len(ts.levels)), ""
+}
+
+// levelDomains returns the value paths of each level found on the base
+// nodes that meet the constraints of the job and task group, and these nodes.
+func (ts *topologySpread) levelDomains(iter *SpreadIterator) []map[string][]*structs.Node {
+	if ts.domains != nil {
+		return ts.domains
+	}
+
+	constraints := append([]*structs.Constraint{}, iter.job.Constraints...)
+	constraints = append(constraints, taskGroupConstraints(ts.tg).constraints...)
+	checker := NewConstraintChecker(iter.ctx, constraints)
+
+	ts.domains = make([]map[string][]*structs.Node, len(ts.levels))
+	for i := range ts.domains {
+		ts.domains[i] = make(map[string][]*structs.Node)
+	}
+
+NODES:
+	for _, node := range iter.nodes {
+		for _, constraint := range constraints {
+			if !checker.meetsConstraint(constraint, node) {
+				continue NODES
+			}
+		}
+		for i, pset := range ts.levels {
+			value, ok := pset.nodeProperty(node)
+			if !ok {
+				break
+			}
+			ts.domains[i][value] = append(ts.domains[i][value], node)
+		}
+	}
+	return ts.domains
+}
+
+// hasCapacity returns whether a node of the domain of the level has the
+// resources to run the task group given the current plan.
+func (ts *topologySpread) hasCapacity(iter *SpreadIterator, level int, value string) bool {
+	if ts.capacity == nil {
+		ts.capacity = make([]map[string]bool, len(ts.levels))
+		for i := range ts.capacity {
+			ts.capacity[i] = make(map[string]bool)
+		}
+	}
+	if ok, checked := ts.capacity[level][value]; checked {
+		return ok
+	}
+
+	ok := false
+	for _, node := range ts.levelDomains(iter)[level][value] {
+		proposed, err := iter.ctx.ProposedAllocs(node.ID)
+		if err != nil {
+			iter.ctx.Logger().Named("spread").Error("failed to get proposed allocations", "node_id", node.ID, "error", err)
+			continue
+		}
+		allocs := append(proposed[:len(proposed):len(proposed)], ts.placeholder)
+		if fit, _, _, _ := structs.AllocsFit(node, allocs, nil, false); fit {
+			ok = true
+			break
+		}
+	}
+	ts.capacity[level][value] = ok
+	return ok
+}
+
+// taskGroupPlaceholder returns an allocation of the cpu, memory and disk
+// resources of the task group.
+func taskGroupPlaceholder(tg *structs.TaskGroup) *structs.Allocation {
+	resources := &structs.AllocatedResources{
+		Tasks: make(map[string]*structs.AllocatedTaskResources, len(tg.Tasks)),
+	}
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		resources.Tasks[task.Name] = &structs.AllocatedTaskResources{
+			Cpu: structs.AllocatedCpuResources{
+				CpuShares: int64(task.Resources.CPU),
+			},
+			Memory: structs.AllocatedMemoryResources{
+				MemoryMB: int64(task.Resources.MemoryMB),
+			},
+		}
+	}
+	if tg.EphemeralDisk != nil {
+		resources.Shared.DiskMB = int64(tg.EphemeralDisk.SizeMB)
+	}
+	return &structs.Allocation{AllocatedResources: resources}
+}
+
+// spreadPath returns the value path of a child of the parent path.
+func spreadPath(parent, value string) string {
+	if parent == "" {
+		return structs.JoinSpreadValues([]string{value})
+	}
+	return parent + structs.SpreadTargetSeparator + structs.JoinSpreadValues([]string{value})
+}
+
+// spreadParent returns the parent of a value path, which is empty for the
+// values of the first level.
+func spreadParent(value string) string {
+	values := structs.SplitSpreadValues(value)
+	return structs.JoinSpreadValues(values[:len(values)-1])
+}
@@ -558,6 +558,228 @@ func TestSpreadIterator_MaxPenalty(t *testing.T) {
 
 }
 
+func TestSpreadIterator_CompoundAttributes(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		racks  []string
+		target string
+	}{
+		{
+			name:   "plain values",
+			racks:  []string{"r1", "r2"},
+			target: "dc1/r1",
+		},
+		{
+			// Values containing the separator are escaped in target paths
+			name:   "values with separator",
+			racks:  []string{"r/1", "r/2"},
+			target: `dc1/r\/1`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, ctx := testContext(t)
+			topology := [][]string{{"dc1", tc.racks[0]}, {"dc1", tc.racks[1]}, {"dc2", tc.racks[0]}}
+			var nodes []*RankedNode
+
+			for i, domain := range topology {
+				node := mock.Node()
+				node.Datacenter = domain[0]
+				node.Meta["rack"] = domain[1]
+				require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+				nodes = append(nodes, &RankedNode{Node: node})
+			}
+
+			job := mock.Job()
+			tg := job.TaskGroups[0]
+			tg.Count = 10
+
+			// Add an alloc in the first rack of dc1
+			alloc := mock.Alloc()
+			alloc.Job = job
+			alloc.JobID = job.ID
+			alloc.TaskGroup = tg.Name
+			alloc.NodeID = nodes[0].Node.ID
+			require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+			// 60% in dc1 split evenly between its first rack and the other
+			// racks, and implicitly 40% in dc2 spread evenly across its racks
+			tg.Spreads = []*structs.Spread{{
+				Weight:     100,
+				Attributes: []string{"${node.datacenter}", "${meta.rack}"},
+				SpreadTarget: []*structs.SpreadTarget{
+					{Value: "dc1", Percent: 60},
+					{Value: tc.target, Percent: 50},
+				},
+			}}
+			require.NoError(t, tg.Spreads[0].Validate())
+
+			static := NewStaticRankIterator(ctx, nodes)
+			spreadIter := NewSpreadIterator(ctx, static)
+			spreadIter.SetJob(job)
+			spreadIter.SetTaskGroup(tg)
+			scoreNorm := NewScoreNormalizationIterator(ctx, spreadIter)
+			out := collectRanked(scoreNorm)
+			require.Len(t, out, 3)
+
+			// The desired counts are dc1=6, dc2=4 and 3 for each rack of
+			// dc1, and the score is the average of the score of each level
+			expectedScores := map[string]float64{
+				nodes[0].Node.ID: ((6.0-2)/6 + (3.0-2)/3) / 2,
+				nodes[1].Node.ID: ((6.0-2)/6 + (3.0-1)/3) / 2,
+				nodes[2].Node.ID: ((4.0 - 1) / 4) / 2,
+			}
+			for _, rn := range out {
+				domain := rn.Node.Datacenter + "/" + rn.Node.Meta["rack"]
+				require.InDelta(t, expectedScores[rn.Node.ID], rn.FinalScore, 0.0001, domain)
+			}
+		})
+	}
+}
+
+func TestSpreadIterator_MaxSkew(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	racks := []string{"r1", "r2", "r3", "r4", ""}
+	var nodes []*RankedNode
+	var baseNodes []*structs.Node
+
+	for i, rack := range racks {
+		node := mock.Node()
+		if rack != "" {
+			node.Meta["rack"] = rack
+		}
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, &RankedNode{Node: node})
+		baseNodes = append(baseNodes, node)
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 6
+	tg.Spreads = []*structs.Spread{{
+		Weight:    100,
+		Attribute: "${meta.rack}",
+		MaxSkew:   1,
+	}}
+
+	// The rack r4 doesn't meet the constraints so it isn't considered when
+	// computing the skew
+	tg.Constraints = append(tg.Constraints, &structs.Constraint{
+		LTarget: "${meta.rack}",
+		RTarget: "r4",
+		Operand: "!=",
+	})
+
+	newAlloc := func(node *structs.Node) *structs.Allocation {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.TaskGroup = tg.Name
+		alloc.NodeID = node.ID
+		return alloc
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{newAlloc(nodes[0].Node)}))
+
+	collectRacks := func() []string {
+		// The node in r4 would be filtered by the constraint
+		candidates := []*RankedNode{nodes[0], nodes[1], nodes[2], nodes[4]}
+		static := NewStaticRankIterator(ctx, candidates)
+		spreadIter := NewSpreadIterator(ctx, static)
+		spreadIter.SetNodes(baseNodes)
+		spreadIter.SetJob(job)
+		spreadIter.SetTaskGroup(tg)
+
+		var out []string
+		for _, rn := range collectRanked(spreadIter) {
+			out = append(out, rn.Node.Meta["rack"])
+		}
+		return out
+	}
+
+	// Another alloc in r1 would exceed the skew, and the node without the
+	// attribute is infeasible
+	require.ElementsMatch(t, []string{"r2", "r3"}, collectRacks())
+	require.Equal(t, 1, ctx.Metrics().ConstraintFiltered[`spread max_skew: ${meta.rack}=r1 skew 2 exceeds 1`])
+	require.Equal(t, 1, ctx.Metrics().ConstraintFiltered[`spread max_skew: missing property "${meta.rack}"`])
+
+	// Once the other racks have an alloc r1 is feasible again
+	ctx.plan.NodeAllocation[nodes[1].Node.ID] = []*structs.Allocation{newAlloc(nodes[1].Node)}
+	ctx.plan.NodeAllocation[nodes[2].Node.ID] = []*structs.Allocation{newAlloc(nodes[2].Node)}
+	require.ElementsMatch(t, []string{"r1", "r2", "r3"}, collectRacks())
+}
+
+func TestSpreadIterator_MaxSkew_FullDomain(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	racks := []string{"r1", "r2", "r3"}
+	var nodes []*RankedNode
+	var baseNodes []*structs.Node
+
+	for i, rack := range racks {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, &RankedNode{Node: node})
+		baseNodes = append(baseNodes, node)
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 6
+	tg.Spreads = []*structs.Spread{{
+		Weight:    100,
+		Attribute: "${meta.rack}",
+		MaxSkew:   1,
+	}}
+
+	newAlloc := func(node *structs.Node) *structs.Allocation {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.TaskGroup = tg.Name
+		alloc.NodeID = node.ID
+		return alloc
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{newAlloc(nodes[0].Node), newAlloc(nodes[1].Node)}))
+
+	// The node of r3 is filled by another job
+	filler := mock.Alloc()
+	filler.NodeID = nodes[2].Node.ID
+	filler.AllocatedResources.Tasks["web"].Cpu.CpuShares = 3800
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001,
+		[]*structs.Allocation{filler}))
+
+	collectRacks := func() []string {
+		// The node in r3 would be exhausted by the bin packing
+		static := NewStaticRankIterator(ctx, nodes[:2])
+		spreadIter := NewSpreadIterator(ctx, static)
+		spreadIter.SetNodes(baseNodes)
+		spreadIter.SetJob(job)
+		spreadIter.SetTaskGroup(tg)
+
+		var out []string
+		for _, rn := range collectRanked(spreadIter) {
+			out = append(out, rn.Node.Meta["rack"])
+		}
+		return out
+	}
+
+	// The full rack r3 doesn't hold back placements on the other racks
+	require.ElementsMatch(t, []string{"r1", "r2"}, collectRacks())
+
+	// Once r3 has capacity again its lack of allocs blocks the other racks
+	ctx.plan.NodeUpdate[nodes[2].Node.ID] = []*structs.Allocation{filler}
+	require.Empty(t, collectRacks())
+}
+
 func Test_evenSpreadScoreBoost(t *testing.T) {
 	ci.Parallel(t)
 
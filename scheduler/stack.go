@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"fmt"
 	"math"
 	"time"
 
@@ -24,8 +25,9 @@ type Stack interface {
 	// SetNodes is used to set the base set of potential nodes
 	SetNodes([]*structs.Node)
 
-	// SetTaskGroup is used to set the job for selection
-	SetJob(job *structs.Job)
+	// SetJob is used to set the job for selection. It fails if the scheduler
+	// configuration of the job can't be read from the state.
+	SetJob(job *structs.Job) error
 
 	// Select is used to select a node for the task group
 	Select(tg *structs.TaskGroup, options *SelectOptions) *RankedNode
@@ -36,6 +38,10 @@ type SelectOptions struct {
 	PreferredNodes []*structs.Node
 	Preempt        bool
 	AllocName      string
+
+	// PreferredNodesOnly restricts the selection to the preferred nodes
+	// instead of falling back to all nodes.
+	PreferredNodesOnly bool
 }
 
 // GenericStack is the Stack used for the Generic scheduler. It is
@@ -48,6 +54,8 @@ type GenericStack struct {
 	wrappedChecks        *FeasibilityWrapper
 	quota                FeasibleIterator
 	jobVersion           *uint64
+	jobSchedConfig       *structs.SchedulerConfiguration
+	jobNodePool          *NodePoolChecker
 	jobConstraint        *ConstraintChecker
 	taskGroupDrivers     *DriverChecker
 	taskGroupConstraint  *ConstraintChecker
@@ -75,6 +83,7 @@ func (s *GenericStack) SetNodes(baseNodes []*structs.Node) {
 
 	// Update the set of base nodes
 	s.source.SetNodes(baseNodes)
+	s.spread.SetNodes(baseNodes)
 
 	// Apply a limit function. This is to avoid scanning *every* possible node.
 	// For batch jobs we only need to evaluate 2 options and depend on the
@@ -91,40 +100,51 @@ func (s *GenericStack) SetNodes(baseNodes []*structs.Node) {
 	s.limit.SetLimit(limit)
 }
 
-func (s *GenericStack) SetJob(job *structs.Job) {
+func (s *GenericStack) SetJob(job *structs.Job) error {
 	if s.jobVersion != nil && *s.jobVersion == job.Version {
-		return
+		return nil
+	}
+
+	schedConfig, err := jobSchedulerConfiguration(s.ctx.State(), job)
+	if err != nil {
+		return err
 	}
 
 	jobVer := job.Version
 	s.jobVersion = &jobVer
 
+	s.jobNodePool.SetPool(job.NodePool)
 	s.jobConstraint.SetConstraints(job.Constraints)
 	s.distinctHostsConstraint.SetJob(job)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetJob(job)
+	s.jobSchedConfig = schedConfig
+	s.binPack.SetSchedulerConfiguration(s.jobSchedConfig)
 	s.jobAntiAff.SetJob(job)
 	s.nodeAffinity.SetJob(job)
 	s.spread.SetJob(job)
 	s.ctx.Eligibility().SetJob(job)
 	s.taskGroupCSIVolumes.SetNamespace(job.Namespace)
+	s.taskGroupHostVolumes.SetNamespace(job.Namespace)
 	s.taskGroupCSIVolumes.SetJobID(job.ID)
 
 	if contextual, ok := s.quota.(ContextualIterator); ok {
 		contextual.SetJob(job)
 	}
+	return nil
 }
 
 func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *RankedNode {
 
 	// This block handles trying to select from preferred nodes if options specify them
 	// It also sets back the set of nodes to the original nodes
-	if options != nil && len(options.PreferredNodes) > 0 {
+	if options != nil && (len(options.PreferredNodes) > 0 || options.PreferredNodesOnly) {
 		originalNodes := s.source.nodes
 		s.source.SetNodes(options.PreferredNodes)
 		optionsNew := *options
 		optionsNew.PreferredNodes = nil
-		if option := s.Select(tg, &optionsNew); option != nil {
+		optionsNew.PreferredNodesOnly = false
+		if option := s.Select(tg, &optionsNew); option != nil || options.PreferredNodesOnly {
 			s.source.SetNodes(originalNodes)
 			return option
 		}
@@ -153,6 +173,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.binPack.SetTaskGroup(tg)
+	s.binPack.SetSchedulerConfiguration(s.jobSchedConfig.WithSchedulerAlgorithm(tg.SchedulerAlgorithm))
 	if options != nil {
 		s.binPack.evict = options.Preempt
 	}
@@ -194,6 +215,8 @@ type SystemStack struct {
 
 	wrappedChecks        *FeasibilityWrapper
 	quota                FeasibleIterator
+	jobSchedConfig       *structs.SchedulerConfiguration
+	jobNodePool          *NodePoolChecker
 	jobConstraint        *ConstraintChecker
 	taskGroupDrivers     *DriverChecker
 	taskGroupConstraint  *ConstraintChecker
@@ -220,6 +243,9 @@ func NewSystemStack(sysbatch bool, ctx Context) *SystemStack {
 	// have to evaluate on all nodes.
 	s.source = NewStaticIterator(ctx, nil)
 
+	// Filter on the node pool of the job. The job is filled in later.
+	s.jobNodePool = NewNodePoolChecker(ctx)
+
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
@@ -245,7 +271,7 @@ func NewSystemStack(sysbatch bool, ctx Context) *SystemStack {
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
-	jobs := []FeasibilityChecker{s.jobConstraint}
+	jobs := []FeasibilityChecker{s.jobNodePool, s.jobConstraint}
 	tgs := []FeasibilityChecker{
 		s.taskGroupDrivers,
 		s.taskGroupConstraint,
@@ -295,15 +321,25 @@ func (s *SystemStack) SetNodes(baseNodes []*structs.Node) {
 	s.source.SetNodes(baseNodes)
 }
 
-func (s *SystemStack) SetJob(job *structs.Job) {
+func (s *SystemStack) SetJob(job *structs.Job) error {
+	schedConfig, err := jobSchedulerConfiguration(s.ctx.State(), job)
+	if err != nil {
+		return err
+	}
+
+	s.jobNodePool.SetPool(job.NodePool)
+	s.taskGroupHostVolumes.SetNamespace(job.Namespace)
 	s.jobConstraint.SetConstraints(job.Constraints)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetJob(job)
+	s.jobSchedConfig = schedConfig
+	s.binPack.SetSchedulerConfiguration(s.jobSchedConfig)
 	s.ctx.Eligibility().SetJob(job)
 
 	if contextual, ok := s.quota.(ContextualIterator); ok {
 		contextual.SetJob(job)
 	}
+	return nil
 }
 
 func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) *RankedNode {
@@ -327,6 +363,7 @@ func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ran
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
 	s.binPack.SetTaskGroup(tg)
+	s.binPack.SetSchedulerConfiguration(s.jobSchedConfig.WithSchedulerAlgorithm(tg.SchedulerAlgorithm))
 
 	if contextual, ok := s.quota.(ContextualIterator); ok {
 		contextual.SetTaskGroup(tg)
@@ -353,6 +390,9 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// balancing across eligible nodes.
 	s.source = NewRandomIterator(ctx, nil)
 
+	// Filter on the node pool of the job. The job is filled in later.
+	s.jobNodePool = NewNodePoolChecker(ctx)
+
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
@@ -378,7 +418,7 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
-	jobs := []FeasibilityChecker{s.jobConstraint}
+	jobs := []FeasibilityChecker{s.jobNodePool, s.jobConstraint}
 	tgs := []FeasibilityChecker{
 		s.taskGroupDrivers,
 		s.taskGroupConstraint,
@@ -437,3 +477,51 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	s.maxScore = NewMaxScoreIterator(ctx, s.limit)
 	return s
 }
+
+// jobSchedulerConfiguration returns the scheduler configuration that applies
+// to the placements of the job, taking into account the overrides set by
+// the node pool the job targets, its namespace, and the job itself, in
+// increasing order of precedence.
+func jobSchedulerConfiguration(state State, job *structs.Job) (*structs.SchedulerConfiguration, error) {
+	_, schedConfig, err := state.SchedulerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduler configuration: %v", err)
+	}
+
+	poolName := job.NodePool
+	if poolName == "" {
+		poolName = structs.NodePoolDefault
+	}
+	pool, err := state.NodePoolByName(nil, poolName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node pool %q: %v", poolName, err)
+	}
+	ns, err := state.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %q: %v", job.Namespace, err)
+	}
+	return schedConfig.
+		WithNodePool(pool).
+		WithNamespace(ns).
+		WithSchedulerAlgorithm(job.SchedulerAlgorithm), nil
+}
+
+// taskGroupSchedulerAlgorithms returns the scheduler algorithm used to place
+// the allocations of each task group of the job, which may override the
+// algorithm of the job.
+func taskGroupSchedulerAlgorithms(state State, job *structs.Job) (map[string]structs.SchedulerAlgorithm, error) {
+	if job == nil {
+		return nil, nil
+	}
+
+	schedConfig, err := jobSchedulerConfiguration(state, job)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithms := make(map[string]structs.SchedulerAlgorithm, len(job.TaskGroups))
+	for _, tg := range job.TaskGroups {
+		algorithms[tg.Name] = schedConfig.WithSchedulerAlgorithm(tg.SchedulerAlgorithm).EffectiveSchedulerAlgorithm()
+	}
+	return algorithms, nil
+}
@@ -1,12 +1,14 @@
 package scheduler
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
@@ -101,6 +103,132 @@ func TestServiceStack_SetJob(t *testing.T) {
 	}
 }
 
+func TestServiceStack_SetJob_NodePoolSchedulerConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	require.NoError(t, state.SchedulerSetConfig(structs.MsgTypeTestSetup, 100, &structs.SchedulerConfiguration{
+		SchedulerAlgorithm:            structs.SchedulerAlgorithmBinpack,
+		MemoryOversubscriptionEnabled: false,
+	}))
+	require.NoError(t, state.UpsertNodePools(structs.MsgTypeTestSetup, 101, []*structs.NodePool{{
+		Name: "dev",
+		SchedulerConfiguration: &structs.NodePoolSchedulerConfiguration{
+			SchedulerAlgorithm:            structs.SchedulerAlgorithmSpread,
+			MemoryOversubscriptionEnabled: helper.BoolToPtr(true),
+		},
+	}}))
+
+	stack := NewGenericStack(false, ctx)
+
+	// Jobs in the default node pool use the cluster configuration
+	job := mock.Job()
+	require.NoError(t, stack.SetJob(job))
+	require.False(t, stack.binPack.memoryOversubscription)
+
+	config, err := jobSchedulerConfiguration(state, job)
+	require.NoError(t, err)
+	require.Equal(t, structs.SchedulerAlgorithmBinpack, config.EffectiveSchedulerAlgorithm())
+
+	// Jobs in the custom node pool use its overrides
+	job = job.Copy()
+	job.NodePool = "dev"
+	job.Version++
+	require.NoError(t, stack.SetJob(job))
+	require.True(t, stack.binPack.memoryOversubscription)
+
+	config, err = jobSchedulerConfiguration(state, job)
+	require.NoError(t, err)
+	require.Equal(t, structs.SchedulerAlgorithmSpread, config.EffectiveSchedulerAlgorithm())
+	require.True(t, config.MemoryOversubscriptionEnabled)
+}
+
+// schedulerConfigErrState is a state whose scheduler configuration can't be
+// read.
+type schedulerConfigErrState struct {
+	State
+}
+
+func (schedulerConfigErrState) SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error) {
+	return 0, nil, errors.New("state read failed")
+}
+
+func TestStack_SetJob_SchedulerConfigError(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	ctx.state = schedulerConfigErrState{state}
+	job := mock.Job()
+
+	// The job isn't placed with the default algorithm when its scheduler
+	// configuration can't be read
+	err := NewGenericStack(false, ctx).SetJob(job)
+	require.ErrorContains(t, err, "failed to get scheduler configuration: state read failed")
+
+	err = NewSystemStack(false, ctx).SetJob(job)
+	require.ErrorContains(t, err, "failed to get scheduler configuration: state read failed")
+
+	_, err = taskGroupSchedulerAlgorithms(ctx.state, job)
+	require.ErrorContains(t, err, "failed to get scheduler configuration: state read failed")
+}
+
+func TestServiceStack_Select_SchedulerAlgorithmOverrides(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	require.NoError(t, state.SchedulerSetConfig(structs.MsgTypeTestSetup, 100, &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+	}))
+	ns := mock.Namespace()
+	ns.SchedulerConfiguration = &structs.NamespaceSchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+	}
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 101, []*structs.Namespace{ns}))
+
+	// The first node is already in use, so binpack prefers it while spread
+	// prefers the empty node
+	used, empty := mock.Node(), mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = used.ID
+	ctx.Plan().NodeAllocation[used.ID] = []*structs.Allocation{alloc}
+
+	stack := NewGenericStack(false, ctx)
+	stack.SetNodes([]*structs.Node{used, empty})
+
+	job := mock.Job()
+	selectNode := func() string {
+		job.Version++
+		require.NoError(t, stack.SetJob(job))
+		option := stack.Select(job.TaskGroups[0], &SelectOptions{})
+		require.NotNil(t, option)
+		return option.Node.ID
+	}
+	algorithms := func() structs.SchedulerAlgorithm {
+		algorithms, err := taskGroupSchedulerAlgorithms(state, job)
+		require.NoError(t, err)
+		return algorithms[job.TaskGroups[0].Name]
+	}
+
+	// Jobs use the cluster configuration by default
+	require.Equal(t, used.ID, selectNode())
+	require.Equal(t, structs.SchedulerAlgorithmBinpack, algorithms())
+
+	// The namespace overrides the cluster configuration
+	job.Namespace = ns.Name
+	require.Equal(t, empty.ID, selectNode())
+	require.Equal(t, structs.SchedulerAlgorithmSpread, algorithms())
+
+	// The job overrides the namespace
+	job.SchedulerAlgorithm = structs.SchedulerAlgorithmBinpack
+	require.Equal(t, used.ID, selectNode())
+	require.Equal(t, structs.SchedulerAlgorithmBinpack, algorithms())
+
+	// The task group overrides the job
+	job.TaskGroups[0].SchedulerAlgorithm = structs.SchedulerAlgorithmSpread
+	require.Equal(t, empty.ID, selectNode())
+	require.Equal(t, structs.SchedulerAlgorithmSpread, algorithms())
+}
+
 func TestServiceStack_Select_Size(t *testing.T) {
 	ci.Parallel(t)
 
@@ -174,6 +302,44 @@ func TestServiceStack_Select_PreferringNodes(t *testing.T) {
 	require.Equal(t, prefNodes1, selectOptions.PreferredNodes)
 }
 
+func TestServiceStack_Select_PreferredNodesOnly(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+	}
+	stack := NewGenericStack(false, ctx)
+	stack.SetNodes(nodes)
+
+	job := mock.Job()
+	stack.SetJob(job)
+
+	// Make the preferred node infeasible and ensure the allocation is not
+	// placed elsewhere
+	preferredNode := mock.Node()
+	preferredNode.Attributes["kernel.name"] = "windows"
+	preferredNode.ComputeClass()
+	selectOptions := &SelectOptions{
+		PreferredNodes:     []*structs.Node{preferredNode},
+		PreferredNodesOnly: true,
+	}
+	option := stack.Select(job.TaskGroups[0], selectOptions)
+	require.Nil(t, option)
+	require.Equal(t, 1, ctx.Metrics().NodesEvaluated)
+
+	// Without preferred nodes nothing is placed
+	selectOptions = &SelectOptions{PreferredNodesOnly: true}
+	option = stack.Select(job.TaskGroups[0], selectOptions)
+	require.Nil(t, option)
+	require.Equal(t, 0, ctx.Metrics().NodesEvaluated)
+
+	// The source nodes are restored after the selection
+	option = stack.Select(job.TaskGroups[0], &SelectOptions{})
+	require.NotNil(t, option)
+	require.Equal(t, nodes[0].ID, option.Node.ID)
+}
+
 func TestServiceStack_Select_MetricsReset(t *testing.T) {
 	ci.Parallel(t)
 
@@ -260,7 +426,7 @@ func TestServiceStack_Select_CSI(t *testing.T) {
 	v.AccessMode = structs.CSIVolumeAccessModeMultiNodeSingleWriter
 	v.AttachmentMode = structs.CSIVolumeAttachmentModeFilesystem
 	v.PluginID = "bar"
-	err := state.UpsertCSIVolume(999, []*structs.CSIVolume{v})
+	err := state.UpsertCSIVolume(structs.MsgTypeTestSetup, 999, []*structs.CSIVolume{v})
 	require.NoError(t, err)
 
 	// Create a node with healthy fingerprints for both controller and node plugins
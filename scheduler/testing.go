@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -49,6 +50,9 @@ type Harness struct {
 	t     testing.TB
 	State *state.StateStore
 
+	// logger is used by the schedulers when the harness isn't used by a test
+	logger log.Logger
+
 	Planner  Planner
 	planLock sync.Mutex
 
@@ -86,6 +90,22 @@ func NewHarnessWithState(t testing.TB, state *state.StateStore) *Harness {
 	}
 }
 
+// NewSimulationHarness creates a new harness with the given state to invoke
+// the schedulers outside of tests. Indexes continue from the latest index of
+// the state.
+func NewSimulationHarness(state *state.StateStore, logger log.Logger) (*Harness, error) {
+	index, err := state.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+	return &Harness{
+		State:                     state,
+		logger:                    logger,
+		nextIndex:                 index + 1,
+		serversMeetMinimumVersion: true,
+	}, nil
+}
+
 // SubmitPlan is used to handle plan submission
 func (h *Harness) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, State, error) {
 	// Ensure sequential plan application
@@ -272,7 +292,10 @@ func (h *Harness) Snapshot() State {
 // Scheduler is used to return a new scheduler from
 // a snapshot of current state using the harness for planning.
 func (h *Harness) Scheduler(factory Factory) Scheduler {
-	logger := testlog.HCLogger(h.t)
+	logger := h.logger
+	if logger == nil {
+		logger = testlog.HCLogger(h.t)
+	}
 	eventsCh := make(chan interface{})
 
 	// Listen for and log events from the scheduler.
@@ -280,6 +303,10 @@ func (h *Harness) Scheduler(factory Factory) Scheduler {
 		for e := range eventsCh {
 			switch event := e.(type) {
 			case *PortCollisionEvent:
+				if h.t == nil {
+					logger.Error("unexpected worker eval event", "reason", event.Reason)
+					continue
+				}
 				h.t.Errorf("unexpected worker eval event: %v", event.Reason)
 			}
 		}
@@ -346,9 +346,10 @@ func diffSystemAllocs(
 	return result
 }
 
-// readyNodesInDCs returns all the ready nodes in the given datacenters and a
-// mapping of each data center to the count of ready nodes.
-func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]struct{}, map[string]int, error) {
+// readyNodesInDCsAndPool returns all the ready nodes in the given datacenters
+// and node pool, and a mapping of each data center to the count of ready
+// nodes.
+func readyNodesInDCsAndPool(state State, dcs []string, pool string) ([]*structs.Node, map[string]struct{}, map[string]int, error) {
 	// Index the DCs
 	dcMap := make(map[string]int, len(dcs))
 	for _, dc := range dcs {
@@ -378,6 +379,9 @@ func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]str
 		if _, ok := dcMap[node.Datacenter]; !ok {
 			continue
 		}
+		if !nodeInPool(node, pool) {
+			continue
+		}
 		out = append(out, node)
 		dcMap[node.Datacenter]++
 	}
@@ -401,7 +401,7 @@ func TestDiffSystemAllocs(t *testing.T) {
 	}
 }
 
-func TestReadyNodesInDCs(t *testing.T) {
+func TestReadyNodesInDCsAndPool(t *testing.T) {
 	ci.Parallel(t)
 
 	state := state.TestStateStore(t)
@@ -412,13 +412,16 @@ func TestReadyNodesInDCs(t *testing.T) {
 	node3.Datacenter = "dc2"
 	node3.Status = structs.NodeStatusDown
 	node4 := mock.DrainNode()
+	node5 := mock.Node()
+	node5.NodePool = "dev"
 
 	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node1))
 	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2))
 	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1002, node3))
 	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1003, node4))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1004, node5))
 
-	nodes, notReady, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"})
+	nodes, notReady, dc, err := readyNodesInDCsAndPool(state, []string{"dc1", "dc2"}, structs.NodePoolDefault)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(nodes))
 	require.NotEqual(t, node3.ID, nodes[0].ID)
@@ -431,6 +434,18 @@ func TestReadyNodesInDCs(t *testing.T) {
 
 	require.Contains(t, notReady, node3.ID)
 	require.Contains(t, notReady, node4.ID)
+
+	// Only the nodes of the given node pool are returned
+	nodes, _, dc, err = readyNodesInDCsAndPool(state, []string{"dc1", "dc2"}, "dev")
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+	require.Equal(t, node5.ID, nodes[0].ID)
+	require.Equal(t, 1, dc["dc1"])
+
+	// The built-in "all" node pool includes every node
+	nodes, _, _, err = readyNodesInDCsAndPool(state, []string{"dc1", "dc2"}, structs.NodePoolAll)
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
 }
 
 func TestRetryMax(t *testing.T) {